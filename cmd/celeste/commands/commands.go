@@ -9,8 +9,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/providers"
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/prompts"
+	"github.com/whykusanagi/celesteCLI/internal/providers"
 )
 
 // Command represents a parsed slash command.
@@ -23,13 +28,19 @@ type Command struct {
 // CommandContext provides context for command execution.
 type CommandContext struct {
 	NSFWMode      bool
-	Provider      string // Current provider (grok, openai, venice, etc.)
-	CurrentModel  string // Current model in use
-	APIKey        string // API key for model listing
-	BaseURL       string // Base URL for API calls
-	SkillsEnabled bool   // Whether skills/functions are currently enabled
-	Version       string // Application version
-	Build         string // Build identifier
+	Provider      string          // Current provider (grok, openai, venice, etc.)
+	CurrentModel  string          // Current model in use
+	APIKey        string          // API key for model listing
+	BaseURL       string          // Base URL for API calls
+	SkillsEnabled bool            // Whether skills/functions are currently enabled
+	Version       string          // Application version
+	Build         string          // Build identifier
+	SafeMode      bool            // Safe mode (--safe-mode / CELESTE_SAFE_MODE) blocks NSFW commands
+	Persona       string          // Active persona (empty means prompts.DefaultPersona)
+	Sampling      config.Sampling // Session sampling overrides set with /set
+	ContextLimit  int             // Configured context_limit, for max_tokens checks
+	SystemPrompt  string          // System prompt sent with each request ("" when none)
+	SystemCustom  bool            // SystemPrompt was set with /system set
 }
 
 // CommandResult represents the result of executing a command.
@@ -61,9 +72,13 @@ type StateChange struct {
 	Model          *string
 	ImageModel     *string
 	ClearHistory   bool
-	MenuState      *string        // "status", "commands", "skills"
-	SessionAction  *SessionAction // Session management operations
-	ShowSelector   *SelectorData  // Show interactive selector
+	MenuState      *string          // "status", "commands", "skills"
+	SessionAction  *SessionAction   // Session management operations
+	ShowSelector   *SelectorData    // Show interactive selector
+	AttachImage    *ImageAttachment // Image attached with /image
+	Persona        *string          // Persona to switch to with /persona
+	Sampling       *config.Sampling // Session sampling overrides from /set
+	SystemPrompt   *string          // System prompt override from /system set; "" clears it
 }
 
 // SessionAction represents a session management operation.
@@ -106,6 +121,14 @@ func Execute(cmd *Command, ctx *CommandContext) *CommandResult {
 		ctx = &CommandContext{}
 	}
 
+	if ctx.SafeMode && IsBlockedInSafeMode(cmd.Name) {
+		return &CommandResult{
+			Success:      false,
+			Message:      fmt.Sprintf("🛡️ /%s is disabled in safe mode.\n\nUnset CELESTE_SAFE_MODE and restart without --safe-mode to use it.", cmd.Name),
+			ShouldRender: true,
+		}
+	}
+
 	switch strings.ToLower(cmd.Name) {
 	case "nsfw":
 		return handleNSFW(cmd)
@@ -113,6 +136,16 @@ func Execute(cmd *Command, ctx *CommandContext) *CommandResult {
 		return handleSafe(cmd)
 	case "endpoint":
 		return handleEndpoint(cmd)
+	case "persona":
+		return handlePersona(cmd, ctx)
+	case "reload-persona":
+		return handleReloadPersona(ctx)
+	case "system":
+		return handleSystem(cmd, ctx)
+	case "set":
+		return handleSet(cmd, ctx)
+	case "temp", "topp", "maxtokens":
+		return handleSamplingShortcut(cmd, ctx)
 	case "model":
 		return handleModel(cmd)
 	case "image-model", "set-model", "list-models":
@@ -155,6 +188,27 @@ func Execute(cmd *Command, ctx *CommandContext) *CommandResult {
 			Message:      "⚠️ /export command requires app context - this should be handled by the TUI",
 			ShouldRender: true,
 		}
+	case "reload-skills":
+		// Note: reloading needs the skill registry owned by the TUI's client
+		return &CommandResult{
+			Success:      false,
+			Message:      "⚠️ /reload-skills command requires app context - this should be handled by the TUI",
+			ShouldRender: true,
+		}
+	case "summarize":
+		// Note: summarizing needs the chat history and the TUI's LLM client
+		return &CommandResult{
+			Success:      false,
+			Message:      "⚠️ /summarize command requires app context - this should be handled by the TUI",
+			ShouldRender: true,
+		}
+	case "retry", "edit", "search":
+		// Note: these work on the chat history owned by the TUI
+		return &CommandResult{
+			Success:      false,
+			Message:      fmt.Sprintf("⚠️ /%s command requires app context - this should be handled by the TUI", cmd.Name),
+			ShouldRender: true,
+		}
 	default:
 		return &CommandResult{
 			Success:      false,
@@ -164,6 +218,16 @@ func Execute(cmd *Command, ctx *CommandContext) *CommandResult {
 	}
 }
 
+// IsBlockedInSafeMode reports whether a slash command is an NSFW or image
+// generation entry point that safe mode must reject.
+func IsBlockedInSafeMode(name string) bool {
+	switch strings.ToLower(name) {
+	case "nsfw", "image-model":
+		return true
+	}
+	return false
+}
+
 // handleNSFW handles the /nsfw command.
 func handleNSFW(cmd *Command) *CommandResult {
 	enabled := true
@@ -229,6 +293,234 @@ func handleEndpoint(cmd *Command) *CommandResult {
 	}
 }
 
+// handlePersona handles the /persona command.
+func handlePersona(cmd *Command, ctx *CommandContext) *CommandResult {
+	current := ctx.Persona
+	if current == "" {
+		current = prompts.DefaultPersona
+	}
+
+	if len(cmd.Args) == 0 {
+		return &CommandResult{
+			Success:      true,
+			Message:      "🎭 Personas:\n" + formatPersonaList(current) + "\nUsage: /persona <name>\nAdd personas as .json or .yaml files in " + prompts.PersonasDir(),
+			ShouldRender: true,
+		}
+	}
+
+	name := cmd.Args[0]
+	if !prompts.IsPersona(name) {
+		return &CommandResult{
+			Success:      false,
+			Message:      fmt.Sprintf("Unknown persona: %s\n\nAvailable personas:\n%s", name, formatPersonaList(current)),
+			ShouldRender: true,
+		}
+	}
+
+	return &CommandResult{
+		Success:      true,
+		Message:      fmt.Sprintf("🎭 Switched persona to %s", name),
+		ShouldRender: true,
+		StateChange: &StateChange{
+			Persona: &name,
+		},
+	}
+}
+
+// handleSystem handles the /system command: without arguments it shows the
+// system prompt being sent, "set <text>" overrides it for this chat and
+// "clear" goes back to the persona's prompt.
+func handleSystem(cmd *Command, ctx *CommandContext) *CommandResult {
+	usage := "Usage: /system [set <text>|clear]"
+	if len(cmd.Args) == 0 {
+		return &CommandResult{
+			Success:      true,
+			Message:      formatSystemPrompt(ctx) + "\n\n" + usage,
+			ShouldRender: true,
+		}
+	}
+
+	switch strings.ToLower(cmd.Args[0]) {
+	case "set":
+		text := rawArgs(cmd, 1)
+		if text == "" {
+			return &CommandResult{
+				Success:      false,
+				Message:      "Missing prompt text\n" + usage,
+				ShouldRender: true,
+			}
+		}
+		return &CommandResult{
+			Success:      true,
+			Message:      fmt.Sprintf("🧾 System prompt overridden for this chat (%d chars). /system clear restores the persona's.", utf8.RuneCountInString(text)),
+			ShouldRender: true,
+			StateChange:  &StateChange{SystemPrompt: &text},
+		}
+	case "clear":
+		if len(cmd.Args) > 1 {
+			return &CommandResult{Success: false, Message: usage, ShouldRender: true}
+		}
+		if !ctx.SystemCustom {
+			return &CommandResult{
+				Success:      true,
+				Message:      "🧾 No system prompt override to clear.",
+				ShouldRender: true,
+			}
+		}
+		clear := ""
+		return &CommandResult{
+			Success:      true,
+			Message:      "🧾 System prompt override cleared; using the persona's prompt again.",
+			ShouldRender: true,
+			StateChange:  &StateChange{SystemPrompt: &clear},
+		}
+	default:
+		return &CommandResult{
+			Success:      false,
+			Message:      fmt.Sprintf("Unknown /system option: %s\n%s", cmd.Args[0], usage),
+			ShouldRender: true,
+		}
+	}
+}
+
+// rawArgs returns the input after the command name and its first n
+// arguments, with spacing and newlines as typed. Commands built without Raw
+// join their Args.
+func rawArgs(cmd *Command, n int) string {
+	if cmd.Raw == "" {
+		return strings.Join(cmd.Args[n:], " ")
+	}
+	rest := cmd.Raw
+	for i := 0; i <= n; i++ {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			return ""
+		}
+		rest = rest[end:]
+	}
+	return strings.TrimSpace(rest)
+}
+
+// formatSystemPrompt describes the system prompt being sent, for /system.
+func formatSystemPrompt(ctx *CommandContext) string {
+	persona := ctx.Persona
+	if persona == "" {
+		persona = prompts.DefaultPersona
+	}
+	if ctx.SystemPrompt == "" {
+		return fmt.Sprintf("🧾 No system prompt is sent (skip_persona_prompt is on and persona %s is the default).", persona)
+	}
+
+	source := "persona " + persona
+	if ctx.SystemCustom {
+		source = "override from /system set"
+	}
+	return fmt.Sprintf("🧾 System prompt (%s, %d chars, ~%d tokens):\n\n%s",
+		source, utf8.RuneCountInString(ctx.SystemPrompt), config.EstimateTokens(ctx.SystemPrompt), ctx.SystemPrompt)
+}
+
+// handleSet handles the /set command, which changes a sampling setting for
+// the rest of the session.
+func handleSet(cmd *Command, ctx *CommandContext) *CommandResult {
+	if len(cmd.Args) == 0 {
+		return &CommandResult{
+			Success:      true,
+			Message:      "🎛️ Sampling: " + ctx.Sampling.String() + "\nUsage: /set <" + strings.Join(config.SamplingSettings, "|") + "> <value|default>",
+			ShouldRender: true,
+		}
+	}
+	if len(cmd.Args) != 2 {
+		return &CommandResult{
+			Success:      false,
+			Message:      "Usage: /set <" + strings.Join(config.SamplingSettings, "|") + "> <value|default>\nExample: /set temperature 0.8",
+			ShouldRender: true,
+		}
+	}
+
+	sampling, err := config.ParseSamplingSetting(ctx.Sampling, cmd.Args[0], cmd.Args[1])
+	if err == nil {
+		err = sampling.Validate(ctx.CurrentModel, ctx.ContextLimit)
+	}
+	if err != nil {
+		return &CommandResult{
+			Success:      false,
+			Message:      fmt.Sprintf("❌ %v", err),
+			ShouldRender: true,
+		}
+	}
+
+	return &CommandResult{
+		Success:      true,
+		Message:      "🎛️ Sampling: " + sampling.String(),
+		ShouldRender: true,
+		StateChange: &StateChange{
+			Sampling: &sampling,
+		},
+	}
+}
+
+// samplingShortcuts maps the /temp, /topp and /maxtokens commands to the
+// setting they change.
+var samplingShortcuts = map[string]string{
+	"temp":      "temperature",
+	"topp":      "top_p",
+	"maxtokens": "max_tokens",
+}
+
+// handleSamplingShortcut handles /temp, /topp and /maxtokens, shorthand for
+// /set temperature, /set top_p and /set max_tokens. Without a value it shows
+// the settings.
+func handleSamplingShortcut(cmd *Command, ctx *CommandContext) *CommandResult {
+	setting := samplingShortcuts[cmd.Name]
+	if len(cmd.Args) == 0 {
+		return &CommandResult{
+			Success:      true,
+			Message:      "🎛️ Sampling: " + ctx.Sampling.String() + "\nUsage: /" + cmd.Name + " <value|default>",
+			ShouldRender: true,
+		}
+	}
+	return handleSet(&Command{Name: "set", Args: append([]string{setting}, cmd.Args...), Raw: cmd.Raw}, ctx)
+}
+
+// handleReloadPersona handles the /reload-persona command by re-applying the
+// active persona, which re-reads its file.
+func handleReloadPersona(ctx *CommandContext) *CommandResult {
+	current := ctx.Persona
+	if current == "" {
+		current = prompts.DefaultPersona
+	}
+	source := prompts.PersonaPath(current)
+	if source == "" {
+		source = "built-in essence"
+	}
+	return &CommandResult{
+		Success:      true,
+		Message:      fmt.Sprintf("🔄 Reloaded persona %s from %s", current, source),
+		ShouldRender: true,
+		StateChange: &StateChange{
+			Persona: &current,
+		},
+	}
+}
+
+// formatPersonaList lists the available personas, marking the active one.
+func formatPersonaList(current string) string {
+	var sb strings.Builder
+	for _, name := range prompts.ListPersonas() {
+		marker := "  "
+		if name == current {
+			marker = "▸ "
+		}
+		sb.WriteString(marker + name)
+		if name == prompts.DefaultPersona {
+			sb.WriteString(" (built-in)")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
 // handleModel handles the /model command.
 func handleModel(cmd *Command) *CommandResult {
 	if len(cmd.Args) == 0 {
@@ -478,8 +770,7 @@ func handleConfig(cmd *Command) *CommandResult {
 
 // listAvailableConfigs lists all available configuration profiles.
 func listAvailableConfigs() *CommandResult {
-	homeDir, _ := os.UserHomeDir()
-	configDir := filepath.Join(homeDir, ".celeste")
+	configDir := paths.ConfigDir()
 
 	// Read directory
 	entries, err := os.ReadDir(configDir)
@@ -510,7 +801,7 @@ func listAvailableConfigs() *CommandResult {
 	if len(configs) == 0 {
 		return &CommandResult{
 			Success:      false,
-			Message:      "❌ No configuration profiles found.\n\nCreate configs in: ~/.celeste/\n\nExample:\n  config.json (default)\n  config.grok.json\n  config.vertex.json",
+			Message:      fmt.Sprintf("❌ No configuration profiles found.\n\nCreate configs in: %s\n\nExample:\n  config.json (default)\n  config.grok.json\n  config.vertex.json", configDir),
 			ShouldRender: true,
 		}
 	}
@@ -651,8 +942,23 @@ Media Generation Commands:
   image[model]: <prompt>       Use specific model for one generation
                                Example: image[nano-banana-pro]: futuristic city
 
-  upscale: <path>              Upscale and enhance existing image
-                               Example: upscale: ~/photo.jpg
+  upscale: <path> [flags]      Upscale and enhance existing image
+                               --scale <2|4> (default 2), --model <upscaler>
+                               Example: upscale: ~/photo.jpg --scale 4
+
+Generation Flags (append to any image prompt):
+  --steps <1-50>               Diffusion steps
+  --cfg-scale <0-20>           Prompt adherence
+  --width <px> --height <px>   Size, multiples of 64 up to 1280
+  --negative-prompt <text>     What to avoid
+  --variants <1-4>             Generate several images to pick from
+  --seed <n>                   Fixed seed, to reproduce an image
+  --reuse-seed-from <file>     Seed, settings and prompt from an earlier image
+                               (its .png or .json); new text is added on
+  --style <name|none>          Preset from ~/.celeste/image_styles.json
+                               ("default" applies when none is given)
+                               Example: image: castle --steps 30 --width 768
+                               Example: image: at night --reuse-seed-from castle.png
 
 Model Management:
   /set-model <model>           Set default image generation model
@@ -662,6 +968,14 @@ Model Management:
 Chat Commands:
   /safe                        Return to safe mode (OpenAI)
   /clear                       Clear conversation history
+  /retry [temperature]         Regenerate the last reply
+  /set <setting> <value>       Set temperature, top_p or max_tokens for this session
+  /temp <value>, /topp <value> Shorthand for /set temperature and /set top_p
+  /maxtokens <n>               Shorthand for /set max_tokens
+  /edit                        Edit and resend your last message
+  /system [set <text>|clear]   Show, override or restore the system prompt
+  /summarize [style]           Recap the conversation (bullets, narrative, tweet-thread)
+  /search <text>               Find text in this chat (n/N to jump, Esc to clear)
   /help                        Show this help message
 
 Current Configuration:
@@ -704,11 +1018,30 @@ Endpoint Control:
                      Options: openai, venice, grok, elevenlabs, google
   /config <name>     Load a named config profile
   /model <name>      Change the model (e.g., gpt-4o, llama-3.3-70b)
+  /persona [name]    List personas, or switch to one
+  /reload-persona    Re-read the active persona's file after editing it
+  /system            Show the system prompt; /system set <text> overrides it,
+                     /system clear restores the persona's
+  /set <name> <val>  Set temperature, top_p or max_tokens for this session
+  /temp, /topp       Shorthand for /set temperature and /set top_p
+  /maxtokens <n>     Shorthand for /set max_tokens
+
+Images:
+  /image <path|url>  Attach an image to your next message (vision models)
+  /image <path> <message>  Send a message about an image right away
+  /attach <path|url> Same as /image; dragging an image file in also attaches it
 
 Session Control:
   /clear             Clear conversation history
+  /retry [temp]      Regenerate the last reply (optionally at a new temperature)
+  /edit              Edit and resend your last message
+  /summarize [style] Recap the conversation (bullets, narrative, tweet-thread)
+  /search <text>     Find text in this chat (n/N to jump, Esc to clear)
   /help              Show this help message
 
+Skills:
+  /reload-skills     Reload custom skills from ~/.celeste/skills
+
 Examples:
   /nsfw              → Enable uncensored mode with media generation
   /endpoint google   → Switch to Google Vertex AI
@@ -1,10 +1,18 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
 )
 
 func TestParse(t *testing.T) {
@@ -91,6 +99,22 @@ func TestExecuteNSFW(t *testing.T) {
 	assert.Equal(t, "lustify-sdxl", *result.StateChange.ImageModel)
 }
 
+func TestExecuteNSFWBlockedInSafeMode(t *testing.T) {
+	for _, name := range []string{"nsfw", "NSFW", "image-model"} {
+		t.Run(name, func(t *testing.T) {
+			result := Execute(&Command{Name: name}, &CommandContext{SafeMode: true})
+
+			assert.False(t, result.Success)
+			assert.Contains(t, result.Message, "disabled in safe mode")
+			assert.Nil(t, result.StateChange)
+		})
+	}
+
+	// Non-NSFW commands still work in safe mode
+	result := Execute(&Command{Name: "clear"}, &CommandContext{SafeMode: true})
+	assert.True(t, result.Success)
+}
+
 func TestExecuteSafe(t *testing.T) {
 	cmd := &Command{Name: "safe"}
 	ctx := &CommandContext{NSFWMode: true}
@@ -192,6 +216,80 @@ func TestExecuteModel(t *testing.T) {
 	}
 }
 
+func TestExecuteSet(t *testing.T) {
+	ctx := &CommandContext{CurrentModel: "gpt-4o-mini"}
+	result := Execute(&Command{Name: "set", Args: []string{"temperature", "0.8"}}, ctx)
+	require.True(t, result.Success, result.Message)
+	require.NotNil(t, result.StateChange)
+	require.NotNil(t, result.StateChange.Sampling)
+	assert.Equal(t, "temperature 0.8", result.StateChange.Sampling.String())
+
+	// Later settings build on the session's current values
+	ctx.Sampling = *result.StateChange.Sampling
+	result = Execute(&Command{Name: "set", Args: []string{"max-tokens", "500"}}, ctx)
+	require.True(t, result.Success, result.Message)
+	assert.Equal(t, "temperature 0.8, max_tokens 500", result.StateChange.Sampling.String())
+
+	result = Execute(&Command{Name: "set"}, ctx)
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Message, "temperature 0.8")
+	assert.Nil(t, result.StateChange)
+
+	for _, args := range [][]string{
+		{"temperature", "3"},
+		{"top_p", "-0.1"},
+		{"max_tokens", "200000"},
+		{"seed", "1"},
+		{"temperature"},
+	} {
+		result := Execute(&Command{Name: "set", Args: args}, ctx)
+		assert.False(t, result.Success, args)
+		assert.Nil(t, result.StateChange, args)
+	}
+}
+
+func TestExecuteSamplingShortcuts(t *testing.T) {
+	ctx := &CommandContext{CurrentModel: "gpt-4o-mini"}
+	result := Execute(&Command{Name: "temp", Args: []string{"1.2"}}, ctx)
+	require.True(t, result.Success, result.Message)
+	require.NotNil(t, result.StateChange)
+	assert.Equal(t, "temperature 1.2", result.StateChange.Sampling.String())
+
+	ctx.Sampling = *result.StateChange.Sampling
+	result = Execute(&Command{Name: "topp", Args: []string{"0.9"}}, ctx)
+	require.True(t, result.Success, result.Message)
+	assert.Equal(t, "temperature 1.2, top_p 0.9", result.StateChange.Sampling.String())
+
+	ctx.Sampling = *result.StateChange.Sampling
+	result = Execute(&Command{Name: "temp", Args: []string{"default"}}, ctx)
+	require.True(t, result.Success, result.Message)
+	assert.Equal(t, "top_p 0.9", result.StateChange.Sampling.String())
+
+	ctx.Sampling = *result.StateChange.Sampling
+	result = Execute(&Command{Name: "maxtokens", Args: []string{"500"}}, ctx)
+	require.True(t, result.Success, result.Message)
+	assert.Equal(t, "top_p 0.9, max_tokens 500", result.StateChange.Sampling.String())
+
+	ctx.Sampling = *result.StateChange.Sampling
+	result = Execute(&Command{Name: "topp"}, ctx)
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Message, "max_tokens 500")
+	assert.Nil(t, result.StateChange)
+
+	for _, cmd := range []*Command{
+		{Name: "maxtokens", Args: []string{"0"}},
+		{Name: "maxtokens", Args: []string{"1000000"}},
+		{Name: "temp", Args: []string{"2.5"}},
+		{Name: "topp", Args: []string{"1.5"}},
+		{Name: "temp", Args: []string{"hot"}},
+		{Name: "temp", Args: []string{"1", "2"}},
+	} {
+		result := Execute(cmd, ctx)
+		assert.False(t, result.Success, cmd.Args)
+		assert.Nil(t, result.StateChange, cmd.Args)
+	}
+}
+
 func TestExecuteClear(t *testing.T) {
 	cmd := &Command{Name: "clear"}
 	ctx := &CommandContext{}
@@ -498,3 +596,278 @@ func TestBoolToStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleImageCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cat.png")
+	require.NoError(t, os.WriteFile(path, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0644))
+
+	t.Run("attach for next message", func(t *testing.T) {
+		result := HandleImageCommand([]string{path}, true, "gpt-4o", 0)
+		require.True(t, result.Success)
+		require.NotNil(t, result.StateChange)
+		require.NotNil(t, result.StateChange.AttachImage)
+		assert.Equal(t, path, result.StateChange.AttachImage.Source)
+		assert.True(t, strings.HasPrefix(result.StateChange.AttachImage.URL, "data:image/png;base64,"))
+		assert.Empty(t, result.StateChange.AttachImage.Message)
+		assert.Contains(t, result.Message, "[image: cat.png, 16B]")
+	})
+
+	t.Run("over the size cap", func(t *testing.T) {
+		result := HandleImageCommand([]string{path}, true, "gpt-4o", 8)
+		assert.False(t, result.Success)
+		assert.Nil(t, result.StateChange)
+		assert.Contains(t, result.Message, "limit is 8B")
+	})
+
+	t.Run("send with message", func(t *testing.T) {
+		result := HandleImageCommand([]string{"https://example.com/a.jpg", "what", "is", "this?"}, true, "gpt-4o", 0)
+		require.True(t, result.Success)
+		assert.Equal(t, "https://example.com/a.jpg", result.StateChange.AttachImage.URL)
+		assert.Equal(t, "what is this?", result.StateChange.AttachImage.Message)
+		assert.False(t, result.ShouldRender)
+	})
+
+	t.Run("text-only model", func(t *testing.T) {
+		result := HandleImageCommand([]string{path}, false, "venice-uncensored", 0)
+		assert.False(t, result.Success)
+		assert.Nil(t, result.StateChange)
+		assert.Contains(t, result.Message, "venice-uncensored")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		result := HandleImageCommand([]string{filepath.Join(t.TempDir(), "nope.png")}, true, "gpt-4o", 0)
+		assert.False(t, result.Success)
+		assert.Nil(t, result.StateChange)
+	})
+
+	t.Run("no args", func(t *testing.T) {
+		result := HandleImageCommand(nil, true, "gpt-4o", 0)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Message, "Usage")
+	})
+}
+
+func TestDetectImagePath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my shots")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	path := filepath.Join(dir, "cat.PNG")
+	require.NoError(t, os.WriteFile(path, []byte("x"), 0644))
+	notes := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(notes, []byte("x"), 0644))
+
+	for _, input := range []string{
+		"'" + path + "'",
+		`"` + path + `" `,
+		strings.ReplaceAll(path, " ", `\ `),
+		"file://" + strings.ReplaceAll(path, " ", "%20"),
+	} {
+		got, ok := DetectImagePath(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, path, got, input)
+	}
+
+	for _, input := range []string{
+		notes,
+		filepath.Join(dir, "gone.png"),
+		"look at " + path,
+		"what's in cat.png?",
+	} {
+		_, ok := DetectImagePath(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func newTestWizard(input string, opts InitOptions) (*InitWizard, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return NewInitWizard(strings.NewReader(input), out, opts), out
+}
+
+func TestInitWizardRun(t *testing.T) {
+	// provider #2 (grok), key, default model, no persona, skip tarot, zip, skip twitch, youtube key
+	input := "2\nsk-test\n\nn\n\n10001\n\nyt-key\n"
+	wizard, out := newTestWizard(input, InitOptions{})
+
+	var tested *config.Config
+	wizard.TestConnection = func(cfg *config.Config) error {
+		tested = cfg
+		return nil
+	}
+
+	cfg, err := wizard.Run()
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://api.x.ai/v1", cfg.BaseURL)
+	assert.Equal(t, "grok-4-latest", cfg.Model)
+	assert.Equal(t, "sk-test", cfg.APIKey)
+	assert.True(t, cfg.SkipPersonaPrompt)
+	assert.Empty(t, cfg.TarotAuthToken)
+	assert.Equal(t, "10001", cfg.WeatherDefaultZipCode)
+	assert.Empty(t, cfg.TwitchClientID)
+	assert.Equal(t, "yt-key", cfg.YouTubeAPIKey)
+	assert.Same(t, cfg, tested)
+	assert.Contains(t, out.String(), "Connection works")
+}
+
+func TestInitWizardChooseProvider(t *testing.T) {
+	wizard, _ := newTestWizard("venice\n", InitOptions{})
+	cfg, err := wizard.ChooseProvider()
+	require.NoError(t, err)
+	assert.Equal(t, "venice-uncensored", cfg.Model)
+
+	wizard, _ = newTestWizard("\n", InitOptions{})
+	cfg, err = wizard.ChooseProvider()
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.openai.com/v1", cfg.BaseURL)
+
+	wizard, _ = newTestWizard("nope\n", InitOptions{})
+	_, err = wizard.ChooseProvider()
+	assert.Error(t, err)
+}
+
+func TestInitWizardEnterAPIKey(t *testing.T) {
+	t.Run("re-prompts on empty key", func(t *testing.T) {
+		wizard, out := newTestWizard("\nsk-second\n", InitOptions{})
+		cfg := &config.Config{}
+		require.NoError(t, wizard.EnterAPIKey(cfg))
+		assert.Equal(t, "sk-second", cfg.APIKey)
+		assert.Contains(t, out.String(), "can't be empty")
+	})
+
+	t.Run("uses hidden reader", func(t *testing.T) {
+		wizard, _ := newTestWizard("", InitOptions{})
+		wizard.ReadSecret = func() (string, error) { return "sk-hidden", nil }
+		cfg := &config.Config{}
+		require.NoError(t, wizard.EnterAPIKey(cfg))
+		assert.Equal(t, "sk-hidden", cfg.APIKey)
+	})
+
+	t.Run("input ends", func(t *testing.T) {
+		wizard, _ := newTestWizard("", InitOptions{})
+		assert.Error(t, wizard.EnterAPIKey(&config.Config{}))
+	})
+}
+
+func TestInitWizardCheckConnection(t *testing.T) {
+	failing := func(cfg *config.Config) error { return errors.New("401 unauthorized") }
+
+	wizard, out := newTestWizard("n\n", InitOptions{})
+	wizard.TestConnection = failing
+	assert.Error(t, wizard.CheckConnection(&config.Config{}))
+	assert.Contains(t, out.String(), "401 unauthorized")
+
+	wizard, _ = newTestWizard("y\n", InitOptions{})
+	wizard.TestConnection = failing
+	assert.NoError(t, wizard.CheckConnection(&config.Config{}))
+
+	wizard, _ = newTestWizard("", InitOptions{NoTest: true})
+	wizard.TestConnection = failing
+	assert.NoError(t, wizard.CheckConnection(&config.Config{}))
+}
+
+func TestInitWizardNonInteractive(t *testing.T) {
+	opts := InitOptions{
+		Provider:       "openai",
+		APIKey:         "sk-flag",
+		Model:          "gpt-4o",
+		SkipPersona:    "true",
+		NoTest:         true,
+		NonInteractive: true,
+	}
+	wizard, _ := newTestWizard("", opts)
+	cfg, err := wizard.Run()
+	require.NoError(t, err)
+	assert.Equal(t, "sk-flag", cfg.APIKey)
+	assert.Equal(t, "gpt-4o", cfg.Model)
+	assert.True(t, cfg.SkipPersonaPrompt)
+
+	wizard, _ = newTestWizard("", InitOptions{NonInteractive: true})
+	_, err = wizard.Run()
+	assert.Error(t, err, "API key is required without a terminal")
+
+	overwrite, err := wizard.ConfirmOverwrite("/tmp/config.json")
+	require.NoError(t, err)
+	assert.False(t, overwrite)
+}
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxLen   int
+		expected string
+	}{
+		{"fits", "gpt-4o", 20, "gpt-4o"},
+		{"ascii", "claude-3-5-sonnet-latest", 10, "claude-..."},
+		{"accented", "Écarlate Ténèbres", 10, "Écarlat..."},
+		{"emoji", "🔮✨🌙⭐🃏🔥", 5, "🔮✨..."},
+		{"exact", "añejo", 5, "añejo"},
+		{"short max", "añejo", 3, "añe"},
+		{"max one", "🔮✨", 1, "🔮"},
+		{"zero", "abc", 0, ""},
+		{"negative", "abc", -2, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := truncateString(tt.input, tt.maxLen)
+			assert.Equal(t, tt.expected, result)
+			assert.True(t, utf8.ValidString(result))
+		})
+	}
+}
+
+func TestCorruptionFollowsAnimationSettings(t *testing.T) {
+	t.Cleanup(func() { config.SetAnimation((&config.Config{}).Animation()) })
+	text := "USAGE ANALYTICS"
+
+	for _, style := range []string{"off", "minimal"} {
+		config.SetAnimation((&config.Config{AnimationStyle: style}).Animation())
+		assert.Equal(t, text, corruptTextSimple(text, 1), style)
+		assert.Equal(t, text, corruptTextCharacterLevel(text, 1), style)
+		assert.Equal(t, text, corruptTextFlicker(text, 3), style)
+	}
+
+	// A custom glyph set only ever introduces its own characters
+	config.SetAnimation((&config.Config{AnimationGlyphs: "#"}).Animation())
+	corrupted := corruptTextCharacterLevel(text, 1)
+	assert.Equal(t, "##### #########", corrupted)
+}
+
+func TestExecuteSystem(t *testing.T) {
+	ctx := &CommandContext{Persona: "moderator", SystemPrompt: "You are a moderator."}
+	result := Execute(&Command{Name: "system"}, ctx)
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Message, "persona moderator, 20 chars")
+	assert.Contains(t, result.Message, "You are a moderator.")
+	assert.Nil(t, result.StateChange)
+
+	ctx.SystemCustom = true
+	result = Execute(&Command{Name: "system"}, ctx)
+	assert.Contains(t, result.Message, "override from /system set")
+
+	result = Execute(&Command{Name: "system"}, &CommandContext{})
+	assert.Contains(t, result.Message, "No system prompt is sent")
+
+	// The prompt keeps its spacing and newlines
+	cmd := Parse("/system  set   Be terse.\n\nNever  apologize. ")
+	result = Execute(cmd, ctx)
+	require.True(t, result.Success, result.Message)
+	require.NotNil(t, result.StateChange.SystemPrompt)
+	assert.Equal(t, "Be terse.\n\nNever  apologize.", *result.StateChange.SystemPrompt)
+
+	result = Execute(Parse("/system clear"), ctx)
+	require.True(t, result.Success)
+	require.NotNil(t, result.StateChange.SystemPrompt)
+	assert.Equal(t, "", *result.StateChange.SystemPrompt)
+
+	ctx.SystemCustom = false
+	result = Execute(Parse("/system clear"), ctx)
+	assert.True(t, result.Success)
+	assert.Nil(t, result.StateChange, "nothing to clear")
+
+	for _, input := range []string{"/system set", "/system set   ", "/system reset", "/system clear now"} {
+		result := Execute(Parse(input), ctx)
+		assert.False(t, result.Success, input)
+		assert.Nil(t, result.StateChange, input)
+	}
+}
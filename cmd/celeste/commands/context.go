@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/config"
+	"github.com/whykusanagi/celesteCLI/internal/config"
 )
 
 // HandleContextCommand handles the /context command and its subcommands.
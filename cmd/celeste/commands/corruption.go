@@ -3,6 +3,8 @@ package commands
 import (
 	"math/rand"
 	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
 )
 
 // Color constants shared across commands (avoiding import cycle with tui)
@@ -55,6 +57,15 @@ var (
 // corruptTextSimple creates contextual language corruption
 // Uses romanji, incomplete kanji, and context-appropriate glitches
 func corruptTextSimple(text string, intensity float64) string {
+	anim := config.CurrentAnimation()
+	intensity *= anim.Intensity
+	if !anim.Enabled || intensity <= 0 {
+		return text
+	}
+	if anim.Glyphs != nil {
+		return corruptWithGlyphs(text, intensity, anim.Glyphs)
+	}
+
 	words := strings.Fields(text)
 	if len(words) == 0 {
 		return text
@@ -124,9 +135,14 @@ func containsAny(text string, substrings []string) bool {
 // corruptTextCharacterLevel mixes Japanese characters INTO English words
 // This creates the classic translation-failure aesthetic: "loaディング", "pro理cessing"
 func corruptTextCharacterLevel(text string, intensity float64) string {
-	if intensity <= 0 {
+	anim := config.CurrentAnimation()
+	intensity *= anim.Intensity
+	if !anim.Enabled || intensity <= 0 {
 		return text
 	}
+	if anim.Glyphs != nil {
+		return corruptWithGlyphs(text, intensity, anim.Glyphs)
+	}
 
 	// Japanese character sets for character-level mixing
 	katakana := []rune("アイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワヲン")
@@ -171,14 +187,41 @@ func corruptTextCharacterLevel(text string, intensity float64) string {
 // corruptTextFlicker adds flickering corruption (like Celeste's animation)
 // Returns text with random corruption artifacts that appear/disappear
 func corruptTextFlicker(text string, frame int) string {
+	anim := config.CurrentAnimation()
+	if !anim.Enabled || anim.Intensity <= 0 {
+		return text
+	}
+
 	// Flicker intensity based on frame
-	flickerIntensity := 0.1 + float64(frame%4)*0.05
+	flickerIntensity := (0.1 + float64(frame%4)*0.05) * anim.Intensity
 
 	if rand.Float64() < flickerIntensity {
 		// Add trailing glitch
 		glitch := glitchFragments[rand.Intn(len(glitchFragments))]
+		if anim.Glyphs != nil {
+			glitch = string(anim.Glyphs[rand.Intn(len(anim.Glyphs))])
+		}
 		return text + " " + glitch
 	}
 
 	return text
 }
+
+// corruptWithGlyphs replaces letters with characters from a configured glyph
+// set instead of the built-in katakana/kanji mix.
+func corruptWithGlyphs(text string, intensity float64, glyphs []rune) string {
+	if len(glyphs) == 0 {
+		return text
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if r == ' ' || r < 'A' || (r > 'Z' && r < 'a') || r > 'z' {
+			continue
+		}
+		if rand.Float64() < intensity {
+			runes[i] = glyphs[rand.Intn(len(glyphs))]
+		}
+	}
+	return string(runes)
+}
@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/config"
+	"github.com/whykusanagi/celesteCLI/internal/config"
 )
 
 // Corruption-themed export phrases
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/skills"
+)
+
+// ImageAttachment is an image attached to a chat message with /image.
+type ImageAttachment struct {
+	Source  string // Path or URL as the user typed it
+	URL     string // http(s) URL or base64 data: URL sent to the model
+	Size    int64  // File size for local images, 0 for URLs
+	Message string // Text to send with the image now; empty queues it for the next message
+}
+
+// Label describes the image for the chat view without its data,
+// e.g. "cat.png, 1.2MB".
+func (a ImageAttachment) Label() string {
+	name := filepath.Base(a.Source)
+	if strings.HasPrefix(strings.ToLower(a.Source), "data:") {
+		name = "pasted image"
+	}
+	if a.Size > 0 {
+		return fmt.Sprintf("%s, %s", name, skills.FormatImageSize(a.Size))
+	}
+	return name
+}
+
+// LoadImageAttachment loads an image from a path or URL, refusing local
+// files over maxBytes (skills.MaxImageBytes if maxBytes <= 0).
+func LoadImageAttachment(source string, maxBytes int64) (ImageAttachment, error) {
+	url, size, err := skills.LoadImage(source, maxBytes)
+	if err != nil {
+		return ImageAttachment{}, err
+	}
+	return ImageAttachment{Source: source, URL: url, Size: size}, nil
+}
+
+// HandleImageCommand attaches an image to the conversation. Local files over
+// maxBytes are refused.
+// Usage:
+//
+//	/image <path|url>            -> attach to the next message
+//	/image <path|url> <message>  -> send the message with the image now
+//
+// /attach is an alias.
+func HandleImageCommand(args []string, supportsVision bool, model string, maxBytes int64) CommandResult {
+	if len(args) == 0 {
+		return CommandResult{
+			Success:      false,
+			Message:      "Usage: /image <path|url> [message]  (or /attach)\n\nExample: /image ~/Pictures/cat.png what breed is this?\n\nYou can also drag an image file into the terminal and press Enter.",
+			ShouldRender: true,
+		}
+	}
+
+	if !supportsVision {
+		return CommandResult{
+			Success:      false,
+			Message:      fmt.Sprintf("🖼️ %s doesn't accept images.\n\nSwitch to a vision-capable model (e.g. /model gpt-4o) and try again.", modelLabel(model)),
+			ShouldRender: true,
+		}
+	}
+
+	source := args[0]
+	loaded, err := LoadImageAttachment(source, maxBytes)
+	if err != nil {
+		return CommandResult{
+			Success:      false,
+			Message:      fmt.Sprintf("❌ Could not load image %s: %v", source, err),
+			ShouldRender: true,
+		}
+	}
+
+	attachment := &loaded
+	attachment.Message = strings.Join(args[1:], " ")
+
+	message := ""
+	if attachment.Message == "" {
+		message = fmt.Sprintf("🖼️ Attached [image: %s]. It will be sent with your next message.", attachment.Label())
+	}
+
+	return CommandResult{
+		Success:      true,
+		Message:      message,
+		ShouldRender: message != "",
+		StateChange: &StateChange{
+			AttachImage: attachment,
+		},
+	}
+}
+
+// imageExtensions are the file types DetectImagePath recognizes.
+var imageExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+}
+
+// DetectImagePath reports whether input is nothing but the path of an
+// existing image file, as terminals paste it when a file is dragged in:
+// possibly quoted, with backslash-escaped spaces, or as a file:// URL.
+func DetectImagePath(input string) (string, bool) {
+	path := strings.TrimSpace(input)
+	if len(path) >= 2 && (path[0] == '\'' || path[0] == '"') && path[len(path)-1] == path[0] {
+		path = path[1 : len(path)-1]
+	} else {
+		path = strings.ReplaceAll(path, `\ `, " ")
+	}
+	if strings.HasPrefix(path, "file://") {
+		u, err := url.Parse(path)
+		if err != nil {
+			return "", false
+		}
+		path = u.Path
+	}
+	if path == "" || strings.Contains(path, "\n") || !imageExtensions[strings.ToLower(filepath.Ext(path))] {
+		return "", false
+	}
+
+	if info, err := os.Stat(paths.ExpandHome(path)); err != nil || info.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// modelLabel names the model in user-facing messages.
+func modelLabel(model string) string {
+	if model == "" {
+		return "The current model"
+	}
+	return fmt.Sprintf("The current model (%s)", model)
+}
@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+// errSetupCancelled is returned when input ends before the wizard finishes.
+var errSetupCancelled = errors.New("setup cancelled: no more input")
+
+// InitOptions pre-answers steps of the init wizard. Any step that already
+// has an answer is skipped, so scripted setups can run without a terminal.
+type InitOptions struct {
+	Provider    string // Template name (openai, grok, ...)
+	APIKey      string
+	Model       string
+	SkipPersona string // "true" or "false"; empty asks
+	NoTest      bool   // Skip the connection test
+	SkipSkills  bool   // Skip skill credential prompts
+
+	// NonInteractive never reads input: unanswered steps keep the template
+	// defaults and the API key must be given.
+	NonInteractive bool
+}
+
+// InitWizard walks a new user through creating their configuration.
+// Each step reads from In and writes to Out so it can be driven by tests.
+type InitWizard struct {
+	In      *bufio.Reader
+	Out     io.Writer
+	Options InitOptions
+
+	// ReadSecret reads the API key without echoing it. Defaults to reading
+	// a line from In.
+	ReadSecret func() (string, error)
+
+	// TestConnection sends a minimal request with the new config.
+	// The connection step is skipped when nil.
+	TestConnection func(cfg *config.Config) error
+}
+
+// NewInitWizard creates a wizard reading answers from in and writing prompts to out.
+func NewInitWizard(in io.Reader, out io.Writer, opts InitOptions) *InitWizard {
+	return &InitWizard{
+		In:      bufio.NewReader(in),
+		Out:     out,
+		Options: opts,
+	}
+}
+
+// Run executes every step and returns the resulting configuration.
+// Nothing is saved; the caller decides where the config is written.
+func (w *InitWizard) Run() (*config.Config, error) {
+	fmt.Fprintln(w.Out, "✨ Welcome to Celeste! Let's get you set up.")
+
+	cfg, err := w.ChooseProvider()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.EnterAPIKey(cfg); err != nil {
+		return nil, err
+	}
+	if err := w.ChooseModel(cfg); err != nil {
+		return nil, err
+	}
+	if err := w.ChoosePersona(cfg); err != nil {
+		return nil, err
+	}
+	if err := w.CheckConnection(cfg); err != nil {
+		return nil, err
+	}
+	if err := w.ConfigureSkills(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ConfirmOverwrite asks before replacing an existing configuration.
+func (w *InitWizard) ConfirmOverwrite(path string) (bool, error) {
+	if w.Options.NonInteractive {
+		return false, nil
+	}
+	return w.confirm(fmt.Sprintf("A configuration already exists at %s. Overwrite it?", path), false)
+}
+
+// ChooseProvider picks a provider template and returns a config based on it.
+func (w *InitWizard) ChooseProvider() (*config.Config, error) {
+	names := config.TemplateNames()
+
+	name := w.Options.Provider
+	if name == "" {
+		if w.Options.NonInteractive {
+			name = names[0]
+		} else {
+			fmt.Fprintln(w.Out, "\nWhich provider do you want to use?")
+			for i, n := range names {
+				tmpl, _ := config.Template(n)
+				fmt.Fprintf(w.Out, "  %d) %-13s %s\n", i+1, n, tmpl.BaseURL)
+			}
+			answer, err := w.ask("Provider", names[0])
+			if err != nil {
+				return nil, err
+			}
+			name = answer
+			if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(names) {
+				name = names[i-1]
+			}
+		}
+	}
+
+	tmpl, ok := config.Template(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown provider '%s'. Available: %s", name, strings.Join(names, ", "))
+	}
+	return tmpl, nil
+}
+
+// EnterAPIKey reads the API key with input hidden.
+func (w *InitWizard) EnterAPIKey(cfg *config.Config) error {
+	if w.Options.APIKey != "" {
+		cfg.APIKey = w.Options.APIKey
+		return nil
+	}
+	if w.Options.NonInteractive {
+		return errors.New("an API key is required (use --api-key)")
+	}
+
+	for {
+		fmt.Fprint(w.Out, "\nAPI key (input hidden): ")
+		key, err := w.readSecret()
+		fmt.Fprintln(w.Out)
+		if err != nil {
+			return err
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			cfg.APIKey = key
+			return nil
+		}
+		fmt.Fprintln(w.Out, "The API key can't be empty.")
+	}
+}
+
+// ChooseModel optionally overrides the template's model.
+func (w *InitWizard) ChooseModel(cfg *config.Config) error {
+	if w.Options.Model != "" {
+		cfg.Model = w.Options.Model
+		return nil
+	}
+	if w.Options.NonInteractive {
+		return nil
+	}
+
+	model, err := w.ask("Model", cfg.Model)
+	if err != nil {
+		return err
+	}
+	cfg.Model = model
+	return nil
+}
+
+// ChoosePersona decides whether the Celeste persona prompt is injected.
+func (w *InitWizard) ChoosePersona(cfg *config.Config) error {
+	if w.Options.SkipPersona != "" {
+		cfg.SkipPersonaPrompt = strings.ToLower(w.Options.SkipPersona) == "true"
+		return nil
+	}
+	if w.Options.NonInteractive {
+		return nil
+	}
+
+	usePersona, err := w.confirm("Use the Celeste persona prompt?", !cfg.SkipPersonaPrompt)
+	if err != nil {
+		return err
+	}
+	cfg.SkipPersonaPrompt = !usePersona
+	return nil
+}
+
+// CheckConnection sends a one-token request to verify the endpoint and key.
+// Interactive users may keep a config that fails the test.
+func (w *InitWizard) CheckConnection(cfg *config.Config) error {
+	if w.Options.NoTest || w.TestConnection == nil {
+		return nil
+	}
+
+	fmt.Fprintf(w.Out, "\nTesting connection to %s...\n", cfg.BaseURL)
+	err := w.TestConnection(cfg)
+	if err == nil {
+		fmt.Fprintln(w.Out, "✓ Connection works")
+		return nil
+	}
+
+	fmt.Fprintf(w.Out, "❌ Connection failed: %v\n", err)
+	if w.Options.NonInteractive {
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	keep, askErr := w.confirm("Save this configuration anyway?", false)
+	if askErr != nil {
+		return askErr
+	}
+	if !keep {
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	return nil
+}
+
+// ConfigureSkills asks for optional skill credentials. A blank answer skips
+// that skill.
+func (w *InitWizard) ConfigureSkills(cfg *config.Config) error {
+	if w.Options.SkipSkills || w.Options.NonInteractive {
+		return nil
+	}
+
+	fmt.Fprintln(w.Out, "\nOptional skills (press Enter to skip any of them):")
+	prompts := []struct {
+		label string
+		field *string
+	}{
+		{"Tarot auth token", &cfg.TarotAuthToken},
+		{"Default weather zip code", &cfg.WeatherDefaultZipCode},
+		{"Twitch Client ID", &cfg.TwitchClientID},
+		{"YouTube API key", &cfg.YouTubeAPIKey},
+	}
+	for _, p := range prompts {
+		answer, err := w.ask(p.label, "")
+		if err != nil {
+			return err
+		}
+		*p.field = answer
+	}
+	return nil
+}
+
+// ask prints a prompt and returns the trimmed answer, or def when blank.
+func (w *InitWizard) ask(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.Out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(w.Out, "%s: ", label)
+	}
+
+	line, err := w.In.ReadString('\n')
+	if err != nil && line == "" {
+		return "", errSetupCancelled
+	}
+
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// confirm asks a yes/no question.
+func (w *InitWizard) confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer, err := w.ask(fmt.Sprintf("%s (%s)", question, hint), "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	default:
+		return def, nil
+	}
+}
+
+func (w *InitWizard) readSecret() (string, error) {
+	if w.ReadSecret != nil {
+		return w.ReadSecret()
+	}
+	line, err := w.In.ReadString('\n')
+	if err != nil && line == "" {
+		return "", errSetupCancelled
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/providers"
+	"github.com/whykusanagi/celesteCLI/internal/providers"
 )
 
 // HandleProvidersCommand handles the /providers command and its subcommands.
@@ -6,7 +6,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/config"
+	"github.com/whykusanagi/celesteCLI/internal/config"
 )
 
 // Corruption-themed phrases for stats dashboard
@@ -302,10 +302,18 @@ func getStatusEmoji(level string) string {
 	}
 }
 
-// truncateString truncates a string to a maximum length
+// truncateString truncates a string to at most maxLen runes, ending in "..."
+// when there is room for it. Multi-byte characters are never split.
 func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if maxLen <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-3]) + "..."
 }
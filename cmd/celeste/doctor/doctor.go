@@ -0,0 +1,118 @@
+// Package doctor checks that Celeste's configured integrations are reachable.
+// Each check is a short network probe; unconfigured integrations are skipped.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds each probe.
+const DefaultTimeout = 5 * time.Second
+
+// Status is the outcome of one check.
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusFail    Status = "fail"
+	StatusSkipped Status = "skipped"
+)
+
+// Check is one integration to probe. A nil Probe means the integration
+// isn't configured and the check is skipped.
+type Check struct {
+	Name  string
+	Hint  string // One-line remediation shown when the probe fails
+	Probe func(ctx context.Context) error
+}
+
+// Result is the outcome of running a Check.
+type Result struct {
+	Name    string
+	Status  Status
+	Latency time.Duration
+	Error   string
+	Hint    string
+}
+
+// Run probes every check concurrently, each with its own timeout, and
+// returns the results in check order.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) []Result {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		results[i] = Result{Name: check.Name, Status: StatusSkipped}
+		if check.Probe == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Probe(probeCtx)
+			results[i].Latency = time.Since(start)
+			if err != nil {
+				if probeCtx.Err() == context.DeadlineExceeded {
+					err = fmt.Errorf("no response within %s", timeout)
+				}
+				results[i].Status = StatusFail
+				results[i].Error = err.Error()
+				results[i].Hint = check.Hint
+				return
+			}
+			results[i].Status = StatusOK
+		}(i, check)
+	}
+	wg.Wait()
+	return results
+}
+
+// Passed reports whether every configured check succeeded.
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteTable prints results as a table, with the error and remediation hint
+// under each failed row.
+func WriteTable(w io.Writer, results []Result) {
+	width := len("Integration")
+	for _, r := range results {
+		if len(r.Name) > width {
+			width = len(r.Name)
+		}
+	}
+
+	fmt.Fprintf(w, "%-*s  %-7s  %s\n", width, "Integration", "Status", "Latency")
+	fmt.Fprintf(w, "%s\n", strings.Repeat("─", width+2+7+2+8))
+	for _, r := range results {
+		latency := "-"
+		if r.Status != StatusSkipped {
+			latency = r.Latency.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%-*s  %-7s  %s\n", width, r.Name, r.Status, latency)
+		if r.Status == StatusFail {
+			fmt.Fprintf(w, "%-*s  %s\n", width, "", r.Error)
+			if r.Hint != "" {
+				fmt.Fprintf(w, "%-*s  → %s\n", width, "", r.Hint)
+			}
+		}
+	}
+}
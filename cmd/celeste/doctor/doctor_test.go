@@ -0,0 +1,182 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+// stub answers every request with status and body, and records the last request.
+func stub(t *testing.T, status int, body string, last **http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if last != nil {
+			*last = r
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestProbeModels tests the chat/Venice endpoint probe
+func TestProbeModels(t *testing.T) {
+	var req *http.Request
+	server := stub(t, http.StatusOK, `{"data":[]}`, &req)
+	require.NoError(t, ProbeModels(server.URL+"/v1/", "sk-test")(context.Background()))
+	assert.Equal(t, "/v1/models", req.URL.Path)
+	assert.Equal(t, "Bearer sk-test", req.Header.Get("Authorization"))
+
+	// No model listing still means the endpoint is up
+	server = stub(t, http.StatusNotFound, "", nil)
+	assert.NoError(t, ProbeModels(server.URL, "sk-test")(context.Background()))
+
+	server = stub(t, http.StatusUnauthorized, "", nil)
+	assert.EqualError(t, ProbeModels(server.URL, "sk-bad")(context.Background()), "API key rejected (HTTP 401)")
+
+	server = stub(t, http.StatusBadGateway, "", nil)
+	assert.EqualError(t, ProbeModels(server.URL, "sk-test")(context.Background()), "HTTP 502")
+}
+
+// TestProbeTarot tests that a 400 with auth counts as reachable
+func TestProbeTarot(t *testing.T) {
+	var req *http.Request
+	server := stub(t, http.StatusBadRequest, "missing spread_type", &req)
+	require.NoError(t, ProbeTarot(server.URL, "dXNlcjpwYXNz")(context.Background()))
+	assert.Equal(t, http.MethodOptions, req.Method)
+	assert.Equal(t, "Basic dXNlcjpwYXNz", req.Header.Get("Authorization"))
+
+	server = stub(t, http.StatusForbidden, "", nil)
+	assert.ErrorContains(t, ProbeTarot(server.URL, "Basic bad")(context.Background()), "auth token rejected")
+
+	server = stub(t, http.StatusInternalServerError, "", nil)
+	assert.Error(t, ProbeTarot(server.URL, "token")(context.Background()))
+}
+
+// TestProbeTwitch tests the client credentials exchange
+func TestProbeTwitch(t *testing.T) {
+	var req *http.Request
+	server := stub(t, http.StatusOK, `{"access_token":"x"}`, &req)
+	require.NoError(t, ProbeTwitch(server.URL, "id", "secret")(context.Background()))
+	assert.Equal(t, http.MethodPost, req.Method)
+
+	server = stub(t, http.StatusBadRequest, `{"message":"invalid client"}`, nil)
+	assert.ErrorContains(t, ProbeTwitch(server.URL, "id", "wrong")(context.Background()), "client credentials rejected")
+}
+
+// TestProbeYouTube tests key validation and quota errors
+func TestProbeYouTube(t *testing.T) {
+	var req *http.Request
+	server := stub(t, http.StatusOK, `{"items":[]}`, &req)
+	require.NoError(t, ProbeYouTube(server.URL, "yt-key")(context.Background()))
+	assert.Equal(t, "/videos", req.URL.Path)
+	assert.Equal(t, "yt-key", req.URL.Query().Get("key"))
+
+	server = stub(t, http.StatusBadRequest, `{"error":{"message":"API key not valid. Please pass a valid API key."}}`, nil)
+	assert.EqualError(t, ProbeYouTube(server.URL, "bad")(context.Background()), "API key rejected")
+
+	server = stub(t, http.StatusForbidden, `{"error":{"message":"quotaExceeded"}}`, nil)
+	assert.ErrorContains(t, ProbeYouTube(server.URL, "yt-key")(context.Background()), "quota")
+}
+
+// TestProbeWeatherAndAlchemy tests the simple reachability probes
+func TestProbeWeatherAndAlchemy(t *testing.T) {
+	server := stub(t, http.StatusOK, "NYC: ☀️ +70°F", nil)
+	assert.NoError(t, ProbeWeather(server.URL)(context.Background()))
+	server = stub(t, http.StatusServiceUnavailable, "", nil)
+	assert.EqualError(t, ProbeWeather(server.URL)(context.Background()), "HTTP 503")
+
+	var req *http.Request
+	server = stub(t, http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, &req)
+	assert.NoError(t, ProbeAlchemy(server.URL)(context.Background()))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	server = stub(t, http.StatusUnauthorized, "", nil)
+	assert.ErrorContains(t, ProbeAlchemy(server.URL)(context.Background()), "API key rejected")
+}
+
+// TestProbeErrorHidesURL tests that connection errors don't leak keys in the URL
+func TestProbeErrorHidesURL(t *testing.T) {
+	err := ProbeYouTube("http://127.0.0.1:0", "secret-key")(context.Background())
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-key")
+}
+
+// TestRun tests concurrency, skipping, failures and timeouts
+func TestRun(t *testing.T) {
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	checks := []Check{
+		{Name: "ok", Probe: func(context.Context) error { return nil }},
+		{Name: "skipped", Hint: "configure it"},
+		{Name: "broken", Hint: "fix it", Probe: func(context.Context) error { return errors.New("boom") }},
+		{Name: "slow", Hint: "wait", Probe: slow},
+	}
+
+	start := time.Now()
+	results := Run(context.Background(), checks, 50*time.Millisecond)
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.Len(t, results, 4)
+	assert.Equal(t, StatusOK, results[0].Status)
+	assert.Equal(t, StatusSkipped, results[1].Status)
+	assert.Empty(t, results[1].Hint)
+	assert.Equal(t, StatusFail, results[2].Status)
+	assert.Equal(t, "boom", results[2].Error)
+	assert.Equal(t, "fix it", results[2].Hint)
+	assert.Equal(t, StatusFail, results[3].Status)
+	assert.Equal(t, "no response within 50ms", results[3].Error)
+	assert.False(t, Passed(results))
+
+	// Skipped checks don't fail the run
+	assert.True(t, Passed(results[:2]))
+
+	var out bytes.Buffer
+	WriteTable(&out, results)
+	assert.Contains(t, out.String(), "skipped")
+	assert.Contains(t, out.String(), "→ fix it")
+	assert.NotContains(t, out.String(), "configure it")
+}
+
+// TestChecksSkipsUnconfigured tests which integrations get probed
+func TestChecksSkipsUnconfigured(t *testing.T) {
+	checks := Checks(&config.Config{BaseURL: "https://api.openai.com/v1"})
+	probed := map[string]bool{}
+	for _, c := range checks {
+		probed[c.Name] = c.Probe != nil
+	}
+	assert.Equal(t, map[string]bool{
+		"Chat endpoint (openai)": false,
+		"Venice.ai":              false,
+		"Tarot function":         false,
+		"Weather (wttr.in)":      true,
+		"Twitch OAuth":           false,
+		"YouTube API":            false,
+		"Alchemy":                false,
+	}, probed)
+
+	checks = Checks(&config.Config{
+		APIKey:             "sk",
+		VeniceAPIKey:       "v",
+		TarotFunctionURL:   "https://tarot.example",
+		TarotAuthToken:     "t",
+		TwitchClientID:     "id",
+		TwitchClientSecret: "s",
+		YouTubeAPIKey:      "y",
+		AlchemyAPIKey:      "a",
+	})
+	for _, c := range checks {
+		assert.NotNil(t, c.Probe, c.Name)
+	}
+}
@@ -0,0 +1,251 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/providers"
+	"github.com/whykusanagi/celesteCLI/internal/skills"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// Endpoints used by the probes. Variables so tests can point them at stubs.
+var (
+	weatherURL     = "https://wttr.in"
+	twitchTokenURL = "https://id.twitch.tv/oauth2/token"
+	youtubeURL     = "https://www.googleapis.com/youtube/v3"
+	alchemyURL     = skills.BuildAlchemyURL
+)
+
+// Checks returns the integration checks for a config, in display order.
+func Checks(cfg *config.Config) []Check {
+	checks := []Check{
+		{
+			Name: fmt.Sprintf("Chat endpoint (%s)", providers.DetectProvider(cfg.BaseURL)),
+			Hint: "Check the key and URL: celeste config --set-key <key> / --set-url <url>",
+		},
+		{
+			Name: "Venice.ai",
+			Hint: "Check venice_api_key and venice_base_url in ~/.celeste/skills.json",
+		},
+		{
+			Name: "Tarot function",
+			Hint: "Check tarot_function_url and run: celeste config --set-tarot-token <token>",
+		},
+		{
+			Name:  "Weather (wttr.in)",
+			Hint:  "wttr.in may be down or blocked on this network",
+			Probe: ProbeWeather(weatherURL),
+		},
+		{
+			Name: "Twitch OAuth",
+			Hint: "Check twitch_client_id and twitch_client_secret in ~/.celeste/skills.json",
+		},
+		{
+			Name: "YouTube API",
+			Hint: "Check youtube_api_key and that the YouTube Data API v3 is enabled for it",
+		},
+		{
+			Name: "Alchemy",
+			Hint: "Check alchemy_api_key and alchemy_default_network in ~/.celeste/skills.json",
+		},
+	}
+
+	if cfg.APIKey != "" {
+		checks[0].Probe = ProbeModels(cfg.BaseURL, cfg.APIKey)
+	}
+	if cfg.VeniceAPIKey != "" {
+		checks[1].Probe = ProbeModels(cfg.VeniceBaseURL, cfg.VeniceAPIKey)
+	}
+	if cfg.TarotFunctionURL != "" && cfg.TarotAuthToken != "" {
+		checks[2].Probe = ProbeTarot(cfg.TarotFunctionURL, cfg.TarotAuthToken)
+	}
+	if cfg.TwitchClientID != "" && cfg.TwitchClientSecret != "" {
+		checks[4].Probe = ProbeTwitch(twitchTokenURL, cfg.TwitchClientID, cfg.TwitchClientSecret)
+	}
+	if cfg.YouTubeAPIKey != "" {
+		checks[5].Probe = ProbeYouTube(youtubeURL, cfg.YouTubeAPIKey)
+	}
+	if cfg.AlchemyAPIKey != "" {
+		network := cfg.AlchemyDefaultNetwork
+		if network == "" {
+			network = "eth-mainnet"
+		}
+		checks[6].Probe = ProbeAlchemy(alchemyURL(network, cfg.AlchemyAPIKey))
+	}
+	return checks
+}
+
+// ProbeModels checks an OpenAI-compatible endpoint by listing models, which
+// verifies the API key without spending tokens. Endpoints that don't list
+// models (404/405) still count as reachable.
+func ProbeModels(baseURL, apiKey string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		modelsURL, err := config.APIURL(baseURL, "models")
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, modelsURL, nil)
+		if err != nil {
+			return err
+		}
+		if apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+		status, _, err := do(req)
+		if err != nil {
+			return err
+		}
+		switch {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			return fmt.Errorf("API key rejected (HTTP %d)", status)
+		case status == http.StatusNotFound || status == http.StatusMethodNotAllowed:
+			return nil
+		case status >= 400:
+			return fmt.Errorf("HTTP %d", status)
+		}
+		return nil
+	}
+}
+
+// ProbeTarot checks the tarot function with an authenticated OPTIONS request.
+// Any answer other than an auth failure or server error counts as reachable,
+// including 400 for the missing body.
+func ProbeTarot(functionURL, authToken string) func(ctx context.Context) error {
+	// The token may already include the "Basic " prefix
+	if !strings.HasPrefix(authToken, "Basic ") {
+		authToken = "Basic " + authToken
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodOptions, functionURL, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", authToken)
+		status, _, err := do(req)
+		if err != nil {
+			return err
+		}
+		switch {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			return fmt.Errorf("auth token rejected (HTTP %d)", status)
+		case status >= 500:
+			return fmt.Errorf("HTTP %d", status)
+		}
+		return nil
+	}
+}
+
+// ProbeWeather checks that wttr.in answers.
+func ProbeWeather(baseURL string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/?format=3", nil)
+		if err != nil {
+			return err
+		}
+		status, _, err := do(req)
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK {
+			return fmt.Errorf("HTTP %d", status)
+		}
+		return nil
+	}
+}
+
+// ProbeTwitch checks the client credentials with a token exchange.
+func ProbeTwitch(tokenURL, clientID, clientSecret string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		form := url.Values{
+			"client_id":     {clientID},
+			"client_secret": {clientSecret},
+			"grant_type":    {"client_credentials"},
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		status, _, err := do(req)
+		if err != nil {
+			return err
+		}
+		switch {
+		case status == http.StatusBadRequest || status == http.StatusUnauthorized || status == http.StatusForbidden:
+			return fmt.Errorf("client credentials rejected (HTTP %d)", status)
+		case status != http.StatusOK:
+			return fmt.Errorf("HTTP %d", status)
+		}
+		return nil
+	}
+}
+
+// ProbeYouTube checks the API key with the cheapest Data API call.
+func ProbeYouTube(baseURL, apiKey string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		params := url.Values{"part": {"id"}, "chart": {"mostPopular"}, "maxResults": {"1"}, "key": {apiKey}}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/videos?"+params.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		status, body, err := do(req)
+		if err != nil {
+			return err
+		}
+		switch {
+		case status == http.StatusBadRequest && strings.Contains(body, "API key not valid"):
+			return fmt.Errorf("API key rejected")
+		case status == http.StatusForbidden:
+			return fmt.Errorf("API key not allowed or quota exceeded (HTTP %d)", status)
+		case status != http.StatusOK:
+			return fmt.Errorf("HTTP %d", status)
+		}
+		return nil
+	}
+}
+
+// ProbeAlchemy checks the Alchemy key with an eth_blockNumber call.
+func ProbeAlchemy(rpcURL string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		payload := `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcURL, strings.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		status, _, err := do(req)
+		if err != nil {
+			return err
+		}
+		switch {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			return fmt.Errorf("API key rejected (HTTP %d)", status)
+		case status != http.StatusOK:
+			return fmt.Errorf("HTTP %d", status)
+		}
+		return nil
+	}
+}
+
+// do sends a probe request and returns the status and up to 4KB of body.
+// Errors never include the request URL, which may carry an API key.
+func do(req *http.Request) (int, string, error) {
+	req.Header.Set("User-Agent", version.UserAgent())
+	resp, err := httpclient.New(0).Do(req)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return resp.StatusCode, string(body), nil
+}
@@ -3,37 +3,109 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
 
 	"github.com/whykusanagi/celesteCLI/cmd/celeste/commands"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/config"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/llm"
+	"github.com/whykusanagi/celesteCLI/cmd/celeste/doctor"
 	"github.com/whykusanagi/celesteCLI/cmd/celeste/monitor"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/prompts"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/providers"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/skills"
 	"github.com/whykusanagi/celesteCLI/cmd/celeste/tui"
+	"github.com/whykusanagi/celesteCLI/cmd/celeste/update"
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/llm"
+	"github.com/whykusanagi/celesteCLI/internal/notify"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/prompts"
+	"github.com/whykusanagi/celesteCLI/internal/providers"
+	"github.com/whykusanagi/celesteCLI/internal/skills"
+	"github.com/whykusanagi/celesteCLI/internal/venice"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+	"github.com/whykusanagi/celesteCLI/pkg/celeste"
 )
 
-// Version information
-const (
-	Version = "1.4.0"
-	Build   = "bubbletea-tui"
-)
+// Build names the interface flavor; the version itself is stamped into
+// internal/version at link time.
+const Build = "bubbletea-tui"
 
 // Global config name (set by -config flag)
 var configName string
 
+// Persona to use instead of the built-in one (set by --persona flag)
+var personaName string
+
+// Transcript log path for message/content runs (set by --transcript flag)
+var transcriptFlag string
+
+// Sampling overrides (set by --temperature, --top-p and --max-tokens flags)
+var samplingFlags config.Sampling
+
+// Fail instead of falling back when the server ignores streaming (set by
+// --require-stream flag)
+var requireStream bool
+
+// Extra request body fields layered over the config's extra_body (set by
+// --extra-body and --extra flags)
+var extraBodyFlags map[string]interface{}
+
+// Request timeout overriding the config's (set by --timeout flag)
+var timeoutFlag time.Duration
+
+// Announce long generations when they finish, as notify_on_complete does
+// (set by --notify flag)
+var notifyFlag bool
+
+// Comma-separated providers to send one prompt to (set by --compare flag)
+var compareFlag string
+
+// globalFlags lists the flags accepted before the command name, and whether
+// each takes a value. Either "-" or "--" may prefix them.
+var globalFlags = map[string]bool{
+	"config":         true,
+	"safe-mode":      false,
+	"persona":        true,
+	"transcript":     true,
+	"temperature":    true,
+	"top-p":          true,
+	"max-tokens":     true,
+	"timeout":        true,
+	"extra-body":     true,
+	"extra":          true,
+	"require-stream": false,
+	"notify":         false,
+	"no-color":       false,
+	"compare":        true,
+}
+
+// neutralThinkingPhrases is the subset of thinking phrases used in safe mode.
+var neutralThinkingPhrases = []string{
+	"Processing...",
+	"Thinking...",
+	"Analyzing...",
+	"Considering...",
+	"Contemplating...",
+}
+
 // Thinking phrases - shown when LLM makes tool calls without accompanying text
 // Similar to Claude Code's random words during thinking
 var thinkingPhrases = []string{
@@ -70,26 +142,37 @@ var thinkingPhrases = []string{
 
 // getRandomThinkingPhrase returns a random thinking phrase
 func getRandomThinkingPhrase() string {
-	if len(thinkingPhrases) == 0 {
+	phrases := thinkingPhrases
+	if config.IsSafeMode() {
+		phrases = neutralThinkingPhrases
+	}
+	if len(phrases) == 0 {
 		return "..."
 	}
-	return thinkingPhrases[time.Now().UnixNano()%int64(len(thinkingPhrases))]
+	return phrases[time.Now().UnixNano()%int64(len(phrases))]
 }
 
 func main() {
-	// Check for -config flag before command
-	args := os.Args[1:]
-	for i := 0; i < len(args); i++ {
-		if args[i] == "-config" && i+1 < len(args) {
-			configName = args[i+1]
-			// Remove -config and its value from args
-			args = append(args[:i], args[i+2:]...)
-			break
-		} else if strings.HasPrefix(args[i], "-config=") {
-			configName = strings.TrimPrefix(args[i], "-config=")
-			args = append(args[:i], args[i+1:]...)
-			break
-		}
+	args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Usage: celeste [global flags] <command> [arguments]")
+		os.Exit(1)
+	}
+	if !config.ColorEnabled() {
+		// Styles keep bold/underline but drop every color escape
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	// Skills and integrations share one HTTP timeout
+	if cfg, err := config.LoadNamed(configName); err == nil {
+		httpclient.SetDefaultTimeout(cfg.GetHTTPTimeout())
+	}
+
+	// --compare fans a single prompt out to several providers
+	if compareFlag != "" {
+		runCompareCommand(compareFlag, args)
+		return
 	}
 
 	// Parse command line
@@ -104,18 +187,32 @@ func main() {
 
 	command := args[0]
 	cmdArgs := args[1:]
+	notifyUpdate(command)
 
 	switch command {
 	case "chat":
+		if err := chatArgs(cmdArgs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(os.Stderr, "Usage: celeste chat [global flags]")
+			os.Exit(1)
+		}
 		runChatTUI()
+	case "init":
+		runInitCommand(cmdArgs)
 	case "config":
 		runConfigCommand(cmdArgs)
 	case "message", "msg":
-		if len(cmdArgs) < 1 {
-			fmt.Fprintln(os.Stderr, "Usage: celeste message <text>")
+		message, stream, err := messageArgs(cmdArgs)
+		if err != nil || message == "" {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			fmt.Fprintln(os.Stderr, "Usage: celeste message [--no-stream] [global flags] [--] <text>")
 			os.Exit(1)
 		}
-		runSingleMessage(strings.Join(cmdArgs, " "))
+		runSingleMessage(message, stream)
+	case "content":
+		runContentCommand(cmdArgs)
 	case "context":
 		runContextCommand(cmdArgs)
 	case "stats":
@@ -125,6 +222,8 @@ func main() {
 	case "skill":
 		// Execute a single skill: celeste skill <name> [args...]
 		runSkillExecuteCommand(cmdArgs)
+	case "tarot":
+		runTarotCommand(cmdArgs)
 	case "wallet-monitor":
 		// Manage wallet monitoring daemon: celeste wallet-monitor <start|stop|status|run>
 		runWalletMonitorCommand(cmdArgs)
@@ -134,14 +233,175 @@ func main() {
 		runProvidersCommand(cmdArgs)
 	case "session", "sessions":
 		runSessionCommand(cmdArgs)
+	case "workspace", "workspaces":
+		runWorkspaceCommand(cmdArgs)
+	case "doctor", "--check":
+		runDoctorCommand()
+	case "styles", "--list-styles":
+		runStylesCommand()
+	case "models", "--list-models":
+		runModelsCommand(cmdArgs)
+	case "update":
+		runUpdateCommand()
 	case "help", "-h", "--help":
 		printUsage()
 	case "version", "-v", "--version":
-		fmt.Printf("Celeste CLI %s (%s)\n", Version, Build)
+		fmt.Printf("Celeste CLI %s (%s)\n", version.Version, Build)
+		fmt.Printf("Commit:  %s\n", version.Commit)
+		fmt.Printf("Built:   %s\n", version.BuildDate)
 	default:
 		// Treat unknown command as a message
-		runSingleMessage(strings.Join(args, " "))
+		message, stream, err := messageArgs(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(os.Stderr, "Usage: celeste [global flags] <command> [arguments]")
+			os.Exit(1)
+		}
+		runSingleMessage(message, stream)
+	}
+}
+
+// parseGlobalFlags applies the global flags at the start of args and returns
+// the arguments from the command name on. Parsing stops at the first
+// argument that isn't a global flag, or after "--", so a command's own flags
+// and a message's text are never taken as global flags.
+func parseGlobalFlags(args []string) ([]string, error) {
+	for len(args) > 0 {
+		if args[0] == "--" {
+			args = args[1:]
+			break
+		}
+		if !strings.HasPrefix(args[0], "-") {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(args[0], "-"), "-"), "=")
+		takesValue, ok := globalFlags[name]
+		if !ok {
+			break
+		}
+		flagName := args[0]
+		if hasValue {
+			flagName, _, _ = strings.Cut(flagName, "=")
+		}
+		switch {
+		case takesValue && !hasValue:
+			if len(args) < 2 {
+				return nil, fmt.Errorf("%s needs a value", flagName)
+			}
+			value, args = args[1], args[2:]
+		case !takesValue && hasValue:
+			return nil, fmt.Errorf("%s doesn't take a value", flagName)
+		default:
+			args = args[1:]
+		}
+
+		if err := setGlobalFlag(name, value); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// extraBodyFlag and extraFlags hold the raw --extra-body and --extra values
+// seen so far; extraBodyFlags is rebuilt from them as each one arrives.
+var (
+	extraBodyFlag string
+	extraFlags    []string
+)
+
+// setGlobalFlag applies one global flag, wherever on the command line it was
+// given.
+func setGlobalFlag(name, value string) error {
+	switch name {
+	case "config":
+		configName = value
+	case "safe-mode":
+		config.EnableSafeMode()
+	case "persona":
+		if !prompts.IsPersona(value) {
+			return fmt.Errorf("unknown persona %q (available: %s); add personas as .json or .yaml files in %s",
+				value, strings.Join(prompts.ListPersonas(), ", "), prompts.PersonasDir())
+		}
+		personaName = value
+	case "transcript":
+		transcriptFlag = value
+	case "temperature", "top-p", "max-tokens":
+		var err error
+		if samplingFlags, err = config.ParseSamplingSetting(samplingFlags, name, value); err != nil {
+			return fmt.Errorf("invalid --%s: %v", name, err)
+		}
+	case "timeout":
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return fmt.Errorf("invalid --timeout: must be a positive number of seconds, got %q", value)
+		}
+		timeoutFlag = time.Duration(seconds) * time.Second
+	case "extra-body", "extra":
+		if name == "extra-body" {
+			extraBodyFlag = value
+		} else {
+			// --extra may repeat, so every occurrence is collected
+			extraFlags = append(extraFlags, value)
+		}
+		var err error
+		if extraBodyFlags, err = config.ParseExtraBodyFlags(extraBodyFlag, extraFlags); err != nil {
+			return fmt.Errorf("invalid --extra-body/--extra: %v", err)
+		}
+	case "require-stream":
+		requireStream = true
+	case "notify":
+		notifyFlag = true
+	case "no-color":
+		config.DisableColor()
+		lipgloss.SetColorProfile(termenv.Ascii)
+	case "compare":
+		compareFlag = value
+	}
+	return nil
+}
+
+// commandGlobalFlags are the global flags chat, message and content also
+// accept after the command name. -config and --compare pick the command's
+// setup, so they only work before it.
+var commandGlobalFlags = []string{
+	"safe-mode", "persona", "transcript", "temperature", "top-p", "max-tokens",
+	"timeout", "extra-body", "extra", "require-stream", "notify", "no-color",
+}
+
+// addGlobalFlags defines commandGlobalFlags on fs, so a command's flag set
+// applies them alongside its own flags.
+func addGlobalFlags(fs *flag.FlagSet) {
+	for _, name := range commandGlobalFlags {
+		name := name
+		usage := fmt.Sprintf("global --%s (see celeste help)", name)
+		if globalFlags[name] {
+			fs.Func(name, usage, func(value string) error { return setGlobalFlag(name, value) })
+		} else {
+			fs.BoolFunc(name, usage, func(value string) error {
+				if value != "true" {
+					return fmt.Errorf("doesn't take a value")
+				}
+				return setGlobalFlag(name, "")
+			})
+		}
+	}
+}
+
+// commandText returns the text left after fs parsed args. A global flag in
+// the text came after the text began, too late to apply, so it is reported
+// rather than sent as part of the prompt; "--" before the text allows one.
+func commandText(fs *flag.FlagSet, args []string) (string, error) {
+	text := fs.Args()
+	if parsed := len(args) - len(text); parsed > 0 && args[parsed-1] == "--" {
+		return strings.Join(text, " "), nil
+	}
+	for _, arg := range text {
+		name, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+		if _, ok := globalFlags[name]; ok && strings.HasPrefix(arg, "-") {
+			return "", fmt.Errorf("misplaced flag %s: flags go before the text (put -- before text that should include it)", arg)
+		}
 	}
+	return strings.Join(text, " "), nil
 }
 
 // hasDefaultConfig checks if a default configuration file exists.
@@ -157,18 +417,44 @@ func printUsage() {
 ✨ Celeste CLI - Interactive AI Assistant
 
 Usage:
-  celeste [-config <name>] <command> [arguments]
+  celeste [global flags] <command> [arguments]
 
-Global Flags:
+Global Flags (before the command, or after chat, message or content; "--" ends them):
   -config <name>          Use named config (loads ~/.celeste/config.<name>.json)
+  --safe-mode             Disable NSFW mode, auto-routing and image generation
+  --persona <name>        Use a persona from ~/.celeste/personas (chat, message, content)
+  --transcript <path>     Append message/content runs to a transcript log
+  --temperature <0-2>     Sampling temperature for this run
+  --top-p <0-1>           Nucleus sampling cutoff for this run
+  --max-tokens <n>        Cap on reply length for this run
+  --timeout <seconds>     Request timeout for chat, message and content,
+                          including the whole streamed reply
+  --require-stream        Fail if the server doesn't stream, instead of waiting
+                          for its buffered reply
+  --extra-body <json>     JSON object of provider-specific fields to add to
+                          every chat request, over the config's extra_body
+  --extra <key=value>     Add one such field (repeatable; dots nest, e.g.
+                          --extra retrieval.k=5; wins over --extra-body)
+  --notify                Ring the bell and send a desktop notification when a
+                          generation takes longer than notify_after_seconds
+  --no-color              Disable colored output
+  --compare <a,b,...>     Send one prompt to several providers side by side
 
 Commands:
+  init                    Set up Celeste interactively (first run)
   chat                    Launch interactive TUI mode
-  message <text>          Send a single message and exit
+  message <text>          Send a single message and exit, printing the reply
+                          as it streams (--no-stream waits for all of it)
+  content <request>       Generate platform-formatted content (see below)
   config                  View/modify configuration
   skills                  List and manage skills
   providers               List and query AI providers
   session                 Manage conversation sessions
+  workspace               List per-profile notes/reminders workspaces
+  doctor, --check         Check that configured integrations are reachable
+  update                  Download and install the latest release
+  styles, --list-styles   List image style presets (image: <prompt> --style <name>)
+  models, --list-models   List Venice.ai models and capabilities [--type text|image|upscale] [--refresh]
   context                 Show context/token usage
   stats                   Show usage statistics
   export                  Export session data
@@ -184,12 +470,16 @@ Interactive Commands (in chat mode):
   exit, quit, q           Exit the application
 
 Keyboard Shortcuts:
-  Ctrl+C                  Exit immediately
+  Ctrl+C / Esc            Cancel the reply being generated
+  Ctrl+C                  Exit (when no reply is being generated)
   PgUp/PgDown            Scroll chat history
   Shift+↑/↓              Scroll chat history
   ↑/↓                    Navigate input history
 
 Configuration:
+  celeste init                           Guided setup (provider, API key, skills)
+  celeste init --provider grok --api-key <key> --non-interactive
+                                         Scripted setup without prompts
   celeste config --show                  Show current config
   celeste config --list                  List all config profiles
   celeste config --init <name>           Create a new config profile
@@ -197,6 +487,16 @@ Configuration:
   celeste config --set-url <url>         Set API URL
   celeste config --set-model <model>     Set model
   celeste config --skip-persona <bool>   Skip persona prompt injection
+  celeste config --set-budget <usd>      Set monthly spend budget (0 disables)
+  celeste config --set-animation <style> Set corruption animation (glitch, subtle, minimal, off)
+
+Content:
+  celeste content --platform twitter --format short "announce tonight's stream"
+  celeste content --platform youtube --format long --tone hype --topic "Elden Ring"
+                                         --platform: twitter, tiktok, youtube, discord
+                                         --format: short (280 chars), long (5000 chars), general
+  celeste content --batch jobs.jsonl --batch-out posts --batch-concurrency 4
+                                         One job per line (or CSV with a header row)
 
 Skills:
   celeste skills --list                  List available skills
@@ -205,6 +505,12 @@ Skills:
   celeste skills --info <name>           Show skill information
   celeste skills --reload                Reload skills from disk
   celeste skill <name> [--args]          Execute a skill
+  celeste skill --describe <name> [--json] Show parameters, prerequisites and an example
+
+Tarot:
+  celeste tarot [--spread three] [--question <text>] [--save]
+                                         Draw a reading; --save adds it to the history
+  celeste tarot --history [--limit 10]   Show saved readings and recurring cards
 
 Providers:
   celeste providers                      List all AI providers
@@ -212,16 +518,42 @@ Providers:
   celeste providers info <name>          Show provider details
   celeste providers current              Show current provider
 
+Spend:
+  celeste stats --spend                  Show this month's estimated spend
+  celeste stats --spend --month 2025-06  Show spend for a given month
+
+Compare:
+  celeste --compare do,venice "<prompt>" Same prompt to each config profile
+  celeste --compare default,openai --json "<prompt>"
+                                         Print results as a JSON array
+
 Sessions:
   celeste session --list                 List saved sessions
-  celeste session --load <id>            Load a session
+  celeste session --load <id> [--full]   Load a session (--full includes archived messages)
   celeste session --clear                Clear all sessions
+  celeste session --export-all [--out <file>] [--include-secrets]
+                                         Back up sessions, skills and workspace data
+  celeste session --import <file> [--overwrite]
+                                         Restore a backup into ~/.celeste
+  celeste session --summarize <id> [--style bullets|narrative|tweet-thread] [--persona <name>] [--out <file>]
+                                         Recap a session in the persona's voice
+  celeste session --search <query> [--limit <n>] [--include-tools]
+                                         Find messages across saved sessions
+
+Workspaces:
+  celeste workspace --list               Show notes/reminders per profile workspace
+  celeste -config work skill save_note   Skills use the profile's workspace
 
 Environment Variables:
   CELESTE_API_KEY         API key (overrides config)
   CELESTE_API_ENDPOINT    API endpoint (overrides config)
   VENICE_API_KEY          Venice.ai API key for NSFW mode
+  CELESTE_SAFE_MODE       Set to 1 to enable safe mode (same as --safe-mode)
+  NO_COLOR                Set to disable colored output (same as --no-color)
   TAROT_AUTH_TOKEN        Tarot function auth token
+  CELESTE_HOME            Directory for all config and data (default ~/.celeste)
+  XDG_CONFIG_HOME         Config goes in $XDG_CONFIG_HOME/celeste when ~/.celeste doesn't exist
+  XDG_DATA_HOME           Data goes in $XDG_DATA_HOME/celeste when ~/.celeste doesn't exist
 
 Examples:
   celeste chat                           Start with default config
@@ -232,6 +564,29 @@ Examples:
 `)
 }
 
+// requestTimeout returns the --timeout value, or the config's timeout.
+func requestTimeout(cfg *config.Config) time.Duration {
+	if timeoutFlag > 0 {
+		return timeoutFlag
+	}
+	return cfg.GetTimeout()
+}
+
+// requestExtraBody returns the fields added to cfg's chat requests: its
+// extra_body with --extra-body and --extra on top.
+func requestExtraBody(cfg *config.Config) map[string]interface{} {
+	return config.MergeExtraBody(cfg.ExtraBody, extraBodyFlags)
+}
+
+// newNotifier returns the completion notifier when --notify or
+// notify_on_complete asks for one, or nil. Failures only reach the debug log.
+func newNotifier(cfg *config.Config) *notify.Notifier {
+	if !notifyFlag && !cfg.NotifyOnComplete {
+		return nil
+	}
+	return &notify.Notifier{Threshold: cfg.GetNotifyThreshold(), Bell: os.Stderr, Log: tui.LogInfo}
+}
+
 // runChatTUI launches the interactive Bubble Tea TUI.
 func runChatTUI() {
 	// Load configuration (named or default)
@@ -246,6 +601,18 @@ func runChatTUI() {
 		fmt.Fprintf(os.Stderr, "Using config: %s\n", configName)
 	}
 
+	// First run: walk through setup instead of failing on the missing key
+	if cfg.APIKey == "" && configName == "" && !hasDefaultConfig() && term.IsTerminal(os.Stdin.Fd()) {
+		if err := runInitWizard(commands.InitOptions{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Setup failed: %v\n", err)
+			os.Exit(1)
+		}
+		if cfg, err = config.Load(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Validate API key
 	if cfg.APIKey == "" {
 		fmt.Fprintln(os.Stderr, "No API key configured.")
@@ -257,6 +624,13 @@ func runChatTUI() {
 		os.Exit(1)
 	}
 
+	config.SetAnimation(cfg.Animation())
+
+	if err := cfg.Sampling().Merge(samplingFlags).Validate(cfg.Model, cfg.ContextLimit); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid sampling settings: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Initialize skill registry
 	registry := skills.NewRegistry()
 	if err := registry.LoadSkills(); err != nil {
@@ -267,18 +641,36 @@ func runChatTUI() {
 	configLoader := config.NewConfigLoader(cfg)
 	skills.RegisterBuiltinSkills(registry, configLoader)
 
+	// Safe mode: make sure the LLM can never see NSFW/image skills
+	safeMode := config.IsSafeMode()
+	if safeMode {
+		registry.RemoveNSFWSkills()
+		fmt.Fprintln(os.Stderr, "🛡️  Safe mode enabled: NSFW and image generation are disabled")
+	}
+
 	// Initialize LLM client
 	llmConfig := &llm.Config{
-		APIKey:            cfg.APIKey,
-		BaseURL:           cfg.BaseURL,
-		Model:             cfg.Model,
-		Timeout:           cfg.GetTimeout(),
-		SkipPersonaPrompt: cfg.SkipPersonaPrompt,
-		SimulateTyping:    cfg.SimulateTyping,
-		TypingSpeed:       cfg.TypingSpeed,
+		APIKey:             cfg.APIKey,
+		BaseURL:            cfg.BaseURL,
+		Model:              cfg.Model,
+		Timeout:            requestTimeout(cfg),
+		SkipPersonaPrompt:  cfg.SkipPersonaPrompt,
+		SimulateTyping:     cfg.SimulateTyping,
+		TypingSpeed:        cfg.TypingSpeed,
+		ToolResultFormat:   cfg.ToolResultFormat,
+		ToolResultMaxBytes: cfg.ToolResultMaxBytes,
+		Temperature:        cfg.Temperature,
+		TopP:               cfg.TopP,
+		MaxTokens:          cfg.MaxTokens,
+		ExtraBody:          requestExtraBody(cfg),
+		RequireStream:      requireStream,
+		Warnings:           tuiLogWriter{},
 	}
 	client := llm.NewClient(llmConfig, registry)
 
+	// Vision skills call back into the client for multimodal requests
+	skills.RegisterVisionSkills(registry, client)
+
 	// Set system prompt if not skipping
 	if !cfg.SkipPersonaPrompt {
 		client.SetSystemPrompt(prompts.GetSystemPrompt(false))
@@ -299,10 +691,17 @@ func runChatTUI() {
 
 	// Initialize session management
 	sessionManager := config.NewSessionManager()
+	sessionManager.SetMaxMessages(cfg.MaxSessionMessages)
 	var currentSession *config.Session
 
 	// Try to load latest session for auto-resume
 	if latest, err := sessionManager.LoadLatest(); err == nil {
+		// Fold in a reply that was still streaming when the TUI last died
+		if recovered, err := sessionManager.RecoverPartial(latest); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if recovered {
+			fmt.Fprintln(os.Stderr, "♻️  Recovered an interrupted reply")
+		}
 		fmt.Fprintf(os.Stderr, "📂 Resuming session: %s (%d messages)\n",
 			latest.ID[:8], len(latest.Messages))
 		currentSession = latest
@@ -315,10 +714,24 @@ func runChatTUI() {
 	app := tui.NewApp(tuiClient)
 
 	// Set version information
-	app = app.SetVersion(Version, Build)
+	app = app.SetVersion(version.Version, Build)
 
 	// Set configuration (for context limits, etc.)
 	app = app.SetConfig(cfg)
+	app = app.SetSafeMode(safeMode)
+	app = app.SetNotifier(newNotifier(cfg))
+
+	// ↑ recalls inputs from earlier runs too
+	historyPath, historySize := config.InputHistoryPath(), cfg.GetInputHistorySize()
+	inputHistory, err := config.LoadInputHistory(historyPath, historySize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	app = app.SetInputHistory(inputHistory, func(entry string) {
+		if err := config.AppendInputHistory(historyPath, entry, historySize); err != nil {
+			tui.LogInfo(fmt.Sprintf("Failed to save input history: %v", err))
+		}
+	})
 
 	// Restore messages from session if available
 	if len(currentSession.Messages) > 0 {
@@ -330,6 +743,9 @@ func runChatTUI() {
 				Content:   msg.Content,
 				Timestamp: msg.Timestamp,
 			}
+			for _, source := range msg.Images {
+				tuiMessages[i].ImageRefs = append(tuiMessages[i].ImageRefs, tui.ImageRef{Source: source})
+			}
 		}
 		app = app.WithMessages(tuiMessages)
 	}
@@ -373,15 +789,71 @@ func runChatTUI() {
 	}
 
 	// Create session manager adapter for TUI
-	smAdapter := &SessionManagerAdapter{manager: sessionManager}
+	smAdapter := &SessionManagerAdapter{
+		manager:   sessionManager,
+		autosaver: sessionManager.NewAutosaver(cfg.GetAutosaveInterval()),
+	}
+
+	// --persona overrides the persona saved with the session
+	if personaName != "" {
+		currentSession.SetPersona(personaName)
+	}
+	// Sampling flags likewise override the session's /set values
+	if !samplingFlags.IsZero() {
+		currentSession.SetSampling(currentSession.GetSampling().Merge(samplingFlags))
+	}
 
 	// Set session manager and current session
 	app = app.SetSessionManager(smAdapter, currentSession)
 
 	// Run the TUI
-	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	// Focus reports let completion notifications skip a window in use
+	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus())
+
+	sessionManager.OnLargeSession(func(id string, size int) {
+		limit := cfg.MaxSessionMessages
+		if limit <= 0 {
+			limit = config.DefaultMaxSessionMessages
+		}
+		p.Send(tui.NoticeMsg{Text: fmt.Sprintf(
+			"⚠️ This session's file is %.1f MB, which slows every save. Messages past max_session_messages (%d) are archived; lower it to keep the file smaller.",
+			float64(size)/(1<<20), limit)})
+	})
+
+	tuiClient.progress = func(msg tui.SkillProgressMsg) { p.Send(msg) }
+
+	// Optionally pick up skill file edits without a restart
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	if cfg.WatchSkills {
+		go func() {
+			err := registry.Watch(watchCtx, func(result skills.ReloadResult, err error) {
+				p.Send(tui.SkillsReloadedMsg{Summary: result.Summary(), Err: err})
+			})
+			if err != nil {
+				tui.LogInfo(fmt.Sprintf("Skill watcher stopped: %v", err))
+			}
+		}()
+	}
+
+	// A panic outside the program's own recovery still saves the reply so far
+	defer func() {
+		if r := recover(); r != nil {
+			tuiClient.FlushPartial()
+			_ = smAdapter.Flush()
+			panic(r)
+		}
+	}()
 
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	// Quitting mid-reply, or a panic Bubble Tea recovered, leaves the reply
+	// so far in the sidecar for the next start
+	tuiClient.FlushPartial()
+	// Write the last autosave the interval was holding back
+	if flushErr := smAdapter.Flush(); flushErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save session: %v\n", flushErr)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		os.Exit(1)
 	}
@@ -397,17 +869,89 @@ type TUIClientAdapter struct {
 	client     *llm.Client
 	registry   *skills.Registry
 	baseConfig *config.Config // Store base config for loading named configs
+	persona    string         // Persona chosen with /persona; empty means the default
+
+	systemOverride string // Prompt set with /system set; empty means the persona's
+
+	personaSampling config.Sampling // Sampling defaults from the persona file
+	sampling        config.Sampling // Session overrides from /set
+
+	partial *config.PartialWriter // Sidecar for the next streamed reply
+
+	// Delivers progress running skills report; set once the program exists
+	progress func(tui.SkillProgressMsg)
+
+	// Cancels the request sendMessage last started. Both are called from the
+	// TUI's update loop, so no lock is needed.
+	cancel context.CancelFunc
+}
+
+// tuiLogWriter sends LLM client warnings to the TUI log, since stderr would
+// draw over the alt screen.
+type tuiLogWriter struct{}
+
+func (tuiLogWriter) Write(p []byte) (int, error) {
+	tui.LogInfo(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+// RecordPartial implements tui.PartialRecorder.
+func (a *TUIClientAdapter) RecordPartial(w *config.PartialWriter) {
+	a.partial = w
+}
+
+// FlushPartial writes whatever has streamed of the current reply, for when
+// the TUI is going down before it could be saved.
+func (a *TUIClientAdapter) FlushPartial() {
+	if err := a.partial.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// CancelRequest implements tui.RequestCanceler.
+func (a *TUIClientAdapter) CancelRequest() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// SupportsVision implements tui.VisionChecker.
+func (a *TUIClientAdapter) SupportsVision() bool {
+	return a.client.SupportsVision()
 }
 
 // SendMessage implements tui.LLMClient.
 func (a *TUIClientAdapter) SendMessage(messages []tui.ChatMessage, tools []tui.SkillDefinition) tea.Cmd {
+	return a.sendMessage(messages, tools, nil)
+}
+
+// SendMessageWithTemperature implements tui.TemperatureSender.
+func (a *TUIClientAdapter) SendMessageWithTemperature(messages []tui.ChatMessage, tools []tui.SkillDefinition, temperature float32) tea.Cmd {
+	return a.sendMessage(messages, tools, &temperature)
+}
+
+// sendMessage streams a request, overriding the sampling temperature when set.
+func (a *TUIClientAdapter) sendMessage(messages []tui.ChatMessage, tools []tui.SkillDefinition, temperature *float32) tea.Cmd {
+	sampling := a.personaSampling.Merge(a.sampling)
+	timeout := a.client.GetConfig().Timeout
+	partial := a.partial
+	// Started here rather than in the command so a cancel can't miss it
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	a.cancel = cancel
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
+		ctx := llm.WithSampling(ctx, sampling)
+		if temperature != nil {
+			ctx = llm.WithTemperature(ctx, *temperature)
+		}
 
 		// Log the request with current endpoint info
 		currentConfig := a.client.GetConfig()
 		tui.LogInfo(fmt.Sprintf("→ Sending request to: %s (model: %s)", currentConfig.BaseURL, currentConfig.Model))
+		if len(currentConfig.ExtraBody) > 0 {
+			extra, _ := json.Marshal(currentConfig.ExtraBody)
+			tui.LogInfo(fmt.Sprintf("  Extra body: %s", extra))
+		}
 		tui.LogLLMRequest(len(messages), len(tools))
 
 		// Log message details for debugging
@@ -416,11 +960,15 @@ func (a *TUIClientAdapter) SendMessage(messages []tui.ChatMessage, tools []tui.S
 				i, msg.Role, len(msg.Content), len(msg.ToolCalls)))
 		}
 
-		// Check if we're sending tools to Venice uncensored (which may not support function calling)
-		if strings.Contains(currentConfig.BaseURL, "venice") && currentConfig.Model == "venice-uncensored" && len(tools) > 0 {
-			tui.LogInfo(fmt.Sprintf("  ⚠️  WARNING: Sending %d tools to venice-uncensored model", len(tools)))
-			tui.LogInfo("     Venice Uncensored may not support function calling")
-			tui.LogInfo("     Consider using llama-3.3-70b or qwen3-235b for function calling")
+		// Check Venice's model list for whether the model can take the tools
+		if strings.Contains(currentConfig.BaseURL, "venice") && len(tools) > 0 {
+			veniceConfig := venice.Config{APIKey: currentConfig.APIKey, BaseURL: currentConfig.BaseURL}
+			if model, found, err := venice.FindModel(ctx, veniceConfig, currentConfig.Model); err != nil {
+				tui.LogInfo(fmt.Sprintf("  Could not check Venice model capabilities: %v", err))
+			} else if found && !model.SupportsFunctionCalling {
+				tui.LogInfo(fmt.Sprintf("  ⚠️  WARNING: Sending %d tools to %s, which doesn't support function calling", len(tools), currentConfig.Model))
+				tui.LogInfo("     Run 'celeste models --type text' to see models that do")
+			}
 		}
 
 		var fullContent string
@@ -429,12 +977,20 @@ func (a *TUIClientAdapter) SendMessage(messages []tui.ChatMessage, tools []tui.S
 
 		err := a.client.SendMessageStream(ctx, messages, tools, func(chunk llm.StreamChunk) {
 			fullContent += chunk.Content
+			_ = partial.Append(chunk.Content) // Best effort; the reply still arrives
 			if chunk.IsFinal {
 				toolCalls = chunk.ToolCalls
 				usage = chunk.Usage // Capture token usage from final chunk
 			}
 		})
+		if err := partial.Flush(); err != nil {
+			tui.LogInfo(fmt.Sprintf("Failed to save partial reply: %v", err))
+		}
 
+		if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+			tui.LogInfo("LLM request cancelled")
+			return tui.StreamCanceledMsg{}
+		}
 		if err != nil {
 			// Extract detailed error information
 			errorMsg := err.Error()
@@ -447,7 +1003,8 @@ func (a *TUIClientAdapter) SendMessage(messages []tui.ChatMessage, tools []tui.S
 			tui.LogInfo(fmt.Sprintf("  Full error type: %T", err))
 
 			// Show helpful hint for Venice 400 errors
-			if strings.Contains(errorMsg, "400") && strings.Contains(currentConfig.BaseURL, "venice") {
+			var providerErr *llm.ProviderError
+			if errors.As(err, &providerErr) && providerErr.StatusCode == 400 && strings.Contains(currentConfig.BaseURL, "venice") {
 				tui.LogInfo("  💡 Venice.ai 400 error - possible causes:")
 				tui.LogInfo("     - Invalid model name (check model ID matches Venice docs)")
 				tui.LogInfo("     - API key might be invalid or expired")
@@ -463,17 +1020,24 @@ func (a *TUIClientAdapter) SendMessage(messages []tui.ChatMessage, tools []tui.S
 
 		// Handle tool calls
 		if len(toolCalls) > 0 {
-			tc := toolCalls[0]
-			tui.LogInfo(fmt.Sprintf("LLM requested tool call: %s (ID: %s)", tc.Name, tc.ID))
-
-			// Convert all tool calls to ToolCallInfo
+			// Carry every call through with its own ID; each result must be
+			// sent back against the call it answers
 			toolCallInfos := make([]tui.ToolCallInfo, len(toolCalls))
+			calls := make([]tui.FunctionCall, len(toolCalls))
 			for i, t := range toolCalls {
+				tui.LogInfo(fmt.Sprintf("LLM requested tool call: %s (ID: %s)", t.Name, t.ID))
 				toolCallInfos[i] = tui.ToolCallInfo{
 					ID:        t.ID,
 					Name:      t.Name,
 					Arguments: t.Arguments,
 				}
+				calls[i] = tui.FunctionCall{
+					ID:        t.ID,
+					Name:      t.Name,
+					Arguments: parseArgs(t.Arguments),
+					Status:    "executing",
+					Timestamp: time.Now(),
+				}
 			}
 
 			// If LLM made tool calls without any text content, show a random thinking phrase
@@ -485,13 +1049,7 @@ func (a *TUIClientAdapter) SendMessage(messages []tui.ChatMessage, tools []tui.S
 			}
 
 			return tui.SkillCallMsg{
-				Call: tui.FunctionCall{
-					Name:      tc.Name,
-					Arguments: parseArgs(tc.Arguments),
-					Status:    "executing",
-					Timestamp: time.Now(),
-				},
-				ToolCallID:       tc.ID,          // Store tool call ID for sending result back
+				Calls:            calls,
 				AssistantContent: displayContent, // Show thinking phrase if empty
 				ToolCalls:        toolCallInfos,
 			}
@@ -520,14 +1078,51 @@ func (a *TUIClientAdapter) GetSkills() []tui.SkillDefinition {
 	return a.client.GetSkills()
 }
 
+// ReloadSkills implements tui.SkillsReloader.
+func (a *TUIClientAdapter) ReloadSkills() (string, error) {
+	result, err := a.registry.Reload()
+	if err != nil {
+		return "", err
+	}
+	return result.Summary(), nil
+}
+
+// SummarizeConversation implements tui.ConversationSummarizer.
+func (a *TUIClientAdapter) SummarizeConversation(messages []tui.ChatMessage, style string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		sessionMsgs := make([]config.SessionMessage, len(messages))
+		for i, msg := range messages {
+			sessionMsgs[i] = config.SessionMessage{
+				Role:      msg.Role,
+				Content:   msg.Content,
+				Name:      msg.Name,
+				Timestamp: msg.Timestamp,
+			}
+		}
+		summary, err := llm.NewSummarizer(a.client).Recap(ctx, sessionMsgs, style)
+		if err == nil && style == "tweet-thread" {
+			summary = strings.Join(llm.SplitThread(summary, llm.TweetLimit), "\n\n")
+		}
+		return tui.ConversationSummaryMsg{Summary: summary, Err: err}
+	}
+}
+
 // ExecuteSkill implements tui.LLMClient.
 func (a *TUIClientAdapter) ExecuteSkill(name string, args map[string]any, toolCallID string) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), tui.SkillTimeout)
 		defer cancel()
+		if a.progress != nil {
+			ctx = skills.WithProgress(ctx, func(status string) {
+				a.progress(tui.SkillProgressMsg{ToolCallID: toolCallID, Status: status})
+			})
+		}
 
 		startTime := time.Now()
-		tui.LogInfo(fmt.Sprintf("Executing skill '%s' with timeout: 30s", name))
+		tui.LogInfo(fmt.Sprintf("Executing skill '%s' with timeout: %v", name, tui.SkillTimeout))
 
 		// Convert args to JSON
 		argsJSON, err := json.Marshal(args)
@@ -543,6 +1138,9 @@ func (a *TUIClientAdapter) ExecuteSkill(name string, args map[string]any, toolCa
 
 		// Execute the skill
 		result, err := a.client.ExecuteSkill(ctx, name, string(argsJSON))
+		if result != nil {
+			tui.LogSkillDefaults(name, result.DefaultsApplied)
+		}
 
 		elapsed := time.Since(startTime)
 		if err != nil {
@@ -639,24 +1237,36 @@ func (a *TUIClientAdapter) SwitchEndpoint(endpoint string) error {
 
 	// Update LLM client configuration
 	llmConfig := &llm.Config{
-		APIKey:            cfg.APIKey,
-		BaseURL:           cfg.BaseURL,
-		Model:             cfg.Model,
-		Timeout:           cfg.GetTimeout(),
-		SkipPersonaPrompt: cfg.SkipPersonaPrompt,
-		SimulateTyping:    cfg.SimulateTyping,
-		TypingSpeed:       cfg.TypingSpeed,
+		APIKey:             cfg.APIKey,
+		BaseURL:            cfg.BaseURL,
+		Model:              cfg.Model,
+		Timeout:            requestTimeout(cfg),
+		SkipPersonaPrompt:  cfg.SkipPersonaPrompt,
+		SimulateTyping:     cfg.SimulateTyping,
+		TypingSpeed:        cfg.TypingSpeed,
+		ToolResultFormat:   cfg.ToolResultFormat,
+		ToolResultMaxBytes: cfg.ToolResultMaxBytes,
+		Temperature:        cfg.Temperature,
+		TopP:               cfg.TopP,
+		MaxTokens:          cfg.MaxTokens,
+		ExtraBody:          requestExtraBody(cfg),
+		RequireStream:      requireStream,
+		Warnings:           tuiLogWriter{},
 	}
 
 	a.client.UpdateConfig(llmConfig)
 
-	// Re-inject Celeste persona prompt after endpoint switch (unless explicitly skipped)
-	if !cfg.SkipPersonaPrompt {
-		a.client.SetSystemPrompt(prompts.GetSystemPrompt(false))
-		tui.LogInfo("✓ Celeste persona prompt re-injected after endpoint switch")
+	// Re-inject the session's persona prompt after endpoint switch. The new
+	// config's SkipPersonaPrompt only clears it when no persona was chosen.
+	prompt, err := prompts.PersonaPrompt(a.persona, cfg.SkipPersonaPrompt)
+	if err != nil {
+		tui.LogInfo(fmt.Sprintf("Warning: %v, using default persona", err))
+		prompt = prompts.GetSystemPrompt(false)
+	}
+	a.setPrompt(prompt)
+	if prompt != "" {
+		tui.LogInfo("✓ Persona prompt re-injected after endpoint switch")
 	} else {
-		// Clear system prompt if persona is disabled in new config
-		a.client.SetSystemPrompt("")
 		tui.LogInfo("  Persona prompt skipped (SkipPersonaPrompt = true)")
 	}
 
@@ -674,17 +1284,85 @@ func (a *TUIClientAdapter) SwitchEndpoint(endpoint string) error {
 	return nil
 }
 
+// SetPersona implements tui.PersonaSwitcher. An empty name returns to the
+// default persona.
+func (a *TUIClientAdapter) SetPersona(name string) error {
+	prompt, err := prompts.PersonaPrompt(name, a.client.GetConfig().SkipPersonaPrompt)
+	if err != nil {
+		return err
+	}
+	a.persona = name
+	a.setPrompt(prompt)
+	if a.personaSampling, err = prompts.PersonaSampling(name); err != nil {
+		tui.LogInfo(fmt.Sprintf("Warning: %v, ignoring persona sampling", err))
+	}
+	if name == "" {
+		name = prompts.DefaultPersona
+	}
+	tui.LogInfo(fmt.Sprintf("✓ Switched persona to: %s", name))
+	return nil
+}
+
+// SystemPrompt implements tui.SystemPromptOverrider.
+func (a *TUIClientAdapter) SystemPrompt() (string, bool) {
+	return a.client.GetSystemPrompt(), a.systemOverride != ""
+}
+
+// OverrideSystemPrompt implements tui.SystemPromptOverrider. The override
+// is kept across persona and endpoint switches; an empty prompt removes it
+// and goes back to the persona's.
+func (a *TUIClientAdapter) OverrideSystemPrompt(prompt string) {
+	a.systemOverride = prompt
+	if prompt != "" {
+		a.client.SetSystemPrompt(prompt)
+		tui.LogInfo(fmt.Sprintf("✓ System prompt overridden (%d chars)", len(prompt)))
+		return
+	}
+
+	personaPrompt, err := prompts.PersonaPrompt(a.persona, a.client.GetConfig().SkipPersonaPrompt)
+	if err != nil {
+		tui.LogInfo(fmt.Sprintf("Warning: %v, using default persona", err))
+		personaPrompt = prompts.GetSystemPrompt(false)
+	}
+	a.client.SetSystemPrompt(personaPrompt)
+	tui.LogInfo("✓ System prompt override cleared")
+}
+
+// setPrompt sends a persona's prompt from now on, unless /system set has
+// overridden it.
+func (a *TUIClientAdapter) setPrompt(prompt string) {
+	if a.systemOverride != "" {
+		tui.LogInfo("  Keeping the system prompt set with /system set")
+		prompt = a.systemOverride
+	}
+	a.client.SetSystemPrompt(prompt)
+}
+
+// SetSampling implements tui.SamplingSetter. The values override the config
+// and persona defaults for every following request.
+func (a *TUIClientAdapter) SetSampling(sampling config.Sampling) {
+	a.sampling = sampling
+}
+
 // ChangeModel changes the model for the current endpoint.
 func (a *TUIClientAdapter) ChangeModel(model string) error {
 	currentConfig := a.client.GetConfig()
 	newConfig := &llm.Config{
-		APIKey:            currentConfig.APIKey,
-		BaseURL:           currentConfig.BaseURL,
-		Model:             model,
-		Timeout:           currentConfig.Timeout,
-		SkipPersonaPrompt: currentConfig.SkipPersonaPrompt,
-		SimulateTyping:    currentConfig.SimulateTyping,
-		TypingSpeed:       currentConfig.TypingSpeed,
+		APIKey:             currentConfig.APIKey,
+		BaseURL:            currentConfig.BaseURL,
+		Model:              model,
+		Timeout:            currentConfig.Timeout,
+		SkipPersonaPrompt:  currentConfig.SkipPersonaPrompt,
+		SimulateTyping:     currentConfig.SimulateTyping,
+		TypingSpeed:        currentConfig.TypingSpeed,
+		ToolResultFormat:   currentConfig.ToolResultFormat,
+		ToolResultMaxBytes: currentConfig.ToolResultMaxBytes,
+		Temperature:        currentConfig.Temperature,
+		TopP:               currentConfig.TopP,
+		MaxTokens:          currentConfig.MaxTokens,
+		ExtraBody:          currentConfig.ExtraBody,
+		RequireStream:      currentConfig.RequireStream,
+		Warnings:           currentConfig.Warnings,
 	}
 
 	a.client.UpdateConfig(newConfig)
@@ -714,6 +1392,8 @@ func runConfigCommand(args []string) {
 	skipPersona := fs.String("skip-persona", "", "Skip persona prompt (true/false)")
 	simulateTyping := fs.String("simulate-typing", "", "Simulate typing (true/false)")
 	typingSpeed := fs.Int("typing-speed", 0, "Typing speed (chars/sec)")
+	setBudget := fs.Float64("set-budget", -1, "Set monthly spend budget in USD (0 disables)")
+	setAnimation := fs.String("set-animation", "", "Set corruption animation style ("+strings.Join(config.AnimationStyleNames(), ", ")+")")
 
 	// Google Cloud authentication flags
 	setGoogleCredentials := fs.String("set-google-credentials", "", "Set Google Cloud service account JSON file path")
@@ -724,10 +1404,16 @@ func runConfigCommand(args []string) {
 	setVeniceKey := fs.String("set-venice-key", "", "Set Venice.ai API key (saved to skills.json)")
 	setTarotURL := fs.String("set-tarot-url", "", "Set tarot function URL (saved to skills.json)")
 	setWeatherZip := fs.String("set-weather-zip", "", "Set default weather zip code (saved to skills.json)")
+	setWeatherAlerts := fs.String("set-weather-alerts", "", "Set the weather alerts source: nws or off (saved to skills.json)")
+	setWeatherUnits := fs.String("set-weather-units", "", "Set weather units: auto, metric or imperial (saved to skills.json)")
+	setGeolocationProvider := fs.String("set-geolocation-provider", "", "Set IP geolocation provider: ipapi, ipinfo, ip-api or off (saved to skills.json)")
 	setTwitchClientID := fs.String("set-twitch-client-id", "", "Set Twitch Client ID (saved to skills.json)")
 	setTwitchStreamer := fs.String("set-twitch-streamer", "", "Set default Twitch streamer (saved to skills.json)")
 	setYouTubeKey := fs.String("set-youtube-key", "", "Set YouTube API key (saved to skills.json)")
 	setYouTubeChannel := fs.String("set-youtube-channel", "", "Set default YouTube channel (saved to skills.json)")
+	setDiscordWebhook := fs.String("set-discord-webhook", "", "Set Discord webhook URL for post_discord (saved to skills.json)")
+	setMastodonInstance := fs.String("set-mastodon-instance", "", "Set Mastodon instance URL for post_mastodon (saved to skills.json)")
+	setMastodonToken := fs.String("set-mastodon-token", "", "Set Mastodon access token for post_mastodon (saved to skills.json)")
 
 	// Parse flags - exits on error due to ExitOnError flag
 	_ = fs.Parse(args)
@@ -774,15 +1460,42 @@ func runConfigCommand(args []string) {
 		fmt.Println("API key updated")
 	}
 	if *setURL != "" {
-		cfg.BaseURL = *setURL
+		baseURL, trimmed, err := config.NormalizeBaseURL(*setURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if trimmed {
+			fmt.Fprintf(os.Stderr, "Warning: removed /chat/completions from the URL; Celeste adds it to every request\n")
+		}
+		cfg.BaseURL = baseURL
 		changed = true
-		fmt.Printf("API URL set to: %s\n", *setURL)
+		fmt.Printf("API URL set to: %s\n", baseURL)
 	}
 	if *setModel != "" {
 		cfg.Model = *setModel
 		changed = true
 		fmt.Printf("Model set to: %s\n", *setModel)
 	}
+	if *setBudget >= 0 {
+		cfg.MonthlyBudgetUSD = *setBudget
+		changed = true
+		if *setBudget == 0 {
+			fmt.Println("Monthly budget disabled")
+		} else {
+			fmt.Printf("Monthly budget set to: %s\n", config.FormatCost(*setBudget))
+		}
+	}
+	if *setAnimation != "" {
+		if !config.IsAnimationStyle(*setAnimation) {
+			fmt.Fprintf(os.Stderr, "Error: unknown animation style '%s'. Available: %s\n",
+				*setAnimation, strings.Join(config.AnimationStyleNames(), ", "))
+			os.Exit(1)
+		}
+		cfg.AnimationStyle = strings.ToLower(*setAnimation)
+		changed = true
+		fmt.Printf("Animation style set to: %s\n", cfg.AnimationStyle)
+	}
 	if *skipPersona != "" {
 		cfg.SkipPersonaPrompt = strings.ToLower(*skipPersona) == "true"
 		changed = true
@@ -851,6 +1564,40 @@ func runConfigCommand(args []string) {
 		skillsChanged = true
 		fmt.Printf("Default weather zip code set to: %s (saved to skills.json)\n", zip)
 	}
+	if *setWeatherAlerts != "" {
+		source := strings.ToLower(*setWeatherAlerts)
+		if source != "nws" && source != "off" {
+			fmt.Fprintf(os.Stderr, "Error: weather alerts source must be nws or off\n")
+			os.Exit(1)
+		}
+		cfg.WeatherAlerts = source
+		skillsChanged = true
+		fmt.Printf("Weather alerts source set to: %s (saved to skills.json)\n", source)
+	}
+	if *setWeatherUnits != "" {
+		units := strings.ToLower(*setWeatherUnits)
+		switch units {
+		case "auto", "metric", "imperial":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: weather units must be auto, metric or imperial\n")
+			os.Exit(1)
+		}
+		cfg.WeatherUnits = units
+		skillsChanged = true
+		fmt.Printf("Weather units set to: %s (saved to skills.json)\n", units)
+	}
+	if *setGeolocationProvider != "" {
+		provider := strings.ToLower(*setGeolocationProvider)
+		switch provider {
+		case "ipapi", "ipinfo", "ip-api", "off":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: geolocation provider must be ipapi, ipinfo, ip-api or off\n")
+			os.Exit(1)
+		}
+		cfg.GeolocationProvider = provider
+		skillsChanged = true
+		fmt.Printf("Geolocation provider set to: %s (saved to skills.json)\n", provider)
+	}
 	if *setTwitchClientID != "" {
 		cfg.TwitchClientID = *setTwitchClientID
 		skillsChanged = true
@@ -871,6 +1618,29 @@ func runConfigCommand(args []string) {
 		skillsChanged = true
 		fmt.Printf("Default YouTube channel set to: %s (saved to skills.json)\n", *setYouTubeChannel)
 	}
+	if *setDiscordWebhook != "" {
+		if !strings.HasPrefix(*setDiscordWebhook, "https://") {
+			fmt.Fprintf(os.Stderr, "Error: Discord webhook URL must start with https://\n")
+			os.Exit(1)
+		}
+		cfg.DiscordWebhookURL = *setDiscordWebhook
+		skillsChanged = true
+		fmt.Printf("Discord webhook URL set (saved to skills.json)\n")
+	}
+	if *setMastodonInstance != "" {
+		instance := *setMastodonInstance
+		if !strings.Contains(instance, "://") {
+			instance = "https://" + instance
+		}
+		cfg.MastodonInstanceURL = strings.TrimRight(instance, "/")
+		skillsChanged = true
+		fmt.Printf("Mastodon instance set to: %s (saved to skills.json)\n", cfg.MastodonInstanceURL)
+	}
+	if *setMastodonToken != "" {
+		cfg.MastodonAccessToken = *setMastodonToken
+		skillsChanged = true
+		fmt.Printf("Mastodon access token set (saved to skills.json)\n")
+	}
 
 	if changed {
 		if err := config.Save(cfg); err != nil {
@@ -893,6 +1663,12 @@ func runConfigCommand(args []string) {
 	}
 
 	if *showConfig || !changed {
+		configDir, dataDir, scheme := paths.Resolved()
+		fmt.Printf("\nLocations (%s):\n", scheme)
+		fmt.Printf("  Config:            %s\n", config.NamedConfigPath(configName))
+		fmt.Printf("  Config Directory:  %s\n", configDir)
+		fmt.Printf("  Data Directory:    %s\n", dataDir)
+
 		fmt.Printf("\nCurrent Configuration:\n")
 		fmt.Printf("  API URL:           %s\n", cfg.BaseURL)
 		fmt.Printf("  Model:             %s\n", cfg.Model)
@@ -900,13 +1676,44 @@ func runConfigCommand(args []string) {
 		fmt.Printf("  Skip Persona:      %v\n", cfg.SkipPersonaPrompt)
 		fmt.Printf("  Simulate Typing:   %v\n", cfg.SimulateTyping)
 		fmt.Printf("  Typing Speed:      %d chars/sec\n", cfg.TypingSpeed)
+		fmt.Printf("  Animation:         %s\n", cfg.Animation().Style)
+		fmt.Printf("  Safe Mode:         %v\n", config.IsSafeMode())
+		if extra := requestExtraBody(cfg); extra != nil {
+			data, _ := json.Marshal(extra)
+			fmt.Printf("  Extra Body:        %s\n", data)
+		}
+		if cfg.MonthlyBudgetUSD > 0 {
+			fmt.Printf("  Monthly Budget:    %s\n", config.FormatCost(cfg.MonthlyBudgetUSD))
+		} else {
+			fmt.Printf("  Monthly Budget:    (not set)\n")
+		}
 		fmt.Printf("  Venice API Key:    %s\n", maskKey(cfg.VeniceAPIKey))
 		fmt.Printf("  Tarot Configured:  %v\n", cfg.TarotAuthToken != "")
 		fmt.Printf("  Twitter Configured:%v\n", cfg.TwitterBearerToken != "")
+		fmt.Printf("  Twitter Posting:   %v\n", cfg.TwitterAPIKey != "" && cfg.TwitterAccessToken != "")
 		if cfg.WeatherDefaultZipCode != "" {
 			fmt.Printf("  Weather Zip Code:  %s\n", cfg.WeatherDefaultZipCode)
 		} else {
-			fmt.Printf("  Weather Zip Code:  (not set)\n")
+			if cfg.GeolocationProvider == "off" {
+				fmt.Printf("  Weather Zip Code:  (not set)\n")
+			} else {
+				fmt.Printf("  Weather Zip Code:  (not set, using IP location)\n")
+			}
+		}
+		if cfg.WeatherAlerts != "" {
+			fmt.Printf("  Weather Alerts:    %s\n", cfg.WeatherAlerts)
+		} else {
+			fmt.Printf("  Weather Alerts:    nws (default, US only)\n")
+		}
+		if cfg.WeatherUnits != "" {
+			fmt.Printf("  Weather Units:     %s\n", cfg.WeatherUnits)
+		} else {
+			fmt.Printf("  Weather Units:     auto (imperial in the US, metric elsewhere)\n")
+		}
+		if cfg.GeolocationProvider != "" {
+			fmt.Printf("  Geolocation:       %s\n", cfg.GeolocationProvider)
+		} else {
+			fmt.Printf("  Geolocation:       ipapi (default)\n")
 		}
 		if cfg.TwitchClientID != "" {
 			fmt.Printf("  Twitch Client ID:   %s\n", maskKey(cfg.TwitchClientID))
@@ -928,57 +1735,163 @@ func runConfigCommand(args []string) {
 		} else {
 			fmt.Printf("  YouTube:           (not configured)\n")
 		}
+		if cfg.DiscordWebhookURL != "" {
+			fmt.Printf("  Discord Webhook:   %s\n", maskKey(cfg.DiscordWebhookURL))
+		} else {
+			fmt.Printf("  Discord:           (not configured)\n")
+		}
+		if cfg.MastodonInstanceURL != "" && cfg.MastodonAccessToken != "" {
+			fmt.Printf("  Mastodon:          %s (token %s)\n", cfg.MastodonInstanceURL, maskKey(cfg.MastodonAccessToken))
+		} else {
+			fmt.Printf("  Mastodon:          (not configured)\n")
+		}
 	}
 }
 
-// createConfigTemplate creates a config file from a template.
-func createConfigTemplate(name string) error {
-	templates := map[string]*config.Config{
-		"openai": {
-			BaseURL:           "https://api.openai.com/v1",
-			Model:             "gpt-4o-mini",
-			Timeout:           60,
-			SkipPersonaPrompt: false, // OpenAI needs persona injection
-			SimulateTyping:    true,
-			TypingSpeed:       25,
-		},
-		"grok": {
-			BaseURL:           "https://api.x.ai/v1",
-			Model:             "grok-4-latest",
-			Timeout:           60,
-			SkipPersonaPrompt: false, // Grok needs persona injection
-			SimulateTyping:    true,
-			TypingSpeed:       25,
-		},
-		"elevenlabs": {
-			BaseURL:           "https://api.elevenlabs.io/v1",
-			Model:             "eleven_multilingual_v2",
-			Timeout:           60,
-			SkipPersonaPrompt: false,
-			SimulateTyping:    true,
-			TypingSpeed:       25,
-		},
-		"venice": {
-			BaseURL:           "https://api.venice.ai/api/v1",
-			Model:             "venice-uncensored",
-			Timeout:           60,
-			SkipPersonaPrompt: false, // Venice needs persona injection
-			SimulateTyping:    true,
-			TypingSpeed:       25,
-		},
-		"digitalocean": {
-			BaseURL:           "https://your-agent.ondigitalocean.app/api/v1",
-			Model:             "gpt-4o-mini",
-			Timeout:           60,
-			SkipPersonaPrompt: true, // DO agents have built-in persona
-			SimulateTyping:    true,
-			TypingSpeed:       25,
-		},
+// runInitCommand runs the interactive setup wizard.
+// Usage: celeste init [--provider <name>] [--api-key <key>] [--model <model>]
+//
+//	[--skip-persona <bool>] [--no-test] [--skip-skills] [--non-interactive] [--force]
+func runInitCommand(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	provider := fs.String("provider", "", "Provider template ("+strings.Join(config.TemplateNames(), ", ")+")")
+	apiKey := fs.String("api-key", "", "API key")
+	model := fs.String("model", "", "Model (defaults to the provider's template)")
+	skipPersona := fs.String("skip-persona", "", "Skip persona prompt (true/false)")
+	noTest := fs.Bool("no-test", false, "Skip the connection test")
+	skipSkills := fs.Bool("skip-skills", false, "Skip skill credential prompts")
+	nonInteractive := fs.Bool("non-interactive", false, "Never prompt; use flags and template defaults")
+	force := fs.Bool("force", false, "Overwrite an existing configuration")
+	_ = fs.Parse(args)
+
+	if configName != "" {
+		fmt.Fprintf(os.Stderr, "init writes the default config. For named profiles use: celeste config --init <template>\n")
+		os.Exit(1)
+	}
+
+	opts := commands.InitOptions{
+		Provider:       *provider,
+		APIKey:         *apiKey,
+		Model:          *model,
+		SkipPersona:    *skipPersona,
+		NoTest:         *noTest,
+		SkipSkills:     *skipSkills,
+		NonInteractive: *nonInteractive || !term.IsTerminal(os.Stdin.Fd()),
+	}
+
+	if hasDefaultConfig() && !*force {
+		wizard := commands.NewInitWizard(os.Stdin, os.Stdout, opts)
+		overwrite, err := wizard.ConfirmOverwrite(config.NamedConfigPath(""))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !overwrite {
+			fmt.Println("Existing configuration kept. Use --force to overwrite it.")
+			return
+		}
+	}
+
+	if err := runInitWizard(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Setup failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runInitWizard runs the setup wizard on the terminal and saves the result.
+func runInitWizard(opts commands.InitOptions) error {
+	wizard := commands.NewInitWizard(os.Stdin, os.Stdout, opts)
+	wizard.TestConnection = testConnection
+	if term.IsTerminal(os.Stdin.Fd()) {
+		wizard.ReadSecret = func() (string, error) {
+			key, err := term.ReadPassword(os.Stdin.Fd())
+			return string(key), err
+		}
+	}
+
+	cfg, err := wizard.Run()
+	if err != nil {
+		return err
+	}
+	if err := saveInitConfig(cfg); err != nil {
+		return err
 	}
 
-	tmpl, ok := templates[strings.ToLower(name)]
+	_, configFile, secretsFile, _ := config.Paths()
+	fmt.Printf("\n✓ Saved %s and %s\n", configFile, secretsFile)
+	fmt.Println("Run `celeste chat` to start.")
+	return nil
+}
+
+// saveInitConfig writes a wizard config through the regular save paths.
+// The API key only goes to secrets.json (0600), not the world-readable config.json.
+func saveInitConfig(cfg *config.Config) error {
+	configDir, _, _, _ := config.Paths()
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	mainConfig := *cfg
+	mainConfig.APIKey = ""
+	if err := config.Save(&mainConfig); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if err := config.SaveSecrets(cfg); err != nil {
+		return fmt.Errorf("failed to save secrets: %w", err)
+	}
+
+	if cfg.TarotAuthToken == "" && cfg.WeatherDefaultZipCode == "" && cfg.TwitchClientID == "" && cfg.YouTubeAPIKey == "" {
+		return nil
+	}
+
+	// Merge into skills.json so existing skill settings are kept
+	skillsConfig, err := config.LoadSkillsConfig()
+	if err != nil {
+		skillsConfig = &config.Config{}
+	}
+	if cfg.TarotAuthToken != "" {
+		skillsConfig.TarotAuthToken = cfg.TarotAuthToken
+	}
+	if cfg.WeatherDefaultZipCode != "" {
+		skillsConfig.WeatherDefaultZipCode = cfg.WeatherDefaultZipCode
+	}
+	if cfg.TwitchClientID != "" {
+		skillsConfig.TwitchClientID = cfg.TwitchClientID
+	}
+	if cfg.YouTubeAPIKey != "" {
+		skillsConfig.YouTubeAPIKey = cfg.YouTubeAPIKey
+	}
+	if err := config.SaveSkillsConfig(skillsConfig); err != nil {
+		return fmt.Errorf("failed to save skills config: %w", err)
+	}
+	return nil
+}
+
+// testConnection sends a one-token request to check the endpoint and API key.
+func testConnection(cfg *config.Config) error {
+	client, err := celeste.NewClient(celeste.Config{
+		APIKey:            cfg.APIKey,
+		BaseURL:           cfg.BaseURL,
+		Model:             cfg.Model,
+		Timeout:           30 * time.Second,
+		SkipPersonaPrompt: true,
+		MaxTokens:         1,
+		ExtraBody:         requestExtraBody(cfg),
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	_, err = client.Generate(context.Background(), celeste.GenerateRequest{Prompt: "Hi"})
+	return err
+}
+
+// createConfigTemplate creates a config file from a template.
+func createConfigTemplate(name string) error {
+	tmpl, ok := config.Template(name)
 	if !ok {
-		return fmt.Errorf("unknown config template '%s'. Available: openai, grok, elevenlabs, venice, digitalocean", name)
+		return fmt.Errorf("unknown config template '%s'. Available: %s", name, strings.Join(config.TemplateNames(), ", "))
 	}
 
 	configPath := config.NamedConfigPath(name)
@@ -1014,24 +1927,121 @@ func maskKey(key string) string {
 	return key[:4] + "..." + key[len(key)-4:]
 }
 
-// runSkillExecuteCommand executes a single skill from the command line.
-// Usage: celeste skill <name> [--arg1 value1] [--arg2 value2]
-func runSkillExecuteCommand(args []string) {
-	if len(args) < 1 {
-		fmt.Fprintln(os.Stderr, "Usage: celeste skill <skill-name> [args...]")
-		fmt.Fprintln(os.Stderr, "\nExamples:")
-		fmt.Fprintln(os.Stderr, "  celeste skill generate_uuid")
-		fmt.Fprintln(os.Stderr, "  celeste skill get_weather --zip 90210")
-		fmt.Fprintln(os.Stderr, "  celeste skill generate_password --length 20")
+// newSkillRegistry builds the registry used to run skills from the command
+// line: skill files, built-in skills and vision skills.
+func newSkillRegistry(cfg *config.Config) (*skills.Registry, skills.ConfigLoader) {
+	registry := skills.NewRegistry()
+	_ = registry.LoadSkills()
+
+	configLoader := config.NewConfigLoader(cfg)
+	skills.RegisterBuiltinSkills(registry, configLoader)
+	if config.IsSafeMode() {
+		registry.RemoveNSFWSkills()
+	}
+
+	// Vision skills need an LLM client for multimodal requests
+	visionClient := llm.NewClient(&llm.Config{
+		APIKey:            cfg.APIKey,
+		BaseURL:           cfg.BaseURL,
+		Model:             cfg.Model,
+		Timeout:           cfg.GetTimeout(),
+		SkipPersonaPrompt: true,
+		ExtraBody:         requestExtraBody(cfg),
+	}, registry)
+	skills.RegisterVisionSkills(registry, visionClient)
+
+	return registry, configLoader
+}
+
+// runSkillDescribeCommand prints a skill's parameters, defaults,
+// prerequisites and an example invocation.
+// Usage: celeste skill --describe <name> [--json]
+func runSkillDescribeCommand(args []string) {
+	fs := flag.NewFlagSet("skill --describe", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the description as JSON")
+	_ = fs.Parse(args)
+	name := fs.Arg(0)
+	// Accept --json after the skill name too
+	if fs.NArg() > 1 {
+		_ = fs.Parse(fs.Args()[1:])
+	}
+	if name == "" {
+		fmt.Fprintln(os.Stderr, "Usage: celeste skill --describe <skill-name> [--json]")
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadNamed(configName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	registry, configLoader := newSkillRegistry(cfg)
+
+	desc, err := registry.DescribeSkill(name, configLoader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		fmt.Fprintln(os.Stderr, "\nUse 'celeste skills --list' to see available skills")
 		os.Exit(1)
 	}
 
-	skillName := args[0]
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(desc)
+		return
+	}
+
+	fmt.Printf("\n%s\n", desc.Name)
+	fmt.Printf("  %s\n", desc.Description)
+
+	fmt.Printf("\nParameters:\n")
+	if len(desc.Parameters) == 0 {
+		fmt.Printf("  (none)\n")
+	}
+	for _, p := range desc.Parameters {
+		required := "optional"
+		if p.Required {
+			required = "required"
+		}
+		fmt.Printf("  --%s (%s, %s)\n", p.Name, p.Type, required)
+		if p.Description != "" {
+			fmt.Printf("      %s\n", p.Description)
+		}
+		if len(p.Enum) > 0 {
+			values := make([]string, len(p.Enum))
+			for i, v := range p.Enum {
+				values[i] = fmt.Sprint(v)
+			}
+			fmt.Printf("      One of: %s\n", strings.Join(values, ", "))
+		}
+		if p.Default != nil {
+			fmt.Printf("      Default: %v (from %s)\n", p.Default, p.DefaultSource)
+		}
+	}
 
-	// Parse remaining args as key-value pairs
+	if len(desc.Prerequisites) > 0 {
+		fmt.Printf("\nPrerequisites:\n")
+		for _, p := range desc.Prerequisites {
+			if p.Satisfied {
+				fmt.Printf("  ✓ %s\n", p.Description)
+			} else {
+				fmt.Printf("  ✗ %s (set with: %s)\n", p.Description, p.ConfigCommand)
+			}
+		}
+	}
+
+	fmt.Printf("\nExample:\n  %s\n", desc.Example)
+
+	schema, _ := json.MarshalIndent(desc.Schema, "  ", "  ")
+	fmt.Printf("\nSchema:\n  %s\n\n", schema)
+}
+
+// parseSkillArgs reads --key value pairs into skill arguments. Numbers
+// become float64, as JSON numbers do, and a flag without a value is true.
+func parseSkillArgs(args []string) map[string]any {
 	skillArgs := make(map[string]any)
-	for i := 1; i < len(args); i++ {
+	for i := 0; i < len(args); i++ {
 		if strings.HasPrefix(args[i], "--") {
 			key := strings.TrimPrefix(args[i], "--")
 			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
@@ -1054,20 +2064,37 @@ func runSkillExecuteCommand(args []string) {
 			}
 		}
 	}
+	return skillArgs
+}
+
+// runSkillExecuteCommand executes a single skill from the command line.
+// Usage: celeste skill <name> [--arg1 value1] [--arg2 value2]
+func runSkillExecuteCommand(args []string) {
+	if len(args) > 0 && (args[0] == "--describe" || args[0] == "-describe") {
+		runSkillDescribeCommand(args[1:])
+		return
+	}
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: celeste skill <skill-name> [args...]")
+		fmt.Fprintln(os.Stderr, "\nExamples:")
+		fmt.Fprintln(os.Stderr, "  celeste skill generate_uuid")
+		fmt.Fprintln(os.Stderr, "  celeste skill get_weather --zip 90210")
+		fmt.Fprintln(os.Stderr, "  celeste skill generate_password --length 20")
+		fmt.Fprintln(os.Stderr, "\nUse 'celeste skills --list' to see available skills")
+		os.Exit(1)
+	}
+
+	skillName := args[0]
+	skillArgs := parseSkillArgs(args[1:])
 
 	// Set up registry and executor
-	cfg, err := config.Load()
+	cfg, err := config.LoadNamed(configName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
-	registry := skills.NewRegistry()
-	_ = registry.LoadSkills()
-
-	configLoader := config.NewConfigLoader(cfg)
-	skills.RegisterBuiltinSkills(registry, configLoader)
-
+	registry, _ := newSkillRegistry(cfg)
 	executor := skills.NewExecutor(registry)
 
 	// Convert args to JSON
@@ -1077,8 +2104,10 @@ func runSkillExecuteCommand(args []string) {
 		os.Exit(1)
 	}
 
-	// Execute skill
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Execute skill; Ctrl+C cancels the in-flight request
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	result, err := executor.Execute(ctx, skillName, string(argsJSON))
@@ -1094,11 +2123,25 @@ func runSkillExecuteCommand(args []string) {
 		case string:
 			fmt.Println(v)
 		case map[string]interface{}:
+			if warning, ok := v["layout_warning"].(string); ok {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+			}
+			// Results with a drawn layout, like tarot spreads, print the drawing
+			if layout, ok := v["layout"].(string); ok {
+				fmt.Println(layout)
+				break
+			}
 			// Pretty print JSON objects
 			jsonOut, _ := json.MarshalIndent(v, "", "  ")
 			fmt.Println(string(jsonOut))
 		default:
-			fmt.Printf("%v\n", v)
+			// Typed results, like dice rolls and text statistics, print as JSON too
+			jsonOut, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				fmt.Printf("%v\n", v)
+				break
+			}
+			fmt.Println(string(jsonOut))
 		}
 	} else {
 		fmt.Fprintf(os.Stderr, "Skill '%s' failed: %s\n", skillName, result.Error)
@@ -1106,6 +2149,114 @@ func runSkillExecuteCommand(args []string) {
 	}
 }
 
+// runTarotCommand draws a tarot reading or shows the saved ones.
+// Usage: celeste tarot [--spread <type>] [--question <text>] [--save]
+//
+//	celeste tarot --history [--limit N]
+func runTarotCommand(args []string) {
+	fs := flag.NewFlagSet("tarot", flag.ExitOnError)
+	spread := fs.String("spread", "three", "Spread type: "+strings.Join(skills.TarotSpreads, ", "))
+	question := fs.String("question", "", "Question to focus the reading on")
+	save := fs.Bool("save", false, "Save the reading to the tarot history")
+	history := fs.Bool("history", false, "Show saved readings")
+	limit := fs.Int("limit", 10, "Saved readings to show with --history")
+	_ = fs.Parse(args)
+
+	cfg, err := config.LoadNamed(configName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *history {
+		runTarotHistory(skills.TarotHistoryPath(config.NewConfigLoader(cfg)), *limit)
+		return
+	}
+	registry, _ := newSkillRegistry(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	output, err := registry.ExecuteContext(ctx, "tarot_reading", map[string]interface{}{
+		"spread_type": *spread,
+		"question":    *question,
+		"save":        *save,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	reading, _ := output.(map[string]interface{})
+	if failed, _ := reading["error"].(bool); failed {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", reading["message"])
+		if hint, ok := reading["hint"].(string); ok && hint != "" {
+			fmt.Fprintln(os.Stderr, hint)
+		}
+		os.Exit(1)
+	}
+
+	if warning, ok := reading["layout_warning"].(string); ok {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if layout, ok := reading["layout"].(string); ok {
+		fmt.Println(layout)
+	} else {
+		fmt.Println(reading["summary"])
+	}
+	if path, ok := reading["saved_to"].(string); ok {
+		fmt.Printf("\nSaved to %s\n", path)
+	}
+	if saveErr, ok := reading["save_error"].(string); ok {
+		fmt.Fprintf(os.Stderr, "Warning: reading not saved: %s\n", saveErr)
+		os.Exit(1)
+	}
+}
+
+// runTarotHistory prints the newest saved readings in the history file at
+// path and the cards that keep coming up across all of them.
+func runTarotHistory(path string, limit int) {
+	entries, err := skills.LoadTarotHistory(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No saved tarot readings yet. Save one with: celeste tarot --save")
+		return
+	}
+
+	shown := entries
+	if limit > 0 && len(shown) > limit {
+		shown = shown[len(shown)-limit:]
+	}
+	fmt.Printf("Tarot history (%d of %d readings, newest first)\n", len(shown), len(entries))
+	for i := len(shown) - 1; i >= 0; i-- {
+		entry := shown[i]
+		fmt.Printf("\n%s  %s", entry.Timestamp.Local().Format("2006-01-02 15:04"), entry.SpreadName)
+		if entry.Question != "" {
+			fmt.Printf(" - %s", entry.Question)
+		}
+		fmt.Println()
+		for _, card := range entry.Cards {
+			fmt.Printf("  %s: %s (%s)\n", card.Position, card.Name, card.Orientation)
+		}
+	}
+
+	var recurring []string
+	for _, count := range skills.TarotCardCounts(entries) {
+		if count.Count < 2 || len(recurring) == 5 {
+			break
+		}
+		recurring = append(recurring, fmt.Sprintf("  %s ×%d (%d reversed)", count.Name, count.Count, count.Reversed))
+	}
+	if len(recurring) > 0 {
+		fmt.Println("\nRecurring cards:")
+		fmt.Println(strings.Join(recurring, "\n"))
+	}
+}
+
 // runSkillsCommand handles skill-related commands.
 func runSkillsCommand(args []string) {
 	fs := flag.NewFlagSet("skills", flag.ExitOnError)
@@ -1216,10 +2367,50 @@ func runSessionCommand(args []string) {
 	fs := flag.NewFlagSet("session", flag.ExitOnError)
 	list := fs.Bool("list", false, "List saved sessions")
 	load := fs.String("load", "", "Load a session by ID")
+	full := fs.Bool("full", false, "Include archived messages with --load")
 	clear := fs.Bool("clear", false, "Clear all sessions")
+	exportAll := fs.Bool("export-all", false, "Export sessions, skill settings and workspace data to a backup archive")
+	out := fs.String("out", "celeste-backup.tar.gz", "Backup archive path (with --export-all)")
+	includeSecrets := fs.Bool("include-secrets", false, "Include API keys in the backup")
+	importFile := fs.String("import", "", "Import a backup archive into ~/.celeste")
+	overwrite := fs.Bool("overwrite", false, "Replace existing sessions and files when importing")
+	summarize := fs.String("summarize", "", "Summarize a session by ID with the persona")
+	style := fs.String("style", "bullets", "Summary style with --summarize ("+strings.Join(llm.RecapStyles, ", ")+")")
+	persona := fs.String("persona", personaName, "Persona for --summarize (default: the session's)")
+	search := fs.String("search", "", "Search every saved session's messages (case-insensitive)")
+	limit := fs.Int("limit", config.DefaultSearchLimit, "Maximum matches to show with --search")
+	includeTools := fs.Bool("include-tools", false, "Also search skill results with --search")
 	// Parse flags - exits on error due to ExitOnError flag
 	_ = fs.Parse(args)
 
+	if *summarize != "" {
+		// --out defaults to the backup archive name; only write a summary
+		// file when it was given explicitly
+		summaryOut := ""
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "out" {
+				summaryOut = *out
+			}
+		})
+		runSessionSummarize(*summarize, *style, *persona, summaryOut)
+		return
+	}
+
+	if *exportAll {
+		runSessionExportAll(*out, *includeSecrets)
+		return
+	}
+
+	if *search != "" {
+		runSessionSearch(*search, config.SearchOptions{Limit: *limit, IncludeTools: *includeTools})
+		return
+	}
+
+	if *importFile != "" {
+		runSessionImport(*importFile, *overwrite)
+		return
+	}
+
 	manager := config.NewSessionManager()
 
 	if *clear {
@@ -1232,12 +2423,19 @@ func runSessionCommand(args []string) {
 	}
 
 	if *load != "" {
-		session, err := manager.Load(*load)
+		loadSession := manager.Load
+		if *full {
+			loadSession = manager.LoadFull
+		}
+		session, err := loadSession(*load)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading session: %v\n", err)
 			os.Exit(1)
 		}
 		fmt.Printf("Loaded session: %s (%d messages)\n", session.ID, len(session.Messages))
+		if session.Archived > 0 {
+			fmt.Printf("%d older messages are archived; use --full to include them\n", session.Archived)
+		}
 		// In full implementation, this would resume the session in TUI
 		return
 	}
@@ -1269,55 +2467,1024 @@ func runSessionCommand(args []string) {
 	}
 }
 
-// runSingleMessage sends a single message and prints the response.
-func runSingleMessage(message string) {
-	cfg, err := config.Load()
+// runSessionSearch prints every saved message containing query, one match
+// per entry with its session, time, role and a highlighted snippet.
+func runSessionSearch(query string, opts config.SearchOptions) {
+	if opts.Limit <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --limit must be positive")
+		os.Exit(1)
+	}
+	matches, more, err := config.NewSessionManager().Search(query, opts)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error searching sessions: %v\n", err)
+		os.Exit(1)
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No messages match %q\n", query)
+		return
+	}
+
+	for _, match := range matches {
+		session := match.SessionID
+		if match.SessionName != "" {
+			session += " (" + match.SessionName + ")"
+		}
+		fmt.Printf("%s  %s  %s\n", session, match.Timestamp.Format("2006-01-02 15:04"), match.Role)
+		highlighted := ansiColor("1;33", match.Snippet[match.MatchStart:match.MatchEnd])
+		if !config.ColorEnabled() {
+			highlighted = "[" + match.Snippet[match.MatchStart:match.MatchEnd] + "]"
+		}
+		fmt.Printf("  %s%s%s\n\n", match.Snippet[:match.MatchStart], highlighted, match.Snippet[match.MatchEnd:])
+	}
+	if more {
+		fmt.Printf("Showing the first %d matches; raise --limit to see more\n", opts.Limit)
+	}
+}
+
+// runSessionSummarize recaps a saved session in the given style, in the
+// voice of the session's persona (or --persona), printing it or writing it
+// to out.
+func runSessionSummarize(id, style, persona, out string) {
+	if !slices.Contains(celeste.RecapStyles, style) {
+		fmt.Fprintf(os.Stderr, "Error: unknown style %q (use %s)\n", style, strings.Join(celeste.RecapStyles, ", "))
+		os.Exit(1)
+	}
+	if persona != "" && !prompts.IsPersona(persona) {
+		fmt.Fprintf(os.Stderr, "Error: unknown persona %q (available: %s)\n", persona, strings.Join(prompts.ListPersonas(), ", "))
 		os.Exit(1)
 	}
 
+	session, err := config.NewSessionManager().Load(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading session: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadNamed(configName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
 	if cfg.APIKey == "" {
 		fmt.Fprintln(os.Stderr, "No API key configured.")
 		os.Exit(1)
 	}
 
-	// Initialize LLM client
-	llmConfig := &llm.Config{
+	client, err := celeste.NewClient(celeste.Config{
 		APIKey:            cfg.APIKey,
 		BaseURL:           cfg.BaseURL,
 		Model:             cfg.Model,
-		Timeout:           cfg.GetTimeout(),
+		Timeout:           requestTimeout(cfg),
 		SkipPersonaPrompt: cfg.SkipPersonaPrompt,
+		RequireStream:     requireStream,
+		Temperature:       cfg.Temperature,
+		TopP:              cfg.TopP,
+		MaxTokens:         cfg.MaxTokens,
+		ExtraBody:         requestExtraBody(cfg),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
-	client := llm.NewClient(llmConfig, nil)
+	defer client.Close()
 
-	if !cfg.SkipPersonaPrompt {
-		client.SetSystemPrompt(prompts.GetSystemPrompt(false))
+	provider := providers.DetectProvider(cfg.BaseURL)
+	if !confirmSpendBudget(cfg, provider) {
+		os.Exit(1)
 	}
 
-	// Send message
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.GetTimeout())
-	defer cancel()
-
-	messages := []tui.ChatMessage{{
-		Role:      "user",
-		Content:   message,
-		Timestamp: time.Now(),
-	}}
-
-	result, err := client.SendMessageSync(ctx, messages, nil)
+	if persona == "" {
+		persona = session.Persona
+	}
+	request := celeste.RecapRequest{Style: style, Persona: persona}
+	for _, msg := range session.Messages {
+		request.Messages = append(request.Messages, celeste.Message{Role: msg.Role, Content: msg.Content, Name: msg.Name})
+	}
+	result, err := client.Recap(context.Background(), request)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-
+	if result.Usage != nil {
+		entry := config.NewLedgerEntry(provider, cfg.BaseURL, cfg.Model, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+		if err := config.RecordUsage(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record usage: %v\n", err)
+		}
+	}
+	summary := result.Content
+	if style == "tweet-thread" {
+		summary = strings.Join(llm.SplitThread(summary, llm.TweetLimit), "\n\n")
+	}
+
+	if out == "" {
+		fmt.Println(summary)
+		return
+	}
+	if err := os.WriteFile(out, []byte(summary+"\n"), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing summary: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Summary written to %s\n", out)
+}
+
+// notifyUpdate prints a one-line notice when a newer release is available.
+// GitHub is asked at most once a day (see update.CheckInterval). Skipped for
+// commands where the notice would be noise, for local builds without a
+// stamped version, and when disabled with disable_update_check or
+// CELESTE_NO_UPDATE_CHECK.
+func notifyUpdate(command string) {
+	switch command {
+	case "update", "version", "-v", "--version", "help", "-h", "--help":
+		return
+	}
+	if update.Disabled() || version.IsDev() {
+		return
+	}
+	if cfg, err := config.LoadNamed(configName); err == nil && cfg.DisableUpdateCheck {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if latest := update.NewClient().Check(ctx, version.Version, update.StatePath(), time.Now()); latest != "" {
+		fmt.Fprintf(os.Stderr, "✨ Celeste %s is available (you have %s). Run `celeste update` to upgrade.\n", latest, version.Version)
+	}
+}
+
+// runUpdateCommand replaces the running binary with the latest release,
+// after verifying the download against the release checksums.
+func runUpdateCommand() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	client := update.NewClient()
+	release, err := client.LatestRelease(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if update.CompareVersions(release.Version(), version.Version) <= 0 {
+		fmt.Printf("Celeste %s is up to date.\n", version.Version)
+		return
+	}
+
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: can't locate the running binary: %v\n", err)
+		os.Exit(1)
+	}
+	if err := update.CheckWritable(exe); err != nil {
+		fmt.Fprintf(os.Stderr, "Can't update %s: %v\n", exe, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Downloading Celeste %s for %s/%s...\n", release.Version(), runtime.GOOS, runtime.GOARCH)
+	binary, err := client.Download(ctx, release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := update.Replace(exe, binary); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Updated Celeste %s → %s (checksum verified)\n", version.Version, release.Version())
+}
+
+// runDoctorCommand probes every configured integration and prints a status
+// table. Exits 1 if any configured check fails.
+func runDoctorCommand() {
+	cfg, err := config.LoadNamed(configName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Checking integrations...")
+	fmt.Println()
+	results := doctor.Run(context.Background(), doctor.Checks(cfg), doctor.DefaultTimeout)
+	doctor.WriteTable(os.Stdout, results)
+
+	if !doctor.Passed(results) {
+		os.Exit(1)
+	}
+}
+
+// runModelsCommand lists Venice.ai models with their capabilities, from a
+// list cached for an hour.
+// Usage: celeste models [--type text|image|upscale] [--refresh]
+func runModelsCommand(args []string) {
+	fs := flag.NewFlagSet("models", flag.ExitOnError)
+	modelType := fs.String("type", "", "Only list models of this type (text, image, upscale)")
+	refresh := fs.Bool("refresh", false, "Fetch the list again instead of using the cache")
+	_ = fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Use the chat endpoint when it is Venice, otherwise the Venice skill settings
+	veniceConfig := venice.Config{APIKey: cfg.APIKey, BaseURL: cfg.BaseURL}
+	if providers.DetectProvider(cfg.BaseURL) != "venice" {
+		skillConfig, err := config.NewConfigLoader(cfg).GetVeniceConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\nSet one with: celeste config --set-venice-key <key>\n", err)
+			os.Exit(1)
+		}
+		veniceConfig = venice.Config{APIKey: skillConfig.APIKey, BaseURL: skillConfig.BaseURL}
+	}
+
+	models, err := venice.ListModels(context.Background(), veniceConfig, *modelType, *refresh)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(models) == 0 {
+		fmt.Println("No models found.")
+		return
+	}
+
+	fmt.Printf("Venice.ai models (cached in %s):\n", venice.ModelCachePath())
+	lastType := ""
+	for _, m := range models {
+		if m.Type != lastType {
+			fmt.Printf("\n%s:\n", m.Type)
+			lastType = m.Type
+		}
+		details := m.Capabilities()
+		if m.ContextTokens > 0 {
+			details = append([]string{fmt.Sprintf("%dk context", m.ContextTokens/1024)}, details...)
+		}
+		fmt.Printf("  %-28s %s\n", m.ID, strings.Join(details, ", "))
+	}
+}
+
+// runStylesCommand lists the image style presets with their key parameters.
+func runStylesCommand() {
+	styles, err := venice.LoadImageStyles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(styles) == 0 {
+		fmt.Printf("No image styles defined. Add presets to %s, e.g.:\n\n", venice.StylesPath())
+		fmt.Println(`{
+  "anime": {
+    "prompt_prefix": "anime style, clean lineart, vibrant colors",
+    "negative_prompt": "blurry, lowres, watermark",
+    "model": "wai-Illustrious",
+    "steps": 30
+  }
+}`)
+		return
+	}
+
+	fmt.Printf("Image styles (%s):\n\n", venice.StylesPath())
+	for _, name := range venice.StyleNames(styles) {
+		style := styles[name]
+		var settings []string
+		if style.Model != "" {
+			settings = append(settings, "model "+style.Model)
+		}
+		if style.Steps != 0 {
+			settings = append(settings, fmt.Sprintf("%d steps", style.Steps))
+		}
+		if style.CFGScale != 0 {
+			settings = append(settings, fmt.Sprintf("cfg %g", style.CFGScale))
+		}
+		if name == venice.DefaultStyle {
+			settings = append(settings, "used when no --style is given")
+		}
+		fmt.Printf("  %-14s %s\n", name, strings.Join(settings, ", "))
+		for _, line := range [][2]string{
+			{"prefix", style.PromptPrefix},
+			{"suffix", style.PromptSuffix},
+			{"negative", style.NegativePrompt},
+		} {
+			if line[1] != "" {
+				fmt.Printf("  %-14s %s: %s\n", "", line[0], line[1])
+			}
+		}
+	}
+	fmt.Printf("  %-14s %s\n", venice.NoStyle, "skip all presets")
+}
+
+// runWorkspaceCommand handles workspace-related commands.
+func runWorkspaceCommand(args []string) {
+	fs := flag.NewFlagSet("workspace", flag.ExitOnError)
+	list := fs.Bool("list", false, "List workspaces with note and reminder counts")
+	// Parse flags - exits on error due to ExitOnError flag
+	_ = fs.Parse(args)
+
+	if !*list && len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: celeste workspace --list")
+		os.Exit(1)
+	}
+
+	workspaces, err := config.ListWorkspaces()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing workspaces: %v\n", err)
+		os.Exit(1)
+	}
+
+	active := configName
+	if active == "" {
+		active = config.DefaultWorkspace
+	}
+
+	fmt.Printf("\nWorkspaces (%d):\n", len(workspaces))
+	for _, ws := range workspaces {
+		marker := " "
+		if ws.Name == active {
+			marker = "*"
+		}
+		fmt.Printf("\n %s %s\n", marker, ws.Name)
+		fmt.Printf("    Path:      %s\n", ws.Dir)
+		fmt.Printf("    Notes:     %d\n", ws.Notes)
+		fmt.Printf("    Reminders: %d\n", ws.Reminders)
+		fmt.Printf("    QR codes:  %d\n", ws.QRCodes)
+	}
+	fmt.Println()
+}
+
+// runSessionExportAll writes a backup archive of ~/.celeste data to path.
+func runSessionExportAll(path string, includeSecrets bool) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating backup: %v\n", err)
+		os.Exit(1)
+	}
+
+	manifest, err := config.ExportBackup(file, config.BackupOptions{
+		Version:        version.Version,
+		IncludeSecrets: includeSecrets,
+	})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		fmt.Fprintf(os.Stderr, "Error creating backup: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Backup written to %s\n", path)
+	fmt.Printf("  Sessions: %d\n", manifest.Sessions)
+	if len(manifest.Files) > 0 {
+		fmt.Printf("  Files:    %s\n", strings.Join(manifest.Files, ", "))
+	}
+	if includeSecrets {
+		fmt.Println("⚠️  This archive contains API keys - keep it somewhere safe")
+	} else {
+		fmt.Println("  API keys were not included (use --include-secrets to add them)")
+	}
+}
+
+// runSessionImport unpacks a backup archive into ~/.celeste.
+func runSessionImport(path string, overwrite bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening backup: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	result, err := config.ImportBackup(file, config.ImportOptions{Overwrite: overwrite})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error importing backup: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported backup from celeste %s (%s)\n",
+		result.Manifest.CelesteVersion, result.Manifest.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("  Sessions imported: %d\n", len(result.SessionsImported))
+	if len(result.SessionsSkipped) > 0 {
+		fmt.Printf("  Sessions skipped:  %d (already exist)\n", len(result.SessionsSkipped))
+	}
+	if len(result.FilesImported) > 0 {
+		fmt.Printf("  Files imported:    %s\n", strings.Join(result.FilesImported, ", "))
+	}
+	if len(result.FilesSkipped) > 0 {
+		fmt.Printf("  Files skipped:     %s (already exist)\n", strings.Join(result.FilesSkipped, ", "))
+	}
+	if len(result.SessionsSkipped)+len(result.FilesSkipped) > 0 {
+		fmt.Println("  Use --overwrite to replace existing items")
+	}
+	for entry, reason := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  ⚠️ %s: %s\n", entry, reason)
+	}
+}
+
+// messageArgs splits a message command line into the text and whether to
+// stream the reply. --no-stream and the global flags may come before the
+// text; an unknown or misplaced flag is an error.
+func messageArgs(args []string) (string, bool, error) {
+	fs := flag.NewFlagSet("message", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	noStream := fs.Bool("no-stream", false, "Wait for the whole reply instead of streaming it")
+	addGlobalFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return "", false, err
+	}
+	text, err := commandText(fs, args)
+	return text, !*noStream, err
+}
+
+// chatArgs applies the global flags given after chat, which takes no other
+// arguments.
+func chatArgs(args []string) error {
+	fs := flag.NewFlagSet("chat", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	addGlobalFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("chat takes no arguments, got %q", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// runSingleMessage sends one message and prints the reply, as it arrives
+// when stream is set.
+func runSingleMessage(message string, stream bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if cfg.APIKey == "" {
+		fmt.Fprintln(os.Stderr, "No API key configured.")
+		os.Exit(1)
+	}
+
+	client, err := celeste.NewClient(celeste.Config{
+		APIKey:            cfg.APIKey,
+		BaseURL:           cfg.BaseURL,
+		Model:             cfg.Model,
+		Timeout:           requestTimeout(cfg),
+		SkipPersonaPrompt: cfg.SkipPersonaPrompt,
+		RequireStream:     requireStream,
+		Temperature:       cfg.Temperature,
+		TopP:              cfg.TopP,
+		MaxTokens:         cfg.MaxTokens,
+		ExtraBody:         requestExtraBody(cfg),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	provider := providers.DetectProvider(cfg.BaseURL)
+	if !confirmSpendBudget(cfg, provider) {
+		os.Exit(1)
+	}
+
+	request := celeste.GenerateRequest{
+		Prompt:      message,
+		Persona:     personaName,
+		Temperature: samplingFlags.Temperature,
+		TopP:        samplingFlags.TopP,
+		MaxTokens:   samplingFlags.MaxTokens,
+	}
+	var onChunk celeste.StreamFunc
+	var printed string // Last chunk written, to end the reply on a newline
+	start := time.Now()
+	if stream {
+		onChunk = func(chunk string) {
+			fmt.Print(chunk)
+			printed = chunk
+		}
+	}
+	result, err := client.GenerateStream(context.Background(), request, onChunk)
+	if printed != "" && !strings.HasSuffix(printed, "\n") {
+		fmt.Println()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !stream {
+		fmt.Println(result.Content)
+	}
+	if notifier := newNotifier(cfg); notifier != nil {
+		notifier.Done("reply finished", time.Since(start))
+	}
+	recordTranscript(cfg, provider, client.SystemPrompt(request), request.Prompt, result, "")
+
+	if result.Usage != nil {
+		entry := config.NewLedgerEntry(provider, cfg.BaseURL, cfg.Model, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+		if err := config.RecordUsage(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record usage: %v\n", err)
+		}
+	}
+}
+
+// runContentCommand generates platform-formatted content with the persona's
+// content prompt. Short and long output is kept within 280 and 5000
+// characters.
+func runContentCommand(args []string) {
+	fs := flag.NewFlagSet("content", flag.ExitOnError)
+	platform := fs.String("platform", "", "Target platform ("+strings.Join(prompts.ContentPlatforms, ", ")+")")
+	format := fs.String("format", "general", "Length format ("+strings.Join(prompts.ContentFormats, ", ")+")")
+	tone := fs.String("tone", "", "Tone, e.g. hype, cozy, sarcastic")
+	topic := fs.String("topic", "", "Topic or subject")
+	onOverflow := fs.String("on-overflow", "", "Over-limit content: shorten (ask the model) or truncate (default from config, else shorten)")
+	batch := fs.String("batch", "", "Run every job in a .jsonl or .csv job file")
+	batchOut := fs.String("batch-out", "celeste-batch", "Output directory for --batch")
+	batchConcurrency := fs.Int("batch-concurrency", 1, "Jobs to run at once with --batch")
+	batchResume := fs.Bool("batch-resume", false, "Skip --batch jobs that already have an output file")
+	lintFlag := fs.Bool("lint", false, "Check the result against platform limits (default for --format short)")
+	noLint := fs.Bool("no-lint", false, "Skip the platform limit check")
+	lintFix := fs.Bool("lint-fix", false, "Ask the model once to fix lint issues and print the fixed version")
+	addGlobalFlags(fs)
+	_ = fs.Parse(args)
+
+	if *noLint && (*lintFlag || *lintFix) {
+		fmt.Fprintln(os.Stderr, "Error: --no-lint can't be combined with --lint or --lint-fix")
+		os.Exit(1)
+	}
+	lint := (*format == "short" || *lintFlag || *lintFix) && !*noLint
+
+	if err := celeste.ValidateOverflow(*onOverflow); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *batch != "" {
+		runContentBatch(*batch, *batchOut, *batchConcurrency, *batchResume, *onOverflow)
+		return
+	}
+
+	if err := celeste.ValidateContent(*platform, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	request, err := commandText(fs, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if request == "" && *topic == "" {
+		fmt.Fprintln(os.Stderr, "Usage: celeste content [--platform <name>] [--format short|long|general] [--tone <tone>] [--topic <topic>] [--on-overflow shorten|truncate] [--lint|--no-lint] [--lint-fix] <request>")
+		fmt.Fprintln(os.Stderr, "       celeste content --batch <jobs.jsonl|jobs.csv> [--batch-out <dir>] [--batch-concurrency <n>] [--batch-resume]")
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadNamed(configName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.APIKey == "" {
+		fmt.Fprintln(os.Stderr, "No API key configured.")
+		os.Exit(1)
+	}
+
+	client, err := celeste.NewClient(celeste.Config{
+		APIKey:        cfg.APIKey,
+		BaseURL:       cfg.BaseURL,
+		Model:         cfg.Model,
+		Timeout:       requestTimeout(cfg),
+		RequireStream: requireStream,
+		Temperature:   cfg.Temperature,
+		TopP:          cfg.TopP,
+		MaxTokens:     cfg.MaxTokens,
+		ExtraBody:     requestExtraBody(cfg),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	provider := providers.DetectProvider(cfg.BaseURL)
+	if !confirmSpendBudget(cfg, provider) {
+		os.Exit(1)
+	}
+
+	contentRequest := celeste.GenerateRequest{
+		Prompt:      request,
+		Platform:    *platform,
+		Format:      *format,
+		Tone:        *tone,
+		Topic:       *topic,
+		Persona:     personaName,
+		Overflow:    contentOverflow(*onOverflow, cfg),
+		Temperature: samplingFlags.Temperature,
+		TopP:        samplingFlags.TopP,
+		MaxTokens:   samplingFlags.MaxTokens,
+	}
+	start := time.Now()
+	result, err := client.GenerateContent(context.Background(), contentRequest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var lintResult string
+	if lint {
+		rules := celeste.ContentLintRules(*platform, *format, celeste.BannedTerms(cfg.ContentBanned))
+		report := celeste.LintContent(result.Content, rules)
+		if !report.OK() {
+			printLintReport("Content lint", report)
+			if *lintFix {
+				fixed, err := client.FixContent(context.Background(), contentRequest, result, report)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: lint fix failed, keeping the original: %v\n", err)
+				} else {
+					result = fixed
+					report = celeste.LintContent(result.Content, rules)
+					if report.OK() {
+						fmt.Fprintln(os.Stderr, "✓ Fixed by the model")
+					} else {
+						printLintReport("Still failing after the fix", report)
+					}
+				}
+			}
+		}
+		lintResult = report.Summary()
+	}
+
 	fmt.Println(result.Content)
+	fmt.Fprintln(os.Stderr, contentLengthReport(result, *format))
+	if notifier := newNotifier(cfg); notifier != nil {
+		notifier.Done(fmt.Sprintf("%s content finished, %d characters", *format, utf8.RuneCountInString(result.Content)), time.Since(start))
+	}
+	recordTranscript(cfg, provider, client.SystemPrompt(contentRequest), request, result, lintResult)
+
+	if result.Usage != nil {
+		entry := config.NewLedgerEntry(provider, cfg.BaseURL, cfg.Model, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+		if err := config.RecordUsage(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record usage: %v\n", err)
+		}
+	}
+}
+
+// recordTranscript appends the run to the transcript log when one is
+// configured, with the content lint result if there is one. Venice.ai runs
+// are skipped unless transcript_nsfw is set.
+func recordTranscript(cfg *config.Config, provider, systemPrompt, prompt string, result celeste.GenerateResult, lint string) {
+	path := cfg.TranscriptPath(transcriptFlag)
+	if path == "" || (provider == "venice" && !cfg.TranscriptNSFW) {
+		return
+	}
+
+	entry := config.TranscriptEntry{
+		Time:         time.Now(),
+		Args:         os.Args[1:],
+		Provider:     provider,
+		Model:        cfg.Model,
+		SystemPrompt: systemPrompt,
+		Prompt:       prompt,
+		Response:     result.Content,
+		Version:      version.String(),
+		Lint:         lint,
+	}
+	if result.Usage != nil {
+		entry.PromptTokens = result.Usage.PromptTokens
+		entry.CompletionTokens = result.Usage.CompletionTokens
+	}
+	if err := config.AppendTranscript(path, cfg.TranscriptMaxBytes(), entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write transcript: %v\n", err)
+	}
+}
+
+// contentOverflow picks the over-limit behavior: the --on-overflow flag,
+// then the config's content_overflow, then the library default.
+func contentOverflow(flagValue string, cfg *config.Config) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return cfg.ContentOverflow
+}
+
+// contentLengthReport describes the final length of generated content,
+// e.g. "274/280 characters (shortened by the model)".
+func contentLengthReport(result celeste.GenerateResult, format string) string {
+	chars := utf8.RuneCountInString(result.Content)
+	limit := celeste.ContentLimit(format)
+	if limit == 0 {
+		return fmt.Sprintf("%d characters", chars)
+	}
+	report := fmt.Sprintf("%d/%d characters", chars, limit)
+	switch {
+	case result.Truncated && result.Shortened:
+		report += " (still over after shortening, trimmed at a word boundary)"
+	case result.Truncated:
+		report += " (trimmed at a word boundary)"
+	case result.Shortened:
+		report += " (shortened by the model)"
+	}
+	return report
+}
+
+// printLintReport writes a content lint warning block to stderr.
+func printLintReport(title string, report celeste.LintReport) {
+	fmt.Fprintf(os.Stderr, "⚠️  %s: %d issue(s)\n", title, len(report.Issues))
+	for _, issue := range report.Issues {
+		fmt.Fprintf(os.Stderr, "   - %s: %s\n", issue.Rule, issue.Message)
+	}
+}
+
+// runContentBatch runs every job in a job file, writing results to outDir,
+// and prints a summary. It exits non-zero if any job failed.
+func runContentBatch(path, outDir string, concurrency int, resume bool, overflow string) {
+	jobs, err := celeste.LoadBatchJobs(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	if len(jobs) == 0 {
+		fmt.Fprintf(os.Stderr, "No jobs in %s\n", path)
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadNamed(configName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.APIKey == "" {
+		fmt.Fprintln(os.Stderr, "No API key configured.")
+		os.Exit(1)
+	}
+	provider := providers.DetectProvider(cfg.BaseURL)
+	if !confirmSpendBudget(cfg, provider) {
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "Running %d jobs from %s → %s\n", len(jobs), path, outDir)
+	start := time.Now()
+	sampling := cfg.Sampling().Merge(samplingFlags)
+	results, err := celeste.RunBatch(context.Background(), celeste.Config{
+		APIKey:        cfg.APIKey,
+		BaseURL:       cfg.BaseURL,
+		Model:         cfg.Model,
+		Timeout:       cfg.GetTimeout(),
+		RequireStream: requireStream,
+		Temperature:   sampling.Temperature,
+		TopP:          sampling.TopP,
+		MaxTokens:     sampling.MaxTokens,
+		ExtraBody:     requestExtraBody(cfg),
+	}, jobs, celeste.BatchOptions{
+		OutputDir:   outDir,
+		Concurrency: concurrency,
+		Resume:      resume,
+		Overflow:    contentOverflow(overflow, cfg),
+		Persona:     personaName,
+		OnResult: func(r celeste.BatchResult) {
+			switch r.Status {
+			case celeste.BatchOK:
+				note := ""
+				if r.Truncated {
+					note = ", trimmed"
+				} else if r.Shortened {
+					note = ", shortened"
+				}
+				fmt.Fprintf(os.Stderr, "  ✓ #%d %s (%d chars%s)\n", r.Index, r.Output, r.Chars, note)
+			case celeste.BatchSkipped:
+				fmt.Fprintf(os.Stderr, "  ↷ #%d %s (already done)\n", r.Index, r.Output)
+			default:
+				fmt.Fprintf(os.Stderr, "  ✗ #%d %s\n", r.Index, r.Error)
+			}
+			if r.Usage != nil {
+				entry := config.NewLedgerEntry(provider, cfg.BaseURL, cfg.Model, r.Usage.PromptTokens, r.Usage.CompletionTokens)
+				if err := config.RecordUsage(entry); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to record usage: %v\n", err)
+				}
+			}
+		},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	summary := celeste.Summarize(results)
+	fmt.Printf("%d succeeded, %d failed, %d skipped. Results: %s\n",
+		summary.Succeeded, summary.Failed, summary.Skipped, filepath.Join(outDir, celeste.BatchResultsFile))
+	if notifier := newNotifier(cfg); notifier != nil {
+		notifier.Done(fmt.Sprintf("batch finished, %d succeeded, %d failed", summary.Succeeded, summary.Failed), time.Since(start))
+	}
+	if summary.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runCompareCommand sends one prompt to each comma-separated target and
+// prints the responses under labeled headers. It exits non-zero only when
+// every target failed.
+func runCompareCommand(targetList string, args []string) {
+	jsonOutput := false
+	var promptArgs []string
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+			continue
+		}
+		promptArgs = append(promptArgs, arg)
+	}
+	prompt := strings.TrimSpace(strings.Join(promptArgs, " "))
+	if prompt == "" {
+		fmt.Fprintln(os.Stderr, "Usage: celeste --compare <name,name,...> [--json] <prompt>")
+		os.Exit(1)
+	}
+
+	var names []string
+	for _, name := range strings.Split(targetList, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) < 2 {
+		fmt.Fprintln(os.Stderr, "Error: --compare needs at least two comma-separated providers")
+		os.Exit(1)
+	}
+
+	type compareSource struct {
+		cfg      *config.Config
+		provider string
+	}
+	targets := make([]celeste.CompareTarget, 0, len(names))
+	sources := make([]compareSource, 0, len(names))
+	for _, name := range names {
+		cfg, target, err := resolveCompareTarget(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		provider := providers.DetectProvider(target.Config.BaseURL)
+		if !confirmSpendBudget(cfg, provider) {
+			os.Exit(1)
+		}
+		targets = append(targets, target)
+		sources = append(sources, compareSource{cfg: cfg, provider: provider})
+	}
+
+	opts := celeste.CompareOptions{}
+	if !jsonOutput {
+		fmt.Fprintf(os.Stderr, "Comparing %d providers...\n", len(targets))
+		// Print each response as soon as it is ready so a slow provider
+		// doesn't hold up the others
+		opts.OnResult = func(_ int, result celeste.CompareResult) {
+			printCompareResult(result)
+		}
+	}
+
+	results := celeste.Compare(context.Background(), targets, celeste.GenerateRequest{
+		Prompt:      prompt,
+		Temperature: samplingFlags.Temperature,
+		TopP:        samplingFlags.TopP,
+		MaxTokens:   samplingFlags.MaxTokens,
+	}, opts)
+
+	failed := 0
+	for i, result := range results {
+		if result.Failed() {
+			failed++
+			continue
+		}
+		if result.Usage != nil {
+			src := sources[i]
+			entry := config.NewLedgerEntry(src.provider, targets[i].Config.BaseURL, targets[i].Config.Model,
+				result.Usage.PromptTokens, result.Usage.CompletionTokens)
+			if err := config.RecordUsage(entry); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to record usage: %v\n", err)
+			}
+		}
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	}
+
+	if failed == len(results) {
+		os.Exit(1)
+	}
+}
+
+// resolveCompareTarget loads the config for a --compare name. "default" is
+// the default config, "venice" falls back to the Venice.ai settings when
+// there is no config.venice.json, and anything else is a named profile.
+func resolveCompareTarget(name string) (*config.Config, celeste.CompareTarget, error) {
+	target := celeste.CompareTarget{Name: name}
+
+	var cfg *config.Config
+	var err error
+	_, statErr := os.Stat(config.NamedConfigPath(name))
+	switch {
+	case name == "default":
+		cfg, err = config.Load()
+	case name == "venice" && statErr != nil:
+		cfg, err = config.Load()
+		if err == nil {
+			if cfg.VeniceAPIKey == "" {
+				return nil, target, fmt.Errorf("venice: no Venice API key configured")
+			}
+			cfg.APIKey = cfg.VeniceAPIKey
+			cfg.BaseURL = cfg.VeniceBaseURL
+			cfg.Model = cfg.VeniceModel
+		}
+	default:
+		cfg, err = config.LoadNamed(name)
+	}
+	if err != nil {
+		return nil, target, err
+	}
+	if cfg.APIKey == "" && !config.IsLocalProvider(providers.DetectProvider(cfg.BaseURL), cfg.BaseURL) {
+		return nil, target, fmt.Errorf("%s: no API key configured", name)
+	}
+
+	target.Config = celeste.Config{
+		APIKey:            cfg.APIKey,
+		BaseURL:           cfg.BaseURL,
+		Model:             cfg.Model,
+		Timeout:           cfg.GetTimeout(),
+		SkipPersonaPrompt: cfg.SkipPersonaPrompt,
+		RequireStream:     requireStream,
+		Temperature:       cfg.Temperature,
+		TopP:              cfg.TopP,
+		MaxTokens:         cfg.MaxTokens,
+		ExtraBody:         requestExtraBody(cfg),
+	}
+	return cfg, target, nil
+}
+
+// printCompareResult prints one --compare response under a labeled header.
+func printCompareResult(result celeste.CompareResult) {
+	header := fmt.Sprintf("━━━ %s (%.1fs", result.Provider, float64(result.ElapsedMS)/1000)
+	if result.Usage != nil {
+		header += fmt.Sprintf(", %d in / %d out tokens", result.Usage.PromptTokens, result.Usage.CompletionTokens)
+	}
+	header += ") ━━━"
+
+	if result.Failed() {
+		fmt.Println(ansiColor("31", header))
+		fmt.Printf("Error: %s\n\n", result.Error)
+		return
+	}
+	fmt.Println(ansiColor("36", header))
+	fmt.Printf("%s\n\n", result.Content)
+}
+
+// ansiColor wraps text in an ANSI color escape unless color is disabled.
+func ansiColor(code, text string) string {
+	if !config.ColorEnabled() {
+		return text
+	}
+	return "\033[" + code + "m" + text + "\033[0m"
+}
+
+// confirmSpendBudget checks this month's spend against the configured budget.
+// At 80% it prints a warning; at 100% it asks the user to type "yes" before
+// sending another paid request. Returns false if the request should not be sent.
+func confirmSpendBudget(cfg *config.Config, provider string) bool {
+	if cfg.MonthlyBudgetUSD <= 0 || config.IsLocalProvider(provider, cfg.BaseURL) {
+		return true
+	}
+
+	ledger, err := config.LoadUsageLedger()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return true
+	}
+
+	spent := ledger.SpendForMonth(time.Now().Format("2006-01"))
+	switch config.CheckBudget(spent, cfg.MonthlyBudgetUSD) {
+	case config.BudgetWarn:
+		fmt.Fprintln(os.Stderr, ansiColor("33", fmt.Sprintf("⚠ %s of %s monthly budget used",
+			config.FormatCost(spent), config.FormatCost(cfg.MonthlyBudgetUSD))))
+	case config.BudgetExceeded:
+		fmt.Fprint(os.Stderr, ansiColor("31", fmt.Sprintf("✖ Monthly budget exceeded: %s of %s used. Type yes to continue: ",
+			config.FormatCost(spent), config.FormatCost(cfg.MonthlyBudgetUSD))))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(answer)) != "yes" {
+			fmt.Fprintln(os.Stderr, "Request cancelled.")
+			return false
+		}
+	}
+	return true
 }
 
 // SessionManagerAdapter adapts config.SessionManager to tui.SessionManager interface.
 type SessionManagerAdapter struct {
-	manager *config.SessionManager
+	manager   *config.SessionManager
+	autosaver *config.Autosaver
 }
 
 func (a *SessionManagerAdapter) NewSession() interface{} {
@@ -1348,9 +3515,27 @@ func (a *SessionManagerAdapter) List() ([]interface{}, error) {
 }
 
 func (a *SessionManagerAdapter) Delete(id string) error {
+	a.autosaver.Cancel(id)
 	return a.manager.Delete(id)
 }
 
+// Autosave implements tui.SessionAutosaver.
+func (a *SessionManagerAdapter) Autosave(session interface{}, after func()) {
+	if s, ok := session.(*config.Session); ok {
+		a.autosaver.Save(s, after)
+	}
+}
+
+// Flush writes any autosave still waiting for its interval.
+func (a *SessionManagerAdapter) Flush() error {
+	return a.autosaver.Flush()
+}
+
+// NewPartialWriter implements tui.PartialStore.
+func (a *SessionManagerAdapter) NewPartialWriter(sessionID string) *config.PartialWriter {
+	return a.manager.NewPartialWriter(sessionID)
+}
+
 func (a *SessionManagerAdapter) MergeSessions(session1, session2 interface{}) interface{} {
 	s1, ok1 := session1.(*config.Session)
 	s2, ok2 := session2.(*config.Session)
@@ -1405,6 +3590,13 @@ func runStatsCommand(args []string) {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
+	config.SetAnimation(cfg.Animation())
+
+	// Handle --spend [--month YYYY-MM] from the usage ledger
+	if len(args) > 0 && args[0] == "--spend" {
+		runSpendReport(cfg, args[1:])
+		return
+	}
 
 	// Load most recent session
 	manager := config.NewSessionManager()
@@ -1434,6 +3626,26 @@ func runStatsCommand(args []string) {
 	}
 }
 
+// runSpendReport prints the monthly spend report from the usage ledger.
+func runSpendReport(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("stats --spend", flag.ExitOnError)
+	month := fs.String("month", time.Now().Format("2006-01"), "Month to report (YYYY-MM)")
+	_ = fs.Parse(args)
+
+	if _, err := time.Parse("2006-01", *month); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid month %q (expected YYYY-MM)\n", *month)
+		os.Exit(1)
+	}
+
+	ledger, err := config.LoadUsageLedger()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(config.FormatSpendReport(ledger.MonthlyReport(*month), cfg.MonthlyBudgetUSD))
+}
+
 // runProvidersCommand handles standalone provider listing and information.
 func runProvidersCommand(args []string) {
 	// Load config to get current provider
@@ -1471,6 +3683,10 @@ func runProvidersCommand(args []string) {
 
 // runExportCommand handles standalone data export.
 func runExportCommand(args []string) {
+	if cfg, err := config.Load(); err == nil {
+		config.SetAnimation(cfg.Animation())
+	}
+
 	// Load most recent session if exporting current session
 	manager := config.NewSessionManager()
 	sessions, err := manager.List()
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/skills"
+)
+
+// resetGlobalFlags clears the state parseGlobalFlags sets once the test ends.
+func resetGlobalFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		configName, personaName, transcriptFlag, compareFlag = "", "", "", ""
+		samplingFlags = config.Sampling{}
+		timeoutFlag = 0
+		requireStream, notifyFlag = false, false
+		extraBodyFlags, extraBodyFlag, extraFlags = nil, "", nil
+	})
+}
+
+// TestParseGlobalFlags tests that global flags are read before the command
+func TestParseGlobalFlags(t *testing.T) {
+	resetGlobalFlags(t)
+
+	args, err := parseGlobalFlags([]string{
+		"-config", "grok", "--persona=celeste", "--timeout", "90", "--notify",
+		"--extra", "a=1", "--extra", "b=2", "message", "hi",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"message", "hi"}, args)
+	assert.Equal(t, "grok", configName)
+	assert.Equal(t, "celeste", personaName)
+	assert.Equal(t, 90*time.Second, timeoutFlag)
+	assert.True(t, notifyFlag)
+	assert.Len(t, extraBodyFlags, 2, "every --extra is kept")
+}
+
+// TestParseGlobalFlagsStopsAtCommand tests that flags after the command name
+// or "--" are left for the command
+func TestParseGlobalFlagsStopsAtCommand(t *testing.T) {
+	resetGlobalFlags(t)
+
+	args, err := parseGlobalFlags([]string{"session", "--summarize", "abc", "--persona", "critic"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"session", "--summarize", "abc", "--persona", "critic"}, args)
+	assert.Empty(t, personaName)
+
+	args, err = parseGlobalFlags([]string{"--notify", "--", "--timeout", "is a word here"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--timeout", "is a word here"}, args)
+	assert.True(t, notifyFlag)
+	assert.Zero(t, timeoutFlag)
+
+	args, err = parseGlobalFlags([]string{"--compare", "do,venice", "--json", "Write an intro"})
+	require.NoError(t, err)
+	assert.Equal(t, "do,venice", compareFlag)
+	assert.Equal(t, []string{"--json", "Write an intro"}, args)
+}
+
+// TestParseGlobalFlagsErrors tests that bad values are reported
+func TestParseGlobalFlagsErrors(t *testing.T) {
+	resetGlobalFlags(t)
+
+	for _, args := range [][]string{
+		{"--timeout", "soon", "chat"},
+		{"--temperature", "warm", "chat"},
+		{"--persona"},
+		{"--notify=yes", "chat"},
+		{"--extra-body", "[1]", "chat"},
+		{"--persona", "nobody-by-that-name", "chat"},
+	} {
+		_, err := parseGlobalFlags(args)
+		assert.Error(t, err, "%v", args)
+	}
+}
+
+// TestMessageArgs tests that global flags after message apply before the
+// text, and that flags within the text are reported instead of sent
+func TestMessageArgs(t *testing.T) {
+	resetGlobalFlags(t)
+
+	text, stream, err := messageArgs([]string{"--timeout", "300", "--no-stream", "--notify", "hi", "there"})
+	require.NoError(t, err)
+	assert.Equal(t, "hi there", text)
+	assert.False(t, stream)
+	assert.Equal(t, 300*time.Second, timeoutFlag)
+	assert.True(t, notifyFlag)
+
+	text, stream, err = messageArgs([]string{"--", "--timeout", "is a word here"})
+	require.NoError(t, err)
+	assert.Equal(t, "--timeout is a word here", text)
+	assert.True(t, stream)
+
+	for _, args := range [][]string{
+		{"hi", "--safe-mode"},
+		{"hi", "--timeout", "300"},
+		{"--bogus", "hi"},
+		{"--config", "grok", "hi"},
+		{"--no-stream=later", "hi"},
+		{"--timeout", "soon", "hi"},
+	} {
+		_, _, err := messageArgs(args)
+		assert.Error(t, err, "%v", args)
+	}
+}
+
+// TestChatArgs tests that chat applies the global flags after it and
+// rejects anything else
+func TestChatArgs(t *testing.T) {
+	resetGlobalFlags(t)
+
+	require.NoError(t, chatArgs([]string{"--timeout", "300", "--notify"}))
+	assert.Equal(t, 300*time.Second, timeoutFlag)
+	assert.True(t, notifyFlag)
+
+	assert.Error(t, chatArgs([]string{"hello"}))
+	assert.Error(t, chatArgs([]string{"--bogus"}))
+	assert.Error(t, chatArgs([]string{"--compare", "a,b"}))
+
+	// Safe mode can't be turned off again, so this comes last
+	require.NoError(t, chatArgs([]string{"--safe-mode"}))
+	assert.True(t, config.IsSafeMode())
+}
+
+// TestSkillArgsGenerateImage tests generate_image arguments given on the
+// command line, from celeste skill's parsing to the Venice.ai request
+func TestSkillArgsGenerateImage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var payload map[string]interface{}
+	img := base64.StdEncoding.EncodeToString([]byte{0x89, 0x50, 0x4E, 0x47})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"images": []string{img}})
+	}))
+	defer server.Close()
+
+	loader := skills.NewMockConfigLoader()
+	loader.VeniceCfg = skills.VeniceConfig{APIKey: "test-key", BaseURL: server.URL, ImageModel: "lustify-sdxl"}
+	registry := skills.NewRegistry()
+	skills.RegisterBuiltinSkills(registry, loader)
+	executor := skills.NewExecutor(registry)
+
+	for _, tt := range []struct {
+		args []string
+		cfg  float64
+	}{
+		{args: []string{"--prompt", "a fox", "--cfg_scale", "7", "--steps", "30"}, cfg: 7},
+		{args: []string{"--prompt", "a fox", "--cfg-scale", "6.5", "--negative-prompt", "people"}, cfg: 6.5},
+	} {
+		argsJSON, err := json.Marshal(parseSkillArgs(tt.args))
+		require.NoError(t, err)
+
+		result, err := executor.Execute(context.Background(), "generate_image", string(argsJSON))
+		require.NoError(t, err)
+		data := result.Result.(map[string]interface{})
+		require.Equal(t, true, data["success"], "%v: %v", tt.args, data)
+		assert.Equal(t, tt.cfg, payload["cfg_scale"], "%v", tt.args)
+	}
+	assert.Equal(t, "people", payload["negative_prompt"])
+}
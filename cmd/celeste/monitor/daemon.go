@@ -10,8 +10,9 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/config"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/skills"
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/skills"
 )
 
 // Daemon manages the background wallet monitoring process
@@ -24,8 +25,7 @@ type Daemon struct {
 
 // NewDaemon creates a new monitoring daemon
 func NewDaemon(configLoader *config.ConfigLoader) *Daemon {
-	homeDir, _ := os.UserHomeDir()
-	pidFile := filepath.Join(homeDir, ".celeste", "wallet_monitor.pid")
+	pidFile := paths.Data("wallet_monitor.pid")
 
 	return &Daemon{
 		configLoader: configLoader,
@@ -3,23 +3,37 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/whykusanagi/celesteCLI/cmd/celeste/commands"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/config"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/providers"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/venice"
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/notify"
+	"github.com/whykusanagi/celesteCLI/internal/prompts"
+	"github.com/whykusanagi/celesteCLI/internal/providers"
+	"github.com/whykusanagi/celesteCLI/internal/venice"
 )
 
 // Typing speed: ~25 chars/sec for smooth, visible corruption effects
 const charsPerTick = 2
 const typingTickInterval = 80 * time.Millisecond
 
+// SkillTimeout is how long a skill call may run. LLMClient.ExecuteSkill
+// should give up at this limit; the chat marks calls past it as timed out.
+const SkillTimeout = 30 * time.Second
+
+// skillTickInterval is how often the elapsed time of running skills updates
+const skillTickInterval = time.Second
+
 // AppModel is the root model for the Celeste TUI application.
 type AppModel struct {
 	// Sub-components
@@ -34,23 +48,39 @@ type AppModel struct {
 	height        int
 	ready         bool
 	nsfwMode      bool
+	safeMode      bool // Safe mode (--safe-mode / CELESTE_SAFE_MODE) blocks every NSFW pathway
 	streaming     bool
-	endpoint      string // Current endpoint (openai, venice, grok, etc.)
-	safeEndpoint  string // Endpoint to return to when leaving NSFW mode
-	model         string // Current model name
-	imageModel    string // Current image generation model (for NSFW mode)
-	provider      string // Current provider (grok, openai, venice, etc.) - detected from endpoint
-	skillsEnabled bool   // Whether skills/function calling is available
-	version       string // Application version (e.g., "1.0.1")
-	build         string // Build identifier (e.g., "bubbletea-tui")
+	endpoint      string          // Current endpoint (openai, venice, grok, etc.)
+	safeEndpoint  string          // Endpoint to return to when leaving NSFW mode
+	model         string          // Current model name
+	imageModel    string          // Current image generation model (for NSFW mode)
+	provider      string          // Current provider (grok, openai, venice, etc.) - detected from endpoint
+	skillsEnabled bool            // Whether skills/function calling is available
+	persona       string          // Active persona (empty means prompts.DefaultPersona)
+	sampling      config.Sampling // Session sampling overrides from /set
+	searchKeys    bool            // n/N jump between /search matches until another key
+	canceled      bool            // The reply in flight was canceled; drop it when it arrives
+	version       string          // Application version (e.g., "1.0.1")
+	build         string          // Build identifier (e.g., "bubbletea-tui")
 
 	// Simulated typing state
 	typingContent string // Full content to type
 	typingPos     int    // Current position in content
 	animFrame     int    // Animation frame counter
 
-	// Pending tool call tracking
-	pendingToolCallID string // Track tool call ID for sending result back to LLM
+	// Pending tool calls from the last assistant message. Results are held
+	// until every call has answered, then sent back in call order.
+	pendingToolCalls   []ToolCallInfo
+	pendingToolResults map[string]string // Tool call ID -> result
+	skillTicking       bool              // A SkillTickMsg is scheduled
+
+	// Images attached with /image, sent with the next user message
+	pendingImages []commands.ImageAttachment
+
+	// Monthly budget confirmation: the message held back while waiting for
+	// "yes", and whether the user already agreed to keep spending
+	budgetPending   string
+	budgetConfirmed bool
 
 	// LLM client (injected)
 	llmClient LLMClient
@@ -59,6 +89,9 @@ type AppModel struct {
 	sessionManager SessionManager
 	currentSession Session
 
+	// Sidecar the streaming reply is written to until it is saved
+	partial *config.PartialWriter
+
 	// Configuration (for context limits, etc.)
 	config *config.Config
 
@@ -68,6 +101,12 @@ type AppModel struct {
 	// Interactive selector
 	selector       SelectorModel
 	selectorActive bool
+
+	// Completion notifications (optional): sent when a generation finishes
+	// after the terminal reported losing focus
+	notifier        *notify.Notifier
+	blurred         bool
+	generationStart time.Time
 }
 
 // LLMClient interface for sending messages to the LLM.
@@ -77,25 +116,89 @@ type LLMClient interface {
 	ExecuteSkill(name string, args map[string]any, toolCallID string) tea.Cmd
 }
 
+// VisionChecker is implemented by clients that can report whether the
+// current model accepts image input.
+type VisionChecker interface {
+	SupportsVision() bool
+}
+
 // EndpointSwitcher interface for clients that support dynamic endpoint switching.
 type EndpointSwitcher interface {
 	SwitchEndpoint(endpoint string) error
 	ChangeModel(model string) error
 }
 
-// SkillDefinition represents a skill/function that can be called.
-type SkillDefinition struct {
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	Parameters  map[string]any `json:"parameters"`
+// PersonaSwitcher is implemented by clients that can change the persona
+// system prompt mid-session.
+type PersonaSwitcher interface {
+	SetPersona(name string) error
+}
+
+// SystemPromptOverrider is implemented by clients that can report the
+// system prompt they send and replace it for the rest of the chat (/system).
+type SystemPromptOverrider interface {
+	SystemPrompt() (prompt string, custom bool)
+	OverrideSystemPrompt(prompt string) // "" goes back to the persona's prompt
+}
+
+// SamplingSetter is implemented by clients that accept session overrides
+// for temperature, top_p and max_tokens (set with /set).
+type SamplingSetter interface {
+	SetSampling(sampling config.Sampling)
+}
+
+// PartialRecorder is implemented by clients that can write the next
+// streamed reply to a .partial sidecar as chunks arrive.
+type PartialRecorder interface {
+	RecordPartial(w *config.PartialWriter)
+}
+
+// RequestCanceler is implemented by clients that can cancel the request
+// started by the last SendMessage, for Ctrl+C and Esc mid-reply.
+type RequestCanceler interface {
+	CancelRequest()
+}
+
+// PartialStore is implemented by session managers that keep streaming
+// replies recoverable after a crash.
+type PartialStore interface {
+	NewPartialWriter(sessionID string) *config.PartialWriter
+}
+
+// SessionAutosaver is implemented by session managers that coalesce saves
+// made in quick succession. after is called once the save has succeeded.
+type SessionAutosaver interface {
+	Autosave(session interface{}, after func())
 }
 
+// SkillsReloader is implemented by clients that can reload user-defined
+// skills from disk.
+type SkillsReloader interface {
+	ReloadSkills() (string, error)
+}
+
+// ConversationSummarizer is implemented by clients that can recap the
+// conversation in the persona's voice (used by /summarize).
+type ConversationSummarizer interface {
+	SummarizeConversation(messages []ChatMessage, style string) tea.Cmd
+}
+
+// TemperatureSender is implemented by clients that can send a single request
+// at a given sampling temperature (used by /retry <temperature>).
+type TemperatureSender interface {
+	SendMessageWithTemperature(messages []ChatMessage, tools []SkillDefinition, temperature float32) tea.Cmd
+}
+
+// SkillDefinition represents a skill/function that can be called.
+type SkillDefinition = chat.SkillDefinition
+
 // VeniceConfigData holds Venice.ai configuration from skills.json.
 type VeniceConfigData struct {
 	APIKey     string
 	BaseURL    string
 	Model      string // Chat model
 	ImageModel string // Image generation model
+	Upscaler   string // Upscaler model
 }
 
 // loadVeniceConfig loads Venice configuration from ~/.celeste/skills.json.
@@ -120,6 +223,7 @@ func loadVeniceConfig() (VeniceConfigData, error) {
 		BaseURL:    veniceConfig.BaseURL,
 		Model:      veniceConfig.Model,
 		ImageModel: veniceConfig.ImageModel,
+		Upscaler:   veniceConfig.Upscaler,
 	}, nil
 }
 
@@ -135,7 +239,7 @@ func NewApp(llmClient LLMClient) AppModel {
 		chat:      NewChatModel(),
 		input:     NewInputModel(),
 		skills:    NewSkillsModel(skills),
-		status:    NewStatusModel(),
+		status:    NewStatusModel().SetPersona(prompts.DefaultPersona),
 		llmClient: llmClient,
 	}
 }
@@ -161,9 +265,39 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Right after /search, n and N step through the matches and Esc
+		// clears them; any other key goes back to typing
+		if m.searchKeys {
+			switch msg.String() {
+			case "n":
+				m.chat = m.chat.NextMatch(1)
+				return m.showSearchPosition(), nil
+			case "N":
+				m.chat = m.chat.NextMatch(-1)
+				return m.showSearchPosition(), nil
+			}
+			m.searchKeys = false
+		}
+
 		switch msg.String() {
 		case "ctrl+c":
+			// The first press stops a reply; the next one exits
+			if m.busy() {
+				return m.cancelResponse(), nil
+			}
 			return m, tea.Quit
+		case "esc":
+			if m.busy() {
+				return m.cancelResponse(), nil
+			}
+			if current, _ := m.chat.SearchPosition(); current > 0 {
+				m.chat = m.chat.ClearSearch()
+				m.status = m.status.SetText("Search cleared")
+				break
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
 		case "ctrl+k":
 			// Toggle skill call logs visibility
 			m.chat = m.chat.ToggleSkillCalls()
@@ -183,6 +317,12 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.skills = m.skills.SetCurrentInput(m.input.Value())
 		}
 
+	case tea.FocusMsg:
+		m.blurred = false
+
+	case tea.BlurMsg:
+		m.blurred = true
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -214,6 +354,24 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case SendMessageMsg:
 		content := strings.TrimSpace(msg.Content)
 
+		// Answer to the over-budget confirmation
+		if m.budgetPending != "" {
+			pending := m.budgetPending
+			m.budgetPending = ""
+			if !strings.EqualFold(content, "yes") {
+				m.chat = m.chat.AddSystemMessage("💸 Request cancelled.")
+				m.status = m.status.SetText("Request cancelled (over budget)")
+				return m, nil
+			}
+			m.budgetConfirmed = true
+			content = pending
+		}
+
+		// A lone image path is what terminals paste when a file is dragged in
+		if path, ok := commands.DetectImagePath(content); ok {
+			return m.attachImage([]string{path})
+		}
+
 		// Check if it's a slash command first
 		if cmd := commands.Parse(content); cmd != nil {
 			// Handle Phase 4 commands that require app state (contextTracker, currentSession)
@@ -245,6 +403,30 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.chat = m.chat.AddSystemMessage(result.Message)
 				}
 				return m, nil
+
+			case "reload-skills":
+				reloader, ok := m.llmClient.(SkillsReloader)
+				if !ok {
+					m.chat = m.chat.AddSystemMessage("⚠️ Skill reloading is not supported by this client")
+					return m, nil
+				}
+				summary, err := reloader.ReloadSkills()
+				return m.applySkillsReload(summary, err), nil
+
+			case "summarize":
+				return m.summarizeConversation(cmd.Args)
+
+			case "search":
+				return m.searchChat(cmd.Args), nil
+
+			case "retry":
+				return m.retryLastExchange(cmd.Args)
+
+			case "edit":
+				return m.editLastMessage(), nil
+
+			case "image", "attach":
+				return m.attachImage(cmd.Args)
 			}
 
 			// For other commands, use normal execution flow
@@ -253,6 +435,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// If not available, commands will fall back to static model lists
 			ctx := &commands.CommandContext{
 				NSFWMode:      m.nsfwMode,
+				SafeMode:      m.safeMode,
 				Provider:      m.provider,
 				CurrentModel:  m.model,
 				APIKey:        "", // Will be populated if config accessible
@@ -260,6 +443,14 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				SkillsEnabled: m.skillsEnabled,
 				Version:       m.version,
 				Build:         m.build,
+				Persona:       m.persona,
+				Sampling:      m.sampling,
+			}
+			if m.config != nil {
+				ctx.ContextLimit = m.config.ContextLimit
+			}
+			if overrider, ok := m.llmClient.(SystemPromptOverrider); ok {
+				ctx.SystemPrompt, ctx.SystemCustom = overrider.SystemPrompt()
 			}
 			result := commands.Execute(cmd, ctx)
 
@@ -323,7 +514,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Persist session state
 					m.persistSession()
 				}
-				if result.StateChange.NSFWMode != nil {
+				if result.StateChange.NSFWMode != nil && !(m.safeMode && *result.StateChange.NSFWMode) {
 					m.nsfwMode = *result.StateChange.NSFWMode
 					m.header = m.header.SetNSFWMode(m.nsfwMode)
 
@@ -387,6 +578,19 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if result.StateChange.ClearHistory {
 					m.chat = m.chat.Clear()
 				}
+				if result.StateChange.Persona != nil {
+					m = m.switchPersona(*result.StateChange.Persona)
+					m.persistSession()
+				}
+				if result.StateChange.SystemPrompt != nil {
+					if overrider, ok := m.llmClient.(SystemPromptOverrider); ok {
+						overrider.OverrideSystemPrompt(*result.StateChange.SystemPrompt)
+					}
+				}
+				if result.StateChange.Sampling != nil {
+					m = m.setSampling(*result.StateChange.Sampling)
+					m.persistSession()
+				}
 
 				if result.StateChange.MenuState != nil {
 					m.skills = m.skills.SetMenuState(*result.StateChange.MenuState)
@@ -433,7 +637,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "help":
 			// Use context-aware /help command instead of static helpText()
 			helpCmd := &commands.Command{Name: "help"}
-			ctx := &commands.CommandContext{NSFWMode: m.nsfwMode}
+			ctx := &commands.CommandContext{NSFWMode: m.nsfwMode, SafeMode: m.safeMode}
 			result := commands.Execute(helpCmd, ctx)
 			if result.Success {
 				m.chat = m.chat.AddSystemMessage(result.Message)
@@ -454,7 +658,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Check for routing hints (hashtags or keywords at end)
-		suggestedEndpoint := commands.DetectRoutingHints(content)
+		// Routing hints only ever point at uncensored endpoints, so safe mode ignores them
+		suggestedEndpoint := ""
+		if !m.safeMode {
+			suggestedEndpoint = commands.DetectRoutingHints(content)
+		}
 		if suggestedEndpoint != "" && suggestedEndpoint != m.endpoint {
 			// Auto-route based on hints
 			m.endpoint = suggestedEndpoint
@@ -476,7 +684,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Check for Venice media commands in NSFW mode
-		if m.nsfwMode {
+		if m.nsfwMode && !m.safeMode {
 			LogInfo(fmt.Sprintf("Checking for media command in: '%s'", content))
 			mediaType, prompt, params, isMediaCmd := venice.ParseMediaCommand(content)
 			LogInfo(fmt.Sprintf("ParseMediaCommand result: isMediaCmd=%v, mediaType=%s, prompt='%s'", isMediaCmd, mediaType, prompt))
@@ -522,8 +730,39 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Hold paid requests once the monthly budget is used up
+		if !m.budgetConfirmed && m.isPaidRequest() {
+			spent, level := m.monthlySpend()
+			m.status = m.status.SetBudget(level, config.FormatCost(spent))
+			if level == config.BudgetExceeded {
+				m.budgetPending = content
+				m.chat = m.chat.AddSystemMessage(BudgetExceededStyle.Render(fmt.Sprintf(
+					"💸 Monthly budget exceeded: %s of %s used.\n\nType yes to send this request anyway, or anything else to cancel.",
+					config.FormatCost(spent), config.FormatCost(m.config.MonthlyBudgetUSD))))
+				return m, nil
+			}
+		}
+
+		// Attach any images queued with /image
+		var images []string
+		var imageRefs []ImageRef
+		if len(m.pendingImages) > 0 {
+			if !m.supportsVision() {
+				// The model may have changed since the image was attached
+				m.pendingImages = nil
+				m.chat = m.chat.AddSystemMessage("🖼️ The current model doesn't accept images, so the attached image was dropped.\n\nSwitch to a vision-capable model and use /image again.")
+				return m, nil
+			}
+			for _, img := range m.pendingImages {
+				images = append(images, img.URL)
+				imageRefs = append(imageRefs, ImageRef{Source: img.Source, Label: img.Label()})
+			}
+			m.pendingImages = nil
+		}
+
 		// Add user message to chat
-		m.chat = m.chat.AddUserMessage(content)
+		m.chat = m.chat.AddUserMessageWithImages(content, images, imageRefs)
+		m = m.startGeneration()
 		m.streaming = true
 		m.status = m.status.SetStreaming(true)
 		m.status = m.status.SetText(StreamingSpinner(0) + " " + ThinkingAnimation(0))
@@ -534,6 +773,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				configSession.Messages = append(configSession.Messages, config.SessionMessage{
 					Role:      "user",
 					Content:   content,
+					Images:    sessionImageSources(imageRefs),
 					Timestamp: time.Now(),
 				})
 			}
@@ -550,16 +790,25 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				toolsToSend = m.skills.GetDefinitions()
 			}
 
+			m.recordPartial()
 			cmds = append(cmds, m.llmClient.SendMessage(m.chat.GetMessages(), toolsToSend))
 			// Start animation tick for waiting state
-			cmds = append(cmds, tea.Tick(typingTickInterval*2, func(t time.Time) tea.Msg {
+			cmds = append(cmds, tea.Tick(animationInterval(), func(t time.Time) tea.Msg {
 				return TickMsg{Time: t}
 			}))
 		}
 
 	case GenerateMediaMsg:
+		if m.safeMode {
+			m.chat = m.chat.SetLastAssistantContent("🛡️ Media generation is disabled in safe mode")
+			m.status = m.status.SetText("Media generation blocked (safe mode)")
+			return m, nil
+		}
+
 		// Generate media asynchronously via Venice.ai
 		LogInfo(fmt.Sprintf("→ Starting %s generation with prompt: '%s'", msg.MediaType, msg.Prompt))
+		m = m.startGeneration()
+		safeMode := m.safeMode
 		cmds = append(cmds, func() tea.Msg {
 			// Load Venice config from skills.json
 			LogInfo("Loading Venice config from skills.json")
@@ -585,33 +834,41 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					modelToUse = veniceConfig.ImageModel
 					LogInfo(fmt.Sprintf("Using config image model: %s", modelToUse))
 				}
+			} else if msg.MediaType == "upscale" {
+				// --model in the command overrides the configured upscaler
+				modelToUse = veniceConfig.Upscaler
+				if model, ok := msg.Params["model"].(string); ok && model != "" {
+					modelToUse = model
+				}
 			}
 			LogInfo(fmt.Sprintf("Using model: %s for %s generation", modelToUse, msg.MediaType))
 
 			config := venice.Config{
-				APIKey:  veniceConfig.APIKey,
-				BaseURL: veniceConfig.BaseURL,
-				Model:   modelToUse,
+				APIKey:   veniceConfig.APIKey,
+				BaseURL:  veniceConfig.BaseURL,
+				Model:    modelToUse,
+				SafeMode: safeMode,
 			}
 
 			var response *venice.MediaResponse
 			var genErr error
+			ctx := context.Background()
 
 			LogInfo(fmt.Sprintf("Calling Venice.ai API for %s generation", msg.MediaType))
 			switch msg.MediaType {
 			case "image":
-				response, genErr = venice.GenerateImage(config, msg.Prompt, msg.Params)
+				response, genErr = venice.GenerateImage(ctx, config, msg.Prompt, msg.Params)
 			case "video":
-				response, genErr = venice.GenerateVideo(config, msg.Prompt, msg.Params)
+				response, genErr = venice.GenerateVideo(ctx, config, msg.Prompt, msg.Params)
 			case "upscale":
 				if path, ok := msg.Params["path"].(string); ok {
-					response, genErr = venice.UpscaleImage(config, path, msg.Params)
+					response, genErr = venice.UpscaleImage(ctx, config, path, msg.Params)
 				} else {
 					genErr = fmt.Errorf("no image path provided for upscale")
 				}
 			case "image-to-video":
 				if path, ok := msg.Params["path"].(string); ok {
-					response, genErr = venice.ImageToVideo(config, path, msg.Params)
+					response, genErr = venice.ImageToVideo(ctx, config, path, msg.Params)
 				} else {
 					genErr = fmt.Errorf("no image path provided for image-to-video")
 				}
@@ -651,7 +908,13 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Success:   true,
 				URL:       response.URL,
 				Path:      response.Path,
+				Paths:     response.Paths,
 				MediaType: msg.MediaType,
+				Params:    response.Params,
+				Width:     response.Width,
+				Height:    response.Height,
+				Seed:      response.Seed,
+				Warning:   response.Warning,
 			}
 		})
 
@@ -659,16 +922,35 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle media generation result
 		LogInfo(fmt.Sprintf("Received MediaResultMsg: success=%v, mediaType=%s", msg.Success, msg.MediaType))
 		if msg.Success {
+			generated := "generated successfully"
+			if msg.Seed != nil {
+				generated += fmt.Sprintf(" with seed %d", *msg.Seed)
+			}
 			var resultText string
 			if msg.URL != "" {
 				LogInfo(fmt.Sprintf("✓ Media generation SUCCESS: URL=%s", msg.URL))
-				resultText = fmt.Sprintf("✅ %s generated successfully!\n\n🔗 URL: %s", msg.MediaType, msg.URL)
+				resultText = fmt.Sprintf("✅ %s %s!\n\n🔗 URL: %s", msg.MediaType, generated, msg.URL)
+			} else if len(msg.Paths) > 1 {
+				LogInfo(fmt.Sprintf("✓ Media generation SUCCESS: %d files", len(msg.Paths)))
+				resultText = fmt.Sprintf("✅ %d %s variants %s!\n\n💾 Saved to:", len(msg.Paths), msg.MediaType, generated)
+				for i, path := range msg.Paths {
+					resultText += fmt.Sprintf("\n  %d. %s", i+1, path)
+				}
 			} else if msg.Path != "" {
 				LogInfo(fmt.Sprintf("✓ Media generation SUCCESS: Path=%s", msg.Path))
-				resultText = fmt.Sprintf("✅ %s generated successfully!\n\n💾 Saved to: %s", msg.MediaType, msg.Path)
+				resultText = fmt.Sprintf("✅ %s %s!\n\n💾 Saved to: %s", msg.MediaType, generated, msg.Path)
 			} else {
 				LogInfo("✓ Media generation SUCCESS (no URL/Path)")
-				resultText = fmt.Sprintf("✅ %s generated successfully!", msg.MediaType)
+				resultText = fmt.Sprintf("✅ %s %s!", msg.MediaType, generated)
+			}
+			if msg.Width > 0 && msg.Height > 0 {
+				resultText += fmt.Sprintf("\n📐 Dimensions: %dx%d", msg.Width, msg.Height)
+			}
+			if params := formatMediaParams(msg.Params); params != "" {
+				resultText += "\n\n⚙️  " + params
+			}
+			if msg.Warning != "" {
+				resultText += "\n\n⚠️ " + msg.Warning
 			}
 
 			// Update the last assistant message with the result
@@ -688,6 +970,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.streaming = false
 		m.status = m.status.SetStreaming(false)
+		cmds = append(cmds, m.notifyDone(mediaSummary(msg)))
 
 	case StreamChunkMsg:
 		m.chat = m.chat.AppendToLastAssistant(msg.Chunk.Content)
@@ -697,6 +980,10 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, nil) // Keep processing
 
 	case StreamDoneMsg:
+		if m.dropCanceled("reply") {
+			break
+		}
+
 		// Update token counts from API response
 		if msg.Usage != nil && m.contextTracker != nil {
 			m.contextTracker.UpdateTokens(
@@ -708,9 +995,15 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.header = m.header.SetContextUsage(m.contextTracker.CurrentTokens, m.contextTracker.MaxTokens)
 		}
 
+		// Record spend in the usage ledger
+		if msg.Usage != nil {
+			m.recordUsage(msg.Usage.PromptTokens, msg.Usage.CompletionTokens)
+		}
+		cmds = append(cmds, m.notifyDone("reply finished"))
+
 		if msg.FullContent != "" {
 			// Check for content policy refusal
-			if commands.IsContentPolicyRefusal(msg.FullContent) && m.endpoint != "venice" {
+			if commands.IsContentPolicyRefusal(msg.FullContent) && m.endpoint != "venice" && !m.safeMode {
 				// Detected refusal - offer to switch to Venice
 				m.chat = m.chat.AddSystemMessage(
 					"⚠️  Content policy refusal detected.\n\n" +
@@ -743,80 +1036,107 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.streaming = false
 			m.status = m.status.SetStreaming(false)
 			m.status = m.status.SetText(fmt.Sprintf("Done (%s)", msg.FinishReason))
+			m.discardPartial()
 		}
 
 	case StreamErrorMsg:
+		if m.dropCanceled("error") {
+			break
+		}
+		m.discardPartial()
 		m.streaming = false
 		m.status = m.status.SetStreaming(false)
 		m.status = m.status.SetText(fmt.Sprintf("Error: %v", msg.Err))
 		m.chat = m.chat.AddSystemMessage(fmt.Sprintf("Error: %v", msg.Err))
+		cmds = append(cmds, m.notifyDone("reply failed"))
+
+	case StreamCanceledMsg:
+		m.dropCanceled("cancellation")
 
 	case SkillCallMsg:
-		// Log the skill call for debugging
-		LogSkillCall(msg.Call.Name, msg.Call.Arguments)
-		LogInfo(fmt.Sprintf("Starting execution of skill: %s", msg.Call.Name))
-		m.skills = m.skills.SetExecuting(msg.Call.Name)
-		m.chat = m.chat.AddFunctionCall(msg.Call)
-		m.status = m.status.SetText(fmt.Sprintf("⚡ Executing: %s", msg.Call.Name))
+		if m.dropCanceled("skill calls") {
+			break
+		}
 
-		// Store tool call ID for sending result back to LLM
-		m.pendingToolCallID = msg.ToolCallID
+		// The reply is only text before the calls; the calls are saved with their results
+		m.discardPartial()
+
+		// The reply is over; the skill tick shows progress until the results
+		// go back to the LLM
+		m.streaming = false
+		m.status = m.status.SetStreaming(false)
 
 		// Add assistant message with tool_calls to conversation (required by OpenAI API)
-		// The assistant message must precede the tool result message
-		// Convert ToolCallInfo to the format needed
+		// The assistant message must precede the tool result messages
 		m.chat = m.chat.AddAssistantMessageWithToolCalls(msg.AssistantContent, msg.ToolCalls)
 
-		// Execute the skill asynchronously
-		if m.llmClient != nil {
-			cmds = append(cmds, m.llmClient.ExecuteSkill(msg.Call.Name, msg.Call.Arguments, msg.ToolCallID))
+		// Every call in the assistant message needs its own result before
+		// the conversation goes back to the LLM
+		m.pendingToolCalls = msg.ToolCalls
+		m.pendingToolResults = make(map[string]string, len(msg.ToolCalls))
+
+		for _, call := range msg.Calls {
+			// Log the skill call for debugging
+			LogSkillCall(call.Name, call.Arguments)
+			LogInfo(fmt.Sprintf("Starting execution of skill: %s (ID: %s)", call.Name, call.ID))
+			m.skills = m.skills.SetExecuting(call.Name)
+			m.chat = m.chat.AddFunctionCall(call)
+
+			// Execute the skills asynchronously
+			if m.llmClient != nil {
+				cmds = append(cmds, m.llmClient.ExecuteSkill(call.Name, call.Arguments, call.ID))
+			}
+		}
+		now := time.Now()
+		m.chat = m.chat.TickFunctions(now)
+		m.status = m.status.SetText(runningSkillsStatus(m.chat.RunningFunctions(), now))
+		if !m.skillTicking {
+			m.skillTicking = true
+			cmds = append(cmds, skillTick())
 		}
 
+	case SkillTickMsg:
+		m.chat = m.chat.TickFunctions(msg.Time)
+		running := m.chat.RunningFunctions()
+		if len(running) == 0 || len(m.pendingToolCalls) == 0 {
+			m.skillTicking = false
+			break
+		}
+		m.status = m.status.SetText(runningSkillsStatus(running, msg.Time))
+		cmds = append(cmds, skillTick())
+
+	case SkillProgressMsg:
+		LogInfo(fmt.Sprintf("Skill progress (ID: %s): %s", msg.ToolCallID, msg.Status))
+		m.chat = m.chat.SetFunctionProgress(msg.ToolCallID, msg.Status)
+
 	case SkillResultMsg:
 		// Log the skill result
 		LogSkillResult(msg.Name, msg.Result, msg.Err)
+
+		// Only accept results for calls from the current assistant message
+		if !m.isPendingToolCall(msg.ToolCallID) {
+			LogInfo(fmt.Sprintf("Dropping result for unknown tool call %q (%s)", msg.ToolCallID, msg.Name))
+			break
+		}
+
+		result := msg.Result
 		if msg.Err != nil {
 			m.skills = m.skills.SetError(msg.Name, msg.Err)
-			m.chat = m.chat.UpdateFunctionResult(msg.Name, fmt.Sprintf("Error: %v", msg.Err))
+			m.chat = m.chat.UpdateFunctionResult(msg.ToolCallID, fmt.Sprintf("Error: %v", msg.Err))
 
 			// IMPORTANT: Send error result back to LLM so conversation can continue
 			// The LLM needs to receive a tool result message even for errors
-			if m.llmClient != nil && msg.ToolCallID != "" {
-				// Format error as JSON for LLM to interpret
-				// Escape quotes and newlines in error message
-				errorMsg := strings.ReplaceAll(msg.Err.Error(), `"`, `\"`)
-				errorMsg = strings.ReplaceAll(errorMsg, "\n", "\\n")
-				errorResult := fmt.Sprintf(`{"error": true, "message": "%s", "skill": "%s"}`, errorMsg, msg.Name)
-
-				// Add tool result as a "tool" message to chat (even for errors)
-				m.chat = m.chat.AddToolResult(msg.ToolCallID, msg.Name, errorResult)
-
-				// Send updated conversation back to LLM for interpretation
-				m.streaming = true
-				m.status = m.status.SetStreaming(true)
-				m.status = m.status.SetText(StreamingSpinner(0) + " " + ThinkingAnimation(0))
-
-				// In NSFW mode, don't send skills
-				var toolsToSend []SkillDefinition
-				if !m.nsfwMode {
-					toolsToSend = m.skills.GetDefinitions()
-				}
-				cmds = append(cmds, m.llmClient.SendMessage(m.chat.GetMessages(), toolsToSend))
-
-				// Start animation tick
-				cmds = append(cmds, tea.Tick(typingTickInterval*2, func(t time.Time) tea.Msg {
-					return TickMsg{Time: t}
-				}))
-
-				// Clear pending tool call ID
-				m.pendingToolCallID = ""
-			}
+			// Format error as JSON for LLM to interpret
+			// Escape quotes and newlines in error message
+			errorMsg := strings.ReplaceAll(msg.Err.Error(), `"`, `\"`)
+			errorMsg = strings.ReplaceAll(errorMsg, "\n", "\\n")
+			result = fmt.Sprintf(`{"error": true, "message": "%s", "skill": "%s"}`, errorMsg, msg.Name)
 		} else {
 			m.skills = m.skills.SetCompleted(msg.Name)
-			m.chat = m.chat.UpdateFunctionResult(msg.Name, msg.Result)
+			m.chat = m.chat.UpdateFunctionResult(msg.ToolCallID, msg.Result)
 
 			// Handle NSFW mode toggle
-			if msg.Name == "nsfw_mode" && strings.Contains(msg.Result, "enabled") {
+			if msg.Name == "nsfw_mode" && strings.Contains(msg.Result, "enabled") && !m.safeMode {
 				m.nsfwMode = true
 				m.header = m.header.SetNSFWMode(true)
 				m.persistSession()
@@ -825,33 +1145,39 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.header = m.header.SetNSFWMode(false)
 				m.persistSession()
 			}
+		}
+		m.pendingToolResults[msg.ToolCallID] = result
 
-			// For successful skill results, send result back to LLM for interpretation
-			// Add tool result message to conversation and send to LLM
-			if m.llmClient != nil && msg.ToolCallID != "" {
-				// Add tool result as a "tool" message to chat
-				m.chat = m.chat.AddToolResult(msg.ToolCallID, msg.Name, msg.Result)
-
-				// Send updated conversation back to LLM for interpretation
-				m.streaming = true
-				m.status = m.status.SetStreaming(true)
-				m.status = m.status.SetText(StreamingSpinner(0) + " " + ThinkingAnimation(0))
-
-				// In NSFW mode, don't send skills
-				var toolsToSend []SkillDefinition
-				if !m.nsfwMode {
-					toolsToSend = m.skills.GetDefinitions()
-				}
-				cmds = append(cmds, m.llmClient.SendMessage(m.chat.GetMessages(), toolsToSend))
+		if remaining := len(m.pendingToolCalls) - len(m.pendingToolResults); remaining > 0 {
+			m.status = m.status.SetText(fmt.Sprintf("⚡ Waiting for %d more skill(s)", remaining))
+			break
+		}
 
-				// Start animation tick
-				cmds = append(cmds, tea.Tick(typingTickInterval*2, func(t time.Time) tea.Msg {
-					return TickMsg{Time: t}
-				}))
+		// Every call has answered: add one tool message per call, in call order
+		for _, tc := range m.pendingToolCalls {
+			m.chat = m.chat.AddToolResult(tc.ID, tc.Name, m.pendingToolResults[tc.ID])
+		}
+		m.pendingToolCalls = nil
+		m.pendingToolResults = nil
+
+		// Send updated conversation back to LLM for interpretation
+		if m.llmClient != nil {
+			m.streaming = true
+			m.status = m.status.SetStreaming(true)
+			m.status = m.status.SetText(StreamingSpinner(0) + " " + ThinkingAnimation(0))
 
-				// Clear pending tool call ID
-				m.pendingToolCallID = ""
+			// In NSFW mode, don't send skills
+			var toolsToSend []SkillDefinition
+			if !m.nsfwMode {
+				toolsToSend = m.skills.GetDefinitions()
 			}
+			m.recordPartial()
+			cmds = append(cmds, m.llmClient.SendMessage(m.chat.GetMessages(), toolsToSend))
+
+			// Start animation tick
+			cmds = append(cmds, tea.Tick(animationInterval(), func(t time.Time) tea.Msg {
+				return TickMsg{Time: t}
+			}))
 		}
 
 	case ShowSelectorMsg:
@@ -954,42 +1280,41 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return TickMsg{Time: t}
 				}))
 			} else {
-				// Typing complete - show final content without corruption
-				m.chat = m.chat.SetLastAssistantContent(m.typingContent)
-
-				// Add assistant message to session for persistence
-				if m.currentSession != nil {
-					if configSession, ok := m.currentSession.(*config.Session); ok {
-						configSession.Messages = append(configSession.Messages, config.SessionMessage{
-							Role:      "assistant",
-							Content:   m.typingContent,
-							Timestamp: time.Now(),
-						})
-					}
-				}
-
-				m.typingContent = ""
-				m.typingPos = 0
-				m.streaming = false
-				m.status = m.status.SetStreaming(false)
-				m.status = m.status.SetText("Ready")
-
-				// Persist session now that the message is complete
-				m.persistSession()
+				m = m.finishTyping()
 			}
 		} else if m.streaming {
 			// Just streaming (waiting for response) - show animated status
 			m.status = m.status.SetText(StreamingSpinner(m.animFrame) + " " + ThinkingAnimation(m.animFrame))
-			cmds = append(cmds, tea.Tick(typingTickInterval*2, func(t time.Time) tea.Msg {
+			cmds = append(cmds, tea.Tick(animationInterval(), func(t time.Time) tea.Msg {
 				return TickMsg{Time: t}
 			}))
 		}
 
 	case NSFWToggleMsg:
+		if m.safeMode && msg.Enabled {
+			m.status = m.status.SetText("NSFW mode blocked (safe mode)")
+			break
+		}
 		m.nsfwMode = msg.Enabled
 		m.header = m.header.SetNSFWMode(msg.Enabled)
 		m.persistSession()
 
+	case SkillsReloadedMsg:
+		m = m.applySkillsReload(msg.Summary, msg.Err)
+
+	case NoticeMsg:
+		m.chat = m.chat.AddSystemMessage(msg.Text)
+
+	case ConversationSummaryMsg:
+		if msg.Err != nil {
+			m.chat = m.chat.AddSystemMessage(fmt.Sprintf("❌ Summary failed: %v", msg.Err))
+			m.status = m.status.SetText("Summary failed")
+			break
+		}
+		// A system message, so the recap is shown but never sent to the LLM
+		m.chat = m.chat.AddSystemMessage("📋 Conversation recap:\n\n" + msg.Summary)
+		m.status = m.status.SetText("Summary ready")
+
 	case ErrorMsg:
 		m.status = m.status.SetText(fmt.Sprintf("Error: %v", msg.Err))
 	}
@@ -1050,6 +1375,21 @@ func (m AppModel) SetLLMClient(client LLMClient) AppModel {
 	return m
 }
 
+// applySkillsReload refreshes the tool list after a skill reload and reports
+// the result in chat.
+func (m AppModel) applySkillsReload(summary string, err error) AppModel {
+	if err != nil {
+		m.chat = m.chat.AddSystemMessage(fmt.Sprintf("❌ Failed to reload skills: %v", err))
+		return m
+	}
+	if m.llmClient != nil {
+		m.skills = m.skills.SetSkills(m.llmClient.GetSkills())
+	}
+	m.chat = m.chat.AddSystemMessage(summary)
+	m.status = m.status.SetText(fmt.Sprintf("🔄 %d skills loaded", len(m.skills.GetDefinitions())))
+	return m
+}
+
 // SessionManager interface for session persistence (avoid circular import).
 // Uses interface{} for return types to avoid circular dependencies.
 type SessionManager interface {
@@ -1070,6 +1410,10 @@ type Session interface {
 	GetModel() string
 	SetNSFWMode(enabled bool)
 	GetNSFWMode() bool
+	SetPersona(name string)
+	GetPersona() string
+	SetSampling(sampling config.Sampling)
+	GetSampling() config.Sampling
 	SetName(name string)
 	ClearMessages()
 	GetMessagesRaw() interface{}     // Returns []SessionMessage
@@ -1126,13 +1470,121 @@ func (m AppModel) SetSessionManager(sm SessionManager, session Session) AppModel
 				}
 			}
 		}
-		m.nsfwMode = session.GetNSFWMode()
+		m.nsfwMode = session.GetNSFWMode() && !m.safeMode
 		m.header = m.header.SetNSFWMode(m.nsfwMode)
+		if persona := session.GetPersona(); persona != "" {
+			m = m.switchPersona(persona)
+		} else {
+			m = m.warnInvalidPersona("")
+		}
+		m = m.setSampling(session.GetSampling())
 	}
 
 	return m
 }
 
+// attachImage handles /image, /attach and dragged-in image paths.
+func (m AppModel) attachImage(args []string) (tea.Model, tea.Cmd) {
+	result := commands.HandleImageCommand(args, m.supportsVision(), m.model, m.imageMaxBytes())
+	if result.ShouldRender {
+		m.chat = m.chat.AddSystemMessage(result.Message)
+	}
+	if result.StateChange == nil || result.StateChange.AttachImage == nil {
+		return m, nil
+	}
+	attachment := *result.StateChange.AttachImage
+	m.pendingImages = append(m.pendingImages, attachment)
+	if attachment.Message != "" {
+		// Send the caption right away; the image rides along
+		return m, func() tea.Msg {
+			return SendMessageMsg{Content: attachment.Message}
+		}
+	}
+	m.status = m.status.SetText(fmt.Sprintf("🖼️ %d image(s) attached", len(m.pendingImages)))
+	return m, nil
+}
+
+// imageMaxBytes is the configured cap on attached local images, or 0 for
+// the default.
+func (m AppModel) imageMaxBytes() int64 {
+	if m.config == nil {
+		return 0
+	}
+	return int64(m.config.ImageMaxMB) * 1024 * 1024
+}
+
+// sessionImageSources returns the image references saved with a session
+// message. Pasted data: URLs are left out so session files stay small.
+func sessionImageSources(refs []ImageRef) []string {
+	var sources []string
+	for _, ref := range refs {
+		if !strings.HasPrefix(strings.ToLower(ref.Source), "data:") {
+			sources = append(sources, ref.Source)
+		}
+	}
+	return sources
+}
+
+// restoreImages reloads the images a saved message refers to. A file that
+// is gone keeps its chip, marked missing, and is not sent.
+func (m AppModel) restoreImages(sources []string) ([]string, []ImageRef) {
+	var images []string
+	var refs []ImageRef
+	for _, source := range sources {
+		attachment, err := commands.LoadImageAttachment(source, m.imageMaxBytes())
+		if err != nil {
+			LogInfo(fmt.Sprintf("Could not restore image %s: %v", source, err))
+			refs = append(refs, ImageRef{Source: source, Label: filepath.Base(source) + " (missing)"})
+			continue
+		}
+		images = append(images, attachment.URL)
+		refs = append(refs, ImageRef{Source: source, Label: attachment.Label()})
+	}
+	return images, refs
+}
+
+// switchPersona applies a persona to the LLM client and the status bar.
+// The persona is kept unchanged if the client rejects it.
+func (m AppModel) switchPersona(name string) AppModel {
+	if name == prompts.DefaultPersona {
+		name = ""
+	}
+	if switcher, ok := m.llmClient.(PersonaSwitcher); ok {
+		if err := switcher.SetPersona(name); err != nil {
+			m.chat = m.chat.AddSystemMessage(fmt.Sprintf("❌ Failed to switch persona: %v", err))
+			return m
+		}
+	}
+
+	m.persona = name
+	display := name
+	if display == "" {
+		display = prompts.DefaultPersona
+	}
+	m.status = m.status.SetPersona(display)
+	m.status = m.status.SetText(fmt.Sprintf("🎭 Persona: %s", display))
+	return m.warnInvalidPersona(name)
+}
+
+// setSampling applies session sampling overrides to the LLM client.
+func (m AppModel) setSampling(sampling config.Sampling) AppModel {
+	m.sampling = sampling
+	if setter, ok := m.llmClient.(SamplingSetter); ok {
+		setter.SetSampling(sampling)
+	}
+	return m
+}
+
+// warnInvalidPersona adds a chat warning when a persona's file is broken or
+// missing required fields, since it otherwise loads as a bland prompt.
+func (m AppModel) warnInvalidPersona(name string) AppModel {
+	if err := prompts.ValidatePersona(name); err != nil {
+		LogInfo(fmt.Sprintf("Persona warning: %v", err))
+		m.chat = m.chat.AddSystemMessage(fmt.Sprintf("⚠️ %v", err))
+	}
+	return m
+}
+
 // SetVersion sets the application version and build information.
 func (m AppModel) SetVersion(version, build string) AppModel {
 	m.version = version
@@ -1140,6 +1592,244 @@ func (m AppModel) SetVersion(version, build string) AppModel {
 	return m
 }
 
+// supportsVision reports whether the current model accepts image input.
+func (m AppModel) supportsVision() bool {
+	if checker, ok := m.llmClient.(VisionChecker); ok {
+		return checker.SupportsVision()
+	}
+	return false
+}
+
+// ledgerBaseURL returns the base URL requests are currently sent to.
+// NSFW mode talks to Venice, which is never local.
+func (m AppModel) ledgerBaseURL() string {
+	if m.config == nil || m.nsfwMode {
+		return ""
+	}
+	return m.config.BaseURL
+}
+
+// isPaidRequest reports whether a budget applies to the next request.
+func (m AppModel) isPaidRequest() bool {
+	if m.config == nil || m.config.MonthlyBudgetUSD <= 0 {
+		return false
+	}
+	return !config.IsLocalProvider(m.provider, m.ledgerBaseURL())
+}
+
+// monthlySpend returns this month's recorded spend and its budget level.
+func (m AppModel) monthlySpend() (float64, string) {
+	if m.config == nil {
+		return 0, config.BudgetOK
+	}
+	ledger, err := config.LoadUsageLedger()
+	if err != nil {
+		LogInfo(fmt.Sprintf("Failed to load usage ledger: %v", err))
+		return 0, config.BudgetOK
+	}
+	spent := ledger.SpendForMonth(time.Now().Format("2006-01"))
+	return spent, config.CheckBudget(spent, m.config.MonthlyBudgetUSD)
+}
+
+// recordUsage appends the finished request to the usage ledger and
+// refreshes the budget badge.
+func (m *AppModel) recordUsage(promptTokens, completionTokens int) {
+	entry := config.NewLedgerEntry(m.provider, m.ledgerBaseURL(), m.model, promptTokens, completionTokens)
+	if sess, ok := m.currentSession.(*config.Session); ok {
+		entry.SessionID = sess.ID
+	}
+	if err := config.RecordUsage(entry); err != nil {
+		LogInfo(fmt.Sprintf("Failed to record usage: %v", err))
+		return
+	}
+	if m.isPaidRequest() {
+		spent, level := m.monthlySpend()
+		m.status = m.status.SetBudget(level, config.FormatCost(spent))
+	}
+}
+
+// SetSafeMode enables safe mode, which blocks NSFW mode, auto-routing to
+// uncensored endpoints and Venice media generation for the whole session.
+func (m AppModel) SetSafeMode(enabled bool) AppModel {
+	m.safeMode = enabled
+	m.status = m.status.SetSafeMode(enabled)
+	if enabled {
+		m.nsfwMode = false
+		m.header = m.header.SetNSFWMode(false)
+	}
+	return m
+}
+
+// retryLastExchange drops the last reply and resends the conversation,
+// optionally at a different sampling temperature.
+func (m AppModel) retryLastExchange(args []string) (AppModel, tea.Cmd) {
+	var temperature *float32
+	if len(args) > 0 {
+		t, err := strconv.ParseFloat(args[0], 32)
+		if err != nil || t <= 0 || t > 2 {
+			m.chat = m.chat.AddSystemMessage("Usage: /retry [temperature]\n\nTemperature must be above 0 and at most 2, e.g. /retry 1.2")
+			return m, nil
+		}
+		v := float32(t)
+		temperature = &v
+	}
+
+	kept, user, ok := m.rewind("retry")
+	if !ok {
+		return m, nil
+	}
+
+	// A retry is a paid request like any other
+	if !m.budgetConfirmed && m.isPaidRequest() {
+		if spent, level := m.monthlySpend(); level == config.BudgetExceeded {
+			m.chat = m.chat.AddSystemMessage(BudgetExceededStyle.Render(fmt.Sprintf(
+				"💸 Monthly budget exceeded: %s of %s used.\n\nSend a new message to confirm further spending.",
+				config.FormatCost(spent), config.FormatCost(m.config.MonthlyBudgetUSD))))
+			return m, nil
+		}
+	}
+
+	m.chat = m.chat.SetMessages(append(kept, user))
+	m = m.afterRewind()
+
+	if m.llmClient == nil {
+		return m, nil
+	}
+
+	m = m.startGeneration()
+	m.streaming = true
+	m.status = m.status.SetStreaming(true)
+	m.status = m.status.SetText(StreamingSpinner(0) + " " + ThinkingAnimation(0))
+
+	// In NSFW mode, don't send skills
+	var toolsToSend []SkillDefinition
+	if !m.nsfwMode {
+		toolsToSend = m.skills.GetDefinitions()
+	}
+
+	m.recordPartial()
+	var send tea.Cmd
+	sender, canSetTemperature := m.llmClient.(TemperatureSender)
+	if temperature != nil && canSetTemperature {
+		LogInfo(fmt.Sprintf("Retrying last message at temperature %.2f", *temperature))
+		send = sender.SendMessageWithTemperature(m.chat.GetMessages(), toolsToSend, *temperature)
+	} else {
+		send = m.llmClient.SendMessage(m.chat.GetMessages(), toolsToSend)
+		if temperature != nil {
+			m.chat = m.chat.AddSystemMessage("⚠️ This client can't change the temperature; retrying with the default")
+		}
+	}
+
+	return m, tea.Batch(send, tea.Tick(animationInterval(), func(t time.Time) tea.Msg {
+		return TickMsg{Time: t}
+	}))
+}
+
+// editLastMessage drops the last exchange and puts the user message back in
+// the input box so it can be changed before resending.
+func (m AppModel) editLastMessage() AppModel {
+	kept, user, ok := m.rewind("edit")
+	if !ok {
+		return m
+	}
+
+	m.chat = m.chat.SetMessages(kept)
+	m = m.afterRewind()
+
+	// Images sent with the message ride along with the edited one
+	for i, img := range user.Images {
+		attachment := commands.ImageAttachment{Source: "previous message", URL: img}
+		if i < len(user.ImageRefs) {
+			attachment.Source = user.ImageRefs[i].Source
+		}
+		m.pendingImages = append(m.pendingImages, attachment)
+	}
+	m.input = m.input.SetValue(user.Content)
+	m.status = m.status.SetText("✏️ Editing last message - press Enter to resend")
+	return m
+}
+
+// rewind splits the history at the last user message for /retry and /edit,
+// posting a notice and returning false when that isn't possible.
+func (m *AppModel) rewind(command string) ([]ChatMessage, ChatMessage, bool) {
+	if m.streaming || len(m.pendingToolCalls) > 0 {
+		m.chat = m.chat.AddSystemMessage(fmt.Sprintf("⏳ Wait for the current reply to finish before using /%s", command))
+		return nil, ChatMessage{}, false
+	}
+
+	kept, user, err := rewindLastExchange(m.chat.GetMessages(), m.skills.GetDefinitions())
+	if err == nil {
+		if _, _, _, isMedia := venice.ParseMediaCommand(user.Content); isMedia {
+			err = errors.New("the last message generated media files; send a new message instead")
+		}
+	}
+	if err != nil {
+		m.chat = m.chat.AddSystemMessage(fmt.Sprintf("⚠️ Can't /%s: %v", command, err))
+		return nil, ChatMessage{}, false
+	}
+	return kept, user, true
+}
+
+// afterRewind saves the shortened history and re-estimates context usage,
+// since the API token counts covered the dropped messages.
+func (m AppModel) afterRewind() AppModel {
+	m.persistSession()
+	if m.contextTracker != nil {
+		m.contextTracker.UpdateFromEstimate()
+		m.header = m.header.SetContextUsage(m.contextTracker.CurrentTokens, m.contextTracker.MaxTokens)
+	}
+	return m
+}
+
+// isPendingToolCall reports whether id belongs to a call from the current
+// assistant message that has not been answered yet.
+func (m AppModel) isPendingToolCall(id string) bool {
+	if id == "" {
+		return false
+	}
+	if _, answered := m.pendingToolResults[id]; answered {
+		return false
+	}
+	for _, tc := range m.pendingToolCalls {
+		if tc.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// skillTick schedules the next update of running skills' elapsed time.
+func skillTick() tea.Cmd {
+	return tea.Tick(skillTickInterval, func(t time.Time) tea.Msg {
+		return SkillTickMsg{Time: t}
+	})
+}
+
+// runningSkillsStatus describes the running calls for the status bar, like
+// "⚡ Running get_weather (4s / 30s limit)".
+func runningSkillsStatus(running []FunctionCall, now time.Time) string {
+	if len(running) == 0 {
+		return "⚡ Finishing skills"
+	}
+	parts := make([]string, len(running))
+	for i, call := range running {
+		elapsed := time.Duration(0)
+		if now.After(call.Timestamp) {
+			elapsed = now.Sub(call.Timestamp)
+		}
+		parts[i] = fmt.Sprintf("%s (%s / %s limit)", call.Name, formatSeconds(elapsed), formatSeconds(SkillTimeout))
+	}
+	return "⚡ Running " + strings.Join(parts, ", ")
+}
+
+// SetInputHistory loads input history from earlier runs, so ↑ recalls it,
+// and sets where new entries are saved. A nil save keeps new entries for
+// this run only.
+func (m AppModel) SetInputHistory(history []string, save func(entry string)) AppModel {
+	m.input = m.input.SetHistory(history).SetHistorySaver(save)
+	return m
+}
+
 // SetConfig sets the configuration for accessing context limits and other settings.
 func (m AppModel) SetConfig(cfg *config.Config) AppModel {
 	m.config = cfg
@@ -1152,6 +1842,15 @@ func (m AppModel) WithMessages(messages []ChatMessage) AppModel {
 	for _, msg := range messages {
 		switch msg.Role {
 		case "user":
+			if len(msg.ImageRefs) > 0 {
+				sources := make([]string, len(msg.ImageRefs))
+				for i, ref := range msg.ImageRefs {
+					sources[i] = ref.Source
+				}
+				images, refs := m.restoreImages(sources)
+				m.chat = m.chat.AddUserMessageWithImages(msg.Content, images, refs)
+				continue
+			}
 			m.chat = m.chat.AddUserMessage(msg.Content)
 		case "assistant":
 			m.chat = m.chat.AddAssistantMessage(msg.Content)
@@ -1195,8 +1894,66 @@ func (m AppModel) WithEndpoint(endpoint string) AppModel {
 	return m
 }
 
+// summarizeConversation handles /summarize [style], asking the client for
+// a recap of the conversation so far.
+func (m AppModel) summarizeConversation(args []string) (tea.Model, tea.Cmd) {
+	summarizer, ok := m.llmClient.(ConversationSummarizer)
+	if !ok {
+		m.chat = m.chat.AddSystemMessage("⚠️ Summaries are not supported by this client")
+		return m, nil
+	}
+	style := "bullets"
+	if len(args) > 0 {
+		style = strings.ToLower(args[0])
+	}
+
+	var messages []ChatMessage
+	for _, msg := range m.chat.GetMessages() {
+		if msg.Role != "system" {
+			messages = append(messages, msg)
+		}
+	}
+	if len(messages) == 0 {
+		m.chat = m.chat.AddSystemMessage("Nothing to summarize yet")
+		return m, nil
+	}
+
+	m.status = m.status.SetText("Summarizing conversation...")
+	return m, summarizer.SummarizeConversation(messages, style)
+}
+
+// searchChat handles /search: it highlights the query in the conversation,
+// jumps to the most recent match and lets n/N step through the rest.
+func (m AppModel) searchChat(args []string) AppModel {
+	if len(args) == 0 {
+		m.chat = m.chat.AddSystemMessage("Usage: /search <text>\nThen n/N jump between matches and Esc clears them.")
+		return m
+	}
+	query := strings.Join(args, " ")
+	var found int
+	if m.chat, found = m.chat.Search(query); found == 0 {
+		m.chat = m.chat.AddSystemMessage(fmt.Sprintf("🔍 No messages match %q", query))
+		return m
+	}
+	m.searchKeys = true
+	return m.showSearchPosition()
+}
+
+// showSearchPosition shows which /search match is selected in the status bar.
+func (m AppModel) showSearchPosition() AppModel {
+	current, total := m.chat.SearchPosition()
+	m.status = m.status.SetText(fmt.Sprintf("🔍 Match %d/%d • n/N next/previous • Esc clear", current, total))
+	return m
+}
+
 // persistSession saves the current session state.
 func (m *AppModel) persistSession() {
+	m.persistSessionThen(nil)
+}
+
+// persistSessionThen saves the current session state and calls after once
+// the save has succeeded.
+func (m *AppModel) persistSessionThen(after func()) {
 	if m.sessionManager == nil || m.currentSession == nil {
 		return
 	}
@@ -1204,6 +1961,8 @@ func (m *AppModel) persistSession() {
 	m.currentSession.SetEndpoint(m.endpoint)
 	m.currentSession.SetModel(m.model)
 	m.currentSession.SetNSFWMode(m.nsfwMode)
+	m.currentSession.SetPersona(m.persona)
+	m.currentSession.SetSampling(m.sampling)
 
 	// Convert TUI ChatMessages to config SessionMessages
 	chatMsgs := m.chat.GetMessages()
@@ -1221,12 +1980,81 @@ func (m *AppModel) persistSession() {
 	}
 	m.currentSession.SetMessagesRaw(sessionMsgs)
 
-	// Save asynchronously (ignore errors for now)
+	if saver, ok := m.sessionManager.(SessionAutosaver); ok {
+		saver.Autosave(m.currentSession, after)
+		return
+	}
+
+	// Save a copy asynchronously, since the session keeps changing here
+	// (ignore errors for now)
+	session, manager := m.currentSession, m.sessionManager
+	if s, ok := session.(*config.Session); ok {
+		session = s.Snapshot()
+	}
 	go func() {
-		_ = m.sessionManager.Save(m.currentSession)
+		if err := manager.Save(session); err == nil && after != nil {
+			after()
+		}
 	}()
 }
 
+// recordPartial has the client write the next streamed reply to a sidecar
+// next to the current session, so it can be recovered after a crash.
+func (m *AppModel) recordPartial() {
+	m.discardPartial()
+	recorder, ok := m.llmClient.(PartialRecorder)
+	if !ok {
+		return
+	}
+	store, ok := m.sessionManager.(PartialStore)
+	if !ok {
+		return
+	}
+	if session, ok := m.currentSession.(*config.Session); ok {
+		m.partial = store.NewPartialWriter(session.ID)
+		recorder.RecordPartial(m.partial)
+	}
+}
+
+// finishTyping shows the whole reply being typed out and saves it to the
+// session.
+func (m AppModel) finishTyping() AppModel {
+	// Show final content without corruption
+	m.chat = m.chat.SetLastAssistantContent(m.typingContent)
+
+	// Add assistant message to session for persistence
+	if m.currentSession != nil {
+		if configSession, ok := m.currentSession.(*config.Session); ok {
+			configSession.Messages = append(configSession.Messages, config.SessionMessage{
+				Role:      "assistant",
+				Content:   m.typingContent,
+				Timestamp: time.Now(),
+			})
+		}
+	}
+
+	m.typingContent = ""
+	m.typingPos = 0
+	m.streaming = false
+	m.status = m.status.SetStreaming(false)
+	m.status = m.status.SetText("Ready")
+
+	// Persist session now that the message is complete, then
+	// drop the sidecar so the reply is always on disk somewhere
+	partial := m.partial
+	m.partial = nil
+	m.persistSessionThen(func() { _ = partial.Discard() })
+	return m
+}
+
+// discardPartial removes the sidecar once the reply is saved or abandoned.
+func (m *AppModel) discardPartial() {
+	if err := m.partial.Discard(); err != nil {
+		LogInfo(fmt.Sprintf("Failed to remove partial reply: %v", err))
+	}
+	m.partial = nil
+}
+
 // handleSessionAction handles session management actions.
 func (m AppModel) handleSessionAction(action *commands.SessionAction) AppModel {
 	if m.sessionManager == nil {
@@ -1308,8 +2136,10 @@ func (m AppModel) handleSessionAction(action *commands.SessionAction) AppModel {
 					m.endpoint = endpoint
 					m.header = m.header.SetEndpoint(m.endpoint)
 				}
-				m.nsfwMode = s.GetNSFWMode()
+				m.nsfwMode = s.GetNSFWMode() && !m.safeMode
 				m.header = m.header.SetNSFWMode(m.nsfwMode)
+				m = m.switchPersona(s.GetPersona())
+				m = m.setSampling(s.GetSampling())
 
 				msgCount := 0
 				if msgs := s.GetMessagesRaw(); msgs != nil {
@@ -1397,8 +2227,8 @@ func (m AppModel) handleSessionAction(action *commands.SessionAction) AppModel {
 						if e, ok := summary.Metadata["endpoint"].(string); ok {
 							endpoint = e
 						}
-						if m, ok := summary.Metadata["model"].(string); ok {
-							model = m
+						if name, ok := summary.Metadata["model"].(string); ok {
+							model = name
 						}
 					}
 
@@ -1700,6 +2530,10 @@ type StatusModel struct {
 	warningMessage string // Context warning message
 	warningLevel   string // "warn", "caution", "critical"
 	showWarning    bool   // Whether to show warning
+	safeMode       bool   // Whether to show the safe mode badge
+	persona        string // Active persona name shown in the bar
+	budgetLevel    string // config.BudgetOK, BudgetWarn or BudgetExceeded
+	budgetSpent    string // Formatted monthly spend shown with the budget badge
 }
 
 // NewStatusModel creates a new status model.
@@ -1725,6 +2559,25 @@ func (m StatusModel) SetStreaming(streaming bool) StatusModel {
 	return m
 }
 
+// SetSafeMode toggles the safe mode badge.
+func (m StatusModel) SetSafeMode(enabled bool) StatusModel {
+	m.safeMode = enabled
+	return m
+}
+
+// SetPersona sets the active persona shown in the status bar.
+func (m StatusModel) SetPersona(name string) StatusModel {
+	m.persona = name
+	return m
+}
+
+// SetBudget sets the monthly budget badge. Nothing is shown at BudgetOK.
+func (m StatusModel) SetBudget(level string, spent string) StatusModel {
+	m.budgetLevel = level
+	m.budgetSpent = spent
+	return m
+}
+
 // ShowContextWarning displays a context warning message.
 func (m StatusModel) ShowContextWarning(level string, message string) StatusModel {
 	m.warningLevel = level
@@ -1767,6 +2620,21 @@ func (m StatusModel) View() string {
 		status = StatusActiveStyle.Render("●") + " " + m.text
 	}
 
+	if m.persona != "" {
+		status = StatusActiveStyle.Render("🎭 "+m.persona) + " • " + status
+	}
+
+	switch m.budgetLevel {
+	case config.BudgetWarn:
+		status = BudgetWarnStyle.Render("💸 "+m.budgetSpent+" (near budget)") + " • " + status
+	case config.BudgetExceeded:
+		status = BudgetExceededStyle.Render("💸 "+m.budgetSpent+" (over budget)") + " • " + status
+	}
+
+	if m.safeMode {
+		status = StatusActiveStyle.Render("🛡️ SAFE MODE") + " • " + status
+	}
+
 	return StatusBarStyle.Width(m.width).Render(status)
 }
 
@@ -1786,6 +2654,24 @@ func (m StatusModel) getWarningStyle() lipgloss.Style {
 
 // --- Helper functions ---
 
+// formatMediaParams renders effective generation parameters as sorted key=value pairs.
+func formatMediaParams(params map[string]interface{}) string {
+	if len(params) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, params[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
 // humanizeTime converts a timestamp to a human-readable relative time.
 func humanizeTime(t time.Time) string {
 	duration := time.Since(t)
@@ -0,0 +1,61 @@
+// Package tui provides the Bubble Tea-based terminal UI for Celeste CLI.
+// This file contains canceling a reply with Ctrl+C or Esc.
+package tui
+
+import "fmt"
+
+// busy reports whether a reply is being generated, typed out or waiting on
+// skills, so Ctrl+C and Esc cancel it instead of exiting or editing.
+func (m AppModel) busy() bool {
+	return m.streaming || len(m.pendingToolCalls) > 0
+}
+
+// cancelResponse stops the reply in progress and returns to the prompt. A
+// request still in flight is canceled when the client supports it, and
+// whatever it sends back is dropped. A reply already received is shown in
+// full rather than typed out.
+func (m AppModel) cancelResponse() AppModel {
+	if m.typingContent != "" {
+		return m.finishTyping()
+	}
+
+	if len(m.pendingToolCalls) > 0 {
+		// Every call still needs a result or the provider rejects the
+		// conversation; skills that finish later are dropped
+		for _, tc := range m.pendingToolCalls {
+			result, ok := m.pendingToolResults[tc.ID]
+			if !ok {
+				result = fmt.Sprintf(`{"error": true, "message": "cancelled by the user", "skill": "%s"}`, tc.Name)
+				m.chat = m.chat.UpdateFunctionResult(tc.ID, "Cancelled")
+			}
+			m.chat = m.chat.AddToolResult(tc.ID, tc.Name, result)
+		}
+		m.pendingToolCalls = nil
+		m.pendingToolResults = nil
+		m.skills = m.skills.ClearStatus()
+	} else {
+		if canceler, ok := m.llmClient.(RequestCanceler); ok {
+			canceler.CancelRequest()
+		}
+		m.canceled = true
+		m.discardPartial()
+	}
+
+	LogInfo("Reply cancelled by the user")
+	m.streaming = false
+	m.status = m.status.SetStreaming(false)
+	m.status = m.status.SetText("Cancelled (Ctrl+C again to exit)")
+	m.chat = m.chat.AddSystemMessage("⏹️ Reply cancelled.")
+	return m
+}
+
+// dropCanceled reports whether a reply that arrived belongs to a canceled
+// request, and clears the flag so the next one is kept.
+func (m *AppModel) dropCanceled(kind string) bool {
+	if !m.canceled {
+		return false
+	}
+	m.canceled = false
+	LogInfo(fmt.Sprintf("Dropping %s from a cancelled request", kind))
+	return true
+}
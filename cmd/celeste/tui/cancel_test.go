@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cancelingClient counts CancelRequest calls.
+type cancelingClient struct {
+	fakeLLMClient
+	cancels int
+}
+
+func (c *cancelingClient) CancelRequest() { c.cancels++ }
+
+func update(t *testing.T, app AppModel, msg tea.Msg) (AppModel, tea.Cmd) {
+	t.Helper()
+	model, cmd := app.Update(msg)
+	return model.(AppModel), cmd
+}
+
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+// TestCtrlCCancelsThenExits tests that the first Ctrl+C cancels the request
+// in flight and drops its late reply, and the second exits
+func TestCtrlCCancelsThenExits(t *testing.T) {
+	client := &cancelingClient{}
+	app := NewApp(client)
+
+	app, _ = update(t, app, SendMessageMsg{Content: "write a very long poem"})
+	require.True(t, app.streaming)
+
+	app, cmd := update(t, app, tea.KeyMsg{Type: tea.KeyCtrlC})
+	assert.False(t, isQuit(cmd))
+	assert.Equal(t, 1, client.cancels)
+	assert.False(t, app.streaming)
+
+	app, _ = update(t, app, StreamDoneMsg{FullContent: "Roses are red", FinishReason: "stop"})
+	for _, msg := range app.chat.GetMessages() {
+		assert.NotEqual(t, "assistant", msg.Role, "the canceled reply is dropped")
+	}
+
+	// A later reply is kept
+	app, _ = update(t, app, SendMessageMsg{Content: "just a haiku"})
+	app, _ = update(t, app, StreamDoneMsg{FullContent: "An old silent pond", FinishReason: "stop"})
+	assert.Equal(t, "An old silent pond", app.typingContent)
+
+	app = app.finishTyping()
+	_, cmd = update(t, app, tea.KeyMsg{Type: tea.KeyCtrlC})
+	assert.True(t, isQuit(cmd))
+}
+
+// TestEscCancelsPendingSkills tests that canceling while skills run answers
+// every call, so the conversation stays valid, and drops later results
+func TestEscCancelsPendingSkills(t *testing.T) {
+	client := &cancelingClient{}
+	app := NewApp(client)
+	app, _ = update(t, app, SendMessageMsg{Content: "weather and time?"})
+
+	calls := []ToolCallInfo{{ID: "call_1", Name: "get_weather"}, {ID: "call_2", Name: "get_time"}}
+	app, _ = update(t, app, SkillCallMsg{
+		Calls:     []FunctionCall{{ID: "call_1", Name: "get_weather"}, {ID: "call_2", Name: "get_time"}},
+		ToolCalls: calls,
+	})
+	app, _ = update(t, app, SkillResultMsg{Name: "get_weather", Result: `{"temp":70}`, ToolCallID: "call_1"})
+
+	app, cmd := update(t, app, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, isQuit(cmd))
+	assert.Zero(t, client.cancels, "no request is in flight while skills run")
+	assert.False(t, app.busy())
+
+	var results []ChatMessage
+	for _, msg := range app.chat.GetMessages() {
+		if msg.Role == "tool" {
+			results = append(results, msg)
+		}
+	}
+	require.Len(t, results, 2)
+	assert.Equal(t, `{"temp":70}`, results[0].Content)
+	assert.Contains(t, results[1].Content, "cancelled by the user")
+
+	sent := len(client.sent)
+	app, _ = update(t, app, SkillResultMsg{Name: "get_time", Result: "12:00", ToolCallID: "call_2"})
+	assert.Len(t, client.sent, sent, "a late result is not sent on")
+	assert.False(t, app.streaming)
+}
@@ -22,6 +22,15 @@ type ChatModel struct {
 	ready          bool
 	userScrolled   bool // Track if user has scrolled manually
 	showSkillCalls bool // Toggle to show/hide skill call logs
+
+	// /search state: matches of search are highlighted and searchHits holds
+	// the indices of matching messages, with searchPos the one jumped to
+	search       string
+	searchHits   []int
+	searchPos    int
+	messageLines []int // Viewport line each message starts on (-1 if hidden)
+
+	now time.Time // Last skill tick, for the elapsed time of running skills
 }
 
 // NewChatModel creates a new chat model.
@@ -113,9 +122,18 @@ func (m ChatModel) View() string {
 
 // AddUserMessage adds a user message to the chat.
 func (m ChatModel) AddUserMessage(content string) ChatModel {
+	return m.AddUserMessageWithImages(content, nil, nil)
+}
+
+// AddUserMessageWithImages adds a user message with attached images
+// (http(s) or data: URLs) to the chat. refs describe the images for the
+// chat view and sessions.
+func (m ChatModel) AddUserMessageWithImages(content string, images []string, refs []ImageRef) ChatModel {
 	m.messages = append(m.messages, ChatMessage{
 		Role:      "user",
 		Content:   content,
+		Images:    images,
+		ImageRefs: refs,
 		Timestamp: time.Now(),
 	})
 	m.updateContent()
@@ -208,12 +226,16 @@ func (m ChatModel) AddFunctionCall(call FunctionCall) ChatModel {
 	return m
 }
 
-// UpdateFunctionResult updates the result of a function call.
-func (m ChatModel) UpdateFunctionResult(name, result string) ChatModel {
+// UpdateFunctionResult updates the result of the function call with the given ID.
+// A call that already timed out keeps its timed-out status.
+func (m ChatModel) UpdateFunctionResult(id, result string) ChatModel {
 	for i := len(m.functionCalls) - 1; i >= 0; i-- {
-		if m.functionCalls[i].Name == name && m.functionCalls[i].Status == "executing" {
-			m.functionCalls[i].Result = result
-			m.functionCalls[i].Status = "completed"
+		call := &m.functionCalls[i]
+		if call.ID == id && (call.Status == "executing" || call.Status == "timeout") {
+			call.Result = result
+			if call.Status == "executing" {
+				call.Status = "completed"
+			}
 			break
 		}
 	}
@@ -221,6 +243,51 @@ func (m ChatModel) UpdateFunctionResult(name, result string) ChatModel {
 	return m
 }
 
+// SetFunctionProgress records the progress a running function call reported.
+func (m ChatModel) SetFunctionProgress(id, progress string) ChatModel {
+	for i := len(m.functionCalls) - 1; i >= 0; i-- {
+		if m.functionCalls[i].ID == id && m.functionCalls[i].Status == "executing" {
+			m.functionCalls[i].Progress = progress
+			break
+		}
+	}
+	m.updateContent()
+	return m
+}
+
+// TickFunctions updates the elapsed time of running function calls to now,
+// marking those that have run for SkillTimeout as timed out.
+func (m ChatModel) TickFunctions(now time.Time) ChatModel {
+	m.now = now
+	for i := range m.functionCalls {
+		call := &m.functionCalls[i]
+		if call.Status == "executing" && now.Sub(call.Timestamp) >= SkillTimeout {
+			call.Status = "timeout"
+		}
+	}
+	m.updateContent()
+	return m
+}
+
+// RunningFunctions returns the function calls still executing.
+func (m ChatModel) RunningFunctions() []FunctionCall {
+	var running []FunctionCall
+	for _, call := range m.functionCalls {
+		if call.Status == "executing" {
+			running = append(running, call)
+		}
+	}
+	return running
+}
+
+// elapsed returns how long call has been running as of the last tick.
+func (m ChatModel) elapsed(call FunctionCall) time.Duration {
+	if m.now.Before(call.Timestamp) {
+		return 0
+	}
+	return m.now.Sub(call.Timestamp)
+}
+
 // GetMessages returns all chat messages.
 func (m ChatModel) GetMessages() []ChatMessage {
 	return m.messages
@@ -230,10 +297,20 @@ func (m ChatModel) GetMessages() []ChatMessage {
 func (m ChatModel) Clear() ChatModel {
 	m.messages = []ChatMessage{}
 	m.functionCalls = []FunctionCall{}
+	m.search, m.searchHits = "", nil
 	m.updateContent()
 	return m
 }
 
+// SetMessages replaces the conversation history.
+func (m ChatModel) SetMessages(messages []ChatMessage) ChatModel {
+	m.messages = messages
+	m.search, m.searchHits = "", nil
+	m.updateContent()
+	m.viewport.GotoBottom()
+	return m
+}
+
 // ToggleSkillCalls toggles the visibility of skill call logs.
 func (m ChatModel) ToggleSkillCalls() ChatModel {
 	m.showSkillCalls = !m.showSkillCalls
@@ -251,18 +328,28 @@ func (m *ChatModel) updateContent() {
 	contentWidth := m.width - 4 // Account for padding and some margin
 
 	// Render messages (skip tool results - only LLM needs to see them)
-	for _, msg := range m.messages {
+	m.messageLines = make([]int, len(m.messages))
+	lineCount := 0
+	for i, msg := range m.messages {
+		m.messageLines[i] = -1
 		// Don't render tool results in UI - they're for LLM only
 		if msg.Role == "tool" {
 			continue
 		}
-		lines = append(lines, m.renderMessage(msg, contentWidth))
+		rendered := m.renderMessage(msg, contentWidth)
+		if m.isCurrentSearchHit(i) {
+			rendered = SearchMatchStyle.Render("▶") + " " + rendered
+		}
+		m.messageLines[i] = lineCount
+		lineCount += strings.Count(rendered, "\n") + 2
+		lines = append(lines, rendered)
 		lines = append(lines, "") // Spacing between messages
 	}
 
-	// Render function calls (only if showSkillCalls is true)
-	if m.showSkillCalls {
-		for _, call := range m.functionCalls {
+	// Render function calls: all of them if showSkillCalls is true,
+	// otherwise only those still running
+	for _, call := range m.functionCalls {
+		if m.showSkillCalls || call.Status == "executing" {
 			lines = append(lines, m.renderFunctionCall(call, contentWidth))
 		}
 	}
@@ -307,10 +394,30 @@ func (m ChatModel) renderMessage(msg ChatMessage, width int) string {
 		wrappedContent = wrapText(msg.Content, width-2)
 	}
 	styledContent := contentStyle.Render(wrappedContent)
+	if m.search != "" && msg.Role != "system" {
+		styledContent = highlightMatches(wrappedContent, m.search, contentStyle)
+	}
+
+	if chips := imageChips(msg); chips != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, header, styledContent, TimestampStyle.Render(chips))
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, styledContent)
 }
 
+// imageChips shows a message's images as [image: name, size] in place of
+// their data.
+func imageChips(msg ChatMessage) string {
+	var chips []string
+	for _, ref := range msg.ImageRefs {
+		chips = append(chips, fmt.Sprintf("[image: %s]", ref.Label))
+	}
+	for i := len(msg.ImageRefs); i < len(msg.Images); i++ {
+		chips = append(chips, "[image]")
+	}
+	return strings.Join(chips, " ")
+}
+
 // renderFunctionCall renders a function call display.
 func (m ChatModel) renderFunctionCall(call FunctionCall, width int) string {
 	// Status indicator
@@ -322,6 +429,8 @@ func (m ChatModel) renderFunctionCall(call FunctionCall, width int) string {
 		statusIndicator = SkillCompletedStyle.Render("✓")
 	case "error":
 		statusIndicator = SkillErrorStyle.Render("✗")
+	case "timeout":
+		statusIndicator = SkillTimedOutStyle.Render("⌛")
 	default:
 		statusIndicator = SkillNameStyle.Render("●")
 	}
@@ -347,6 +456,16 @@ func (m ChatModel) renderFunctionCall(call FunctionCall, width int) string {
 	}
 
 	header := fmt.Sprintf("%s %s %s", statusIndicator, name, args)
+	switch call.Status {
+	case "executing":
+		running := formatSeconds(m.elapsed(call))
+		if call.Progress != "" {
+			running += " · " + call.Progress
+		}
+		header += " " + SkillExecutingStyle.Render(running)
+	case "timeout":
+		header += " " + SkillTimedOutStyle.Render("timed out after "+formatSeconds(SkillTimeout))
+	}
 	content := header
 	if result != "" {
 		content = lipgloss.JoinVertical(lipgloss.Left, header, result)
@@ -355,6 +474,11 @@ func (m ChatModel) renderFunctionCall(call FunctionCall, width int) string {
 	return FunctionCallStyle.Width(width - 4).Render(content)
 }
 
+// formatSeconds formats d in whole seconds, like "4s".
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%ds", int(d/time.Second))
+}
+
 // wrapText wraps text to the specified width.
 func wrapText(text string, width int) string {
 	if width <= 0 {
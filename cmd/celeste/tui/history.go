@@ -0,0 +1,73 @@
+// Package tui provides the Bubble Tea-based terminal UI for Celeste CLI.
+// This file contains the history rewinding used by /retry and /edit.
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sideEffectSkills returns the names of the skills marked SideEffect, which
+// change something outside the conversation (reminders, notes, files,
+// uploads, posts). An exchange that ran one can't be retried or edited
+// without repeating or orphaning that change.
+func sideEffectSkills(skills []SkillDefinition) map[string]bool {
+	names := make(map[string]bool)
+	for _, skill := range skills {
+		if skill.SideEffect {
+			names[skill.Name] = true
+		}
+	}
+	return names
+}
+
+// errNothingToRewind is returned when there is no user message to go back to.
+var errNothingToRewind = errors.New("there is no message to retry or edit yet")
+
+// lastUserIndex returns the index of the most recent user message, or -1.
+func lastUserIndex(messages []ChatMessage) int {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return i
+		}
+	}
+	return -1
+}
+
+// sideEffectsAfter returns the skills in sideEffects called after index i,
+// in call order without duplicates.
+func sideEffectsAfter(messages []ChatMessage, i int, sideEffects map[string]bool) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, msg := range messages[i+1:] {
+		for _, call := range msg.ToolCalls {
+			if sideEffects[call.Name] && !seen[call.Name] {
+				seen[call.Name] = true
+				names = append(names, call.Name)
+			}
+		}
+	}
+	return names
+}
+
+// rewindLastExchange splits the history at the most recent user message.
+// It returns the messages before it and the user message itself; everything
+// after it (replies, tool calls and results, notices) is dropped. /retry
+// resends kept plus the user message, /edit puts the user message back in
+// the input box. It fails if the exchange called one of skills marked
+// SideEffect.
+func rewindLastExchange(messages []ChatMessage, skills []SkillDefinition) ([]ChatMessage, ChatMessage, error) {
+	i := lastUserIndex(messages)
+	if i < 0 {
+		return nil, ChatMessage{}, errNothingToRewind
+	}
+	if names := sideEffectsAfter(messages, i, sideEffectSkills(skills)); len(names) > 0 {
+		return nil, ChatMessage{}, fmt.Errorf("the last reply ran %s, which already changed things outside the chat; send a new message instead",
+			strings.Join(names, ", "))
+	}
+
+	kept := make([]ChatMessage, i)
+	copy(kept, messages[:i])
+	return kept, messages[i], nil
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLLMClient records what the app sends without calling a provider.
+type fakeLLMClient struct {
+	sent        [][]ChatMessage
+	temperature *float32
+}
+
+func (f *fakeLLMClient) SendMessage(messages []ChatMessage, tools []SkillDefinition) tea.Cmd {
+	f.sent = append(f.sent, append([]ChatMessage(nil), messages...))
+	return nil
+}
+
+func (f *fakeLLMClient) SendMessageWithTemperature(messages []ChatMessage, tools []SkillDefinition, temperature float32) tea.Cmd {
+	f.temperature = &temperature
+	return f.SendMessage(messages, tools)
+}
+
+func (f *fakeLLMClient) GetSkills() []SkillDefinition { return nil }
+
+func (f *fakeLLMClient) ExecuteSkill(name string, args map[string]any, toolCallID string) tea.Cmd {
+	return nil
+}
+
+func conversation() []ChatMessage {
+	return []ChatMessage{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+		{Role: "user", Content: "weather?"},
+		{Role: "assistant", ToolCalls: []ToolCallInfo{{ID: "call_1", Name: "get_weather"}}},
+		{Role: "tool", ToolCallID: "call_1", Name: "get_weather", Content: `{"temp":70}`},
+		{Role: "assistant", Content: "It's 70."},
+		{Role: "system", Content: "Done"},
+	}
+}
+
+// TestRewindLastExchange tests that everything after the last user message is dropped
+func TestRewindLastExchange(t *testing.T) {
+	messages := conversation()
+	kept, user, err := rewindLastExchange(messages, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "weather?", user.Content)
+	assert.Equal(t, messages[:2], kept)
+
+	// The original slice is left alone
+	assert.Len(t, messages, 7)
+}
+
+// TestRewindLastExchangeEmpty tests that there must be a user message to go back to
+func TestRewindLastExchangeEmpty(t *testing.T) {
+	_, _, err := rewindLastExchange([]ChatMessage{{Role: "system", Content: "Welcome"}}, nil)
+	assert.ErrorIs(t, err, errNothingToRewind)
+}
+
+// TestRewindLastExchangeSideEffects tests that exchanges which saved data can't be rewound
+func TestRewindLastExchangeSideEffects(t *testing.T) {
+	skills := []SkillDefinition{
+		{Name: "set_reminder", SideEffect: true},
+		{Name: "get_weather"},
+		{Name: "save_note", SideEffect: true},
+	}
+	messages := []ChatMessage{
+		{Role: "user", Content: "remind me and note it"},
+		{Role: "assistant", ToolCalls: []ToolCallInfo{
+			{ID: "a", Name: "set_reminder"},
+			{ID: "b", Name: "get_weather"},
+			{ID: "c", Name: "save_note"},
+		}},
+		{Role: "tool", ToolCallID: "a", Content: "ok"},
+		{Role: "tool", ToolCallID: "b", Content: "ok"},
+		{Role: "tool", ToolCallID: "c", Content: "ok"},
+	}
+	_, _, err := rewindLastExchange(messages, skills)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "set_reminder, save_note")
+	assert.NotContains(t, err.Error(), "get_weather")
+
+	// Side effects in earlier exchanges don't matter
+	messages = append(messages, ChatMessage{Role: "user", Content: "thanks"}, ChatMessage{Role: "assistant", Content: "np"})
+	_, user, err := rewindLastExchange(messages, skills)
+	require.NoError(t, err)
+	assert.Equal(t, "thanks", user.Content)
+}
+
+// TestRetryResendsWithoutLastReply tests /retry at the app level
+func TestRetryResendsWithoutLastReply(t *testing.T) {
+	client := &fakeLLMClient{}
+	app := NewApp(client)
+	app.chat = app.chat.SetMessages(conversation())
+
+	model, _ := app.Update(SendMessageMsg{Content: "/retry 1.3"})
+	app = model.(AppModel)
+
+	require.Len(t, client.sent, 1)
+	sent := client.sent[0]
+	require.Len(t, sent, 3)
+	assert.Equal(t, "weather?", sent[2].Content)
+	require.NotNil(t, client.temperature)
+	assert.InDelta(t, 1.3, *client.temperature, 0.001)
+	assert.True(t, app.streaming)
+
+	// A second retry while the first is in flight is refused
+	model, _ = app.Update(SendMessageMsg{Content: "/retry"})
+	assert.Len(t, client.sent, 1)
+	assert.Equal(t, "system", model.(AppModel).chat.GetMessages()[3].Role)
+}
+
+// TestEditReloadsLastMessage tests /edit at the app level
+func TestEditReloadsLastMessage(t *testing.T) {
+	client := &fakeLLMClient{}
+	app := NewApp(client)
+	app.chat = app.chat.SetMessages(conversation())
+
+	model, _ := app.Update(SendMessageMsg{Content: "/edit"})
+	app = model.(AppModel)
+
+	assert.Empty(t, client.sent)
+	assert.Equal(t, "weather?", app.input.Value())
+	assert.Equal(t, conversation()[:2], app.chat.GetMessages())
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+// fakeVisionClient is a client for a vision-capable model.
+type fakeVisionClient struct {
+	fakeLLMClient
+}
+
+func (f *fakeVisionClient) SupportsVision() bool { return true }
+
+// writeTestImage writes a minimal PNG and returns its path.
+func writeTestImage(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0644))
+	return path
+}
+
+// TestDraggedImageIsAttached tests that a pasted image path attaches the image
+// and the next message sends it with a chip instead of the data
+func TestDraggedImageIsAttached(t *testing.T) {
+	path := writeTestImage(t, "screen shot.png")
+	client := &fakeVisionClient{}
+	session := &config.Session{ID: "s1"}
+	app := NewApp(client).SetSessionManager(&fakeSessionManager{}, session)
+
+	model, _ := app.Update(SendMessageMsg{Content: "'" + path + "'"})
+	app = model.(AppModel)
+	require.Len(t, app.pendingImages, 1)
+	assert.Contains(t, lastMessage(app), "[image: screen shot.png, 16B]")
+
+	model, _ = app.Update(SendMessageMsg{Content: "what's on screen?"})
+	app = model.(AppModel)
+	require.Len(t, client.sent, 1)
+	sent := client.sent[0][len(client.sent[0])-1]
+	require.Len(t, sent.Images, 1)
+	assert.True(t, strings.HasPrefix(sent.Images[0], "data:image/png;base64,"))
+	assert.Contains(t, imageChips(sent), "[image: screen shot.png, 16B]")
+
+	// The session keeps the path, not the base64 data
+	require.NotEmpty(t, session.Messages)
+	assert.Equal(t, []string{path}, session.Messages[len(session.Messages)-1].Images)
+}
+
+// TestAttachRespectsSizeCap tests the image_max_mb setting
+func TestAttachRespectsSizeCap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.png")
+	data := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 2*1024*1024)...)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	app := NewApp(&fakeVisionClient{}).SetConfig(&config.Config{ImageMaxMB: 1})
+	model, _ := app.Update(SendMessageMsg{Content: "/attach " + path})
+	app = model.(AppModel)
+	assert.Empty(t, app.pendingImages)
+	assert.Contains(t, lastMessage(app), "limit is 1MB")
+}
+
+// TestAttachWithoutVision tests the local error for text-only models
+func TestAttachWithoutVision(t *testing.T) {
+	path := writeTestImage(t, "cat.png")
+	app := NewApp(&fakeLLMClient{})
+	model, _ := app.Update(SendMessageMsg{Content: "/attach " + path})
+	app = model.(AppModel)
+	assert.Empty(t, app.pendingImages)
+	assert.Contains(t, lastMessage(app), "doesn't accept images")
+}
+
+// TestRestoredImagesReload tests that saved image references are reloaded on resume
+func TestRestoredImagesReload(t *testing.T) {
+	path := writeTestImage(t, "cat.png")
+	gone := filepath.Join(t.TempDir(), "gone.png")
+
+	app := NewApp(&fakeVisionClient{}).WithMessages([]ChatMessage{
+		{Role: "user", Content: "look", ImageRefs: []ImageRef{{Source: path}, {Source: gone}}},
+	})
+	user := app.chat.GetMessages()[0]
+	require.Len(t, user.Images, 1)
+	assert.True(t, strings.HasPrefix(user.Images[0], "data:image/png;base64,"))
+	assert.Equal(t, "[image: cat.png, 16B] [image: gone.png (missing)]", imageChips(user))
+}
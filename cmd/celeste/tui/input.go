@@ -3,6 +3,8 @@
 package tui
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -14,6 +16,8 @@ type InputModel struct {
 	history      []string
 	historyIndex int
 	tempInput    string // Stores current input when browsing history
+
+	saveHistory func(entry string) // Persists each new history entry; nil keeps history per run
 }
 
 // NewInputModel creates a new input model.
@@ -57,8 +61,15 @@ func (m InputModel) Update(msg tea.Msg) (InputModel, tea.Cmd) {
 		case "enter":
 			value := m.textInput.Value()
 			if value != "" {
-				// Add to history
-				m.history = append(m.history, value)
+				// Add to history, skipping blank input and repeats
+				cmds := []tea.Cmd{SendMessage(value)}
+				if m.addHistory(value) && m.saveHistory != nil {
+					save := m.saveHistory
+					cmds = append(cmds, func() tea.Msg {
+						save(value)
+						return nil
+					})
+				}
 				m.historyIndex = len(m.history) // Reset index past end
 				m.tempInput = ""
 
@@ -66,7 +77,7 @@ func (m InputModel) Update(msg tea.Msg) (InputModel, tea.Cmd) {
 				m.textInput.Reset()
 
 				// Send message
-				return m, SendMessage(value)
+				return m, tea.Batch(cmds...)
 			}
 
 		case "up":
@@ -174,6 +185,26 @@ func (m InputModel) GetHistory() []string {
 	return m.history
 }
 
+// addHistory appends entry to the history unless it is blank or repeats the
+// last entry, reporting whether it was added.
+func (m *InputModel) addHistory(entry string) bool {
+	if strings.TrimSpace(entry) == "" {
+		return false
+	}
+	if n := len(m.history); n > 0 && m.history[n-1] == entry {
+		return false
+	}
+	m.history = append(m.history, entry)
+	return true
+}
+
+// SetHistorySaver sets the function each new history entry is passed to,
+// so history outlives the run. It is called off the update loop.
+func (m InputModel) SetHistorySaver(save func(entry string)) InputModel {
+	m.saveHistory = save
+	return m
+}
+
 // SetHistory sets the command history.
 func (m InputModel) SetHistory(history []string) InputModel {
 	m.history = history
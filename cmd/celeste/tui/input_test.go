@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// submit types value into the input and presses Enter, running the
+// commands it returns
+func submit(m InputModel, value string) InputModel {
+	m = m.SetValue(value)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if c != nil {
+				c()
+			}
+		}
+	}
+	return m
+}
+
+// TestInputHistoryPersists tests that history from earlier runs is recalled
+// with ↑ and that new entries are saved without blanks or repeats
+func TestInputHistoryPersists(t *testing.T) {
+	var saved []string
+	app := NewApp(&fakeLLMClient{}).SetInputHistory([]string{"/persona moderator", "hello"}, func(entry string) {
+		saved = append(saved, entry)
+	})
+
+	m := app.input
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	assert.Equal(t, "hello", m.Value())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	assert.Equal(t, "/persona moderator", m.Value())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, "", m.Value())
+
+	m = submit(m, "hello")
+	m = submit(m, "what's the weather?")
+	m = submit(m, "what's the weather?")
+	m = submit(m, "   ")
+	assert.Equal(t, []string{"what's the weather?"}, saved)
+	assert.Equal(t, []string{"/persona moderator", "hello", "what's the weather?"}, m.GetHistory())
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
 )
 
 var (
@@ -22,12 +24,7 @@ func InitLogging() error {
 	}
 
 	// Create log directory
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-
-	logDir := filepath.Join(homeDir, ".celeste", "logs")
+	logDir := paths.Data("logs")
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		return err
 	}
@@ -71,6 +68,16 @@ func LogSkillCall(name string, args map[string]any) {
 	fmt.Fprintf(logFile, "  Arguments: %v\n", args)
 }
 
+// LogSkillDefaults logs the skill_defaults values filled in for a call.
+func LogSkillDefaults(name string, defaults map[string]any) {
+	if logFile == nil || len(defaults) == 0 {
+		return
+	}
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	fmt.Fprintf(logFile, "[%s] SKILL_DEFAULTS: %s\n", timestamp, name)
+	fmt.Fprintf(logFile, "  Applied: %v\n", defaults)
+}
+
 // LogSkillResult logs the result of a skill execution.
 func LogSkillResult(name string, result string, err error) {
 	if logFile == nil {
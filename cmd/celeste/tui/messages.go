@@ -6,32 +6,27 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/whykusanagi/celesteCLI/internal/chat"
 )
 
 // ChatMessage represents a message in the conversation.
-type ChatMessage struct {
-	Role       string         // "user", "assistant", "system", "tool"
-	Content    string         // Message content
-	ToolCallID string         // For tool messages, the tool call ID
-	Name       string         // For tool messages, the function name
-	ToolCalls  []ToolCallInfo // For assistant messages, the tool calls that were made
-	Timestamp  time.Time      // When the message was created
-}
+type ChatMessage = chat.Message
+
+// ImageRef describes an attached image without its data.
+type ImageRef = chat.ImageRef
 
 // ToolCallInfo represents a tool call in an assistant message.
-type ToolCallInfo struct {
-	ID        string
-	Name      string
-	Arguments string
-}
+type ToolCallInfo = chat.ToolCall
 
 // FunctionCall represents a tool/function call from the LLM.
 type FunctionCall struct {
+	ID        string         // Tool call ID the result is sent back with
 	Name      string         // Function name
 	Arguments map[string]any // Arguments passed to the function
 	Result    string         // Result of the function call
-	Status    string         // "executing", "completed", "error"
+	Status    string         // "executing", "completed", "error", "timeout"
 	Timestamp time.Time      // When the call was initiated
+	Progress  string         // Latest progress the running skill reported
 }
 
 // StreamChunk represents a piece of streamed response.
@@ -68,12 +63,15 @@ type StreamErrorMsg struct {
 	Err error
 }
 
-// SkillCallMsg is sent when the LLM wants to call a skill/function.
+// StreamCanceledMsg is sent when a request ends because it was canceled
+// with Ctrl+C or Esc.
+type StreamCanceledMsg struct{}
+
+// SkillCallMsg is sent when the LLM wants to call one or more skills/functions.
 type SkillCallMsg struct {
-	Call             FunctionCall
-	ToolCallID       string         // OpenAI tool call ID for sending result back
+	Calls            []FunctionCall // Every call to execute, in the order the model made them
 	AssistantContent string         // The assistant message content (may be empty if only tool calls)
-	ToolCalls        []ToolCallInfo // All tool calls from the assistant message
+	ToolCalls        []ToolCallInfo // All tool calls from the assistant message, as sent by the model
 }
 
 // SkillResultMsg is sent when a skill execution completes.
@@ -81,7 +79,19 @@ type SkillResultMsg struct {
 	Name       string
 	Result     string
 	Err        error
-	ToolCallID string // OpenAI tool call ID for sending result back
+	ToolCallID string // ID of the tool call this answers; results for unknown IDs are dropped
+}
+
+// SkillProgressMsg is sent when a running skill reports what it is doing.
+type SkillProgressMsg struct {
+	ToolCallID string
+	Status     string // e.g. "fetching forecast"
+}
+
+// SkillTickMsg is sent every second while skills run, to update their
+// elapsed time.
+type SkillTickMsg struct {
+	Time time.Time
 }
 
 // SendMessageMsg is sent when the user submits a message.
@@ -120,6 +130,25 @@ type SessionLoadedMsg struct {
 	Messages []ChatMessage
 }
 
+// SkillsReloadedMsg is sent when skills were reloaded from disk by the
+// file watcher.
+type SkillsReloadedMsg struct {
+	Summary string
+	Err     error
+}
+
+// ConversationSummaryMsg carries the recap requested with /summarize.
+type ConversationSummaryMsg struct {
+	Summary string
+	Err     error
+}
+
+// NoticeMsg shows a notice in the chat from outside the TUI, such as a
+// warning from a background save.
+type NoticeMsg struct {
+	Text string
+}
+
 // ClearChatMsg is sent to clear the chat history.
 type ClearChatMsg struct{}
 
@@ -139,8 +168,14 @@ type MediaResultMsg struct {
 	Success   bool
 	URL       string
 	Path      string
+	Paths     []string // All saved files when multiple variants were generated
 	Error     string
 	MediaType string
+	Params    map[string]interface{} // Effective generation parameters (images)
+	Width     int                    // Output dimensions, when known
+	Height    int
+	Seed      *int   // Seed used for image generation, when known
+	Warning   string // Non-fatal problem to show with the result
 }
 
 // ShowSelectorMsg triggers the interactive selector.
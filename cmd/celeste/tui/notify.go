@@ -0,0 +1,56 @@
+// Package tui provides the Bubble Tea-based terminal UI for Celeste CLI.
+// This file announces long generations that finish while the window is in
+// the background.
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/whykusanagi/celesteCLI/internal/notify"
+)
+
+// SetNotifier sets the notifier for replies and media that finish while the
+// terminal reports it has lost focus. nil disables notifications.
+func (m AppModel) SetNotifier(n *notify.Notifier) AppModel {
+	m.notifier = n
+	return m
+}
+
+// startGeneration records when the user's request began, so a reply that
+// takes several skill rounds is timed as a whole.
+func (m AppModel) startGeneration() AppModel {
+	m.generationStart = time.Now()
+	return m
+}
+
+// notifyDone returns a command announcing a finished generation, or nil
+// when notifications are off or the window appears to be in use. Terminals
+// that don't report focus are treated as focused.
+func (m AppModel) notifyDone(summary string) tea.Cmd {
+	if m.notifier == nil || !m.blurred || m.generationStart.IsZero() {
+		return nil
+	}
+	notifier, elapsed := m.notifier, time.Since(m.generationStart)
+	return func() tea.Msg {
+		notifier.Done(summary, elapsed)
+		return nil
+	}
+}
+
+// mediaSummary describes a media result for a notification, e.g. "image
+// saved as nsfw_image_1.png".
+func mediaSummary(msg MediaResultMsg) string {
+	switch {
+	case !msg.Success:
+		return fmt.Sprintf("%s generation failed", msg.MediaType)
+	case len(msg.Paths) > 1:
+		return fmt.Sprintf("%d %s variants saved", len(msg.Paths), msg.MediaType)
+	case msg.Path != "":
+		return fmt.Sprintf("%s saved as %s", msg.MediaType, filepath.Base(msg.Path))
+	}
+	return fmt.Sprintf("%s ready", msg.MediaType)
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/notify"
+)
+
+// TestNotifyOnlyWhenBlurred tests that a finished reply is announced only
+// after the terminal reported losing focus
+func TestNotifyOnlyWhenBlurred(t *testing.T) {
+	var sent []string
+	notifier := &notify.Notifier{Threshold: time.Nanosecond, GOOS: "linux", Run: func(ctx context.Context, name string, args ...string) error {
+		sent = append(sent, name+" "+strings.Join(args, " "))
+		return nil
+	}}
+	app := NewApp(&fakeLLMClient{}).SetNotifier(notifier)
+
+	model, _ := app.Update(SendMessageMsg{Content: "write me a long story"})
+	app = model.(AppModel)
+	require.False(t, app.generationStart.IsZero())
+	assert.Nil(t, app.notifyDone("reply finished"), "a focused window is not notified")
+
+	model, _ = app.Update(tea.BlurMsg{})
+	app = model.(AppModel)
+	cmd := app.notifyDone("reply finished")
+	require.NotNil(t, cmd)
+	cmd()
+	require.Len(t, sent, 1)
+	assert.True(t, strings.HasPrefix(sent[0], "notify-send --app-name=Celeste -- Celeste reply finished, "), sent[0])
+
+	model, _ = app.Update(tea.FocusMsg{})
+	app = model.(AppModel)
+	assert.Nil(t, app.notifyDone("reply finished"))
+
+	assert.Nil(t, app.SetNotifier(nil).notifyDone("reply finished"))
+}
+
+func TestMediaSummary(t *testing.T) {
+	assert.Equal(t, "image saved as nsfw_image_1.png", mediaSummary(MediaResultMsg{Success: true, MediaType: "image", Path: "/tmp/out/nsfw_image_1.png"}))
+	assert.Equal(t, "4 image variants saved", mediaSummary(MediaResultMsg{Success: true, MediaType: "image", Paths: []string{"a", "b", "c", "d"}}))
+	assert.Equal(t, "image generation failed", mediaSummary(MediaResultMsg{MediaType: "image", Error: "boom"}))
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+// recordingClient hands the sidecar writer back to the test, which plays
+// the part of the stream.
+type recordingClient struct {
+	fakeLLMClient
+	partial *config.PartialWriter
+}
+
+func (c *recordingClient) RecordPartial(w *config.PartialWriter) { c.partial = w }
+
+// diskSessionManager saves sessions and sidecars under the test's home.
+type diskSessionManager struct {
+	fakeSessionManager
+	manager *config.SessionManager
+	saves   atomic.Int32 // Finished saves; the app saves in the background
+}
+
+func (d *diskSessionManager) Save(session interface{}) error {
+	defer d.saves.Add(1)
+	return d.manager.Save(session.(*config.Session))
+}
+
+func (d *diskSessionManager) NewPartialWriter(sessionID string) *config.PartialWriter {
+	return d.manager.NewPartialWriter(sessionID)
+}
+
+func newRecoverableApp(t *testing.T) (AppModel, *recordingClient, *diskSessionManager, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	manager := config.NewSessionManager()
+	session := manager.NewSession()
+	client := &recordingClient{}
+	store := &diskSessionManager{manager: manager}
+	app := NewApp(client).SetSessionManager(store, session)
+	sidecar := filepath.Join(home, ".celeste", "sessions", session.ID+".partial")
+	return app, client, store, sidecar
+}
+
+// TestRecoverInterruptedReply tests that a kill between chunks keeps the
+// user message and the reply streamed so far
+func TestRecoverInterruptedReply(t *testing.T) {
+	app, client, store, _ := newRecoverableApp(t)
+	session := app.currentSession.(*config.Session)
+
+	app.Update(SendMessageMsg{Content: "Tell me a story"})
+	require.NotNil(t, client.partial)
+	require.NoError(t, client.partial.Append("Once upon a time"))
+	require.NoError(t, client.partial.Append(", there was a cat")) // Not yet flushed
+	// The TUI is killed here, before the reply is saved
+
+	require.Eventually(t, func() bool { return store.saves.Load() == 1 }, time.Second, 10*time.Millisecond)
+	manager := config.NewSessionManager()
+	loaded, err := manager.Load(session.ID)
+	require.NoError(t, err)
+	require.Len(t, loaded.Messages, 1, "the user message is saved on send")
+
+	recovered, err := manager.RecoverPartial(loaded)
+	require.NoError(t, err)
+	assert.True(t, recovered)
+	require.Len(t, loaded.Messages, 2)
+	assert.Equal(t, "Tell me a story", loaded.Messages[0].Content)
+	assert.Equal(t, "Once upon a time"+config.InterruptedSuffix, loaded.Messages[1].Content)
+}
+
+// TestCompletedReplyRemovesPartial tests that the sidecar goes once the
+// reply is saved, and when the request fails
+func TestCompletedReplyRemovesPartial(t *testing.T) {
+	app, client, store, sidecar := newRecoverableApp(t)
+
+	model, _ := app.Update(SendMessageMsg{Content: "Hi"})
+	app = model.(AppModel)
+	require.NoError(t, client.partial.Append("Hello!"))
+	require.FileExists(t, sidecar)
+
+	model, _ = app.Update(StreamDoneMsg{FullContent: "Hello!", FinishReason: "stop"})
+	app = model.(AppModel)
+	for i := 0; app.streaming && i < 100; i++ {
+		model, _ = app.Update(TickMsg{Time: time.Now()})
+		app = model.(AppModel)
+	}
+	require.False(t, app.streaming)
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(sidecar)
+		return os.IsNotExist(err)
+	}, time.Second, 10*time.Millisecond)
+
+	model, _ = app.Update(SendMessageMsg{Content: "Again"})
+	app = model.(AppModel)
+	require.NoError(t, client.partial.Append("Hel"))
+	require.FileExists(t, sidecar)
+	app.Update(StreamErrorMsg{Err: os.ErrDeadlineExceeded})
+	assert.NoFileExists(t, sidecar)
+
+	// Let the background saves (two sends and the reply) finish before the
+	// temp home is removed
+	assert.Eventually(t, func() bool { return store.saves.Load() == 3 }, time.Second, 10*time.Millisecond)
+}
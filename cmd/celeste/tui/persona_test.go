@@ -0,0 +1,276 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+// fakePersonaClient records persona and endpoint switches.
+type fakePersonaClient struct {
+	fakeLLMClient
+	persona   string
+	switches  []string
+	endpoints []string
+}
+
+func (f *fakePersonaClient) SetPersona(name string) error {
+	if name == "broken" {
+		return errors.New("failed to parse persona")
+	}
+	f.persona = name
+	f.switches = append(f.switches, name)
+	return nil
+}
+
+func (f *fakePersonaClient) SwitchEndpoint(endpoint string) error {
+	f.endpoints = append(f.endpoints, endpoint)
+	return nil
+}
+
+func (f *fakePersonaClient) ChangeModel(model string) error { return nil }
+
+// fakeSessionManager hands out stored sessions without touching disk.
+type fakeSessionManager struct {
+	sessions map[string]*config.Session
+}
+
+func (f *fakeSessionManager) NewSession() interface{}        { return &config.Session{ID: "new"} }
+func (f *fakeSessionManager) Save(session interface{}) error { return nil }
+func (f *fakeSessionManager) List() ([]interface{}, error)   { return nil, nil }
+func (f *fakeSessionManager) Delete(id string) error         { return nil }
+func (f *fakeSessionManager) MergeSessions(s1, s2 interface{}) interface{} {
+	return s1
+}
+
+func (f *fakeSessionManager) Load(id string) (interface{}, error) {
+	if s, ok := f.sessions[id]; ok {
+		return s, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// withPersonas points the persona directory at a temp home with the given personas.
+func withPersonas(t *testing.T, names ...string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	dir := filepath.Join(home, ".celeste", "personas")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for _, name := range names {
+		data := []byte(`{"character":"` + name + `","description":"test","voice":{"style":"calm"}}`)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), data, 0644))
+	}
+}
+
+func lastMessage(app AppModel) string {
+	messages := app.chat.GetMessages()
+	return messages[len(messages)-1].Content
+}
+
+// TestPersonaCommand tests listing, switching and rejecting personas
+func TestPersonaCommand(t *testing.T) {
+	withPersonas(t, "moderator", "broken")
+	client := &fakePersonaClient{}
+	session := &config.Session{ID: "s1"}
+	app := NewApp(client).SetSessionManager(&fakeSessionManager{}, session)
+
+	model, _ := app.Update(SendMessageMsg{Content: "/persona"})
+	app = model.(AppModel)
+	assert.Contains(t, lastMessage(app), "▸ celeste (built-in)")
+	assert.Contains(t, lastMessage(app), "  moderator")
+	assert.Empty(t, client.switches)
+
+	model, _ = app.Update(SendMessageMsg{Content: "/persona nobody"})
+	app = model.(AppModel)
+	assert.Contains(t, lastMessage(app), "Unknown persona: nobody")
+	assert.Contains(t, lastMessage(app), "moderator")
+	assert.Empty(t, client.switches)
+
+	model, _ = app.Update(SendMessageMsg{Content: "/persona moderator"})
+	app = model.(AppModel)
+	assert.Equal(t, []string{"moderator"}, client.switches)
+	assert.Equal(t, "moderator", app.persona)
+	assert.Equal(t, "moderator", session.GetPersona())
+	assert.Contains(t, app.status.View(), "🎭 moderator")
+
+	// A persona the client can't load leaves the current one active
+	model, _ = app.Update(SendMessageMsg{Content: "/persona broken"})
+	app = model.(AppModel)
+	assert.Equal(t, "moderator", app.persona)
+	assert.Contains(t, lastMessage(app), "Failed to switch persona")
+
+	// Switching back to the built-in persona is stored as the default
+	model, _ = app.Update(SendMessageMsg{Content: "/persona celeste"})
+	app = model.(AppModel)
+	assert.Equal(t, "", client.persona)
+	assert.Empty(t, session.GetPersona())
+	assert.Contains(t, app.status.View(), "🎭 celeste")
+}
+
+// TestPersonaSurvivesEndpointSwitch tests that endpoint changes keep the session's persona
+func TestPersonaSurvivesEndpointSwitch(t *testing.T) {
+	withPersonas(t, "moderator")
+	client := &fakePersonaClient{}
+	session := &config.Session{ID: "s1"}
+	app := NewApp(client).SetSessionManager(&fakeSessionManager{}, session)
+
+	model, _ := app.Update(SendMessageMsg{Content: "/persona moderator"})
+	model, _ = model.(AppModel).Update(SendMessageMsg{Content: "/endpoint grok"})
+	app = model.(AppModel)
+
+	assert.Equal(t, []string{"grok"}, client.endpoints)
+	assert.Equal(t, "moderator", client.persona)
+	assert.Equal(t, "moderator", app.persona)
+	assert.Equal(t, "moderator", session.GetPersona())
+	assert.Equal(t, "grok", session.GetEndpoint())
+}
+
+// TestPersonaRestoredOnResume tests restoring the persona at startup and with /session resume
+func TestPersonaRestoredOnResume(t *testing.T) {
+	withPersonas(t, "moderator")
+	client := &fakePersonaClient{}
+	saved := &config.Session{ID: "s1", Persona: "moderator"}
+	manager := &fakeSessionManager{sessions: map[string]*config.Session{
+		"s1": saved,
+		"s2": {ID: "s2"},
+	}}
+
+	app := NewApp(client).SetSessionManager(manager, saved)
+	assert.Equal(t, "moderator", client.persona)
+	assert.Equal(t, "moderator", app.persona)
+	assert.Contains(t, app.status.View(), "🎭 moderator")
+
+	// Resuming a session without a persona returns to the default
+	model, _ := app.Update(SendMessageMsg{Content: "/session resume s2"})
+	app = model.(AppModel)
+	assert.Equal(t, "", client.persona)
+	assert.Equal(t, "", app.persona)
+
+	model, _ = app.Update(SendMessageMsg{Content: "/session resume s1"})
+	app = model.(AppModel)
+	assert.Equal(t, "moderator", client.persona)
+	assert.Equal(t, "moderator", app.persona)
+}
+
+// TestReloadPersona tests re-applying the active persona and warning about incomplete files
+func TestReloadPersona(t *testing.T) {
+	withPersonas(t, "moderator")
+	client := &fakePersonaClient{}
+	app := NewApp(client).SetSessionManager(&fakeSessionManager{}, &config.Session{ID: "s1"})
+
+	model, _ := app.Update(SendMessageMsg{Content: "/persona moderator"})
+	app = model.(AppModel)
+
+	// Edit the file so it's missing its instructions, then reload
+	path := filepath.Join(os.Getenv("HOME"), ".celeste", "personas", "moderator.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"character":"moderator"}`), 0644))
+	model, _ = app.Update(SendMessageMsg{Content: "/reload-persona"})
+	app = model.(AppModel)
+
+	assert.Equal(t, []string{"moderator", "moderator"}, client.switches)
+	assert.Equal(t, "moderator", app.persona)
+	assert.Contains(t, lastMessage(app), path)
+	assert.Contains(t, lastMessage(app), `missing "description"`)
+}
+
+// TestBrokenEssenceWarnsOnStartup tests the warning for a broken ~/.celeste/celeste_essence.json
+func TestBrokenEssenceWarnsOnStartup(t *testing.T) {
+	withPersonas(t)
+	path := filepath.Join(os.Getenv("HOME"), ".celeste", "celeste_essence.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+
+	app := NewApp(&fakePersonaClient{}).SetSessionManager(&fakeSessionManager{}, &config.Session{ID: "s1"})
+	assert.Contains(t, lastMessage(app), path)
+	assert.Contains(t, lastMessage(app), "invalid JSON")
+}
+
+// fakeSamplingClient records the sampling overrides applied with /set.
+type fakeSamplingClient struct {
+	fakePersonaClient
+	sampling config.Sampling
+}
+
+func (f *fakeSamplingClient) SetSampling(sampling config.Sampling) {
+	f.sampling = sampling
+}
+
+// TestSetCommand tests /set, its persistence and its restore on resume
+func TestSetCommand(t *testing.T) {
+	withPersonas(t)
+	temperature := 0.2
+	saved := &config.Session{ID: "s1", Sampling: &config.Sampling{Temperature: &temperature}}
+	manager := &fakeSessionManager{sessions: map[string]*config.Session{
+		"s1": saved,
+		"s2": {ID: "s2"},
+	}}
+	client := &fakeSamplingClient{}
+	app := NewApp(client).SetSessionManager(manager, saved)
+	app.model = "gpt-4o-mini"
+	assert.Equal(t, "temperature 0.2", client.sampling.String())
+
+	model, _ := app.Update(SendMessageMsg{Content: "/set max_tokens 300"})
+	app = model.(AppModel)
+	assert.Equal(t, "temperature 0.2, max_tokens 300", client.sampling.String())
+	assert.Equal(t, "temperature 0.2, max_tokens 300", saved.GetSampling().String())
+
+	model, _ = app.Update(SendMessageMsg{Content: "/set temperature 9"})
+	app = model.(AppModel)
+	assert.Contains(t, lastMessage(app), "temperature must be between 0 and 2")
+	assert.Equal(t, "temperature 0.2, max_tokens 300", client.sampling.String())
+
+	model, _ = app.Update(SendMessageMsg{Content: "/session resume s2"})
+	app = model.(AppModel)
+	assert.True(t, client.sampling.IsZero())
+	assert.True(t, app.sampling.IsZero())
+}
+
+// fakeSystemPromptClient keeps the prompt shown and overridden with /system.
+type fakeSystemPromptClient struct {
+	fakePersonaClient
+	override string
+}
+
+func (f *fakeSystemPromptClient) SystemPrompt() (string, bool) {
+	if f.override != "" {
+		return f.override, true
+	}
+	return "persona prompt for " + f.persona, false
+}
+
+func (f *fakeSystemPromptClient) OverrideSystemPrompt(prompt string) {
+	f.override = prompt
+}
+
+// TestSystemCommand tests showing, overriding and restoring the system prompt
+func TestSystemCommand(t *testing.T) {
+	withPersonas(t, "moderator")
+	client := &fakeSystemPromptClient{}
+	app := NewApp(client).SetSessionManager(&fakeSessionManager{}, &config.Session{ID: "s1"})
+
+	model, _ := app.Update(SendMessageMsg{Content: "/persona moderator"})
+	app = model.(AppModel)
+	model, _ = app.Update(SendMessageMsg{Content: "/system"})
+	app = model.(AppModel)
+	assert.Contains(t, lastMessage(app), "persona prompt for moderator")
+
+	model, _ = app.Update(SendMessageMsg{Content: "/system set Answer in French."})
+	app = model.(AppModel)
+	assert.Equal(t, "Answer in French.", client.override)
+	model, _ = app.Update(SendMessageMsg{Content: "/system"})
+	app = model.(AppModel)
+	assert.Contains(t, lastMessage(app), "override from /system set")
+	assert.Contains(t, lastMessage(app), "Answer in French.")
+
+	model, _ = app.Update(SendMessageMsg{Content: "/system clear"})
+	app = model.(AppModel)
+	assert.Equal(t, "", client.override)
+	assert.Contains(t, lastMessage(app), "override cleared")
+}
@@ -0,0 +1,104 @@
+// Package tui provides the Bubble Tea-based terminal UI for Celeste CLI.
+// This file contains /search over the current conversation.
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+// Search highlights query in the conversation and jumps to the most recent
+// message containing it. Matching ignores case, skill results, system
+// messages and image data. It returns the number of matching messages.
+func (m ChatModel) Search(query string) (ChatModel, int) {
+	m.search = query
+	m.searchHits = nil
+	for i, msg := range m.messages {
+		if msg.Role == "system" {
+			continue
+		}
+		if text, ok := config.SearchableText(msg.Role, msg.Content, false); ok && config.IndexFold(text, query) >= 0 {
+			m.searchHits = append(m.searchHits, i)
+		}
+	}
+	if len(m.searchHits) == 0 {
+		m.search = ""
+		m.updateContent()
+		return m, 0
+	}
+	m.searchPos = len(m.searchHits) - 1
+	return m.showSearchHit(), len(m.searchHits)
+}
+
+// NextMatch jumps to the next (delta 1) or previous (delta -1) match,
+// wrapping around at either end.
+func (m ChatModel) NextMatch(delta int) ChatModel {
+	if len(m.searchHits) == 0 {
+		return m
+	}
+	m.searchPos = (m.searchPos + delta + len(m.searchHits)) % len(m.searchHits)
+	return m.showSearchHit()
+}
+
+// SearchPosition returns the 1-based position of the current match and the
+// number of matches, or 0, 0 when no search is active.
+func (m ChatModel) SearchPosition() (current, total int) {
+	if len(m.searchHits) == 0 {
+		return 0, 0
+	}
+	return m.searchPos + 1, len(m.searchHits)
+}
+
+// ClearSearch removes the search highlighting.
+func (m ChatModel) ClearSearch() ChatModel {
+	m.search, m.searchHits = "", nil
+	m.updateContent()
+	return m
+}
+
+// showSearchHit re-renders with the current match marked and scrolls to it.
+func (m ChatModel) showSearchHit() ChatModel {
+	m.updateContent()
+	hit := m.searchHits[m.searchPos]
+	if hit < len(m.messageLines) && m.messageLines[hit] >= 0 {
+		m.viewport.SetYOffset(m.messageLines[hit])
+		m.userScrolled = true
+	}
+	return m
+}
+
+// isCurrentSearchHit reports whether message i is the match jumped to last.
+func (m ChatModel) isCurrentSearchHit(i int) bool {
+	return len(m.searchHits) > 0 && m.searchHits[m.searchPos] == i
+}
+
+// highlightMatches renders text in style with every case-insensitive match
+// of query in SearchMatchStyle. Matches split by wrapping stay unmarked.
+func highlightMatches(text, query string, style lipgloss.Style) string {
+	if config.IndexFold(text, query) < 0 {
+		return style.Render(text)
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		var b strings.Builder
+		for {
+			at := config.IndexFold(line, query)
+			if at < 0 {
+				break
+			}
+			if at > 0 {
+				b.WriteString(style.Render(line[:at]))
+			}
+			b.WriteString(SearchMatchStyle.Render(line[at : at+len(query)]))
+			line = line[at+len(query):]
+		}
+		if line != "" {
+			b.WriteString(style.Render(line))
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
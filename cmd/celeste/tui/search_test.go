@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pressKey(t *testing.T, app AppModel, key string) AppModel {
+	t.Helper()
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	if key == "esc" {
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	model, _ := app.Update(msg)
+	return model.(AppModel)
+}
+
+// TestSearchCommand tests /search matching, n/N navigation and clearing
+func TestSearchCommand(t *testing.T) {
+	app := NewApp(&fakeLLMClient{}).WithMessages([]ChatMessage{
+		{Role: "user", Content: "Write an ad read"},
+		{Role: "assistant", Content: "Here is the AD READ tone"},
+		{Role: "tool", ToolCallID: "call_1", Name: "get_weather", Content: "ad read in a tool result"},
+		{Role: "user", Content: "Shorter ad read please"},
+	})
+	model, _ := app.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	app = model.(AppModel)
+
+	model, _ = app.Update(SendMessageMsg{Content: "/search Ad Read"})
+	app = model.(AppModel)
+	current, total := app.chat.SearchPosition()
+	assert.Equal(t, 3, total, "the tool result is not searched")
+	assert.Equal(t, 3, current, "starts at the most recent match")
+	assert.Contains(t, app.status.View(), "Match 3/3")
+
+	app = pressKey(t, app, "n")
+	current, _ = app.chat.SearchPosition()
+	assert.Equal(t, 1, current, "n wraps to the first match")
+	app = pressKey(t, app, "N")
+	app = pressKey(t, app, "N")
+	current, _ = app.chat.SearchPosition()
+	assert.Equal(t, 2, current)
+	assert.Empty(t, app.input.Value())
+
+	// Typing leaves navigation, so n is typed again
+	app = pressKey(t, app, "x")
+	app = pressKey(t, app, "n")
+	assert.Equal(t, "xn", app.input.Value())
+	current, _ = app.chat.SearchPosition()
+	assert.Equal(t, 2, current)
+
+	app = pressKey(t, app, "esc")
+	current, total = app.chat.SearchPosition()
+	assert.Zero(t, current)
+	assert.Zero(t, total)
+
+	model, _ = app.Update(SendMessageMsg{Content: "/search nowhere"})
+	app = model.(AppModel)
+	assert.Contains(t, lastMessage(app), `No messages match "nowhere"`)
+}
+
+// TestHighlightMatches tests that every match on a line is marked
+func TestHighlightMatches(t *testing.T) {
+	text := "ad read, AD READ\nnothing"
+	out := highlightMatches(text, "ad read", TextStyle)
+	require.Contains(t, out, "nothing")
+	assert.Equal(t, TextStyle.Render("nothing"), highlightMatches("nothing", "ad read", TextStyle))
+	assert.Contains(t, out, SearchMatchStyle.Render("AD READ"))
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSkill sends a weather call started at start to a sized app
+func startSkill(t *testing.T, start time.Time) AppModel {
+	t.Helper()
+	app := NewApp(&fakeLLMClient{})
+	app, _ = update(t, app, tea.WindowSizeMsg{Width: 100, Height: 40})
+	app, _ = update(t, app, SendMessageMsg{Content: "weather?"})
+
+	app, cmd := update(t, app, SkillCallMsg{
+		Calls:     []FunctionCall{{ID: "call_1", Name: "get_weather", Status: "executing", Timestamp: start}},
+		ToolCalls: []ToolCallInfo{{ID: "call_1", Name: "get_weather"}},
+	})
+	require.NotNil(t, cmd, "a skill tick is scheduled")
+	assert.True(t, app.skillTicking)
+	return app
+}
+
+// TestSkillTickUpdatesElapsed tests that skill ticks update the running
+// call's elapsed time in the chat and the status bar
+func TestSkillTickUpdatesElapsed(t *testing.T) {
+	start := time.Now()
+	app := startSkill(t, start)
+
+	app, cmd := update(t, app, SkillTickMsg{Time: start.Add(4 * time.Second)})
+	assert.NotNil(t, cmd, "ticks continue while the call runs")
+	assert.Contains(t, app.chat.View(), "get_weather")
+	assert.Contains(t, app.chat.View(), "4s")
+	assert.Contains(t, app.status.View(), "Running get_weather (4s / 30s limit)")
+
+	app, _ = update(t, app, SkillProgressMsg{ToolCallID: "call_1", Status: "fetching forecast"})
+	app, _ = update(t, app, SkillTickMsg{Time: start.Add(5 * time.Second)})
+	assert.Contains(t, app.chat.View(), "5s · fetching forecast")
+	assert.Contains(t, app.status.View(), "(5s / 30s limit)")
+
+	// Once the result arrives the chip leaves the chat and ticking stops
+	app, _ = update(t, app, SkillResultMsg{Name: "get_weather", Result: `{"temp":70}`, ToolCallID: "call_1"})
+	assert.NotContains(t, app.chat.View(), "fetching forecast")
+	app, cmd = update(t, app, SkillTickMsg{Time: start.Add(6 * time.Second)})
+	assert.Nil(t, cmd)
+	assert.False(t, app.skillTicking)
+}
+
+// TestSkillTickTimesOut tests that a call running past SkillTimeout is shown
+// as timed out and keeps that status when its result arrives
+func TestSkillTickTimesOut(t *testing.T) {
+	start := time.Now()
+	app := startSkill(t, start)
+
+	app, cmd := update(t, app, SkillTickMsg{Time: start.Add(SkillTimeout)})
+	assert.Nil(t, cmd, "no call is left running")
+	require.Len(t, app.chat.functionCalls, 1)
+	assert.Equal(t, "timeout", app.chat.functionCalls[0].Status)
+	assert.Empty(t, app.chat.RunningFunctions())
+
+	app.chat = app.chat.ToggleSkillCalls()
+	assert.Contains(t, app.chat.View(), "timed out after 30s")
+
+	app, _ = update(t, app, SkillResultMsg{Name: "get_weather", Result: "Error: The request timed out", ToolCallID: "call_1"})
+	assert.Equal(t, "timeout", app.chat.functionCalls[0].Status)
+	assert.Equal(t, "Error: The request timed out", app.chat.functionCalls[0].Result)
+}
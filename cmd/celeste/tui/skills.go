@@ -353,6 +353,12 @@ func (m SkillsModel) getContextualHelp(input string) string {
 	return ""
 }
 
+// SetSkills replaces the skill definitions, keeping panel state.
+func (m SkillsModel) SetSkills(skills []SkillDefinition) SkillsModel {
+	m.skills = skills
+	return m
+}
+
 // AddSkill adds a skill definition.
 func (m SkillsModel) AddSkill(skill SkillDefinition) SkillsModel {
 	m.skills = append(m.skills, skill)
@@ -9,6 +9,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
 )
 
 // Corruption colors
@@ -232,16 +234,23 @@ func GetRandomCorruptionPlain() string {
 // Used for loading states and other animated text.
 // For character-level Japanese mixing, use CorruptTextJapanese instead.
 func CorruptText(text string, intensity float64) string {
-	if intensity <= 0 {
+	anim := config.CurrentAnimation()
+	intensity *= anim.Intensity
+	if !anim.Enabled || intensity <= 0 {
 		return text
 	}
 
+	glyphs := corruptChars
+	if anim.Glyphs != nil {
+		glyphs = anim.Glyphs
+	}
+
 	runes := []rune(text)
 	result := make([]rune, len(runes))
 
 	for i, r := range runes {
 		if rand.Float64() < intensity {
-			result[i] = corruptChars[rand.Intn(len(corruptChars))]
+			result[i] = glyphs[rand.Intn(len(glyphs))]
 		} else {
 			result[i] = r
 		}
@@ -254,7 +263,13 @@ func CorruptText(text string, intensity float64) string {
 // This creates the classic translation-failure aesthetic: "loaディング", "pro理cessing"
 // Use this for dashboard titles and headers where you want readable corruption.
 func CorruptTextJapanese(text string, intensity float64) string {
-	if intensity <= 0 {
+	anim := config.CurrentAnimation()
+	if anim.Glyphs != nil {
+		// A custom glyph set replaces the Japanese mix entirely
+		return CorruptText(text, intensity)
+	}
+	intensity *= anim.Intensity
+	if !anim.Enabled || intensity <= 0 {
 		return text
 	}
 
@@ -308,6 +323,10 @@ func ThinkingAnimation(frame int) string {
 		"Celeste is being overwritten",
 		"Celeste is sinking deeper",
 	}
+	anim := config.CurrentAnimation()
+	if !anim.Enabled || anim.Intensity <= 0 {
+		return corruptMagenta.Render(prefixes[0]) + "..."
+	}
 	prefix := prefixes[(frame/4)%len(prefixes)]
 
 	// Add corrupted dots with varying intensity
@@ -325,16 +344,25 @@ func ThinkingAnimation(frame int) string {
 	return corruptMagenta.Render(prefix) + dots + suffix
 }
 
+// animationInterval returns the frame interval for the thinking animation.
+func animationInterval() time.Duration {
+	return config.CurrentAnimation().Interval
+}
+
 // StreamingSpinner returns an animated spinner for streaming.
 func StreamingSpinner(frame int) string {
 	// Corrupted-style spinner
 	frames := []string{
 		"◐", "◓", "◑", "◒",
 	}
+	anim := config.CurrentAnimation()
+	if !anim.Enabled {
+		return corruptMagenta.Render(frames[0])
+	}
 	spinner := frames[frame%len(frames)]
 
 	// Add occasional glitch - more frequent
-	if rand.Float64() < 0.2 {
+	if rand.Float64() < 0.2*anim.Intensity {
 		spinner = symbolGlitch[rand.Intn(len(symbolGlitch))]
 	}
 
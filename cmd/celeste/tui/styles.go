@@ -123,6 +123,11 @@ var (
 				Foreground(ColorPurpleNeon).
 				Italic(true)
 
+	SearchMatchStyle = lipgloss.NewStyle().
+				Foreground(ColorBg).
+				Background(ColorWarning).
+				Bold(true)
+
 	TimestampStyle = lipgloss.NewStyle().
 			Foreground(ColorTextMuted).
 			Width(6)
@@ -175,6 +180,10 @@ var (
 			Foreground(ColorError).
 			Bold(true)
 
+	SkillTimedOutStyle = lipgloss.NewStyle().
+				Foreground(ColorPurpleNeon).
+				Bold(true)
+
 	// Status bar styles - minimal
 	StatusBarStyle = lipgloss.NewStyle().
 			Foreground(ColorTextMuted)
@@ -185,6 +194,14 @@ var (
 	StatusStreamingStyle = lipgloss.NewStyle().
 				Foreground(ColorWarning)
 
+	// Monthly budget badges - yellow at 80%, red once exceeded
+	BudgetWarnStyle = lipgloss.NewStyle().
+			Foreground(ColorWarning)
+
+	BudgetExceededStyle = lipgloss.NewStyle().
+				Foreground(ColorError).
+				Bold(true)
+
 	// NSFW indicator - bold glowing effect
 	NSFWStyle = lipgloss.NewStyle().
 			Foreground(ColorCorrupt1).
@@ -249,6 +266,8 @@ func SkillStatusStyle(status string) lipgloss.Style {
 		return SkillCompletedStyle
 	case "error":
 		return SkillErrorStyle
+	case "timeout":
+		return SkillTimedOutStyle
 	default:
 		return SkillNameStyle
 	}
@@ -0,0 +1,194 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// checksumsAsset is the release file listing the SHA256 of every archive.
+const checksumsAsset = "checksums.txt"
+
+// BinaryName returns the name of the binary inside a release archive,
+// e.g. celeste-linux-amd64 or celeste-windows-amd64.exe.
+func BinaryName(goos, goarch string) string {
+	name := fmt.Sprintf("celeste-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// AssetName returns the release archive for a platform: a .zip on Windows
+// and a .tar.gz everywhere else.
+func AssetName(goos, goarch string) string {
+	if goos == "windows" {
+		return fmt.Sprintf("celeste-%s-%s.zip", goos, goarch)
+	}
+	return fmt.Sprintf("celeste-%s-%s.tar.gz", goos, goarch)
+}
+
+// Download fetches the release archive for a platform, verifies it against
+// the release's checksums.txt and returns the extracted binary.
+func (c *Client) Download(ctx context.Context, release *Release, goos, goarch string) ([]byte, error) {
+	name := AssetName(goos, goarch)
+	asset, ok := release.asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no build for %s/%s (%s)", release.TagName, goos, goarch, name)
+	}
+	sums, ok := release.asset(checksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.TagName, checksumsAsset)
+	}
+
+	sumsData, err := c.get(ctx, sums.URL, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", checksumsAsset, err)
+	}
+	want, err := findChecksum(sumsData, name)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := c.get(ctx, asset.URL, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, want, got)
+	}
+
+	binary, err := extractBinary(archive, name, BinaryName(goos, goarch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return binary, nil
+}
+
+// findChecksum returns the SHA256 for file from sha256sum-style output.
+func findChecksum(sums []byte, file string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == file {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no checksum for %s", checksumsAsset, file)
+}
+
+// extractBinary returns the named file from a .tar.gz or .zip archive.
+func extractBinary(archive []byte, archiveName, binaryName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if filepath.Base(f.Name) != binaryName {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s not found in archive", binaryName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in archive", binaryName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == binaryName {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// CheckWritable reports why the binary at exePath can't be replaced, or nil
+// if it can. Replacing needs write access to the directory, since the new
+// binary is written next to the old one and renamed over it.
+func CheckWritable(exePath string) error {
+	dir := filepath.Dir(exePath)
+	f, err := os.CreateTemp(dir, ".celeste-update-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable by this user (%v); reinstall with the permissions used to install it, e.g. with sudo, or download the release manually", dir, err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// Replace atomically swaps the binary at exePath for binary: it writes a
+// temp file in the same directory and renames it over the original, so an
+// interrupted update never leaves a half-written executable.
+func Replace(exePath string, binary []byte) error {
+	if err := CheckWritable(exePath); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(exePath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(exePath)
+	tmp, err := os.CreateTemp(dir, ".celeste-update-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // No-op once renamed
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return err
+	}
+
+	// A running executable can't be overwritten on Windows, but it can be moved
+	old := exePath + ".old"
+	if runtime.GOOS == "windows" {
+		_ = os.Remove(old)
+		if err := os.Rename(exePath, old); err != nil {
+			return fmt.Errorf("failed to move current binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmpName, exePath); err != nil {
+		if runtime.GOOS == "windows" {
+			_ = os.Rename(old, exePath)
+		}
+		return fmt.Errorf("failed to replace binary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// CheckInterval is how often the startup check contacts GitHub.
+const CheckInterval = 24 * time.Hour
+
+// DisableEnv turns off the startup check when set to any non-empty value.
+const DisableEnv = "CELESTE_NO_UPDATE_CHECK"
+
+// checkState is the cached result of the last update check.
+type checkState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// StatePath returns where the last check is cached (update_check.json in
+// the data directory).
+func StatePath() string {
+	return paths.Data("update_check.json")
+}
+
+// Disabled reports whether the startup check is turned off by environment.
+func Disabled() bool {
+	return os.Getenv(DisableEnv) != ""
+}
+
+// Check returns the latest release version if it is newer than current.
+// GitHub is contacted at most once per CheckInterval; in between, the cached
+// result from statePath is used. Returns "" when current is up to date or
+// the check failed.
+func (c *Client) Check(ctx context.Context, current, statePath string, now time.Time) string {
+	var state checkState
+	if data, err := os.ReadFile(statePath); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+
+	if now.Sub(state.CheckedAt) >= CheckInterval || now.Before(state.CheckedAt) {
+		release, err := c.LatestRelease(ctx)
+		// Record the attempt even on failure, so an offline machine doesn't
+		// retry on every start
+		state.CheckedAt = now
+		if err == nil {
+			state.Latest = release.Version()
+		}
+		if data, err := json.MarshalIndent(state, "", "  "); err == nil {
+			_ = os.MkdirAll(filepath.Dir(statePath), 0755)
+			_ = os.WriteFile(statePath, data, 0644)
+		}
+	}
+
+	if state.Latest != "" && CompareVersions(state.Latest, current) > 0 {
+		return state.Latest
+	}
+	return ""
+}
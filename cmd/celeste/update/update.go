@@ -0,0 +1,153 @@
+// Package update checks GitHub releases for newer versions of Celeste and
+// replaces the running binary with the latest release.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// Repo is the GitHub repository releases are published to.
+const Repo = "whykusanagi/celesteCLI"
+
+// DefaultAPIURL is the GitHub API base URL.
+const DefaultAPIURL = "https://api.github.com"
+
+// Release is a published GitHub release.
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the leading "v".
+func (r Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// asset returns the release asset with the given name.
+func (r Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Client talks to the GitHub releases API and downloads release assets.
+type Client struct {
+	APIURL string
+	HTTP   *http.Client
+}
+
+// NewClient returns a client for the public GitHub API.
+func NewClient() *Client {
+	return &Client{
+		APIURL: DefaultAPIURL,
+		HTTP:   httpclient.New(60 * time.Second),
+	}
+}
+
+// LatestRelease fetches the most recent published release.
+func (c *Client) LatestRelease(ctx context.Context) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimRight(c.APIURL, "/"), Repo)
+	body, err := c.get(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse latest release: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("latest release has no tag")
+	}
+	return &release, nil
+}
+
+// get fetches url and returns the body, failing on non-200 responses.
+func (c *Client) get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// CompareVersions compares two semantic versions, with or without a leading
+// "v". It returns -1 if a < b, 0 if they are equal and 1 if a > b. A
+// pre-release (1.5.0-rc1) sorts before its release (1.5.0).
+func CompareVersions(a, b string) int {
+	coreA, preA := parseVersion(a)
+	coreB, preB := parseVersion(b)
+	for i := range coreA {
+		if coreA[i] != coreB[i] {
+			if coreA[i] < coreB[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	case preA < preB:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// parseVersion splits a version into major, minor and patch numbers and a
+// pre-release suffix. Missing or non-numeric parts count as 0.
+func parseVersion(v string) ([3]int, string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i] // Build metadata doesn't affect ordering
+	}
+	var pre string
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v, pre = v[:i], v[i+1:]
+	}
+
+	var core [3]int
+	for i, part := range strings.SplitN(v, ".", 3) {
+		core[i], _ = strconv.Atoi(part)
+	}
+	return core, pre
+}
@@ -0,0 +1,273 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseServer mocks the GitHub releases API and asset downloads. files maps
+// asset names to their contents; every file is listed on the latest release.
+type releaseServer struct {
+	*httptest.Server
+	tag      string
+	files    map[string][]byte
+	apiCalls atomic.Int32
+}
+
+func newReleaseServer(t *testing.T, tag string, files map[string][]byte) *releaseServer {
+	t.Helper()
+	rs := &releaseServer{tag: tag, files: files}
+	rs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/"+Repo+"/releases/latest" {
+			rs.apiCalls.Add(1)
+			release := Release{TagName: rs.tag}
+			for name := range rs.files {
+				release.Assets = append(release.Assets, Asset{Name: name, URL: rs.URL + "/download/" + name})
+			}
+			_ = json.NewEncoder(w).Encode(release)
+			return
+		}
+		data, ok := rs.files[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func (rs *releaseServer) client() *Client {
+	return &Client{APIURL: rs.URL, HTTP: rs.Client()}
+}
+
+func tarGz(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func zipped(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create(name)
+	require.NoError(t, err)
+	_, err = f.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func checksums(files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	for name, data := range files {
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(&buf, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	return buf.Bytes()
+}
+
+// TestCompareVersions tests semver ordering
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.4.0", "1.4.0", 0},
+		{"v1.4.0", "1.4.0", 0},
+		{"1.5.0", "1.4.0", 1},
+		{"1.4.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.4", "1.4.0", 0},
+		{"1.5.0-rc1", "1.5.0", -1},
+		{"1.5.0-rc2", "1.5.0-rc1", 1},
+		{"1.5.0-rc1", "1.4.0", 1},
+		{"1.4.0+build5", "1.4.0", 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b), "%s vs %s", tt.a, tt.b)
+	}
+}
+
+// TestLatestRelease tests fetching and parsing the latest release
+func TestLatestRelease(t *testing.T) {
+	rs := newReleaseServer(t, "v1.5.0", map[string][]byte{checksumsAsset: nil})
+	release, err := rs.client().LatestRelease(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v1.5.0", release.TagName)
+	assert.Equal(t, "1.5.0", release.Version())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	_, err = (&Client{APIURL: server.URL, HTTP: server.Client()}).LatestRelease(context.Background())
+	assert.ErrorContains(t, err, "HTTP 403")
+}
+
+// TestCheckCaches tests that GitHub is asked at most once per interval
+func TestCheckCaches(t *testing.T) {
+	rs := newReleaseServer(t, "v1.5.0", nil)
+	statePath := filepath.Join(t.TempDir(), "update_check.json")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "1.5.0", rs.client().Check(context.Background(), "1.4.0", statePath, now))
+	assert.EqualValues(t, 1, rs.apiCalls.Load())
+
+	// Within the interval the cached answer is used
+	rs.tag = "v1.6.0"
+	assert.Equal(t, "1.5.0", rs.client().Check(context.Background(), "1.4.0", statePath, now.Add(23*time.Hour)))
+	assert.EqualValues(t, 1, rs.apiCalls.Load())
+
+	// Already on the cached version
+	assert.Empty(t, rs.client().Check(context.Background(), "1.5.0", statePath, now.Add(time.Hour)))
+
+	// After the interval GitHub is asked again
+	assert.Equal(t, "1.6.0", rs.client().Check(context.Background(), "1.4.0", statePath, now.Add(25*time.Hour)))
+	assert.EqualValues(t, 2, rs.apiCalls.Load())
+
+	data, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	var state checkState
+	require.NoError(t, json.Unmarshal(data, &state))
+	assert.Equal(t, "1.6.0", state.Latest)
+	assert.True(t, state.CheckedAt.Equal(now.Add(25*time.Hour)))
+}
+
+// TestCheckRecordsFailures tests that an unreachable API isn't retried on every start
+func TestCheckRecordsFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := &Client{APIURL: server.URL, HTTP: server.Client()}
+	statePath := filepath.Join(t.TempDir(), "nested", "update_check.json")
+	now := time.Now()
+
+	assert.Empty(t, client.Check(context.Background(), "1.4.0", statePath, now))
+	assert.Empty(t, client.Check(context.Background(), "1.4.0", statePath, now.Add(time.Hour)))
+	assert.EqualValues(t, 1, calls.Load())
+	assert.FileExists(t, statePath)
+}
+
+// TestDownload tests fetching and verifying the archive for each platform
+func TestDownload(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho celeste 1.5.0\n")
+	files := map[string][]byte{
+		AssetName("linux", "amd64"):   tarGz(t, "celeste-linux-amd64", binary),
+		AssetName("windows", "amd64"): zipped(t, "celeste-windows-amd64.exe", binary),
+	}
+	files[checksumsAsset] = checksums(files)
+	rs := newReleaseServer(t, "v1.5.0", files)
+
+	release, err := rs.client().LatestRelease(context.Background())
+	require.NoError(t, err)
+
+	got, err := rs.client().Download(context.Background(), release, "linux", "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, binary, got)
+
+	got, err = rs.client().Download(context.Background(), release, "windows", "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, binary, got)
+
+	_, err = rs.client().Download(context.Background(), release, "plan9", "386")
+	assert.ErrorContains(t, err, "no build for plan9/386")
+}
+
+// TestDownloadRejectsBadChecksum tests that tampered or unverifiable archives are refused
+func TestDownloadRejectsBadChecksum(t *testing.T) {
+	name := AssetName("linux", "arm64")
+	archive := tarGz(t, "celeste-linux-arm64", []byte("binary"))
+
+	rs := newReleaseServer(t, "v1.5.0", map[string][]byte{
+		name:           archive,
+		checksumsAsset: checksums(map[string][]byte{name: []byte("something else")}),
+	})
+	release, err := rs.client().LatestRelease(context.Background())
+	require.NoError(t, err)
+	_, err = rs.client().Download(context.Background(), release, "linux", "arm64")
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	rs = newReleaseServer(t, "v1.5.0", map[string][]byte{
+		name:           archive,
+		checksumsAsset: checksums(map[string][]byte{"other.tar.gz": archive}),
+	})
+	release, err = rs.client().LatestRelease(context.Background())
+	require.NoError(t, err)
+	_, err = rs.client().Download(context.Background(), release, "linux", "arm64")
+	assert.ErrorContains(t, err, "no checksum for "+name)
+
+	rs = newReleaseServer(t, "v1.5.0", map[string][]byte{name: archive})
+	release, err = rs.client().LatestRelease(context.Background())
+	require.NoError(t, err)
+	_, err = rs.client().Download(context.Background(), release, "linux", "arm64")
+	assert.ErrorContains(t, err, "refusing to install an unverified binary")
+}
+
+// TestReplace tests swapping the binary while keeping its permissions
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "celeste")
+	require.NoError(t, os.WriteFile(exe, []byte("old"), 0750))
+
+	require.NoError(t, Replace(exe, []byte("new")))
+
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	info, err := os.Stat(exe)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+
+	// No temp files are left behind
+	entries, err := os.ReadDir(filepath.Dir(exe))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+// TestCheckWritable tests refusing to update a binary in a read-only directory
+func TestCheckWritable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "celeste")
+	require.NoError(t, os.WriteFile(exe, []byte("old"), 0755))
+	assert.NoError(t, CheckWritable(exe))
+
+	require.NoError(t, os.Chmod(dir, 0555))
+	t.Cleanup(func() { _ = os.Chmod(dir, 0755) })
+
+	err := CheckWritable(exe)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not writable")
+	assert.Error(t, Replace(exe, []byte("new")))
+}
@@ -0,0 +1,40 @@
+// Package chat holds the conversation types shared by the LLM client, the
+// TUI and the embeddable celeste package, so none of them has to import
+// another just for its message shapes.
+package chat
+
+import "time"
+
+// Message represents a message in the conversation.
+type Message struct {
+	Role       string     // "user", "assistant", "system", "tool"
+	Content    string     // Message content
+	ToolCallID string     // For tool messages, the tool call ID
+	Name       string     // For tool messages, the function name
+	ToolCalls  []ToolCall // For assistant messages, the tool calls that were made
+	Images     []string   // For user messages, image URLs or data: URLs (multimodal)
+	ImageRefs  []ImageRef // For user messages, what each image was loaded from
+	Timestamp  time.Time  // When the message was created
+}
+
+// ImageRef describes an attached image without its data. Sessions store
+// these instead of the base64 data.
+type ImageRef struct {
+	Source string // Path or URL the image was loaded from
+	Label  string // Shown in the chat as [image: Label], e.g. "cat.png, 1.2MB"
+}
+
+// ToolCall represents a tool call in an assistant message.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+}
+
+// SkillDefinition represents a skill/function that can be called.
+type SkillDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+	SideEffect  bool           `json:"side_effect,omitempty"` // Changes something outside the conversation
+}
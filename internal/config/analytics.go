@@ -7,6 +7,9 @@ import (
 	"path/filepath"
 	"sort"
 	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
 )
 
 // GlobalAnalytics tracks cumulative usage across all sessions
@@ -64,7 +67,7 @@ func NewGlobalAnalytics() *GlobalAnalytics {
 	}
 }
 
-// LoadGlobalAnalytics loads analytics from ~/.celeste/analytics.json
+// LoadGlobalAnalytics loads analytics from analytics.json in the data directory
 func LoadGlobalAnalytics() (*GlobalAnalytics, error) {
 	analyticsPath := GetAnalyticsPath()
 
@@ -100,7 +103,7 @@ func LoadGlobalAnalytics() (*GlobalAnalytics, error) {
 	return &analytics, nil
 }
 
-// Save persists analytics to ~/.celeste/analytics.json
+// Save persists analytics to analytics.json in the data directory
 func (ga *GlobalAnalytics) Save() error {
 	analyticsPath := GetAnalyticsPath()
 
@@ -119,8 +122,8 @@ func (ga *GlobalAnalytics) Save() error {
 		return fmt.Errorf("failed to marshal analytics: %w", err)
 	}
 
-	// Write file
-	if err := os.WriteFile(analyticsPath, data, 0644); err != nil {
+	// Write file atomically, as every session save rewrites it
+	if err := filelock.WriteFile(analyticsPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write analytics file: %w", err)
 	}
 
@@ -301,9 +304,5 @@ func (ga *GlobalAnalytics) GetTopProviders() []struct {
 
 // GetAnalyticsPath returns the path to the analytics file
 func GetAnalyticsPath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return filepath.Join(".celeste", "analytics.json")
-	}
-	return filepath.Join(homeDir, ".celeste", "analytics.json")
+	return paths.Data("analytics.json")
 }
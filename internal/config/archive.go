@@ -0,0 +1,168 @@
+// Package config provides configuration management for Celeste CLI.
+// This file rolls the oldest messages of long sessions into an archive file
+// so the session file rewritten on every save stays small.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxSessionMessages is how many messages a session file holds before
+// the oldest are moved to its archive.
+const DefaultMaxSessionMessages = 500
+
+// LargeSessionSize is the session file size past which a warning is shown.
+const LargeSessionSize = 1 << 20
+
+const archiveSuffix = ".archive.json"
+
+// sessionArchive is the <id>.archive.json file, oldest message first.
+type sessionArchive struct {
+	ID       string           `json:"id"`
+	Messages []SessionMessage `json:"messages"`
+}
+
+// archivePath returns the archive path for a session.
+func (m *SessionManager) archivePath(sessionID string) string {
+	return filepath.Join(m.sessionsDir, sessionID+archiveSuffix)
+}
+
+// isArchive reports whether a file in the sessions directory is an archive
+// rather than a session.
+func isArchive(path string) bool {
+	return strings.HasSuffix(path, archiveSuffix)
+}
+
+// sessionFiles returns the session files in the sessions directory,
+// leaving out archives.
+func (m *SessionManager) sessionFiles() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(m.sessionsDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sessions := files[:0]
+	for _, file := range files {
+		if !isArchive(file) {
+			sessions = append(sessions, file)
+		}
+	}
+	return sessions, nil
+}
+
+// SetMaxMessages sets how many messages a session file keeps before the
+// oldest are archived, e.g. from the max_session_messages config setting.
+// Zero or negative restores DefaultMaxSessionMessages.
+func (m *SessionManager) SetMaxMessages(n int) {
+	m.maxMessages = n
+}
+
+// OnLargeSession sets fn to be called, once per session, when a saved
+// session file grows past LargeSessionSize.
+func (m *SessionManager) OnLargeSession(fn func(sessionID string, size int)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLarge = fn
+}
+
+// checkSize calls the OnLargeSession callback the first time a session's
+// file is larger than LargeSessionSize.
+func (m *SessionManager) checkSize(sessionID string, size int) {
+	if size <= LargeSessionSize {
+		return
+	}
+	m.mu.Lock()
+	fn := m.onLarge
+	if fn == nil || m.warned[sessionID] {
+		m.mu.Unlock()
+		return
+	}
+	if m.warned == nil {
+		m.warned = make(map[string]bool)
+	}
+	m.warned[sessionID] = true
+	m.mu.Unlock()
+	fn(sessionID, size)
+}
+
+// rollover moves the oldest messages of a session with more than the
+// message limit into its archive, keeping the newest three quarters of the
+// limit so the next rollover is a while off rather than on every save.
+func (m *SessionManager) rollover(session *Session) error {
+	limit := m.maxMessages
+	if limit <= 0 {
+		limit = DefaultMaxSessionMessages
+	}
+	if len(session.Messages) <= limit {
+		return nil
+	}
+	n := len(session.Messages) - limit*3/4
+
+	archived, err := m.LoadArchive(session.ID)
+	if err != nil {
+		return err
+	}
+	// Messages written by a save that died before the session file was
+	// updated are still in the session, so drop them from the archive
+	if len(archived) > session.Archived {
+		archived = archived[:session.Archived]
+	}
+	archived = append(archived, session.Messages[:n]...)
+
+	data, err := json.MarshalIndent(sessionArchive{ID: session.ID, Messages: archived}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session archive: %w", err)
+	}
+	path := m.archivePath(session.ID)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write session archive: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write session archive: %w", err)
+	}
+
+	session.Messages = append([]SessionMessage(nil), session.Messages[n:]...)
+	session.Archived = len(archived)
+	session.rolled += n
+	return nil
+}
+
+// LoadArchive returns the messages archived from a session, oldest first,
+// or none if it has no archive.
+func (m *SessionManager) LoadArchive(sessionID string) ([]SessionMessage, error) {
+	data, err := os.ReadFile(m.archivePath(sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session archive: %w", err)
+	}
+	var archive sessionArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("failed to parse session archive: %w", err)
+	}
+	return archive.Messages, nil
+}
+
+// LoadFull loads a session with its archived messages put back in front.
+// Saving it archives the oldest messages again.
+func (m *SessionManager) LoadFull(id string) (*Session, error) {
+	session, err := m.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := m.LoadArchive(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(archived) > session.Archived {
+		archived = archived[:session.Archived]
+	}
+	session.Messages = append(archived, session.Messages...)
+	session.Archived = 0
+	return session, nil
+}
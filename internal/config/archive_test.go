@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSessionManager(t *testing.T) *SessionManager {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+	return NewSessionManager()
+}
+
+func numberedMessages(from, to int) []SessionMessage {
+	var msgs []SessionMessage
+	for i := from; i <= to; i++ {
+		role := "user"
+		if i%2 == 0 {
+			role = "assistant"
+		}
+		msgs = append(msgs, SessionMessage{Role: role, Content: fmt.Sprintf("message %d", i), Timestamp: time.Now()})
+	}
+	return msgs
+}
+
+// TestSessionRollover tests archiving at the message limit
+func TestSessionRollover(t *testing.T) {
+	manager := newTestSessionManager(t)
+	manager.SetMaxMessages(8)
+
+	session := manager.NewSession()
+	session.Messages = numberedMessages(1, 8)
+	require.NoError(t, manager.Save(session))
+	assert.Len(t, session.Messages, 8, "at the limit nothing is archived")
+	_, err := os.Stat(manager.archivePath(session.ID))
+	assert.True(t, os.IsNotExist(err))
+
+	// One past the limit keeps the newest three quarters
+	session.Messages = append(session.Messages, numberedMessages(9, 9)...)
+	require.NoError(t, manager.Save(session))
+	assert.Equal(t, 3, session.Archived)
+	require.Len(t, session.Messages, 6)
+	assert.Equal(t, "message 4", session.Messages[0].Content)
+
+	loaded, err := manager.Load(session.ID)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Messages, 6, "loading skips the archive")
+	summary := loaded.Summarize()
+	assert.Equal(t, 9, summary.MessageCount)
+	assert.Equal(t, "message 5", summary.FirstMessage, "the preview comes from the session file")
+
+	// The TUI passes its whole conversation; archived messages are not archived twice
+	session.SetMessagesRaw(numberedMessages(1, 12))
+	require.NoError(t, manager.Save(session))
+	assert.Equal(t, 6, session.Archived)
+	assert.Equal(t, "message 7", session.Messages[0].Content)
+
+	full, err := manager.LoadFull(session.ID)
+	require.NoError(t, err)
+	require.Len(t, full.Messages, 12)
+	for i, msg := range full.Messages {
+		assert.Equal(t, fmt.Sprintf("message %d", i+1), msg.Content)
+	}
+
+	sessions, err := manager.List()
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1, "archives are not listed as sessions")
+
+	require.NoError(t, manager.Delete(session.ID))
+	_, err = os.Stat(manager.archivePath(session.ID))
+	assert.True(t, os.IsNotExist(err))
+}
+
+// TestRolloverAfterInterruptedSave tests that messages archived by a save
+// that never reached the session file are not archived twice
+func TestRolloverAfterInterruptedSave(t *testing.T) {
+	manager := newTestSessionManager(t)
+	manager.SetMaxMessages(8)
+
+	session := manager.NewSession()
+	session.Messages = numberedMessages(1, 9)
+	require.NoError(t, manager.Save(session))
+
+	// The archive was written again but the session file was not
+	stale, err := manager.Load(session.ID)
+	require.NoError(t, err)
+	stale.Messages = append(stale.Messages, numberedMessages(10, 12)...)
+	require.NoError(t, manager.rollover(stale))
+
+	session, err = manager.Load(session.ID)
+	require.NoError(t, err)
+	session.Messages = append(session.Messages, numberedMessages(10, 12)...)
+	require.NoError(t, manager.Save(session))
+
+	full, err := manager.LoadFull(session.ID)
+	require.NoError(t, err)
+	require.Len(t, full.Messages, 12)
+	for i, msg := range full.Messages {
+		assert.Equal(t, fmt.Sprintf("message %d", i+1), msg.Content)
+	}
+}
+
+// TestLargeSessionWarning tests that a large session file warns once
+func TestLargeSessionWarning(t *testing.T) {
+	manager := newTestSessionManager(t)
+	var warned []string
+	manager.OnLargeSession(func(id string, size int) {
+		assert.Greater(t, size, LargeSessionSize)
+		warned = append(warned, id)
+	})
+
+	small := manager.NewSession()
+	small.Messages = numberedMessages(1, 2)
+	require.NoError(t, manager.Save(small))
+
+	large := manager.NewSession()
+	large.Messages = []SessionMessage{{Role: "user", Content: strings.Repeat("x", LargeSessionSize)}}
+	require.NoError(t, manager.Save(large))
+	require.NoError(t, manager.Save(large))
+
+	assert.Equal(t, []string{large.ID}, warned)
+}
+
+// BenchmarkSaveLongSession compares saving a 2000-message session whole
+// with saving it once older messages are archived.
+func BenchmarkSaveLongSession(b *testing.B) {
+	content := strings.Repeat("A long reply about stream schedules and overlays. ", 40)
+	for _, bc := range []struct {
+		name  string
+		limit int
+	}{
+		{"unbounded", 1 << 30},
+		{"archived", DefaultMaxSessionMessages},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			manager := &SessionManager{sessionsDir: b.TempDir(), maxMessages: bc.limit}
+			session := manager.NewSession()
+			for i := 0; i < 2000; i++ {
+				session.Messages = append(session.Messages, SessionMessage{Role: "user", Content: content, Timestamp: time.Now()})
+			}
+			if err := manager.Save(session); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				session.Messages = append(session.Messages, SessionMessage{Role: "user", Content: content, Timestamp: time.Now()})
+				if err := manager.Save(session); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
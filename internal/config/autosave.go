@@ -0,0 +1,146 @@
+// Package config provides configuration management for Celeste CLI.
+// This file coalesces the TUI's session saves so a burst of messages
+// rewrites the session file once rather than once per message.
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultAutosaveInterval is the shortest time between two autosaves of a
+// session.
+const DefaultAutosaveInterval = 2 * time.Second
+
+// Autosaver saves sessions in the background at most once per interval.
+// A save after a quiet spell is written at once; saves within the interval
+// of the last write are merged into one written when the interval is up.
+// Save copies the session, so the caller can keep changing it while the
+// copy waits to be written. Its methods are safe for concurrent use.
+type Autosaver struct {
+	manager  *SessionManager
+	interval time.Duration
+
+	mu        sync.Mutex
+	pending   *Session
+	after     []func()
+	timer     *time.Timer
+	lastWrite time.Time
+	archived  map[string]archivedState // By session ID, from the last write
+
+	writeMu sync.Mutex // Keeps writes of the same session in order
+}
+
+// archivedState is how far a written session's messages had been moved to
+// its archive, for Save to bring the caller's session up to date.
+type archivedState struct {
+	rolled   int
+	archived int
+}
+
+// NewAutosaver returns an autosaver for the manager's sessions. Zero or
+// negative interval uses DefaultAutosaveInterval.
+func (m *SessionManager) NewAutosaver(interval time.Duration) *Autosaver {
+	if interval <= 0 {
+		interval = DefaultAutosaveInterval
+	}
+	return &Autosaver{manager: m, interval: interval}
+}
+
+// Save schedules a copy of session to be saved and after, if not nil, to be
+// called once that save has succeeded. A pending save of another session is
+// written straight away. Messages an earlier save moved to the archive are
+// dropped from session first, as a direct save would have done. Call it
+// from the goroutine that changes session.
+func (a *Autosaver) Save(session *Session, after func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if state, ok := a.archived[session.ID]; ok {
+		delete(a.archived, session.ID)
+		if n := state.rolled - session.rolled; n > 0 && n <= len(session.Messages) {
+			session.Messages = session.Messages[n:]
+			session.rolled = state.rolled
+			session.Archived = state.archived
+		}
+	}
+	if a.pending != nil && a.pending.ID != session.ID {
+		go a.write(a.pending, a.after) //nolint:errcheck // Autosaves are best-effort
+		a.pending, a.after = nil, nil
+	}
+	a.pending = session.Snapshot()
+	if after != nil {
+		a.after = append(a.after, after)
+	}
+	if a.timer != nil {
+		return // Already scheduled; this save joins it
+	}
+	wait := a.interval - time.Since(a.lastWrite)
+	if wait < 0 {
+		wait = 0
+	}
+	a.timer = time.AfterFunc(wait, a.fire)
+}
+
+// fire writes the pending save when its interval is up.
+func (a *Autosaver) fire() {
+	a.mu.Lock()
+	session, after := a.pending, a.after
+	a.pending, a.after, a.timer = nil, nil, nil
+	a.lastWrite = time.Now()
+	a.mu.Unlock()
+
+	_ = a.write(session, after) // Autosaves are best-effort
+}
+
+// Flush writes any pending save now, e.g. before exiting.
+func (a *Autosaver) Flush() error {
+	a.mu.Lock()
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+	session, after := a.pending, a.after
+	a.pending, a.after, a.timer = nil, nil, nil
+	a.lastWrite = time.Now()
+	a.mu.Unlock()
+
+	return a.write(session, after)
+}
+
+// Cancel drops a pending save of the session, e.g. when it is deleted.
+func (a *Autosaver) Cancel(sessionID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending == nil || a.pending.ID != sessionID {
+		return
+	}
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+	a.pending, a.after, a.timer = nil, nil, nil
+}
+
+func (a *Autosaver) write(session *Session, after []func()) error {
+	if session == nil {
+		return nil
+	}
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+
+	rolled := session.rolled
+	if err := a.manager.Save(session); err != nil {
+		return err
+	}
+	if session.rolled > rolled {
+		a.mu.Lock()
+		if a.archived == nil {
+			a.archived = make(map[string]archivedState)
+		}
+		a.archived[session.ID] = archivedState{rolled: session.rolled, archived: session.Archived}
+		a.mu.Unlock()
+	}
+	for _, fn := range after {
+		fn()
+	}
+	return nil
+}
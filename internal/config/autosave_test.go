@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAutosaverCoalesces tests that saves within the interval are written
+// together after a first save written at once
+func TestAutosaverCoalesces(t *testing.T) {
+	manager := newTestSessionManager(t)
+	saver := manager.NewAutosaver(300 * time.Millisecond)
+	session := manager.NewSession()
+
+	first := make(chan time.Time, 1)
+	rest := make(chan time.Time, 3)
+	start := time.Now()
+	session.Messages = numberedMessages(1, 1)
+	saver.Save(session, func() { first <- time.Now() })
+	select {
+	case at := <-first:
+		assert.Less(t, at.Sub(start), 300*time.Millisecond, "a save after a quiet spell is written at once")
+	case <-time.After(time.Second):
+		t.Fatal("first save was not written")
+	}
+
+	for i := 2; i <= 4; i++ {
+		session.Messages = numberedMessages(1, i)
+		saver.Save(session, func() { rest <- time.Now() })
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case at := <-rest:
+			assert.GreaterOrEqual(t, at.Sub(start), 300*time.Millisecond, "later saves wait for the interval")
+		case <-time.After(2 * time.Second):
+			t.Fatal("coalesced save was not written")
+		}
+	}
+
+	loaded, err := manager.Load(session.ID)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Messages, 4)
+}
+
+// TestAutosaverFlush tests writing a waiting save straight away and
+// dropping one for a deleted session
+func TestAutosaverFlush(t *testing.T) {
+	manager := newTestSessionManager(t)
+	saver := manager.NewAutosaver(time.Hour)
+	session := manager.NewSession()
+
+	saved := make(chan struct{}, 1)
+	saver.Save(session, func() { saved <- struct{}{} })
+	<-saved
+
+	session.Messages = numberedMessages(1, 2)
+	flushed := false
+	saver.Save(session, func() { flushed = true })
+	require.NoError(t, saver.Flush())
+	assert.True(t, flushed)
+	loaded, err := manager.Load(session.ID)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Messages, 2)
+
+	saver.Save(session, nil)
+	saver.Cancel(session.ID)
+	require.NoError(t, manager.Delete(session.ID))
+	require.NoError(t, saver.Flush())
+	_, err = manager.Load(session.ID)
+	assert.Error(t, err, "a cancelled save does not bring the session back")
+}
+
+// TestAutosaverSavesACopy tests that the session can change while its save
+// is written, and that messages the save archived are dropped from it on
+// the next save
+func TestAutosaverSavesACopy(t *testing.T) {
+	manager := newTestSessionManager(t)
+	manager.SetMaxMessages(8)
+	saver := manager.NewAutosaver(time.Hour)
+	session := manager.NewSession()
+
+	saved := make(chan struct{}, 1)
+	session.Messages = numberedMessages(1, 9)
+	saver.Save(session, func() { saved <- struct{}{} })
+	session.Messages = append(session.Messages, numberedMessages(10, 10)...) // While the copy is written
+	<-saved
+	assert.Len(t, session.Messages, 10, "the rollover happens on the copy")
+
+	session.SetMessagesRaw(numberedMessages(1, 12))
+	saver.Save(session, nil)
+	require.NoError(t, saver.Flush())
+	require.Len(t, session.Messages, 9, "the first save's rollover is applied")
+	assert.Equal(t, "message 4", session.Messages[0].Content)
+	assert.Equal(t, 3, session.Archived)
+
+	full, err := manager.LoadFull(session.ID)
+	require.NoError(t, err)
+	require.Len(t, full.Messages, 12)
+	for i, msg := range full.Messages {
+		assert.Equal(t, fmt.Sprintf("message %d", i+1), msg.Content)
+	}
+}
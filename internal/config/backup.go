@@ -0,0 +1,453 @@
+// Package config provides configuration management for Celeste CLI.
+// This file handles full backups of Celeste's config and data for moving to
+// another machine.
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// BackupFormatVersion is the archive layout version written to the manifest.
+// Bump it when the layout changes in a way older versions can't read.
+const BackupFormatVersion = 1
+
+// maxBackupEntrySize caps a single archive entry to guard against bad input.
+const maxBackupEntrySize = 64 << 20
+
+const backupManifestName = "manifest.json"
+
+// backupDataFiles are the top-level files carried in a backup.
+var backupDataFiles = []string{"notes.json", "reminders.json", "skills.json", "tarot_history.json"}
+
+// workspaceDataFiles are the skill data files carried for each named
+// workspace, alongside its qr_codes/*.png.
+var workspaceDataFiles = []string{"notes.json", "reminders.json", "tarot_history.json"}
+
+// backupSecretsFile is only included with IncludeSecrets.
+const backupSecretsFile = "secrets.json"
+
+// backupFilePath returns where a top-level backup file lives: settings in the
+// config directory, notes, reminders and tarot history in the data directory.
+func backupFilePath(name string) string {
+	if name == "skills.json" || name == backupSecretsFile {
+		return paths.Config(name)
+	}
+	return paths.Data(name)
+}
+
+// secretKeys are the skills.json fields removed unless secrets are included.
+var secretKeys = []string{
+	"api_key",
+	"venice_api_key",
+	"tarot_auth_token",
+	"twitter_bearer_token",
+	"twitter_api_key",
+	"twitter_api_secret",
+	"twitter_access_token",
+	"twitter_access_token_secret",
+	"twitch_client_secret",
+	"youtube_api_key",
+	"discord_webhook_url",
+	"mastodon_access_token",
+	"geolocation_token",
+	"ipfs_api_key",
+	"ipfs_api_secret",
+	"alchemy_api_key",
+	"blockmon_alchemy_api_key",
+}
+
+// BackupManifest describes the contents of a backup archive.
+type BackupManifest struct {
+	FormatVersion   int       `json:"format_version"`
+	CelesteVersion  string    `json:"celeste_version"`
+	CreatedAt       time.Time `json:"created_at"`
+	IncludesSecrets bool      `json:"includes_secrets"`
+	Sessions        int       `json:"sessions"`
+	Files           []string  `json:"files"`
+}
+
+// BackupOptions controls what ExportBackup writes.
+type BackupOptions struct {
+	Version        string // Celeste version recorded in the manifest
+	IncludeSecrets bool   // Include secrets.json and unredacted skills.json
+}
+
+// ImportOptions controls how ImportBackup resolves conflicts.
+type ImportOptions struct {
+	Overwrite bool // Replace existing sessions and files instead of skipping them
+}
+
+// ImportResult summarizes what ImportBackup did.
+type ImportResult struct {
+	Manifest         BackupManifest
+	SessionsImported []string
+	SessionsSkipped  []string
+	FilesImported    []string
+	FilesSkipped     []string
+
+	// Errors maps archive entries that could not be imported to the reason.
+	Errors map[string]string
+}
+
+// ExportBackup writes a gzipped tarball of sessions, skill settings and the
+// skill data of every workspace (notes, reminders, tarot history, QR codes)
+// to w. API keys are left out unless opts.IncludeSecrets is set.
+func ExportBackup(w io.Writer, opts BackupOptions) (*BackupManifest, error) {
+	manifest := &BackupManifest{
+		FormatVersion:   BackupFormatVersion,
+		CelesteVersion:  opts.Version,
+		CreatedAt:       time.Now(),
+		IncludesSecrets: opts.IncludeSecrets,
+	}
+
+	entries := make(map[string][]byte)
+
+	sessionFiles, err := filepath.Glob(paths.Data("sessions", "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, file := range sessionFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read session %s: %w", filepath.Base(file), err)
+		}
+		entries["sessions/"+filepath.Base(file)] = data
+		if !isArchive(file) {
+			manifest.Sessions++
+		}
+	}
+
+	files := backupDataFiles
+	if opts.IncludeSecrets {
+		files = append(append([]string{}, files...), backupSecretsFile)
+	}
+	for _, name := range files {
+		data, err := os.ReadFile(backupFilePath(name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if name == "skills.json" && !opts.IncludeSecrets {
+			if data, err = redactSecrets(data); err != nil {
+				return nil, fmt.Errorf("failed to redact skills.json: %w", err)
+			}
+		}
+		entries[name] = data
+		manifest.Files = append(manifest.Files, name)
+	}
+
+	workspaceFiles, err := listWorkspaceFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range workspaceFiles {
+		data, err := os.ReadFile(paths.Data(strings.Split(name, "/")...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		entries[name] = data
+		manifest.Files = append(manifest.Files, name)
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	// The manifest goes first so readers can check compatibility early
+	if err := writeTarEntry(tw, backupManifestName, manifestData, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeTarEntry(tw, name, entries[name], manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// ImportBackup unpacks an archive written by ExportBackup into the config
+// and data directories.
+// Existing sessions and files are skipped unless opts.Overwrite is set.
+func ImportBackup(r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	entries, err := readBackupEntries(r)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestData, ok := entries[backupManifestName]
+	if !ok {
+		return nil, fmt.Errorf("not a celeste backup: %s is missing", backupManifestName)
+	}
+	result := &ImportResult{Errors: make(map[string]string)}
+	if err := json.Unmarshal(manifestData, &result.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if result.Manifest.FormatVersion > BackupFormatVersion {
+		return nil, fmt.Errorf("backup was created by celeste %s (format %d); this version only reads format %d or older - please upgrade",
+			result.Manifest.CelesteVersion, result.Manifest.FormatVersion, BackupFormatVersion)
+	}
+
+	sessionsDir := paths.Data("sessions")
+	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data := entries[name]
+		switch {
+		case name == backupManifestName:
+			continue
+
+		case path.Dir(name) == "sessions" && isArchive(name):
+			// Archives go with their session and are not counted separately
+			dest := filepath.Join(sessionsDir, path.Base(name))
+			if fileExists(dest) && !opts.Overwrite {
+				continue
+			}
+			if err := os.WriteFile(dest, data, 0644); err != nil {
+				result.Errors[name] = err.Error()
+			}
+
+		case path.Dir(name) == "sessions" && path.Ext(name) == ".json":
+			var session Session
+			if err := json.Unmarshal(data, &session); err != nil {
+				result.Errors[name] = fmt.Sprintf("invalid session: %v", err)
+				continue
+			}
+			id := strings.TrimSuffix(path.Base(name), ".json")
+			if session.ID != id {
+				result.Errors[name] = fmt.Sprintf("session ID %q does not match file name", session.ID)
+				continue
+			}
+			dest := filepath.Join(sessionsDir, id+".json")
+			if fileExists(dest) && !opts.Overwrite {
+				result.SessionsSkipped = append(result.SessionsSkipped, id)
+				continue
+			}
+			if err := os.WriteFile(dest, data, 0644); err != nil {
+				result.Errors[name] = err.Error()
+				continue
+			}
+			result.SessionsImported = append(result.SessionsImported, id)
+
+		case isBackupDataFile(name):
+			dest := backupFilePath(name)
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				result.Errors[name] = err.Error()
+				continue
+			}
+			if fileExists(dest) && !opts.Overwrite {
+				result.FilesSkipped = append(result.FilesSkipped, name)
+				continue
+			}
+			perm := os.FileMode(0644)
+			if name == "skills.json" || name == backupSecretsFile {
+				perm = 0600
+			}
+			if err := os.WriteFile(dest, data, perm); err != nil {
+				result.Errors[name] = err.Error()
+				continue
+			}
+			result.FilesImported = append(result.FilesImported, name)
+
+		case isWorkspaceEntry(name):
+			dest := paths.Data(strings.Split(name, "/")...)
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				result.Errors[name] = err.Error()
+				continue
+			}
+			if fileExists(dest) && !opts.Overwrite {
+				result.FilesSkipped = append(result.FilesSkipped, name)
+				continue
+			}
+			if err := os.WriteFile(dest, data, 0644); err != nil {
+				result.Errors[name] = err.Error()
+				continue
+			}
+			result.FilesImported = append(result.FilesImported, name)
+
+		default:
+			result.Errors[name] = "unknown entry, ignored"
+		}
+	}
+
+	return result, nil
+}
+
+// readBackupEntries reads every regular file in a gzipped tarball.
+func readBackupEntries(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzip archive: %w", err)
+	}
+	defer gz.Close()
+
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxBackupEntrySize {
+			return nil, fmt.Errorf("archive entry %s is too large", header.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBackupEntrySize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		// path.Clean keeps "../" prefixes, which never match a known entry
+		entries[path.Clean(header.Name)] = data
+	}
+	return entries, nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// redactSecrets removes API keys and tokens from a skills.json document,
+// keeping every other field as-is.
+func redactSecrets(data []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, key := range secretKeys {
+		delete(fields, key)
+	}
+	return json.MarshalIndent(fields, "", "  ")
+}
+
+func isBackupDataFile(name string) bool {
+	if name == backupSecretsFile {
+		return true
+	}
+	for _, f := range backupDataFiles {
+		if name == f {
+			return true
+		}
+	}
+	return false
+}
+
+// listWorkspaceFiles returns the QR codes of the default workspace and the
+// skill data of every named workspace, as slash-separated paths relative to
+// the data directory. The default workspace's other files are backupDataFiles.
+func listWorkspaceFiles() ([]string, error) {
+	var files []string
+	addQRCodes := func(dir string) error {
+		matches, err := filepath.Glob(paths.Data(dir, "qr_codes", "*.png"))
+		if err != nil {
+			return fmt.Errorf("failed to list QR codes: %w", err)
+		}
+		for _, match := range matches {
+			files = append(files, path.Join(dir, "qr_codes", filepath.Base(match)))
+		}
+		return nil
+	}
+
+	if err := addQRCodes(""); err != nil {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(paths.Data("workspaces"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		workspace := path.Join("workspaces", dir.Name())
+		for _, name := range workspaceDataFiles {
+			if fileExists(paths.Data(workspace, name)) {
+				files = append(files, path.Join(workspace, name))
+			}
+		}
+		if err := addQRCodes(workspace); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// isWorkspaceEntry reports whether an archive entry is workspace skill data:
+// qr_codes/<file>.png, or workspaces/<name>/ followed by one of
+// workspaceDataFiles or qr_codes/<file>.png.
+func isWorkspaceEntry(name string) bool {
+	parts := strings.Split(name, "/")
+	if parts[0] == "workspaces" {
+		if len(parts) < 3 {
+			return false
+		}
+		parts = parts[2:]
+		if len(parts) == 1 {
+			for _, f := range workspaceDataFiles {
+				if parts[0] == f {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return len(parts) == 2 && parts[0] == "qr_codes" && path.Ext(parts[1]) == ".png"
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// useTempHome points the config paths at a fresh home directory.
+func useTempHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	// Keep the developer's own locations out of the default layout
+	t.Setenv(paths.EnvHome, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	return home
+}
+
+// useXDGHome sets up a temp home without ~/.celeste and with XDG base
+// directories, returning Celeste's config and data directories under them.
+func useXDGHome(t *testing.T) (configDir, dataDir string) {
+	t.Helper()
+	home := useTempHome(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	return filepath.Join(home, ".config", "celeste"), filepath.Join(home, ".local", "share", "celeste")
+}
+
+func seedBackupHome(t *testing.T) []*Session {
+	t.Helper()
+	useTempHome(t)
+	configDir, _, secretsFile, skillsFile := Paths()
+
+	manager := NewSessionManager()
+	var sessions []*Session
+	for i, text := range []string{"hello", "plan the trip"} {
+		s := manager.NewSession()
+		s.ID = fmt.Sprintf("%s%d", s.ID, i)
+		s.Name = text
+		s.Messages = []SessionMessage{
+			{Role: "user", Content: text, Timestamp: time.Now()},
+			{Role: "assistant", Content: "ok: " + text, Timestamp: time.Now()},
+		}
+		require.NoError(t, manager.Save(s))
+		sessions = append(sessions, s)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "notes.json"), []byte(`{"groceries":"milk"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "reminders.json"), []byte(`[]`), 0644))
+	require.NoError(t, os.WriteFile(skillsFile, []byte(`{"youtube_api_key":"yt-secret","weather_default_zip_code":"10001"}`), 0600))
+	require.NoError(t, os.WriteFile(secretsFile, []byte(`{"api_key":"sk-secret"}`), 0600))
+	return sessions
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	sessions := seedBackupHome(t)
+
+	var archive bytes.Buffer
+	manifest, err := ExportBackup(&archive, BackupOptions{Version: "1.2.3"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, manifest.Sessions)
+	assert.Equal(t, []string{"notes.json", "reminders.json", "skills.json"}, manifest.Files)
+
+	// Import into an empty home
+	home := useTempHome(t)
+	result, err := ImportBackup(bytes.NewReader(archive.Bytes()), ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", result.Manifest.CelesteVersion)
+	assert.Len(t, result.SessionsImported, 2)
+	assert.Empty(t, result.SessionsSkipped)
+	assert.Empty(t, result.Errors)
+
+	manager := NewSessionManager()
+	for _, want := range sessions {
+		got, err := manager.Load(want.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want.Name, got.Name)
+		assert.Equal(t, len(want.Messages), len(got.Messages))
+		for i := range want.Messages {
+			assert.Equal(t, want.Messages[i].Content, got.Messages[i].Content)
+			assert.True(t, want.Messages[i].Timestamp.Equal(got.Messages[i].Timestamp))
+		}
+	}
+
+	notes, err := os.ReadFile(filepath.Join(home, ".celeste", "notes.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"groceries":"milk"}`, string(notes))
+
+	// Secrets are left out by default
+	assert.NoFileExists(t, filepath.Join(home, ".celeste", "secrets.json"))
+	skills, err := os.ReadFile(filepath.Join(home, ".celeste", "skills.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"weather_default_zip_code":"10001"}`, string(skills))
+}
+
+func TestBackupIncludeSecrets(t *testing.T) {
+	seedBackupHome(t)
+
+	var archive bytes.Buffer
+	manifest, err := ExportBackup(&archive, BackupOptions{IncludeSecrets: true})
+	require.NoError(t, err)
+	assert.True(t, manifest.IncludesSecrets)
+
+	home := useTempHome(t)
+	_, err = ImportBackup(&archive, ImportOptions{})
+	require.NoError(t, err)
+
+	secrets, err := os.ReadFile(filepath.Join(home, ".celeste", "secrets.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"api_key":"sk-secret"}`, string(secrets))
+
+	skills, err := os.ReadFile(filepath.Join(home, ".celeste", "skills.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(skills), "yt-secret")
+}
+
+func TestBackupImportConflicts(t *testing.T) {
+	sessions := seedBackupHome(t)
+
+	var archive bytes.Buffer
+	_, err := ExportBackup(&archive, BackupOptions{})
+	require.NoError(t, err)
+
+	// Change one session locally; importing must not clobber it
+	manager := NewSessionManager()
+	local := sessions[0]
+	local.Name = "edited locally"
+	require.NoError(t, manager.Save(local))
+
+	result, err := ImportBackup(bytes.NewReader(archive.Bytes()), ImportOptions{})
+	require.NoError(t, err)
+	assert.Len(t, result.SessionsSkipped, 2)
+	assert.Len(t, result.FilesSkipped, 3)
+	assert.Empty(t, result.SessionsImported)
+
+	got, err := manager.Load(local.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "edited locally", got.Name)
+
+	result, err = ImportBackup(bytes.NewReader(archive.Bytes()), ImportOptions{Overwrite: true})
+	require.NoError(t, err)
+	assert.Len(t, result.SessionsImported, 2)
+
+	got, err = manager.Load(local.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", got.Name)
+}
+
+func TestBackupWorkspaces(t *testing.T) {
+	seedBackupHome(t)
+	stream := WorkspaceDir("stream")
+	require.NoError(t, os.MkdirAll(filepath.Join(stream, "qr_codes"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(stream, "notes.json"), []byte(`{"setlist":"intro"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(stream, "reminders.json"), []byte(`[{"text":"go live"}]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(stream, "qr_codes", "qr_1.png"), []byte("png"), 0644))
+	require.NoError(t, os.MkdirAll(paths.Data("qr_codes"), 0755))
+	require.NoError(t, os.WriteFile(paths.Data("qr_codes", "qr_2.png"), []byte("png"), 0644))
+
+	var archive bytes.Buffer
+	manifest, err := ExportBackup(&archive, BackupOptions{})
+	require.NoError(t, err)
+	assert.Subset(t, manifest.Files, []string{
+		"qr_codes/qr_2.png",
+		"workspaces/stream/notes.json",
+		"workspaces/stream/reminders.json",
+		"workspaces/stream/qr_codes/qr_1.png",
+	})
+
+	home := useTempHome(t)
+	result, err := ImportBackup(&archive, ImportOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+
+	stream = filepath.Join(home, ".celeste", "workspaces", "stream")
+	notes, err := os.ReadFile(filepath.Join(stream, "notes.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"setlist":"intro"}`, string(notes))
+	assert.FileExists(t, filepath.Join(stream, "reminders.json"))
+	assert.FileExists(t, filepath.Join(stream, "qr_codes", "qr_1.png"))
+	assert.FileExists(t, filepath.Join(home, ".celeste", "qr_codes", "qr_2.png"))
+
+	info := workspaceInfo("stream")
+	assert.Equal(t, 1, info.Notes)
+	assert.Equal(t, 1, info.Reminders)
+	assert.Equal(t, 1, info.QRCodes)
+}
+
+// TestBackupImportXDG tests that a backup from ~/.celeste is restored into
+// the XDG directories: settings with the config, sessions and notes with the data
+func TestBackupImportXDG(t *testing.T) {
+	sessions := seedBackupHome(t)
+	var archive bytes.Buffer
+	_, err := ExportBackup(&archive, BackupOptions{IncludeSecrets: true})
+	require.NoError(t, err)
+
+	configDir, dataDir := useXDGHome(t)
+	result, err := ImportBackup(&archive, ImportOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+
+	for _, s := range sessions {
+		assert.FileExists(t, filepath.Join(dataDir, "sessions", s.ID+".json"))
+	}
+	assert.FileExists(t, filepath.Join(dataDir, "notes.json"))
+	assert.FileExists(t, filepath.Join(dataDir, "reminders.json"))
+	assert.FileExists(t, filepath.Join(configDir, "skills.json"))
+	assert.FileExists(t, filepath.Join(configDir, "secrets.json"))
+	assert.NoFileExists(t, filepath.Join(configDir, "notes.json"))
+	assert.NoDirExists(t, filepath.Join(configDir, "sessions"))
+}
+
+// buildArchive writes the given entries as a gzipped tarball.
+func buildArchive(t *testing.T, entries map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		require.NoError(t, writeTarEntry(tw, name, []byte(content), time.Now()))
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return &buf
+}
+
+func TestBackupImportRejectsNewerFormat(t *testing.T) {
+	useTempHome(t)
+	manifest, err := json.Marshal(BackupManifest{FormatVersion: BackupFormatVersion + 1, CelesteVersion: "9.0.0"})
+	require.NoError(t, err)
+
+	_, err = ImportBackup(buildArchive(t, map[string]string{"manifest.json": string(manifest)}), ImportOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "9.0.0")
+}
+
+func TestBackupImportIgnoresUnsafeEntries(t *testing.T) {
+	home := useTempHome(t)
+	manifest, err := json.Marshal(BackupManifest{FormatVersion: BackupFormatVersion})
+	require.NoError(t, err)
+
+	result, err := ImportBackup(buildArchive(t, map[string]string{
+		"manifest.json":                 string(manifest),
+		"../escape.json":                "{}",
+		"sessions/../evil.json":         "{}",
+		"sessions/bad.json":             `{"id":"other"}`,
+		"workspaces/stream/skills.json": "{}",
+		"workspaces/notes.json":         "{}",
+	}), ImportOptions{})
+	require.NoError(t, err)
+
+	assert.Len(t, result.Errors, 5)
+	assert.NoFileExists(t, filepath.Join(home, ".celeste", "workspaces", "stream", "skills.json"))
+	assert.NoFileExists(t, filepath.Join(home, "escape.json"))
+	assert.NoFileExists(t, filepath.Join(home, ".celeste", "evil.json"))
+	assert.NoFileExists(t, filepath.Join(home, ".celeste", "sessions", "bad.json"))
+}
+
+func TestBackupImportRequiresManifest(t *testing.T) {
+	useTempHome(t)
+	_, err := ImportBackup(buildArchive(t, map[string]string{"notes.json": "{}"}), ImportOptions{})
+	require.Error(t, err)
+}
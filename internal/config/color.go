@@ -0,0 +1,26 @@
+package config
+
+import "os"
+
+// NoColorEnvVar disables colored output when set to any non-empty value.
+// See https://no-color.org.
+const NoColorEnvVar = "NO_COLOR"
+
+// colorDisabled is set by the --no-color global flag.
+var colorDisabled bool
+
+// DisableColor turns colored output off for the rest of the process.
+// Called by main when --no-color is passed.
+func DisableColor() {
+	colorDisabled = true
+}
+
+// ColorEnabled reports whether output may contain ANSI color escapes.
+// Every code path that colors output (TUI theme, corruption animation,
+// terminal prompts) should go through this check.
+func ColorEnabled() bool {
+	if colorDisabled {
+		return false
+	}
+	return os.Getenv(NoColorEnvVar) == ""
+}
@@ -8,7 +8,9 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/skills"
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/skills"
 )
 
 // Config holds all configuration for Celeste CLI.
@@ -18,8 +20,18 @@ type Config struct {
 	BaseURL      string `json:"base_url"`
 	Model        string `json:"model"`
 	Timeout      int    `json:"timeout"`                 // seconds
+	HTTPTimeout  int    `json:"http_timeout,omitempty"`  // seconds, for skill and integration requests (default 15)
 	ContextLimit int    `json:"context_limit,omitempty"` // Optional: Override context window size
 
+	// Sampling defaults for chat requests (unset = provider default)
+	Temperature *float64 `json:"temperature,omitempty"` // 0-2
+	TopP        *float64 `json:"top_p,omitempty"`       // 0-1
+	MaxTokens   int      `json:"max_tokens,omitempty"`  // Cap on reply length
+
+	// Provider-specific fields added to every chat request to this endpoint,
+	// e.g. DigitalOcean agent retrieval options
+	ExtraBody map[string]interface{} `json:"extra_body,omitempty"`
+
 	// Google Cloud authentication (for Gemini/Vertex AI)
 	GoogleCredentialsFile string `json:"google_credentials_file,omitempty"` // Path to service account JSON file
 	GoogleUseADC          bool   `json:"google_use_adc,omitempty"`          // Use Application Default Credentials
@@ -27,18 +39,59 @@ type Config struct {
 	// Runtime-detected provider (not persisted to config file)
 	Provider string `json:"-"` // Detected from BaseURL at runtime
 
+	// Profile is the named config this was loaded from ("" for the default config)
+	Profile string `json:"-"`
+
+	// Spend tracking
+	MonthlyBudgetUSD float64 `json:"monthly_budget_usd,omitempty"` // Warn at 80%, confirm at 100%
+
+	// Update settings
+	DisableUpdateCheck bool `json:"disable_update_check,omitempty"` // Skip the daily check for a newer release
+
+	// Skill settings
+	WatchSkills        bool   `json:"watch_skills,omitempty"`          // Reload ~/.celeste/skills when files change
+	ToolResultFormat   string `json:"tool_result_format,omitempty"`    // Wrapper for skill results sent to the model: "fence" or "xml"
+	ToolResultMaxBytes int    `json:"tool_result_max_bytes,omitempty"` // Truncate longer skill results (default 8192)
+
 	// Persona settings
 	SkipPersonaPrompt bool `json:"skip_persona_prompt"`
 
+	// Image attachment settings
+	ImageMaxMB int `json:"image_max_mb,omitempty"` // Largest local image /image and /attach will send (default 20)
+
+	// Content settings
+	ContentOverflow string   `json:"content_overflow,omitempty"` // Over-limit `celeste content`: "shorten" (ask the model) or "truncate"
+	ContentBanned   []string `json:"content_banned,omitempty"`   // Phrases the content lint flags, on top of the persona's refuse list
+
+	// Transcript settings (classic CLI runs)
+	TranscriptLogPath string `json:"transcript_log_path,omitempty"` // Append every message/content run here; empty disables
+	TranscriptMaxMB   int    `json:"transcript_max_mb,omitempty"`   // Rotate the log past this size (default 10)
+	TranscriptNSFW    bool   `json:"transcript_nsfw,omitempty"`     // Also log runs against Venice.ai (NSFW)
+
+	// Session settings
+	AutosaveIntervalMS int `json:"autosave_interval_ms,omitempty"` // Shortest time between TUI session saves (default 2000)
+	MaxSessionMessages int `json:"max_session_messages,omitempty"` // Archive older messages past this many (default 500)
+	InputHistorySize   int `json:"input_history_size,omitempty"`   // Chat inputs kept for ↑ across runs (default 1000)
+
+	// Notification settings
+	NotifyOnComplete   bool `json:"notify_on_complete,omitempty"`   // Bell and desktop notification when a long generation finishes
+	NotifyAfterSeconds int  `json:"notify_after_seconds,omitempty"` // Only for generations that took at least this long (default 10)
+
 	// Streaming settings
 	SimulateTyping bool `json:"simulate_typing"`
 	TypingSpeed    int  `json:"typing_speed"` // chars per second
 
+	// Corruption animation settings
+	AnimationStyle   string `json:"animation_style,omitempty"`    // glitch, subtle, minimal, off
+	AnimationSpeedMS int    `json:"animation_speed_ms,omitempty"` // Overrides the preset frame interval
+	AnimationGlyphs  string `json:"animation_glyphs,omitempty"`   // Overrides the preset corruption characters
+
 	// Venice.ai settings (for NSFW mode)
 	VeniceAPIKey     string `json:"venice_api_key,omitempty"`
 	VeniceBaseURL    string `json:"venice_base_url,omitempty"`
 	VeniceModel      string `json:"venice_model,omitempty"`       // Chat model (venice-uncensored)
 	VeniceImageModel string `json:"venice_image_model,omitempty"` // Image model (lustify-sdxl)
+	VeniceUpscaler   string `json:"venice_upscaler,omitempty"`    // Upscaler model (upscaler)
 
 	// Tarot settings
 	TarotFunctionURL string `json:"tarot_function_url,omitempty"`
@@ -53,6 +106,12 @@ type Config struct {
 
 	// Weather settings
 	WeatherDefaultZipCode string `json:"weather_default_zip_code,omitempty"`
+	WeatherAlerts         string `json:"weather_alerts,omitempty"` // "nws" (default) or "off"
+	WeatherUnits          string `json:"weather_units,omitempty"`  // "auto" (default), "metric" or "imperial"
+
+	// IP geolocation settings (get_location, and get_weather without a zip code)
+	GeolocationProvider string `json:"geolocation_provider,omitempty"` // "ipapi" (default), "ipinfo", "ip-api" or "off"
+	GeolocationToken    string `json:"geolocation_token,omitempty"`    // ipinfo.io access token
 
 	// Twitch settings
 	TwitchClientID        string `json:"twitch_client_id,omitempty"`
@@ -63,6 +122,13 @@ type Config struct {
 	YouTubeAPIKey         string `json:"youtube_api_key,omitempty"`
 	YouTubeDefaultChannel string `json:"youtube_default_channel,omitempty"`
 
+	// Discord settings
+	DiscordWebhookURL string `json:"discord_webhook_url,omitempty"`
+
+	// Mastodon settings
+	MastodonInstanceURL string `json:"mastodon_instance_url,omitempty"`
+	MastodonAccessToken string `json:"mastodon_access_token,omitempty"`
+
 	// IPFS settings
 	IPFSProvider       string `json:"ipfs_provider,omitempty"` // "infura", "pinata", "custom"
 	IPFSAPIKey         string `json:"ipfs_api_key,omitempty"`
@@ -82,6 +148,10 @@ type Config struct {
 	BlockmonDefaultNetwork      string `json:"blockmon_default_network,omitempty"`
 	BlockmonPollIntervalSeconds int    `json:"blockmon_poll_interval_seconds,omitempty"`
 
+	// Skill parameter defaults: skill name -> parameter -> value, filled in
+	// when the model leaves a declared parameter out
+	SkillDefaults map[string]map[string]any `json:"skill_defaults,omitempty"`
+
 	// Wallet security settings
 	WalletSecurityEnabled      bool   `json:"wallet_security_enabled,omitempty"`
 	WalletSecurityPollInterval int    `json:"wallet_security_poll_interval,omitempty"` // seconds
@@ -104,8 +174,7 @@ func DefaultConfig() *Config {
 
 // Paths returns the configuration directory and file paths.
 func Paths() (configDir, configFile, secretsFile, skillsFile string) {
-	homeDir, _ := os.UserHomeDir()
-	configDir = filepath.Join(homeDir, ".celeste")
+	configDir = paths.ConfigDir()
 	configFile = filepath.Join(configDir, "config.json")
 	secretsFile = filepath.Join(configDir, "secrets.json")
 	skillsFile = filepath.Join(configDir, "skills.json")
@@ -115,8 +184,7 @@ func Paths() (configDir, configFile, secretsFile, skillsFile string) {
 // NamedConfigPath returns the path for a named config file.
 // If name is empty, returns the default config path.
 func NamedConfigPath(name string) string {
-	homeDir, _ := os.UserHomeDir()
-	configDir := filepath.Join(homeDir, ".celeste")
+	configDir := paths.ConfigDir()
 	if name == "" {
 		return filepath.Join(configDir, "config.json")
 	}
@@ -148,6 +216,7 @@ func SaveSkillsConfig(skillsConfig *Config) error {
 		VeniceAPIKey:                skillsConfig.VeniceAPIKey,
 		VeniceBaseURL:               skillsConfig.VeniceBaseURL,
 		VeniceModel:                 skillsConfig.VeniceModel,
+		VeniceUpscaler:              skillsConfig.VeniceUpscaler,
 		TarotFunctionURL:            skillsConfig.TarotFunctionURL,
 		TarotAuthToken:              skillsConfig.TarotAuthToken,
 		TwitterBearerToken:          skillsConfig.TwitterBearerToken,
@@ -156,10 +225,17 @@ func SaveSkillsConfig(skillsConfig *Config) error {
 		TwitterAccessToken:          skillsConfig.TwitterAccessToken,
 		TwitterAccessTokenSecret:    skillsConfig.TwitterAccessTokenSecret,
 		WeatherDefaultZipCode:       skillsConfig.WeatherDefaultZipCode,
+		WeatherAlerts:               skillsConfig.WeatherAlerts,
+		WeatherUnits:                skillsConfig.WeatherUnits,
+		GeolocationProvider:         skillsConfig.GeolocationProvider,
+		GeolocationToken:            skillsConfig.GeolocationToken,
 		TwitchClientID:              skillsConfig.TwitchClientID,
 		TwitchDefaultStreamer:       skillsConfig.TwitchDefaultStreamer,
 		YouTubeAPIKey:               skillsConfig.YouTubeAPIKey,
 		YouTubeDefaultChannel:       skillsConfig.YouTubeDefaultChannel,
+		DiscordWebhookURL:           skillsConfig.DiscordWebhookURL,
+		MastodonInstanceURL:         skillsConfig.MastodonInstanceURL,
+		MastodonAccessToken:         skillsConfig.MastodonAccessToken,
 		IPFSProvider:                skillsConfig.IPFSProvider,
 		IPFSAPIKey:                  skillsConfig.IPFSAPIKey,
 		IPFSAPISecret:               skillsConfig.IPFSAPISecret,
@@ -173,6 +249,7 @@ func SaveSkillsConfig(skillsConfig *Config) error {
 		BlockmonWebhookURL:          skillsConfig.BlockmonWebhookURL,
 		BlockmonDefaultNetwork:      skillsConfig.BlockmonDefaultNetwork,
 		BlockmonPollIntervalSeconds: skillsConfig.BlockmonPollIntervalSeconds,
+		SkillDefaults:               skillsConfig.SkillDefaults,
 	}
 
 	data, err := json.MarshalIndent(skillsOnly, "", "  ")
@@ -180,7 +257,13 @@ func SaveSkillsConfig(skillsConfig *Config) error {
 		return fmt.Errorf("failed to marshal skills config: %w", err)
 	}
 
-	return os.WriteFile(skillsFile, data, 0600) // Restrictive permissions for secrets
+	// Locked and atomic, as every running celeste shares skills.json
+	unlock, err := filelock.Lock(skillsFile, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return filelock.WriteFile(skillsFile, data, 0600) // Restrictive permissions for secrets
 }
 
 // LoadNamed loads configuration from a named config file.
@@ -202,6 +285,8 @@ func LoadNamed(name string) (*Config, error) {
 	if err := json.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse config '%s': %w", name, err)
 	}
+	config.Profile = name
+	config.normalizeBaseURL()
 
 	// Load shared skills.json (for all skill configurations)
 	if skillsConfig, err := LoadSkillsConfig(); err == nil {
@@ -215,6 +300,9 @@ func LoadNamed(name string) (*Config, error) {
 		if skillsConfig.VeniceModel != "" {
 			config.VeniceModel = skillsConfig.VeniceModel
 		}
+		if skillsConfig.VeniceUpscaler != "" {
+			config.VeniceUpscaler = skillsConfig.VeniceUpscaler
+		}
 		if skillsConfig.TarotFunctionURL != "" {
 			config.TarotFunctionURL = skillsConfig.TarotFunctionURL
 		}
@@ -239,6 +327,18 @@ func LoadNamed(name string) (*Config, error) {
 		if skillsConfig.WeatherDefaultZipCode != "" {
 			config.WeatherDefaultZipCode = skillsConfig.WeatherDefaultZipCode
 		}
+		if skillsConfig.WeatherAlerts != "" {
+			config.WeatherAlerts = skillsConfig.WeatherAlerts
+		}
+		if skillsConfig.WeatherUnits != "" {
+			config.WeatherUnits = skillsConfig.WeatherUnits
+		}
+		if skillsConfig.GeolocationProvider != "" {
+			config.GeolocationProvider = skillsConfig.GeolocationProvider
+		}
+		if skillsConfig.GeolocationToken != "" {
+			config.GeolocationToken = skillsConfig.GeolocationToken
+		}
 		if skillsConfig.TwitchClientID != "" {
 			config.TwitchClientID = skillsConfig.TwitchClientID
 		}
@@ -254,6 +354,15 @@ func LoadNamed(name string) (*Config, error) {
 		if skillsConfig.YouTubeDefaultChannel != "" {
 			config.YouTubeDefaultChannel = skillsConfig.YouTubeDefaultChannel
 		}
+		if skillsConfig.DiscordWebhookURL != "" {
+			config.DiscordWebhookURL = skillsConfig.DiscordWebhookURL
+		}
+		if skillsConfig.MastodonInstanceURL != "" {
+			config.MastodonInstanceURL = skillsConfig.MastodonInstanceURL
+		}
+		if skillsConfig.MastodonAccessToken != "" {
+			config.MastodonAccessToken = skillsConfig.MastodonAccessToken
+		}
 		if skillsConfig.IPFSProvider != "" {
 			config.IPFSProvider = skillsConfig.IPFSProvider
 		}
@@ -293,6 +402,9 @@ func LoadNamed(name string) (*Config, error) {
 		if skillsConfig.BlockmonPollIntervalSeconds > 0 {
 			config.BlockmonPollIntervalSeconds = skillsConfig.BlockmonPollIntervalSeconds
 		}
+		if skillsConfig.SkillDefaults != nil {
+			config.SkillDefaults = skillsConfig.SkillDefaults
+		}
 	}
 
 	return config, nil
@@ -339,6 +451,8 @@ func Load() (*Config, error) {
 		}
 	}
 
+	config.normalizeBaseURL()
+
 	// Load secrets file (for API keys - backward compatibility)
 	if data, err := os.ReadFile(secretsFile); err == nil {
 		var secrets Config
@@ -361,6 +475,9 @@ func Load() (*Config, error) {
 		if skillsConfig.VeniceModel != "" {
 			config.VeniceModel = skillsConfig.VeniceModel
 		}
+		if skillsConfig.VeniceUpscaler != "" {
+			config.VeniceUpscaler = skillsConfig.VeniceUpscaler
+		}
 		if skillsConfig.TarotFunctionURL != "" {
 			config.TarotFunctionURL = skillsConfig.TarotFunctionURL
 		}
@@ -385,6 +502,18 @@ func Load() (*Config, error) {
 		if skillsConfig.WeatherDefaultZipCode != "" {
 			config.WeatherDefaultZipCode = skillsConfig.WeatherDefaultZipCode
 		}
+		if skillsConfig.WeatherAlerts != "" {
+			config.WeatherAlerts = skillsConfig.WeatherAlerts
+		}
+		if skillsConfig.WeatherUnits != "" {
+			config.WeatherUnits = skillsConfig.WeatherUnits
+		}
+		if skillsConfig.GeolocationProvider != "" {
+			config.GeolocationProvider = skillsConfig.GeolocationProvider
+		}
+		if skillsConfig.GeolocationToken != "" {
+			config.GeolocationToken = skillsConfig.GeolocationToken
+		}
 		if skillsConfig.TwitchClientID != "" {
 			config.TwitchClientID = skillsConfig.TwitchClientID
 		}
@@ -400,6 +529,15 @@ func Load() (*Config, error) {
 		if skillsConfig.YouTubeDefaultChannel != "" {
 			config.YouTubeDefaultChannel = skillsConfig.YouTubeDefaultChannel
 		}
+		if skillsConfig.DiscordWebhookURL != "" {
+			config.DiscordWebhookURL = skillsConfig.DiscordWebhookURL
+		}
+		if skillsConfig.MastodonInstanceURL != "" {
+			config.MastodonInstanceURL = skillsConfig.MastodonInstanceURL
+		}
+		if skillsConfig.MastodonAccessToken != "" {
+			config.MastodonAccessToken = skillsConfig.MastodonAccessToken
+		}
 		if skillsConfig.IPFSProvider != "" {
 			config.IPFSProvider = skillsConfig.IPFSProvider
 		}
@@ -439,6 +577,9 @@ func Load() (*Config, error) {
 		if skillsConfig.BlockmonPollIntervalSeconds > 0 {
 			config.BlockmonPollIntervalSeconds = skillsConfig.BlockmonPollIntervalSeconds
 		}
+		if skillsConfig.SkillDefaults != nil {
+			config.SkillDefaults = skillsConfig.SkillDefaults
+		}
 	}
 
 	return config, nil
@@ -520,12 +661,17 @@ func (l *ConfigLoader) GetVeniceConfig() (skills.VeniceConfig, error) {
 		imageModel = "lustify-sdxl" // Default NSFW image generation model
 	}
 
+	upscaler := l.config.VeniceUpscaler
+	if upscaler == "" {
+		upscaler = "upscaler"
+	}
+
 	return skills.VeniceConfig{
 		APIKey:     l.config.VeniceAPIKey,
 		BaseURL:    baseURL,
 		Model:      model,
 		ImageModel: imageModel,
-		Upscaler:   "upscaler",
+		Upscaler:   upscaler,
 	}, nil
 }
 
@@ -533,6 +679,21 @@ func (l *ConfigLoader) GetVeniceConfig() (skills.VeniceConfig, error) {
 func (l *ConfigLoader) GetWeatherConfig() (skills.WeatherConfig, error) {
 	return skills.WeatherConfig{
 		DefaultZipCode: l.config.WeatherDefaultZipCode,
+		AlertsSource:   l.config.WeatherAlerts,
+		Units:          l.config.WeatherUnits,
+	}, nil
+}
+
+// GetLocationConfig returns IP geolocation configuration. It returns an
+// error when geolocation_provider is "off".
+func (l *ConfigLoader) GetLocationConfig() (skills.LocationConfig, error) {
+	if l.config.GeolocationProvider == "off" {
+		return skills.LocationConfig{}, fmt.Errorf("IP geolocation is turned off")
+	}
+
+	return skills.LocationConfig{
+		Provider: l.config.GeolocationProvider,
+		Token:    l.config.GeolocationToken,
 	}, nil
 }
 
@@ -571,6 +732,46 @@ func (l *ConfigLoader) GetYouTubeConfig() (skills.YouTubeConfig, error) {
 	}, nil
 }
 
+// GetTwitterConfig returns X (Twitter) API credentials. Posting needs the
+// OAuth 1.0a consumer key and secret plus the account's access token and secret.
+func (l *ConfigLoader) GetTwitterConfig() (skills.TwitterConfig, error) {
+	if l.config.TwitterAPIKey == "" || l.config.TwitterAPISecret == "" ||
+		l.config.TwitterAccessToken == "" || l.config.TwitterAccessTokenSecret == "" {
+		return skills.TwitterConfig{}, fmt.Errorf("Twitter API credentials not configured")
+	}
+
+	return skills.TwitterConfig{
+		BearerToken:       l.config.TwitterBearerToken,
+		APIKey:            l.config.TwitterAPIKey,
+		APISecret:         l.config.TwitterAPISecret,
+		AccessToken:       l.config.TwitterAccessToken,
+		AccessTokenSecret: l.config.TwitterAccessTokenSecret,
+	}, nil
+}
+
+// GetDiscordConfig returns Discord webhook configuration.
+func (l *ConfigLoader) GetDiscordConfig() (skills.DiscordConfig, error) {
+	if l.config.DiscordWebhookURL == "" {
+		return skills.DiscordConfig{}, fmt.Errorf("Discord webhook URL not configured")
+	}
+
+	return skills.DiscordConfig{
+		WebhookURL: l.config.DiscordWebhookURL,
+	}, nil
+}
+
+// GetMastodonConfig returns Mastodon instance configuration.
+func (l *ConfigLoader) GetMastodonConfig() (skills.MastodonConfig, error) {
+	if l.config.MastodonInstanceURL == "" || l.config.MastodonAccessToken == "" {
+		return skills.MastodonConfig{}, fmt.Errorf("Mastodon instance URL and access token not configured")
+	}
+
+	return skills.MastodonConfig{
+		InstanceURL: l.config.MastodonInstanceURL,
+		AccessToken: l.config.MastodonAccessToken,
+	}, nil
+}
+
 // GetIPFSConfig returns IPFS configuration.
 func (l *ConfigLoader) GetIPFSConfig() (skills.IPFSConfig, error) {
 	if l.config.IPFSAPIKey == "" {
@@ -668,6 +869,28 @@ func (l *ConfigLoader) GetWalletSecurityConfig() (skills.WalletSecuritySettingsC
 	}, nil
 }
 
+// GetWorkspaceConfig returns where skill data is stored for this profile.
+func (l *ConfigLoader) GetWorkspaceConfig() (skills.WorkspaceConfig, error) {
+	name := l.config.Profile
+	if name == "" {
+		name = DefaultWorkspace
+	}
+	return skills.WorkspaceConfig{
+		Name: name,
+		Dir:  WorkspaceDir(l.config.Profile),
+	}, nil
+}
+
+// GetSkillDefaults returns the skill_defaults parameter values.
+func (l *ConfigLoader) GetSkillDefaults() (skills.SkillDefaults, error) {
+	return l.config.SkillDefaults, nil
+}
+
+// Sampling returns the configured sampling defaults.
+func (c *Config) Sampling() Sampling {
+	return Sampling{Temperature: c.Temperature, TopP: c.TopP, MaxTokens: c.MaxTokens}
+}
+
 // GetTimeout returns the configured timeout as a duration.
 func (c *Config) GetTimeout() time.Duration {
 	if c.Timeout <= 0 {
@@ -675,3 +898,38 @@ func (c *Config) GetTimeout() time.Duration {
 	}
 	return time.Duration(c.Timeout) * time.Second
 }
+
+// GetAutosaveInterval returns the configured time between TUI session
+// saves, or zero to use the built-in default.
+func (c *Config) GetAutosaveInterval() time.Duration {
+	if c.AutosaveIntervalMS <= 0 {
+		return 0
+	}
+	return time.Duration(c.AutosaveIntervalMS) * time.Millisecond
+}
+
+// GetInputHistorySize returns how many chat inputs to keep across runs.
+func (c *Config) GetInputHistorySize() int {
+	if c.InputHistorySize <= 0 {
+		return DefaultInputHistorySize
+	}
+	return c.InputHistorySize
+}
+
+// GetNotifyThreshold returns how long a generation must take before it is
+// announced, or zero to use the built-in default.
+func (c *Config) GetNotifyThreshold() time.Duration {
+	if c.NotifyAfterSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.NotifyAfterSeconds) * time.Second
+}
+
+// GetHTTPTimeout returns the configured skill and integration request
+// timeout, or zero to use the built-in default.
+func (c *Config) GetHTTPTimeout() time.Duration {
+	if c.HTTPTimeout <= 0 {
+		return 0
+	}
+	return time.Duration(c.HTTPTimeout) * time.Second
+}
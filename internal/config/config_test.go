@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,9 +29,9 @@ func TestDefaultConfig(t *testing.T) {
 
 // TestPaths tests config path generation
 func TestPaths(t *testing.T) {
+	homeDir := useTempHome(t)
 	configDir, configFile, secretsFile, skillsFile := Paths()
 
-	homeDir, _ := os.UserHomeDir()
 	expectedDir := filepath.Join(homeDir, ".celeste")
 
 	assert.Equal(t, expectedDir, configDir)
@@ -39,6 +40,47 @@ func TestPaths(t *testing.T) {
 	assert.Equal(t, filepath.Join(expectedDir, "skills.json"), skillsFile)
 }
 
+// TestLocations tests where config and data files land under each layout
+func TestLocations(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) (configDir, dataDir string)
+	}{
+		{"legacy", func(t *testing.T) (string, string) {
+			dir := filepath.Join(useTempHome(t), ".celeste")
+			return dir, dir
+		}},
+		{"xdg", useXDGHome},
+		{"celeste home", func(t *testing.T) (string, string) {
+			useXDGHome(t)
+			dir := filepath.Join(t.TempDir(), "celeste")
+			t.Setenv("CELESTE_HOME", dir)
+			return dir, dir
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configDir, dataDir := tt.setup(t)
+
+			cfg, err := Load()
+			require.NoError(t, err)
+			require.NoError(t, Save(cfg))
+			assert.FileExists(t, filepath.Join(configDir, "config.json"))
+			assert.Equal(t, filepath.Join(configDir, "config.work.json"), NamedConfigPath("work"))
+
+			manager := NewSessionManager()
+			session := manager.NewSession()
+			require.NoError(t, manager.Save(session))
+			assert.FileExists(t, filepath.Join(dataDir, "sessions", session.ID+".json"))
+
+			assert.Equal(t, filepath.Join(dataDir, "usage.json"), GetLedgerPath())
+			assert.Equal(t, filepath.Join(dataDir, "exports"), GetExportDir())
+			assert.Equal(t, dataDir, WorkspaceDir(DefaultWorkspace))
+			assert.Equal(t, filepath.Join(dataDir, "workspaces", "work"), WorkspaceDir("work"))
+		})
+	}
+}
+
 // TestNamedConfigPath tests named config path generation
 func TestNamedConfigPath(t *testing.T) {
 	tests := []struct {
@@ -516,6 +558,7 @@ func TestConfigLoader(t *testing.T) {
 	assert.Equal(t, "venice-key", veniceConfig.APIKey)
 	assert.Equal(t, "https://venice.example.com", veniceConfig.BaseURL)
 	assert.Equal(t, "venice-model", veniceConfig.Model)
+	assert.Equal(t, "upscaler", veniceConfig.Upscaler, "Upscaler should default when unset")
 
 	// Test GetTarotConfig
 	tarotConfig, err := loader.GetTarotConfig()
@@ -540,3 +583,70 @@ func TestConfigLoader(t *testing.T) {
 	assert.Equal(t, "youtube-key", youtubeConfig.APIKey)
 	assert.Equal(t, "test-channel", youtubeConfig.DefaultChannel)
 }
+
+// TestIsSafeMode tests safe mode detection from the environment and the global flag
+func TestIsSafeMode(t *testing.T) {
+	t.Cleanup(func() { safeModeForced = false })
+
+	for _, value := range []string{"", "0", "false", "off"} {
+		t.Setenv(SafeModeEnvVar, value)
+		assert.False(t, IsSafeMode(), "value %q should not enable safe mode", value)
+	}
+
+	for _, value := range []string{"1", "true", "TRUE", "yes", "on"} {
+		t.Setenv(SafeModeEnvVar, value)
+		assert.True(t, IsSafeMode(), "value %q should enable safe mode", value)
+	}
+
+	t.Setenv(SafeModeEnvVar, "")
+	EnableSafeMode()
+	assert.True(t, IsSafeMode())
+}
+
+// TestColorEnabled tests NO_COLOR handling and the --no-color global flag
+func TestColorEnabled(t *testing.T) {
+	t.Cleanup(func() { colorDisabled = false })
+
+	t.Setenv(NoColorEnvVar, "")
+	assert.True(t, ColorEnabled())
+
+	for _, value := range []string{"1", "true", "0"} {
+		t.Setenv(NoColorEnvVar, value)
+		assert.False(t, ColorEnabled(), "NO_COLOR=%q should disable color", value)
+	}
+
+	t.Setenv(NoColorEnvVar, "")
+	DisableColor()
+	assert.False(t, ColorEnabled())
+}
+
+// TestAnimationSettings tests animation preset resolution and overrides
+func TestAnimationSettings(t *testing.T) {
+	t.Cleanup(func() { currentAnimation = nil })
+
+	// Unset and unknown styles fall back to the default preset
+	for _, style := range []string{"", "sparkly"} {
+		anim := (&Config{AnimationStyle: style}).Animation()
+		assert.Equal(t, DefaultAnimationStyle, anim.Style)
+		assert.True(t, anim.Enabled)
+		assert.Nil(t, anim.Glyphs)
+	}
+
+	off := (&Config{AnimationStyle: "OFF"}).Animation()
+	assert.Equal(t, "off", off.Style)
+	assert.False(t, off.Enabled)
+
+	custom := (&Config{AnimationStyle: "subtle", AnimationSpeedMS: 50, AnimationGlyphs: "*#"}).Animation()
+	assert.Equal(t, 50*time.Millisecond, custom.Interval)
+	assert.Equal(t, []rune("*#"), custom.Glyphs)
+	assert.InDelta(t, 0.4, custom.Intensity, 0.001)
+
+	for _, name := range AnimationStyleNames() {
+		assert.True(t, IsAnimationStyle(name))
+	}
+	assert.False(t, IsAnimationStyle("sparkly"))
+
+	assert.Equal(t, DefaultAnimationStyle, CurrentAnimation().Style)
+	SetAnimation(off)
+	assert.False(t, CurrentAnimation().Enabled)
+}
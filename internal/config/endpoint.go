@@ -0,0 +1,59 @@
+// Package config provides configuration management for Celeste CLI.
+// This file puts API base URLs in one canonical form.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// chatCompletionsPath is the path OpenAI-compatible clients add to the base
+// URL themselves.
+const chatCompletionsPath = "/chat/completions"
+
+// NormalizeBaseURL returns the canonical form of an API base URL: an http or
+// https URL with no repeated or trailing slashes in its path, so the client
+// can add "/chat/completions" to it. A URL that already ends in
+// /chat/completions, pasted from a provider's docs, has it removed, and
+// trimmed reports that so callers can warn about it.
+func NormalizeBaseURL(raw string) (normalized string, trimmed bool, err error) {
+	s := strings.TrimSpace(raw)
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", false, fmt.Errorf("invalid API URL %q: it must start with http:// or https://, like https://%s", raw, strings.TrimPrefix(s, "//"))
+	}
+
+	path := u.Path
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	path = strings.TrimRight(path, "/")
+	if strings.HasSuffix(strings.ToLower(path), chatCompletionsPath) {
+		path = strings.TrimRight(path[:len(path)-len(chatCompletionsPath)], "/")
+		trimmed = true
+	}
+	u.Path, u.RawPath = path, ""
+	return u.String(), trimmed, nil
+}
+
+// APIURL joins path elements onto a base URL, like APIURL(base, "models").
+func APIURL(base string, elem ...string) (string, error) {
+	normalized, _, err := NormalizeBaseURL(base)
+	if err != nil {
+		return "", err
+	}
+	return url.JoinPath(normalized, elem...)
+}
+
+// normalizeBaseURL puts BaseURL in canonical form. A URL that can't be
+// normalized is left as it is, so `celeste config --set-url` can still be
+// run to fix it.
+func (c *Config) normalizeBaseURL() {
+	if c.BaseURL == "" {
+		return
+	}
+	if normalized, _, err := NormalizeBaseURL(c.BaseURL); err == nil {
+		c.BaseURL = normalized
+	}
+}
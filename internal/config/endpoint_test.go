@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		trimmed bool
+	}{
+		{"canonical", "https://api.openai.com/v1", "https://api.openai.com/v1", false},
+		{"trailing slash", "https://api.openai.com/v1/", "https://api.openai.com/v1", false},
+		{"double slash", "https://api.openai.com//v1//", "https://api.openai.com/v1", false},
+		{"full path pasted", "https://api.openai.com/v1/chat/completions", "https://api.openai.com/v1", true},
+		{"full path with slash", "https://api.x.ai/v1/chat/completions/", "https://api.x.ai/v1", true},
+		{"full path, other case", "https://agent.example.com/api/v1/Chat/Completions", "https://agent.example.com/api/v1", true},
+		{"surrounding space", "  http://localhost:11434/v1/ ", "http://localhost:11434/v1", false},
+		{"host only", "https://api.example.com/", "https://api.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, trimmed, err := NormalizeBaseURL(tt.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.trimmed, trimmed)
+		})
+	}
+}
+
+func TestNormalizeBaseURLInvalid(t *testing.T) {
+	for _, raw := range []string{
+		"api.openai.com/v1",
+		"localhost:11434/v1",
+		"ftp://files.example.com/v1",
+		"https://",
+		"",
+	} {
+		_, _, err := NormalizeBaseURL(raw)
+		assert.Error(t, err, raw)
+	}
+
+	_, _, err := NormalizeBaseURL("api.openai.com/v1")
+	assert.Contains(t, err.Error(), "https://api.openai.com/v1", "the error suggests the URL with a scheme")
+}
+
+func TestAPIURL(t *testing.T) {
+	got, err := APIURL("https://api.openai.com/v1/", "models")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.openai.com/v1/models", got)
+
+	got, err = APIURL("https://api.openai.com/v1", "chat", "completions")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.openai.com/v1/chat/completions", got)
+}
+
+func TestLoadNormalizesBaseURL(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CELESTE_HOME", dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"),
+		[]byte(`{"base_url": "https://api.openai.com/v1/chat/completions/"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.local.json"),
+		[]byte(`{"base_url": "http://localhost:11434/v1/"}`), 0600))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.openai.com/v1", cfg.BaseURL)
+
+	cfg, err = LoadNamed("local")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:11434/v1", cfg.BaseURL)
+}
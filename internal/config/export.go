@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
 )
 
 // Exporter handles session export to various formats
@@ -228,11 +230,7 @@ func (e *Exporter) ExportToFile(format string) (string, error) {
 
 // GetExportDir returns the path to the exports directory
 func GetExportDir() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return filepath.Join(".celeste", "exports")
-	}
-	return filepath.Join(homeDir, ".celeste", "exports")
+	return paths.Data("exports")
 }
 
 // ExportSession is a helper function to export a session by ID
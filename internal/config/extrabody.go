@@ -0,0 +1,128 @@
+// Package config provides configuration management for Celeste CLI.
+// This file handles extra_body, provider-specific fields added to chat requests.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseExtraBody parses a JSON object given with --extra-body. Numbers keep
+// their exact digits.
+func ParseExtraBody(s string) (map[string]interface{}, error) {
+	var body map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil || body == nil || decoder.More() {
+		return nil, fmt.Errorf("extra body must be a JSON object, got %q", s)
+	}
+	return body, nil
+}
+
+// SetExtraField applies a key=value assignment from --extra to body. Dots in
+// the key nest objects ("retrieval.k=5" sets {"retrieval": {"k": 5}}). The
+// value is read as JSON when it looks like a number, boolean, null, array,
+// object or quoted string, and as a plain string otherwise.
+func SetExtraField(body map[string]interface{}, assignment string) error {
+	key, raw, ok := strings.Cut(assignment, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", assignment)
+	}
+	keys := strings.Split(strings.TrimSpace(key), ".")
+	for _, k := range keys {
+		if k == "" {
+			return fmt.Errorf("invalid key %q in %q", key, assignment)
+		}
+	}
+	value, err := parseExtraValue(raw)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	m := body
+	for i, k := range keys[:len(keys)-1] {
+		switch next := m[k].(type) {
+		case map[string]interface{}:
+			m = next
+		case nil:
+			child := make(map[string]interface{})
+			m[k] = child
+			m = child
+		default:
+			return fmt.Errorf("can't set %s: %s is already set to %v", key, strings.Join(keys[:i+1], "."), next)
+		}
+	}
+	m[keys[len(keys)-1]] = value
+	return nil
+}
+
+// parseExtraValue reads an --extra value: JSON when it looks like JSON,
+// otherwise the string as given. A number JSON can't read, like the zip
+// code "02134", stays a string.
+func parseExtraValue(raw string) (interface{}, error) {
+	s := strings.TrimSpace(raw)
+	looksNumeric := s != "" && (s[0] == '-' || (s[0] >= '0' && s[0] <= '9'))
+	looksJSON := s == "true" || s == "false" || s == "null" || strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{") || strings.HasPrefix(s, `"`)
+	if !looksNumeric && !looksJSON {
+		return raw, nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		if looksNumeric {
+			return raw, nil
+		}
+		return nil, fmt.Errorf("%q is not valid JSON", s)
+	}
+	return value, nil
+}
+
+// ParseExtraBodyFlags builds the extra body from the --extra-body JSON
+// object, then each --extra key=value assignment in order, so assignments
+// override fields in the object.
+func ParseExtraBodyFlags(body string, assignments []string) (map[string]interface{}, error) {
+	extra := make(map[string]interface{})
+	if body != "" {
+		parsed, err := ParseExtraBody(body)
+		if err != nil {
+			return nil, err
+		}
+		extra = parsed
+	}
+	for _, assignment := range assignments {
+		if err := SetExtraField(extra, assignment); err != nil {
+			return nil, err
+		}
+	}
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
+
+// MergeExtraBody returns base with override's fields on top. Objects set in
+// both are merged key by key; anything else in override replaces base's
+// value. Neither map is modified. It returns nil when both are empty.
+func MergeExtraBody(base, override map[string]interface{}) map[string]interface{} {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		baseObj, baseIsObj := merged[k].(map[string]interface{})
+		overrideObj, overrideIsObj := v.(map[string]interface{})
+		if baseIsObj && overrideIsObj {
+			merged[k] = MergeExtraBody(baseObj, overrideObj)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExtraBody(t *testing.T) {
+	body, err := ParseExtraBody(`{"include_retrieval_info": true, "k": 12345678901234567890}`)
+	require.NoError(t, err)
+	assert.Equal(t, true, body["include_retrieval_info"])
+	assert.Equal(t, json.Number("12345678901234567890"), body["k"])
+
+	for _, s := range []string{"", "[1]", `"x"`, "null", "{", `{} {}`} {
+		_, err := ParseExtraBody(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSetExtraField(t *testing.T) {
+	tests := []struct {
+		assignment string
+		expected   interface{}
+	}{
+		{"k=5", json.Number("5")},
+		{"k=-0.5", json.Number("-0.5")},
+		{"k=true", true},
+		{"k=null", nil},
+		{"k=hello world", "hello world"},
+		{"k=", ""},
+		{"k=02134", "02134"},
+		{"k=5pm", "5pm"},
+		{`k="5"`, "5"},
+		{"k=[1,2]", []interface{}{json.Number("1"), json.Number("2")}},
+		{`k={"a":1}`, map[string]interface{}{"a": json.Number("1")}},
+		{"k=a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.assignment, func(t *testing.T) {
+			body := map[string]interface{}{}
+			require.NoError(t, SetExtraField(body, tt.assignment))
+			assert.Equal(t, tt.expected, body["k"])
+		})
+	}
+}
+
+func TestSetExtraFieldNested(t *testing.T) {
+	body := map[string]interface{}{"retrieval": map[string]interface{}{"k": 3}}
+	require.NoError(t, SetExtraField(body, "retrieval.method=rewrite"))
+	require.NoError(t, SetExtraField(body, "a.b.c=1"))
+	assert.Equal(t, map[string]interface{}{
+		"retrieval": map[string]interface{}{"k": 3, "method": "rewrite"},
+		"a":         map[string]interface{}{"b": map[string]interface{}{"c": json.Number("1")}},
+	}, body)
+}
+
+func TestSetExtraFieldErrors(t *testing.T) {
+	for _, assignment := range []string{"k", "=5", "a..b=1", "a.=1", "k=[1,", `k={"a"}`, "x.y=1"} {
+		body := map[string]interface{}{"x": 1}
+		assert.Error(t, SetExtraField(body, assignment), assignment)
+	}
+}
+
+func TestParseExtraBodyFlags(t *testing.T) {
+	extra, err := ParseExtraBodyFlags("", nil)
+	require.NoError(t, err)
+	assert.Nil(t, extra)
+
+	extra, err = ParseExtraBodyFlags(`{"k": 1, "retrieval": {"k": 3, "method": "none"}}`, []string{"k=2", "retrieval.k=5"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"k":         json.Number("2"),
+		"retrieval": map[string]interface{}{"k": json.Number("5"), "method": "none"},
+	}, extra, "--extra wins over --extra-body")
+
+	_, err = ParseExtraBodyFlags("[]", nil)
+	assert.Error(t, err)
+	_, err = ParseExtraBodyFlags("", []string{"k"})
+	assert.Error(t, err)
+}
+
+func TestMergeExtraBody(t *testing.T) {
+	assert.Nil(t, MergeExtraBody(nil, map[string]interface{}{}))
+
+	base := map[string]interface{}{
+		"k":         1,
+		"keep":      true,
+		"retrieval": map[string]interface{}{"k": 3, "method": "none"},
+		"filter":    map[string]interface{}{"a": 1},
+	}
+	override := map[string]interface{}{
+		"k":         2,
+		"retrieval": map[string]interface{}{"k": 5},
+		"filter":    "none",
+	}
+	assert.Equal(t, map[string]interface{}{
+		"k":         2,
+		"keep":      true,
+		"retrieval": map[string]interface{}{"k": 5, "method": "none"},
+		"filter":    "none",
+	}, MergeExtraBody(base, override))
+	assert.Equal(t, map[string]interface{}{"k": 3, "method": "none"}, base["retrieval"], "base is unchanged")
+}
+
+// TestExtraBodyConfig tests that extra_body is read from config JSON and
+// left out when unset
+func TestExtraBodyConfig(t *testing.T) {
+	var cfg Config
+	require.NoError(t, json.Unmarshal([]byte(`{"extra_body": {"include_retrieval_info": true}}`), &cfg))
+	assert.Equal(t, map[string]interface{}{"include_retrieval_info": true}, cfg.ExtraBody)
+
+	data, err := json.Marshal(&Config{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "extra_body")
+}
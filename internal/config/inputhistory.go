@@ -0,0 +1,114 @@
+// Package config provides configuration management for Celeste CLI.
+// This file keeps chat input history across runs, so ↑ recalls earlier
+// sessions' inputs like a shell.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// DefaultInputHistorySize is how many inputs the history file keeps when
+// input_history_size is unset.
+const DefaultInputHistorySize = 1000
+
+// InputHistoryPath returns the file chat input history is kept in.
+func InputHistoryPath() string {
+	return paths.Data("input_history")
+}
+
+// LoadInputHistory returns the newest max entries of the history file at
+// path, oldest first. A missing file is an empty history.
+func LoadInputHistory(path string, max int) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input history: %w", err)
+	}
+	return newestEntries(parseInputHistory(data), max), nil
+}
+
+// AppendInputHistory adds entry to the history file at path, keeping the
+// newest max entries. Blank entries and repeats of the last entry are
+// skipped.
+func AppendInputHistory(path, entry string, max int) error {
+	if strings.TrimSpace(entry) == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create input history directory: %w", err)
+	}
+	return filelock.Update(path, 0600, func(data []byte) ([]byte, error) {
+		entries := parseInputHistory(data)
+		if len(entries) > 0 && entries[len(entries)-1] == entry {
+			return data, nil
+		}
+		entries = newestEntries(append(entries, entry), max)
+
+		var b strings.Builder
+		for _, e := range entries {
+			b.WriteString(escapeInputHistory(e))
+			b.WriteByte('\n')
+		}
+		return []byte(b.String()), nil
+	})
+}
+
+// newestEntries returns the last max entries, or all of them if max <= 0.
+func newestEntries(entries []string, max int) []string {
+	if max > 0 && len(entries) > max {
+		return entries[len(entries)-max:]
+	}
+	return entries
+}
+
+// parseInputHistory reads one entry per line, skipping blank lines.
+func parseInputHistory(data []byte) []string {
+	var entries []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSuffix(line, "\r"); strings.TrimSpace(line) != "" {
+			entries = append(entries, unescapeInputHistory(line))
+		}
+	}
+	return entries
+}
+
+// escapeInputHistory keeps an entry on one line by writing newlines as \n
+// and backslashes as \\.
+func escapeInputHistory(entry string) string {
+	entry = strings.ReplaceAll(entry, `\`, `\\`)
+	entry = strings.ReplaceAll(entry, "\r", `\r`)
+	return strings.ReplaceAll(entry, "\n", `\n`)
+}
+
+// unescapeInputHistory reverses escapeInputHistory.
+func unescapeInputHistory(line string) string {
+	if !strings.Contains(line, `\`) {
+		return line
+	}
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] != '\\' || i+1 == len(line) {
+			b.WriteByte(line[i])
+			continue
+		}
+		i++
+		switch line[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(line[i])
+		}
+	}
+	return b.String()
+}
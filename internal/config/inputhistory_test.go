@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "input_history")
+
+	history, err := LoadInputHistory(path, 10)
+	require.NoError(t, err)
+	assert.Empty(t, history, "a missing file is an empty history")
+
+	for _, entry := range []string{"/help", "hello", "hello", "  ", "", "/help", "line one\nline \\n two"} {
+		require.NoError(t, AppendInputHistory(path, entry, 10))
+	}
+	history, err = LoadInputHistory(path, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/help", "hello", "/help", "line one\nline \\n two"}, history,
+		"blank entries and consecutive repeats are skipped; multi-line entries round trip")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	history, err = LoadInputHistory(path, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/help", "line one\nline \\n two"}, history, "only the newest are loaded")
+}
+
+func TestInputHistoryCap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input_history")
+	for _, entry := range []string{"one", "two", "three", "four"} {
+		require.NoError(t, AppendInputHistory(path, entry, 3))
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "two\nthree\nfour\n", string(data))
+}
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// Budget thresholds as a fraction of MonthlyBudgetUSD.
+const (
+	BudgetWarnThreshold     = 0.8
+	BudgetExceededThreshold = 1.0
+)
+
+// Budget levels returned by CheckBudget.
+const (
+	BudgetOK       = "ok"
+	BudgetWarn     = "warn"
+	BudgetExceeded = "exceeded"
+)
+
+// LedgerEntry records the usage and estimated cost of one request.
+type LedgerEntry struct {
+	Timestamp        time.Time `json:"timestamp"`
+	Date             string    `json:"date"` // YYYY-MM-DD, local time
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	SessionID        string    `json:"session_id,omitempty"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	Cost             float64   `json:"cost"`
+}
+
+// UsageLedger is the cumulative spend ledger, stored in usage.json in the
+// data directory.
+type UsageLedger struct {
+	Entries []LedgerEntry `json:"entries"`
+}
+
+// SpendReport summarizes ledger spend for one month.
+type SpendReport struct {
+	Month            string
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	Total            float64
+	ByDay            map[string]float64
+	ByModel          map[string]float64
+}
+
+// GetLedgerPath returns the path to the usage ledger.
+func GetLedgerPath() string {
+	return paths.Data("usage.json")
+}
+
+// IsLocalProvider reports whether requests to this provider cost nothing
+// (ollama, LM Studio or anything served from the local machine).
+func IsLocalProvider(provider, baseURL string) bool {
+	switch strings.ToLower(provider) {
+	case "ollama", "local", "lmstudio":
+		return true
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "0.0.0.0", "::1":
+		return true
+	}
+	return false
+}
+
+// NewLedgerEntry builds a ledger entry for a completed request, estimating
+// its cost from the model pricing table. Local providers are always free.
+func NewLedgerEntry(provider, baseURL, model string, promptTokens, completionTokens int) LedgerEntry {
+	now := time.Now()
+	cost := 0.0
+	if !IsLocalProvider(provider, baseURL) {
+		cost = CalculateCost(model, promptTokens, completionTokens)
+	}
+	return LedgerEntry{
+		Timestamp:        now,
+		Date:             now.Format("2006-01-02"),
+		Provider:         provider,
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		Cost:             cost,
+	}
+}
+
+// LoadUsageLedger reads the ledger. A missing file is an empty ledger.
+func LoadUsageLedger() (*UsageLedger, error) {
+	return loadLedgerFile(GetLedgerPath())
+}
+
+func loadLedgerFile(path string) (*UsageLedger, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &UsageLedger{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage ledger: %w", err)
+	}
+
+	var ledger UsageLedger
+	if err := json.Unmarshal(data, &ledger); err != nil {
+		return nil, fmt.Errorf("failed to parse usage ledger: %w", err)
+	}
+	return &ledger, nil
+}
+
+// RecordUsage appends an entry to the ledger.
+// Writes are atomic (temp file + rename) and serialized across processes
+// with filelock, so the classic CLI and the TUI can record concurrently.
+func RecordUsage(entry LedgerEntry) error {
+	return recordUsageAt(GetLedgerPath(), entry)
+}
+
+func recordUsageAt(path string, entry LedgerEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+
+	unlock, err := filelock.Lock(path, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ledger, err := loadLedgerFile(path)
+	if err != nil {
+		return err
+	}
+	ledger.Entries = append(ledger.Entries, entry)
+
+	data, err := json.MarshalIndent(ledger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage ledger: %w", err)
+	}
+
+	if err := filelock.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write usage ledger: %w", err)
+	}
+	return nil
+}
+
+// SpendForDay returns the total cost recorded on a date (YYYY-MM-DD).
+func (l *UsageLedger) SpendForDay(date string) float64 {
+	total := 0.0
+	for _, e := range l.Entries {
+		if e.Date == date {
+			total += e.Cost
+		}
+	}
+	return total
+}
+
+// SpendForMonth returns the total cost recorded in a month (YYYY-MM).
+func (l *UsageLedger) SpendForMonth(month string) float64 {
+	total := 0.0
+	for _, e := range l.Entries {
+		if strings.HasPrefix(e.Date, month) {
+			total += e.Cost
+		}
+	}
+	return total
+}
+
+// SpendForSession returns the total cost recorded for a session.
+func (l *UsageLedger) SpendForSession(sessionID string) float64 {
+	total := 0.0
+	for _, e := range l.Entries {
+		if sessionID != "" && e.SessionID == sessionID {
+			total += e.Cost
+		}
+	}
+	return total
+}
+
+// MonthlyReport summarizes spend for a month (YYYY-MM).
+func (l *UsageLedger) MonthlyReport(month string) SpendReport {
+	report := SpendReport{
+		Month:   month,
+		ByDay:   make(map[string]float64),
+		ByModel: make(map[string]float64),
+	}
+	for _, e := range l.Entries {
+		if !strings.HasPrefix(e.Date, month) {
+			continue
+		}
+		report.Requests++
+		report.PromptTokens += e.PromptTokens
+		report.CompletionTokens += e.CompletionTokens
+		report.Total += e.Cost
+		report.ByDay[e.Date] += e.Cost
+		report.ByModel[e.Model] += e.Cost
+	}
+	return report
+}
+
+// CheckBudget compares spend against a monthly budget.
+// Returns BudgetOK when no budget is set.
+func CheckBudget(spent, budget float64) string {
+	if budget <= 0 {
+		return BudgetOK
+	}
+	switch {
+	case spent >= budget*BudgetExceededThreshold:
+		return BudgetExceeded
+	case spent >= budget*BudgetWarnThreshold:
+		return BudgetWarn
+	default:
+		return BudgetOK
+	}
+}
+
+// FormatSpendReport renders a monthly spend report for the terminal.
+func FormatSpendReport(report SpendReport, budget float64) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Spend for %s\n\n", report.Month))
+	sb.WriteString(fmt.Sprintf("  Requests:          %s\n", FormatNumber(report.Requests)))
+	sb.WriteString(fmt.Sprintf("  Prompt tokens:     %s\n", FormatNumber(report.PromptTokens)))
+	sb.WriteString(fmt.Sprintf("  Completion tokens: %s\n", FormatNumber(report.CompletionTokens)))
+	sb.WriteString(fmt.Sprintf("  Estimated cost:    %s\n", FormatCost(report.Total)))
+	if budget > 0 {
+		sb.WriteString(fmt.Sprintf("  Monthly budget:    %s (%.0f%% used)\n", FormatCost(budget), report.Total/budget*100))
+	}
+
+	if len(report.ByDay) > 0 {
+		sb.WriteString("\nBy day:\n")
+		days := make([]string, 0, len(report.ByDay))
+		for day := range report.ByDay {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+		for _, day := range days {
+			sb.WriteString(fmt.Sprintf("  %s  %s\n", day, FormatCost(report.ByDay[day])))
+		}
+	}
+
+	if len(report.ByModel) > 0 {
+		sb.WriteString("\nBy model:\n")
+		models := make([]string, 0, len(report.ByModel))
+		for model := range report.ByModel {
+			models = append(models, model)
+		}
+		sort.Slice(models, func(i, j int) bool {
+			return report.ByModel[models[i]] > report.ByModel[models[j]]
+		})
+		for _, model := range models {
+			sb.WriteString(fmt.Sprintf("  %-32s %s\n", model, FormatCost(report.ByModel[model])))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLocalProvider(t *testing.T) {
+	assert.True(t, IsLocalProvider("ollama", ""))
+	assert.True(t, IsLocalProvider("openai", "http://localhost:11434/v1"))
+	assert.True(t, IsLocalProvider("", "http://127.0.0.1:1234/v1"))
+	assert.False(t, IsLocalProvider("openai", "https://api.openai.com/v1"))
+	assert.False(t, IsLocalProvider("", ""))
+}
+
+func TestNewLedgerEntry(t *testing.T) {
+	paid := NewLedgerEntry("openai", "https://api.openai.com/v1", "gpt-4o", 1000, 500)
+	assert.Greater(t, paid.Cost, 0.0)
+	assert.Equal(t, paid.Timestamp.Format("2006-01-02"), paid.Date)
+
+	local := NewLedgerEntry("ollama", "http://localhost:11434/v1", "gpt-4o", 1000, 500)
+	assert.Equal(t, 0.0, local.Cost)
+	assert.Equal(t, 1000, local.PromptTokens)
+}
+
+func TestRecordUsageConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, recordUsageAt(path, LedgerEntry{Date: "2025-06-01", Model: "gpt-4o", Cost: 0.01}))
+		}()
+	}
+	wg.Wait()
+
+	ledger, err := loadLedgerFile(path)
+	require.NoError(t, err)
+	assert.Len(t, ledger.Entries, 20)
+	assert.NoFileExists(t, path+".lock")
+}
+
+func TestLoadLedgerFileMissing(t *testing.T) {
+	ledger, err := loadLedgerFile(filepath.Join(t.TempDir(), "usage.json"))
+	require.NoError(t, err)
+	assert.Empty(t, ledger.Entries)
+}
+
+func TestMonthlyReport(t *testing.T) {
+	ledger := &UsageLedger{Entries: []LedgerEntry{
+		{Date: "2025-06-01", Model: "gpt-4o", SessionID: "a", PromptTokens: 100, CompletionTokens: 50, Cost: 1.0},
+		{Date: "2025-06-01", Model: "grok-4", SessionID: "a", PromptTokens: 200, CompletionTokens: 100, Cost: 2.0},
+		{Date: "2025-06-15", Model: "gpt-4o", SessionID: "b", PromptTokens: 100, CompletionTokens: 50, Cost: 0.5},
+		{Date: "2025-07-01", Model: "gpt-4o", SessionID: "c", PromptTokens: 100, CompletionTokens: 50, Cost: 4.0},
+	}}
+
+	report := ledger.MonthlyReport("2025-06")
+	assert.Equal(t, 3, report.Requests)
+	assert.Equal(t, 400, report.PromptTokens)
+	assert.Equal(t, 200, report.CompletionTokens)
+	assert.InDelta(t, 3.5, report.Total, 1e-9)
+	assert.InDelta(t, 3.0, report.ByDay["2025-06-01"], 1e-9)
+	assert.InDelta(t, 1.5, report.ByModel["gpt-4o"], 1e-9)
+
+	assert.InDelta(t, 3.0, ledger.SpendForDay("2025-06-01"), 1e-9)
+	assert.InDelta(t, 4.0, ledger.SpendForMonth("2025-07"), 1e-9)
+	assert.InDelta(t, 3.0, ledger.SpendForSession("a"), 1e-9)
+	assert.Equal(t, 0.0, ledger.SpendForSession(""))
+
+	output := FormatSpendReport(report, 10)
+	assert.Contains(t, output, "Spend for 2025-06")
+	assert.Contains(t, output, "35% used")
+}
+
+func TestCheckBudget(t *testing.T) {
+	assert.Equal(t, BudgetOK, CheckBudget(100, 0))
+	assert.Equal(t, BudgetOK, CheckBudget(7.9, 10))
+	assert.Equal(t, BudgetWarn, CheckBudget(8, 10))
+	assert.Equal(t, BudgetExceeded, CheckBudget(10, 10))
+	assert.Equal(t, BudgetExceeded, CheckBudget(12, 10))
+}
@@ -0,0 +1,139 @@
+// Package config provides configuration management for Celeste CLI.
+// This file handles the .partial sidecar that keeps a streaming reply
+// recoverable if the TUI dies before the exchange is saved.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PartialFlushInterval is the longest a streamed chunk waits before it is
+// written to the session's .partial sidecar.
+const PartialFlushInterval = 500 * time.Millisecond
+
+// InterruptedSuffix marks a reply recovered from a .partial sidecar.
+const InterruptedSuffix = " (interrupted)"
+
+// PartialWriter records a reply as it streams, so it survives a crash.
+// Its methods are safe for concurrent use and do nothing on a nil writer.
+type PartialWriter struct {
+	mu        sync.Mutex
+	path      string
+	content   strings.Builder
+	dirty     bool
+	discarded bool
+	lastFlush time.Time
+	now       func() time.Time
+}
+
+// partialPath returns the sidecar path for a session.
+func (m *SessionManager) partialPath(sessionID string) string {
+	return filepath.Join(m.sessionsDir, sessionID+".partial")
+}
+
+// NewPartialWriter returns a writer for the session's .partial sidecar.
+// Nothing is written until the first chunk arrives.
+func (m *SessionManager) NewPartialWriter(sessionID string) *PartialWriter {
+	return &PartialWriter{path: m.partialPath(sessionID), now: time.Now}
+}
+
+// Append adds a streamed chunk, writing the sidecar if the last write was
+// at least PartialFlushInterval ago.
+func (w *PartialWriter) Append(chunk string) error {
+	if w == nil || chunk == "" {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.content.WriteString(chunk)
+	w.dirty = true
+	if w.now().Sub(w.lastFlush) < PartialFlushInterval {
+		return nil
+	}
+	return w.flushLocked()
+}
+
+// Flush writes any chunks not yet in the sidecar.
+func (w *PartialWriter) Flush() error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flushLocked()
+}
+
+func (w *PartialWriter) flushLocked() error {
+	if !w.dirty || w.discarded {
+		return nil
+	}
+	// Replace the whole file so a crash mid-write never leaves half a chunk
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(w.content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write partial reply: %w", err)
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return fmt.Errorf("failed to write partial reply: %w", err)
+	}
+	w.dirty = false
+	w.lastFlush = w.now()
+	return nil
+}
+
+// Discard removes the sidecar once the reply is saved with the session.
+// Later chunks are ignored.
+func (w *PartialWriter) Discard() error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.content.Reset()
+	w.discarded = true
+	if err := os.Remove(w.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// RecoverPartial folds a reply left in the session's .partial sidecar into
+// the session as an assistant message marked InterruptedSuffix, saves the
+// session and removes the sidecar. A sidecar left behind after the reply
+// was saved is just removed. It reports whether a reply was recovered.
+func (m *SessionManager) RecoverPartial(session *Session) (bool, error) {
+	path := m.partialPath(session.ID)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read partial reply: %w", err)
+	}
+
+	content := strings.TrimRight(string(data), " \n")
+	n := len(session.Messages)
+	saved := n > 0 && session.Messages[n-1].Role == "assistant"
+	if content != "" && !saved {
+		session.Messages = append(session.Messages, SessionMessage{
+			Role:      "assistant",
+			Content:   content + InterruptedSuffix,
+			Timestamp: time.Now(),
+		})
+		if err := m.Save(session); err != nil {
+			return false, err
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		return false, fmt.Errorf("failed to remove partial reply: %w", err)
+	}
+	return content != "" && !saved, nil
+}
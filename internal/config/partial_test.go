@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPartialRecovery tests recovering a reply cut off between chunks
+func TestPartialRecovery(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+	manager := NewSessionManager()
+
+	session := manager.NewSession()
+	session.Messages = append(session.Messages, SessionMessage{Role: "user", Content: "Tell me a story"})
+	require.NoError(t, manager.Save(session))
+
+	clock := time.Now()
+	w := manager.NewPartialWriter(session.ID)
+	w.now = func() time.Time { return clock }
+
+	require.NoError(t, w.Append("Once upon"))
+	clock = clock.Add(100 * time.Millisecond)
+	require.NoError(t, w.Append(" a time"))
+	data, err := os.ReadFile(manager.partialPath(session.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "Once upon", string(data), "chunks within the interval wait for the next write")
+
+	clock = clock.Add(PartialFlushInterval)
+	require.NoError(t, w.Append(", there was"))
+	require.NoError(t, w.Append(" a cat")) // Killed before this is written
+
+	// Restart: the session only has the user message
+	manager = NewSessionManager()
+	loaded, err := manager.Load(session.ID)
+	require.NoError(t, err)
+	recovered, err := manager.RecoverPartial(loaded)
+	require.NoError(t, err)
+	assert.True(t, recovered)
+	require.Len(t, loaded.Messages, 2)
+	assert.Equal(t, "assistant", loaded.Messages[1].Role)
+	assert.Equal(t, "Once upon a time, there was"+InterruptedSuffix, loaded.Messages[1].Content)
+
+	// Saved with the session and the sidecar is gone
+	_, err = os.Stat(manager.partialPath(session.ID))
+	assert.True(t, os.IsNotExist(err))
+	loaded, err = manager.Load(session.ID)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Messages, 2)
+
+	recovered, err = manager.RecoverPartial(loaded)
+	require.NoError(t, err)
+	assert.False(t, recovered)
+}
+
+// TestPartialAfterSavedReply tests that a leftover sidecar is dropped once
+// the reply is already in the session
+func TestPartialAfterSavedReply(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+	manager := NewSessionManager()
+
+	session := manager.NewSession()
+	session.Messages = append(session.Messages,
+		SessionMessage{Role: "user", Content: "Hi"},
+		SessionMessage{Role: "assistant", Content: "Hello!"},
+	)
+	w := manager.NewPartialWriter(session.ID)
+	require.NoError(t, w.Append("Hello!"))
+
+	recovered, err := manager.RecoverPartial(session)
+	require.NoError(t, err)
+	assert.False(t, recovered)
+	assert.Len(t, session.Messages, 2)
+	_, err = os.Stat(manager.partialPath(session.ID))
+	assert.True(t, os.IsNotExist(err))
+
+	// Discarding removes the sidecar and ignores later chunks
+	w = manager.NewPartialWriter(session.ID)
+	require.NoError(t, w.Append("More"))
+	require.NoError(t, w.Discard())
+	require.NoError(t, w.Append(" text"))
+	require.NoError(t, w.Flush())
+	_, err = os.Stat(manager.partialPath(session.ID))
+	assert.True(t, os.IsNotExist(err))
+
+	var nilWriter *PartialWriter
+	assert.NoError(t, nilWriter.Append("x"))
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// SafeModeEnvVar is the environment variable that enables safe mode.
+const SafeModeEnvVar = "CELESTE_SAFE_MODE"
+
+// safeModeForced is set by the --safe-mode global flag.
+var safeModeForced bool
+
+// EnableSafeMode forces safe mode on for the rest of the process.
+// Called by main when --safe-mode is passed.
+func EnableSafeMode() {
+	safeModeForced = true
+}
+
+// IsSafeMode reports whether safe mode is active.
+// Safe mode strips every NSFW and image generation pathway so the CLI
+// can be used on stream or in shared environments.
+func IsSafeMode() bool {
+	if safeModeForced {
+		return true
+	}
+
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(SafeModeEnvVar))) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
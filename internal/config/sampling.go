@@ -0,0 +1,113 @@
+// Package config provides configuration management for Celeste CLI.
+// This file handles request sampling parameters (temperature, top_p, max_tokens).
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sampling holds the sampling parameters sent with chat requests. A nil
+// Temperature or TopP and a zero MaxTokens leave the provider's default.
+type Sampling struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+}
+
+// SamplingSettings are the names accepted by ParseSamplingSetting.
+var SamplingSettings = []string{"temperature", "top_p", "max_tokens"}
+
+// IsZero reports whether no parameter is set.
+func (s Sampling) IsZero() bool {
+	return s.Temperature == nil && s.TopP == nil && s.MaxTokens == 0
+}
+
+// Merge returns s with every parameter set in override replacing its own.
+func (s Sampling) Merge(override Sampling) Sampling {
+	if override.Temperature != nil {
+		s.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		s.TopP = override.TopP
+	}
+	if override.MaxTokens != 0 {
+		s.MaxTokens = override.MaxTokens
+	}
+	return s
+}
+
+// Validate checks that temperature is 0-2, top_p is 0-1 and max_tokens is
+// positive and below the model's context window.
+func (s Sampling) Validate(model string, contextLimit int) error {
+	if s.Temperature != nil && (*s.Temperature < 0 || *s.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got %g", *s.Temperature)
+	}
+	if s.TopP != nil && (*s.TopP < 0 || *s.TopP > 1) {
+		return fmt.Errorf("top_p must be between 0 and 1, got %g", *s.TopP)
+	}
+	if s.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must be positive, got %d", s.MaxTokens)
+	}
+	if limit := GetModelLimitWithOverride(model, contextLimit); s.MaxTokens >= limit {
+		return fmt.Errorf("max_tokens must be below %s's %d token limit, got %d", model, limit, s.MaxTokens)
+	}
+	return nil
+}
+
+// String describes the set parameters, e.g. "temperature 0.8, max_tokens 500".
+func (s Sampling) String() string {
+	var parts []string
+	if s.Temperature != nil {
+		parts = append(parts, "temperature "+strconv.FormatFloat(*s.Temperature, 'g', -1, 64))
+	}
+	if s.TopP != nil {
+		parts = append(parts, "top_p "+strconv.FormatFloat(*s.TopP, 'g', -1, 64))
+	}
+	if s.MaxTokens != 0 {
+		parts = append(parts, "max_tokens "+strconv.Itoa(s.MaxTokens))
+	}
+	if len(parts) == 0 {
+		return "provider defaults"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseSamplingSetting applies one "name value" setting to s. Names may use
+// dashes (top-p) and value "default" clears the setting. The result is not
+// range checked; call Validate.
+func ParseSamplingSetting(s Sampling, name, value string) (Sampling, error) {
+	name = strings.ReplaceAll(strings.ToLower(name), "-", "_")
+	value = strings.TrimSpace(value)
+	clear := strings.EqualFold(value, "default")
+
+	switch name {
+	case "temperature", "top_p":
+		var v *float64
+		if !clear {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return s, fmt.Errorf("%s must be a number, got %q", name, value)
+			}
+			v = &f
+		}
+		if name == "temperature" {
+			s.Temperature = v
+		} else {
+			s.TopP = v
+		}
+	case "max_tokens":
+		n := 0
+		if !clear {
+			var err error
+			if n, err = strconv.Atoi(value); err != nil || n <= 0 {
+				return s, fmt.Errorf("max_tokens must be a positive whole number, got %q", value)
+			}
+		}
+		s.MaxTokens = n
+	default:
+		return s, fmt.Errorf("unknown setting %q (available: %s)", name, strings.Join(SamplingSettings, ", "))
+	}
+	return s, nil
+}
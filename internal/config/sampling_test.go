@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSamplingSetting(t *testing.T) {
+	s, err := ParseSamplingSetting(Sampling{}, "temperature", "1.1")
+	require.NoError(t, err)
+	s, err = ParseSamplingSetting(s, "top-p", "0.9")
+	require.NoError(t, err)
+	s, err = ParseSamplingSetting(s, "max_tokens", "500")
+	require.NoError(t, err)
+	assert.Equal(t, "temperature 1.1, top_p 0.9, max_tokens 500", s.String())
+
+	// "default" clears a setting
+	s, err = ParseSamplingSetting(s, "temperature", "default")
+	require.NoError(t, err)
+	assert.Nil(t, s.Temperature)
+	assert.Equal(t, "top_p 0.9, max_tokens 500", s.String())
+
+	for _, bad := range [][2]string{
+		{"temperature", "warm"},
+		{"max_tokens", "0"},
+		{"max_tokens", "1.5"},
+		{"seed", "42"},
+	} {
+		_, err := ParseSamplingSetting(Sampling{}, bad[0], bad[1])
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSamplingValidate(t *testing.T) {
+	temperature, topP := 0.3, 1.0
+	assert.NoError(t, Sampling{Temperature: &temperature, TopP: &topP, MaxTokens: 4000}.Validate("gpt-4o-mini", 0))
+	assert.NoError(t, Sampling{}.Validate("gpt-4o-mini", 0))
+
+	tooHot, tooWide := 2.5, 1.2
+	assert.ErrorContains(t, Sampling{Temperature: &tooHot}.Validate("gpt-4o-mini", 0), "temperature must be between 0 and 2")
+	assert.ErrorContains(t, Sampling{TopP: &tooWide}.Validate("gpt-4o-mini", 0), "top_p must be between 0 and 1")
+	assert.ErrorContains(t, Sampling{MaxTokens: 8192}.Validate("venice-uncensored", 0), "below venice-uncensored's 8192 token limit")
+
+	// context_limit raises the limit for models Celeste doesn't know
+	assert.NoError(t, Sampling{MaxTokens: 16000}.Validate("new-model", 32000))
+}
+
+func TestSamplingMerge(t *testing.T) {
+	low, high := 0.3, 1.1
+	base := Sampling{Temperature: &low, MaxTokens: 200}
+	merged := base.Merge(Sampling{Temperature: &high})
+	assert.Equal(t, "temperature 1.1, max_tokens 200", merged.String())
+	assert.Equal(t, "temperature 0.3, max_tokens 200", base.String(), "the receiver is unchanged")
+	assert.True(t, Sampling{}.IsZero())
+	assert.Equal(t, "provider defaults", Sampling{}.String())
+}
@@ -0,0 +1,176 @@
+// Package config provides configuration management for Celeste CLI.
+// This file handles searching saved sessions.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultSearchLimit caps a session search when SearchOptions.Limit is zero.
+const DefaultSearchLimit = 50
+
+// searchSnippetRadius is how much text a snippet keeps on each side of a match.
+const searchSnippetRadius = 40
+
+// imageDataPattern matches base64 image payloads embedded in message text.
+var imageDataPattern = regexp.MustCompile(`data:image/[a-zA-Z0-9.+-]+;base64,[A-Za-z0-9+/=]+`)
+
+// SearchOptions controls a search across saved sessions.
+type SearchOptions struct {
+	Limit        int  // Maximum matches to return (0 = DefaultSearchLimit)
+	IncludeTools bool // Also search skill results, which are skipped by default
+}
+
+// SearchMatch is a message that contains the search query.
+type SearchMatch struct {
+	SessionID   string
+	SessionName string
+	Index       int // Position of the message in the session
+	Role        string
+	Timestamp   time.Time
+	Snippet     string // Text around the first match, on one line
+	MatchStart  int    // Byte offset of the match in Snippet
+	MatchEnd    int
+}
+
+// IndexFold returns the byte index of the first case-insensitive match of
+// substr in s, or -1.
+func IndexFold(s, substr string) int {
+	if substr == "" {
+		return -1
+	}
+	for i := range s {
+		if len(s)-i < len(substr) {
+			break
+		}
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SearchableText returns the text of a message that search looks at, with
+// base64 image payloads removed. Skill results are left out (ok is false)
+// unless includeTools is set.
+func SearchableText(role, content string, includeTools bool) (text string, ok bool) {
+	if role == "tool" && !includeTools {
+		return "", false
+	}
+	return imageDataPattern.ReplaceAllString(content, "[image]"), true
+}
+
+// searchSnippet cuts text down to the first match of query and some context
+// around it, collapsed onto one line.
+func searchSnippet(text, query string) (snippet string, start, end int) {
+	at := IndexFold(text, query)
+	if at < 0 {
+		return "", 0, 0
+	}
+	from, to := at-searchSnippetRadius, at+len(query)+searchSnippetRadius
+	prefix, suffix := "…", "…"
+	if from <= 0 {
+		from, prefix = 0, ""
+	}
+	if to >= len(text) {
+		to, suffix = len(text), ""
+	}
+	// Keep the cut on rune boundaries
+	for from > 0 && !isRuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !isRuneStart(text[to]) {
+		to++
+	}
+
+	flatten := strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ")
+	before := prefix + flatten.Replace(text[from:at])
+	match := flatten.Replace(text[at : at+len(query)])
+	return before + match + flatten.Replace(text[at+len(query):to]) + suffix, len(before), len(before) + len(match)
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// Scan calls fn with each saved session, newest first, reading one file at a
+// time so large session directories are never loaded at once. Unreadable
+// files are skipped. Scanning stops when fn returns false.
+func (m *SessionManager) Scan(fn func(*Session) bool) error {
+	files, err := m.sessionFiles()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	modTimes := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return modTimes[files[i]].After(modTimes[files[j]])
+	})
+
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			continue
+		}
+		var session Session
+		err = json.NewDecoder(f).Decode(&session)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		if !fn(&session) {
+			break
+		}
+	}
+	return nil
+}
+
+// Search finds messages containing query (case-insensitive) across every
+// saved session, newest session first. more reports whether matches were
+// left out because of the limit.
+func (m *SessionManager) Search(query string, opts SearchOptions) (matches []SearchMatch, more bool, err error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	err = m.Scan(func(session *Session) bool {
+		for i, msg := range session.Messages {
+			text, ok := SearchableText(msg.Role, msg.Content, opts.IncludeTools)
+			if !ok {
+				continue
+			}
+			snippet, start, end := searchSnippet(text, query)
+			if snippet == "" {
+				continue
+			}
+			if len(matches) == limit {
+				more = true
+				return false
+			}
+			matches = append(matches, SearchMatch{
+				SessionID:   session.ID,
+				SessionName: session.Name,
+				Index:       i,
+				Role:        msg.Role,
+				Timestamp:   msg.Timestamp,
+				Snippet:     snippet,
+				MatchStart:  start,
+				MatchEnd:    end,
+			})
+		}
+		return true
+	})
+	return matches, more, err
+}
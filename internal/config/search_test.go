@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSessionSearch tests searching across saved sessions
+func TestSessionSearch(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+	manager := NewSessionManager()
+
+	older := &Session{ID: "older", Name: "Ad reads", Messages: []SessionMessage{
+		{Role: "user", Content: "Write a sponsor spot"},
+		{Role: "assistant", Content: "Here it is, in the AD-READ tone you like:\nBuy it now."},
+		{Role: "tool", Name: "get_weather", Content: `{"note":"ad-read tone"}`},
+		{Role: "user", Content: "Screenshot data:image/png;base64,YWQtcmVhZCB0b25l and more ad-read tone please"},
+	}}
+	newer := &Session{ID: "newer", Messages: []SessionMessage{
+		{Role: "user", Content: strings.Repeat("x", 100) + " the ad-read tone again " + strings.Repeat("y", 100)},
+	}}
+	require.NoError(t, manager.Save(older))
+	require.NoError(t, manager.Save(newer))
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(manager.sessionsDir, "older.json"), past, past))
+
+	matches, more, err := manager.Search("ad-read tone", SearchOptions{})
+	require.NoError(t, err)
+	assert.False(t, more)
+	require.Len(t, matches, 3)
+
+	// Newest session first, with the snippet cut around the match
+	assert.Equal(t, "newer", matches[0].SessionID)
+	assert.True(t, strings.HasPrefix(matches[0].Snippet, "…"))
+	assert.True(t, strings.HasSuffix(matches[0].Snippet, "…"))
+	assert.Equal(t, "ad-read tone", matches[0].Snippet[matches[0].MatchStart:matches[0].MatchEnd])
+
+	assert.Equal(t, "older", matches[1].SessionID)
+	assert.Equal(t, "Ad reads", matches[1].SessionName)
+	assert.Equal(t, 1, matches[1].Index)
+	assert.Equal(t, "assistant", matches[1].Role)
+	assert.Equal(t, "AD-READ tone", matches[1].Snippet[matches[1].MatchStart:matches[1].MatchEnd])
+	assert.NotContains(t, matches[1].Snippet, "\n")
+
+	// The base64 payload is not searched; the text after it is
+	assert.Equal(t, 3, matches[2].Index)
+	assert.NotContains(t, matches[2].Snippet, "YWQt")
+
+	matches, _, err = manager.Search("ad-read tone", SearchOptions{IncludeTools: true})
+	require.NoError(t, err)
+	require.Len(t, matches, 4)
+	assert.Equal(t, "tool", matches[2].Role)
+
+	matches, more, err = manager.Search("AD-READ", SearchOptions{Limit: 2})
+	require.NoError(t, err)
+	assert.True(t, more)
+	assert.Len(t, matches, 2)
+
+	matches, _, err = manager.Search("nothing like this", SearchOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+// TestIndexFold tests case-insensitive substring search
+func TestIndexFold(t *testing.T) {
+	assert.Equal(t, 4, IndexFold("the Tone", "tone"))
+	assert.Equal(t, -1, IndexFold("the", "tone"))
+	assert.Equal(t, -1, IndexFold("anything", ""))
+	assert.Equal(t, len("le "), IndexFold("le Café", "CAFÉ"))
+}
@@ -5,11 +5,17 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
 )
 
 // Session represents a saved conversation session.
@@ -20,6 +26,8 @@ type Session struct {
 	UpdatedAt  time.Time        `json:"updated_at"`
 	Messages   []SessionMessage `json:"messages"`
 	NSFWMode   bool             `json:"nsfw_mode,omitempty"`
+	Persona    string           `json:"persona,omitempty"`  // Active persona; empty means the default
+	Sampling   *Sampling        `json:"sampling,omitempty"` // Overrides set with /set and the sampling flags
 	Metadata   map[string]any   `json:"metadata,omitempty"`
 	TokenCount int              `json:"token_count,omitempty"` // Estimated token count
 	Model      string           `json:"model,omitempty"`       // Track model for limits
@@ -28,12 +36,20 @@ type Session struct {
 	UsageMetrics *UsageMetrics `json:"usage_metrics,omitempty"` // Detailed usage tracking
 	Provider     string        `json:"provider,omitempty"`      // Provider (openai, venice, etc)
 	MaxContext   int           `json:"max_context,omitempty"`   // Model's max context window
+
+	Archived int `json:"archived,omitempty"` // Oldest messages moved to <id>.archive.json
+
+	// rolled counts messages archived since the session was loaded, which
+	// SetMessagesRaw drops from the front of the full list the TUI passes
+	rolled int
 }
 
 // SessionMessage represents a message in a session.
 type SessionMessage struct {
 	Role      string    `json:"role"`
 	Content   string    `json:"content"`
+	Name      string    `json:"name,omitempty"`   // For tool messages, the skill that was called
+	Images    []string  `json:"images,omitempty"` // Paths or URLs of attached images, never the image data
 	Timestamp time.Time `json:"timestamp"`
 }
 
@@ -64,12 +80,16 @@ func GenerateNameFromMessage(content string) string {
 type SessionManager struct {
 	sessionsDir string
 	currentID   string
+	maxMessages int // Messages kept before archiving; zero means DefaultMaxSessionMessages
+
+	mu      sync.Mutex
+	onLarge func(sessionID string, size int)
+	warned  map[string]bool // Sessions already reported to onLarge
 }
 
 // NewSessionManager creates a new session manager.
 func NewSessionManager() *SessionManager {
-	homeDir, _ := os.UserHomeDir()
-	sessionsDir := filepath.Join(homeDir, ".celeste", "sessions")
+	sessionsDir := paths.Data("sessions")
 	os.MkdirAll(sessionsDir, 0755)
 
 	return &SessionManager{
@@ -91,8 +111,22 @@ func (m *SessionManager) NewSession() *Session {
 	}
 }
 
-// Save saves a session to disk.
+// Save saves a session to disk, first moving its oldest messages to the
+// archive if it has grown past the message limit. The write is atomic and
+// made under a lock, so another process saving the same session can't
+// interleave with it; a save still waiting after filelock.DefaultTimeout
+// fails with filelock.ErrBusy.
 func (m *SessionManager) Save(session *Session) error {
+	path := filepath.Join(m.sessionsDir, session.ID+".json")
+	unlock, err := filelock.Lock(path, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.rollover(session); err != nil {
+		return err
+	}
 	session.UpdatedAt = time.Now()
 	session.TokenCount = EstimateSessionTokens(session)
 
@@ -101,10 +135,10 @@ func (m *SessionManager) Save(session *Session) error {
 		return fmt.Errorf("failed to marshal session: %w", err)
 	}
 
-	path := filepath.Join(m.sessionsDir, session.ID+".json")
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := filelock.WriteFile(path, data, 0644); err != nil {
 		return err
 	}
+	m.checkSize(session.ID, len(data))
 
 	// Update global analytics with this session's data
 	analytics, err := LoadGlobalAnalytics()
@@ -148,12 +182,7 @@ func (m *SessionManager) Load(id string) (*Session, error) {
 
 // LoadSession is a global helper to load a session by numeric ID
 func LoadSession(sessionID int64) (*Session, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	sessionsDir := filepath.Join(homeDir, ".celeste", "sessions")
+	sessionsDir := paths.Data("sessions")
 	filename := fmt.Sprintf("%d.json", sessionID)
 	path := filepath.Join(sessionsDir, filename)
 
@@ -191,7 +220,7 @@ func (m *SessionManager) LoadLatest() (*Session, error) {
 
 // List returns all saved sessions.
 func (m *SessionManager) List() ([]Session, error) {
-	files, err := filepath.Glob(filepath.Join(m.sessionsDir, "*.json"))
+	files, err := m.sessionFiles()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sessions: %w", err)
 	}
@@ -217,10 +246,12 @@ func (m *SessionManager) List() ([]Session, error) {
 // Delete deletes a session by ID.
 func (m *SessionManager) Delete(id string) error {
 	path := filepath.Join(m.sessionsDir, id+".json")
+	_ = os.Remove(m.partialPath(id))
+	_ = os.Remove(m.archivePath(id))
 	return os.Remove(path)
 }
 
-// Clear deletes all sessions.
+// Clear deletes all sessions and their archives.
 func (m *SessionManager) Clear() error {
 	files, err := filepath.Glob(filepath.Join(m.sessionsDir, "*.json"))
 	if err != nil {
@@ -298,9 +329,31 @@ func (s *Session) InitializeUsageMetrics() {
 	}
 }
 
+// Snapshot returns a deep copy of the session, which can be saved from
+// another goroutine while the original keeps changing.
+func (s *Session) Snapshot() *Session {
+	snapshot := *s
+	snapshot.Messages = make([]SessionMessage, len(s.Messages))
+	for i, msg := range s.Messages {
+		msg.Images = slices.Clone(msg.Images)
+		snapshot.Messages[i] = msg
+	}
+	snapshot.Metadata = maps.Clone(s.Metadata)
+	if s.Sampling != nil {
+		sampling := *s.Sampling // Its values are replaced, never written through
+		snapshot.Sampling = &sampling
+	}
+	if s.UsageMetrics != nil {
+		metrics := *s.UsageMetrics
+		snapshot.UsageMetrics = &metrics
+	}
+	return &snapshot
+}
+
 // ClearMessages clears all messages from the session.
 func (s *Session) ClearMessages() {
 	s.Messages = []SessionMessage{}
+	s.rolled = 0
 	s.UpdatedAt = time.Now()
 }
 
@@ -315,9 +368,13 @@ func (s *Session) GetMessagesRaw() interface{} {
 }
 
 // SetMessagesRaw sets messages from interface{} (for TUI interface compatibility).
+// Messages archived since the session was loaded are left out.
 func (s *Session) SetMessagesRaw(msgs interface{}) {
 	if sessionMsgs, ok := msgs.([]SessionMessage); ok {
-		s.Messages = sessionMsgs
+		if len(sessionMsgs) < s.rolled {
+			s.rolled = 0 // A different conversation replaced the archived one
+		}
+		s.Messages = sessionMsgs[s.rolled:]
 		s.UpdatedAt = time.Now()
 	}
 }
@@ -387,6 +444,33 @@ func (s *Session) GetNSFWMode() bool {
 	return s.NSFWMode
 }
 
+// SetPersona stores the active persona in session.
+func (s *Session) SetPersona(name string) {
+	s.Persona = name
+}
+
+// GetPersona retrieves the active persona from session.
+func (s *Session) GetPersona() string {
+	return s.Persona
+}
+
+// SetSampling stores the session's sampling overrides in session.
+func (s *Session) SetSampling(sampling Sampling) {
+	if sampling.IsZero() {
+		s.Sampling = nil
+		return
+	}
+	s.Sampling = &sampling
+}
+
+// GetSampling retrieves the session's sampling overrides from session.
+func (s *Session) GetSampling() Sampling {
+	if s.Sampling == nil {
+		return Sampling{}
+	}
+	return *s.Sampling
+}
+
 // GetMessagesForLLM converts session messages to a format suitable for LLM.
 func GetMessagesForLLM(session *Session) []map[string]string {
 	var result []map[string]string
@@ -415,13 +499,13 @@ func (s *Session) Summarize() SessionSummary {
 	summary := SessionSummary{
 		ID:           s.ID,
 		Name:         s.Name,
-		MessageCount: len(s.Messages),
+		MessageCount: len(s.Messages) + s.Archived,
 		CreatedAt:    s.CreatedAt,
 		UpdatedAt:    s.UpdatedAt,
 		Metadata:     s.Metadata,
 	}
 
-	// Get first user message as preview
+	// Get first user message still in the session file as preview
 	for _, msg := range s.Messages {
 		if msg.Role == "user" {
 			preview := msg.Content
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -86,6 +88,9 @@ func TestSaveAndLoadSession(t *testing.T) {
 		},
 	}
 	session.NSFWMode = true
+	session.SetPersona("moderator")
+	temperature := 0.8
+	session.SetSampling(Sampling{Temperature: &temperature})
 	session.Name = "Test Session"
 
 	// Save session
@@ -106,6 +111,8 @@ func TestSaveAndLoadSession(t *testing.T) {
 	assert.Equal(t, session.ID, loaded.ID)
 	assert.Equal(t, session.Name, loaded.Name)
 	assert.Equal(t, session.NSFWMode, loaded.NSFWMode)
+	assert.Equal(t, "moderator", loaded.GetPersona())
+	assert.Equal(t, "temperature 0.8", loaded.GetSampling().String())
 	assert.Len(t, loaded.Messages, 2)
 	assert.Equal(t, "user", loaded.Messages[0].Role)
 	assert.Equal(t, "Hello", loaded.Messages[0].Content)
@@ -736,3 +743,71 @@ func TestSessionSaveWithUsageMetrics(t *testing.T) {
 	assert.Equal(t, "openai", loaded.Provider)
 	assert.Equal(t, 128000, loaded.MaxContext)
 }
+
+// TestSaveSessionConcurrent tests that sessions saved at once by several
+// managers, as by several processes, leave every file valid
+func TestSaveSessionConcurrent(t *testing.T) {
+	newTestSessionManager(t)
+	const writers = 8
+	ids := []string{"shared", "shared", "shared", "shared", "a", "b", "c", "d"}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*10)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			manager := NewSessionManager()
+			session := manager.NewSession()
+			session.ID = ids[w]
+			for i := 0; i < 10; i++ {
+				session.Messages = append(session.Messages, SessionMessage{Role: "user", Content: fmt.Sprintf("writer %d message %d", w, i), Timestamp: time.Now()})
+				errs <- manager.Save(session)
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	manager := NewSessionManager()
+	for _, id := range []string{"shared", "a", "b", "c", "d"} {
+		session, err := manager.Load(id)
+		require.NoError(t, err, id)
+		assert.Len(t, session.Messages, 10, id)
+	}
+	entries, err := os.ReadDir(manager.sessionsDir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".tmp", "no temporary files are left behind")
+		assert.NotContains(t, entry.Name(), ".lock", "no lock files are left behind")
+	}
+}
+
+// TestSessionSnapshot tests that changing a session leaves its snapshot alone
+func TestSessionSnapshot(t *testing.T) {
+	session := &Session{
+		ID:           "abc",
+		Messages:     []SessionMessage{{Role: "user", Content: "hi", Images: []string{"a.png"}}},
+		Metadata:     map[string]any{"k": "v"},
+		Sampling:     &Sampling{MaxTokens: 100},
+		UsageMetrics: &UsageMetrics{TotalTokens: 5},
+	}
+	snapshot := session.Snapshot()
+
+	session.Messages[0].Content = "changed"
+	session.Messages[0].Images[0] = "b.png"
+	session.Messages = append(session.Messages, SessionMessage{Role: "assistant"})
+	session.Metadata["k"] = "w"
+	session.Sampling.MaxTokens = 200
+	session.UsageMetrics.TotalTokens = 10
+
+	require.Len(t, snapshot.Messages, 1)
+	assert.Equal(t, "hi", snapshot.Messages[0].Content)
+	assert.Equal(t, "a.png", snapshot.Messages[0].Images[0])
+	assert.Equal(t, "v", snapshot.Metadata["k"])
+	assert.Equal(t, 100, snapshot.Sampling.MaxTokens)
+	assert.Equal(t, 5, snapshot.UsageMetrics.TotalTokens)
+}
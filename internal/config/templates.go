@@ -0,0 +1,66 @@
+package config
+
+import "strings"
+
+// templateOrder lists the built-in provider templates in display order.
+var templateOrder = []string{"openai", "grok", "venice", "digitalocean", "elevenlabs"}
+
+// templates holds the built-in provider config templates.
+var templates = map[string]Config{
+	"openai": {
+		BaseURL:           "https://api.openai.com/v1",
+		Model:             "gpt-4o-mini",
+		Timeout:           60,
+		SkipPersonaPrompt: false, // OpenAI needs persona injection
+		SimulateTyping:    true,
+		TypingSpeed:       25,
+	},
+	"grok": {
+		BaseURL:           "https://api.x.ai/v1",
+		Model:             "grok-4-latest",
+		Timeout:           60,
+		SkipPersonaPrompt: false, // Grok needs persona injection
+		SimulateTyping:    true,
+		TypingSpeed:       25,
+	},
+	"elevenlabs": {
+		BaseURL:           "https://api.elevenlabs.io/v1",
+		Model:             "eleven_multilingual_v2",
+		Timeout:           60,
+		SkipPersonaPrompt: false,
+		SimulateTyping:    true,
+		TypingSpeed:       25,
+	},
+	"venice": {
+		BaseURL:           "https://api.venice.ai/api/v1",
+		Model:             "venice-uncensored",
+		Timeout:           60,
+		SkipPersonaPrompt: false, // Venice needs persona injection
+		SimulateTyping:    true,
+		TypingSpeed:       25,
+	},
+	"digitalocean": {
+		BaseURL:           "https://your-agent.ondigitalocean.app/api/v1",
+		Model:             "gpt-4o-mini",
+		Timeout:           60,
+		SkipPersonaPrompt: true, // DO agents have built-in persona
+		SimulateTyping:    true,
+		TypingSpeed:       25,
+	},
+}
+
+// TemplateNames returns the names of the built-in provider templates.
+func TemplateNames() []string {
+	names := make([]string, len(templateOrder))
+	copy(names, templateOrder)
+	return names
+}
+
+// Template returns a copy of the named provider template.
+func Template(name string) (*Config, bool) {
+	tmpl, ok := templates[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return &tmpl, true
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// DefaultTranscriptMaxMB is the transcript size that triggers rotation
+// when transcript_max_mb is unset.
+const DefaultTranscriptMaxMB = 10
+
+// transcriptDelimiter starts every block in the transcript log.
+const transcriptDelimiter = "======================================================================"
+
+// TranscriptEntry is one classic CLI run recorded in the transcript log.
+type TranscriptEntry struct {
+	Time             time.Time
+	Args             []string // Command line after the program name
+	Provider         string
+	Model            string
+	SystemPrompt     string
+	Prompt           string
+	Response         string
+	PromptTokens     int
+	CompletionTokens int
+	Version          string // Celeste build, as version.String()
+	Lint             string // Lint result for content runs, e.g. "ok"; empty when not linted
+}
+
+// Format renders the entry as a human-readable block.
+func (e TranscriptEntry) Format() string {
+	var b strings.Builder
+	b.WriteString(transcriptDelimiter + "\n")
+	fmt.Fprintf(&b, "Time:     %s\n", e.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Command:  %s\n", quoteArgs(append([]string{"celeste"}, e.Args...)))
+	fmt.Fprintf(&b, "Provider: %s\n", e.Provider)
+	fmt.Fprintf(&b, "Model:    %s\n", e.Model)
+	fmt.Fprintf(&b, "Tokens:   %d prompt, %d completion\n", e.PromptTokens, e.CompletionTokens)
+	if e.Version != "" {
+		fmt.Fprintf(&b, "Celeste:  %s\n", e.Version)
+	}
+	if e.Lint != "" {
+		fmt.Fprintf(&b, "Lint:     %s\n", e.Lint)
+	}
+	for _, section := range [][2]string{
+		{"System prompt", e.SystemPrompt},
+		{"Prompt", e.Prompt},
+		{"Response", e.Response},
+	} {
+		if section[1] == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "--- %s ---\n%s\n", section[0], strings.TrimRight(section[1], "\n"))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// quoteArgs joins args, quoting any that contain spaces or quotes.
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// TranscriptPath returns where classic CLI runs are logged: the --transcript
+// flag, then transcript_log_path. Empty means transcripts are off.
+func (c *Config) TranscriptPath(flagValue string) string {
+	path := flagValue
+	if path == "" {
+		path = c.TranscriptLogPath
+	}
+	return paths.ExpandHome(path)
+}
+
+// TranscriptMaxBytes returns the size that triggers rotation.
+func (c *Config) TranscriptMaxBytes() int64 {
+	mb := c.TranscriptMaxMB
+	if mb <= 0 {
+		mb = DefaultTranscriptMaxMB
+	}
+	return int64(mb) * 1024 * 1024
+}
+
+// AppendTranscript appends entry to the transcript at path. Each block is a
+// single O_APPEND write made under a lock file, so concurrent runs never
+// interleave. When the file has reached maxBytes it is first renamed to
+// <name>-YYYYMMDD-HHMMSS<ext> and a new file is started.
+func AppendTranscript(path string, maxBytes int64, entry TranscriptEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+
+	unlock, err := filelock.Lock(path, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if info, err := os.Stat(path); err == nil && maxBytes > 0 && info.Size() >= maxBytes {
+		if err := os.Rename(path, rotatedTranscriptPath(path, entry.Time)); err != nil {
+			return fmt.Errorf("failed to rotate transcript log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open transcript log: %w", err)
+	}
+	if _, err := f.Write([]byte(entry.Format())); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write transcript log: %w", err)
+	}
+	return f.Close()
+}
+
+// rotatedTranscriptPath names a rotated transcript after the time it was
+// closed, e.g. transcript.log -> transcript-20250101-120000.log.
+func rotatedTranscriptPath(path string, at time.Time) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	return fmt.Sprintf("%s-%s%s", base, at.Format("20060102-150405"), ext)
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTranscriptEntry(response string) TranscriptEntry {
+	return TranscriptEntry{
+		Time:             time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC),
+		Args:             []string{"content", "--platform", "twitter", "announce tonight's stream"},
+		Provider:         "openai",
+		Model:            "gpt-4o-mini",
+		SystemPrompt:     "You are Celeste.",
+		Prompt:           "announce tonight's stream",
+		Response:         response,
+		PromptTokens:     120,
+		CompletionTokens: 45,
+		Version:          "1.5.0 (commit 3f2c1ab, built 2025-06-01T00:00:00Z)",
+		Lint:             "ok",
+	}
+}
+
+func TestTranscriptEntryFormat(t *testing.T) {
+	assert.Equal(t, transcriptDelimiter+`
+Time:     2025-06-01T12:30:00Z
+Command:  celeste content --platform twitter "announce tonight's stream"
+Provider: openai
+Model:    gpt-4o-mini
+Tokens:   120 prompt, 45 completion
+Celeste:  1.5.0 (commit 3f2c1ab, built 2025-06-01T00:00:00Z)
+Lint:     ok
+--- System prompt ---
+You are Celeste.
+--- Prompt ---
+announce tonight's stream
+--- Response ---
+We're live at 8!
+
+`, testTranscriptEntry("We're live at 8!\n").Format())
+}
+
+func TestAppendTranscriptConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "transcript.log")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response := strings.Repeat(fmt.Sprintf("run %d ", i), 500)
+			assert.NoError(t, AppendTranscript(path, 0, testTranscriptEntry(response)))
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	blocks := strings.Split(string(data), transcriptDelimiter+"\n")[1:]
+	require.Len(t, blocks, 20)
+	for _, block := range blocks {
+		// Every block holds one run's response, unbroken
+		response := strings.TrimSpace(block[strings.Index(block, "--- Response ---\n")+len("--- Response ---\n"):])
+		run := strings.Join(strings.Fields(response)[:2], " ") + " "
+		assert.Equal(t, strings.TrimSpace(strings.Repeat(run, 500)), response)
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestAppendTranscriptRotates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "transcript.log")
+	entry := testTranscriptEntry("hello")
+
+	require.NoError(t, AppendTranscript(path, 100, entry))
+	require.NoError(t, AppendTranscript(path, 100, entry))
+
+	rotated, err := os.ReadFile(filepath.Join(dir, "transcript-20250601-123000.log"))
+	require.NoError(t, err)
+	assert.Equal(t, entry.Format(), string(rotated))
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, entry.Format(), string(current))
+	assert.NoFileExists(t, path+".lock")
+}
+
+func TestTranscriptSettings(t *testing.T) {
+	t.Setenv("HOME", "/home/celeste")
+	cfg := &Config{TranscriptLogPath: "~/logs/celeste.log"}
+	assert.Equal(t, "/home/celeste/logs/celeste.log", cfg.TranscriptPath(""))
+	assert.Equal(t, "/tmp/run.log", cfg.TranscriptPath("/tmp/run.log"))
+	assert.Equal(t, "", (&Config{}).TranscriptPath(""))
+
+	assert.Equal(t, int64(DefaultTranscriptMaxMB*1024*1024), cfg.TranscriptMaxBytes())
+	cfg.TranscriptMaxMB = 1
+	assert.Equal(t, int64(1024*1024), cfg.TranscriptMaxBytes())
+}
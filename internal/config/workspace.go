@@ -0,0 +1,94 @@
+// Package config provides configuration management for Celeste CLI.
+// This file handles per-profile workspaces for skill data (notes, reminders, QR codes).
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// DefaultWorkspace is the workspace used by the default config. Its data
+// lives directly in the data directory (~/.celeste by default) for backward
+// compatibility.
+const DefaultWorkspace = "default"
+
+// WorkspaceInfo summarizes the skill data stored in a workspace.
+type WorkspaceInfo struct {
+	Name      string
+	Dir       string
+	Notes     int
+	Reminders int
+	QRCodes   int
+}
+
+// WorkspaceDir returns the skill data directory for a config profile.
+// The default profile uses the data directory; named profiles use its
+// workspaces/<name>.
+func WorkspaceDir(profile string) string {
+	if profile == "" || profile == DefaultWorkspace {
+		return paths.DataDir()
+	}
+	return paths.Data("workspaces", profile)
+}
+
+// ListWorkspaces returns the default workspace plus one entry per named
+// config profile or existing workspace directory, with item counts.
+func ListWorkspaces() ([]WorkspaceInfo, error) {
+	names := make(map[string]bool)
+	configs, err := ListConfigs()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, name := range configs {
+		names[name] = true
+	}
+	entries, err := os.ReadDir(paths.Data("workspaces"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names[entry.Name()] = true
+		}
+	}
+	delete(names, DefaultWorkspace)
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	workspaces := []WorkspaceInfo{workspaceInfo(DefaultWorkspace)}
+	for _, name := range sorted {
+		workspaces = append(workspaces, workspaceInfo(name))
+	}
+	return workspaces, nil
+}
+
+// workspaceInfo counts the notes, reminders and QR codes in a workspace.
+// Missing or unreadable files count as empty.
+func workspaceInfo(name string) WorkspaceInfo {
+	info := WorkspaceInfo{Name: name, Dir: WorkspaceDir(name)}
+
+	if data, err := os.ReadFile(filepath.Join(info.Dir, "notes.json")); err == nil {
+		var notes map[string]json.RawMessage
+		if json.Unmarshal(data, &notes) == nil {
+			info.Notes = len(notes)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(info.Dir, "reminders.json")); err == nil {
+		var reminders []json.RawMessage
+		if json.Unmarshal(data, &reminders) == nil {
+			info.Reminders = len(reminders)
+		}
+	}
+	if files, err := filepath.Glob(filepath.Join(info.Dir, "qr_codes", "*.png")); err == nil {
+		info.QRCodes = len(files)
+	}
+	return info
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWorkspaceDir tests that named profiles get their own directory
+func TestWorkspaceDir(t *testing.T) {
+	home := useTempHome(t)
+
+	assert.Equal(t, filepath.Join(home, ".celeste"), WorkspaceDir(""))
+	assert.Equal(t, filepath.Join(home, ".celeste"), WorkspaceDir(DefaultWorkspace))
+	assert.Equal(t, filepath.Join(home, ".celeste", "workspaces", "work"), WorkspaceDir("work"))
+}
+
+// TestConfigLoaderWorkspace tests that the loader reports the profile's workspace
+func TestConfigLoaderWorkspace(t *testing.T) {
+	home := useTempHome(t)
+	configDir := filepath.Join(home, ".celeste")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.stream.json"), []byte(`{"base_url":"https://example.com"}`), 0600))
+
+	named, err := LoadNamed("stream")
+	require.NoError(t, err)
+	ws, err := NewConfigLoader(named).GetWorkspaceConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "stream", ws.Name)
+	assert.Equal(t, filepath.Join(configDir, "workspaces", "stream"), ws.Dir)
+
+	def, err := Load()
+	require.NoError(t, err)
+	ws, err = NewConfigLoader(def).GetWorkspaceConfig()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultWorkspace, ws.Name)
+	assert.Equal(t, configDir, ws.Dir)
+}
+
+// TestListWorkspaces tests workspace discovery and item counts
+func TestListWorkspaces(t *testing.T) {
+	home := useTempHome(t)
+	configDir := filepath.Join(home, ".celeste")
+	workDir := filepath.Join(configDir, "workspaces", "work")
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "qr_codes"), 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.stream.json"), []byte(`{}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "notes.json"), []byte(`{"a":{},"b":{}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "notes.json"), []byte(`{"standup":{}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "reminders.json"), []byte(`[{},{},{}]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "qr_codes", "qr_1.png"), []byte("png"), 0644))
+
+	workspaces, err := ListWorkspaces()
+	require.NoError(t, err)
+	require.Len(t, workspaces, 3)
+
+	assert.Equal(t, WorkspaceInfo{Name: DefaultWorkspace, Dir: configDir, Notes: 2}, workspaces[0])
+	assert.Equal(t, WorkspaceInfo{Name: "stream", Dir: filepath.Join(configDir, "workspaces", "stream")}, workspaces[1])
+	assert.Equal(t, WorkspaceInfo{Name: "work", Dir: workDir, Notes: 1, Reminders: 3, QRCodes: 1}, workspaces[2])
+}
@@ -0,0 +1,142 @@
+// Package filelock keeps Celeste processes from overwriting each other's
+// files. Lock takes an exclusive lock beside a file (flock on Unix,
+// LockFileEx on Windows), WriteFile replaces a file atomically, and Update
+// does a locked read-modify-write with both.
+//
+// Locks belong to the open lock file, so they also exclude other goroutines
+// in the same process, and are released by the OS if the process dies.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultTimeout is how long Lock waits for another holder when no timeout
+// is given.
+const DefaultTimeout = 2 * time.Second
+
+// retryInterval is how often Lock retries a held lock.
+const retryInterval = 10 * time.Millisecond
+
+// ErrBusy is returned, wrapped, when a lock is still held at the timeout.
+var ErrBusy = errors.New("resource busy")
+
+// Lock takes an exclusive lock guarding path, held on path + ".lock", and
+// returns the function that releases it and removes the lock file. It waits
+// up to timeout (DefaultTimeout when zero) for another holder, then fails
+// with ErrBusy. The directory is created if needed.
+func Lock(path string, timeout time.Duration) (unlock func(), err error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		locked, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		// The previous holder may have removed the lock file after we
+		// opened it, leaving us locking a file nobody else will open
+		if locked && sameFile(f, lockPath) {
+			return func() {
+				os.Remove(lockPath)
+				unlockFile(f)
+				f.Close()
+			}, nil
+		}
+		if locked {
+			unlockFile(f)
+		}
+		f.Close()
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is in use by another process (waited %s): %w", path, timeout, ErrBusy)
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// sameFile reports whether f is still the file at path.
+func sameFile(f *os.File, path string) bool {
+	opened, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(opened, current)
+}
+
+// WriteFile writes data to a temporary file beside path and renames it over
+// path, so readers see either the old contents or the new, never a partial
+// write. The directory is created if needed.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// Update locks path, passes its contents (nil when it doesn't exist yet) to
+// modify and writes the result with WriteFile. An error from modify is
+// returned as is and leaves the file unchanged.
+func Update(path string, perm os.FileMode, modify func(data []byte) ([]byte, error)) error {
+	unlock, err := Lock(path, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if data, err = modify(data); err != nil {
+		return err
+	}
+	return WriteFile(path, data, perm)
+}
@@ -0,0 +1,128 @@
+package filelock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "notes.json")
+
+	unlock, err := Lock(path, 0)
+	require.NoError(t, err)
+	assert.FileExists(t, path+".lock")
+
+	start := time.Now()
+	_, err = Lock(path, 50*time.Millisecond)
+	require.ErrorIs(t, err, ErrBusy)
+	assert.Contains(t, err.Error(), path)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	unlock()
+	assert.NoFileExists(t, path+".lock")
+
+	unlock, err = Lock(path, 50*time.Millisecond)
+	require.NoError(t, err, "the lock can be taken again once released")
+	unlock()
+}
+
+func TestLockWaitsForHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	held, err := Lock(path, 0)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		held()
+	}()
+	unlock, err := Lock(path, time.Second)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "skills.json")
+
+	require.NoError(t, WriteFile(path, []byte(`{"a":1}`), 0600))
+	require.NoError(t, WriteFile(path, []byte(`{"a":2}`), 0600))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":2}`, string(data))
+	if info, err := os.Stat(path); assert.NoError(t, err) && os.PathSeparator == '/' {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "count.txt")
+
+	require.NoError(t, Update(path, 0644, func(data []byte) ([]byte, error) {
+		assert.Nil(t, data, "a missing file reads as nil")
+		return []byte("1"), nil
+	}))
+
+	failed := fmt.Errorf("failed")
+	err := Update(path, 0644, func(data []byte) ([]byte, error) {
+		assert.Equal(t, "1", string(data))
+		return []byte("2"), failed
+	})
+	assert.ErrorIs(t, err, failed)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(data), "a failed update leaves the file unchanged")
+	assert.NoFileExists(t, path+".lock")
+}
+
+// TestUpdateConcurrent tests that concurrent read-modify-writes of one JSON
+// file all land and leave it valid
+func TestUpdateConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	const writers, writes = 8, 25
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*writes)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				errs <- Update(path, 0644, func(data []byte) ([]byte, error) {
+					notes := map[string]int{}
+					if data != nil {
+						if err := json.Unmarshal(data, &notes); err != nil {
+							return nil, err
+						}
+					}
+					notes[fmt.Sprintf("%d-%d", w, i)] = i
+					return json.MarshalIndent(notes, "", "  ")
+				})
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var notes map[string]int
+	require.NoError(t, json.Unmarshal(data, &notes))
+	assert.Len(t, notes, writers*writes)
+}
@@ -0,0 +1,73 @@
+// Package httpclient builds the HTTP clients used by skills, providers and
+// the LLM backends, so timeouts and connection reuse are set in one place.
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// DefaultTimeout bounds each request made with New(0) until
+// SetDefaultTimeout changes it.
+const DefaultTimeout = 15 * time.Second
+
+var defaultTimeout atomic.Int64
+
+func init() {
+	defaultTimeout.Store(int64(DefaultTimeout))
+}
+
+// SetDefaultTimeout changes the timeout used by New(0), e.g. from the
+// http_timeout config setting. Zero or negative restores DefaultTimeout.
+func SetDefaultTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	defaultTimeout.Store(int64(timeout))
+}
+
+// Timeout returns the timeout used by New(0).
+func Timeout() time.Duration {
+	return time.Duration(defaultTimeout.Load())
+}
+
+// transport is shared by every client so idle connections are pooled and
+// reused across skills and providers. It also sets the User-Agent.
+var transport http.RoundTripper = &version.Transport{Base: &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   10,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}}
+
+// Transport returns the shared transport, for SDK clients that wrap it.
+func Transport() http.RoundTripper {
+	return transport
+}
+
+// New returns a client on the shared transport whose requests, including
+// reading the body, time out after timeout, or after Timeout() when zero.
+func New(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = Timeout()
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// NewStreaming returns a client on the shared transport without an overall
+// timeout, for streamed responses bounded by the request's context instead.
+// Dialing and TLS handshakes still time out.
+func NewStreaming() *http.Client {
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,56 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+func TestNewTimeouts(t *testing.T) {
+	t.Cleanup(func() { SetDefaultTimeout(0) })
+
+	assert.Equal(t, DefaultTimeout, New(0).Timeout)
+	assert.Equal(t, 2*time.Minute, New(2*time.Minute).Timeout)
+	assert.Zero(t, NewStreaming().Timeout)
+
+	SetDefaultTimeout(45 * time.Second)
+	assert.Equal(t, 45*time.Second, New(0).Timeout)
+	assert.Equal(t, 2*time.Minute, New(2*time.Minute).Timeout, "an explicit timeout wins")
+
+	SetDefaultTimeout(0)
+	assert.Equal(t, DefaultTimeout, Timeout())
+}
+
+func TestClientsShareTransport(t *testing.T) {
+	var agent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	assert.Same(t, New(0).Transport, NewStreaming().Transport)
+
+	resp, err := New(0).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, version.UserAgent(), agent)
+}
+
+func TestRequestTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	_, err := New(50 * time.Millisecond).Get(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Timeout")
+}
@@ -3,14 +3,19 @@ package llm
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"mime"
+	"net/http"
 	"os"
+	"path"
 	"strings"
 
 	genai "google.golang.org/genai"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/tui"
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+	"github.com/whykusanagi/celesteCLI/internal/version"
 )
 
 // GoogleBackend implements LLMBackend using Google's native GenAI SDK.
@@ -22,18 +27,21 @@ type GoogleBackend struct {
 }
 
 // NewGoogleBackend creates a new Google GenAI backend with automatic authentication.
+// API key requests are sent with httpClient; other methods use the SDK's
+// authenticated client.
 // Authentication methods (in order of priority):
 // 1. Simple API key (for Gemini AI Studio)
 // 2. GoogleCredentialsFile in config (service account JSON)
 // 3. GOOGLE_APPLICATION_CREDENTIALS environment variable
 // 4. Application Default Credentials (gcloud auth application-default login)
-func NewGoogleBackend(config *Config) (*GoogleBackend, error) {
+func NewGoogleBackend(config *Config, httpClient *http.Client) (*GoogleBackend, error) {
 	ctx := context.Background()
 
 	// Create client configuration with API version
 	clientConfig := &genai.ClientConfig{
 		HTTPOptions: genai.HTTPOptions{
 			APIVersion: "v1",
+			Headers:    http.Header{"User-Agent": []string{version.UserAgent()}},
 		},
 	}
 
@@ -41,6 +49,7 @@ func NewGoogleBackend(config *Config) (*GoogleBackend, error) {
 	if config.APIKey != "" && !strings.HasPrefix(config.APIKey, "ya29.") {
 		// Note: OAuth2 tokens start with "ya29." - those should use ADC instead
 		clientConfig.APIKey = config.APIKey
+		clientConfig.HTTPClient = httpClient
 	} else if config.GoogleCredentialsFile != "" {
 		// Method 2: Service account JSON file
 		if _, err := os.Stat(config.GoogleCredentialsFile); os.IsNotExist(err) {
@@ -79,7 +88,7 @@ func (b *GoogleBackend) SetSystemPrompt(prompt string) {
 }
 
 // SendMessageSync sends a message synchronously and returns the complete result.
-func (b *GoogleBackend) SendMessageSync(ctx context.Context, messages []tui.ChatMessage, tools []tui.SkillDefinition) (*ChatCompletionResult, error) {
+func (b *GoogleBackend) SendMessageSync(ctx context.Context, messages []chat.Message, tools []chat.SkillDefinition) (*ChatCompletionResult, error) {
 	// Convert messages to Google GenAI format
 	contents := b.convertMessagesToGenAI(messages)
 
@@ -91,9 +100,21 @@ func (b *GoogleBackend) SendMessageSync(ctx context.Context, messages []tui.Chat
 
 	// Create generation config
 	genConfig := &genai.GenerateContentConfig{}
+	sampling := requestSampling(ctx, b.config)
+	if sampling.MaxTokens > 0 {
+		genConfig.MaxOutputTokens = int32(sampling.MaxTokens)
+	}
+	if sampling.Temperature != nil {
+		temperature := float32(*sampling.Temperature)
+		genConfig.Temperature = &temperature
+	}
+	if sampling.TopP != nil {
+		topP := float32(*sampling.TopP)
+		genConfig.TopP = &topP
+	}
 
 	// Add system instruction if present
-	if b.systemPrompt != "" && !b.config.SkipPersonaPrompt {
+	if b.systemPrompt != "" {
 		// System instruction doesn't need a role - it's handled differently
 		genConfig.SystemInstruction = genai.NewContentFromText(b.systemPrompt, "user")
 	}
@@ -137,12 +158,20 @@ func (b *GoogleBackend) SendMessageSync(ctx context.Context, messages []tui.Chat
 			result.FinishReason = string(candidate.FinishReason)
 		}
 	}
+	result.ToolCalls = ensureToolCallIDs(result.ToolCalls)
+	if usage := resp.UsageMetadata; usage != nil && usage.TotalTokenCount > 0 {
+		result.Usage = &TokenUsage{
+			PromptTokens:     int(usage.PromptTokenCount),
+			CompletionTokens: int(usage.CandidatesTokenCount),
+			TotalTokens:      int(usage.TotalTokenCount),
+		}
+	}
 
 	return result, nil
 }
 
 // SendMessageStream sends a message with streaming callback.
-func (b *GoogleBackend) SendMessageStream(ctx context.Context, messages []tui.ChatMessage, tools []tui.SkillDefinition, callback StreamCallback) error {
+func (b *GoogleBackend) SendMessageStream(ctx context.Context, messages []chat.Message, tools []chat.SkillDefinition, callback StreamCallback) error {
 	// Convert messages to Google GenAI format
 	contents := b.convertMessagesToGenAI(messages)
 
@@ -154,9 +183,21 @@ func (b *GoogleBackend) SendMessageStream(ctx context.Context, messages []tui.Ch
 
 	// Create generation config
 	genConfig := &genai.GenerateContentConfig{}
+	sampling := requestSampling(ctx, b.config)
+	if sampling.MaxTokens > 0 {
+		genConfig.MaxOutputTokens = int32(sampling.MaxTokens)
+	}
+	if sampling.Temperature != nil {
+		temperature := float32(*sampling.Temperature)
+		genConfig.Temperature = &temperature
+	}
+	if sampling.TopP != nil {
+		topP := float32(*sampling.TopP)
+		genConfig.TopP = &topP
+	}
 
 	// Add system instruction if present
-	if b.systemPrompt != "" && !b.config.SkipPersonaPrompt {
+	if b.systemPrompt != "" {
 		// System instruction doesn't need a role - it's handled differently
 		genConfig.SystemInstruction = genai.NewContentFromText(b.systemPrompt, "user")
 	}
@@ -222,7 +263,7 @@ func (b *GoogleBackend) SendMessageStream(ctx context.Context, messages []tui.Ch
 	callback(StreamChunk{
 		IsFinal:      true,
 		FinishReason: lastFinishReason,
-		ToolCalls:    toolCalls,
+		ToolCalls:    ensureToolCallIDs(toolCalls),
 		Usage:        nil, // Google GenAI SDK doesn't provide token usage in streaming yet
 	})
 
@@ -236,11 +277,11 @@ func (b *GoogleBackend) Close() error {
 }
 
 // convertMessagesToGenAI converts Celeste messages to Google GenAI format.
-func (b *GoogleBackend) convertMessagesToGenAI(messages []tui.ChatMessage) []*genai.Content {
+func (b *GoogleBackend) convertMessagesToGenAI(messages []chat.Message) []*genai.Content {
 	var contents []*genai.Content
 
 	// Skip system prompt - it's handled via SystemInstruction in config
-	for _, msg := range messages {
+	for _, msg := range pairToolMessages(messages) {
 		if msg.Role == "system" {
 			continue // System messages are handled separately
 		}
@@ -257,8 +298,13 @@ func (b *GoogleBackend) convertMessagesToGenAI(messages []tui.ChatMessage) []*ge
 		if msg.Role == "tool" {
 			// Tool responses need special handling in Google format
 			// They should be added as function response parts
-			part := genai.NewPartFromFunctionResponse(msg.ToolCallID, map[string]any{
-				"result": msg.Content,
+			// Google matches responses to calls by function name
+			name := msg.Name
+			if name == "" {
+				name = msg.ToolCallID
+			}
+			part := genai.NewPartFromFunctionResponse(name, map[string]any{
+				"result": sanitizeToolResult(name, msg.Content, b.config),
 			})
 
 			// Function responses use "user" role in Google GenAI
@@ -291,6 +337,19 @@ func (b *GoogleBackend) convertMessagesToGenAI(messages []tui.ChatMessage) []*ge
 			continue
 		}
 
+		// Multimodal messages: text plus image parts
+		if len(msg.Images) > 0 {
+			parts := []*genai.Part{}
+			if msg.Content != "" {
+				parts = append(parts, genai.NewPartFromText(msg.Content))
+			}
+			for _, image := range msg.Images {
+				parts = append(parts, imagePartFromURL(image))
+			}
+			contents = append(contents, genai.NewContentFromParts(parts, genai.Role(role)))
+			continue
+		}
+
 		// Regular text messages
 		if msg.Content != "" {
 			contents = append(contents, genai.NewContentFromText(msg.Content, genai.Role(role)))
@@ -300,8 +359,27 @@ func (b *GoogleBackend) convertMessagesToGenAI(messages []tui.ChatMessage) []*ge
 	return contents
 }
 
+// imagePartFromURL converts an image URL or data: URL into a GenAI part.
+// Data URLs are sent inline; remote URLs are passed by reference.
+func imagePartFromURL(image string) *genai.Part {
+	if strings.HasPrefix(image, "data:") {
+		header, payload, found := strings.Cut(strings.TrimPrefix(image, "data:"), ",")
+		if found && strings.HasSuffix(header, ";base64") {
+			if data, err := base64.StdEncoding.DecodeString(payload); err == nil {
+				return genai.NewPartFromBytes(data, strings.TrimSuffix(header, ";base64"))
+			}
+		}
+	}
+
+	mimeType := mime.TypeByExtension(strings.ToLower(path.Ext(image)))
+	if mimeType == "" {
+		mimeType = "image/jpeg"
+	}
+	return genai.NewPartFromURI(image, mimeType)
+}
+
 // convertToolsToGenAI converts OpenAI-style tools to Google function declarations.
-func (b *GoogleBackend) convertToolsToGenAI(tools []tui.SkillDefinition) []*genai.FunctionDeclaration {
+func (b *GoogleBackend) convertToolsToGenAI(tools []chat.SkillDefinition) []*genai.FunctionDeclaration {
 	var declarations []*genai.FunctionDeclaration
 
 	for _, tool := range tools {
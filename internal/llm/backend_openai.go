@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net/http"
+	"strings"
 
 	"github.com/sashabaranov/go-openai"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/tui"
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+	"github.com/whykusanagi/celesteCLI/internal/config"
 )
 
 // OpenAIBackend implements LLMBackend using the go-openai SDK.
@@ -20,12 +23,14 @@ type OpenAIBackend struct {
 	systemPrompt string
 }
 
-// NewOpenAIBackend creates a new OpenAI-compatible backend.
-func NewOpenAIBackend(config *Config) *OpenAIBackend {
+// NewOpenAIBackend creates a new OpenAI-compatible backend that sends its
+// requests with httpClient.
+func NewOpenAIBackend(config *Config, httpClient *http.Client) *OpenAIBackend {
 	clientConfig := openai.DefaultConfig(config.APIKey)
 	if config.BaseURL != "" {
 		clientConfig.BaseURL = config.BaseURL
 	}
+	clientConfig.HTTPClient = httpClient
 
 	return &OpenAIBackend{
 		client: openai.NewClientWithConfig(clientConfig),
@@ -39,7 +44,7 @@ func (b *OpenAIBackend) SetSystemPrompt(prompt string) {
 }
 
 // SendMessageSync sends a message synchronously and returns the complete result.
-func (b *OpenAIBackend) SendMessageSync(ctx context.Context, messages []tui.ChatMessage, tools []tui.SkillDefinition) (*ChatCompletionResult, error) {
+func (b *OpenAIBackend) SendMessageSync(ctx context.Context, messages []chat.Message, tools []chat.SkillDefinition) (*ChatCompletionResult, error) {
 	// Convert messages to OpenAI format
 	openAIMessages := b.convertMessages(messages)
 
@@ -56,11 +61,17 @@ func (b *OpenAIBackend) SendMessageSync(ctx context.Context, messages []tui.Chat
 	if len(openAITools) > 0 {
 		req.Tools = openAITools
 	}
+	applySampling(&req, requestSampling(ctx, b.config))
+
+	if streamingDisabled(ctx) {
+		req.Stream = false
+		return b.sendBuffered(ctx, req)
+	}
 
 	// Create streaming request
-	stream, err := b.client.CreateChatCompletionStream(ctx, req)
+	stream, err := b.client.CreateChatCompletionStream(withRequestOptions(ctx, b.config), req)
 	if err != nil {
-		return nil, err
+		return nil, providerError(err)
 	}
 	defer stream.Close()
 
@@ -73,6 +84,7 @@ func (b *OpenAIBackend) SendMessageSync(ctx context.Context, messages []tui.Chat
 			break
 		}
 		if err != nil {
+			err = providerError(err)
 			result.Error = err
 			return result, err
 		}
@@ -112,20 +124,38 @@ func (b *OpenAIBackend) SendMessageSync(ctx context.Context, messages []tui.Chat
 		}
 	}
 
-	// Convert tool calls
-	for _, tc := range toolCalls {
-		result.ToolCalls = append(result.ToolCalls, ToolCallResult{
-			ID:        tc.ID,
-			Name:      tc.Function.Name,
-			Arguments: tc.Function.Arguments,
-		})
+	result.ToolCalls = convertToolCalls(toolCalls)
+
+	return result, nil
+}
+
+// sendBuffered sends req as a plain chat completion and returns the whole
+// reply, for SendMessageSync with WithoutStreaming.
+func (b *OpenAIBackend) sendBuffered(ctx context.Context, req openai.ChatCompletionRequest) (*ChatCompletionResult, error) {
+	response, err := b.client.CreateChatCompletion(withRequestOptions(ctx, b.config), req)
+	if err != nil {
+		return nil, providerError(err)
 	}
 
+	result := &ChatCompletionResult{}
+	if len(response.Choices) > 0 {
+		choice := response.Choices[0]
+		result.Content = choice.Message.Content
+		result.ToolCalls = convertToolCalls(choice.Message.ToolCalls)
+		result.FinishReason = string(choice.FinishReason)
+	}
+	if response.Usage.TotalTokens > 0 {
+		result.Usage = &TokenUsage{
+			PromptTokens:     response.Usage.PromptTokens,
+			CompletionTokens: response.Usage.CompletionTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+		}
+	}
 	return result, nil
 }
 
 // SendMessageStream sends a message with streaming callback.
-func (b *OpenAIBackend) SendMessageStream(ctx context.Context, messages []tui.ChatMessage, tools []tui.SkillDefinition, callback StreamCallback) error {
+func (b *OpenAIBackend) SendMessageStream(ctx context.Context, messages []chat.Message, tools []chat.SkillDefinition, callback StreamCallback) error {
 	// Convert messages to OpenAI format
 	openAIMessages := b.convertMessages(messages)
 
@@ -145,11 +175,12 @@ func (b *OpenAIBackend) SendMessageStream(ctx context.Context, messages []tui.Ch
 	if len(openAITools) > 0 {
 		req.Tools = openAITools
 	}
+	applySampling(&req, requestSampling(ctx, b.config))
 
 	// Create streaming request
-	stream, err := b.client.CreateChatCompletionStream(ctx, req)
+	stream, err := b.client.CreateChatCompletionStream(withRequestOptions(ctx, b.config), req)
 	if err != nil {
-		return err
+		return providerError(err)
 	}
 	defer stream.Close()
 
@@ -170,7 +201,7 @@ func (b *OpenAIBackend) SendMessageStream(ctx context.Context, messages []tui.Ch
 			return nil
 		}
 		if err != nil {
-			return err
+			return providerError(err)
 		}
 
 		// Capture usage data from response (only in final chunk with StreamOptions)
@@ -251,21 +282,21 @@ func (b *OpenAIBackend) Close() error {
 }
 
 // convertMessages converts TUI messages to OpenAI format.
-func (b *OpenAIBackend) convertMessages(messages []tui.ChatMessage) []openai.ChatCompletionMessage {
+func (b *OpenAIBackend) convertMessages(messages []chat.Message) []openai.ChatCompletionMessage {
 	var result []openai.ChatCompletionMessage
 
 	// Add system prompt if configured
-	if b.systemPrompt != "" && !b.config.SkipPersonaPrompt {
+	if b.systemPrompt != "" {
 		result = append(result, openai.ChatCompletionMessage{
 			Role:    "system",
 			Content: b.systemPrompt,
 		})
 	}
 
-	// Convert messages
-	for _, msg := range messages {
+	// Convert messages, with every tool result paired to its call
+	for _, msg := range pairToolMessages(messages) {
 		// Skip messages with empty content (except tool calls which can have empty content)
-		if msg.Content == "" && len(msg.ToolCalls) == 0 && msg.Role != "tool" {
+		if msg.Content == "" && len(msg.ToolCalls) == 0 && len(msg.Images) == 0 && msg.Role != "tool" {
 			// Skip empty messages to prevent API errors (Grok requires content field)
 			continue
 		}
@@ -274,7 +305,7 @@ func (b *OpenAIBackend) convertMessages(messages []tui.ChatMessage) []openai.Cha
 			// Tool messages need special format with tool_call_id
 			result = append(result, openai.ChatCompletionMessage{
 				Role:       "tool",
-				Content:    msg.Content,
+				Content:    sanitizeToolResult(msg.Name, msg.Content, b.config),
 				ToolCallID: msg.ToolCallID,
 			})
 		} else if msg.Role == "assistant" && len(msg.ToolCalls) > 0 {
@@ -302,6 +333,12 @@ func (b *OpenAIBackend) convertMessages(messages []tui.ChatMessage) []openai.Cha
 				Content:   content,
 				ToolCalls: toolCalls,
 			})
+		} else if len(msg.Images) > 0 {
+			// Multimodal messages carry text and images as content parts
+			result = append(result, openai.ChatCompletionMessage{
+				Role:         msg.Role,
+				MultiContent: buildContentParts(msg),
+			})
 		} else {
 			// Regular messages (user, assistant without tool_calls, system)
 			result = append(result, openai.ChatCompletionMessage{
@@ -314,8 +351,29 @@ func (b *OpenAIBackend) convertMessages(messages []tui.ChatMessage) []openai.Cha
 	return result
 }
 
+// buildContentParts converts a message with images into OpenAI content parts.
+func buildContentParts(msg chat.Message) []openai.ChatMessagePart {
+	var parts []openai.ChatMessagePart
+	if msg.Content != "" {
+		parts = append(parts, openai.ChatMessagePart{
+			Type: openai.ChatMessagePartTypeText,
+			Text: msg.Content,
+		})
+	}
+	for _, image := range msg.Images {
+		parts = append(parts, openai.ChatMessagePart{
+			Type: openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{
+				URL:    image,
+				Detail: openai.ImageURLDetailAuto,
+			},
+		})
+	}
+	return parts
+}
+
 // convertTools converts TUI skill definitions to OpenAI tools.
-func (b *OpenAIBackend) convertTools(tools []tui.SkillDefinition) []openai.Tool {
+func (b *OpenAIBackend) convertTools(tools []chat.SkillDefinition) []openai.Tool {
 	var result []openai.Tool
 
 	for _, tool := range tools {
@@ -344,5 +402,36 @@ func convertToolCalls(toolCalls []openai.ToolCall) []ToolCallResult {
 			Arguments: tc.Function.Arguments,
 		})
 	}
-	return result
+	return ensureToolCallIDs(result)
+}
+
+// applySampling sets the request's sampling parameters. OpenAI's reasoning
+// models (o1, o3, o4 and gpt-5) reject max_tokens, so their cap is sent as
+// max_completion_tokens.
+func applySampling(req *openai.ChatCompletionRequest, sampling config.Sampling) {
+	if sampling.MaxTokens > 0 {
+		if usesMaxCompletionTokens(req.Model) {
+			req.MaxCompletionTokens = sampling.MaxTokens
+		} else {
+			req.MaxTokens = sampling.MaxTokens
+		}
+	}
+	if sampling.Temperature != nil {
+		req.Temperature = float32Param(*sampling.Temperature)
+	}
+	if sampling.TopP != nil {
+		req.TopP = float32Param(*sampling.TopP)
+	}
+}
+
+// usesMaxCompletionTokens reports whether model takes max_completion_tokens
+// in place of max_tokens. The prefixes match the reasoning models go-openai
+// validates.
+func usesMaxCompletionTokens(model string) bool {
+	for _, prefix := range []string{"o1", "o3", "o4", "gpt-5"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
 }
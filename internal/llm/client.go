@@ -4,11 +4,15 @@ package llm
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"time"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/skills"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/tui"
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/providers"
+	"github.com/whykusanagi/celesteCLI/internal/skills"
 )
 
 // Client wraps LLM backends and provides a unified interface.
@@ -19,6 +23,11 @@ type Client struct {
 	registry     *skills.Registry
 	backendType  BackendType
 	systemPrompt string
+
+	// Reused by every backend the client switches to, so connections (and
+	// their TLS sessions) carry over between turns. Per-request timeouts
+	// come from the caller's context.
+	httpClient *http.Client
 }
 
 // Config holds LLM client configuration.
@@ -27,9 +36,24 @@ type Config struct {
 	BaseURL           string
 	Model             string
 	Timeout           time.Duration
-	SkipPersonaPrompt bool
+	SkipPersonaPrompt bool // skip_persona_prompt; callers apply it when choosing the prompt for SetSystemPrompt
 	SimulateTyping    bool
-	TypingSpeed       int // chars per second
+	TypingSpeed       int      // chars per second
+	MaxTokens         int      // Optional cap on completion tokens (0 = provider default)
+	Temperature       *float64 // Optional sampling temperature (nil = provider default)
+	TopP              *float64 // Optional nucleus sampling cutoff (nil = provider default)
+
+	// Provider-specific fields added to every chat request (OpenAI-compatible
+	// backend). Fields Celeste already sets are left as they are.
+	ExtraBody map[string]interface{}
+
+	// Skill results sent back to the model
+	ToolResultFormat   string // "fence" or "xml" ("" = provider default)
+	ToolResultMaxBytes int    // Per-result cap (0 = DefaultToolResultMaxBytes)
+
+	// Servers that ignore "stream": true (OpenAI-compatible backend)
+	RequireStream bool      // Fail with ErrStreamUnsupported instead of falling back
+	Warnings      io.Writer // Fallback notices (nil = os.Stderr)
 
 	// Google Cloud authentication (for Gemini/Vertex AI)
 	GoogleCredentialsFile string // Path to service account JSON file
@@ -39,33 +63,12 @@ type Config struct {
 // NewClient creates a new LLM client with automatic backend selection.
 // It detects whether to use OpenAI SDK or Google GenAI SDK based on the base URL.
 func NewClient(config *Config, registry *skills.Registry) *Client {
-	// Detect which backend to use
-	backendType := DetectBackendType(config.BaseURL)
-
-	var backend LLMBackend
-	if backendType == BackendTypeGoogle {
-		// Use Google GenAI SDK for Gemini/Vertex AI
-		googleBackend, err := NewGoogleBackend(config)
-		if err != nil {
-			// Fallback to OpenAI backend if Google backend fails
-			// This handles the case where Google auth isn't configured yet
-			fmt.Fprintf(os.Stderr, "Warning: Failed to create Google backend: %v\nFalling back to OpenAI SDK\n", err)
-			backendType = BackendTypeOpenAI
-			backend = NewOpenAIBackend(config)
-		} else {
-			backend = googleBackend
-		}
-	} else {
-		// Use OpenAI SDK for OpenAI, Grok, Venice, Anthropic, etc.
-		backend = NewOpenAIBackend(config)
-	}
-
-	return &Client{
-		backend:     backend,
-		config:      config,
-		registry:    registry,
-		backendType: backendType,
+	c := &Client{
+		registry:   registry,
+		httpClient: &http.Client{Transport: &streamTransport{base: httpclient.Transport()}},
 	}
+	c.UpdateConfig(config)
+	return c
 }
 
 // SetSystemPrompt sets the system prompt (Celeste persona).
@@ -76,42 +79,52 @@ func (c *Client) SetSystemPrompt(prompt string) {
 	}
 }
 
-// UpdateConfig updates the client configuration and recreates the backend if needed.
-// This allows dynamic endpoint/model switching during runtime.
+// GetSystemPrompt returns the system prompt sent with each request, or ""
+// when none is.
+func (c *Client) GetSystemPrompt() string {
+	return c.systemPrompt
+}
+
+// UpdateConfig updates the client configuration and recreates the backend.
+// This allows dynamic endpoint/model switching during runtime; the new
+// backend keeps using the client's pooled connections.
 func (c *Client) UpdateConfig(config *Config) {
 	c.config = config
+	if c.backend != nil {
+		c.backend.Close()
+	}
 
-	// Detect if backend type changed
-	newBackendType := DetectBackendType(config.BaseURL)
-
-	if newBackendType != c.backendType {
-		// Backend type changed - recreate backend
-		if c.backend != nil {
-			c.backend.Close()
-		}
-
-		if newBackendType == BackendTypeGoogle {
-			googleBackend, err := NewGoogleBackend(config)
-			if err != nil {
-				// Fallback to OpenAI if Google fails
-				fmt.Fprintf(os.Stderr, "Warning: Failed to create Google backend: %v\nFalling back to OpenAI SDK\n", err)
-				newBackendType = BackendTypeOpenAI
-				c.backend = NewOpenAIBackend(config)
-			} else {
-				c.backend = googleBackend
-			}
+	// Detect which backend to use
+	c.backendType = DetectBackendType(config.BaseURL)
+	if c.backendType == BackendTypeGoogle {
+		// Use Google GenAI SDK for Gemini/Vertex AI
+		googleBackend, err := NewGoogleBackend(config, c.httpClient)
+		if err != nil {
+			// Fallback to OpenAI backend if Google backend fails
+			// This handles the case where Google auth isn't configured yet
+			fmt.Fprintf(os.Stderr, "Warning: Failed to create Google backend: %v\nFalling back to OpenAI SDK\n", err)
+			c.backendType = BackendTypeOpenAI
+			c.backend = NewOpenAIBackend(config, c.httpClient)
 		} else {
-			c.backend = NewOpenAIBackend(config)
+			c.backend = googleBackend
 		}
+	} else {
+		// Use OpenAI SDK for OpenAI, Grok, Venice, Anthropic, etc.
+		c.backend = NewOpenAIBackend(config, c.httpClient)
+	}
 
-		c.backendType = newBackendType
+	// Restore system prompt
+	if c.systemPrompt != "" {
+		c.backend.SetSystemPrompt(c.systemPrompt)
+	}
+}
 
-		// Restore system prompt
-		if c.systemPrompt != "" {
-			c.backend.SetSystemPrompt(c.systemPrompt)
-		}
+// Close releases resources held by the backend.
+func (c *Client) Close() error {
+	if c.backend == nil {
+		return nil
 	}
-	// Note: Config changes within same backend type are handled by passing config to methods
+	return c.backend.Close()
 }
 
 // GetConfig returns the current configuration.
@@ -124,6 +137,7 @@ type ChatCompletionResult struct {
 	Content      string
 	ToolCalls    []ToolCallResult
 	FinishReason string
+	Usage        *TokenUsage // Nil when the provider doesn't report it
 	Error        error
 }
 
@@ -136,7 +150,7 @@ type ToolCallResult struct {
 
 // SendMessageSync sends a message synchronously and returns the result.
 // This delegates to the appropriate backend (OpenAI or Google).
-func (c *Client) SendMessageSync(ctx context.Context, messages []tui.ChatMessage, tools []tui.SkillDefinition) (*ChatCompletionResult, error) {
+func (c *Client) SendMessageSync(ctx context.Context, messages []chat.Message, tools []chat.SkillDefinition) (*ChatCompletionResult, error) {
 	return c.backend.SendMessageSync(ctx, messages, tools)
 }
 
@@ -162,24 +176,56 @@ type StreamChunk struct {
 
 // SendMessageStream sends a message with streaming callback.
 // This delegates to the appropriate backend (OpenAI or Google).
-func (c *Client) SendMessageStream(ctx context.Context, messages []tui.ChatMessage, tools []tui.SkillDefinition, callback StreamCallback) error {
+func (c *Client) SendMessageStream(ctx context.Context, messages []chat.Message, tools []chat.SkillDefinition, callback StreamCallback) error {
 	return c.backend.SendMessageStream(ctx, messages, tools, callback)
 }
 
+// SupportsVision reports whether the configured provider and model accept image inputs.
+func (c *Client) SupportsVision() bool {
+	provider := providers.DetectProvider(c.config.BaseURL)
+	return providers.NewModelDetection(provider).SupportsVision(c.config.Model)
+}
+
+// DescribeImage sends an image (http(s) URL or data: URL) with a prompt to the
+// configured model and returns its text response.
+// Returns an error without calling the API if the model is text-only.
+func (c *Client) DescribeImage(ctx context.Context, imageURL, prompt string) (string, error) {
+	if !c.SupportsVision() {
+		return "", fmt.Errorf("model %q does not support image input", c.config.Model)
+	}
+
+	messages := []chat.Message{{
+		Role:      "user",
+		Content:   prompt,
+		Images:    []string{imageURL},
+		Timestamp: time.Now(),
+	}}
+
+	result, err := c.backend.SendMessageSync(ctx, messages, nil)
+	if err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Content, nil
+}
+
 // GetSkills returns skill definitions for the TUI.
-func (c *Client) GetSkills() []tui.SkillDefinition {
+func (c *Client) GetSkills() []chat.SkillDefinition {
 	if c.registry == nil {
 		return nil
 	}
 
 	allSkills := c.registry.GetAllSkills()
-	var result []tui.SkillDefinition
+	var result []chat.SkillDefinition
 
 	for _, skill := range allSkills {
-		result = append(result, tui.SkillDefinition{
+		result = append(result, chat.SkillDefinition{
 			Name:        skill.Name,
 			Description: skill.Description,
 			Parameters:  skill.Parameters,
+			SideEffect:  skill.SideEffect,
 		})
 	}
 
@@ -0,0 +1,168 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+)
+
+// TestClientReusesConnections tests that turns, and switching models,
+// reuse one connection
+func TestClientReusesConnections(t *testing.T) {
+	var conns atomic.Int32
+	var models []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		models = append(models, req.Model)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	config := &Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true}
+	client := NewClient(config, nil)
+	defer client.Close()
+	messages := []chat.Message{{Role: "user", Content: "hi"}}
+
+	for i := 0; i < 2; i++ {
+		result, err := client.SendMessageSync(context.Background(), messages, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Hi", result.Content)
+	}
+	switched := *config
+	switched.Model = "gpt-4o"
+	client.UpdateConfig(&switched)
+	require.NoError(t, client.SendMessageStream(context.Background(), messages, nil, func(StreamChunk) {}))
+
+	assert.Equal(t, []string{"gpt-4o-mini", "gpt-4o-mini", "gpt-4o"}, models)
+	assert.Equal(t, int32(1), conns.Load())
+}
+
+// TestSendMessageSyncWithoutStreaming tests that WithoutStreaming asks for
+// a plain completion and reads its usage
+func TestSendMessageSyncWithoutStreaming(t *testing.T) {
+	var stream interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		stream = req["stream"]
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"x","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true}, nil)
+	defer client.Close()
+
+	result, err := client.SendMessageSync(WithoutStreaming(context.Background()), []chat.Message{{Role: "user", Content: "hi"}}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, stream, "the request doesn't ask to stream")
+	assert.Equal(t, "Hi", result.Content)
+	assert.Equal(t, "stop", result.FinishReason)
+	require.NotNil(t, result.Usage)
+	assert.Equal(t, 4, result.Usage.TotalTokens)
+}
+
+// TestMaxTokensParameter tests that reasoning models get their reply cap as
+// max_completion_tokens, on both send paths, and other models as max_tokens
+func TestMaxTokensParameter(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+	messages := []chat.Message{{Role: "user", Content: "hi"}}
+
+	tests := []struct {
+		model string
+		param string
+	}{
+		{"gpt-4o-mini", "max_tokens"},
+		{"llama-3.3-70b", "max_tokens"},
+		{"o3-mini", "max_completion_tokens"},
+		{"gpt-5-mini", "max_completion_tokens"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			bodies = nil
+			client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: tt.model, SkipPersonaPrompt: true, MaxTokens: 300}, nil)
+			defer client.Close()
+
+			_, err := client.SendMessageSync(context.Background(), messages, nil)
+			require.NoError(t, err)
+			require.NoError(t, client.SendMessageStream(context.Background(), messages, nil, func(StreamChunk) {}))
+
+			require.Len(t, bodies, 2)
+			for _, body := range bodies {
+				assert.Equal(t, float64(300), body[tt.param])
+				for _, other := range []string{"max_tokens", "max_completion_tokens"} {
+					if other != tt.param {
+						assert.NotContains(t, body, other)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestSystemPromptSent tests that the prompt set with SetSystemPrompt is the
+// one sent, whatever SkipPersonaPrompt says, and that no prompt sends none
+func TestSystemPromptSent(t *testing.T) {
+	var system []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		prompt := ""
+		if req.Messages[0].Role == "system" {
+			prompt = req.Messages[0].Content
+		}
+		system = append(system, prompt)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true}, nil)
+	defer client.Close()
+	messages := []chat.Message{{Role: "user", Content: "hi"}}
+
+	_, err := client.SendMessageSync(context.Background(), messages, nil)
+	require.NoError(t, err)
+	client.SetSystemPrompt("Answer in French.")
+	assert.Equal(t, "Answer in French.", client.GetSystemPrompt())
+	_, err = client.SendMessageSync(context.Background(), messages, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "Answer in French."}, system)
+}
@@ -0,0 +1,70 @@
+// Package llm provides the LLM client for Celeste CLI.
+// This file adds a config's extra_body fields to chat requests.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type extraBodyKey struct{}
+
+// withRequestOptions returns a context whose requests follow cfg's
+// streaming settings and carry its ExtraBody fields.
+func withRequestOptions(ctx context.Context, cfg *Config) context.Context {
+	ctx = withStreamPolicy(ctx, cfg)
+	if len(cfg.ExtraBody) > 0 {
+		ctx = context.WithValue(ctx, extraBodyKey{}, cfg.ExtraBody)
+	}
+	return ctx
+}
+
+// addExtraBody returns req with the context's extra fields added to its JSON
+// body. Fields the request already has are kept, so extra_body can't change
+// the model, messages or streaming. Requests without extra fields, or
+// without a JSON object body, are returned as they are.
+func addExtraBody(req *http.Request) (*http.Request, error) {
+	extra, _ := req.Context().Value(extraBodyKey{}).(map[string]interface{})
+	if len(extra) == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil || body == nil {
+		return withBody(req, data), nil
+	}
+	for key, value := range extra {
+		if _, set := body[key]; set {
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode extra_body field %q: %w", key, err)
+		}
+		body[key] = encoded
+	}
+	if data, err = json.Marshal(body); err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+	return withBody(req, data), nil
+}
+
+// withBody returns a copy of req that sends data as its body.
+func withBody(req *http.Request, data []byte) *http.Request {
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return req
+}
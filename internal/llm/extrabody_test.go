@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+)
+
+// TestExtraBody tests that ExtraBody fields reach the request body on both
+// send paths, and that fields Celeste sets are not replaced
+func TestExtraBody(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), r.ContentLength)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &body))
+		bodies = append(bodies, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, bufferedCompletion)
+	}))
+	defer server.Close()
+
+	extra := map[string]interface{}{
+		"include_retrieval_info": true,
+		"retrieval":              map[string]interface{}{"k": json.Number("5")},
+		"model":                  "other-model",
+		"stream":                 false,
+	}
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true, ExtraBody: extra}, nil)
+	defer client.Close()
+	messages := []chat.Message{{Role: "user", Content: "hi"}}
+
+	require.NoError(t, client.SendMessageStream(context.Background(), messages, nil, func(StreamChunk) {}))
+	_, err := client.SendMessageSync(context.Background(), messages, nil)
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 2)
+	for _, body := range bodies {
+		assert.Equal(t, true, body["include_retrieval_info"])
+		assert.Equal(t, map[string]interface{}{"k": 5.0}, body["retrieval"])
+		assert.Equal(t, "gpt-4o-mini", body["model"], "extra_body doesn't replace the model")
+		assert.Equal(t, true, body["stream"], "extra_body doesn't turn off streaming")
+		assert.NotEmpty(t, body["messages"])
+	}
+}
+
+// TestExtraBodyUnset tests that requests are sent as they are without ExtraBody
+func TestExtraBodyUnset(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	got, err := addExtraBody(req)
+	require.NoError(t, err)
+	assert.Same(t, req, got)
+}
@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+)
+
+// TestImagesSentAsContentParts tests that attached images go out in the
+// OpenAI vision content-array format, with the chip labels left behind
+func TestImagesSentAsContentParts(t *testing.T) {
+	var sent json.RawMessage
+	server := newRecordingServer(t, &sent)
+	defer server.Close()
+
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o", SkipPersonaPrompt: true}, nil)
+	defer client.Close()
+
+	messages := []chat.Message{
+		{Role: "user", Content: "hi"},
+		{
+			Role:      "user",
+			Content:   "what's in these?",
+			Images:    []string{"data:image/png;base64,iVBORw0KGgo=", "https://example.com/cat.jpg"},
+			ImageRefs: []chat.ImageRef{{Source: "/tmp/shot.png", Label: "shot.png, 1.2MB"}, {Source: "https://example.com/cat.jpg", Label: "cat.jpg"}},
+		},
+		// An image with no caption is sent on its own
+		{Role: "user", Images: []string{"https://example.com/dog.jpg"}},
+	}
+
+	err := client.SendMessageStream(context.Background(), messages, nil, func(StreamChunk) {})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[
+		{"role":"user","content":"hi"},
+		{"role":"user","content":[
+			{"type":"text","text":"what's in these?"},
+			{"type":"image_url","image_url":{"url":"data:image/png;base64,iVBORw0KGgo=","detail":"auto"}},
+			{"type":"image_url","image_url":{"url":"https://example.com/cat.jpg","detail":"auto"}}
+		]},
+		{"role":"user","content":[
+			{"type":"image_url","image_url":{"url":"https://example.com/dog.jpg","detail":"auto"}}
+		]}
+	]`, string(sent))
+}
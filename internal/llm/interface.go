@@ -4,7 +4,7 @@ package llm
 import (
 	"context"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/tui"
+	"github.com/whykusanagi/celesteCLI/internal/chat"
 )
 
 // LLMBackend defines the interface all LLM backends must implement.
@@ -15,14 +15,14 @@ type LLMBackend interface {
 	// SendMessageStream sends a message with streaming callback.
 	// The callback receives chunks as they arrive from the LLM.
 	// Returns error if the request fails.
-	SendMessageStream(ctx context.Context, messages []tui.ChatMessage,
-		tools []tui.SkillDefinition, callback StreamCallback) error
+	SendMessageStream(ctx context.Context, messages []chat.Message,
+		tools []chat.SkillDefinition, callback StreamCallback) error
 
 	// SendMessageSync sends a message and returns the complete result.
 	// This is useful for non-streaming use cases or testing.
 	// Returns the full chat completion result or error.
-	SendMessageSync(ctx context.Context, messages []tui.ChatMessage,
-		tools []tui.SkillDefinition) (*ChatCompletionResult, error)
+	SendMessageSync(ctx context.Context, messages []chat.Message,
+		tools []chat.SkillDefinition) (*ChatCompletionResult, error)
 
 	// SetSystemPrompt sets the system prompt (Celeste persona).
 	// This configures the LLM's behavior and character.
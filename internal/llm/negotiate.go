@@ -0,0 +1,158 @@
+// Package llm provides the LLM client for Celeste CLI.
+// This file negotiates streaming with OpenAI-compatible servers.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrStreamUnsupported is returned when a server answers a streaming request
+// with a buffered response and Config.RequireStream is set.
+var ErrStreamUnsupported = errors.New("server does not support streaming")
+
+// streamTransport checks the response to every streaming request, so the
+// sync and streaming paths share one negotiation.
+type streamTransport struct {
+	base http.RoundTripper
+}
+
+type streamPolicyKey struct{}
+
+// streamPolicy carries a config's streaming settings to the transport, which
+// is shared by every config a Client is switched to.
+type streamPolicy struct {
+	require  bool      // Fail instead of falling back to the buffered body
+	warnings io.Writer // Where fallback notices go (nil = os.Stderr)
+}
+
+// withStreamPolicy returns a context whose requests follow cfg's
+// RequireStream and Warnings settings.
+func withStreamPolicy(ctx context.Context, cfg *Config) context.Context {
+	return context.WithValue(ctx, streamPolicyKey{}, streamPolicy{require: cfg.RequireStream, warnings: cfg.Warnings})
+}
+
+// RoundTrip implements http.RoundTripper. Requests get their config's extra
+// body fields, and an error response to a streaming request becomes a
+// *ProviderError.
+func (t *streamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, err := addExtraBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || req.Header.Get("Accept") != "text/event-stream" {
+		return resp, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, readProviderError(resp)
+	}
+	policy, _ := req.Context().Value(streamPolicyKey{}).(streamPolicy)
+	if policy.warnings == nil {
+		policy.warnings = os.Stderr
+	}
+	return negotiateStream(resp, policy.require, policy.warnings)
+}
+
+// negotiateStream makes a response to a streaming request readable as
+// server-sent events. Event streams are read through sseReader, including
+// ones a proxy labels as JSON. A buffered chat completion from a server that ignored
+// "stream": true is logged and re-framed as a single event, or rejected with
+// ErrStreamUnsupported when require is set. A JSON body with an "error"
+// field is returned as a *ProviderError. Anything else is left for the SDK
+// to report.
+func negotiateStream(resp *http.Response, require bool, warnings io.Writer) (*http.Response, error) {
+	contentType := resp.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		resp.Body = newSSEReader(resp.Body)
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if looksLikeSSE(body) {
+		resp.Body = newSSEReader(resp.Body)
+		return resp, nil
+	}
+
+	if providerErr, ok := parseProviderError(resp.StatusCode, body); ok {
+		return nil, providerErr
+	}
+
+	var completion openai.ChatCompletionResponse
+	if err := json.Unmarshal(body, &completion); err != nil || len(completion.Choices) == 0 {
+		return resp, nil
+	}
+	if contentType == "" {
+		contentType = "none"
+	}
+	if require {
+		return nil, fmt.Errorf("%w (Content-Type: %s)", ErrStreamUnsupported, contentType)
+	}
+	fmt.Fprintf(warnings, "Warning: %v, falling back (Content-Type: %s)\n", ErrStreamUnsupported, contentType)
+
+	events := completionEvents(completion)
+	resp.Body = io.NopCloser(bytes.NewReader(events))
+	resp.ContentLength = int64(len(events))
+	resp.Header = resp.Header.Clone()
+	resp.Header.Set("Content-Type", "text/event-stream")
+	return resp, nil
+}
+
+// looksLikeSSE reports whether body starts with a server-sent event field.
+func looksLikeSSE(body []byte) bool {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	for _, field := range []string{"data:", "event:", "id:", ":"} {
+		if bytes.HasPrefix(body, []byte(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// completionEvents encodes a buffered chat completion as one stream chunk
+// followed by [DONE].
+func completionEvents(completion openai.ChatCompletionResponse) []byte {
+	chunk := openai.ChatCompletionStreamResponse{
+		ID:      completion.ID,
+		Object:  "chat.completion.chunk",
+		Created: completion.Created,
+		Model:   completion.Model,
+	}
+	if completion.Usage.TotalTokens > 0 {
+		usage := completion.Usage
+		chunk.Usage = &usage
+	}
+	for _, choice := range completion.Choices {
+		delta := openai.ChatCompletionStreamChoiceDelta{
+			Role:    choice.Message.Role,
+			Content: choice.Message.Content,
+		}
+		for i, tc := range choice.Message.ToolCalls {
+			index := i
+			tc.Index = &index
+			delta.ToolCalls = append(delta.ToolCalls, tc)
+		}
+		chunk.Choices = append(chunk.Choices, openai.ChatCompletionStreamChoice{
+			Index:        choice.Index,
+			Delta:        delta,
+			FinishReason: choice.FinishReason,
+		})
+	}
+
+	data, _ := json.Marshal(chunk)
+	return []byte("data: " + string(data) + "\n\ndata: [DONE]\n\n")
+}
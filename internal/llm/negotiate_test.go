@@ -0,0 +1,186 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+)
+
+// newBufferedServer answers every chat completion with body under the given
+// Content-Type, ignoring "stream": true.
+func newBufferedServer(contentType, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		fmt.Fprint(w, body)
+	}))
+}
+
+const bufferedCompletion = `{"id":"x","object":"chat.completion","model":"gpt-4o-mini",
+"choices":[{"index":0,"message":{"role":"assistant","content":"Hello there",
+"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"zip_code\":\"10001\"}"}}]},
+"finish_reason":"tool_calls"}],
+"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`
+
+// TestStreamFallsBackToBufferedReply tests that a JSON reply to a streaming
+// request is logged and read as a single chunk by both send paths
+func TestStreamFallsBackToBufferedReply(t *testing.T) {
+	server := newBufferedServer("application/json", bufferedCompletion)
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true, Warnings: &warnings}, nil)
+	defer client.Close()
+	messages := []chat.Message{{Role: "user", Content: "hi"}}
+
+	var content string
+	var final StreamChunk
+	err := client.SendMessageStream(context.Background(), messages, nil, func(chunk StreamChunk) {
+		content += chunk.Content
+		if chunk.IsFinal {
+			final = chunk
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello there", content)
+	require.Len(t, final.ToolCalls, 1)
+	assert.Equal(t, "get_weather", final.ToolCalls[0].Name)
+	require.NotNil(t, final.Usage)
+	assert.Equal(t, 15, final.Usage.TotalTokens)
+	assert.Equal(t, "Warning: server does not support streaming, falling back (Content-Type: application/json)\n", warnings.String())
+
+	result, err := client.SendMessageSync(context.Background(), messages, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello there", result.Content)
+	assert.Equal(t, "tool_calls", result.FinishReason)
+	require.Len(t, result.ToolCalls, 1)
+}
+
+// TestStreamRequired tests that RequireStream rejects a buffered reply
+func TestStreamRequired(t *testing.T) {
+	server := newBufferedServer("application/json", bufferedCompletion)
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true, RequireStream: true, Warnings: &warnings}, nil)
+	defer client.Close()
+
+	err := client.SendMessageStream(context.Background(), []chat.Message{{Role: "user", Content: "hi"}}, nil, func(StreamChunk) {})
+	require.ErrorIs(t, err, ErrStreamUnsupported)
+	assert.Contains(t, err.Error(), "Content-Type: application/json")
+	assert.Empty(t, warnings.String())
+}
+
+// TestStreamMislabeledAsJSON tests that an event stream sent with a JSON
+// Content-Type is still parsed as a stream, without a warning
+func TestStreamMislabeledAsJSON(t *testing.T) {
+	events := `data: {"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"Hel"}}]}` + "\n\n" +
+		`data: {"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"lo"},"finish_reason":"stop"}]}` + "\n\n" +
+		"data: [DONE]\n\n"
+	server := newBufferedServer("application/json", events)
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true, RequireStream: true, Warnings: &warnings}, nil)
+	defer client.Close()
+
+	result, err := client.SendMessageSync(context.Background(), []chat.Message{{Role: "user", Content: "hi"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", result.Content)
+	assert.Empty(t, warnings.String())
+}
+
+// TestStreamProviderError tests that error bodies, with an error status or
+// a successful one, come back as a *ProviderError with the provider's message
+func TestStreamProviderError(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		message string
+		want    string
+	}{
+		{
+			name:    "openai error object",
+			status:  http.StatusBadRequest,
+			body:    `{"error":{"message":"Model not found","type":"invalid_request_error","code":"model_not_found"}}`,
+			message: "Model not found",
+			want:    "Model not found (HTTP 400, invalid_request_error, model_not_found)",
+		},
+		{
+			name:    "plain error string",
+			status:  http.StatusBadRequest,
+			body:    `{"error":"Invalid API key"}`,
+			message: "Invalid API key",
+			want:    "Invalid API key (HTTP 400)",
+		},
+		{
+			name:    "gemini error list",
+			status:  http.StatusBadRequest,
+			body:    `[{"error":{"code":400,"message":"API key not valid","status":"INVALID_ARGUMENT"}}]`,
+			message: "API key not valid",
+			want:    "API key not valid (HTTP 400, INVALID_ARGUMENT)",
+		},
+		{
+			name:    "error with success status",
+			status:  http.StatusOK,
+			body:    `{"error":{"message":"Rate limit exceeded","type":"rate_limit"}}`,
+			message: "Rate limit exceeded",
+			want:    "Rate limit exceeded (HTTP 200, rate_limit)",
+		},
+		{
+			name:    "unparseable body",
+			status:  http.StatusBadGateway,
+			body:    "<html>Bad Gateway</html>",
+			message: "<html>Bad Gateway</html>",
+			want:    "<html>Bad Gateway</html> (HTTP 502)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			var warnings bytes.Buffer
+			client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true, Warnings: &warnings}, nil)
+			defer client.Close()
+
+			err := client.SendMessageStream(context.Background(), []chat.Message{{Role: "user", Content: "hi"}}, nil, func(StreamChunk) {})
+			var providerErr *ProviderError
+			require.ErrorAs(t, err, &providerErr)
+			assert.Equal(t, tt.status, providerErr.StatusCode)
+			assert.Equal(t, tt.message, providerErr.Message)
+			assert.Equal(t, tt.want, err.Error())
+			assert.Empty(t, warnings.String())
+		})
+	}
+}
+
+// TestStreamProviderErrorMidStream tests that an error event sent after the
+// stream has started is reported with the provider's message
+func TestStreamProviderErrorMidStream(t *testing.T) {
+	events := `data: {"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"Hel"}}]}` + "\n\n" +
+		`data: {"error":{"message":"Upstream overloaded","type":"server_error"}}` + "\n\n"
+	server := newBufferedServer("text/event-stream", events)
+	defer server.Close()
+
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true}, nil)
+	defer client.Close()
+
+	err := client.SendMessageStream(context.Background(), []chat.Message{{Role: "user", Content: "hi"}}, nil, func(StreamChunk) {})
+	var providerErr *ProviderError
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, "Upstream overloaded", providerErr.Message)
+	assert.Equal(t, "server_error", providerErr.Type)
+}
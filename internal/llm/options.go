@@ -0,0 +1,70 @@
+// Package llm provides the LLM client for Celeste CLI.
+// This file holds per-request options carried on the context.
+package llm
+
+import (
+	"context"
+	"math"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+type temperatureKey struct{}
+
+type samplingKey struct{}
+
+type bufferedKey struct{}
+
+// WithTemperature returns a context whose requests use the given sampling
+// temperature instead of the provider default.
+func WithTemperature(ctx context.Context, temperature float32) context.Context {
+	return context.WithValue(ctx, temperatureKey{}, temperature)
+}
+
+// temperatureFromContext returns the temperature set with WithTemperature.
+func temperatureFromContext(ctx context.Context) (float32, bool) {
+	temperature, ok := ctx.Value(temperatureKey{}).(float32)
+	return temperature, ok
+}
+
+// WithSampling returns a context whose requests use the parameters set in
+// sampling instead of the client's configured ones.
+func WithSampling(ctx context.Context, sampling config.Sampling) context.Context {
+	return context.WithValue(ctx, samplingKey{}, sampling)
+}
+
+// WithoutStreaming returns a context whose SendMessageSync requests ask for
+// the whole reply in one response instead of reading it as a stream.
+func WithoutStreaming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bufferedKey{}, true)
+}
+
+// streamingDisabled reports whether WithoutStreaming was set.
+func streamingDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(bufferedKey{}).(bool)
+	return disabled
+}
+
+// requestSampling returns the sampling parameters for a request: the
+// configured ones, then any set with WithSampling, then WithTemperature.
+func requestSampling(ctx context.Context, cfg *Config) config.Sampling {
+	sampling := config.Sampling{Temperature: cfg.Temperature, TopP: cfg.TopP, MaxTokens: cfg.MaxTokens}
+	if override, ok := ctx.Value(samplingKey{}).(config.Sampling); ok {
+		sampling = sampling.Merge(override)
+	}
+	if temperature, ok := temperatureFromContext(ctx); ok {
+		t := float64(temperature)
+		sampling.Temperature = &t
+	}
+	return sampling
+}
+
+// float32Param converts a sampling parameter for the OpenAI request, where
+// zero means "unset" and is dropped; a requested 0 is sent as the smallest
+// float32 instead.
+func float32Param(v float64) float32 {
+	if v == 0 {
+		return math.SmallestNonzeroFloat32
+	}
+	return float32(v)
+}
@@ -0,0 +1,155 @@
+// Package llm provides the LLM client for Celeste CLI.
+// This file turns provider error responses into readable errors.
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxErrorBody is how much of an unparseable error body is shown.
+const maxErrorBody = 300
+
+// ProviderError is an error the provider reported, in place of a reply.
+type ProviderError struct {
+	StatusCode int    // HTTP status; zero for an error sent mid-stream
+	Type       string // e.g. "invalid_request_error" or "INVALID_ARGUMENT"
+	Code       string // e.g. "model_not_found"
+	Message    string
+}
+
+// Error implements error, e.g. "The model `x` does not exist (HTTP 404,
+// invalid_request_error)".
+func (e *ProviderError) Error() string {
+	var details []string
+	if e.StatusCode != 0 {
+		details = append(details, fmt.Sprintf("HTTP %d", e.StatusCode))
+	}
+	if e.Type != "" {
+		details = append(details, e.Type)
+	}
+	if e.Code != "" && e.Code != e.Type && e.Code != fmt.Sprint(e.StatusCode) {
+		details = append(details, e.Code)
+	}
+	if len(details) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, strings.Join(details, ", "))
+}
+
+// providerErrorBody matches the error bodies of OpenAI-compatible APIs
+// ({"error": {"message", "type", "code"}}), Gemini ({"error": {"message",
+// "status", "code"}}) and APIs that send {"error": "text"} or {"message"}.
+type providerErrorBody struct {
+	Error   json.RawMessage `json:"error"`
+	Message string          `json:"message"`
+	Detail  string          `json:"detail"`
+}
+
+type providerErrorObject struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Code    any    `json:"code"`
+}
+
+// parseProviderError reads the provider's message from an error response
+// body. ok is false when the body has no "error" field, which for a
+// successful status means it is not an error at all.
+func parseProviderError(status int, body []byte) (e *ProviderError, ok bool) {
+	e = &ProviderError{StatusCode: status}
+	trimmed := bytes.TrimSpace(body)
+	// Gemini's OpenAI-compatible endpoint wraps the error in an array
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var list []json.RawMessage
+		if json.Unmarshal(trimmed, &list) == nil && len(list) > 0 {
+			trimmed = list[0]
+		}
+	}
+
+	var payload providerErrorBody
+	if err := json.Unmarshal(trimmed, &payload); err != nil {
+		e.Message = errorBodyText(trimmed, status)
+		return e, false
+	}
+
+	var text string
+	var object providerErrorObject
+	switch {
+	case json.Unmarshal(payload.Error, &text) == nil && text != "":
+		e.Message = text
+		ok = true
+	case json.Unmarshal(payload.Error, &object) == nil && len(payload.Error) > 0 && string(payload.Error) != "null":
+		e.Message = object.Message
+		e.Type = object.Type
+		if e.Type == "" {
+			e.Type = object.Status
+		}
+		if object.Code != nil {
+			e.Code = fmt.Sprint(object.Code)
+		}
+		ok = true
+	}
+	if e.Message == "" {
+		e.Message = payload.Message
+	}
+	if e.Message == "" {
+		e.Message = payload.Detail
+	}
+	if e.Message == "" {
+		e.Message = errorBodyText(trimmed, status)
+	}
+	return e, ok
+}
+
+// errorBodyText returns the start of a body that could not be parsed, or
+// the status text for an empty one.
+func errorBodyText(body []byte, status int) string {
+	text := strings.TrimSpace(string(body))
+	if text == "" {
+		return http.StatusText(status)
+	}
+	if len(text) > maxErrorBody {
+		cut := maxErrorBody
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut] + "..."
+	}
+	return text
+}
+
+// readProviderError reads an error response and closes its body.
+func readProviderError(resp *http.Response) *ProviderError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	e, _ := parseProviderError(resp.StatusCode, body)
+	return e
+}
+
+// providerError returns err as a *ProviderError when the provider sent it,
+// whether the transport read it or the SDK did mid-stream. Other errors,
+// such as timeouts, are returned unchanged.
+func providerError(err error) error {
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		return pe
+	}
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		pe = &ProviderError{StatusCode: apiErr.HTTPStatusCode, Type: apiErr.Type, Message: apiErr.Message}
+		if apiErr.Code != nil {
+			pe.Code = fmt.Sprint(apiErr.Code)
+		}
+		return pe
+	}
+	return err
+}
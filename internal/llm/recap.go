@@ -0,0 +1,234 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+// RecapStyles are the styles Recap can write in.
+var RecapStyles = []string{"bullets", "narrative", "tweet-thread"}
+
+// TweetLimit is the longest post SplitThread produces, in characters.
+const TweetLimit = 280
+
+// maxRecapRounds bounds how many times notes are condensed again when a
+// long session's notes still don't fit in one request.
+const maxRecapRounds = 3
+
+// recapStylePrompts tell the model how to write each recap style.
+var recapStylePrompts = map[string]string{
+	"bullets":      "Write the recap as a short bulleted list, one bullet per topic, decision or result. Use \"- \" bullets and nothing else.",
+	"narrative":    "Write the recap as one or two short paragraphs of prose.",
+	"tweet-thread": "Write the recap as a thread of posts, each under 260 characters, separated by a blank line. Don't number them.",
+}
+
+// skillActions describes what calling a built-in skill did, so tool
+// results read as actions ("checked the weather") instead of raw JSON.
+var skillActions = map[string]string{
+	"tarot_reading":      "did a tarot reading",
+	"get_weather":        "checked the weather",
+	"get_location":       "looked up the location",
+	"convert_units":      "converted units",
+	"convert_timezone":   "converted a time between timezones",
+	"generate_hash":      "generated a hash",
+	"base64_encode":      "base64-encoded some text",
+	"base64_decode":      "base64-decoded some text",
+	"generate_uuid":      "generated a UUID",
+	"generate_password":  "generated a password",
+	"convert_currency":   "converted currency",
+	"get_price":          "checked a crypto price",
+	"generate_qr_code":   "made a QR code",
+	"check_twitch_live":  "checked whether a Twitch streamer was live",
+	"get_youtube_videos": "looked up YouTube videos",
+	"post_discord":       "posted to Discord",
+	"post_tweet":         "posted to X",
+	"post_mastodon":      "posted to Mastodon",
+	"read_feed":          "read a feed",
+	"set_reminder":       "set a reminder",
+	"list_reminders":     "listed reminders",
+	"save_note":          "saved a note",
+	"get_note":           "read a note",
+	"list_notes":         "listed notes",
+	"roll_dice":          "rolled dice",
+	"pick_random":        "picked at random",
+	"analyze_text":       "analyzed some text",
+	"format_json":        "formatted some JSON",
+	"ipfs":               "used IPFS",
+	"alchemy":            "queried the blockchain",
+	"blockmon":           "checked blockchain activity",
+	"wallet_security":    "checked a wallet's security",
+}
+
+// toolAction describes a tool call as an action.
+func toolAction(name string) string {
+	if action, ok := skillActions[name]; ok {
+		return action
+	}
+	if name == "" {
+		return "used a skill"
+	}
+	return fmt.Sprintf("used the %s skill", name)
+}
+
+// recapTranscript renders messages as transcript lines for the recap
+// prompt. Tool results become one-line actions and empty messages (such as
+// assistant turns that only call tools) are dropped.
+func recapTranscript(messages []config.SessionMessage) []string {
+	lines := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		content := strings.TrimSpace(msg.Content)
+		switch msg.Role {
+		case "tool":
+			lines = append(lines, fmt.Sprintf("[Celeste %s]", toolAction(msg.Name)))
+		case "user", "assistant":
+			if content == "" {
+				continue
+			}
+			speaker := "User"
+			if msg.Role == "assistant" {
+				speaker = "Celeste"
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s", speaker, content))
+		}
+	}
+	return lines
+}
+
+// chunkTranscript groups lines into chunks of at most maxTokens estimated
+// tokens. A line longer than maxTokens is cut to fit.
+func chunkTranscript(lines []string, maxTokens int) [][]string {
+	var chunks [][]string
+	var current []string
+	tokens := 0
+	for _, line := range lines {
+		lineTokens := config.EstimateTokens(line) + 1
+		if lineTokens > maxTokens {
+			line = strings.ToValidUTF8(line[:maxTokens*4-4], "") + "…"
+			lineTokens = maxTokens
+		}
+		if tokens+lineTokens > maxTokens && len(current) > 0 {
+			chunks = append(chunks, current)
+			current, tokens = nil, 0
+		}
+		current = append(current, line)
+		tokens += lineTokens
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// CompleteFunc sends a single prompt and returns the reply.
+type CompleteFunc func(ctx context.Context, prompt string) (string, error)
+
+// Recap summarizes a conversation in the given style, in the voice of the
+// client's persona. Conversations too long for one request are first
+// condensed chunk by chunk into notes, which are then recapped.
+func (s *Summarizer) Recap(ctx context.Context, messages []config.SessionMessage, style string) (string, error) {
+	var model string
+	if s.client != nil {
+		model = s.client.GetConfig().Model
+	}
+	return Recap(ctx, messages, style, model, s.complete)
+}
+
+// Recap summarizes a conversation in the given style, sending each request
+// through complete. Chunks are sized to model's context window.
+func Recap(ctx context.Context, messages []config.SessionMessage, style, model string, complete CompleteFunc) (string, error) {
+	stylePrompt, ok := recapStylePrompts[style]
+	if !ok {
+		return "", fmt.Errorf("unknown recap style %q (use %s)", style, strings.Join(RecapStyles, ", "))
+	}
+
+	lines := recapTranscript(messages)
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no messages to summarize")
+	}
+
+	// Leave half the context window for the persona prompt and the reply
+	budget := config.GetModelLimit(model) / 2
+	chunks := chunkTranscript(lines, budget)
+	for round := 0; len(chunks) > 1 && round < maxRecapRounds; round++ {
+		var notes []string
+		for i, chunk := range chunks {
+			note, err := complete(ctx, fmt.Sprintf(
+				"Here is part %d of %d of a conversation between the user and you. Write brief factual notes on what was discussed, decided and done in it.\n\n%s",
+				i+1, len(chunks), strings.Join(chunk, "\n")))
+			if err != nil {
+				return "", err
+			}
+			notes = append(notes, note)
+		}
+		chunks = chunkTranscript(notes, budget)
+	}
+
+	return complete(ctx, fmt.Sprintf(
+		"Recap the following conversation between the user and you, in your own voice. Cover what was talked about, what was decided and what you did. %s\n\n%s",
+		stylePrompt, strings.Join(chunks[0], "\n")))
+}
+
+// complete sends a single prompt and returns the reply.
+func (s *Summarizer) complete(ctx context.Context, prompt string) (string, error) {
+	result, err := s.client.SendMessageSync(ctx, []chat.Message{
+		{Role: "user", Content: prompt, Timestamp: time.Now()},
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("summarization failed: %w", err)
+	}
+	if result.Error != nil {
+		return "", fmt.Errorf("summarization error: %w", result.Error)
+	}
+	if strings.TrimSpace(result.Content) == "" {
+		return "", fmt.Errorf("empty summary returned")
+	}
+	return strings.TrimSpace(result.Content), nil
+}
+
+// SplitThread splits text into posts of at most limit characters, each
+// starting with its position ("1/3"). Paragraphs start new posts; paragraphs
+// too long for one post are split between words.
+func SplitThread(text string, limit int) []string {
+	// Reserve room for the widest "n/N " prefix
+	reserve := len("999/999 ")
+
+	var posts []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		var current string
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > limit-reserve {
+				// A single word longer than a post (a long URL); hard-wrap it
+				runes := []rune(word)
+				if current != "" {
+					posts = append(posts, current)
+					current = ""
+				}
+				posts = append(posts, string(runes[:limit-reserve]))
+				word = string(runes[limit-reserve:])
+			}
+			switch {
+			case current == "":
+				current = word
+			case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= limit-reserve:
+				current += " " + word
+			default:
+				posts = append(posts, current)
+				current = word
+			}
+		}
+		if current != "" {
+			posts = append(posts, current)
+		}
+	}
+
+	for i := range posts {
+		posts[i] = fmt.Sprintf("%d/%d %s", i+1, len(posts), posts[i])
+	}
+	return posts
+}
@@ -0,0 +1,116 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+)
+
+// TestRecapTranscriptDescribesToolCalls tests that tool results become
+// actions and tool-call-only assistant turns are dropped
+func TestRecapTranscriptDescribesToolCalls(t *testing.T) {
+	lines := recapTranscript([]config.SessionMessage{
+		{Role: "user", Content: "Is it raining in NYC?"},
+		{Role: "assistant", Content: ""},
+		{Role: "tool", Name: "get_weather", Content: `{"temperature":12,"conditions":"rain"}`},
+		{Role: "tool", Name: "my_custom_skill", Content: `{}`},
+		{Role: "assistant", Content: "Yes, bring an umbrella."},
+		{Role: "system", Content: "📂 Resumed session"},
+	})
+
+	assert.Equal(t, []string{
+		"User: Is it raining in NYC?",
+		"[Celeste checked the weather]",
+		"[Celeste used the my_custom_skill skill]",
+		"Celeste: Yes, bring an umbrella.",
+	}, lines)
+}
+
+// TestChunkTranscript tests that chunks stay within the token budget and
+// that an oversized line is cut rather than dropped
+func TestChunkTranscript(t *testing.T) {
+	line := strings.Repeat("a", 40) // 10 tokens, plus 1 for the newline
+	chunks := chunkTranscript([]string{line, line, line, line, line}, 25)
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], 2)
+	assert.Len(t, chunks[2], 1)
+
+	chunks = chunkTranscript([]string{strings.Repeat("b", 400)}, 25)
+	require.Len(t, chunks, 1)
+	assert.LessOrEqual(t, config.EstimateTokens(chunks[0][0]), 25)
+	assert.True(t, strings.HasSuffix(chunks[0][0], "…"))
+}
+
+// TestSplitThread tests numbering, the length limit, paragraph breaks and
+// hard-wrapping of words longer than a post
+func TestSplitThread(t *testing.T) {
+	long := strings.TrimSpace(strings.Repeat("stream recap words ", 40))
+	posts := SplitThread("Short opener.\n\n"+long+"\n\nhttps://example.com/"+strings.Repeat("x", 300), TweetLimit)
+
+	require.Greater(t, len(posts), 3)
+	assert.Equal(t, fmt.Sprintf("1/%d Short opener.", len(posts)), posts[0])
+	for i, post := range posts {
+		assert.LessOrEqual(t, utf8.RuneCountInString(post), TweetLimit, "post %d", i+1)
+		assert.True(t, strings.HasPrefix(post, fmt.Sprintf("%d/%d ", i+1, len(posts))), post)
+	}
+	assert.Contains(t, posts[len(posts)-1], "xxx")
+
+	assert.Equal(t, []string{"1/1 hello"}, SplitThread("hello", TweetLimit))
+	assert.Empty(t, SplitThread("  \n\n ", TweetLimit))
+}
+
+// TestRecapChunksLongSessions tests that a session over the context budget
+// is condensed into notes first, with the style applied to the final pass
+func TestRecapChunksLongSessions(t *testing.T) {
+	var requests int32
+	var lastPrompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		lastPrompt = body.Messages[len(body.Messages)-1].Content
+		n := atomic.AddInt32(&requests, 1)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, `data: {"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"summary %d"},"finish_reason":"stop"}]}`+"\n\n", n)
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "unknown-model", SkipPersonaPrompt: true}, nil)
+	defer client.Close()
+
+	// The default 8192-token limit gives a 4096-token budget; this is ~3x that
+	var messages []config.SessionMessage
+	for i := 0; i < 30; i++ {
+		messages = append(messages, config.SessionMessage{Role: "user", Content: strings.Repeat("word ", 300)})
+	}
+
+	summary, err := NewSummarizer(client).Recap(context.Background(), messages, "narrative")
+	require.NoError(t, err)
+
+	assert.Greater(t, int(requests), 2, "expected one request per chunk plus the final recap")
+	assert.Equal(t, fmt.Sprintf("summary %d", requests), summary)
+	assert.Contains(t, lastPrompt, recapStylePrompts["narrative"])
+	assert.Contains(t, lastPrompt, "summary 1")
+}
+
+// TestRecapRejectsUnknownStyle tests style validation
+func TestRecapRejectsUnknownStyle(t *testing.T) {
+	_, err := NewSummarizer(nil).Recap(context.Background(), []config.SessionMessage{{Role: "user", Content: "hi"}}, "haiku")
+	assert.ErrorContains(t, err, "unknown recap style")
+}
@@ -0,0 +1,97 @@
+// Package llm provides the LLM client for Celeste CLI.
+// This file reassembles server-sent events before the SDK reads them.
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// sseReader re-frames a server-sent event stream so each event reaches the
+// SDK as a single "data:" line, which is all the SDK understands. Events
+// whose data is split over several "data:" lines are joined, CRLF line
+// endings and comment keep-alives are dropped, and lines of any length are
+// read. Lines that are not SSE fields, such as an error body, pass through.
+type sseReader struct {
+	src     *bufio.Reader
+	body    io.Closer
+	data    bytes.Buffer // Data of the event being read
+	hasData bool
+	out     bytes.Buffer // Re-framed events not yet read
+	err     error
+}
+
+func newSSEReader(body io.ReadCloser) *sseReader {
+	return &sseReader{src: bufio.NewReaderSize(body, 64*1024), body: body}
+}
+
+// Read implements io.Reader.
+func (r *sseReader) Read(p []byte) (int, error) {
+	for r.out.Len() == 0 && r.err == nil {
+		r.err = r.readLine()
+	}
+	if r.out.Len() > 0 {
+		return r.out.Read(p)
+	}
+	return 0, r.err
+}
+
+// Close implements io.Closer.
+func (r *sseReader) Close() error {
+	return r.body.Close()
+}
+
+// readLine reads one line of the stream, however many reads it arrives in.
+func (r *sseReader) readLine() error {
+	line, err := r.src.ReadBytes('\n')
+	if len(line) > 0 {
+		r.processLine(bytes.TrimRight(line, "\r\n"))
+	}
+	if err != nil {
+		// A stream that ends without a blank line still ends its last event
+		r.dispatch()
+	}
+	return err
+}
+
+func (r *sseReader) processLine(line []byte) {
+	if len(line) == 0 {
+		r.dispatch()
+		return
+	}
+	if line[0] == ':' {
+		return // Comment, usually a keep-alive
+	}
+
+	field, value, found := bytes.Cut(line, []byte(":"))
+	if found && len(value) > 0 && value[0] == ' ' {
+		value = value[1:]
+	}
+	switch string(field) {
+	case "data":
+		if r.hasData {
+			// The spec joins data lines with a newline; in JSON that is
+			// whitespace, and a space keeps the event on one line
+			r.data.WriteByte(' ')
+		}
+		r.data.Write(value)
+		r.hasData = true
+	case "event", "id", "retry":
+	default:
+		r.out.Write(line)
+		r.out.WriteByte('\n')
+	}
+}
+
+// dispatch writes the event read so far as one data line.
+func (r *sseReader) dispatch() {
+	if !r.hasData {
+		return
+	}
+	r.out.WriteString("data: ")
+	r.out.Write(r.data.Bytes())
+	r.out.WriteString("\n\n")
+	r.data.Reset()
+	r.hasData = false
+}
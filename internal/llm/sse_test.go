@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+)
+
+// fragmentedStream is a large event stream the way an unfriendly server
+// might send it: a chunk bigger than bufio.Scanner's 64KB limit whose JSON
+// spans several data lines, CRLF endings, keep-alives and event names.
+func fragmentedStream(t *testing.T, content string) string {
+	t.Helper()
+	delta, err := json.Marshal(content)
+	require.NoError(t, err)
+	return ": keep-alive\r\n\r\n" +
+		"event: message\r\n" +
+		`data: {"id":"x","object":"chat.completion.chunk",` + "\r\n" +
+		`data:  "choices":[{"index":0,"delta":{"content":` + string(delta) + `}}]}` + "\r\n\r\n" +
+		"id: 2\n" +
+		`data:{"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"!"},"finish_reason":"stop"}]}` + "\n\n" +
+		"data: [DONE]\n"
+}
+
+// TestSSEReaderReassemblesEvents tests re-framing a fragmented stream read
+// a few bytes at a time
+func TestSSEReaderReassemblesEvents(t *testing.T) {
+	content := strings.Repeat("0123456789abcdef", 12*1024)
+	r := newSSEReader(io.NopCloser(iotest.HalfReader(strings.NewReader(fragmentedStream(t, content)))))
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	events := strings.Split(strings.TrimSuffix(string(out), "\n\n"), "\n\n")
+	require.Len(t, events, 3)
+	assert.Equal(t, `data: {"id":"x","object":"chat.completion.chunk",  "choices":[{"index":0,"delta":{"content":"`+content+`"}}]}`, events[0])
+	assert.True(t, strings.HasPrefix(events[1], `data: {"id":"x"`))
+	assert.Equal(t, "data: [DONE]", events[2])
+}
+
+// TestSSEReaderPassesErrorBody tests that lines which are not SSE fields
+// reach the SDK unchanged
+func TestSSEReaderPassesErrorBody(t *testing.T) {
+	body := "{\"error\": {\"message\": \"rate limited\"}}\n"
+	out, err := io.ReadAll(newSSEReader(io.NopCloser(strings.NewReader(body))))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(out))
+}
+
+// TestStreamLargeFragmentedChunk tests that a large chunk trickled out in
+// small writes streams back complete
+func TestStreamLargeFragmentedChunk(t *testing.T) {
+	content := strings.Repeat("Celeste streams long replies. ", 8*1024)
+	stream := fragmentedStream(t, content)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for len(stream) > 0 {
+			n := min(4093, len(stream))
+			io.WriteString(w, stream[:n])
+			w.(http.Flusher).Flush()
+			stream = stream[n:]
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true}, nil)
+	defer client.Close()
+
+	var got strings.Builder
+	err := client.SendMessageStream(context.Background(), []chat.Message{{Role: "user", Content: "hi"}}, nil, func(chunk StreamChunk) {
+		got.WriteString(chunk.Content)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, len(content)+1, got.Len())
+	assert.Equal(t, content+"!", got.String())
+}
@@ -7,8 +7,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/config"
-	"github.com/whykusanagi/celesteCLI/cmd/celeste/tui"
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+	"github.com/whykusanagi/celesteCLI/internal/config"
 )
 
 // Summarizer handles automatic conversation summarization for context management.
@@ -58,7 +58,7 @@ The summary should be 150-250 words and written in a clear, factual style.`
 	ctx := context.Background()
 
 	// Build messages for summarization request
-	summaryMessages := []tui.ChatMessage{
+	summaryMessages := []chat.Message{
 		{Role: "system", Content: systemPrompt, Timestamp: time.Now()},
 		{Role: "user", Content: userPrompt, Timestamp: time.Now()},
 	}
@@ -0,0 +1,85 @@
+// Package llm provides the LLM client for Celeste CLI.
+// This file keeps tool calls and tool results paired up before they are sent.
+package llm
+
+import (
+	"fmt"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+)
+
+// ensureToolCallIDs gives every tool call a unique ID. Some providers omit
+// IDs (or Google, which has none), and a missing or repeated ID makes it
+// impossible to send each result back against the right call.
+func ensureToolCallIDs(calls []ToolCallResult) []ToolCallResult {
+	seen := make(map[string]bool, len(calls))
+	for i := range calls {
+		if calls[i].ID == "" || seen[calls[i].ID] {
+			calls[i].ID = fmt.Sprintf("call_%d_%s", i, calls[i].Name)
+		}
+		seen[calls[i].ID] = true
+	}
+	return calls
+}
+
+// pairToolMessages makes the tool-call sequence in a conversation valid for
+// the API: every assistant message with tool_calls is followed by exactly one
+// role "tool" message per call, in call order, each carrying the matching
+// tool_call_id. Tool messages that don't answer the preceding assistant
+// message (e.g. from a restored session) are dropped, as are calls that never
+// got a result. Other messages between an assistant message and its results
+// are kept, after the results.
+func pairToolMessages(messages []chat.Message) []chat.Message {
+	result := make([]chat.Message, 0, len(messages))
+
+	for i := 0; i < len(messages); i++ {
+		msg := messages[i]
+
+		if msg.Role == "tool" {
+			// Not claimed by a preceding assistant message
+			continue
+		}
+		if msg.Role != "assistant" || len(msg.ToolCalls) == 0 {
+			result = append(result, msg)
+			continue
+		}
+
+		// Gather the results that follow, up to the next user/assistant turn
+		results := make(map[string]chat.Message)
+		var others []chat.Message
+		j := i + 1
+		for ; j < len(messages) && messages[j].Role != "user" && messages[j].Role != "assistant"; j++ {
+			if messages[j].Role != "tool" {
+				others = append(others, messages[j])
+				continue
+			}
+			if _, dup := results[messages[j].ToolCallID]; !dup {
+				results[messages[j].ToolCallID] = messages[j]
+			}
+		}
+
+		var calls []chat.ToolCall
+		var answers []chat.Message
+		for _, call := range msg.ToolCalls {
+			answer, ok := results[call.ID]
+			if !ok || call.ID == "" {
+				continue
+			}
+			if answer.Name == "" {
+				answer.Name = call.Name
+			}
+			calls = append(calls, call)
+			answers = append(answers, answer)
+		}
+
+		msg.ToolCalls = calls
+		if len(calls) > 0 || msg.Content != "" {
+			result = append(result, msg)
+		}
+		result = append(result, answers...)
+		result = append(result, others...)
+		i = j - 1
+	}
+
+	return result
+}
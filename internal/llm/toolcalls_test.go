@@ -0,0 +1,116 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+)
+
+// newRecordingServer answers every chat completion with a short streamed
+// reply and records the raw JSON messages of the last request.
+func newRecordingServer(t *testing.T, lastMessages *json.RawMessage) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages json.RawMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		*lastMessages = body.Messages
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"x","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"ok"},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+// TestFollowUpSendsEveryToolResult is a regression test for parallel tool
+// calls: the follow-up request must carry the assistant tool_calls message
+// followed by one tool message per call, each with its own tool_call_id.
+func TestFollowUpSendsEveryToolResult(t *testing.T) {
+	var sent json.RawMessage
+	server := newRecordingServer(t, &sent)
+	defer server.Close()
+
+	client := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "gpt-4o-mini", SkipPersonaPrompt: true}, nil)
+	defer client.Close()
+
+	messages := []chat.Message{
+		// Orphaned result from a restored session, with no assistant call before it
+		{Role: "tool", ToolCallID: "call_old", Name: "get_weather", Content: "stale"},
+		{Role: "user", Content: "Weather in NYC and LA?"},
+		{Role: "assistant", Content: "Checking...", ToolCalls: []chat.ToolCall{
+			{ID: "call_nyc", Name: "get_weather", Arguments: `{"zip_code":"10001"}`},
+			{ID: "call_la", Name: "get_weather", Arguments: `{"zip_code":"90001"}`},
+		}},
+		// Results arrive out of order
+		{Role: "tool", ToolCallID: "call_la", Name: "get_weather", Content: `{"temp":75}`},
+		{Role: "tool", ToolCallID: "call_nyc", Name: "get_weather", Content: `{"temp":60}`},
+	}
+
+	err := client.SendMessageStream(context.Background(), messages, nil, func(StreamChunk) {})
+	require.NoError(t, err)
+
+	// Results go out wrapped as untrusted data
+	wrapped := func(content string) string {
+		data, err := json.Marshal(sanitizeToolResult("get_weather", content, client.GetConfig()))
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.JSONEq(t, `[
+		{"role":"user","content":"Weather in NYC and LA?"},
+		{"role":"assistant","content":"Checking...","tool_calls":[
+			{"id":"call_nyc","type":"function","function":{"name":"get_weather","arguments":"{\"zip_code\":\"10001\"}"}},
+			{"id":"call_la","type":"function","function":{"name":"get_weather","arguments":"{\"zip_code\":\"90001\"}"}}
+		]},
+		{"role":"tool","content":`+wrapped(`{"temp":60}`)+`,"tool_call_id":"call_nyc"},
+		{"role":"tool","content":`+wrapped(`{"temp":75}`)+`,"tool_call_id":"call_la"}
+	]`, string(sent))
+}
+
+// TestPairToolMessagesDropsUnansweredCalls tests that calls without a result
+// are removed from the assistant message
+func TestPairToolMessagesDropsUnansweredCalls(t *testing.T) {
+	messages := pairToolMessages([]chat.Message{
+		{Role: "assistant", ToolCalls: []chat.ToolCall{{ID: "a", Name: "x"}, {ID: "b", Name: "y"}}},
+		{Role: "system", Content: "note"},
+		{Role: "tool", ToolCallID: "b", Content: "done"},
+		{Role: "tool", ToolCallID: "b", Content: "duplicate"},
+		{Role: "user", Content: "next"},
+	})
+
+	require.Len(t, messages, 4)
+	assert.Equal(t, []chat.ToolCall{{ID: "b", Name: "y"}}, messages[0].ToolCalls)
+	assert.Equal(t, "tool", messages[1].Role)
+	assert.Equal(t, "done", messages[1].Content)
+	assert.Equal(t, "y", messages[1].Name)
+	assert.Equal(t, "system", messages[2].Role)
+	assert.Equal(t, "user", messages[3].Role)
+
+	// An assistant message left with no calls and no content is dropped
+	messages = pairToolMessages([]chat.Message{
+		{Role: "assistant", ToolCalls: []chat.ToolCall{{ID: "a", Name: "x"}}},
+		{Role: "user", Content: "next"},
+	})
+	require.Len(t, messages, 1)
+	assert.Equal(t, "user", messages[0].Role)
+}
+
+// TestEnsureToolCallIDs tests that missing and repeated IDs are made unique
+func TestEnsureToolCallIDs(t *testing.T) {
+	calls := ensureToolCallIDs([]ToolCallResult{
+		{ID: "call_get_weather", Name: "get_weather"},
+		{ID: "call_get_weather", Name: "get_weather"},
+		{Name: "get_time"},
+	})
+	assert.Equal(t, "call_get_weather", calls[0].ID)
+	assert.NotEqual(t, calls[0].ID, calls[1].ID)
+	assert.NotEmpty(t, calls[2].ID)
+}
@@ -0,0 +1,141 @@
+// Package notify tells the user a long-running generation has finished, by
+// ringing the terminal bell and sending a desktop notification where the
+// platform has one (osascript on macOS, notify-send on Linux, a PowerShell
+// toast on Windows). Notifying is best-effort and never reports an error.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultThreshold is how long an operation must take before it is
+// announced, unless Notifier.Threshold says otherwise.
+const DefaultThreshold = 10 * time.Second
+
+// Title is the heading of every desktop notification.
+const Title = "Celeste"
+
+// commandTimeout bounds how long a desktop notification command may run.
+const commandTimeout = 5 * time.Second
+
+// Runner runs a desktop notification command.
+type Runner func(ctx context.Context, name string, args ...string) error
+
+// Notifier announces finished operations.
+type Notifier struct {
+	Threshold time.Duration    // Shorter operations are not announced; zero uses DefaultThreshold
+	Bell      io.Writer        // Terminal the bell is rung on; nil for no bell
+	GOOS      string           // Platform to notify on; runtime.GOOS when empty
+	Run       Runner           // Runs the notification command; exec when nil
+	Log       func(msg string) // Debug log for failed notifications; nil drops them
+}
+
+// Done announces an operation that took elapsed, e.g. Done("image saved as
+// x.png", 42*time.Second) shows "image saved as x.png, 42s". It reports
+// whether a notification was sent, which is false under the threshold.
+func (n *Notifier) Done(summary string, elapsed time.Duration) bool {
+	threshold := n.Threshold
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	if elapsed < threshold {
+		return false
+	}
+	n.Send(Summary(summary, elapsed))
+	return true
+}
+
+// Send rings the bell and sends body as a desktop notification.
+func (n *Notifier) Send(body string) {
+	if n.Bell != nil {
+		if _, err := io.WriteString(n.Bell, "\a"); err != nil {
+			n.log(fmt.Sprintf("Notification bell failed: %v", err))
+		}
+	}
+
+	goos := n.GOOS
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	name, args, ok := Command(goos, Title, body)
+	if !ok {
+		return
+	}
+	run := n.Run
+	if run == nil {
+		run = execRun
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	if err := run(ctx, name, args...); err != nil {
+		n.log(fmt.Sprintf("Desktop notification (%s) failed: %v", name, err))
+	}
+}
+
+func (n *Notifier) log(msg string) {
+	if n.Log != nil {
+		n.Log(msg)
+	}
+}
+
+// Summary appends the rounded elapsed time to summary, e.g. "reply
+// finished, 1m12s".
+func Summary(summary string, elapsed time.Duration) string {
+	return fmt.Sprintf("%s, %s", summary, elapsed.Round(time.Second))
+}
+
+// Command returns the desktop notification command for goos. ok is false on
+// platforms without one.
+func Command(goos, title, body string) (name string, args []string, ok bool) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}, true
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=" + title, "--", title, body}, true
+	case "windows":
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", toastScript(title, body)}, true
+	}
+	return "", nil, false
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// powerShellString quotes s as a PowerShell single-quoted string, in which
+// only the quote itself needs escaping.
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// toastAppID is PowerShell's own app ID; toasts from unregistered IDs are
+// dropped silently.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// toastScript builds a PowerShell script that shows a two-line toast.
+func toastScript(title, body string) string {
+	lines := []string{
+		"[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null",
+		"$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)",
+		"$text = $xml.GetElementsByTagName('text')",
+		"$text.Item(0).AppendChild($xml.CreateTextNode(" + powerShellString(title) + ")) > $null",
+		"$text.Item(1).AppendChild($xml.CreateTextNode(" + powerShellString(body) + ")) > $null",
+		"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(" + powerShellString(toastAppID) + ").Show([Windows.UI.Notifications.ToastNotification]::new($xml))",
+	}
+	return strings.Join(lines, "; ")
+}
+
+// execRun runs the command, failing if it isn't installed.
+func execRun(ctx context.Context, name string, args ...string) error {
+	return exec.CommandContext(ctx, name, args...).Run()
+}
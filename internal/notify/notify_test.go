@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder stubs the platform command and keeps what it was asked to run.
+type recorder struct {
+	calls [][]string
+	err   error
+}
+
+func (r *recorder) run(ctx context.Context, name string, args ...string) error {
+	r.calls = append(r.calls, append([]string{name}, args...))
+	return r.err
+}
+
+func TestCommand(t *testing.T) {
+	name, args, ok := Command("darwin", "Celeste", `reply "done", 42s`)
+	require.True(t, ok)
+	assert.Equal(t, "osascript", name)
+	assert.Equal(t, []string{"-e", `display notification "reply \"done\", 42s" with title "Celeste"`}, args)
+
+	name, args, ok = Command("linux", "Celeste", "-image saved as a.png, 42s")
+	require.True(t, ok)
+	assert.Equal(t, "notify-send", name)
+	assert.Equal(t, []string{"--app-name=Celeste", "--", "Celeste", "-image saved as a.png, 42s"}, args)
+
+	name, args, ok = Command("windows", "Celeste", "it's done, 42s")
+	require.True(t, ok)
+	assert.Equal(t, "powershell", name)
+	require.Len(t, args, 4)
+	assert.Equal(t, []string{"-NoProfile", "-NonInteractive", "-Command"}, args[:3])
+	assert.Contains(t, args[3], "CreateTextNode('Celeste')")
+	assert.Contains(t, args[3], "CreateTextNode('it''s done, 42s')")
+
+	_, _, ok = Command("plan9", "Celeste", "done")
+	assert.False(t, ok)
+}
+
+func TestDoneThreshold(t *testing.T) {
+	var bell bytes.Buffer
+	rec := &recorder{}
+	n := &Notifier{Bell: &bell, GOOS: "linux", Run: rec.run}
+
+	assert.False(t, n.Done("reply finished", 3*time.Second))
+	assert.Empty(t, bell.String())
+	assert.Empty(t, rec.calls)
+
+	assert.True(t, n.Done("image saved as nsfw_image_1.png", 42*time.Second+300*time.Millisecond))
+	assert.Equal(t, "\a", bell.String())
+	require.Len(t, rec.calls, 1)
+	assert.Equal(t, []string{"notify-send", "--app-name=Celeste", "--", "Celeste", "image saved as nsfw_image_1.png, 42s"}, rec.calls[0])
+
+	n.Threshold = time.Second
+	assert.True(t, n.Done("reply finished", 2*time.Second))
+}
+
+func TestSendFailureIsLogged(t *testing.T) {
+	var logged []string
+	rec := &recorder{err: errors.New(`exec: "notify-send": executable file not found in $PATH`)}
+	n := &Notifier{GOOS: "linux", Run: rec.run, Log: func(msg string) { logged = append(logged, msg) }}
+
+	n.Send("reply finished, 12s")
+	require.Len(t, logged, 1)
+	assert.Contains(t, logged[0], "notify-send")
+
+	// Without a platform command only the bell rings
+	var bell bytes.Buffer
+	n = &Notifier{Bell: &bell, GOOS: "plan9", Run: rec.run}
+	n.Send("reply finished, 12s")
+	assert.Equal(t, "\a", bell.String())
+	assert.Len(t, rec.calls, 1)
+}
@@ -0,0 +1,115 @@
+// Package paths resolves where Celeste keeps its files.
+//
+// Configuration (config files, secrets, skills.json, custom skills, personas
+// and presets) lives in ConfigDir; everything Celeste writes as it runs
+// (sessions, caches, logs, notes, reminders, exports, usage) lives in
+// DataDir. Both are chosen in this order:
+//
+//  1. $CELESTE_HOME, for both, when it is set
+//  2. ~/.celeste, for both, when it already exists, so existing setups keep
+//     working
+//  3. $XDG_CONFIG_HOME/celeste and $XDG_DATA_HOME/celeste, each when set
+//  4. ~/.celeste
+//
+// The directories are resolved on every call, so they follow the
+// environment, and are not created here.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvHome is the environment variable that overrides both directories.
+const EnvHome = "CELESTE_HOME"
+
+// Scheme names how the directories were chosen, for `celeste config --show`.
+type Scheme string
+
+// Schemes, in the order they are tried.
+const (
+	SchemeEnv    Scheme = "CELESTE_HOME"
+	SchemeLegacy Scheme = "~/.celeste"
+	SchemeXDG    Scheme = "XDG base directories"
+)
+
+// Home returns the legacy directory, ~/.celeste. Without a home directory
+// it is ".celeste", relative to the working directory.
+func Home() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".celeste"
+	}
+	return filepath.Join(home, ".celeste")
+}
+
+// ConfigDir returns the directory for configuration.
+func ConfigDir() string {
+	config, _, _ := resolve()
+	return config
+}
+
+// DataDir returns the directory for data Celeste writes as it runs.
+func DataDir() string {
+	_, data, _ := resolve()
+	return data
+}
+
+// Config joins elem onto ConfigDir.
+func Config(elem ...string) string {
+	return filepath.Join(append([]string{ConfigDir()}, elem...)...)
+}
+
+// Data joins elem onto DataDir.
+func Data(elem ...string) string {
+	return filepath.Join(append([]string{DataDir()}, elem...)...)
+}
+
+// Resolved returns both directories and the scheme that chose them.
+func Resolved() (configDir, dataDir string, scheme Scheme) {
+	return resolve()
+}
+
+func resolve() (configDir, dataDir string, scheme Scheme) {
+	if dir := os.Getenv(EnvHome); dir != "" {
+		dir = ExpandHome(dir)
+		return dir, dir, SchemeEnv
+	}
+
+	legacy := Home()
+	if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+		return legacy, legacy, SchemeLegacy
+	}
+
+	configDir, dataDir, scheme = legacy, legacy, SchemeLegacy
+	if dir := xdgDir("XDG_CONFIG_HOME"); dir != "" {
+		configDir, scheme = filepath.Join(dir, "celeste"), SchemeXDG
+	}
+	if dir := xdgDir("XDG_DATA_HOME"); dir != "" {
+		dataDir, scheme = filepath.Join(dir, "celeste"), SchemeXDG
+	}
+	return configDir, dataDir, scheme
+}
+
+// xdgDir returns an XDG base directory variable. Relative paths are
+// invalid under the spec and ignored.
+func xdgDir(name string) string {
+	dir := os.Getenv(name)
+	if !filepath.IsAbs(dir) {
+		return ""
+	}
+	return dir
+}
+
+// ExpandHome replaces a leading "~/" with the user's home directory.
+func ExpandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
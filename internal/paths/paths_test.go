@@ -0,0 +1,86 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withEnv points HOME at a temp directory and sets the location variables,
+// returning the home directory.
+func withEnv(t *testing.T, celesteHome, xdgConfig, xdgData string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv(EnvHome, celesteHome)
+	t.Setenv("XDG_CONFIG_HOME", xdgConfig)
+	t.Setenv("XDG_DATA_HOME", xdgData)
+	return home
+}
+
+func TestResolved(t *testing.T) {
+	xdg := t.TempDir()
+	xdgConfig := filepath.Join(xdg, "config")
+	xdgData := filepath.Join(xdg, "data")
+	custom := filepath.Join(xdg, "custom")
+
+	tests := []struct {
+		name                            string
+		celesteHome, xdgConfig, xdgData string
+		legacyExists                    bool
+		wantConfig, wantData            string // "~" is the temp home
+		wantScheme                      Scheme
+	}{
+		{"nothing set", "", "", "", false, "~/.celeste", "~/.celeste", SchemeLegacy},
+		{"xdg", "", xdgConfig, xdgData, false, filepath.Join(xdgConfig, "celeste"), filepath.Join(xdgData, "celeste"), SchemeXDG},
+		{"xdg config only", "", xdgConfig, "", false, filepath.Join(xdgConfig, "celeste"), "~/.celeste", SchemeXDG},
+		{"xdg data only", "", "", xdgData, false, "~/.celeste", filepath.Join(xdgData, "celeste"), SchemeXDG},
+		{"relative xdg is ignored", "", "config", "data", false, "~/.celeste", "~/.celeste", SchemeLegacy},
+		{"legacy directory wins over xdg", "", xdgConfig, xdgData, true, "~/.celeste", "~/.celeste", SchemeLegacy},
+		{"CELESTE_HOME wins over everything", custom, xdgConfig, xdgData, true, custom, custom, SchemeEnv},
+		{"CELESTE_HOME with a tilde", "~/celeste-data", "", "", false, "~/celeste-data", "~/celeste-data", SchemeEnv},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := withEnv(t, tt.celesteHome, tt.xdgConfig, tt.xdgData)
+			if tt.legacyExists {
+				require.NoError(t, os.Mkdir(filepath.Join(home, ".celeste"), 0755))
+			}
+			expand := func(p string) string {
+				if len(p) > 0 && p[0] == '~' {
+					return filepath.Join(home, p[1:])
+				}
+				return p
+			}
+
+			configDir, dataDir, scheme := Resolved()
+			assert.Equal(t, expand(tt.wantConfig), configDir)
+			assert.Equal(t, expand(tt.wantData), dataDir)
+			assert.Equal(t, tt.wantScheme, scheme)
+			assert.Equal(t, configDir, ConfigDir())
+			assert.Equal(t, dataDir, DataDir())
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	xdg := t.TempDir()
+	withEnv(t, "", filepath.Join(xdg, "config"), filepath.Join(xdg, "data"))
+
+	assert.Equal(t, filepath.Join(xdg, "config", "celeste", "skills.json"), Config("skills.json"))
+	assert.Equal(t, filepath.Join(xdg, "data", "celeste", "cache", "http"), Data("cache", "http"))
+	assert.Equal(t, filepath.Join(xdg, "data", "celeste"), Data())
+}
+
+func TestExpandHome(t *testing.T) {
+	home := withEnv(t, "", "", "")
+
+	assert.Equal(t, filepath.Join(home, "Pictures", "cat.png"), ExpandHome("~/Pictures/cat.png"))
+	assert.Equal(t, "/tmp/cat.png", ExpandHome("/tmp/cat.png"))
+	assert.Equal(t, "~user/cat.png", ExpandHome("~user/cat.png"))
+	assert.Equal(t, "cat.png", ExpandHome("cat.png"))
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
 )
 
 // Embedded persona prompt for when no external file is available
@@ -32,6 +34,10 @@ type CelesteEssence struct {
 	OperationalLaws  map[string]string `json:"operational_laws"`
 	InteractionRules []string          `json:"interaction_rules"`
 	KnowledgeUsage   string            `json:"knowledge_usage"`
+
+	// Sampling optionally sets the temperature, top_p and max_tokens used
+	// while the persona is selected
+	Sampling *config.Sampling `json:"sampling,omitempty"`
 }
 
 // BehaviorTier defines behavior based on score.
@@ -48,17 +54,19 @@ type SafetyConfig struct {
 	SafeAlternatives string   `json:"safe_alternatives"`
 }
 
+// EssencePath returns the user's override for the built-in essence
+// (~/.celeste/celeste_essence.json). It need not exist.
+func EssencePath() string {
+	return paths.Config("celeste_essence.json")
+}
+
 // LoadEssence loads the Celeste essence from file or embedded.
 func LoadEssence() (*CelesteEssence, error) {
 	var data []byte
 
 	// Try to load from config directory first
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		configPath := filepath.Join(homeDir, ".celeste", "celeste_essence.json")
-		if fileData, err := os.ReadFile(configPath); err == nil {
-			data = fileData
-		}
+	if fileData, err := os.ReadFile(EssencePath()); err == nil {
+		data = fileData
 	}
 
 	// Fallback to embedded
@@ -178,10 +186,21 @@ NSFW MODE ACTIVE:
 	return basePrompt + nsfwAddendum
 }
 
+// ContentPlatforms and ContentFormats are the values GetContentPrompt has
+// specific guidance for.
+var (
+	ContentPlatforms = []string{"twitter", "tiktok", "youtube", "discord"}
+	ContentFormats   = []string{"short", "long", "general"}
+)
+
 // GetContentPrompt returns a prompt tailored for content generation.
 func GetContentPrompt(platform, format, tone, topic string) string {
-	basePrompt := GetSystemPrompt(false)
+	return GetSystemPrompt(false) + ContentGuidance(platform, format, tone, topic)
+}
 
+// ContentGuidance returns the content generation section appended to a
+// persona prompt for the given platform, format, tone and topic.
+func ContentGuidance(platform, format, tone, topic string) string {
 	var contentAddendum strings.Builder
 	contentAddendum.WriteString("\n\nCONTENT GENERATION MODE:\n")
 
@@ -217,5 +236,5 @@ func GetContentPrompt(platform, format, tone, topic string) string {
 		contentAddendum.WriteString(fmt.Sprintf("- Topic/Subject: %s\n", topic))
 	}
 
-	return basePrompt + contentAddendum.String()
+	return contentAddendum.String()
 }
@@ -0,0 +1,227 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// DefaultPersona is the built-in Celeste persona (celeste_essence.json).
+const DefaultPersona = "celeste"
+
+// PersonaExtensions are the persona file types, in the order used when one
+// name has several files.
+var PersonaExtensions = []string{".json", ".yaml", ".yml"}
+
+// PersonasDir returns the directory holding extra persona files
+// (~/.celeste/personas). Each <name>.json, <name>.yaml or <name>.yml file
+// uses the same fields as celeste_essence.json and adds a persona called
+// <name>.
+func PersonasDir() string {
+	return paths.Config("personas")
+}
+
+// isPersonaFile reports whether a file name has a persona extension.
+func isPersonaFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range PersonaExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// personaFile returns the file for a persona in PersonasDir, or "" if
+// there is none.
+func personaFile(name string) string {
+	for _, ext := range PersonaExtensions {
+		path := filepath.Join(PersonasDir(), name+ext)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// loadPersonaFile parses a persona file as YAML or JSON by its extension.
+func loadPersonaFile(path string) (*CelesteEssence, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format := "JSON"
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		// Decode generically and re-encode so YAML files share the JSON field names
+		format = "YAML"
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+	}
+
+	var essence CelesteEssence
+	if err := json.Unmarshal(data, &essence); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", format, err)
+	}
+	return &essence, nil
+}
+
+// ListPersonas returns the available persona names: the built-in default
+// first, then the persona files in PersonasDir sorted by name.
+func ListPersonas() []string {
+	names := []string{DefaultPersona}
+
+	entries, err := os.ReadDir(PersonasDir())
+	if err != nil {
+		return names
+	}
+	var extra []string
+	seen := map[string]bool{DefaultPersona: true}
+	for _, entry := range entries {
+		if entry.IsDir() || !isPersonaFile(entry.Name()) {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if !seen[name] {
+			seen[name] = true
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// IsPersona reports whether name is an available persona.
+func IsPersona(name string) bool {
+	for _, p := range ListPersonas() {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPersonaPrompt returns the system prompt for a persona. An empty name
+// means the default persona.
+func GetPersonaPrompt(name string) (string, error) {
+	if name == "" || name == DefaultPersona {
+		return GetSystemPrompt(false), nil
+	}
+	if !IsPersona(name) {
+		return "", fmt.Errorf("unknown persona %q (available: %s)", name, strings.Join(ListPersonas(), ", "))
+	}
+
+	essence, err := loadPersonaFile(personaFile(name))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse persona %q: %w", name, err)
+	}
+	return buildPromptFromEssence(essence), nil
+}
+
+// PersonaSampling returns the sampling defaults a persona declares in its
+// "sampling" section, or none when it has no such section. An empty name
+// means the default persona, whose sampling comes from celeste_essence.json.
+func PersonaSampling(name string) (config.Sampling, error) {
+	var essence *CelesteEssence
+	if name == "" || name == DefaultPersona {
+		// A broken essence falls back to the basic prompt, without sampling
+		var err error
+		if essence, err = LoadEssence(); err != nil {
+			return config.Sampling{}, nil
+		}
+	} else {
+		if !IsPersona(name) {
+			return config.Sampling{}, fmt.Errorf("unknown persona %q (available: %s)", name, strings.Join(ListPersonas(), ", "))
+		}
+		var err error
+		if essence, err = loadPersonaFile(personaFile(name)); err != nil {
+			return config.Sampling{}, fmt.Errorf("failed to parse persona %q: %w", name, err)
+		}
+	}
+	if essence.Sampling == nil {
+		return config.Sampling{}, nil
+	}
+	return *essence.Sampling, nil
+}
+
+// PersonaPath returns the file a persona is loaded from, or "" when the
+// default persona uses the embedded essence.
+func PersonaPath(name string) string {
+	if name == "" || name == DefaultPersona {
+		if _, err := os.Stat(EssencePath()); err != nil {
+			return ""
+		}
+		return EssencePath()
+	}
+	if path := personaFile(name); path != "" {
+		return path
+	}
+	return filepath.Join(PersonasDir(), name+".json")
+}
+
+// ValidatePersona checks a persona's file and reports every problem in one
+// error naming the file. A file that won't parse makes the default persona
+// fall back to a basic prompt, and one missing fields produces a bland
+// prompt, so both are worth warning about even though the persona still
+// loads.
+func ValidatePersona(name string) error {
+	path := PersonaPath(name)
+	if path == "" {
+		return nil
+	}
+
+	essence, err := loadPersonaFile(path)
+	if err != nil {
+		return fmt.Errorf("persona file %s: %w", path, err)
+	}
+	if problems := essenceProblems(essence); len(problems) > 0 {
+		return fmt.Errorf("persona file %s: %s", path, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// essenceProblems lists missing required fields: the persona's name, a
+// description, and at least one set of instructions to build a prompt from.
+// Out-of-range sampling defaults are reported too.
+func essenceProblems(e *CelesteEssence) []string {
+	var problems []string
+	if strings.TrimSpace(e.Character) == "" {
+		problems = append(problems, `missing "character" (the persona's name)`)
+	}
+	if strings.TrimSpace(e.Description) == "" {
+		problems = append(problems, `missing "description"`)
+	}
+	if strings.TrimSpace(e.Voice.Style) == "" && len(e.CoreRules) == 0 && len(e.InteractionRules) == 0 {
+		problems = append(problems, `no instructions: set "voice.style", "core_rules" or "interaction_rules"`)
+	}
+	if e.Sampling != nil {
+		// The model isn't known here, so only the ranges are checked
+		if err := e.Sampling.Validate("", math.MaxInt); err != nil {
+			problems = append(problems, "sampling: "+err.Error())
+		}
+	}
+	return problems
+}
+
+// PersonaPrompt returns the system prompt to send for the active persona.
+// skipDefault (skip_persona_prompt) drops the default persona's prompt, but
+// a persona chosen by name is always sent.
+func PersonaPrompt(name string, skipDefault bool) (string, error) {
+	if name == "" && skipDefault {
+		return "", nil
+	}
+	return GetPersonaPrompt(name)
+}
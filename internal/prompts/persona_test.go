@@ -0,0 +1,198 @@
+package prompts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePersona adds a persona file under a temporary home directory.
+func writePersona(t *testing.T, home, name, character string) {
+	t.Helper()
+	dir := filepath.Join(home, ".celeste", "personas")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	essence := CelesteEssence{Character: character, Description: "A test persona"}
+	essence.Voice.Style = "Calm and firm"
+	data, err := json.Marshal(essence)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), data, 0644))
+}
+
+// TestListPersonas tests that the built-in persona comes first, then files by name
+func TestListPersonas(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	assert.Equal(t, []string{DefaultPersona}, ListPersonas())
+
+	writePersona(t, home, "stream", "Stream Celeste")
+	writePersona(t, home, "moderator", "Mod Celeste")
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".celeste", "personas", "notes.txt"), []byte("x"), 0644))
+
+	assert.Equal(t, []string{DefaultPersona, "moderator", "stream"}, ListPersonas())
+	assert.True(t, IsPersona("moderator"))
+	assert.False(t, IsPersona("notes"))
+}
+
+// TestGetPersonaPrompt tests building prompts for built-in and file personas
+func TestGetPersonaPrompt(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	writePersona(t, home, "moderator", "Mod Celeste")
+
+	prompt, err := GetPersonaPrompt("")
+	require.NoError(t, err)
+	assert.Equal(t, GetSystemPrompt(false), prompt)
+
+	prompt, err = GetPersonaPrompt("moderator")
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "You are Mod Celeste.")
+	assert.Contains(t, prompt, "Calm and firm")
+
+	_, err = GetPersonaPrompt("missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: celeste, moderator")
+
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".celeste", "personas", "broken.json"), []byte("{"), 0644))
+	_, err = GetPersonaPrompt("broken")
+	assert.ErrorContains(t, err, `failed to parse persona "broken"`)
+}
+
+// TestYAMLPersonas tests that .yaml and .yml files are personas with the same fields as JSON
+func TestYAMLPersonas(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	writePersona(t, home, "moderator", "Mod Celeste")
+	dir := filepath.Join(home, ".celeste", "personas")
+
+	streamer := `character: Stream Celeste
+description: Hypes up the chat
+voice:
+  style: Loud and playful
+core_rules:
+  - Never spoil the game
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "streamer.yaml"), []byte(streamer), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cozy.yml"), []byte("character: Cozy Celeste\n"), 0644))
+	// A name with both a JSON and a YAML file is listed once and uses the JSON
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "moderator.yaml"), []byte("character: Other\n"), 0644))
+
+	assert.Equal(t, []string{DefaultPersona, "cozy", "moderator", "streamer"}, ListPersonas())
+
+	prompt, err := GetPersonaPrompt("streamer")
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "You are Stream Celeste. Hypes up the chat")
+	assert.Contains(t, prompt, "Loud and playful")
+	assert.Contains(t, prompt, "- Never spoil the game")
+	assert.NoError(t, ValidatePersona("streamer"))
+
+	prompt, err = GetPersonaPrompt("moderator")
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "You are Mod Celeste.")
+
+	// Validation names the YAML file
+	err = ValidatePersona("cozy")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(dir, "cozy.yml"))
+	assert.Contains(t, err.Error(), `missing "description"`)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tabs.yaml"), []byte("character: [unclosed\n"), 0644))
+	assert.ErrorContains(t, ValidatePersona("tabs"), "invalid YAML")
+}
+
+// TestPersonaPromptSkipDefault tests that skip_persona_prompt only drops the default persona
+func TestPersonaPromptSkipDefault(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	writePersona(t, home, "moderator", "Mod Celeste")
+
+	prompt, err := PersonaPrompt("", true)
+	require.NoError(t, err)
+	assert.Empty(t, prompt)
+
+	prompt, err = PersonaPrompt("", false)
+	require.NoError(t, err)
+	assert.Equal(t, GetSystemPrompt(false), prompt)
+
+	prompt, err = PersonaPrompt("moderator", true)
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "Mod Celeste")
+}
+
+// TestValidatePersona tests reporting missing fields and bad JSON with the file path
+func TestValidatePersona(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	// The embedded essence is always valid
+	assert.NoError(t, ValidatePersona(""))
+	assert.Empty(t, PersonaPath(DefaultPersona))
+
+	writePersona(t, home, "moderator", "Mod Celeste")
+	assert.NoError(t, ValidatePersona("moderator"))
+
+	dir := filepath.Join(home, ".celeste", "personas")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bland.json"), []byte(`{"version":"1"}`), 0644))
+	err := ValidatePersona("bland")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(dir, "bland.json"))
+	assert.Contains(t, err.Error(), `missing "character"`)
+	assert.Contains(t, err.Error(), `missing "description"`)
+	assert.Contains(t, err.Error(), "no instructions")
+
+	// A broken override of the built-in essence is reported too
+	essencePath := filepath.Join(home, ".celeste", "celeste_essence.json")
+	require.NoError(t, os.WriteFile(essencePath, []byte(`{"character":`), 0644))
+	assert.Equal(t, essencePath, PersonaPath(""))
+	err = ValidatePersona(DefaultPersona)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), essencePath)
+	assert.Contains(t, err.Error(), "invalid JSON")
+}
+
+// TestPersonaSampling tests reading and validating a persona's sampling defaults
+func TestPersonaSampling(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	writePersona(t, home, "moderator", "Mod Celeste")
+	dir := filepath.Join(home, ".celeste", "personas")
+
+	tweets := `character: Tweet Celeste
+description: Writes hype tweets
+voice:
+  style: Chaotic
+sampling:
+  temperature: 1.1
+  max_tokens: 300
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tweets.yaml"), []byte(tweets), 0644))
+
+	sampling, err := PersonaSampling("tweets")
+	require.NoError(t, err)
+	assert.Equal(t, "temperature 1.1, max_tokens 300", sampling.String())
+	assert.NoError(t, ValidatePersona("tweets"))
+
+	// Personas without a sampling section leave the defaults alone
+	sampling, err = PersonaSampling("moderator")
+	require.NoError(t, err)
+	assert.True(t, sampling.IsZero())
+	sampling, err = PersonaSampling("")
+	require.NoError(t, err)
+	assert.True(t, sampling.IsZero())
+
+	_, err = PersonaSampling("missing")
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hot.yaml"), []byte(tweets+"  top_p: 1.5\n"), 0644))
+	assert.ErrorContains(t, ValidatePersona("hot"), "sampling: top_p must be between 0 and 1")
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/sashabaranov/go-openai"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
 )
 
 // ModelService handles model listing and metadata.
@@ -25,6 +27,7 @@ func NewModelService(apiKey, baseURL, provider string) *ModelService {
 	if baseURL != "" {
 		config.BaseURL = baseURL
 	}
+	config.HTTPClient = httpclient.New(0)
 
 	return &ModelService{
 		client:   openai.NewClientWithConfig(config),
@@ -8,10 +8,12 @@ type ProviderCapabilities struct {
 	SupportsFunctionCalling bool
 	SupportsModelListing    bool
 	SupportsTokenTracking   bool // Returns usage data with stream_options
+	SupportsVision          bool // Accepts image content parts on vision-capable models
 	DefaultModel            string
 	PreferredToolModel      string // Best model for function calling
 	RequiresAPIKey          bool
 	IsOpenAICompatible      bool
+	ToolResultFormat        string // Preferred wrapper for skill results ("" = fence, "xml")
 	Notes                   string
 }
 
@@ -36,6 +38,7 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: true,
 		SupportsModelListing:    true,
 		SupportsTokenTracking:   true, // Full support via stream_options
+		SupportsVision:          true,
 		DefaultModel:            "gpt-4o-mini",
 		PreferredToolModel:      "gpt-4o-mini",
 		RequiresAPIKey:          true,
@@ -49,6 +52,7 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: true,
 		SupportsModelListing:    true,
 		SupportsTokenTracking:   true, // OpenAI-compatible token tracking
+		SupportsVision:          true,
 		DefaultModel:            "grok-4-1-fast",
 		PreferredToolModel:      "grok-4-1-fast", // Specifically trained for tool calling
 		RequiresAPIKey:          true,
@@ -62,6 +66,7 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: false, // venice-uncensored doesn't support it
 		SupportsModelListing:    true,
 		SupportsTokenTracking:   true, // Returns usage data
+		SupportsVision:          true, // Only on vision models (e.g. qwen-2.5-vl)
 		DefaultModel:            "venice-uncensored",
 		PreferredToolModel:      "", // No tool calling support in uncensored mode
 		RequiresAPIKey:          true,
@@ -77,10 +82,12 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: true,
 		SupportsModelListing:    false, // Anthropic has fixed model list
 		SupportsTokenTracking:   false, // Uses native API with different usage format
+		SupportsVision:          true,
 		DefaultModel:            "claude-sonnet-4-5-20250929",
 		PreferredToolModel:      "claude-sonnet-4-5-20250929",
 		RequiresAPIKey:          true,
 		IsOpenAICompatible:      false, // Has compatibility layer but native API differs
+		ToolResultFormat:        "xml",
 		Notes:                   "Advanced tool use features. OpenAI SDK compatibility is for testing only. Native API recommended.",
 	},
 
@@ -90,6 +97,7 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: true,
 		SupportsModelListing:    false,
 		SupportsTokenTracking:   true,
+		SupportsVision:          true,
 		DefaultModel:            "gemini-2.0-flash",
 		PreferredToolModel:      "gemini-2.0-flash",
 		RequiresAPIKey:          true,  // Simple API key from https://aistudio.google.com/apikey
@@ -103,6 +111,7 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: true,
 		SupportsModelListing:    false,
 		SupportsTokenTracking:   true,
+		SupportsVision:          true,
 		DefaultModel:            "gemini-2.0-flash",
 		PreferredToolModel:      "gemini-2.0-flash",
 		RequiresAPIKey:          false, // Uses ADC or service account - NO manual token needed!
@@ -116,6 +125,7 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: true,
 		SupportsModelListing:    true,
 		SupportsTokenTracking:   true, // OpenAI-compatible
+		SupportsVision:          true,
 		DefaultModel:            "openai/gpt-4o-mini",
 		PreferredToolModel:      "openai/gpt-4o-mini",
 		RequiresAPIKey:          true,
@@ -131,6 +141,7 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: false, // Requires cloud-hosted functions
 		SupportsModelListing:    false,
 		SupportsTokenTracking:   true, // Returns usage data with stream_options.include_usage
+		SupportsVision:          false,
 		DefaultModel:            "gpt-4o-mini",
 		PreferredToolModel:      "",
 		RequiresAPIKey:          true,
@@ -144,6 +155,7 @@ var Registry = map[string]ProviderCapabilities{
 		SupportsFunctionCalling: false, // Voice AI focused, unclear tool support
 		SupportsModelListing:    false,
 		SupportsTokenTracking:   false, // Voice-focused API, no token tracking
+		SupportsVision:          false,
 		DefaultModel:            "",
 		PreferredToolModel:      "",
 		RequiresAPIKey:          true,
@@ -276,6 +288,46 @@ func (d *ModelDetection) SupportsTools(modelID string) bool {
 	}
 }
 
+// SupportsVision determines if a model accepts image inputs.
+func (d *ModelDetection) SupportsVision(modelID string) bool {
+	caps, ok := Registry[d.provider]
+	if !ok || !caps.SupportsVision {
+		return false
+	}
+
+	switch d.provider {
+	case "openai":
+		// gpt-4o, gpt-4.1, gpt-4-turbo and gpt-5 accept images; gpt-3.5 does not
+		return contains(modelID, "gpt-4o") || contains(modelID, "gpt-4.1") ||
+			contains(modelID, "gpt-4-turbo") || contains(modelID, "gpt-5") || contains(modelID, "vision")
+
+	case "grok":
+		// grok-4 is natively multimodal, older models need the -vision variant
+		return contains(modelID, "grok-4") || contains(modelID, "vision")
+
+	case "venice":
+		// Only the dedicated vision-language models accept images
+		return contains(modelID, "-vl") || contains(modelID, "vision")
+
+	case "anthropic":
+		// All Claude 3+ models accept images
+		return contains(modelID, "claude-3") || contains(modelID, "claude-4") ||
+			contains(modelID, "claude-sonnet") || contains(modelID, "claude-opus") || contains(modelID, "claude-haiku")
+
+	case "gemini", "vertex":
+		// Every Gemini model is multimodal
+		return contains(modelID, "gemini")
+
+	case "openrouter":
+		// OpenRouter prefixes models with provider name
+		return contains(modelID, "gpt-4o") || contains(modelID, "claude-") ||
+			contains(modelID, "gemini-") || contains(modelID, "vision") || contains(modelID, "-vl")
+
+	default:
+		return false
+	}
+}
+
 // GetDefaultToolModel returns the best model for tool calling.
 func (d *ModelDetection) GetDefaultToolModel() string {
 	caps, ok := Registry[d.provider]
@@ -280,3 +280,30 @@ func TestProviderNotes(t *testing.T) {
 		})
 	}
 }
+
+// TestModelSupportsVision tests vision capability detection per provider and model
+func TestModelSupportsVision(t *testing.T) {
+	tests := []struct {
+		provider string
+		model    string
+		expected bool
+	}{
+		{"openai", "gpt-4o-mini", true},
+		{"openai", "gpt-3.5-turbo", false},
+		{"grok", "grok-4-1-fast", true},
+		{"venice", "venice-uncensored", false},
+		{"venice", "qwen-2.5-vl", true},
+		{"anthropic", "claude-sonnet-4-5-20250929", true},
+		{"gemini", "gemini-2.0-flash", true},
+		{"openrouter", "openai/gpt-4o-mini", true},
+		{"digitalocean", "gpt-4o-mini", false},
+		{"unknown", "gpt-4o", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+"/"+tt.model, func(t *testing.T) {
+			detection := NewModelDetection(tt.provider)
+			assert.Equal(t, tt.expected, detection.SupportsVision(tt.model))
+		})
+	}
+}
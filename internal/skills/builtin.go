@@ -4,6 +4,7 @@ package skills
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha256"
@@ -11,23 +12,36 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/skip2/go-qrcode"
+
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/version"
 )
 
 // RegisterBuiltinSkills registers all built-in skills with the registry.
 func RegisterBuiltinSkills(registry *Registry, configLoader ConfigLoader) {
+	if defaults, err := configLoader.GetSkillDefaults(); err == nil {
+		registry.SetDefaults(defaults)
+	}
+
 	// Register skill definitions
 	registry.RegisterSkill(TarotSkill())
 	registry.RegisterSkill(WeatherSkill())
+	registry.RegisterSkill(GetLocationSkill())
 	registry.RegisterSkill(UnitConverterSkill())
 	registry.RegisterSkill(TimezoneConverterSkill())
 	registry.RegisterSkill(HashGeneratorSkill())
@@ -36,21 +50,34 @@ func RegisterBuiltinSkills(registry *Registry, configLoader ConfigLoader) {
 	registry.RegisterSkill(UUIDGeneratorSkill())
 	registry.RegisterSkill(PasswordGeneratorSkill())
 	registry.RegisterSkill(CurrencyConverterSkill())
+	registry.RegisterSkill(GetPriceSkill())
 	registry.RegisterSkill(QRCodeGeneratorSkill())
 	registry.RegisterSkill(TwitchLiveCheckSkill())
 	registry.RegisterSkill(YouTubeVideosSkill())
+	registry.RegisterSkill(PostDiscordSkill())
+	registry.RegisterSkill(PostTweetSkill())
+	registry.RegisterSkill(PostMastodonSkill())
+	registry.RegisterSkill(ReadFeedSkill())
 	registry.RegisterSkill(SetReminderSkill())
 	registry.RegisterSkill(ListRemindersSkill())
 	registry.RegisterSkill(SaveNoteSkill())
 	registry.RegisterSkill(GetNoteSkill())
 	registry.RegisterSkill(ListNotesSkill())
+	registry.RegisterSkill(RollDiceSkill())
+	registry.RegisterSkill(PickRandomSkill())
+	registry.RegisterSkill(AnalyzeTextSkill())
+	registry.RegisterSkill(FormatJSONSkill())
+	registry.RegisterSkill(ImageGenerationSkill())
 
 	// Register handlers
-	registry.RegisterHandler("tarot_reading", func(args map[string]interface{}) (interface{}, error) {
-		return TarotHandler(args, configLoader)
+	registry.RegisterContextHandler("tarot_reading", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return TarotHandler(ctx, args, configLoader)
 	})
-	registry.RegisterHandler("get_weather", func(args map[string]interface{}) (interface{}, error) {
-		return WeatherHandler(args, configLoader)
+	registry.RegisterContextHandler("get_weather", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return WeatherHandler(ctx, args, configLoader)
+	})
+	registry.RegisterContextHandler("get_location", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return GetLocationHandler(ctx, configLoader)
 	})
 	registry.RegisterHandler("convert_units", func(args map[string]interface{}) (interface{}, error) {
 		return UnitConverterHandler(args)
@@ -73,32 +100,62 @@ func RegisterBuiltinSkills(registry *Registry, configLoader ConfigLoader) {
 	registry.RegisterHandler("generate_password", func(args map[string]interface{}) (interface{}, error) {
 		return PasswordGeneratorHandler(args)
 	})
-	registry.RegisterHandler("convert_currency", func(args map[string]interface{}) (interface{}, error) {
-		return CurrencyConverterHandler(args)
+	registry.RegisterContextHandler("convert_currency", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return CurrencyConverterHandler(ctx, args)
+	})
+	registry.RegisterContextHandler("get_price", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return GetPriceHandler(ctx, args)
 	})
 	registry.RegisterHandler("generate_qr_code", func(args map[string]interface{}) (interface{}, error) {
-		return QRCodeGeneratorHandler(args)
+		return QRCodeGeneratorHandler(args, configLoader)
+	})
+	registry.RegisterContextHandler("check_twitch_live", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return TwitchLiveCheckHandler(ctx, args, configLoader)
 	})
-	registry.RegisterHandler("check_twitch_live", func(args map[string]interface{}) (interface{}, error) {
-		return TwitchLiveCheckHandler(args, configLoader)
+	registry.RegisterContextHandler("get_youtube_videos", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return YouTubeVideosHandler(ctx, args, configLoader)
 	})
-	registry.RegisterHandler("get_youtube_videos", func(args map[string]interface{}) (interface{}, error) {
-		return YouTubeVideosHandler(args, configLoader)
+	registry.RegisterContextHandler("post_discord", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return PostDiscordHandler(ctx, args, configLoader)
+	})
+	registry.RegisterContextHandler("post_tweet", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return PostTweetHandler(ctx, args, configLoader)
+	})
+	registry.RegisterContextHandler("post_mastodon", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return PostMastodonHandler(ctx, args, configLoader)
+	})
+	registry.RegisterContextHandler("read_feed", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return ReadFeedHandler(ctx, args)
 	})
 	registry.RegisterHandler("set_reminder", func(args map[string]interface{}) (interface{}, error) {
-		return SetReminderHandler(args)
+		return SetReminderHandler(args, configLoader)
 	})
 	registry.RegisterHandler("list_reminders", func(args map[string]interface{}) (interface{}, error) {
-		return ListRemindersHandler(args)
+		return ListRemindersHandler(args, configLoader)
 	})
 	registry.RegisterHandler("save_note", func(args map[string]interface{}) (interface{}, error) {
-		return SaveNoteHandler(args)
+		return SaveNoteHandler(args, configLoader)
 	})
 	registry.RegisterHandler("get_note", func(args map[string]interface{}) (interface{}, error) {
-		return GetNoteHandler(args)
+		return GetNoteHandler(args, configLoader)
 	})
 	registry.RegisterHandler("list_notes", func(args map[string]interface{}) (interface{}, error) {
-		return ListNotesHandler(args)
+		return ListNotesHandler(args, configLoader)
+	})
+	registry.RegisterHandler("roll_dice", func(args map[string]interface{}) (interface{}, error) {
+		return RollDiceHandler(args)
+	})
+	registry.RegisterHandler("pick_random", func(args map[string]interface{}) (interface{}, error) {
+		return PickRandomHandler(args)
+	})
+	registry.RegisterHandler("analyze_text", func(args map[string]interface{}) (interface{}, error) {
+		return AnalyzeTextHandler(args)
+	})
+	registry.RegisterHandler("format_json", func(args map[string]interface{}) (interface{}, error) {
+		return FormatJSONHandler(args)
+	})
+	registry.RegisterContextHandler("generate_image", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return ImageGenerationHandler(ctx, args, configLoader)
 	})
 
 	// Register crypto skills (IPFS, Alchemy, Blockchain Monitoring)
@@ -110,12 +167,18 @@ type ConfigLoader interface {
 	GetTarotConfig() (TarotConfig, error)
 	GetVeniceConfig() (VeniceConfig, error)
 	GetWeatherConfig() (WeatherConfig, error)
+	GetLocationConfig() (LocationConfig, error)
 	GetTwitchConfig() (TwitchConfig, error)
 	GetYouTubeConfig() (YouTubeConfig, error)
+	GetDiscordConfig() (DiscordConfig, error)
+	GetTwitterConfig() (TwitterConfig, error)
+	GetMastodonConfig() (MastodonConfig, error)
 	GetIPFSConfig() (IPFSConfig, error)
 	GetAlchemyConfig() (AlchemyConfig, error)
 	GetBlockmonConfig() (BlockmonConfig, error)
 	GetWalletSecurityConfig() (WalletSecuritySettingsConfig, error)
+	GetWorkspaceConfig() (WorkspaceConfig, error)
+	GetSkillDefaults() (SkillDefaults, error)
 }
 
 // TarotConfig holds tarot function configuration.
@@ -136,6 +199,14 @@ type VeniceConfig struct {
 // WeatherConfig holds weather skill configuration.
 type WeatherConfig struct {
 	DefaultZipCode string
+	AlertsSource   string // "nws" (default) or "off"
+	Units          string // "auto" (default), "metric" or "imperial"
+}
+
+// LocationConfig holds IP geolocation configuration.
+type LocationConfig struct {
+	Provider string // "ipapi" (default), "ipinfo" or "ip-api"
+	Token    string // ipinfo.io access token (optional)
 }
 
 // TwitchConfig holds Twitch API configuration.
@@ -151,6 +222,26 @@ type YouTubeConfig struct {
 	DefaultChannel string
 }
 
+// DiscordConfig holds Discord webhook configuration.
+type DiscordConfig struct {
+	WebhookURL string
+}
+
+// TwitterConfig holds X (Twitter) API credentials.
+type TwitterConfig struct {
+	BearerToken       string
+	APIKey            string // OAuth 1.0a consumer key
+	APISecret         string
+	AccessToken       string
+	AccessTokenSecret string
+}
+
+// MastodonConfig holds Mastodon instance configuration.
+type MastodonConfig struct {
+	InstanceURL string // e.g. https://mastodon.social
+	AccessToken string // Needs the write:statuses scope
+}
+
 // IPFSConfig holds IPFS configuration.
 type IPFSConfig struct {
 	Provider       string
@@ -183,6 +274,13 @@ type WalletSecuritySettingsConfig struct {
 	AlertLevel   string // minimum severity to alert on
 }
 
+// WorkspaceConfig holds where skill data files (notes, reminders, QR codes)
+// are stored for the active config profile.
+type WorkspaceConfig struct {
+	Name string // Profile name, "default" for the global workspace
+	Dir  string // Directory holding notes.json, reminders.json, tarot_history.json and qr_codes/
+}
+
 // --- Helper Functions for Error Handling ---
 
 // formatErrorResponse creates a structured error response for LLM interpretation.
@@ -202,6 +300,35 @@ func formatErrorResponse(errorType, message, hint string, context map[string]int
 	return result
 }
 
+// contextErrorResponse reports a skill call that stopped because its context
+// was cancelled or ran out of time. It returns nil while ctx is still live.
+func contextErrorResponse(ctx context.Context, skill string) map[string]interface{} {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return formatErrorResponse(
+			"timeout",
+			"The request timed out",
+			"The service took too long to respond. Please try again.",
+			map[string]interface{}{
+				"skill": skill,
+				"error": err.Error(),
+			},
+		)
+	}
+	return formatErrorResponse(
+		"cancelled",
+		"The request was cancelled",
+		"",
+		map[string]interface{}{
+			"skill": skill,
+			"error": err.Error(),
+		},
+	)
+}
+
 // getUserOrDefault gets a value from args first, then falls back to config default.
 // Returns: (value, found) - found is true if value was found (either from args or config)
 func getUserOrDefault(args map[string]interface{}, key string, configGetter func() string) (string, bool) {
@@ -244,19 +371,23 @@ func formatConfigError(skillName, fieldName, configCommand string) map[string]in
 func TarotSkill() Skill {
 	return Skill{
 		Name:        "tarot_reading",
-		Description: "Generate a tarot card reading using either a three-card spread (past/present/future) or a celtic cross spread",
+		Description: "Generate a tarot card reading: a single card of the day, a three-card spread (past/present/future), a five-card horseshoe or a celtic cross spread",
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"spread_type": map[string]interface{}{
 					"type":        "string",
-					"enum":        []string{"three", "celtic"},
-					"description": "Type of spread: 'three' for 3-card past/present/future, 'celtic' for 10-card celtic cross",
+					"enum":        TarotSpreads,
+					"description": "Type of spread: 'single' for one card of the day, 'three' for 3-card past/present/future, 'horseshoe' for a 5-card horseshoe, 'celtic' for 10-card celtic cross",
 				},
 				"question": map[string]interface{}{
 					"type":        "string",
 					"description": "Optional question to focus the reading on",
 				},
+				"save": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Save the reading to the user's tarot history. Only when the user asks to keep or track it",
+				},
 			},
 			"required": []string{"spread_type"},
 		},
@@ -267,18 +398,39 @@ func TarotSkill() Skill {
 func WeatherSkill() Skill {
 	return Skill{
 		Name:        "get_weather",
-		Description: "Get current weather and forecast for a location. Uses default zip code if not specified. User can provide zip code in the prompt to override default.",
+		Description: "Get current weather and forecast for a location given as a city name, latitude/longitude or US zip code. Uses default zip code if none is given, or the user's approximate location from their IP address when no default is set. Includes active severe weather alerts for US locations.",
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
+				"city": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional city name, anywhere in the world (e.g. 'Tokyo', 'Paris, France')",
+				},
+				"lat": map[string]interface{}{
+					"type":        "number",
+					"description": "Optional latitude (-90 to 90). Must be given together with lon.",
+				},
+				"lon": map[string]interface{}{
+					"type":        "number",
+					"description": "Optional longitude (-180 to 180). Must be given together with lat.",
+				},
 				"zip_code": map[string]interface{}{
 					"type":        "string",
-					"description": "Optional zip code (5 digits). If not provided, uses default zip code from configuration. User can specify zip code in their message to override default.",
+					"description": "Optional US zip code (5 digits). If no location is given, uses default zip code from configuration. User can specify zip code in their message to override default.",
 				},
 				"days": map[string]interface{}{
 					"type":        "integer",
 					"description": "Number of days for forecast (1-3, default: 1 for current weather)",
 				},
+				"units": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"metric", "imperial"},
+					"description": "Optional unit system. Defaults to the configured units, or imperial in the US and metric elsewhere.",
+				},
+				"raw": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also include the full wttr.in response (large; only when the summary lacks something)",
+				},
 			},
 			"required": []string{},
 		},
@@ -325,11 +477,11 @@ func TimezoneConverterSkill() Skill {
 				},
 				"from_timezone": map[string]interface{}{
 					"type":        "string",
-					"description": "Source timezone (e.g., 'America/New_York', 'UTC', 'Asia/Tokyo')",
+					"description": "Source timezone: IANA name (e.g., 'America/New_York'), abbreviation (e.g., 'PST', 'JST') or city (e.g., 'Tokyo')",
 				},
 				"to_timezone": map[string]interface{}{
 					"type":        "string",
-					"description": "Target timezone (e.g., 'America/New_York', 'UTC', 'Asia/Tokyo')",
+					"description": "Target timezone: IANA name (e.g., 'America/New_York'), abbreviation (e.g., 'PST', 'JST') or city (e.g., 'Tokyo')",
 				},
 				"date": map[string]interface{}{
 					"type":        "string",
@@ -470,6 +622,7 @@ func QRCodeGeneratorSkill() Skill {
 	return Skill{
 		Name:        "generate_qr_code",
 		Description: "Generate a QR code from text or URL",
+		SideEffect:  true,
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -515,7 +668,7 @@ func YouTubeVideosSkill() Skill {
 			"properties": map[string]interface{}{
 				"channel": map[string]interface{}{
 					"type":        "string",
-					"description": "Optional YouTube channel username or channel ID. If not provided, uses default channel from configuration. User can specify channel in their message to override default.",
+					"description": "Optional YouTube channel @handle, username or channel ID. If not provided, uses default channel from configuration. User can specify channel in their message to override default.",
 				},
 				"max_results": map[string]interface{}{
 					"type":        "integer",
@@ -532,6 +685,7 @@ func SetReminderSkill() Skill {
 	return Skill{
 		Name:        "set_reminder",
 		Description: "Set a reminder with a specific time and message",
+		SideEffect:  true,
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -567,6 +721,7 @@ func SaveNoteSkill() Skill {
 	return Skill{
 		Name:        "save_note",
 		Description: "Save a note with an optional title",
+		SideEffect:  true,
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -617,8 +772,21 @@ func ListNotesSkill() Skill {
 
 // --- Skill Handlers ---
 
-// TarotHandler executes a tarot reading.
-func TarotHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+// TarotHandler executes a tarot reading. With save set, the reading is
+// also added to the tarot history.
+func TarotHandler(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+	result, err := drawTarotReading(ctx, args, configLoader)
+	if save, _ := args["save"].(bool); save && err == nil {
+		if reading, ok := result.(map[string]interface{}); ok {
+			saveTarotReading(reading, configLoader)
+		}
+	}
+	return result, err
+}
+
+// drawTarotReading draws a reading from the tarot function, or from the
+// local deck when the function is unavailable.
+func drawTarotReading(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
 	config, err := configLoader.GetTarotConfig()
 	if err != nil {
 		return formatErrorResponse(
@@ -634,7 +802,19 @@ func TarotHandler(args map[string]interface{}, configLoader ConfigLoader) (inter
 
 	spreadType := "three"
 	if st, ok := args["spread_type"].(string); ok {
-		spreadType = st
+		spreadType = strings.ToLower(strings.TrimSpace(st))
+	}
+	if err := validateTarotSpread(spreadType); err != nil {
+		return formatErrorResponse(
+			"validation_error",
+			"Unknown tarot spread",
+			"Use spread_type "+strings.Join(TarotSpreads, ", ")+".",
+			map[string]interface{}{
+				"skill":  "tarot_reading",
+				"field":  "spread_type",
+				"reason": err.Error(),
+			},
+		), nil
 	}
 
 	question := ""
@@ -663,7 +843,7 @@ func TarotHandler(args map[string]interface{}, configLoader ConfigLoader) (inter
 		), nil
 	}
 
-	req, err := http.NewRequest("POST", config.FunctionURL, bytes.NewBuffer(reqBodyBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", config.FunctionURL, bytes.NewBuffer(reqBodyBytes))
 	if err != nil {
 		return formatErrorResponse(
 			"internal_error",
@@ -675,6 +855,7 @@ func TarotHandler(args map[string]interface{}, configLoader ConfigLoader) (inter
 			},
 		), nil
 	}
+	req.Header.Set("User-Agent", version.UserAgent())
 
 	// Set Authorization header - token may already include "Basic " prefix
 	authToken := config.AuthToken
@@ -685,9 +866,7 @@ func TarotHandler(args map[string]interface{}, configLoader ConfigLoader) (inter
 	req.Header.Set("Content-Type", "application/json")
 
 	// Create client with timeout and logging
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	client := httpclient.New(0)
 
 	// Make request with timeout
 	startTime := time.Now()
@@ -695,49 +874,28 @@ func TarotHandler(args map[string]interface{}, configLoader ConfigLoader) (inter
 	elapsed := time.Since(startTime)
 
 	if err != nil {
-		return formatErrorResponse(
-			"network_error",
-			"Failed to connect to tarot API",
-			"Please check your internet connection and try again.",
-			map[string]interface{}{
-				"skill":   "tarot_reading",
-				"error":   err.Error(),
-				"elapsed": elapsed.String(),
-			},
-		), nil
+		if cancelled := contextErrorResponse(ctx, "tarot_reading"); cancelled != nil {
+			return cancelled, nil
+		}
+		// Remote function unreachable or timed out - draw from the local deck
+		return localTarotReading(spreadType, question, fmt.Sprintf("tarot API unavailable: %v", err))
 	}
 	defer resp.Body.Close()
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return formatErrorResponse(
-			"api_error",
-			"Failed to read tarot API response",
-			"The tarot service may have returned invalid data. Please try again.",
-			map[string]interface{}{
-				"skill":   "tarot_reading",
-				"error":   err.Error(),
-				"elapsed": elapsed.String(),
-			},
-		), nil
+		if cancelled := contextErrorResponse(ctx, "tarot_reading"); cancelled != nil {
+			return cancelled, nil
+		}
+		return localTarotReading(spreadType, question, fmt.Sprintf("failed to read tarot API response after %s: %v", elapsed, err))
 	}
 
 	if resp.StatusCode != 200 {
-		return formatErrorResponse(
-			"api_error",
-			fmt.Sprintf("Tarot API returned error (status %d)", resp.StatusCode),
-			"The tarot reading service may be temporarily unavailable. Please try again later.",
-			map[string]interface{}{
-				"skill":       "tarot_reading",
-				"status_code": resp.StatusCode,
-				"response":    string(responseBody),
-				"elapsed":     elapsed.String(),
-			},
-		), nil
+		return localTarotReading(spreadType, question, fmt.Sprintf("tarot API returned status %d", resp.StatusCode))
 	}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(responseBody, &result); err != nil {
+	reading, err := decodeTarotReading(responseBody, spreadType)
+	if err != nil {
 		return formatErrorResponse(
 			"api_error",
 			"Failed to parse tarot API response",
@@ -750,11 +908,17 @@ func TarotHandler(args map[string]interface{}, configLoader ConfigLoader) (inter
 		), nil
 	}
 
-	return result, nil
+	return reading.result("remote", question), nil
 }
 
+// weatherCacheTTL is how long a forecast is reused before asking wttr.in again.
+const weatherCacheTTL = 15 * time.Minute
+
+// wttrBaseURL is the wttr.in weather service. Overridden in tests.
+var wttrBaseURL = "https://wttr.in"
+
 // WeatherHandler gets weather forecast for a location.
-func WeatherHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+func WeatherHandler(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
 	// Try to get config, but don't fail if it's not configured
 	config, err := configLoader.GetWeatherConfig()
 	if err != nil {
@@ -762,47 +926,88 @@ func WeatherHandler(args map[string]interface{}, configLoader ConfigLoader) (int
 		config = WeatherConfig{}
 	}
 
-	// Get zip code - accept both string and number types
-	var zipCode string
-	var found bool
-
-	// Try string first
-	if val, ok := args["zip_code"].(string); ok && val != "" {
-		zipCode = val
-		found = true
-	} else if val, ok := args["zip_code"].(float64); ok {
-		// Convert number to string (for CLI numeric conversion)
-		zipCode = fmt.Sprintf("%.0f", val)
-		found = true
-	} else {
-		// Fall back to config default
-		zipCode = config.DefaultZipCode
-		found = zipCode != ""
-	}
-
-	// Only return error/info if BOTH user-provided zip AND default are missing
-	if !found {
-		return formatConfigError("get_weather", "zip_code", "celeste config --set-weather-zip <zip>"), nil
+	// A city or coordinates take precedence over any zip code
+	city, _ := args["city"].(string)
+	city = strings.TrimSpace(city)
+	lat, hasLat := weatherCoordinate(args["lat"])
+	lon, hasLon := weatherCoordinate(args["lon"])
+	if hasLat != hasLon {
+		missing := "lat"
+		if hasLat {
+			missing = "lon"
+		}
+		return formatErrorResponse(
+			"validation_error",
+			"Both lat and lon are required for a coordinate lookup",
+			"Provide lat and lon together, e.g. lat 35.68 and lon 139.69",
+			map[string]interface{}{
+				"skill": "get_weather",
+				"field": missing,
+			},
+		), nil
 	}
-
-	// Validate zip code format (5 digits)
-	if len(zipCode) != 5 {
+	if hasLat && (lat < -90 || lat > 90 || lon < -180 || lon > 180) {
 		return formatErrorResponse(
 			"validation_error",
-			"Zip code must be exactly 5 digits",
-			"Please provide a valid 5-digit US zip code",
+			"Coordinates out of range",
+			"Latitude must be between -90 and 90 and longitude between -180 and 180",
 			map[string]interface{}{
 				"skill":    "get_weather",
-				"field":    "zip_code",
-				"provided": zipCode,
+				"field":    "lat",
+				"provided": fmt.Sprintf("%g,%g", lat, lon),
 			},
 		), nil
 	}
-	for _, c := range zipCode {
-		if c < '0' || c > '9' {
+
+	// Get zip code - accept both string and number types
+	var zipCode string
+	var found bool
+
+	if !hasLat && city == "" {
+		// Try string first
+		if val, ok := args["zip_code"].(string); ok && val != "" {
+			zipCode = val
+			found = true
+		} else if val, ok := args["zip_code"].(float64); ok {
+			// Convert number to string (for CLI numeric conversion)
+			zipCode = fmt.Sprintf("%.0f", val)
+			found = true
+		} else {
+			// Fall back to config default
+			zipCode = config.DefaultZipCode
+			found = zipCode != ""
+		}
+	}
+
+	// Without a city, coordinates or zip code, locate the user by IP
+	// address unless geolocation is turned off
+	query := zipCode
+	var location *Location
+	switch {
+	case hasLat:
+		query = strconv.FormatFloat(lat, 'f', 4, 64) + "," + strconv.FormatFloat(lon, 'f', 4, 64)
+	case city != "":
+		query = url.PathEscape(city)
+	case !found:
+		locationConfig, err := configLoader.GetLocationConfig()
+		if err != nil {
+			return formatConfigError("get_weather", "zip_code", "celeste config --set-weather-zip <zip>"), nil
+		}
+		ReportProgress(ctx, "looking up location")
+		loc, errResp := lookupLocation(ctx, locationConfig, "get_weather")
+		if errResp != nil {
+			return errResp, nil
+		}
+		location = &loc
+		query = loc.Coordinates()
+	}
+
+	if found {
+		// Validate zip code format (5 digits)
+		if len(zipCode) != 5 {
 			return formatErrorResponse(
 				"validation_error",
-				"Zip code must contain only digits",
+				"Zip code must be exactly 5 digits",
 				"Please provide a valid 5-digit US zip code",
 				map[string]interface{}{
 					"skill":    "get_weather",
@@ -811,6 +1016,20 @@ func WeatherHandler(args map[string]interface{}, configLoader ConfigLoader) (int
 				},
 			), nil
 		}
+		for _, c := range zipCode {
+			if c < '0' || c > '9' {
+				return formatErrorResponse(
+					"validation_error",
+					"Zip code must contain only digits",
+					"Please provide a valid 5-digit US zip code",
+					map[string]interface{}{
+						"skill":    "get_weather",
+						"field":    "zip_code",
+						"provided": zipCode,
+					},
+				), nil
+			}
+		}
 	}
 
 	// Get forecast days (default 1 for current weather)
@@ -825,62 +1044,96 @@ func WeatherHandler(args map[string]interface{}, configLoader ConfigLoader) (int
 		}
 	}
 
-	// Use wttr.in API (free, no key required)
-	// Format: https://wttr.in/{zip}?format=j1 for JSON
-	url := fmt.Sprintf("https://wttr.in/%s?format=j1", zipCode)
-	if days > 1 {
-		url = fmt.Sprintf("https://wttr.in/%s?format=j1&days=%d", zipCode, days)
-	}
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
-	if err != nil {
+	// An unknown unit system is rejected before anything is fetched
+	requestedUnits, _ := args["units"].(string)
+	if _, ok := weatherUnits(requestedUnits, config.Units, ""); !ok {
 		return formatErrorResponse(
-			"network_error",
-			"Failed to connect to weather service",
-			"Please check your internet connection and try again.",
+			"validation_error",
+			"Units must be metric or imperial",
+			"Use metric or imperial, or leave units out for the default",
 			map[string]interface{}{
-				"skill": "get_weather",
-				"error": err.Error(),
+				"skill":    "get_weather",
+				"field":    "units",
+				"provided": requestedUnits,
 			},
 		), nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return formatErrorResponse(
-			"api_error",
-			fmt.Sprintf("Weather API returned error (status %d)", resp.StatusCode),
-			"The weather service may be temporarily unavailable. Please try again later.",
-			map[string]interface{}{
-				"skill":       "get_weather",
-				"status_code": resp.StatusCode,
-				"response":    string(body),
-			},
-		), nil
-	}
+	// Use wttr.in API (free, no key required)
+	// Format: https://wttr.in/{zip, city or lat,lon}?format=j1 for JSON, which
+	// always includes three days of forecast
+	endpoint := fmt.Sprintf("%s/%s?format=j1", wttrBaseURL, query)
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return formatErrorResponse(
-			"api_error",
-			"Failed to parse weather API response",
-			"The weather service returned invalid data. Please try again.",
-			map[string]interface{}{
-				"skill": "get_weather",
-				"error": err.Error(),
-			},
-		), nil
+	ReportProgress(ctx, "fetching forecast")
+	var raw json.RawMessage
+	if err := CachedGetJSON(ctx, endpoint, weatherCacheTTL, &raw); err != nil {
+		return httpGetErrorResponse(ctx, err, "get_weather", "Weather API"), nil
+	}
+	var report wttrResponse
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return httpGetErrorResponse(ctx, &httpGetError{Kind: "parse", Err: err}, "get_weather", "Weather API"), nil
 	}
 
-	// Add zip code to result for reference
-	result["zip_code"] = zipCode
+	// The full j1 response is thousands of tokens; return the essentials
+	units, _ := weatherUnits(requestedUnits, config.Units, report.country())
+	current, area, forecast := summarizeWeather(report, days, units)
+	result := map[string]interface{}{
+		"current":  current,
+		"forecast": forecast,
+		"units":    units,
+	}
+	if area != "" {
+		result["area"] = area
+	}
+	if includeRaw, _ := args["raw"].(bool); includeRaw {
+		result["raw"] = raw
+	}
+
+	// Alerts come from a second service; the forecast is returned without
+	// them when it fails
+	alerts, checked, err := lookupWeatherAlerts(ctx, config.AlertsSource, report)
+	switch {
+	case err != nil:
+		result["alerts_error"] = fmt.Sprintf("Weather alerts are unavailable right now: %v", err)
+	case checked:
+		result["alerts"] = alerts
+		result["alerts_source"] = "National Weather Service"
+	}
+
+	// Add the location to result for reference
+	switch {
+	case hasLat:
+		result["latitude"] = lat
+		result["longitude"] = lon
+		result["location_source"] = "coordinates"
+	case city != "":
+		result["city"] = city
+		result["location_source"] = "city"
+	case location != nil:
+		result["location"] = location
+		result["location_source"] = "ip"
+	default:
+		result["zip_code"] = zipCode
+	}
 	result["requested_days"] = days
 
 	return result, nil
 }
 
+// weatherCoordinate reads a lat or lon argument, given as a number or (from
+// the command line) a numeric string.
+func weatherCoordinate(arg interface{}) (float64, bool) {
+	switch v := arg.(type) {
+	case float64:
+		return v, true
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
 // UnitConverterHandler converts between different units.
 func UnitConverterHandler(args map[string]interface{}) (interface{}, error) {
 	value, ok := args["value"].(float64)
@@ -1082,37 +1335,17 @@ func TimezoneConverterHandler(args map[string]interface{}) (interface{}, error)
 		), nil
 	}
 
-	// Load timezone locations
-	fromLoc, err := time.LoadLocation(fromTZ)
-	if err != nil {
-		return formatErrorResponse(
-			"validation_error",
-			fmt.Sprintf("Invalid timezone '%s'", fromTZ),
-			"Please use a valid IANA timezone identifier (e.g., 'America/New_York', 'UTC', 'Asia/Tokyo').",
-			map[string]interface{}{
-				"skill":    "convert_timezone",
-				"field":    "from_timezone",
-				"provided": fromTZ,
-				"error":    err.Error(),
-			},
-		), nil
+	// Resolve abbreviations and city names to IANA zones
+	fromLoc, fromName, errResp := resolveTimezoneArg("from_timezone", fromTZ)
+	if errResp != nil {
+		return errResp, nil
 	}
-
-	toLoc, err := time.LoadLocation(toTZ)
-	if err != nil {
-		return formatErrorResponse(
-			"validation_error",
-			fmt.Sprintf("Invalid timezone '%s'", toTZ),
-			"Please use a valid IANA timezone identifier (e.g., 'America/New_York', 'UTC', 'Asia/Tokyo').",
-			map[string]interface{}{
-				"skill":    "convert_timezone",
-				"field":    "to_timezone",
-				"provided": toTZ,
-				"error":    err.Error(),
-			},
-		), nil
+	toLoc, toName, errResp := resolveTimezoneArg("to_timezone", toTZ)
+	if errResp != nil {
+		return errResp, nil
 	}
 
+	var err error
 	// Parse time if provided, otherwise use current time
 	var t time.Time
 	if timeStr, ok := args["time"].(string); ok && timeStr != "" {
@@ -1189,14 +1422,55 @@ func TimezoneConverterHandler(args map[string]interface{}) (interface{}, error)
 	return map[string]interface{}{
 		"original_time":   t.Format("2006-01-02 15:04:05 MST"),
 		"converted_time":  converted.Format("2006-01-02 15:04:05 MST"),
-		"from_timezone":   fromTZ,
-		"to_timezone":     toTZ,
+		"from_timezone":   fromName,
+		"to_timezone":     toName,
+		"from_input":      fromTZ,
+		"to_input":        toTZ,
 		"original_utc":    t.UTC().Format("2006-01-02 15:04:05 UTC"),
 		"converted_utc":   converted.UTC().Format("2006-01-02 15:04:05 UTC"),
 		"timezone_offset": converted.Format("-07:00"),
 	}, nil
 }
 
+// resolveTimezoneArg resolves a timezone argument to a location and its IANA name.
+// Returns a structured error response when the input is unknown or ambiguous.
+func resolveTimezoneArg(field, input string) (*time.Location, string, map[string]interface{}) {
+	zone, candidates := resolveTimezone(input)
+	if len(candidates) > 0 {
+		return nil, "", formatErrorResponse(
+			"validation_error",
+			fmt.Sprintf("Ambiguous timezone '%s'", input),
+			fmt.Sprintf("'%s' can refer to several timezones. Ask the user which one they mean: %s.", input, strings.Join(candidates, ", ")),
+			map[string]interface{}{
+				"skill":      "convert_timezone",
+				"field":      field,
+				"provided":   input,
+				"candidates": candidates,
+			},
+		)
+	}
+
+	loc, err := time.LoadLocation(zone)
+	if zone == "" || err != nil {
+		context := map[string]interface{}{
+			"skill":    "convert_timezone",
+			"field":    field,
+			"provided": input,
+		}
+		if err != nil {
+			context["error"] = err.Error()
+		}
+		return nil, "", formatErrorResponse(
+			"validation_error",
+			fmt.Sprintf("Invalid timezone '%s'", input),
+			"Please use an IANA timezone (e.g., 'America/New_York'), a common abbreviation (e.g., 'PST', 'JST') or a city name (e.g., 'Tokyo').",
+			context,
+		)
+	}
+
+	return loc, zone, nil
+}
+
 // HashGeneratorHandler generates a hash for the given text.
 func HashGeneratorHandler(args map[string]interface{}) (interface{}, error) {
 	text, ok := args["text"].(string)
@@ -1379,8 +1653,12 @@ func PasswordGeneratorHandler(args map[string]interface{}) (interface{}, error)
 	}, nil
 }
 
+// currencyCacheTTL is how long exchange rates are reused; the free tier
+// updates them once a day.
+const currencyCacheTTL = time.Hour
+
 // CurrencyConverterHandler converts between currencies using exchangerate-api.com.
-func CurrencyConverterHandler(args map[string]interface{}) (interface{}, error) {
+func CurrencyConverterHandler(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	amount, ok := args["amount"].(float64)
 	if !ok {
 		return formatErrorResponse(
@@ -1438,51 +1716,13 @@ func CurrencyConverterHandler(args map[string]interface{}) (interface{}, error)
 	// First get rates for the base currency
 	url := fmt.Sprintf("https://api.exchangerate-api.com/v6/latest/%s", fromCurrency)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
-	if err != nil {
-		return formatErrorResponse(
-			"network_error",
-			"Failed to connect to currency API",
-			"Please check your internet connection and try again.",
-			map[string]interface{}{
-				"skill": "convert_currency",
-				"error": err.Error(),
-			},
-		), nil
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return formatErrorResponse(
-			"api_error",
-			fmt.Sprintf("Currency API returned error (status %d)", resp.StatusCode),
-			"The currency exchange service may be temporarily unavailable. Please try again later.",
-			map[string]interface{}{
-				"skill":       "convert_currency",
-				"status_code": resp.StatusCode,
-				"response":    string(body),
-			},
-		), nil
-	}
-
 	var result struct {
 		Rates map[string]float64 `json:"rates"`
 		Base  string             `json:"base"`
 		Date  string             `json:"date"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return formatErrorResponse(
-			"api_error",
-			"Failed to parse currency API response",
-			"The currency service returned invalid data. Please try again.",
-			map[string]interface{}{
-				"skill": "convert_currency",
-				"error": err.Error(),
-			},
-		), nil
+	if err := CachedGetJSON(ctx, url, currencyCacheTTL, &result); err != nil {
+		return httpGetErrorResponse(ctx, err, "convert_currency", "Currency API"), nil
 	}
 
 	rate, ok := result.Rates[toCurrency]
@@ -1512,7 +1752,7 @@ func CurrencyConverterHandler(args map[string]interface{}) (interface{}, error)
 }
 
 // QRCodeGeneratorHandler generates a QR code from text.
-func QRCodeGeneratorHandler(args map[string]interface{}) (interface{}, error) {
+func QRCodeGeneratorHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
 	text, ok := args["text"].(string)
 	if !ok || text == "" {
 		return formatErrorResponse(
@@ -1565,15 +1805,15 @@ func QRCodeGeneratorHandler(args map[string]interface{}) (interface{}, error) {
 		), nil
 	}
 
-	// Save to file
-	homeDir, _ := os.UserHomeDir()
-	qrDir := filepath.Join(homeDir, ".celeste", "qr_codes")
+	// Save to file, under a name no other run has taken this second
+	qrDir := filepath.Join(workspaceDir(configLoader), "qr_codes")
 	os.MkdirAll(qrDir, 0755)
 
-	filename := fmt.Sprintf("qr_%d.png", time.Now().Unix())
-	filepath := filepath.Join(qrDir, filename)
-
-	if err := os.WriteFile(filepath, pngData, 0644); err != nil {
+	filepath, err := reserveFile(qrDir, fmt.Sprintf("qr_%d", time.Now().Unix()), ".png")
+	if err == nil {
+		err = filelock.WriteFile(filepath, pngData, 0644)
+	}
+	if err != nil {
 		return formatErrorResponse(
 			"internal_error",
 			"Failed to save QR code file",
@@ -1595,7 +1835,7 @@ func QRCodeGeneratorHandler(args map[string]interface{}) (interface{}, error) {
 }
 
 // TwitchLiveCheckHandler checks if a Twitch streamer is live.
-func TwitchLiveCheckHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+func TwitchLiveCheckHandler(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
 	// Try to get config, but don't fail if it's not configured
 	config, err := configLoader.GetTwitchConfig()
 	if err != nil {
@@ -1631,8 +1871,8 @@ func TwitchLiveCheckHandler(args map[string]interface{}, configLoader ConfigLoad
 	tokenData := fmt.Sprintf("client_id=%s&client_secret=%s&grant_type=client_credentials",
 		config.ClientID, config.ClientSecret)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	tokenReq, err := http.NewRequest("POST", tokenURL, strings.NewReader(tokenData))
+	client := httpclient.New(0)
+	tokenReq, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(tokenData))
 	if err != nil {
 		return formatErrorResponse(
 			"internal_error",
@@ -1644,10 +1884,14 @@ func TwitchLiveCheckHandler(args map[string]interface{}, configLoader ConfigLoad
 			},
 		), nil
 	}
+	tokenReq.Header.Set("User-Agent", version.UserAgent())
 	tokenReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	tokenResp, err := client.Do(tokenReq)
 	if err != nil {
+		if cancelled := contextErrorResponse(ctx, "check_twitch_live"); cancelled != nil {
+			return cancelled, nil
+		}
 		return formatErrorResponse(
 			"network_error",
 			"Failed to get Twitch OAuth token",
@@ -1695,7 +1939,7 @@ func TwitchLiveCheckHandler(args map[string]interface{}, configLoader ConfigLoad
 	// Step 2: Use OAuth token to check if streamer is live
 	url := fmt.Sprintf("https://api.twitch.tv/helix/streams?user_login=%s", streamer)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return formatErrorResponse(
 			"internal_error",
@@ -1710,9 +1954,13 @@ func TwitchLiveCheckHandler(args map[string]interface{}, configLoader ConfigLoad
 
 	req.Header.Set("Client-ID", config.ClientID)
 	req.Header.Set("Authorization", "Bearer "+tokenResult.AccessToken)
+	req.Header.Set("User-Agent", version.UserAgent())
 
 	resp, err := client.Do(req)
 	if err != nil {
+		if cancelled := contextErrorResponse(ctx, "check_twitch_live"); cancelled != nil {
+			return cancelled, nil
+		}
 		return formatErrorResponse(
 			"network_error",
 			"Failed to connect to Twitch API",
@@ -1790,7 +2038,7 @@ func TwitchLiveCheckHandler(args map[string]interface{}, configLoader ConfigLoad
 }
 
 // YouTubeVideosHandler gets recent videos from a YouTube channel.
-func YouTubeVideosHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+func YouTubeVideosHandler(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
 	// Try to get config, but don't fail if it's not configured
 	config, err := configLoader.GetYouTubeConfig()
 	if err != nil {
@@ -1832,112 +2080,22 @@ func YouTubeVideosHandler(args map[string]interface{}, configLoader ConfigLoader
 		}
 	}
 
-	// First, try to get channel ID if channel is a username
-	// YouTube Data API v3 requires channel ID for search
-	// We'll try to search by username first, then use channel ID
-	channelID := channel
-
-	// If it doesn't look like a channel ID (starts with UC), try to resolve it
-	if !strings.HasPrefix(channel, "UC") && len(channel) != 24 {
-		// Try to get channel ID from username
-		searchURL := fmt.Sprintf("https://www.googleapis.com/youtube/v3/search?part=snippet&q=%s&type=channel&maxResults=1&key=%s", channel, config.APIKey)
-
-		client := &http.Client{Timeout: 10 * time.Second}
-		resp, err := client.Get(searchURL)
-		if err == nil && resp.StatusCode == 200 {
-			var searchResult struct {
-				Items []struct {
-					ID struct {
-						ChannelID string `json:"channelId"`
-					} `json:"id"`
-				} `json:"items"`
-			}
-			if json.NewDecoder(resp.Body).Decode(&searchResult) == nil && len(searchResult.Items) > 0 {
-				channelID = searchResult.Items[0].ID.ChannelID
-			}
-			resp.Body.Close()
-		}
-	}
-
-	// Get recent videos
-	url := fmt.Sprintf("https://www.googleapis.com/youtube/v3/search?part=snippet&channelId=%s&order=date&type=video&maxResults=%d&key=%s", channelID, maxResults, config.APIKey)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	// Resolve the channel as cheaply as possible (cache, @handle, then search),
+	// then read its uploads playlist rather than running another search
+	resolved, resolution, err := resolveYouTubeChannel(ctx, channel, config.APIKey)
 	if err != nil {
-		return formatErrorResponse(
-			"network_error",
-			"Failed to connect to YouTube API",
-			"Please check your internet connection and try again.",
-			map[string]interface{}{
-				"skill": "get_youtube_videos",
-				"error": err.Error(),
-			},
-		), nil
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return formatErrorResponse(
-			"api_error",
-			fmt.Sprintf("YouTube API returned error (status %d)", resp.StatusCode),
-			"The YouTube API may be temporarily unavailable or the channel may not exist.",
-			map[string]interface{}{
-				"skill":       "get_youtube_videos",
-				"status_code": resp.StatusCode,
-				"response":    string(body),
-			},
-		), nil
-	}
-
-	var result struct {
-		Items []struct {
-			ID struct {
-				VideoID string `json:"videoId"`
-			} `json:"id"`
-			Snippet struct {
-				Title       string    `json:"title"`
-				Description string    `json:"description"`
-				PublishedAt time.Time `json:"publishedAt"`
-				Thumbnails  struct {
-					Default struct {
-						URL string `json:"url"`
-					} `json:"default"`
-				} `json:"thumbnails"`
-				ChannelTitle string `json:"channelTitle"`
-			} `json:"snippet"`
-		} `json:"items"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return formatErrorResponse(
-			"api_error",
-			"Failed to parse YouTube API response",
-			"The YouTube API returned invalid data. Please try again.",
-			map[string]interface{}{
-				"skill": "get_youtube_videos",
-				"error": err.Error(),
-			},
-		), nil
+		return youtubeErrorResponse(ctx, channel, err), nil
 	}
 
-	videos := make([]map[string]interface{}, 0, len(result.Items))
-	for _, item := range result.Items {
-		videos = append(videos, map[string]interface{}{
-			"video_id":      item.ID.VideoID,
-			"title":         item.Snippet.Title,
-			"description":   item.Snippet.Description,
-			"published_at":  item.Snippet.PublishedAt.Format(time.RFC3339),
-			"thumbnail_url": item.Snippet.Thumbnails.Default.URL,
-			"channel_title": item.Snippet.ChannelTitle,
-			"url":           fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.ID.VideoID),
-		})
+	videos, err := fetchYouTubeUploads(ctx, resolved.UploadsPlaylistID, config.APIKey, maxResults)
+	if err != nil {
+		return youtubeErrorResponse(ctx, channel, err), nil
 	}
 
 	return map[string]interface{}{
 		"channel":    channel,
-		"channel_id": channelID,
+		"channel_id": resolved.ChannelID,
+		"resolution": resolution,
 		"count":      len(videos),
 		"videos":     videos,
 	}, nil
@@ -1959,20 +2117,29 @@ type Note struct {
 	Updated time.Time `json:"updated"`
 }
 
+// workspaceDir returns the skill data directory for the active profile,
+// falling back to the data directory when no workspace is configured.
+func workspaceDir(configLoader ConfigLoader) string {
+	if configLoader != nil {
+		if ws, err := configLoader.GetWorkspaceConfig(); err == nil && ws.Dir != "" {
+			return ws.Dir
+		}
+	}
+	return paths.DataDir()
+}
+
 // getRemindersPath returns the path to reminders.json.
-func getRemindersPath() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".celeste", "reminders.json")
+func getRemindersPath(configLoader ConfigLoader) string {
+	return filepath.Join(workspaceDir(configLoader), "reminders.json")
 }
 
 // getNotesPath returns the path to notes.json.
-func getNotesPath() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".celeste", "notes.json")
+func getNotesPath(configLoader ConfigLoader) string {
+	return filepath.Join(workspaceDir(configLoader), "notes.json")
 }
 
 // SetReminderHandler sets a reminder.
-func SetReminderHandler(args map[string]interface{}) (interface{}, error) {
+func SetReminderHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
 	message, ok := args["message"].(string)
 	if !ok || message == "" {
 		return formatErrorResponse(
@@ -2056,47 +2223,22 @@ func SetReminderHandler(args map[string]interface{}) (interface{}, error) {
 		), nil
 	}
 
-	// Load existing reminders
-	remindersPath := getRemindersPath()
-	var reminders []Reminder
-	if data, err := os.ReadFile(remindersPath); err == nil {
-		// Ignore unmarshal error - if file is corrupt, start with empty list
-		_ = json.Unmarshal(data, &reminders)
-	}
-
-	// Add new reminder
+	// Add the reminder under a lock, so concurrent runs don't drop each other's
+	remindersPath := getRemindersPath(configLoader)
 	reminder := Reminder{
 		ID:      uuid.New().String(),
 		Message: message,
 		Time:    reminderTime,
 		Created: now,
 	}
-	reminders = append(reminders, reminder)
-
-	// Save reminders
-	os.MkdirAll(filepath.Dir(remindersPath), 0755)
-	data, err := json.MarshalIndent(reminders, "", "  ")
+	err = filelock.Update(remindersPath, 0644, func(data []byte) ([]byte, error) {
+		var reminders []Reminder
+		// Ignore unmarshal error - if file is corrupt, start with empty list
+		_ = json.Unmarshal(data, &reminders)
+		return json.MarshalIndent(append(reminders, reminder), "", "  ")
+	})
 	if err != nil {
-		return formatErrorResponse(
-			"internal_error",
-			"Failed to save reminder",
-			"An internal error occurred while saving the reminder. Please try again.",
-			map[string]interface{}{
-				"skill": "set_reminder",
-				"error": err.Error(),
-			},
-		), nil
-	}
-	if err := os.WriteFile(remindersPath, data, 0644); err != nil {
-		return formatErrorResponse(
-			"internal_error",
-			"Failed to save reminder file",
-			"An internal error occurred while saving the reminder. Please try again.",
-			map[string]interface{}{
-				"skill": "set_reminder",
-				"error": err.Error(),
-			},
-		), nil
+		return saveFileError("set_reminder", "reminder", err), nil
 	}
 
 	return map[string]interface{}{
@@ -2108,8 +2250,8 @@ func SetReminderHandler(args map[string]interface{}) (interface{}, error) {
 }
 
 // ListRemindersHandler lists all reminders.
-func ListRemindersHandler(args map[string]interface{}) (interface{}, error) {
-	remindersPath := getRemindersPath()
+func ListRemindersHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+	remindersPath := getRemindersPath(configLoader)
 	var reminders []Reminder
 	if data, err := os.ReadFile(remindersPath); err == nil {
 		// Ignore unmarshal error - if file is corrupt, return empty list
@@ -2136,8 +2278,52 @@ func ListRemindersHandler(args map[string]interface{}) (interface{}, error) {
 	}, nil
 }
 
+// reserveFile creates an empty dir/<base><ext>, or dir/<base>-2<ext> and so
+// on when that is taken, and returns its path.
+func reserveFile(dir, base, ext string) (string, error) {
+	for n := 1; ; n++ {
+		name := base + ext
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, n, ext)
+		}
+		path := filepath.Join(dir, name)
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			return path, f.Close()
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+	}
+}
+
+// saveFileError describes a failed save of a notes or reminders file. A file
+// another process kept locked is reported as resource_busy.
+func saveFileError(skill, item string, err error) map[string]interface{} {
+	if errors.Is(err, filelock.ErrBusy) {
+		return formatErrorResponse(
+			"resource_busy",
+			fmt.Sprintf("The %s file is busy", item),
+			"Another Celeste process is saving to the same file. Please try again in a moment.",
+			map[string]interface{}{
+				"skill": skill,
+				"error": err.Error(),
+			},
+		)
+	}
+	return formatErrorResponse(
+		"internal_error",
+		fmt.Sprintf("Failed to save %s file", item),
+		fmt.Sprintf("An internal error occurred while saving the %s. Please try again.", item),
+		map[string]interface{}{
+			"skill": skill,
+			"error": err.Error(),
+		},
+	)
+}
+
 // SaveNoteHandler saves a note.
-func SaveNoteHandler(args map[string]interface{}) (interface{}, error) {
+func SaveNoteHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
 	content, ok := args["content"].(string)
 	if !ok || content == "" {
 		return formatErrorResponse(
@@ -2163,55 +2349,34 @@ func SaveNoteHandler(args map[string]interface{}) (interface{}, error) {
 		}
 	}
 
-	// Load existing notes
-	notesPath := getNotesPath()
-	var notes map[string]Note
-	if data, err := os.ReadFile(notesPath); err == nil {
+	// Save or update the note under a lock, so concurrent runs don't drop
+	// each other's notes
+	notesPath := getNotesPath(configLoader)
+	err := filelock.Update(notesPath, 0644, func(data []byte) ([]byte, error) {
+		notes := make(map[string]Note)
 		// Ignore unmarshal error - if file is corrupt, start with empty map
 		_ = json.Unmarshal(data, &notes)
-	} else {
-		notes = make(map[string]Note)
-	}
-
-	// Save or update note
-	now := time.Now()
-	if existing, exists := notes[title]; exists {
-		existing.Content = content
-		existing.Updated = now
-		notes[title] = existing
-	} else {
-		notes[title] = Note{
-			Title:   title,
-			Content: content,
-			Created: now,
-			Updated: now,
+		if notes == nil {
+			notes = make(map[string]Note)
 		}
-	}
 
-	// Save notes
-	os.MkdirAll(filepath.Dir(notesPath), 0755)
-	data, err := json.MarshalIndent(notes, "", "  ")
+		now := time.Now()
+		if existing, exists := notes[title]; exists {
+			existing.Content = content
+			existing.Updated = now
+			notes[title] = existing
+		} else {
+			notes[title] = Note{
+				Title:   title,
+				Content: content,
+				Created: now,
+				Updated: now,
+			}
+		}
+		return json.MarshalIndent(notes, "", "  ")
+	})
 	if err != nil {
-		return formatErrorResponse(
-			"internal_error",
-			"Failed to save note",
-			"An internal error occurred while saving the note. Please try again.",
-			map[string]interface{}{
-				"skill": "save_note",
-				"error": err.Error(),
-			},
-		), nil
-	}
-	if err := os.WriteFile(notesPath, data, 0644); err != nil {
-		return formatErrorResponse(
-			"internal_error",
-			"Failed to save note file",
-			"An internal error occurred while saving the note. Please try again.",
-			map[string]interface{}{
-				"skill": "save_note",
-				"error": err.Error(),
-			},
-		), nil
+		return saveFileError("save_note", "note", err), nil
 	}
 
 	return map[string]interface{}{
@@ -2221,7 +2386,7 @@ func SaveNoteHandler(args map[string]interface{}) (interface{}, error) {
 }
 
 // GetNoteHandler retrieves a note.
-func GetNoteHandler(args map[string]interface{}) (interface{}, error) {
+func GetNoteHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
 	title, ok := args["title"].(string)
 	if !ok || title == "" {
 		return formatErrorResponse(
@@ -2236,7 +2401,7 @@ func GetNoteHandler(args map[string]interface{}) (interface{}, error) {
 	}
 
 	// Load notes
-	notesPath := getNotesPath()
+	notesPath := getNotesPath(configLoader)
 	var notes map[string]Note
 	if data, err := os.ReadFile(notesPath); err != nil {
 		return formatErrorResponse(
@@ -2284,8 +2449,8 @@ func GetNoteHandler(args map[string]interface{}) (interface{}, error) {
 }
 
 // ListNotesHandler lists all notes.
-func ListNotesHandler(args map[string]interface{}) (interface{}, error) {
-	notesPath := getNotesPath()
+func ListNotesHandler(args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+	notesPath := getNotesPath(configLoader)
 	var notes map[string]Note
 	if data, err := os.ReadFile(notesPath); err == nil {
 		// Ignore unmarshal error - if file is corrupt, return empty map
@@ -2309,14 +2474,10 @@ func ListNotesHandler(args map[string]interface{}) (interface{}, error) {
 	}, nil
 }
 
-// CreateDefaultSkillFiles creates default skill JSON files in ~/.celeste/skills/
+// CreateDefaultSkillFiles creates default skill JSON files in the config
+// directory's skills/.
 func CreateDefaultSkillFiles() error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-
-	skillsDir := filepath.Join(homeDir, ".celeste", "skills")
+	skillsDir := paths.Config("skills")
 	if err := os.MkdirAll(skillsDir, 0755); err != nil {
 		return err
 	}
@@ -10,6 +10,9 @@ import (
 	"math/big"
 	"net/http"
 	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/version"
 )
 
 // AlchemySkill returns the Alchemy skill definition
@@ -106,9 +109,7 @@ func AlchemyHandler(args map[string]interface{}, configLoader ConfigLoader) (int
 	}
 
 	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Duration(config.TimeoutSeconds) * time.Second,
-	}
+	client := httpclient.New(time.Duration(config.TimeoutSeconds) * time.Second)
 
 	// Route to appropriate handler
 	ctx := context.Background()
@@ -169,6 +170,7 @@ func alchemyRequest(ctx context.Context, client *http.Client, config AlchemyConf
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent())
 
 	// Execute request
 	resp, err := client.Do(req)
@@ -7,6 +7,8 @@ import (
 	"math/big"
 	"net/http"
 	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
 )
 
 // BlockmonSkill returns the blockchain monitoring skill definition
@@ -94,9 +96,7 @@ func BlockmonHandler(args map[string]interface{}, configLoader ConfigLoader) (in
 	}
 
 	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Duration(config.PollIntervalSeconds) * time.Second,
-	}
+	client := httpclient.New(time.Duration(config.PollIntervalSeconds) * time.Second)
 
 	// Build Alchemy config for reusing Alchemy functions
 	alchemyConfig := AlchemyConfig{
@@ -23,6 +23,7 @@ func IPFSSkill() Skill {
 	return Skill{
 		Name:        "ipfs",
 		Description: "IPFS decentralized storage operations: upload content/files, download by CID, manage pins. Supports string content and binary files. Works with Infura, Pinata, and custom IPFS nodes.",
+		SideEffect:  true,
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -0,0 +1,198 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// coingeckoAPIBaseURL is the CoinGecko public API. Overridden in tests.
+var coingeckoAPIBaseURL = "https://api.coingecko.com/api/v3"
+
+// Cache lifetimes for CoinGecko responses. The free tier allows only a few
+// calls a minute, so prices are reused briefly and symbol lookups for a day.
+const (
+	priceCacheTTL  = time.Minute
+	symbolCacheTTL = 24 * time.Hour
+)
+
+// coingeckoCoins maps common tickers to CoinGecko coins, skipping the
+// search for them (and the ambiguity of tickers several coins share).
+var coingeckoCoins = map[string]coinGeckoCoin{
+	"BTC":   {ID: "bitcoin", Name: "Bitcoin"},
+	"ETH":   {ID: "ethereum", Name: "Ethereum"},
+	"SOL":   {ID: "solana", Name: "Solana"},
+	"USDT":  {ID: "tether", Name: "Tether"},
+	"USDC":  {ID: "usd-coin", Name: "USDC"},
+	"BNB":   {ID: "binancecoin", Name: "BNB"},
+	"XRP":   {ID: "ripple", Name: "XRP"},
+	"ADA":   {ID: "cardano", Name: "Cardano"},
+	"DOGE":  {ID: "dogecoin", Name: "Dogecoin"},
+	"AVAX":  {ID: "avalanche-2", Name: "Avalanche"},
+	"DOT":   {ID: "polkadot", Name: "Polkadot"},
+	"MATIC": {ID: "matic-network", Name: "Polygon"},
+	"POL":   {ID: "polygon-ecosystem-token", Name: "POL (ex-MATIC)"},
+	"LTC":   {ID: "litecoin", Name: "Litecoin"},
+	"LINK":  {ID: "chainlink", Name: "Chainlink"},
+	"ARB":   {ID: "arbitrum", Name: "Arbitrum"},
+	"OP":    {ID: "optimism", Name: "Optimism"},
+}
+
+// GetPriceSkill returns the crypto price quote skill definition.
+func GetPriceSkill() Skill {
+	return Skill{
+		Name:        "get_price",
+		Description: "Get the current price, 24h change and market cap of a cryptocurrency by ticker symbol (e.g. BTC, ETH, SOL) from CoinGecko",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol": map[string]interface{}{
+					"type":        "string",
+					"description": "Ticker symbol (e.g. BTC) or CoinGecko coin ID (e.g. bitcoin)",
+				},
+				"vs_currency": map[string]interface{}{
+					"type":        "string",
+					"description": "Currency to quote in (e.g. usd, eur, jpy, btc). Default: usd",
+				},
+			},
+			"required": []string{"symbol"},
+		},
+	}
+}
+
+// GetPriceHandler quotes a cryptocurrency's price from CoinGecko.
+func GetPriceHandler(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	symbol, _ := args["symbol"].(string)
+	symbol = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(symbol), "$"))
+	if symbol == "" {
+		return formatErrorResponse(
+			"validation_error",
+			"The 'symbol' parameter is required",
+			"Please provide a ticker symbol such as BTC, ETH or SOL.",
+			map[string]interface{}{
+				"skill": "get_price",
+				"field": "symbol",
+			},
+		), nil
+	}
+
+	vsCurrency := "usd"
+	if vs, ok := args["vs_currency"].(string); ok && strings.TrimSpace(vs) != "" {
+		vsCurrency = strings.ToLower(strings.TrimSpace(vs))
+	}
+
+	coin, err := resolveCoinGeckoCoin(ctx, symbol)
+	if err != nil {
+		return httpGetErrorResponse(ctx, err, "get_price", "CoinGecko API"), nil
+	}
+	if coin.ID == "" {
+		return formatErrorResponse(
+			"not_found",
+			fmt.Sprintf("No cryptocurrency found for symbol '%s'", symbol),
+			"Check the ticker, or pass the CoinGecko coin ID (e.g. 'bitcoin').",
+			map[string]interface{}{
+				"skill":    "get_price",
+				"field":    "symbol",
+				"provided": symbol,
+			},
+		), nil
+	}
+
+	params := url.Values{}
+	params.Set("ids", coin.ID)
+	params.Set("vs_currencies", vsCurrency)
+	params.Set("include_24hr_change", "true")
+	params.Set("include_market_cap", "true")
+	params.Set("include_last_updated_at", "true")
+
+	var prices map[string]map[string]float64
+	if err := CachedGetJSON(ctx, coingeckoAPIBaseURL+"/simple/price?"+params.Encode(), priceCacheTTL, &prices); err != nil {
+		return httpGetErrorResponse(ctx, err, "get_price", "CoinGecko API"), nil
+	}
+
+	quote, known := prices[coin.ID]
+	price, ok := quote[vsCurrency]
+	if !ok {
+		// CoinGecko answers {} for an unknown coin and {"<id>": {}} for an
+		// unknown quote currency
+		if !known {
+			return formatErrorResponse(
+				"not_found",
+				fmt.Sprintf("CoinGecko has no price for '%s'", coin.ID),
+				"Check the ticker, or pass the CoinGecko coin ID (e.g. 'bitcoin').",
+				map[string]interface{}{
+					"skill":    "get_price",
+					"provided": symbol,
+				},
+			), nil
+		}
+		return formatErrorResponse(
+			"validation_error",
+			fmt.Sprintf("Unsupported quote currency '%s'", vsCurrency),
+			"Use a currency code CoinGecko supports, such as usd, eur, jpy, gbp or btc.",
+			map[string]interface{}{
+				"skill":    "get_price",
+				"field":    "vs_currency",
+				"provided": vsCurrency,
+			},
+		), nil
+	}
+
+	result := map[string]interface{}{
+		"symbol":         strings.ToUpper(coin.Symbol),
+		"name":           coin.Name,
+		"coin_id":        coin.ID,
+		"vs_currency":    vsCurrency,
+		"price":          price,
+		"change_24h_pct": quote[vsCurrency+"_24h_change"],
+		"market_cap":     quote[vsCurrency+"_market_cap"],
+		"source":         "CoinGecko",
+	}
+	if updated, ok := quote["last_updated_at"]; ok && updated > 0 {
+		result["last_updated"] = time.Unix(int64(updated), 0).UTC().Format(time.RFC3339)
+	}
+	return result, nil
+}
+
+// coinGeckoCoin identifies a coin on CoinGecko.
+type coinGeckoCoin struct {
+	ID            string `json:"id"`
+	Symbol        string `json:"symbol"`
+	Name          string `json:"name"`
+	MarketCapRank int    `json:"market_cap_rank"`
+}
+
+// resolveCoinGeckoCoin finds the coin for a ticker or coin ID. Tickers shared
+// by several coins resolve to the one with the best market cap rank. A
+// zero coin means nothing matched.
+func resolveCoinGeckoCoin(ctx context.Context, symbol string) (coinGeckoCoin, error) {
+	upper := strings.ToUpper(symbol)
+	if coin, ok := coingeckoCoins[upper]; ok {
+		coin.Symbol = upper
+		return coin, nil
+	}
+
+	var search struct {
+		Coins []coinGeckoCoin `json:"coins"`
+	}
+	endpoint := coingeckoAPIBaseURL + "/search?query=" + url.QueryEscape(strings.ToLower(symbol))
+	if err := CachedGetJSON(ctx, endpoint, symbolCacheTTL, &search); err != nil {
+		return coinGeckoCoin{}, err
+	}
+
+	var best coinGeckoCoin
+	for _, coin := range search.Coins {
+		if strings.EqualFold(coin.ID, symbol) {
+			return coin, nil
+		}
+		if !strings.EqualFold(coin.Symbol, symbol) {
+			continue
+		}
+		if best.ID == "" || (coin.MarketCapRank > 0 && (best.MarketCapRank == 0 || coin.MarketCapRank < best.MarketCapRank)) {
+			best = coin
+		}
+	}
+	return best, nil
+}
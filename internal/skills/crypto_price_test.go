@@ -0,0 +1,93 @@
+package skills
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCoinGecko serves /search and /simple/price, counting price requests.
+func stubCoinGecko(t *testing.T) *int {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	priceRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			_, _ = w.Write([]byte(`{"coins":[
+				{"id":"pepe-fork","symbol":"PEPE","name":"Pepe Fork","market_cap_rank":0},
+				{"id":"pepe","symbol":"PEPE","name":"Pepe","market_cap_rank":40},
+				{"id":"pepecoin","symbol":"PEPECOIN","name":"PepeCoin","market_cap_rank":900}]}`))
+		case "/simple/price":
+			priceRequests++
+			switch {
+			case r.URL.Query().Get("ids") == "nothing":
+				_, _ = w.Write([]byte(`{}`))
+			case r.URL.Query().Get("vs_currencies") != "usd" && r.URL.Query().Get("vs_currencies") != "eur":
+				_, _ = w.Write([]byte(`{"` + r.URL.Query().Get("ids") + `":{}}`))
+			default:
+				vs := r.URL.Query().Get("vs_currencies")
+				_, _ = w.Write([]byte(`{"` + r.URL.Query().Get("ids") + `":{"` + vs + `":0.0000123,"` + vs + `_market_cap":5170000000,"` + vs + `_24h_change":-3.5,"last_updated_at":1748880000}}`))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	original := coingeckoAPIBaseURL
+	coingeckoAPIBaseURL = server.URL
+	t.Cleanup(func() { coingeckoAPIBaseURL = original })
+	return &priceRequests
+}
+
+func TestGetPriceHandler(t *testing.T) {
+	priceRequests := stubCoinGecko(t)
+
+	result, err := GetPriceHandler(context.Background(), map[string]interface{}{"symbol": "$pepe", "vs_currency": "EUR"})
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, "PEPE", data["symbol"])
+	assert.Equal(t, "pepe", data["coin_id"], "shared tickers resolve to the best ranked coin")
+	assert.Equal(t, "eur", data["vs_currency"])
+	assert.Equal(t, 0.0000123, data["price"])
+	assert.Equal(t, -3.5, data["change_24h_pct"])
+	assert.Equal(t, float64(5170000000), data["market_cap"])
+	assert.Equal(t, "2025-06-02T16:00:00Z", data["last_updated"])
+
+	// Quotes are cached briefly
+	_, err = GetPriceHandler(context.Background(), map[string]interface{}{"symbol": "PEPE", "vs_currency": "eur"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, *priceRequests)
+
+	// Common tickers skip the search
+	result, err = GetPriceHandler(context.Background(), map[string]interface{}{"symbol": "btc"})
+	require.NoError(t, err)
+	assert.Equal(t, "bitcoin", result.(map[string]interface{})["coin_id"])
+	assert.Equal(t, "Bitcoin", result.(map[string]interface{})["name"])
+}
+
+func TestGetPriceHandlerErrors(t *testing.T) {
+	stubCoinGecko(t)
+
+	result, err := GetPriceHandler(context.Background(), map[string]interface{}{"symbol": "NOPE"})
+	require.NoError(t, err)
+	assert.Equal(t, "not_found", result.(map[string]interface{})["error_type"])
+
+	result, err = GetPriceHandler(context.Background(), map[string]interface{}{"symbol": "nothing"})
+	require.NoError(t, err)
+	assert.Equal(t, "not_found", result.(map[string]interface{})["error_type"])
+
+	result, err = GetPriceHandler(context.Background(), map[string]interface{}{"symbol": "BTC", "vs_currency": "doubloons"})
+	require.NoError(t, err)
+	assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+	assert.Equal(t, "vs_currency", result.(map[string]interface{})["field"])
+
+	result, err = GetPriceHandler(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+}
@@ -15,6 +15,9 @@ import (
 	"sort"
 	"strconv"
 	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
 )
 
 // WalletSecuritySkill returns the wallet security monitoring skill definition
@@ -22,6 +25,7 @@ func WalletSecuritySkill() Skill {
 	return Skill{
 		Name:        "wallet_security",
 		Description: "Monitor wallet addresses for security threats: dust attacks, NFT scams, dangerous approvals, large transfers across Ethereum and L2s",
+		SideEffect:  true,
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -118,13 +122,11 @@ type AssetTransfer struct {
 
 // Storage path helpers
 func getWalletSecurityPath() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".celeste", "wallet_security.json")
+	return paths.Data("wallet_security.json")
 }
 
 func getWalletAlertsPath() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".celeste", "wallet_alerts.json")
+	return paths.Data("wallet_alerts.json")
 }
 
 // WalletSecurityHandler handles wallet security skill execution
@@ -425,7 +427,7 @@ func handleCheckWalletSecurity(ctx context.Context, configLoader ConfigLoader) (
 	}
 
 	// Create HTTP client
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := httpclient.New(0)
 
 	// Get current block
 	blockNumResult, err := alchemyRequest(ctx, client, alchemyConfig,
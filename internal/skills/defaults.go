@@ -0,0 +1,63 @@
+// Package skills provides the skill registry and execution system.
+// This file applies per-user parameter defaults before a skill runs.
+package skills
+
+// SkillDefaults maps a skill name to parameter values used when the model
+// leaves a declared parameter out, from skill_defaults in skills.json.
+type SkillDefaults map[string]map[string]interface{}
+
+// DefaultsAppliedKey is the result field listing the defaults the executor
+// filled in, so the model can mention them.
+const DefaultsAppliedKey = "_defaults_applied"
+
+// SetDefaults replaces the parameter defaults the executor applies.
+func (r *Registry) SetDefaults(defaults SkillDefaults) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaults = defaults
+}
+
+// parameterDefault returns the configured default for one of a skill's
+// declared parameters.
+func (r *Registry) parameterDefault(name, param string) (interface{}, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	value, ok := r.defaults[name][param]
+	if !ok || value == nil {
+		return nil, false
+	}
+	properties, _ := r.skills[name].Parameters["properties"].(map[string]interface{})
+	if _, declared := properties[param]; !declared {
+		return nil, false
+	}
+	return value, true
+}
+
+// applyDefaults fills in the configured default for each declared parameter
+// args leaves out, and returns the values it filled in (nil if none).
+// Arguments the model gave always win; handlers still validate the result.
+func (r *Registry) applyDefaults(name string, args map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	params := make([]string, 0, len(r.defaults[name]))
+	for param := range r.defaults[name] {
+		params = append(params, param)
+	}
+	r.mu.RUnlock()
+
+	var applied map[string]interface{}
+	for _, param := range params {
+		if value, given := args[param]; given && value != nil {
+			continue
+		}
+		value, ok := r.parameterDefault(name, param)
+		if !ok {
+			continue
+		}
+		if applied == nil {
+			applied = make(map[string]interface{})
+		}
+		args[param] = value
+		applied[param] = value
+	}
+	return applied
+}
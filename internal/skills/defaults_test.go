@@ -0,0 +1,79 @@
+package skills
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDefaultsRegistry registers the built-in skills with skill_defaults for
+// tarot and weather, and handlers that return the arguments they got.
+func newDefaultsRegistry() *Registry {
+	loader := NewMockConfigLoader()
+	loader.SkillDefaults = SkillDefaults{
+		"tarot_reading": {"spread_type": "celtic", "deck": "thoth"},
+		"get_weather":   {"days": float64(3)},
+	}
+	registry := NewRegistry()
+	RegisterBuiltinSkills(registry, loader)
+	for _, name := range []string{"tarot_reading", "get_weather"} {
+		registry.RegisterHandler(name, func(args map[string]interface{}) (interface{}, error) {
+			got := make(map[string]interface{}, len(args))
+			for k, v := range args {
+				got[k] = v
+			}
+			return map[string]interface{}{"args": got}, nil
+		})
+	}
+	return registry
+}
+
+// TestSkillDefaultsApplied tests that absent parameters get their
+// configured default and the result says so
+func TestSkillDefaultsApplied(t *testing.T) {
+	executor := NewExecutor(newDefaultsRegistry())
+
+	result, err := executor.Execute(context.Background(), "tarot_reading", `{"question":"Will the stream go well?"}`)
+	require.NoError(t, err)
+	output := result.Result.(map[string]interface{})
+	args := output["args"].(map[string]interface{})
+	assert.Equal(t, "celtic", args["spread_type"])
+	assert.NotContains(t, args, "deck", "tarot_reading declares no deck parameter")
+	assert.Equal(t, map[string]interface{}{"spread_type": "celtic"}, output[DefaultsAppliedKey])
+	assert.Equal(t, map[string]interface{}{"spread_type": "celtic"}, result.DefaultsApplied)
+
+	result, err = executor.Execute(context.Background(), "get_weather", "")
+	require.NoError(t, err)
+	output = result.Result.(map[string]interface{})
+	assert.Equal(t, float64(3), output["args"].(map[string]interface{})["days"])
+	assert.Equal(t, map[string]interface{}{"days": float64(3)}, output[DefaultsAppliedKey])
+}
+
+// TestSkillDefaultsExplicitWins tests that arguments from the model are
+// never replaced
+func TestSkillDefaultsExplicitWins(t *testing.T) {
+	registry := newDefaultsRegistry()
+	executor := NewExecutor(registry)
+
+	result, err := executor.Execute(context.Background(), "tarot_reading", `{"spread_type":"three"}`)
+	require.NoError(t, err)
+	output := result.Result.(map[string]interface{})
+	assert.Equal(t, "three", output["args"].(map[string]interface{})["spread_type"])
+	assert.NotContains(t, output, DefaultsAppliedKey)
+	assert.Nil(t, result.DefaultsApplied)
+
+	result, err = executor.Execute(context.Background(), "get_weather", `{"days":1}`)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), result.Result.(map[string]interface{})["args"].(map[string]interface{})["days"])
+
+	desc, err := registry.DescribeSkill("get_weather", NewMockConfigLoader())
+	require.NoError(t, err)
+	for _, param := range desc.Parameters {
+		if param.Name == "days" {
+			assert.Equal(t, float64(3), param.Default)
+			assert.Equal(t, "skill_defaults", param.DefaultSource)
+		}
+	}
+}
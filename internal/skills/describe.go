@@ -0,0 +1,244 @@
+package skills
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SkillDescription is everything needed to call a skill by hand, as shown
+// by `celeste skill --describe`.
+type SkillDescription struct {
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	HasHandler    bool                   `json:"has_handler"`
+	Parameters    []ParameterDescription `json:"parameters"`
+	Prerequisites []Prerequisite         `json:"prerequisites,omitempty"`
+	Example       string                 `json:"example"`
+	Schema        map[string]interface{} `json:"schema"`
+}
+
+// ParameterDescription describes one skill parameter.
+type ParameterDescription struct {
+	Name          string        `json:"name"`
+	Type          string        `json:"type"`
+	Description   string        `json:"description,omitempty"`
+	Required      bool          `json:"required"`
+	Enum          []interface{} `json:"enum,omitempty"`
+	Default       interface{}   `json:"default,omitempty"`
+	DefaultSource string        `json:"default_source,omitempty"` // "schema", "skills.json" or "skill_defaults"
+}
+
+// Prerequisite is configuration a skill needs before it can run.
+type Prerequisite struct {
+	Description   string `json:"description"`
+	ConfigCommand string `json:"config_command,omitempty"`
+	Satisfied     bool   `json:"satisfied"`
+}
+
+// skillPrerequisite pairs a Prerequisite with how to check it.
+type skillPrerequisite struct {
+	description   string
+	configCommand string
+	satisfied     func(ConfigLoader) bool
+}
+
+// skillPrerequisites lists the configuration each built-in skill needs.
+var skillPrerequisites = map[string][]skillPrerequisite{
+	"tarot_reading": {{
+		description:   "Tarot function auth token",
+		configCommand: "celeste config --set-tarot-token <token>",
+		satisfied: func(c ConfigLoader) bool {
+			cfg, err := c.GetTarotConfig()
+			return err == nil && cfg.AuthToken != ""
+		},
+	}},
+	"check_twitch_live": {{
+		description:   "Twitch client ID and client secret",
+		configCommand: "celeste config --set-twitch-client-id <id> (twitch_client_secret in skills.json)",
+		satisfied: func(c ConfigLoader) bool {
+			cfg, err := c.GetTwitchConfig()
+			return err == nil && cfg.ClientID != "" && cfg.ClientSecret != ""
+		},
+	}},
+	"get_youtube_videos": {{
+		description:   "YouTube Data API key",
+		configCommand: "celeste config --set-youtube-key <key>",
+		satisfied: func(c ConfigLoader) bool {
+			cfg, err := c.GetYouTubeConfig()
+			return err == nil && cfg.APIKey != ""
+		},
+	}},
+	"post_discord": {{
+		description:   "Discord webhook URL",
+		configCommand: "celeste config --set-discord-webhook <url>",
+		satisfied: func(c ConfigLoader) bool {
+			cfg, err := c.GetDiscordConfig()
+			return err == nil && cfg.WebhookURL != ""
+		},
+	}},
+	"post_tweet": {{
+		description:   "X (Twitter) API key, secret, access token and access token secret",
+		configCommand: "twitter_api_key, twitter_api_secret, twitter_access_token and twitter_access_token_secret in skills.json",
+		satisfied: func(c ConfigLoader) bool {
+			_, err := c.GetTwitterConfig()
+			return err == nil
+		},
+	}},
+	"post_mastodon": {{
+		description:   "Mastodon instance and access token",
+		configCommand: "celeste config --set-mastodon-instance <url> --set-mastodon-token <token>",
+		satisfied: func(c ConfigLoader) bool {
+			cfg, err := c.GetMastodonConfig()
+			return err == nil && cfg.InstanceURL != "" && cfg.AccessToken != ""
+		},
+	}},
+	"alchemy": {{
+		description:   "Alchemy API key",
+		configCommand: "CELESTE_ALCHEMY_API_KEY=<key> or alchemy_api_key in skills.json",
+		satisfied: func(c ConfigLoader) bool {
+			cfg, err := c.GetAlchemyConfig()
+			return err == nil && cfg.APIKey != ""
+		},
+	}},
+}
+
+// skillConfigDefaults lists parameters that fall back to a skills.json
+// value when the caller leaves them out.
+var skillConfigDefaults = map[string]map[string]func(ConfigLoader) string{
+	"get_weather": {
+		"zip_code": func(c ConfigLoader) string {
+			cfg, _ := c.GetWeatherConfig()
+			return cfg.DefaultZipCode
+		},
+		"units": func(c ConfigLoader) string {
+			cfg, _ := c.GetWeatherConfig()
+			return cfg.Units
+		},
+	},
+	"check_twitch_live": {"streamer": func(c ConfigLoader) string {
+		cfg, _ := c.GetTwitchConfig()
+		return cfg.DefaultStreamer
+	}},
+	"get_youtube_videos": {"channel": func(c ConfigLoader) string {
+		cfg, _ := c.GetYouTubeConfig()
+		return cfg.DefaultChannel
+	}},
+	"alchemy": {"network": func(c ConfigLoader) string {
+		cfg, _ := c.GetAlchemyConfig()
+		return cfg.DefaultNetwork
+	}},
+}
+
+// DescribeSkill describes a registered skill: its parameters, defaults
+// (including those taken from skills.json), whether its configuration
+// prerequisites are met, and an example command line. configLoader may be
+// nil, in which case config defaults and prerequisites are left out.
+func (r *Registry) DescribeSkill(name string, configLoader ConfigLoader) (SkillDescription, error) {
+	skill, exists := r.GetSkill(name)
+	if !exists {
+		return SkillDescription{}, fmt.Errorf("skill not found: %s", name)
+	}
+
+	desc := SkillDescription{
+		Name:        skill.Name,
+		Description: skill.Description,
+		HasHandler:  r.HasHandler(name),
+		Parameters:  []ParameterDescription{},
+		Schema:      skill.Parameters,
+	}
+
+	required := make(map[string]bool)
+	switch req := skill.Parameters["required"].(type) {
+	case []string:
+		for _, p := range req {
+			required[p] = true
+		}
+	case []interface{}:
+		for _, p := range req {
+			if s, ok := p.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	properties, _ := skill.Parameters["properties"].(map[string]interface{})
+	for paramName, raw := range properties {
+		prop, _ := raw.(map[string]interface{})
+		param := ParameterDescription{Name: paramName, Required: required[paramName]}
+		param.Type, _ = prop["type"].(string)
+		param.Description, _ = prop["description"].(string)
+		param.Enum = enumValues(prop["enum"])
+		if def, ok := prop["default"]; ok {
+			param.Default, param.DefaultSource = def, "schema"
+		}
+		if lookup, ok := skillConfigDefaults[name][paramName]; ok && configLoader != nil {
+			if value := lookup(configLoader); value != "" {
+				param.Default, param.DefaultSource = value, "skills.json"
+			}
+		}
+		if value, ok := r.parameterDefault(name, paramName); ok {
+			param.Default, param.DefaultSource = value, "skill_defaults"
+		}
+		desc.Parameters = append(desc.Parameters, param)
+	}
+	// Required parameters first, then alphabetical
+	sort.Slice(desc.Parameters, func(i, j int) bool {
+		a, b := desc.Parameters[i], desc.Parameters[j]
+		if a.Required != b.Required {
+			return a.Required
+		}
+		return a.Name < b.Name
+	})
+
+	if configLoader != nil {
+		for _, p := range skillPrerequisites[name] {
+			desc.Prerequisites = append(desc.Prerequisites, Prerequisite{
+				Description:   p.description,
+				ConfigCommand: p.configCommand,
+				Satisfied:     p.satisfied(configLoader),
+			})
+		}
+	}
+
+	desc.Example = exampleCommand(name, desc.Parameters)
+	return desc, nil
+}
+
+// enumValues normalizes a schema enum, which built-in skills declare as
+// []string and JSON skill files as []interface{}.
+func enumValues(raw interface{}) []interface{} {
+	switch values := raw.(type) {
+	case []string:
+		out := make([]interface{}, len(values))
+		for i, v := range values {
+			out[i] = v
+		}
+		return out
+	case []interface{}:
+		return values
+	}
+	return nil
+}
+
+// exampleCommand builds a `celeste skill` command line passing every
+// required parameter a placeholder value.
+func exampleCommand(name string, params []ParameterDescription) string {
+	parts := []string{"celeste", "skill", name}
+	for _, p := range params {
+		if !p.Required {
+			continue
+		}
+		switch {
+		case len(p.Enum) > 0:
+			parts = append(parts, "--"+p.Name, fmt.Sprint(p.Enum[0]))
+		case p.Type == "boolean":
+			parts = append(parts, "--"+p.Name)
+		case p.Type == "number" || p.Type == "integer":
+			parts = append(parts, "--"+p.Name, "1")
+		default:
+			parts = append(parts, "--"+p.Name, "<"+p.Name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
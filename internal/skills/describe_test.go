@@ -0,0 +1,87 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeSkillWeather(t *testing.T) {
+	registry := NewRegistry()
+	RegisterBuiltinSkills(registry, NewMockConfigLoader())
+
+	desc, err := registry.DescribeSkill("get_weather", NewMockConfigLoader())
+	require.NoError(t, err)
+	assert.True(t, desc.HasHandler)
+	assert.Empty(t, desc.Prerequisites)
+	assert.Equal(t, "celeste skill get_weather", desc.Example)
+
+	// No required parameters, so alphabetical
+	require.Len(t, desc.Parameters, 7)
+	names := make([]string, len(desc.Parameters))
+	for i, p := range desc.Parameters {
+		names[i] = p.Name
+	}
+	assert.Equal(t, []string{"city", "days", "lat", "lon", "raw", "units", "zip_code"}, names)
+	assert.Equal(t, "integer", desc.Parameters[1].Type)
+	assert.Nil(t, desc.Parameters[5].Default, "auto units have no single default")
+	zip := desc.Parameters[6]
+	assert.Equal(t, "zip_code", zip.Name)
+	assert.False(t, zip.Required)
+	assert.Equal(t, "10001", zip.Default)
+	assert.Equal(t, "skills.json", zip.DefaultSource)
+}
+
+func TestDescribeSkillTarot(t *testing.T) {
+	registry := NewRegistry()
+	RegisterBuiltinSkills(registry, NewMockConfigLoader())
+
+	desc, err := registry.DescribeSkill("tarot_reading", NewMockConfigLoader())
+	require.NoError(t, err)
+	require.Len(t, desc.Parameters, 3)
+	spread := desc.Parameters[0]
+	assert.Equal(t, "spread_type", spread.Name, "required parameters come first")
+	assert.True(t, spread.Required)
+	assert.Equal(t, []interface{}{"three", "single", "horseshoe", "celtic"}, spread.Enum)
+	assert.Equal(t, "celeste skill tarot_reading --spread_type three", desc.Example)
+
+	require.Len(t, desc.Prerequisites, 1)
+	assert.True(t, desc.Prerequisites[0].Satisfied)
+
+	desc, err = registry.DescribeSkill("tarot_reading", NewMockConfigLoaderWithErrors())
+	require.NoError(t, err)
+	assert.False(t, desc.Prerequisites[0].Satisfied)
+	assert.Equal(t, "celeste config --set-tarot-token <token>", desc.Prerequisites[0].ConfigCommand)
+}
+
+func TestDescribeSkillPrerequisites(t *testing.T) {
+	registry := NewRegistry()
+	RegisterBuiltinSkills(registry, NewMockConfigLoader())
+
+	// The mock has a Twitch client ID but no secret
+	desc, err := registry.DescribeSkill("check_twitch_live", NewMockConfigLoader())
+	require.NoError(t, err)
+	require.Len(t, desc.Prerequisites, 1)
+	assert.False(t, desc.Prerequisites[0].Satisfied)
+
+	// Without a config loader only the schema is described
+	desc, err = registry.DescribeSkill("check_twitch_live", nil)
+	require.NoError(t, err)
+	assert.Empty(t, desc.Prerequisites)
+	for _, p := range desc.Parameters {
+		assert.Nil(t, p.Default)
+	}
+}
+
+func TestDescribeSkillExample(t *testing.T) {
+	registry := NewRegistry()
+	RegisterBuiltinSkills(registry, NewMockConfigLoader())
+
+	desc, err := registry.DescribeSkill("convert_currency", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "celeste skill convert_currency --amount 1 --from_currency <from_currency> --to_currency <to_currency>", desc.Example)
+
+	_, err = registry.DescribeSkill("no_such_skill", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,289 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// Discord webhook limits.
+const (
+	discordMaxContent     = 2000
+	discordMaxEmbedTitle  = 256
+	discordMaxEmbedDesc   = 4096
+	discordMaxUsername    = 80
+	discordMaxRateRetries = 2
+)
+
+// discordMaxRetryAfter is the longest 429 wait post_discord will sit through
+// before giving up. Overridden in tests.
+var discordMaxRetryAfter = 10 * time.Second
+
+// discordEmbed is the subset of a Discord embed the skill can send.
+type discordEmbed struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Color       int    `json:"color,omitempty"`
+}
+
+// discordPayload is the JSON body of a webhook execute request.
+type discordPayload struct {
+	Content   string         `json:"content,omitempty"`
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []discordEmbed `json:"embeds,omitempty"`
+}
+
+// discordRateLimit is the body Discord sends with a 429.
+type discordRateLimit struct {
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"` // seconds
+	Global     bool    `json:"global"`
+}
+
+// PostDiscordSkill returns the Discord webhook skill definition.
+func PostDiscordSkill() Skill {
+	return Skill{
+		Name:        "post_discord",
+		Description: "Post a message to the user's configured Discord channel through a webhook, e.g. to announce a stream or share news with their community. Only post when the user asks for it.",
+		SideEffect:  true,
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "Message text (max 2000 characters). Required unless an embed is given.",
+				},
+				"username": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional display name to post as, overriding the webhook's name",
+				},
+				"avatar_url": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional avatar image URL to post with, overriding the webhook's avatar",
+				},
+				"embed": map[string]interface{}{
+					"type":        "object",
+					"description": "Optional rich embed shown under the message",
+					"properties": map[string]interface{}{
+						"title": map[string]interface{}{
+							"type":        "string",
+							"description": "Embed title (max 256 characters)",
+						},
+						"description": map[string]interface{}{
+							"type":        "string",
+							"description": "Embed body text (max 4096 characters)",
+						},
+						"url": map[string]interface{}{
+							"type":        "string",
+							"description": "Link opened by clicking the title",
+						},
+						"color": map[string]interface{}{
+							"type":        "integer",
+							"description": "Sidebar color as a decimal RGB value, e.g. 16711935 for magenta",
+						},
+					},
+				},
+			},
+			"required": []string{},
+		},
+	}
+}
+
+// PostDiscordHandler posts a message to the configured Discord webhook,
+// waiting out rate limits up to discordMaxRetryAfter.
+func PostDiscordHandler(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+	config, err := configLoader.GetDiscordConfig()
+	if err != nil || config.WebhookURL == "" {
+		return formatErrorResponse(
+			"config_error",
+			"Discord webhook URL is required. Please configure it using: celeste config --set-discord-webhook <url>",
+			"Create a webhook under Server Settings > Integrations > Webhooks in Discord and copy its URL.",
+			map[string]interface{}{
+				"skill":          "post_discord",
+				"config_command": "celeste config --set-discord-webhook <url>",
+			},
+		), nil
+	}
+
+	payload, problem := buildDiscordPayload(args)
+	if problem != "" {
+		return formatErrorResponse(
+			"validation_error",
+			problem,
+			"Shorten the message or provide content or an embed, then try again.",
+			map[string]interface{}{
+				"skill": "post_discord",
+			},
+		), nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Discord payload: %w", err)
+	}
+
+	resp, respBody, err := executeDiscordWebhook(ctx, config.WebhookURL, body)
+	if err != nil {
+		if cancelled := contextErrorResponse(ctx, "post_discord"); cancelled != nil {
+			return cancelled, nil
+		}
+		return formatErrorResponse(
+			"network_error",
+			"Failed to connect to Discord",
+			"Please check your internet connection and the webhook URL, then try again.",
+			map[string]interface{}{
+				"skill": "post_discord",
+				"error": err.Error(),
+			},
+		), nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := discordRetryAfter(resp, respBody)
+		return formatErrorResponse(
+			"rate_limited",
+			fmt.Sprintf("Discord is rate limiting this webhook; retry in %.1fs", retryAfter.Seconds()),
+			"Wait a little before posting again.",
+			map[string]interface{}{
+				"skill":       "post_discord",
+				"status_code": resp.StatusCode,
+				"retry_after": retryAfter.Seconds(),
+			},
+		), nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("Discord returned error (status %d)", resp.StatusCode),
+			"The webhook may have been deleted, or the message was rejected.",
+			map[string]interface{}{
+				"skill":       "post_discord",
+				"status_code": resp.StatusCode,
+				"response":    string(respBody),
+			},
+		), nil
+	}
+
+	result := map[string]interface{}{
+		"success":     true,
+		"status_code": resp.StatusCode,
+	}
+	// With ?wait=true Discord returns the created message
+	var message struct {
+		ID        string `json:"id"`
+		ChannelID string `json:"channel_id"`
+	}
+	if json.Unmarshal(respBody, &message) == nil && message.ID != "" {
+		result["message_id"] = message.ID
+		result["channel_id"] = message.ChannelID
+	}
+	return result, nil
+}
+
+// buildDiscordPayload turns skill arguments into a webhook payload, or
+// describes why they can't be posted.
+func buildDiscordPayload(args map[string]interface{}) (discordPayload, string) {
+	payload := discordPayload{}
+	payload.Content, _ = args["content"].(string)
+	payload.Username, _ = args["username"].(string)
+	payload.AvatarURL, _ = args["avatar_url"].(string)
+
+	if e, ok := args["embed"].(map[string]interface{}); ok {
+		embed := discordEmbed{}
+		embed.Title, _ = e["title"].(string)
+		embed.Description, _ = e["description"].(string)
+		embed.URL, _ = e["url"].(string)
+		if color, ok := e["color"].(float64); ok {
+			embed.Color = int(color)
+		}
+		if len([]rune(embed.Title)) > discordMaxEmbedTitle {
+			return payload, fmt.Sprintf("Embed title is longer than %d characters", discordMaxEmbedTitle)
+		}
+		if len([]rune(embed.Description)) > discordMaxEmbedDesc {
+			return payload, fmt.Sprintf("Embed description is longer than %d characters", discordMaxEmbedDesc)
+		}
+		if embed.Title != "" || embed.Description != "" {
+			payload.Embeds = []discordEmbed{embed}
+		}
+	}
+
+	if strings.TrimSpace(payload.Content) == "" && len(payload.Embeds) == 0 {
+		return payload, "Nothing to post: provide content or an embed with a title or description"
+	}
+	if len([]rune(payload.Content)) > discordMaxContent {
+		return payload, fmt.Sprintf("Message is longer than Discord's %d character limit", discordMaxContent)
+	}
+	if len([]rune(payload.Username)) > discordMaxUsername {
+		return payload, fmt.Sprintf("Username is longer than %d characters", discordMaxUsername)
+	}
+	return payload, ""
+}
+
+// executeDiscordWebhook POSTs body to the webhook. A 429 is retried after
+// the retry_after Discord asks for, as long as that wait is short; the
+// final response and its body are returned.
+func executeDiscordWebhook(ctx context.Context, webhookURL string, body []byte) (*http.Response, []byte, error) {
+	target := webhookURL
+	if strings.Contains(target, "?") {
+		target += "&wait=true"
+	} else {
+		target += "?wait=true"
+	}
+
+	client := httpclient.New(0)
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("User-Agent", version.UserAgent())
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= discordMaxRateRetries {
+			return resp, respBody, nil
+		}
+		wait := discordRetryAfter(resp, respBody)
+		if wait > discordMaxRetryAfter {
+			return resp, respBody, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// discordRetryAfter reads how long to wait after a 429, preferring the
+// JSON body's retry_after and falling back to the Retry-After header.
+func discordRetryAfter(resp *http.Response, body []byte) time.Duration {
+	var limit discordRateLimit
+	if json.Unmarshal(body, &limit) == nil && limit.RetryAfter > 0 {
+		return time.Duration(limit.RetryAfter * float64(time.Second))
+	}
+	if seconds, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return time.Second
+}
@@ -0,0 +1,121 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDiscordWebhook serves a webhook that answers 429 for the first
+// rateLimited requests, then 200 with the created message. It returns the
+// loader pointing at it and the payloads received.
+func stubDiscordWebhook(t *testing.T, rateLimited int, retryAfter string) (*MockConfigLoader, *[]discordPayload) {
+	t.Helper()
+	payloads := []discordPayload{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/webhooks/1/token", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("wait"))
+
+		var payload discordPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads = append(payloads, payload)
+
+		if len(payloads) <= rateLimited {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"You are being rate limited.","retry_after":` + retryAfter + `,"global":false}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"111","channel_id":"222","content":"` + payload.Content + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return &MockConfigLoader{DiscordCfg: DiscordConfig{WebhookURL: server.URL + "/api/webhooks/1/token"}}, &payloads
+}
+
+func TestPostDiscordHandler(t *testing.T) {
+	loader, payloads := stubDiscordWebhook(t, 0, "0")
+
+	result, err := PostDiscordHandler(context.Background(), map[string]interface{}{
+		"content":    "We're live!",
+		"username":   "Celeste",
+		"avatar_url": "https://example.com/celeste.png",
+		"embed": map[string]interface{}{
+			"title":       "Elden Ring DLC",
+			"description": "Blind run, no summons",
+			"url":         "https://twitch.tv/whykusanagi",
+			"color":       float64(16711935),
+		},
+	}, loader)
+	require.NoError(t, err)
+
+	data := result.(map[string]interface{})
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, 200, data["status_code"])
+	assert.Equal(t, "111", data["message_id"])
+
+	require.Len(t, *payloads, 1)
+	assert.Equal(t, discordPayload{
+		Content:   "We're live!",
+		Username:  "Celeste",
+		AvatarURL: "https://example.com/celeste.png",
+		Embeds: []discordEmbed{{
+			Title:       "Elden Ring DLC",
+			Description: "Blind run, no summons",
+			URL:         "https://twitch.tv/whykusanagi",
+			Color:       16711935,
+		}},
+	}, (*payloads)[0])
+}
+
+func TestPostDiscordHandlerRetriesAfterRateLimit(t *testing.T) {
+	loader, payloads := stubDiscordWebhook(t, 1, "0.01")
+
+	result, err := PostDiscordHandler(context.Background(), map[string]interface{}{"content": "hi"}, loader)
+	require.NoError(t, err)
+	assert.Equal(t, true, result.(map[string]interface{})["success"])
+	assert.Len(t, *payloads, 2)
+}
+
+func TestPostDiscordHandlerLongRateLimit(t *testing.T) {
+	original := discordMaxRetryAfter
+	discordMaxRetryAfter = 50 * time.Millisecond
+	t.Cleanup(func() { discordMaxRetryAfter = original })
+
+	loader, payloads := stubDiscordWebhook(t, 1, "30")
+
+	result, err := PostDiscordHandler(context.Background(), map[string]interface{}{"content": "hi"}, loader)
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, "rate_limited", data["error_type"])
+	assert.Equal(t, 429, data["status_code"])
+	assert.Equal(t, 30.0, data["retry_after"])
+	assert.Len(t, *payloads, 1, "waits longer than the cap are not retried")
+}
+
+func TestPostDiscordHandlerValidation(t *testing.T) {
+	loader, payloads := stubDiscordWebhook(t, 0, "0")
+
+	for name, args := range map[string]map[string]interface{}{
+		"empty":     {},
+		"too long":  {"content": strings.Repeat("a", discordMaxContent+1)},
+		"bad embed": {"embed": map[string]interface{}{"url": "https://example.com"}},
+	} {
+		result, err := PostDiscordHandler(context.Background(), args, loader)
+		require.NoError(t, err, name)
+		assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"], name)
+	}
+	assert.Empty(t, *payloads)
+
+	// Unconfigured webhook
+	result, err := PostDiscordHandler(context.Background(), map[string]interface{}{"content": "hi"}, &MockConfigLoader{})
+	require.NoError(t, err)
+	assert.Equal(t, "config_error", result.(map[string]interface{})["error_type"])
+}
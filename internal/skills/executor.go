@@ -14,6 +14,10 @@ type ExecutionResult struct {
 	Result  interface{} `json:"result,omitempty"`
 	Error   string      `json:"error,omitempty"`
 	Time    time.Time   `json:"timestamp"`
+
+	// DefaultsApplied holds the skill_defaults values filled in for
+	// parameters the call left out
+	DefaultsApplied map[string]interface{} `json:"defaults_applied,omitempty"`
 }
 
 // ExecutionContext provides context for skill execution.
@@ -51,13 +55,17 @@ func (e *Executor) Execute(ctx context.Context, name string, argsJSON string) (*
 	} else {
 		args = make(map[string]interface{})
 	}
+	result.DefaultsApplied = e.registry.applyDefaults(name, args)
 
 	// Execute skill
-	output, err := e.registry.Execute(name, args)
+	output, err := e.registry.ExecuteContext(ctx, name, args)
 	if err != nil {
 		result.Error = err.Error()
 		return result, err
 	}
+	if m, ok := output.(map[string]interface{}); ok && result.DefaultsApplied != nil {
+		m[DefaultsAppliedKey] = result.DefaultsApplied
+	}
 
 	result.Success = true
 	result.Result = output
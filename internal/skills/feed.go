@@ -0,0 +1,322 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// Feed item limits.
+const (
+	feedDefaultItems    = 10
+	feedMaxItems        = 50
+	feedMaxSummaryRunes = 500
+)
+
+// htmlTagPattern matches tags stripped from item summaries.
+var htmlTagPattern = regexp.MustCompile(`(?s)<[^>]*>`)
+
+// feedDateLayouts are the date formats seen in RSS and Atom feeds.
+var feedDateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// ReadFeedSkill returns the RSS/Atom feed reader skill definition.
+func ReadFeedSkill() Skill {
+	return Skill{
+		Name:        "read_feed",
+		Description: "Read the latest items from an RSS or Atom feed, such as a blog, news site or GitHub releases feed",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"url": map[string]interface{}{
+					"type":        "string",
+					"description": "Feed URL (http or https)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Number of items to return (default %d, max %d)", feedDefaultItems, feedMaxItems),
+				},
+			},
+			"required": []string{"url"},
+		},
+	}
+}
+
+// feedText collects all character data inside an element, so plain text,
+// CDATA and inline XHTML (Atom type="xhtml") all decode the same way.
+type feedText string
+
+func (t *feedText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var b strings.Builder
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch token := token.(type) {
+		case xml.CharData:
+			b.Write(token)
+		case xml.StartElement:
+			b.WriteString(" ")
+		case xml.EndElement:
+			if token.Name == start.Name {
+				*t = feedText(b.String())
+				return nil
+			}
+			b.WriteString(" ")
+		}
+	}
+}
+
+// feedLink is an RSS <link>text</link> or an Atom <link href="..."/>.
+type feedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Text string `xml:",chardata"`
+}
+
+// feedEntry is an RSS <item> or an Atom <entry>.
+type feedEntry struct {
+	Title       feedText   `xml:"title"`
+	Links       []feedLink `xml:"link"`
+	GUID        string     `xml:"guid"`
+	PubDate     string     `xml:"pubDate"`
+	Published   string     `xml:"published"`
+	Updated     string     `xml:"updated"`
+	Date        string     `xml:"date"` // dc:date in RSS 1.0
+	Description feedText   `xml:"description"`
+	Summary     feedText   `xml:"summary"`
+	Content     feedText   `xml:"content"`
+}
+
+// feedDocument decodes RSS 2.0 (items under <channel>), RSS 1.0 (items
+// beside <channel>) and Atom (<entry> under <feed>).
+type feedDocument struct {
+	XMLName xml.Name
+	Title   feedText `xml:"title"`
+	Channel struct {
+		Title feedText    `xml:"title"`
+		Items []feedEntry `xml:"item"`
+	} `xml:"channel"`
+	Items   []feedEntry `xml:"item"`
+	Entries []feedEntry `xml:"entry"`
+}
+
+// feedItem is one item returned by read_feed.
+type feedItem struct {
+	Title     string `json:"title"`
+	Link      string `json:"link"`
+	Published string `json:"published,omitempty"`
+	Summary   string `json:"summary,omitempty"`
+}
+
+// parseFeed decodes an RSS or Atom document into its title and items.
+func parseFeed(data []byte) (string, []feedItem, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		enc, err := htmlindex.Get(label)
+		if err != nil {
+			return nil, err
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+
+	var doc feedDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return "", nil, err
+	}
+
+	var title string
+	var entries []feedEntry
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "rss", "rdf":
+		title = cleanFeedText(string(doc.Channel.Title))
+		entries = append(doc.Channel.Items, doc.Items...)
+	case "feed":
+		title = cleanFeedText(string(doc.Title))
+		entries = doc.Entries
+	default:
+		return "", nil, fmt.Errorf("not an RSS or Atom feed (root element <%s>)", doc.XMLName.Local)
+	}
+
+	items := make([]feedItem, len(entries))
+	for i, entry := range entries {
+		summary := entry.Description
+		for _, alt := range []feedText{entry.Summary, entry.Content} {
+			if strings.TrimSpace(string(summary)) == "" {
+				summary = alt
+			}
+		}
+		items[i] = feedItem{
+			Title:     cleanFeedText(string(entry.Title)),
+			Link:      entry.link(),
+			Published: feedDate(entry.PubDate, entry.Published, entry.Date, entry.Updated),
+			Summary:   truncateRunes(cleanFeedText(string(summary)), feedMaxSummaryRunes),
+		}
+	}
+	return title, items, nil
+}
+
+// link picks the item's web link: Atom's alternate link, then RSS's link
+// text, then a permalink GUID.
+func (e feedEntry) link() string {
+	for _, l := range e.Links {
+		if l.Href != "" && (l.Rel == "" || l.Rel == "alternate") {
+			return l.Href
+		}
+	}
+	for _, l := range e.Links {
+		if text := strings.TrimSpace(l.Text); text != "" {
+			return text
+		}
+	}
+	if strings.HasPrefix(e.GUID, "http") {
+		return strings.TrimSpace(e.GUID)
+	}
+	return ""
+}
+
+// feedDate returns the first non-empty date, normalized to RFC 3339 when
+// it's in a known format and passed through as-is otherwise.
+func feedDate(candidates ...string) string {
+	for _, value := range candidates {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		for _, layout := range feedDateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.Format(time.RFC3339)
+			}
+		}
+		return value
+	}
+	return ""
+}
+
+// cleanFeedText strips HTML tags and entities and collapses whitespace.
+func cleanFeedText(s string) string {
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// truncateRunes shortens s to at most max runes, ending with an ellipsis.
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
+
+// ReadFeedHandler fetches a feed and returns its latest items.
+func ReadFeedHandler(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	feedURL, _ := args["url"].(string)
+	feedURL = strings.TrimSpace(feedURL)
+	parsed, err := url.Parse(feedURL)
+	if feedURL == "" || err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return formatErrorResponse(
+			"validation_error",
+			"A valid http or https feed URL is required",
+			"Provide the address of an RSS or Atom feed, e.g. https://github.com/whykusanagi/celesteCLI/releases.atom",
+			map[string]interface{}{
+				"skill":    "read_feed",
+				"field":    "url",
+				"provided": feedURL,
+			},
+		), nil
+	}
+
+	limit := feedDefaultItems
+	if l, ok := args["limit"].(float64); ok && l >= 1 {
+		limit = int(l)
+	}
+	if limit > feedMaxItems {
+		limit = feedMaxItems
+	}
+
+	data, err := fetchFeed(ctx, feedURL)
+	if err != nil {
+		return httpGetErrorResponse(ctx, err, "read_feed", parsed.Host), nil
+	}
+
+	title, items, err := parseFeed(data)
+	if err != nil {
+		return formatErrorResponse(
+			"api_error",
+			"Failed to parse the feed",
+			"Check that the URL points at an RSS or Atom feed rather than a web page.",
+			map[string]interface{}{
+				"skill": "read_feed",
+				"url":   feedURL,
+				"error": err.Error(),
+			},
+		), nil
+	}
+
+	total := len(items)
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return map[string]interface{}{
+		"feed_title":  title,
+		"url":         feedURL,
+		"items":       items,
+		"count":       len(items),
+		"total_items": total,
+	}, nil
+}
+
+// fetchFeed downloads a feed, capped at maxCachedResponseBytes. Failures
+// are returned as *httpGetError.
+func fetchFeed(ctx context.Context, feedURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, &httpGetError{Kind: "network", Err: err}
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	req.Header.Set("User-Agent", version.UserAgent())
+
+	client := httpclient.New(0)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &httpGetError{Kind: "network", Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedResponseBytes+1))
+	if err != nil {
+		return nil, &httpGetError{Kind: "network", Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpGetError{Kind: "status", StatusCode: resp.StatusCode, Body: truncateRunes(string(body), 500)}
+	}
+	if int64(len(body)) > maxCachedResponseBytes {
+		return nil, &httpGetError{Kind: "too_large", StatusCode: resp.StatusCode}
+	}
+	return body, nil
+}
@@ -0,0 +1,104 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Celeste News</title>
+    <atom:link href="https://example.com/feed.xml" rel="self"/>
+    <item>
+      <title>v1.2 released</title>
+      <link>https://example.com/v1.2</link>
+      <pubDate>Mon, 02 Jun 2025 18:00:00 +0000</pubDate>
+      <description><![CDATA[<p>New <b>skills</b> &amp; fixes</p>]]></description>
+    </item>
+    <item>
+      <title>Stream schedule</title>
+      <guid>https://example.com/schedule</guid>
+      <description>&lt;p&gt;Live every &lt;em&gt;Friday&lt;/em&gt;&amp;nbsp;night&lt;/p&gt;</description>
+    </item>
+  </channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title type="text">Release notes</title>
+  <entry>
+    <title>v2.0</title>
+    <link rel="replies" href="https://example.com/v2.0/comments"/>
+    <link rel="alternate" href="https://example.com/v2.0"/>
+    <updated>2025-06-03T09:30:00Z</updated>
+    <content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Big</p><p>release</p></div></content>
+  </entry>
+</feed>`
+
+func TestParseFeedRSS(t *testing.T) {
+	title, items, err := parseFeed([]byte(testRSSFeed))
+	require.NoError(t, err)
+	assert.Equal(t, "Celeste News", title)
+	assert.Equal(t, []feedItem{
+		{Title: "v1.2 released", Link: "https://example.com/v1.2", Published: "2025-06-02T18:00:00Z", Summary: "New skills & fixes"},
+		{Title: "Stream schedule", Link: "https://example.com/schedule", Summary: "Live every Friday night"},
+	}, items)
+}
+
+func TestParseFeedAtom(t *testing.T) {
+	title, items, err := parseFeed([]byte(testAtomFeed))
+	require.NoError(t, err)
+	assert.Equal(t, "Release notes", title)
+	assert.Equal(t, []feedItem{
+		{Title: "v2.0", Link: "https://example.com/v2.0", Published: "2025-06-03T09:30:00Z", Summary: "Big release"},
+	}, items)
+}
+
+func TestParseFeedRejectsHTML(t *testing.T) {
+	_, _, err := parseFeed([]byte(`<html><body>Not a feed</body></html>`))
+	assert.Error(t, err)
+}
+
+func TestReadFeedHandler(t *testing.T) {
+	var entries strings.Builder
+	for i := 1; i <= 60; i++ {
+		fmt.Fprintf(&entries, "<item><title>Item %d</title><link>https://example.com/%d</link></item>", i, i)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `<rss version="2.0"><channel><title>Many</title>%s</channel></rss>`, entries.String())
+	}))
+	defer server.Close()
+
+	result, err := ReadFeedHandler(context.Background(), map[string]interface{}{"url": server.URL + "/feed", "limit": float64(3)})
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, "Many", data["feed_title"])
+	assert.Equal(t, 3, data["count"])
+	assert.Equal(t, 60, data["total_items"])
+	assert.Equal(t, "Item 1", data["items"].([]feedItem)[0].Title)
+
+	result, err = ReadFeedHandler(context.Background(), map[string]interface{}{"url": server.URL + "/feed", "limit": float64(500)})
+	require.NoError(t, err)
+	assert.Equal(t, feedMaxItems, result.(map[string]interface{})["count"])
+
+	result, err = ReadFeedHandler(context.Background(), map[string]interface{}{"url": server.URL + "/missing"})
+	require.NoError(t, err)
+	assert.Equal(t, "api_error", result.(map[string]interface{})["error_type"])
+	assert.Equal(t, 404, result.(map[string]interface{})["status_code"])
+
+	result, err = ReadFeedHandler(context.Background(), map[string]interface{}{"url": "file:///etc/passwd"})
+	require.NoError(t, err)
+	assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+}
@@ -0,0 +1,227 @@
+package skills
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// maxCachedResponseBytes caps the size of a response CachedGetJSON will
+// read. Overridden in tests.
+var maxCachedResponseBytes int64 = 2 << 20
+
+// httpGetError is a failed CachedGetJSON call. Kind is one of "network",
+// "status", "too_large" or "parse".
+type httpGetError struct {
+	Kind       string
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+func (e *httpGetError) Error() string {
+	switch e.Kind {
+	case "status":
+		return fmt.Sprintf("HTTP status %d", e.StatusCode)
+	case "too_large":
+		return fmt.Sprintf("response larger than %d bytes", maxCachedResponseBytes)
+	}
+	return e.Err.Error()
+}
+
+func (e *httpGetError) Unwrap() error {
+	return e.Err
+}
+
+// httpCacheEntry is one cached response.
+type httpCacheEntry struct {
+	URL       string          `json:"url"`
+	ETag      string          `json:"etag,omitempty"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// getHTTPCachePath returns the cache file for url, named by its SHA-256.
+func getHTTPCachePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return paths.Data("cache", "http", hex.EncodeToString(sum[:])+".json")
+}
+
+// loadHTTPCacheEntry reads the cached response for url. A missing or
+// corrupt entry is treated as absent.
+func loadHTTPCacheEntry(url string) *httpCacheEntry {
+	data, err := os.ReadFile(getHTTPCachePath(url))
+	if err != nil {
+		return nil
+	}
+	var entry httpCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url || len(entry.Body) == 0 {
+		return nil
+	}
+	return &entry
+}
+
+// saveHTTPCacheEntry stores entry. Failures are ignored; the next call
+// simply fetches again.
+func saveHTTPCacheEntry(entry *httpCacheEntry) {
+	path := getHTTPCachePath(entry.URL)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return
+	}
+	// Write then rename so a concurrent reader never sees half an entry
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// CachedGetJSON fetches url and decodes its JSON body into out. Responses
+// are kept in the data directory's cache/http/ and served without a request for ttl.
+// After that the stored ETag is sent as If-None-Match, and a 304 serves
+// the cached body again. Failures are returned as *httpGetError; see
+// httpGetErrorResponse.
+func CachedGetJSON(ctx context.Context, url string, ttl time.Duration, out interface{}) error {
+	cached := loadHTTPCacheEntry(url)
+	if cached != nil && time.Since(cached.FetchedAt) < ttl {
+		if err := json.Unmarshal(cached.Body, out); err == nil {
+			return nil
+		}
+		cached = nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return &httpGetError{Kind: "network", Err: err}
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+	req.Header.Set("Accept", "application/json")
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	client := httpclient.New(0)
+	resp, err := client.Do(req)
+	if err != nil {
+		return &httpGetError{Kind: "network", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		if err := json.Unmarshal(cached.Body, out); err != nil {
+			return &httpGetError{Kind: "parse", Err: err}
+		}
+		cached.FetchedAt = time.Now()
+		saveHTTPCacheEntry(cached)
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedResponseBytes+1))
+	if err != nil {
+		return &httpGetError{Kind: "network", Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		if int64(len(body)) > maxCachedResponseBytes {
+			body = body[:maxCachedResponseBytes]
+		}
+		return &httpGetError{Kind: "status", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	if int64(len(body)) > maxCachedResponseBytes {
+		return &httpGetError{Kind: "too_large", StatusCode: resp.StatusCode}
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return &httpGetError{Kind: "parse", Err: err}
+	}
+
+	saveHTTPCacheEntry(&httpCacheEntry{
+		URL:       url,
+		ETag:      resp.Header.Get("ETag"),
+		FetchedAt: time.Now(),
+		Body:      body,
+	})
+	return nil
+}
+
+// httpGetErrorResponse turns a CachedGetJSON failure into an error
+// response for skill. service names the upstream, e.g. "Weather API".
+func httpGetErrorResponse(ctx context.Context, err error, skill, service string) map[string]interface{} {
+	var getErr *httpGetError
+	if !errors.As(err, &getErr) {
+		getErr = &httpGetError{Kind: "network", Err: err}
+	}
+
+	switch getErr.Kind {
+	case "network":
+		if cancelled := contextErrorResponse(ctx, skill); cancelled != nil {
+			return cancelled
+		}
+		return formatErrorResponse(
+			"network_error",
+			fmt.Sprintf("Failed to connect to %s", service),
+			"Please check your internet connection and try again.",
+			map[string]interface{}{
+				"skill": skill,
+				"error": getErr.Error(),
+			},
+		)
+	case "status":
+		if getErr.StatusCode == http.StatusTooManyRequests {
+			return formatErrorResponse(
+				"rate_limited",
+				fmt.Sprintf("%s rate limit reached", service),
+				"Wait a minute before trying again.",
+				map[string]interface{}{
+					"skill":       skill,
+					"status_code": getErr.StatusCode,
+				},
+			)
+		}
+		return formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("%s returned error (status %d)", service, getErr.StatusCode),
+			"The service may be temporarily unavailable. Please try again later.",
+			map[string]interface{}{
+				"skill":       skill,
+				"status_code": getErr.StatusCode,
+				"response":    getErr.Body,
+			},
+		)
+	case "too_large":
+		return formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("%s response was larger than %d bytes", service, maxCachedResponseBytes),
+			"The service returned an unexpectedly large response. Please try again later.",
+			map[string]interface{}{
+				"skill": skill,
+			},
+		)
+	default:
+		return formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("Failed to parse %s response", service),
+			"The service returned invalid data. Please try again.",
+			map[string]interface{}{
+				"skill": skill,
+				"error": getErr.Error(),
+			},
+		)
+	}
+}
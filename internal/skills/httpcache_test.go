@@ -0,0 +1,140 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// stubETagServer serves {"n": <request count>} with a fixed ETag, answering
+// 304 when the client already has it.
+func stubETagServer(t *testing.T) (*httptest.Server, *int, *int) {
+	t.Helper()
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, version.UserAgent(), r.Header.Get("User-Agent"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(map[string]int{"n": requests})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, &notModified
+}
+
+func TestCachedGetJSONCacheHit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, requests, _ := stubETagServer(t)
+
+	for i := 0; i < 3; i++ {
+		var out struct{ N int }
+		require.NoError(t, CachedGetJSON(context.Background(), server.URL+"/weather", time.Hour, &out))
+		assert.Equal(t, 1, out.N)
+	}
+	assert.Equal(t, 1, *requests, "responses within the TTL are served from the cache")
+	assert.FileExists(t, getHTTPCachePath(server.URL+"/weather"))
+}
+
+func TestCachedGetJSONExpiredUsesETag(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, requests, notModified := stubETagServer(t)
+	url := server.URL + "/rates"
+
+	var out struct{ N int }
+	require.NoError(t, CachedGetJSON(context.Background(), url, time.Hour, &out))
+
+	// Age the entry past its TTL
+	entry := loadHTTPCacheEntry(url)
+	require.NotNil(t, entry)
+	entry.FetchedAt = time.Now().Add(-2 * time.Hour)
+	saveHTTPCacheEntry(entry)
+
+	out.N = 0
+	require.NoError(t, CachedGetJSON(context.Background(), url, time.Hour, &out))
+	assert.Equal(t, 1, out.N, "a 304 serves the cached body")
+	assert.Equal(t, 2, *requests)
+	assert.Equal(t, 1, *notModified)
+
+	// The 304 refreshed the entry, so the next call doesn't hit the server
+	require.NoError(t, CachedGetJSON(context.Background(), url, time.Hour, &out))
+	assert.Equal(t, 2, *requests)
+}
+
+func TestCachedGetJSONExpiredWithoutETag(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		_ = json.NewEncoder(w).Encode(map[string]int{"n": requests})
+	}))
+	defer server.Close()
+
+	var out struct{ N int }
+	require.NoError(t, CachedGetJSON(context.Background(), server.URL, 0, &out))
+	require.NoError(t, CachedGetJSON(context.Background(), server.URL, 0, &out))
+	assert.Equal(t, 2, out.N)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCachedGetJSONSizeCap(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	original := maxCachedResponseBytes
+	maxCachedResponseBytes = 64
+	defer func() { maxCachedResponseBytes = original }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"padding":"` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	var out map[string]interface{}
+	err := CachedGetJSON(context.Background(), server.URL, time.Hour, &out)
+	require.Error(t, err)
+	assert.Equal(t, "too_large", err.(*httpGetError).Kind)
+	assert.NoFileExists(t, getHTTPCachePath(server.URL), "oversized responses aren't cached")
+
+	response := httpGetErrorResponse(context.Background(), err, "get_weather", "Weather API")
+	assert.Equal(t, "api_error", response["error_type"])
+}
+
+func TestCachedGetJSONErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("<html>not json</html>"))
+	}))
+
+	var out map[string]interface{}
+	err := CachedGetJSON(context.Background(), server.URL+"/down", time.Hour, &out)
+	response := httpGetErrorResponse(context.Background(), err, "convert_currency", "Currency API")
+	assert.Equal(t, "api_error", response["error_type"])
+	assert.Equal(t, "Currency API returned error (status 503)", response["message"])
+	assert.Equal(t, 503, response["status_code"])
+
+	err = CachedGetJSON(context.Background(), server.URL+"/html", time.Hour, &out)
+	response = httpGetErrorResponse(context.Background(), err, "convert_currency", "Currency API")
+	assert.Equal(t, "Failed to parse Currency API response", response["message"])
+	assert.NoFileExists(t, getHTTPCachePath(server.URL+"/html"))
+
+	server.Close()
+	err = CachedGetJSON(context.Background(), server.URL+"/gone", time.Hour, &out)
+	response = httpGetErrorResponse(context.Background(), err, "convert_currency", "Currency API")
+	assert.Equal(t, "network_error", response["error_type"])
+}
@@ -0,0 +1,200 @@
+// Package skills provides the image generation skill
+package skills
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/venice"
+)
+
+// imageIntParams are generate_image arguments Venice expects as integers.
+// JSON numbers arrive as float64 and are converted when they are whole.
+var imageIntParams = []string{"width", "height", "steps", "seed", "variants"}
+
+// ImageGenerationSkill returns the image generation skill definition.
+// It is marked NSFW, so safe mode removes it, and SideEffect, since it
+// writes files.
+func ImageGenerationSkill() Skill {
+	return Skill{
+		Name:        "generate_image",
+		Description: "Generate an image from a text prompt with Venice.ai. Saves the image to the downloads folder and returns its path and the parameters used.",
+		NSFW:        true,
+		SideEffect:  true,
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"prompt": map[string]interface{}{
+					"type":        "string",
+					"description": "What to draw",
+				},
+				"negative_prompt": map[string]interface{}{
+					"type":        "string",
+					"description": "What to keep out of the image",
+				},
+				"model": map[string]interface{}{
+					"type":        "string",
+					"description": "Image model (default: venice_image_model, or lustify-sdxl)",
+				},
+				"width": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("Width in pixels, a multiple of %d up to %d (default %d)", venice.ImageDimensionMul, venice.MaxImageDimension, venice.DefaultImageWidth),
+				},
+				"height": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("Height in pixels, a multiple of %d up to %d (default %d)", venice.ImageDimensionMul, venice.MaxImageDimension, venice.DefaultImageHeight),
+				},
+				"steps": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("Diffusion steps, %d-%d (default %d)", venice.MinImageSteps, venice.MaxImageSteps, venice.DefaultImageSteps),
+				},
+				"cfg_scale": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Prompt adherence, greater than 0 and at most %.0f (default %.0f)", venice.MaxImageCFGScale, venice.DefaultImageCFGScale),
+				},
+				"seed": map[string]interface{}{
+					"type":        "integer",
+					"description": "Seed to reproduce an earlier image",
+				},
+				"variants": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("Number of images to generate, 1-%d (default 1)", venice.MaxImageVariants),
+				},
+			},
+			"required": []string{"prompt"},
+		},
+	}
+}
+
+// ImageGenerationHandler generates an image with Venice.ai using the
+// generation parameters in args. Out-of-range values are rejected before
+// any request is made, and ctx cancels the request.
+func ImageGenerationHandler(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+	prompt, _ := args["prompt"].(string)
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return formatErrorResponse(
+			"validation_error",
+			"A prompt is required to generate an image.",
+			"Describe what the image should show.",
+			map[string]interface{}{
+				"skill": "generate_image",
+				"field": "prompt",
+			},
+		), nil
+	}
+
+	config, err := configLoader.GetVeniceConfig()
+	if err != nil {
+		return formatErrorResponse(
+			"config_error",
+			"Venice.ai API key is required. Please configure it using: celeste config --set-venice-key <key>",
+			"Image generation runs on Venice.ai.",
+			map[string]interface{}{
+				"skill":          "generate_image",
+				"config_command": "celeste config --set-venice-key <key>",
+			},
+		), nil
+	}
+
+	params := imageParams(args)
+	if err := venice.ValidateImageParams(params); err != nil {
+		return formatErrorResponse(
+			"validation_error",
+			fmt.Sprintf("Invalid image parameters: %v", err),
+			"",
+			map[string]interface{}{
+				"skill": "generate_image",
+			},
+		), nil
+	}
+
+	response, err := venice.GenerateImage(ctx, venice.Config{
+		APIKey:  config.APIKey,
+		BaseURL: config.BaseURL,
+		Model:   config.ImageModel,
+	}, prompt, params)
+	if err != nil {
+		return formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("Image generation failed: %v", err),
+			"",
+			map[string]interface{}{
+				"skill": "generate_image",
+			},
+		), nil
+	}
+	if !response.Success {
+		return formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("Image generation failed: %s", response.Error),
+			"",
+			map[string]interface{}{
+				"skill": "generate_image",
+			},
+		), nil
+	}
+
+	result := map[string]interface{}{
+		"success": true,
+		"params":  response.Params,
+	}
+	if response.Path != "" {
+		result["path"] = response.Path
+	}
+	if len(response.Paths) > 1 {
+		result["paths"] = response.Paths
+	}
+	if response.URL != "" {
+		result["url"] = response.URL
+	}
+	if response.Seed != nil {
+		result["seed"] = *response.Seed
+	}
+	if response.Warning != "" {
+		result["warning"] = response.Warning
+	}
+	return result, nil
+}
+
+// imageParams copies the generation parameters from skill arguments into
+// the form venice.GenerateImage takes. Names may use dashes for underscores,
+// as with --cfg-scale on the command line. Whole numbers become ints and
+// cfg_scale becomes a float64; anything else is passed through for
+// ValidateImageParams to report.
+func imageParams(args map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(args))
+	for key, v := range args {
+		normalized[strings.ReplaceAll(key, "-", "_")] = v
+	}
+	args = normalized
+
+	params := make(map[string]interface{})
+	for _, key := range []string{"negative_prompt", "model"} {
+		if v, ok := args[key]; ok && v != "" {
+			params[key] = v
+		}
+	}
+	if v, ok := args["cfg_scale"]; ok {
+		switch n := v.(type) {
+		case int:
+			v = float64(n)
+		case int64:
+			v = float64(n)
+		}
+		params["cfg_scale"] = v
+	}
+	for _, key := range imageIntParams {
+		v, ok := args[key]
+		if !ok {
+			continue
+		}
+		if f, isFloat := v.(float64); isFloat && f == math.Trunc(f) {
+			v = int(f)
+		}
+		params[key] = v
+	}
+	return params
+}
@@ -0,0 +1,160 @@
+package skills
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/venice"
+)
+
+// stubVeniceImages serves /image/generate and returns a loader pointing at
+// it along with the last payload received.
+func stubVeniceImages(t *testing.T) (*MockConfigLoader, *map[string]interface{}) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	payload := map[string]interface{}{}
+	img := base64.StdEncoding.EncodeToString([]byte{0x89, 0x50, 0x4E, 0x47})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/image/generate", r.URL.Path)
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"images": []string{img}})
+	}))
+	t.Cleanup(server.Close)
+
+	loader := NewMockConfigLoader()
+	loader.VeniceCfg = VeniceConfig{APIKey: "test-key", BaseURL: server.URL, ImageModel: "hidream"}
+	return loader, &payload
+}
+
+func TestImageGenerationHandler(t *testing.T) {
+	loader, payload := stubVeniceImages(t)
+
+	result, err := ImageGenerationHandler(context.Background(), map[string]interface{}{
+		"prompt":          "a lighthouse at dusk",
+		"negative_prompt": "people",
+		"width":           float64(768),
+		"height":          float64(512),
+		"steps":           float64(20),
+		"cfg_scale":       float64(7.5),
+	}, loader)
+	require.NoError(t, err)
+
+	data := result.(map[string]interface{})
+	require.Equal(t, true, data["success"], data)
+	assert.NotEmpty(t, data["path"])
+
+	assert.Equal(t, "hidream", (*payload)["model"])
+	assert.Equal(t, "people", (*payload)["negative_prompt"])
+	assert.Equal(t, float64(768), (*payload)["width"])
+	assert.Equal(t, float64(512), (*payload)["height"])
+	assert.Equal(t, float64(20), (*payload)["steps"])
+	assert.Equal(t, 7.5, (*payload)["cfg_scale"])
+
+	params := data["params"].(map[string]interface{})
+	assert.Equal(t, 768, params["width"])
+	assert.Equal(t, 20, params["steps"])
+}
+
+func TestImageGenerationHandlerDefaults(t *testing.T) {
+	loader, payload := stubVeniceImages(t)
+	loader.VeniceCfg.ImageModel = "lustify-sdxl"
+
+	result, err := ImageGenerationHandler(context.Background(), map[string]interface{}{"prompt": "a fox"}, loader)
+	require.NoError(t, err)
+	require.Equal(t, true, result.(map[string]interface{})["success"])
+
+	assert.Equal(t, float64(1024), (*payload)["width"])
+	assert.Equal(t, float64(40), (*payload)["steps"])
+	assert.Equal(t, float64(12), (*payload)["cfg_scale"])
+}
+
+func TestImageGenerationHandlerValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{name: "missing prompt", args: map[string]interface{}{}, message: "prompt is required"},
+		{name: "steps out of range", args: map[string]interface{}{"prompt": "x", "steps": float64(80)}, message: "steps must be between 1 and 50"},
+		{name: "width not a multiple of 64", args: map[string]interface{}{"prompt": "x", "width": float64(700)}, message: "width must be a multiple of 64"},
+		{name: "fractional height", args: map[string]interface{}{"prompt": "x", "height": float64(512.5)}, message: "height must be an integer"},
+		{name: "cfg_scale too high", args: map[string]interface{}{"prompt": "x", "cfg_scale": float64(25)}, message: "cfg_scale must be greater than 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The server fails the test if validation lets a request through
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("no request should be made")
+			}))
+			defer server.Close()
+			loader := NewMockConfigLoader()
+			loader.VeniceCfg = VeniceConfig{APIKey: "test-key", BaseURL: server.URL}
+
+			result, err := ImageGenerationHandler(context.Background(), tt.args, loader)
+			require.NoError(t, err)
+
+			data := result.(map[string]interface{})
+			assert.Equal(t, true, data["error"])
+			assert.Equal(t, "validation_error", data["error_type"])
+			assert.Contains(t, data["message"], tt.message)
+		})
+	}
+}
+
+func TestImageGenerationHandlerRequiresVeniceKey(t *testing.T) {
+	loader := NewMockConfigLoader()
+	loader.VeniceError = errors.New("Venice.ai API key not configured")
+
+	result, err := ImageGenerationHandler(context.Background(), map[string]interface{}{"prompt": "a fox"}, loader)
+	require.NoError(t, err)
+
+	data := result.(map[string]interface{})
+	assert.Equal(t, "config_error", data["error_type"])
+	assert.Equal(t, "celeste config --set-venice-key <key>", data["config_command"])
+}
+
+func TestImageGenerationHandlerCanceled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body) // The server notices a closed connection once the body is read
+		<-r.Context().Done()               // Never answers; only the cancellation ends the request
+	}))
+	defer server.Close()
+	loader := NewMockConfigLoader()
+	loader.VeniceCfg = VeniceConfig{APIKey: "test-key", BaseURL: server.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := ImageGenerationHandler(ctx, map[string]interface{}{"prompt": "a fox"}, loader)
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	data := result.(map[string]interface{})
+	assert.Equal(t, "api_error", data["error_type"])
+	assert.Contains(t, data["message"], "context deadline exceeded")
+}
+
+func TestImageParams(t *testing.T) {
+	params := imageParams(map[string]interface{}{
+		"cfg-scale":       7,
+		"negative-prompt": "people",
+		"steps":           float64(30),
+	})
+	assert.Equal(t, float64(7), params["cfg_scale"])
+	assert.Equal(t, "people", params["negative_prompt"])
+	assert.Equal(t, 30, params["steps"])
+	assert.NoError(t, venice.ValidateImageParams(params))
+}
@@ -0,0 +1,192 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// locationCacheTTL is how long an IP lookup is reused. Short enough that
+// a laptop moving between networks is noticed within the hour.
+const locationCacheTTL = 30 * time.Minute
+
+// geolocationProviders maps provider names to their lookup endpoints.
+// Overridden in tests.
+var geolocationProviders = map[string]string{
+	"ipapi":  "https://ipapi.co/json/",
+	"ipinfo": "https://ipinfo.io/json",
+	"ip-api": "http://ip-api.com/json/", // The free tier is HTTP only
+}
+
+// Location is an approximate location derived from an IP address.
+type Location struct {
+	IP          string  `json:"ip,omitempty"`
+	City        string  `json:"city,omitempty"`
+	Region      string  `json:"region,omitempty"`
+	Country     string  `json:"country,omitempty"`
+	CountryCode string  `json:"country_code,omitempty"`
+	PostalCode  string  `json:"postal_code,omitempty"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Timezone    string  `json:"timezone,omitempty"`
+	Provider    string  `json:"provider"`
+}
+
+// Coordinates returns "lat,lon" as wttr.in accepts them.
+func (l Location) Coordinates() string {
+	return strconv.FormatFloat(l.Latitude, 'f', 4, 64) + "," + strconv.FormatFloat(l.Longitude, 'f', 4, 64)
+}
+
+// geolocationResponse holds the fields of every supported provider:
+// ipapi.co, ipinfo.io and ip-api.com.
+type geolocationResponse struct {
+	IP          string  `json:"ip"`
+	Query       string  `json:"query"` // ip-api
+	City        string  `json:"city"`
+	Region      string  `json:"region"`
+	RegionName  string  `json:"regionName"` // ip-api
+	Country     string  `json:"country"`    // Name, except ipinfo's code
+	CountryName string  `json:"country_name"`
+	CountryCode string  `json:"country_code"`
+	CountryISO  string  `json:"countryCode"` // ip-api
+	Postal      string  `json:"postal"`
+	Zip         string  `json:"zip"` // ip-api
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Lat         float64 `json:"lat"` // ip-api
+	Lon         float64 `json:"lon"` // ip-api
+	Loc         string  `json:"loc"` // ipinfo: "lat,lon"
+	Timezone    string  `json:"timezone"`
+
+	// Failures reported with a 200 status
+	Error   bool   `json:"error"`  // ipapi
+	Reason  string `json:"reason"` // ipapi
+	Status  string `json:"status"` // ip-api: "success" or "fail"
+	Message string `json:"message"`
+}
+
+// GetLocationSkill returns the IP geolocation skill definition.
+func GetLocationSkill() Skill {
+	return Skill{
+		Name:        "get_location",
+		Description: "Get the user's approximate location (city, region, country, coordinates, timezone) from their public IP address",
+		Parameters: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+	}
+}
+
+// GetLocationHandler looks up the user's location by IP address.
+func GetLocationHandler(ctx context.Context, configLoader ConfigLoader) (interface{}, error) {
+	config, err := configLoader.GetLocationConfig()
+	if err != nil {
+		return formatErrorResponse(
+			"config_error",
+			"IP geolocation is turned off",
+			"Set geolocation_provider in ~/.celeste/skills.json to ipapi, ipinfo or ip-api to turn it on.",
+			map[string]interface{}{
+				"skill":          "get_location",
+				"config_command": "celeste config --set-geolocation-provider ipapi",
+			},
+		), nil
+	}
+
+	location, errResp := lookupLocation(ctx, config, "get_location")
+	if errResp != nil {
+		return errResp, nil
+	}
+	return location, nil
+}
+
+// lookupLocation asks the configured provider where the user's IP address
+// is. On failure it returns an error response for skill.
+func lookupLocation(ctx context.Context, config LocationConfig, skill string) (Location, map[string]interface{}) {
+	provider := strings.ToLower(strings.TrimSpace(config.Provider))
+	if provider == "" {
+		provider = "ipapi"
+	}
+	endpoint, ok := geolocationProviders[provider]
+	if !ok {
+		return Location{}, formatErrorResponse(
+			"config_error",
+			fmt.Sprintf("Unknown geolocation provider '%s'", config.Provider),
+			"Set geolocation_provider to ipapi, ipinfo or ip-api.",
+			map[string]interface{}{
+				"skill":          skill,
+				"config_command": "celeste config --set-geolocation-provider ipapi",
+			},
+		)
+	}
+	if provider == "ipinfo" && config.Token != "" {
+		endpoint += "?token=" + url.QueryEscape(config.Token)
+	}
+
+	var resp geolocationResponse
+	if err := CachedGetJSON(ctx, endpoint, locationCacheTTL, &resp); err != nil {
+		return Location{}, httpGetErrorResponse(ctx, err, skill, "geolocation service")
+	}
+	if resp.Error || resp.Status == "fail" {
+		// Don't serve the failure from the cache on the next call
+		_ = os.Remove(getHTTPCachePath(endpoint))
+		reason := resp.Reason
+		if reason == "" {
+			reason = resp.Message
+		}
+		return Location{}, formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("Geolocation lookup failed: %s", reason),
+			"Try again later, or set a default zip code with: celeste config --set-weather-zip <zip>",
+			map[string]interface{}{
+				"skill":    skill,
+				"provider": provider,
+			},
+		)
+	}
+
+	location := Location{
+		IP:          resp.IP,
+		City:        resp.City,
+		Region:      resp.Region,
+		Country:     resp.CountryName,
+		CountryCode: resp.CountryCode,
+		PostalCode:  resp.Postal,
+		Latitude:    resp.Latitude,
+		Longitude:   resp.Longitude,
+		Timezone:    resp.Timezone,
+		Provider:    provider,
+	}
+	switch provider {
+	case "ipinfo":
+		// ipinfo gives the two-letter country code and "lat,lon"
+		location.CountryCode = resp.Country
+		if lat, lon, ok := strings.Cut(resp.Loc, ","); ok {
+			location.Latitude, _ = strconv.ParseFloat(lat, 64)
+			location.Longitude, _ = strconv.ParseFloat(lon, 64)
+		}
+	case "ip-api":
+		location.IP = resp.Query
+		location.Region = resp.RegionName
+		location.Country = resp.Country
+		location.CountryCode = resp.CountryISO
+		location.PostalCode = resp.Zip
+		location.Latitude, location.Longitude = resp.Lat, resp.Lon
+	}
+	if location.Latitude == 0 && location.Longitude == 0 {
+		return Location{}, formatErrorResponse(
+			"api_error",
+			"Geolocation service returned no coordinates",
+			"Try again later, or set a default zip code with: celeste config --set-weather-zip <zip>",
+			map[string]interface{}{
+				"skill":    skill,
+				"provider": provider,
+			},
+		)
+	}
+	return location, nil
+}
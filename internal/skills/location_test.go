@@ -0,0 +1,135 @@
+package skills
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubGeolocation points every provider at a server answering with its
+// documented response shape, and wttr.in at the same server.
+func stubGeolocation(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ipapi":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.7","city":"Brooklyn","region":"New York","country_name":"United States","country_code":"US","postal":"11201","latitude":40.6943,"longitude":-73.9918,"timezone":"America/New_York"}`))
+		case "/ipinfo":
+			assert.Equal(t, "secret", r.URL.Query().Get("token"))
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.7","city":"Brooklyn","region":"New York","country":"US","loc":"40.6943,-73.9918","postal":"11201","timezone":"America/New_York"}`))
+		case "/ip-api":
+			_, _ = w.Write([]byte(`{"status":"success","country":"United States","countryCode":"US","regionName":"New York","city":"Brooklyn","zip":"11201","lat":40.6943,"lon":-73.9918,"timezone":"America/New_York","query":"203.0.113.7"}`))
+		case "/ipapi-limited":
+			_, _ = w.Write([]byte(`{"error":true,"reason":"RateLimited"}`))
+		case "/40.6943,-73.9918", "/35.6800,139.6900", "/São Paulo":
+			_, _ = w.Write([]byte(`{"current_condition":[{"temp_F":"72"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	originalProviders, originalWttr := geolocationProviders, wttrBaseURL
+	geolocationProviders = map[string]string{
+		"ipapi":   server.URL + "/ipapi",
+		"ipinfo":  server.URL + "/ipinfo",
+		"ip-api":  server.URL + "/ip-api",
+		"limited": server.URL + "/ipapi-limited",
+	}
+	wttrBaseURL = server.URL
+	t.Cleanup(func() { geolocationProviders, wttrBaseURL = originalProviders, originalWttr })
+}
+
+func TestGetLocationHandlerProviders(t *testing.T) {
+	stubGeolocation(t)
+
+	for _, provider := range []string{"", "ipapi", "ipinfo", "ip-api"} {
+		loader := NewMockConfigLoader()
+		loader.LocationCfg = LocationConfig{Provider: provider, Token: "secret"}
+
+		result, err := GetLocationHandler(context.Background(), loader)
+		require.NoError(t, err)
+		location, ok := result.(Location)
+		require.True(t, ok, "provider %q: %v", provider, result)
+		assert.Equal(t, "Brooklyn", location.City, provider)
+		assert.Equal(t, "New York", location.Region, provider)
+		assert.Equal(t, "US", location.CountryCode, provider)
+		assert.Equal(t, "11201", location.PostalCode, provider)
+		assert.Equal(t, "203.0.113.7", location.IP, provider)
+		assert.Equal(t, "40.6943,-73.9918", location.Coordinates(), provider)
+	}
+}
+
+func TestGetLocationHandlerErrors(t *testing.T) {
+	stubGeolocation(t)
+
+	result, err := GetLocationHandler(context.Background(), NewMockConfigLoaderWithErrors())
+	require.NoError(t, err)
+	assert.Equal(t, "config_error", result.(map[string]interface{})["error_type"])
+
+	loader := NewMockConfigLoader()
+	loader.LocationCfg.Provider = "geoguesser"
+	result, err = GetLocationHandler(context.Background(), loader)
+	require.NoError(t, err)
+	assert.Equal(t, "config_error", result.(map[string]interface{})["error_type"])
+
+	loader.LocationCfg.Provider = "limited"
+	result, err = GetLocationHandler(context.Background(), loader)
+	require.NoError(t, err)
+	assert.Equal(t, "Geolocation lookup failed: RateLimited", result.(map[string]interface{})["message"])
+	assert.NoFileExists(t, getHTTPCachePath(geolocationProviders["limited"]), "failures aren't cached")
+}
+
+func TestWeatherHandlerFallsBackToIPLocation(t *testing.T) {
+	stubGeolocation(t)
+
+	loader := NewMockConfigLoader()
+	loader.WeatherCfg.DefaultZipCode = ""
+	result, err := WeatherHandler(context.Background(), map[string]interface{}{}, loader)
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, "ip", data["location_source"])
+	assert.Equal(t, "Brooklyn", data["location"].(*Location).City)
+	assert.NotContains(t, data, "zip_code")
+
+	// With geolocation off a zip code is still required
+	loader.LocationError = assert.AnError
+	result, err = WeatherHandler(context.Background(), map[string]interface{}{}, loader)
+	require.NoError(t, err)
+	assert.Equal(t, "config_error", result.(map[string]interface{})["error_type"])
+}
+
+func TestWeatherHandlerCityAndCoordinates(t *testing.T) {
+	stubGeolocation(t)
+	loader := NewMockConfigLoader()
+
+	// A city overrides the default zip code and is path-escaped
+	result, err := WeatherHandler(context.Background(), map[string]interface{}{"city": " São Paulo ", "zip_code": "bad"}, loader)
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, "city", data["location_source"])
+	assert.Equal(t, "São Paulo", data["city"])
+	assert.NotContains(t, data, "zip_code")
+
+	// Coordinates, including numeric strings from the command line
+	result, err = WeatherHandler(context.Background(), map[string]interface{}{"lat": 35.68, "lon": "139.69"}, loader)
+	require.NoError(t, err)
+	data = result.(map[string]interface{})
+	assert.Equal(t, "coordinates", data["location_source"])
+	assert.Equal(t, 139.69, data["longitude"])
+
+	for name, args := range map[string]map[string]interface{}{
+		"lat without lon": {"lat": 35.68},
+		"out of range":    {"lat": 95.0, "lon": 0.0},
+		"short zip":       {"zip_code": "123"},
+	} {
+		result, err := WeatherHandler(context.Background(), args, loader)
+		require.NoError(t, err)
+		assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"], name)
+	}
+}
@@ -0,0 +1,256 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// Mastodon status limits.
+const (
+	mastodonDefaultMaxChars = 500 // Used when the instance doesn't report its own
+	mastodonURLLength       = 23  // Links always count as this many characters
+)
+
+// mastodonVisibilities are the accepted visibility levels.
+var mastodonVisibilities = []string{"public", "unlisted", "private", "direct"}
+
+// PostMastodonSkill returns the Mastodon posting skill definition.
+func PostMastodonSkill() Skill {
+	return Skill{
+		Name:        "post_mastodon",
+		Description: "Post a status (toot) to the user's Mastodon account on their configured instance. Only post when the user asks for it.",
+		SideEffect:  true,
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"status": map[string]interface{}{
+					"type":        "string",
+					"description": "Status text. Most instances allow 500 characters, counting the content warning; links count as 23.",
+				},
+				"content_warning": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional content warning shown before the status is expanded",
+				},
+				"visibility": map[string]interface{}{
+					"type":        "string",
+					"enum":        mastodonVisibilities,
+					"description": "Who can see the status: public (default), unlisted, private (followers only) or direct (mentioned users only)",
+				},
+			},
+			"required": []string{"status"},
+		},
+	}
+}
+
+// PostMastodonHandler posts a status to the configured Mastodon instance.
+func PostMastodonHandler(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+	config, err := configLoader.GetMastodonConfig()
+	if err != nil || config.InstanceURL == "" || config.AccessToken == "" {
+		return formatErrorResponse(
+			"config_error",
+			"Mastodon instance and access token are required. Please configure them using: celeste config --set-mastodon-instance <url> --set-mastodon-token <token>",
+			"Create an application with the write:statuses scope under Preferences > Development on your instance and copy its access token.",
+			map[string]interface{}{
+				"skill":          "post_mastodon",
+				"config_command": "celeste config --set-mastodon-instance <url> --set-mastodon-token <token>",
+			},
+		), nil
+	}
+	instance := strings.TrimRight(config.InstanceURL, "/")
+
+	status, _ := args["status"].(string)
+	status = strings.TrimSpace(status)
+	warning, _ := args["content_warning"].(string)
+	visibility, _ := args["visibility"].(string)
+	if visibility == "" {
+		visibility = "public"
+	}
+
+	if status == "" {
+		return mastodonValidationError("Status text is required", nil), nil
+	}
+	valid := false
+	for _, v := range mastodonVisibilities {
+		valid = valid || v == visibility
+	}
+	if !valid {
+		return mastodonValidationError(
+			fmt.Sprintf("Unknown visibility '%s' (use %s)", visibility, strings.Join(mastodonVisibilities, ", ")), nil), nil
+	}
+
+	limit := mastodonMaxChars(ctx, instance)
+	length := mastodonStatusLength(status) + utf8.RuneCountInString(warning)
+	if length > limit {
+		return mastodonValidationError(
+			fmt.Sprintf("Status is %d characters, over this instance's %d character limit", length, limit),
+			map[string]interface{}{"length": length, "limit": limit}), nil
+	}
+
+	payload := map[string]interface{}{
+		"status":     status,
+		"visibility": visibility,
+	}
+	if warning != "" {
+		payload["spoiler_text"] = warning
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode status: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", instance+"/api/v1/statuses", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+config.AccessToken)
+	// Retrying the same status within an hour won't post it twice
+	sum := sha256.Sum256(append([]byte(visibility+"\x00"+warning+"\x00"), status...))
+	req.Header.Set("Idempotency-Key", hex.EncodeToString(sum[:16]))
+
+	client := httpclient.New(0)
+	resp, err := client.Do(req)
+	if err != nil {
+		if cancelled := contextErrorResponse(ctx, "post_mastodon"); cancelled != nil {
+			return cancelled, nil
+		}
+		return formatErrorResponse(
+			"network_error",
+			"Failed to connect to the Mastodon instance",
+			"Please check your internet connection and the instance URL, then try again.",
+			map[string]interface{}{
+				"skill":    "post_mastodon",
+				"instance": instance,
+				"error":    err.Error(),
+			},
+		), nil
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(respBody, &apiErr)
+		message := fmt.Sprintf("Mastodon returned error (status %d)", resp.StatusCode)
+		if apiErr.Error != "" {
+			message += ": " + apiErr.Error
+		}
+		return formatErrorResponse(
+			"api_error",
+			message,
+			"Check that the access token is valid and has the write:statuses scope.",
+			map[string]interface{}{
+				"skill":       "post_mastodon",
+				"status_code": resp.StatusCode,
+				"response":    string(respBody),
+			},
+		), nil
+	}
+
+	var posted struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(respBody, &posted); err != nil {
+		return formatErrorResponse(
+			"api_error",
+			"Failed to parse Mastodon response",
+			"The status may have been posted; check the account before retrying.",
+			map[string]interface{}{
+				"skill": "post_mastodon",
+				"error": err.Error(),
+			},
+		), nil
+	}
+	url := posted.URL
+	if url == "" {
+		url = posted.URI
+	}
+
+	return map[string]interface{}{
+		"success":     true,
+		"status_code": resp.StatusCode,
+		"status_id":   posted.ID,
+		"url":         url,
+		"visibility":  visibility,
+		"length":      length,
+		"limit":       limit,
+	}, nil
+}
+
+// mastodonValidationError reports a status that can't be posted as given.
+func mastodonValidationError(message string, extra map[string]interface{}) map[string]interface{} {
+	details := map[string]interface{}{"skill": "post_mastodon"}
+	for k, v := range extra {
+		details[k] = v
+	}
+	return formatErrorResponse("validation_error", message, "Fix the status and try again.", details)
+}
+
+// mastodonMaxChars asks the instance for its status length limit, falling
+// back to Mastodon's default of 500. Mastodon reports it under
+// configuration.statuses; Pleroma and Akkoma use max_toot_chars.
+func mastodonMaxChars(ctx context.Context, instance string) int {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", instance+"/api/v1/instance", nil)
+	if err != nil {
+		return mastodonDefaultMaxChars
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+	resp, err := httpclient.New(0).Do(req)
+	if err != nil {
+		return mastodonDefaultMaxChars
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return mastodonDefaultMaxChars
+	}
+
+	var info struct {
+		MaxTootChars  int `json:"max_toot_chars"`
+		Configuration struct {
+			Statuses struct {
+				MaxCharacters int `json:"max_characters"`
+			} `json:"statuses"`
+		} `json:"configuration"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return mastodonDefaultMaxChars
+	}
+	switch {
+	case info.Configuration.Statuses.MaxCharacters > 0:
+		return info.Configuration.Statuses.MaxCharacters
+	case info.MaxTootChars > 0:
+		return info.MaxTootChars
+	}
+	return mastodonDefaultMaxChars
+}
+
+// mastodonStatusLength counts characters the way Mastodon does, with every
+// link counting as mastodonURLLength.
+func mastodonStatusLength(status string) int {
+	length := 0
+	rest := tweetURLPattern.ReplaceAllStringFunc(status, func(string) string {
+		length += mastodonURLLength
+		return ""
+	})
+	return length + utf8.RuneCountInString(rest)
+}
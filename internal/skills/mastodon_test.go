@@ -0,0 +1,107 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubMastodon serves /api/v1/instance with the given body (404 when empty)
+// and /api/v1/statuses, recording the statuses posted.
+func stubMastodon(t *testing.T, instance string) (*MockConfigLoader, *[]map[string]interface{}) {
+	t.Helper()
+	posted := []map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/instance":
+			if instance == "" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(instance))
+		case "/api/v1/statuses":
+			assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+			assert.NotEmpty(t, r.Header.Get("Idempotency-Key"))
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			posted = append(posted, body)
+			_, _ = w.Write([]byte(`{"id":"1101","url":"https://mastodon.example/@celeste/1101","uri":"https://mastodon.example/users/celeste/statuses/1101"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &MockConfigLoader{MastodonCfg: MastodonConfig{InstanceURL: server.URL + "/", AccessToken: "test-token"}}, &posted
+}
+
+func TestPostMastodonHandler(t *testing.T) {
+	loader, posted := stubMastodon(t, `{"configuration":{"statuses":{"max_characters":500}}}`)
+
+	result, err := PostMastodonHandler(context.Background(), map[string]interface{}{
+		"status":          "Stream starts in 10 minutes! https://twitch.tv/whykusanagi",
+		"content_warning": "horror game",
+		"visibility":      "unlisted",
+	}, loader)
+	require.NoError(t, err)
+
+	data := result.(map[string]interface{})
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, "https://mastodon.example/@celeste/1101", data["url"])
+	assert.Equal(t, 29+mastodonURLLength+11, data["length"], "the link counts as 23 and the warning counts too")
+
+	require.Len(t, *posted, 1)
+	assert.Equal(t, map[string]interface{}{
+		"status":       "Stream starts in 10 minutes! https://twitch.tv/whykusanagi",
+		"spoiler_text": "horror game",
+		"visibility":   "unlisted",
+	}, (*posted)[0])
+}
+
+func TestPostMastodonHandlerInstanceLimit(t *testing.T) {
+	long := map[string]interface{}{"status": strings.Repeat("a", 600)}
+
+	// The default limit applies when the instance doesn't say
+	loader, posted := stubMastodon(t, "")
+	result, err := PostMastodonHandler(context.Background(), long, loader)
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, "validation_error", data["error_type"])
+	assert.Equal(t, 500, data["limit"])
+	assert.Empty(t, *posted)
+
+	// Mastodon's configuration.statuses limit
+	loader, _ = stubMastodon(t, `{"configuration":{"statuses":{"max_characters":1000}}}`)
+	result, err = PostMastodonHandler(context.Background(), long, loader)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, result.(map[string]interface{})["limit"])
+
+	// Pleroma's max_toot_chars
+	loader, _ = stubMastodon(t, `{"max_toot_chars":5000}`)
+	result, err = PostMastodonHandler(context.Background(), long, loader)
+	require.NoError(t, err)
+	assert.Equal(t, 5000, result.(map[string]interface{})["limit"])
+}
+
+func TestPostMastodonHandlerValidation(t *testing.T) {
+	loader, posted := stubMastodon(t, "")
+
+	result, err := PostMastodonHandler(context.Background(), map[string]interface{}{"status": "hi", "visibility": "everyone"}, loader)
+	require.NoError(t, err)
+	assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+
+	result, err = PostMastodonHandler(context.Background(), map[string]interface{}{"status": "  "}, loader)
+	require.NoError(t, err)
+	assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+	assert.Empty(t, *posted)
+
+	result, err = PostMastodonHandler(context.Background(), map[string]interface{}{"status": "hi"}, NewMockConfigLoaderWithErrors())
+	require.NoError(t, err)
+	assert.Equal(t, "config_error", result.(map[string]interface{})["error_type"])
+}
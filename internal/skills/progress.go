@@ -0,0 +1,27 @@
+// Package skills provides the skill registry and execution system.
+// This file lets long-running skills report what they are doing.
+package skills
+
+import "context"
+
+// ProgressFunc receives a running skill's intermediate status, such as
+// "fetching forecast". It may be called from the skill's goroutine.
+type ProgressFunc func(status string)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose skills report progress to fn. The
+// context passes through the executor to context-aware handlers.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress sends status to the context's ProgressFunc, if it has one.
+func ReportProgress(ctx context.Context, status string) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(status)
+	}
+}
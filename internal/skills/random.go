@@ -0,0 +1,358 @@
+package skills
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Limits that keep dice rolls and random picks to sizes a person would ask
+// for, rather than whatever an LLM hallucinates.
+const (
+	maxDice        = 1000
+	maxDiceSides   = 10000
+	maxDiceBonus   = 1000000
+	maxRandomPicks = 1000
+)
+
+// DiceSpec is parsed dice notation such as "4d8kh3+2".
+type DiceSpec struct {
+	Count    int    // Number of dice
+	Sides    int    // Sides per die
+	Keep     int    // Dice kept, 0 to keep them all
+	KeepLow  bool   // Keep the lowest dice instead of the highest
+	Modifier int    // Sum of the +/- modifiers
+	Notation string // Normalized notation, e.g. "4d8kh3+2"
+}
+
+// parseDice parses dice notation: an optional count, "d", the number of
+// sides, an optional keep suffix ("kh3" or "k3" keeps the highest 3, "kl1"
+// the lowest), then any number of "+N" or "-N" modifiers. Spaces and case
+// are ignored, so "2D20 + 3" is "2d20+3".
+func parseDice(notation string) (DiceSpec, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(notation), ""))
+	if s == "" {
+		return DiceSpec{}, fmt.Errorf("dice notation is empty")
+	}
+	pos := 0
+
+	// number reads the digits at pos, reporting whether there were any
+	number := func(what string) (int, bool, error) {
+		start := pos
+		for pos < len(s) && s[pos] >= '0' && s[pos] <= '9' {
+			pos++
+		}
+		if pos == start {
+			return 0, false, nil
+		}
+		n, err := strconv.Atoi(s[start:pos])
+		if err != nil || pos-start > 9 {
+			return 0, true, fmt.Errorf("%s %q is too large", what, s[start:pos])
+		}
+		return n, true, nil
+	}
+
+	spec := DiceSpec{Count: 1}
+	count, hasCount, err := number("dice count")
+	if err != nil {
+		return DiceSpec{}, err
+	}
+	if hasCount {
+		spec.Count = count
+	}
+	if pos >= len(s) || s[pos] != 'd' {
+		return DiceSpec{}, fmt.Errorf("%q is not dice notation: expected something like 2d20+3", notation)
+	}
+	pos++
+
+	sides, hasSides, err := number("sides")
+	if err != nil {
+		return DiceSpec{}, err
+	}
+	if !hasSides {
+		return DiceSpec{}, fmt.Errorf("%q is missing the number of sides after 'd'", notation)
+	}
+	spec.Sides = sides
+
+	if pos < len(s) && s[pos] == 'k' {
+		pos++
+		if pos < len(s) && (s[pos] == 'h' || s[pos] == 'l') {
+			spec.KeepLow = s[pos] == 'l'
+			pos++
+		}
+		keep, hasKeep, err := number("keep count")
+		if err != nil {
+			return DiceSpec{}, err
+		}
+		if !hasKeep {
+			return DiceSpec{}, fmt.Errorf("%q is missing how many dice to keep, e.g. kh3", notation)
+		}
+		if keep < 1 {
+			return DiceSpec{}, fmt.Errorf("keep at least 1 die")
+		}
+		spec.Keep = keep
+	}
+
+	for pos < len(s) {
+		sign := s[pos]
+		if sign != '+' && sign != '-' {
+			return DiceSpec{}, fmt.Errorf("unexpected %q in %q", s[pos:], notation)
+		}
+		pos++
+		n, hasN, err := number("modifier")
+		if err != nil {
+			return DiceSpec{}, err
+		}
+		if !hasN {
+			return DiceSpec{}, fmt.Errorf("%q has a %q without a number after it", notation, string(sign))
+		}
+		if sign == '-' {
+			n = -n
+		}
+		spec.Modifier += n
+		if spec.Modifier > maxDiceBonus || spec.Modifier < -maxDiceBonus {
+			return DiceSpec{}, fmt.Errorf("modifier must be between -%d and %d", maxDiceBonus, maxDiceBonus)
+		}
+	}
+
+	switch {
+	case spec.Count < 1:
+		return DiceSpec{}, fmt.Errorf("roll at least 1 die")
+	case spec.Count > maxDice:
+		return DiceSpec{}, fmt.Errorf("at most %d dice can be rolled at once, not %d", maxDice, spec.Count)
+	case spec.Sides < 1:
+		return DiceSpec{}, fmt.Errorf("dice need at least 1 side")
+	case spec.Sides > maxDiceSides:
+		return DiceSpec{}, fmt.Errorf("dice can have at most %d sides, not %d", maxDiceSides, spec.Sides)
+	case spec.Keep > spec.Count:
+		return DiceSpec{}, fmt.Errorf("can't keep %d of %d dice", spec.Keep, spec.Count)
+	}
+
+	spec.Notation = fmt.Sprintf("%dd%d", spec.Count, spec.Sides)
+	if spec.Keep > 0 {
+		direction := "kh"
+		if spec.KeepLow {
+			direction = "kl"
+		}
+		spec.Notation += direction + strconv.Itoa(spec.Keep)
+	}
+	if spec.Modifier != 0 {
+		spec.Notation += fmt.Sprintf("%+d", spec.Modifier)
+	}
+	return spec, nil
+}
+
+// DiceResult is the outcome of a roll.
+type DiceResult struct {
+	Notation string `json:"notation"`
+	Rolls    []int  `json:"rolls"`          // Every die, in the order rolled
+	Kept     []int  `json:"kept,omitempty"` // The dice counted, when some were dropped
+	Modifier int    `json:"modifier"`
+	Total    int    `json:"total"`
+}
+
+// roll rolls the dice with intn, which returns a uniform int in [0, n).
+func (d DiceSpec) roll(intn func(int) (int, error)) (DiceResult, error) {
+	result := DiceResult{Notation: d.Notation, Rolls: make([]int, d.Count), Modifier: d.Modifier}
+	for i := range result.Rolls {
+		n, err := intn(d.Sides)
+		if err != nil {
+			return DiceResult{}, err
+		}
+		result.Rolls[i] = n + 1
+	}
+
+	counted := result.Rolls
+	if d.Keep > 0 {
+		// Keep the highest (or lowest) dice, in the order they were rolled
+		order := make([]int, d.Count)
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			if d.KeepLow {
+				return result.Rolls[order[a]] < result.Rolls[order[b]]
+			}
+			return result.Rolls[order[a]] > result.Rolls[order[b]]
+		})
+		kept := order[:d.Keep]
+		sort.Ints(kept)
+		result.Kept = make([]int, 0, d.Keep)
+		for _, i := range kept {
+			result.Kept = append(result.Kept, result.Rolls[i])
+		}
+		counted = result.Kept
+	}
+
+	result.Total = d.Modifier
+	for _, n := range counted {
+		result.Total += n
+	}
+	return result, nil
+}
+
+// RollDiceSkill returns the dice roll skill definition.
+func RollDiceSkill() Skill {
+	return Skill{
+		Name:        "roll_dice",
+		Description: "Roll dice using standard dice notation and return each die, the dice kept and the total. Always use this instead of making up numbers.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"notation": map[string]interface{}{
+					"type":        "string",
+					"description": "Dice notation, e.g. 'd6', '2d20+3', '4d6kh3' (keep highest 3) or '2d20kl1' (keep lowest 1). At most 1000 dice of up to 10000 sides.",
+				},
+			},
+			"required": []string{"notation"},
+		},
+	}
+}
+
+// PickRandomSkill returns the random choice skill definition.
+func PickRandomSkill() Skill {
+	return Skill{
+		Name:        "pick_random",
+		Description: "Pick one or more options at random, e.g. which game to play next. Always use this instead of choosing yourself when asked to pick randomly.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"options": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "The options to choose from",
+				},
+				"count": map[string]interface{}{
+					"type":        "integer",
+					"description": "How many to pick (default: 1)",
+				},
+				"unique": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Never pick the same option twice (default: true)",
+				},
+			},
+			"required": []string{"options"},
+		},
+	}
+}
+
+// RollDiceHandler rolls dice given in dice notation.
+func RollDiceHandler(args map[string]interface{}) (interface{}, error) {
+	notation, _ := args["notation"].(string)
+	spec, err := parseDice(notation)
+	if err != nil {
+		return formatErrorResponse(
+			"validation_error",
+			fmt.Sprintf("Invalid dice notation: %v", err),
+			"Use standard dice notation such as 'd6', '2d20+3' or '4d8kh3', with at most 1000 dice of up to 10000 sides.",
+			map[string]interface{}{
+				"skill": "roll_dice",
+				"field": "notation",
+			},
+		), nil
+	}
+
+	result, err := spec.roll(randomIntn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll dice: %w", err)
+	}
+	return result, nil
+}
+
+// PickRandomHandler picks from a list of options.
+func PickRandomHandler(args map[string]interface{}) (interface{}, error) {
+	var options []string
+	switch v := args["options"].(type) {
+	case []interface{}:
+		for _, option := range v {
+			options = append(options, strings.TrimSpace(fmt.Sprint(option)))
+		}
+	case []string:
+		options = v
+	case string:
+		// From the command line: --options "Elden Ring, Hades, Celeste"
+		for _, option := range strings.Split(v, ",") {
+			if option = strings.TrimSpace(option); option != "" {
+				options = append(options, option)
+			}
+		}
+	}
+	if len(options) == 0 {
+		return formatErrorResponse(
+			"validation_error",
+			"The 'options' parameter is required",
+			"Provide a list of options to choose from.",
+			map[string]interface{}{
+				"skill": "pick_random",
+				"field": "options",
+			},
+		), nil
+	}
+
+	count := 1
+	if c, ok := args["count"].(float64); ok {
+		count = int(c)
+	}
+	unique := true
+	if u, ok := args["unique"].(bool); ok {
+		unique = u
+	}
+
+	var problem string
+	switch {
+	case count < 1 || count > maxRandomPicks:
+		problem = fmt.Sprintf("The 'count' parameter must be between 1 and %d", maxRandomPicks)
+	case unique && count > len(options):
+		problem = fmt.Sprintf("Can't pick %d different options from %d", count, len(options))
+	}
+	if problem != "" {
+		return formatErrorResponse(
+			"validation_error",
+			problem,
+			"Lower the count, add options, or set unique to false to allow repeats.",
+			map[string]interface{}{
+				"skill": "pick_random",
+				"field": "count",
+			},
+		), nil
+	}
+
+	picks, err := pickRandom(options, count, unique, randomIntn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pick options: %w", err)
+	}
+	result := map[string]interface{}{
+		"picks":   picks,
+		"options": len(options),
+		"unique":  unique,
+	}
+	if count == 1 {
+		result["pick"] = picks[0]
+	}
+	return result, nil
+}
+
+// pickRandom picks count options with intn, without repeats when unique.
+func pickRandom(options []string, count int, unique bool, intn func(int) (int, error)) ([]string, error) {
+	pool := append([]string(nil), options...)
+	picks := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if !unique {
+			j, err := intn(len(pool))
+			if err != nil {
+				return nil, err
+			}
+			picks = append(picks, pool[j])
+			continue
+		}
+		// Partial Fisher-Yates, as in drawLocalTarotSpread
+		j, err := intn(len(pool) - i)
+		if err != nil {
+			return nil, err
+		}
+		pool[i], pool[i+j] = pool[i+j], pool[i]
+		picks = append(picks, pool[i])
+	}
+	return picks, nil
+}
@@ -0,0 +1,203 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDice(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     DiceSpec
+	}{
+		{"d6", DiceSpec{Count: 1, Sides: 6, Notation: "1d6"}},
+		{"1d6", DiceSpec{Count: 1, Sides: 6, Notation: "1d6"}},
+		{"2d20", DiceSpec{Count: 2, Sides: 20, Notation: "2d20"}},
+		{"2d20+3", DiceSpec{Count: 2, Sides: 20, Modifier: 3, Notation: "2d20+3"}},
+		{"3d8-2", DiceSpec{Count: 3, Sides: 8, Modifier: -2, Notation: "3d8-2"}},
+		{"d20+5-2+1", DiceSpec{Count: 1, Sides: 20, Modifier: 4, Notation: "1d20+4"}},
+		{"d6+2-2", DiceSpec{Count: 1, Sides: 6, Notation: "1d6"}},
+		{"4d8kh3", DiceSpec{Count: 4, Sides: 8, Keep: 3, Notation: "4d8kh3"}},
+		{"4d6k3", DiceSpec{Count: 4, Sides: 6, Keep: 3, Notation: "4d6kh3"}},
+		{"2d20kl1", DiceSpec{Count: 2, Sides: 20, Keep: 1, KeepLow: true, Notation: "2d20kl1"}},
+		{"4d6kh3+2", DiceSpec{Count: 4, Sides: 6, Keep: 3, Modifier: 2, Notation: "4d6kh3+2"}},
+		{"2d6kh2", DiceSpec{Count: 2, Sides: 6, Keep: 2, Notation: "2d6kh2"}},
+		{" 2D20 + 3 ", DiceSpec{Count: 2, Sides: 20, Modifier: 3, Notation: "2d20+3"}},
+		{"4D8KH3", DiceSpec{Count: 4, Sides: 8, Keep: 3, Notation: "4d8kh3"}},
+		{"d1", DiceSpec{Count: 1, Sides: 1, Notation: "1d1"}},
+		{"1000d10000", DiceSpec{Count: 1000, Sides: 10000, Notation: "1000d10000"}},
+		{"d6+1000000", DiceSpec{Count: 1, Sides: 6, Modifier: 1000000, Notation: "1d6+1000000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := parseDice(tt.notation)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDiceErrors(t *testing.T) {
+	tests := []struct {
+		notation string
+		errPart  string
+	}{
+		{"", "empty"},
+		{"   ", "empty"},
+		{"20", "not dice notation"},
+		{"two d6", "not dice notation"},
+		{"d", "missing the number of sides"},
+		{"2d", "missing the number of sides"},
+		{"2d+3", "missing the number of sides"},
+		{"0d6", "at least 1 die"},
+		{"1001d6", "at most 1000 dice"},
+		{"d0", "at least 1 side"},
+		{"d10001", "at most 10000 sides"},
+		{"99999999999d6", "too large"},
+		{"d99999999999", "too large"},
+		{"4d6k", "missing how many dice to keep"},
+		{"4d6kh", "missing how many dice to keep"},
+		{"4d6kh0", "keep at least 1"},
+		{"2d6kh3", "can't keep 3 of 2"},
+		{"4d6kx3", "missing how many dice to keep"},
+		{"2d6+", "without a number"},
+		{"2d6-", "without a number"},
+		{"2d6++3", "without a number"},
+		{"2d6*2", "unexpected"},
+		{"2d6+3kh1", "unexpected"},
+		{"2d6 foo", "unexpected"},
+		{"-2d6", "not dice notation"},
+		{"d6+1000001", "modifier must be between"},
+		{"d6-1000001", "modifier must be between"},
+		{"d6+999999+2", "modifier must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			_, err := parseDice(tt.notation)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errPart)
+		})
+	}
+}
+
+// sequence returns an intn that yields values in order, ignoring n.
+func sequence(values ...int) func(int) (int, error) {
+	return func(int) (int, error) {
+		v := values[0]
+		values = values[1:]
+		return v, nil
+	}
+}
+
+func TestDiceRoll(t *testing.T) {
+	spec, err := parseDice("2d20+3")
+	require.NoError(t, err)
+	result, err := spec.roll(sequence(11, 4))
+	require.NoError(t, err)
+	assert.Equal(t, DiceResult{Notation: "2d20+3", Rolls: []int{12, 5}, Modifier: 3, Total: 20}, result)
+
+	// Kept dice stay in roll order; ties keep the earlier die
+	spec, err = parseDice("4d8kh3")
+	require.NoError(t, err)
+	result, err = spec.roll(sequence(1, 7, 1, 4))
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 8, 2, 5}, result.Rolls)
+	assert.Equal(t, []int{2, 8, 5}, result.Kept)
+	assert.Equal(t, 15, result.Total)
+
+	spec, err = parseDice("2d20kl1-1")
+	require.NoError(t, err)
+	result, err = spec.roll(sequence(17, 2))
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, result.Kept)
+	assert.Equal(t, 2, result.Total)
+}
+
+func TestRollDiceHandler(t *testing.T) {
+	result, err := RollDiceHandler(map[string]interface{}{"notation": "10d6+1"})
+	require.NoError(t, err)
+	roll := result.(DiceResult)
+	assert.Len(t, roll.Rolls, 10)
+	sum := 1
+	for _, n := range roll.Rolls {
+		assert.True(t, n >= 1 && n <= 6, "roll %d out of range", n)
+		sum += n
+	}
+	assert.Equal(t, sum, roll.Total)
+
+	result, err = RollDiceHandler(map[string]interface{}{"notation": "5000d6"})
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, true, data["error"])
+	assert.Equal(t, "validation_error", data["error_type"])
+	assert.Contains(t, data["message"], "at most 1000 dice")
+
+	result, err = RollDiceHandler(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+}
+
+func TestPickRandomHandler(t *testing.T) {
+	options := []interface{}{"Elden Ring", "Hades", "Celeste"}
+
+	result, err := PickRandomHandler(map[string]interface{}{"options": options})
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Contains(t, options, data["pick"])
+	assert.Len(t, data["picks"], 1)
+
+	// Unique picks never repeat
+	result, err = PickRandomHandler(map[string]interface{}{"options": options, "count": 3.0})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Elden Ring", "Hades", "Celeste"}, result.(map[string]interface{})["picks"])
+
+	// Repeats allowed can pick more than there are options
+	result, err = PickRandomHandler(map[string]interface{}{"options": options, "count": 5.0, "unique": false})
+	require.NoError(t, err)
+	assert.Len(t, result.(map[string]interface{})["picks"], 5)
+
+	// A comma-separated string, as from the command line
+	result, err = PickRandomHandler(map[string]interface{}{"options": "heads, tails"})
+	require.NoError(t, err)
+	assert.Contains(t, []string{"heads", "tails"}, result.(map[string]interface{})["pick"])
+}
+
+func TestPickRandomHandlerValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		field   string
+		message string
+	}{
+		{"no options", map[string]interface{}{}, "options", "required"},
+		{"empty options", map[string]interface{}{"options": []interface{}{}}, "options", "required"},
+		{"zero count", map[string]interface{}{"options": []interface{}{"a"}, "count": 0.0}, "count", "between 1 and 1000"},
+		{"huge count", map[string]interface{}{"options": []interface{}{"a"}, "count": 5000.0, "unique": false}, "count", "between 1 and 1000"},
+		{"more unique than options", map[string]interface{}{"options": []interface{}{"a", "b"}, "count": 3.0}, "count", "3 different options from 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := PickRandomHandler(tt.args)
+			require.NoError(t, err)
+			data := result.(map[string]interface{})
+			assert.Equal(t, "validation_error", data["error_type"])
+			assert.Equal(t, tt.field, data["field"])
+			assert.Contains(t, data["message"], tt.message)
+		})
+	}
+}
+
+func TestPickRandom(t *testing.T) {
+	options := []string{"a", "b", "c", "d"}
+
+	picks, err := pickRandom(options, 3, true, sequence(2, 2, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "d", "a"}, picks)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, options, "options aren't reordered")
+
+	picks, err = pickRandom(options, 3, false, sequence(1, 1, 3))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "b", "d"}, picks)
+}
@@ -0,0 +1,348 @@
+// Package skills provides the skill registry and execution system.
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// Skill represents a skill definition loaded from JSON.
+type Skill struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	Handler     string                 `json:"handler,omitempty"` // Not used in Go, skills are built-in
+
+	// NSFW marks skills that expose NSFW content or media generation.
+	// They are removed from the registry in safe mode.
+	NSFW bool `json:"nsfw,omitempty"`
+
+	// SideEffect marks skills that change something outside the
+	// conversation (reminders, notes, files, uploads, posts). An exchange
+	// that called one can't be retried or edited in chat.
+	SideEffect bool `json:"side_effect,omitempty"`
+}
+
+// Registry manages skill definitions and execution.
+// It is safe for concurrent use so skills can be reloaded while they run.
+type Registry struct {
+	mu        sync.RWMutex
+	skills    map[string]Skill
+	handlers  map[string]ContextSkillHandler
+	skillsDir string
+
+	// fileSkills holds definitions loaded from skillsDir, by name, so a
+	// reload can tell what changed on disk. skillFiles maps names to paths.
+	fileSkills map[string]Skill
+	skillFiles map[string]string
+
+	// builtins holds definitions registered in code. They take precedence
+	// over files with the same name and survive reloads.
+	builtins map[string]Skill
+
+	// unregistered names stay out of the registry across reloads.
+	unregistered map[string]bool
+
+	// safeMode keeps skills marked NSFW out of the registry, including
+	// ones registered or loaded after RemoveNSFWSkills.
+	safeMode bool
+
+	// defaults fill in parameters the model leaves out (skill_defaults)
+	defaults SkillDefaults
+}
+
+// SkillHandler is a function that executes a skill.
+type SkillHandler func(args map[string]interface{}) (interface{}, error)
+
+// ContextSkillHandler is a skill handler that receives the caller's context,
+// so network requests stop when the skill call is cancelled or times out.
+type ContextSkillHandler func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// NewRegistry creates a new skill registry.
+func NewRegistry() *Registry {
+	skillsDir := paths.Config("skills")
+
+	return &Registry{
+		skills:       make(map[string]Skill),
+		handlers:     make(map[string]ContextSkillHandler),
+		skillsDir:    skillsDir,
+		fileSkills:   make(map[string]Skill),
+		skillFiles:   make(map[string]string),
+		builtins:     make(map[string]Skill),
+		unregistered: make(map[string]bool),
+	}
+}
+
+// SetSkillsDir sets the directory to load skills from.
+func (r *Registry) SetSkillsDir(dir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.skillsDir = dir
+}
+
+// RegisterHandler registers a handler function for a skill.
+func (r *Registry) RegisterHandler(name string, handler SkillHandler) {
+	r.RegisterContextHandler(name, func(_ context.Context, args map[string]interface{}) (interface{}, error) {
+		return handler(args)
+	})
+}
+
+// RegisterContextHandler registers a context-aware handler function for a skill.
+func (r *Registry) RegisterContextHandler(name string, handler ContextSkillHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[name] = handler
+}
+
+// LoadSkills loads all skill definitions from the skills directory.
+func (r *Registry) LoadSkills() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Ensure directory exists
+	if err := os.MkdirAll(r.skillsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create skills directory: %w", err)
+	}
+
+	// Find all JSON files
+	files, err := filepath.Glob(filepath.Join(r.skillsDir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list skill files: %w", err)
+	}
+
+	// Load each skill file
+	for _, file := range files {
+		skill, err := readSkillFile(file)
+		if err != nil {
+			// Log warning but continue loading other skills
+			fmt.Fprintf(os.Stderr, "Warning: failed to load skill %s: %v\n", file, err)
+			continue
+		}
+		r.fileSkills[skill.Name] = skill
+		r.skillFiles[skill.Name] = file
+		if _, isBuiltin := r.builtins[skill.Name]; !isBuiltin && !r.hidden(skill) {
+			r.skills[skill.Name] = skill
+		}
+	}
+
+	return nil
+}
+
+// readSkillFile reads a single skill definition from a JSON file.
+func readSkillFile(path string) (Skill, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Skill{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var skill Skill
+	if err := json.Unmarshal(data, &skill); err != nil {
+		return Skill{}, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	if skill.Name == "" {
+		return Skill{}, fmt.Errorf("skill name is required")
+	}
+
+	return skill, nil
+}
+
+// RegisterSkill manually registers a skill definition.
+// Skills registered in code take precedence over skill files and are kept
+// when skills are reloaded from disk.
+func (r *Registry) RegisterSkill(skill Skill) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.builtins[skill.Name] = skill
+	delete(r.unregistered, skill.Name)
+	if !r.hidden(skill) {
+		r.skills[skill.Name] = skill
+	}
+}
+
+// hidden reports whether a skill is kept out of the registry: unregistered
+// by name, or marked NSFW while safe mode is on. Callers hold r.mu.
+func (r *Registry) hidden(skill Skill) bool {
+	return r.unregistered[skill.Name] || (r.safeMode && skill.NSFW)
+}
+
+// GetSkill returns a skill by name.
+func (r *Registry) GetSkill(name string) (Skill, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	skill, ok := r.skills[name]
+	return skill, ok
+}
+
+// GetAllSkills returns all registered skills.
+func (r *Registry) GetAllSkills() []Skill {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	skills := make([]Skill, 0, len(r.skills))
+	for _, skill := range r.skills {
+		skills = append(skills, skill)
+	}
+	return skills
+}
+
+// GetToolDefinitions returns skills in OpenAI tool format for API calls.
+func (r *Registry) GetToolDefinitions() []map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tools := make([]map[string]interface{}, 0, len(r.skills))
+	for _, skill := range r.skills {
+		tool := map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        skill.Name,
+				"description": skill.Description,
+				"parameters":  skill.Parameters,
+			},
+		}
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// Execute runs a skill by name with the given arguments.
+func (r *Registry) Execute(name string, args map[string]interface{}) (interface{}, error) {
+	return r.ExecuteContext(context.Background(), name, args)
+}
+
+// ExecuteContext runs a skill by name, passing ctx to its handler.
+func (r *Registry) ExecuteContext(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	r.mu.RLock()
+	_, skillOK := r.skills[name]
+	handler, handlerOK := r.handlers[name]
+	r.mu.RUnlock()
+
+	// Check if skill exists
+	if !skillOK {
+		return nil, fmt.Errorf("skill not found: %s", name)
+	}
+
+	// Check if handler exists
+	if !handlerOK {
+		return nil, fmt.Errorf("no handler for skill: %s", name)
+	}
+
+	// Execute handler
+	return handler(ctx, args)
+}
+
+// HasHandler checks if a skill has a registered handler.
+func (r *Registry) HasHandler(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.handlers[name]
+	return ok
+}
+
+// SaveSkill saves a skill definition to the skills directory.
+func (r *Registry) SaveSkill(skill Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Ensure directory exists
+	if err := os.MkdirAll(r.skillsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create skills directory: %w", err)
+	}
+
+	// Marshal to JSON
+	data, err := json.MarshalIndent(skill, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal skill: %w", err)
+	}
+
+	// Write file
+	filename := strings.ReplaceAll(skill.Name, " ", "_") + ".json"
+	path := filepath.Join(r.skillsDir, filename)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write skill file: %w", err)
+	}
+
+	// Register the skill
+	if !r.hidden(skill) {
+		r.skills[skill.Name] = skill
+	}
+	r.fileSkills[skill.Name] = skill
+	r.skillFiles[skill.Name] = path
+	return nil
+}
+
+// UnregisterSkill removes a skill and its handler from the registry
+// without touching its file on disk.
+func (r *Registry) UnregisterSkill(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.skills, name)
+	delete(r.handlers, name)
+	delete(r.builtins, name)
+	r.unregistered[name] = true
+}
+
+// NSFWSkillNames returns the sorted names of registered skills marked NSFW,
+// which must not be offered to the LLM when safe mode is active.
+func (r *Registry) NSFWSkillNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var names []string
+	for name, skill := range r.skills {
+		if skill.NSFW {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RemoveNSFWSkills turns on safe mode: every skill marked NSFW is removed
+// along with its handler, and NSFW skills registered or loaded later are
+// ignored.
+func (r *Registry) RemoveNSFWSkills() {
+	for _, name := range r.NSFWSkillNames() {
+		r.UnregisterSkill(name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.safeMode = true
+}
+
+// DeleteSkill removes a skill from the registry and deletes its file.
+func (r *Registry) DeleteSkill(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Remove from registry
+	delete(r.skills, name)
+	delete(r.handlers, name)
+	delete(r.builtins, name)
+	delete(r.fileSkills, name)
+	delete(r.skillFiles, name)
+
+	// Delete file if exists
+	filename := strings.ReplaceAll(name, " ", "_") + ".json"
+	path := filepath.Join(r.skillsDir, filename)
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to delete skill file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Count returns the number of registered skills.
+func (r *Registry) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.skills)
+}
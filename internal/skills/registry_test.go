@@ -1,7 +1,14 @@
 package skills
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -235,11 +242,12 @@ func TestBuiltinSkillsRegistration(t *testing.T) {
 	// Register builtin skills
 	RegisterBuiltinSkills(registry, mockConfig)
 
-	// List expected skill names (22 active skills)
+	// List expected skill names (32 active skills)
 	// Note: nsfw_mode, generate_content, generate_image are disabled (unimplemented)
 	expectedSkills := []string{
 		"tarot_reading",
 		"get_weather",
+		"get_location",
 		"convert_units",
 		"convert_timezone",
 		"generate_hash",
@@ -248,14 +256,24 @@ func TestBuiltinSkillsRegistration(t *testing.T) {
 		"generate_uuid",
 		"generate_password",
 		"convert_currency",
+		"get_price",
 		"generate_qr_code",
 		"check_twitch_live",
 		"get_youtube_videos",
+		"post_discord",
+		"post_tweet",
+		"post_mastodon",
+		"read_feed",
 		"set_reminder",
 		"list_reminders",
 		"save_note",
 		"get_note",
 		"list_notes",
+		"roll_dice",
+		"pick_random",
+		"analyze_text",
+		"format_json",
+		"generate_image",
 		"ipfs",
 		"alchemy",
 		"blockmon",
@@ -394,6 +412,66 @@ func TestDeleteSkill(t *testing.T) {
 	assert.NoError(t, err, "deleting non-existent skill should not error (silently succeeds)")
 }
 
+// TestRemoveNSFWSkills tests that safe mode strips every skill marked NSFW
+// from the tool definitions offered to the LLM, including ones loaded later
+func TestRemoveNSFWSkills(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spicy.json"),
+		[]byte(`{"name":"spicy","description":"custom NSFW skill","nsfw":true}`), 0644))
+
+	registry := NewRegistry()
+	registry.SetSkillsDir(dir)
+	RegisterBuiltinSkills(registry, NewMockConfigLoader())
+	require.NoError(t, registry.LoadSkills())
+	registry.RegisterSkill(Skill{Name: "render", Description: "media skill", NSFW: true})
+	registry.RegisterHandler("render", func(args map[string]interface{}) (interface{}, error) {
+		return "generated", nil
+	})
+
+	nsfw := registry.NSFWSkillNames()
+	require.Contains(t, nsfw, "generate_image")
+	require.Contains(t, nsfw, "spicy")
+	require.Contains(t, nsfw, "render")
+	safeCount := registry.Count() - len(nsfw)
+
+	registry.RemoveNSFWSkills()
+
+	assert.Empty(t, registry.NSFWSkillNames())
+	for _, tool := range registry.GetToolDefinitions() {
+		name := tool["function"].(map[string]interface{})["name"]
+		assert.NotContains(t, nsfw, name, "%s should be removed in safe mode", name)
+	}
+	assert.False(t, registry.HasHandler("render"), "NSFW handlers should be removed in safe mode")
+	assert.False(t, registry.HasHandler("generate_image"), "NSFW handlers should be removed in safe mode")
+	assert.Equal(t, safeCount, registry.Count(), "regular skills should be untouched")
+
+	// NSFW skills added after safe mode is on stay out too
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lewd.json"),
+		[]byte(`{"name":"lewd","description":"another NSFW skill","nsfw":true}`), 0644))
+	_, err := registry.Reload()
+	require.NoError(t, err)
+	registry.RegisterSkill(Skill{Name: "render", Description: "media skill", NSFW: true})
+	assert.Empty(t, registry.NSFWSkillNames())
+	assert.Equal(t, safeCount, registry.Count())
+}
+
+// TestSideEffectSkills tests which built-in skills change things outside
+// the conversation, so chat won't retry or edit an exchange that ran them
+func TestSideEffectSkills(t *testing.T) {
+	registry := NewRegistry()
+	RegisterBuiltinSkills(registry, NewMockConfigLoader())
+
+	var names []string
+	for _, skill := range registry.GetAllSkills() {
+		if skill.SideEffect {
+			names = append(names, skill.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"generate_image", "generate_qr_code", "ipfs", "post_discord", "post_mastodon", "post_tweet", "save_note", "set_reminder", "wallet_security",
+	}, names)
+}
+
 // TestCount tests skill counting
 func TestCount(t *testing.T) {
 	registry := NewRegistry()
@@ -506,3 +584,79 @@ func TestSetSkillsDir(t *testing.T) {
 		registry.SetSkillsDir(customDir)
 	}, "setting skills directory should not panic")
 }
+
+// TestExecuteContextPassesContext tests that context-aware handlers receive the caller's context
+func TestExecuteContextPassesContext(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterSkill(Skill{Name: "ctx_skill"})
+
+	type key struct{}
+	registry.RegisterContextHandler("ctx_skill", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return ctx.Value(key{}), nil
+	})
+
+	ctx := context.WithValue(context.Background(), key{}, "from caller")
+	result, err := registry.ExecuteContext(ctx, "ctx_skill", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "from caller", result)
+}
+
+// slowServer never answers until the client goes away.
+func slowServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reading the body lets the server notice when the client hangs up
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestExecutorCancelsSlowRequest tests that cancelling the executor's context
+// aborts a handler's in-flight HTTP request
+func TestExecutorCancelsSlowRequest(t *testing.T) {
+	server := slowServer(t)
+	t.Setenv("HOME", t.TempDir())
+	original := youtubeAPIBaseURL
+	youtubeAPIBaseURL = server.URL
+	t.Cleanup(func() { youtubeAPIBaseURL = original })
+
+	registry := NewRegistry()
+	RegisterBuiltinSkills(registry, &MockConfigLoader{YouTubeCfg: YouTubeConfig{APIKey: "test-key"}})
+	executor := NewExecutor(registry)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	result, err := executor.Execute(ctx, "get_youtube_videos", `{"channel": "@slow"}`)
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Less(t, elapsed, 2*time.Second, "handler should stop as soon as the context is cancelled")
+	data := result.Result.(map[string]interface{})
+	assert.Equal(t, "cancelled", data["error_type"])
+}
+
+// TestTarotHandlerTimeoutSkipsLocalDeck tests that a timed-out tarot call
+// reports the timeout instead of drawing a local reading
+func TestTarotHandlerTimeoutSkipsLocalDeck(t *testing.T) {
+	server := slowServer(t)
+	loader := &MockConfigLoader{TarotCfg: TarotConfig{FunctionURL: server.URL, AuthToken: "token"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := TarotHandler(ctx, map[string]interface{}{"spread_type": "three"}, loader)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	data := result.(map[string]interface{})
+	assert.Equal(t, "timeout", data["error_type"])
+	assert.NotContains(t, data, "source")
+}
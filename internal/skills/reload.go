@@ -0,0 +1,184 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce groups bursts of file events (editors often write a file
+// several times per save) into a single reload.
+const reloadDebounce = 300 * time.Millisecond
+
+// ReloadResult describes what changed when skills were reloaded from disk.
+type ReloadResult struct {
+	Added   []string
+	Removed []string
+	Changed []string
+
+	// Errors maps skill files that could not be loaded to the reason.
+	Errors map[string]string
+}
+
+// HasChanges reports whether the reload changed anything or hit errors.
+func (r ReloadResult) HasChanges() bool {
+	return len(r.Added)+len(r.Removed)+len(r.Changed)+len(r.Errors) > 0
+}
+
+// Summary renders the reload result for display in chat.
+func (r ReloadResult) Summary() string {
+	if !r.HasChanges() {
+		return "🔄 Skills reloaded - no changes"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🔄 Skills reloaded")
+	if len(r.Added) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  + Added: %s", strings.Join(r.Added, ", ")))
+	}
+	if len(r.Changed) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  ~ Changed: %s", strings.Join(r.Changed, ", ")))
+	}
+	if len(r.Removed) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  - Removed: %s", strings.Join(r.Removed, ", ")))
+	}
+	if len(r.Errors) > 0 {
+		files := make([]string, 0, len(r.Errors))
+		for file := range r.Errors {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			sb.WriteString(fmt.Sprintf("\n  ⚠️ %s: %s", filepath.Base(file), r.Errors[file]))
+		}
+	}
+	return sb.String()
+}
+
+// Reload re-reads the skills directory and applies the differences to the
+// registry. Skills registered in code (and their handlers) are never
+// dropped, and a malformed file is reported without aborting the reload.
+func (r *Registry) Reload() (ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := ReloadResult{Errors: make(map[string]string)}
+
+	files, err := filepath.Glob(filepath.Join(r.skillsDir, "*.json"))
+	if err != nil {
+		return result, fmt.Errorf("failed to list skill files: %w", err)
+	}
+
+	loaded := make(map[string]Skill, len(files))
+	paths := make(map[string]string, len(files))
+	for _, file := range files {
+		skill, err := readSkillFile(file)
+		if err != nil {
+			result.Errors[file] = err.Error()
+			continue
+		}
+		loaded[skill.Name] = skill
+		paths[skill.Name] = file
+	}
+
+	for name, skill := range loaded {
+		old, existed := r.fileSkills[name]
+		r.fileSkills[name] = skill
+		r.skillFiles[name] = paths[name]
+		if _, isBuiltin := r.builtins[name]; isBuiltin || r.hidden(skill) {
+			continue
+		}
+		r.skills[name] = skill
+		switch {
+		case !existed:
+			result.Added = append(result.Added, name)
+		case !reflect.DeepEqual(old, skill):
+			result.Changed = append(result.Changed, name)
+		}
+	}
+
+	for name := range r.fileSkills {
+		if _, ok := loaded[name]; ok {
+			continue
+		}
+		// Keep the previous definition while its file is broken
+		if _, broken := result.Errors[r.skillFiles[name]]; broken {
+			continue
+		}
+		old := r.fileSkills[name]
+		delete(r.fileSkills, name)
+		delete(r.skillFiles, name)
+		if _, isBuiltin := r.builtins[name]; isBuiltin || r.hidden(old) {
+			continue
+		}
+		delete(r.skills, name)
+		result.Removed = append(result.Removed, name)
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Changed)
+	sort.Strings(result.Removed)
+	return result, nil
+}
+
+// Watch reloads skills whenever a JSON file in the skills directory changes,
+// calling onReload with each result. It blocks until ctx is cancelled.
+func (r *Registry) Watch(ctx context.Context, onReload func(ReloadResult, error)) error {
+	r.mu.RLock()
+	dir := r.skillsDir
+	r.mu.RUnlock()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create skills directory: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start skills watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	var timer *time.Timer
+	var fire <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Ext(event.Name) != ".json" {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(reloadDebounce)
+			} else {
+				timer.Reset(reloadDebounce)
+			}
+			fire = timer.C
+		case <-fire:
+			fire = nil
+			result, err := r.Reload()
+			if err != nil || result.HasChanges() {
+				onReload(result, err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			onReload(ReloadResult{}, err)
+		}
+	}
+}
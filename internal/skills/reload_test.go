@@ -0,0 +1,151 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSkillFile(t *testing.T, dir, file string, skill Skill) {
+	t.Helper()
+	data, err := json.Marshal(skill)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, file), data, 0644))
+}
+
+func newReloadRegistry(t *testing.T) (*Registry, string) {
+	t.Helper()
+	dir := t.TempDir()
+	registry := NewRegistry()
+	registry.SetSkillsDir(dir)
+
+	writeSkillFile(t, dir, "greet.json", Skill{Name: "greet", Description: "Say hello"})
+	// A file that shadows a built-in skill
+	writeSkillFile(t, dir, "generate_uuid.json", Skill{Name: "generate_uuid", Description: "From disk"})
+
+	require.NoError(t, registry.LoadSkills())
+	registry.RegisterSkill(UUIDGeneratorSkill())
+	registry.RegisterHandler("generate_uuid", func(args map[string]interface{}) (interface{}, error) {
+		return "uuid", nil
+	})
+	return registry, dir
+}
+
+func TestReloadAddModifyDelete(t *testing.T) {
+	registry, dir := newReloadRegistry(t)
+
+	t.Run("no changes", func(t *testing.T) {
+		result, err := registry.Reload()
+		require.NoError(t, err)
+		assert.False(t, result.HasChanges())
+		assert.Contains(t, result.Summary(), "no changes")
+	})
+
+	t.Run("add", func(t *testing.T) {
+		writeSkillFile(t, dir, "farewell.json", Skill{Name: "farewell", Description: "Say goodbye"})
+		result, err := registry.Reload()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"farewell"}, result.Added)
+		_, ok := registry.GetSkill("farewell")
+		assert.True(t, ok)
+	})
+
+	t.Run("modify", func(t *testing.T) {
+		writeSkillFile(t, dir, "greet.json", Skill{Name: "greet", Description: "Say hello loudly"})
+		result, err := registry.Reload()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"greet"}, result.Changed)
+		skill, _ := registry.GetSkill("greet")
+		assert.Equal(t, "Say hello loudly", skill.Description)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "farewell.json")))
+		result, err := registry.Reload()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"farewell"}, result.Removed)
+		_, ok := registry.GetSkill("farewell")
+		assert.False(t, ok)
+	})
+
+	t.Run("built-in survives", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "generate_uuid.json")))
+		result, err := registry.Reload()
+		require.NoError(t, err)
+		assert.Empty(t, result.Removed)
+
+		skill, ok := registry.GetSkill("generate_uuid")
+		require.True(t, ok)
+		assert.Equal(t, UUIDGeneratorSkill().Description, skill.Description)
+		out, err := registry.Execute("generate_uuid", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "uuid", out)
+	})
+}
+
+func TestReloadBrokenFile(t *testing.T) {
+	registry, dir := newReloadRegistry(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "greet.json"), []byte(`{"name": "greet",`), 0644))
+	writeSkillFile(t, dir, "farewell.json", Skill{Name: "farewell", Description: "Say goodbye"})
+
+	result, err := registry.Reload()
+	require.NoError(t, err)
+
+	// The good file still loads and the broken one is reported by path
+	assert.Equal(t, []string{"farewell"}, result.Added)
+	assert.Contains(t, result.Errors, filepath.Join(dir, "greet.json"))
+	assert.Contains(t, result.Summary(), "greet.json")
+
+	// The last good definition is kept until the file is fixed
+	skill, ok := registry.GetSkill("greet")
+	require.True(t, ok)
+	assert.Equal(t, "Say hello", skill.Description)
+}
+
+func TestReloadKeepsUnregisteredSkillsOut(t *testing.T) {
+	registry, dir := newReloadRegistry(t)
+	registry.UnregisterSkill("greet")
+
+	writeSkillFile(t, dir, "greet.json", Skill{Name: "greet", Description: "Changed"})
+	_, err := registry.Reload()
+	require.NoError(t, err)
+
+	_, ok := registry.GetSkill("greet")
+	assert.False(t, ok)
+}
+
+func TestWatchReloadsOnChange(t *testing.T) {
+	registry, dir := newReloadRegistry(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make(chan ReloadResult, 1)
+	ready := make(chan struct{})
+	go func() {
+		close(ready)
+		_ = registry.Watch(ctx, func(result ReloadResult, err error) {
+			if err == nil {
+				results <- result
+			}
+		})
+	}()
+	<-ready
+	time.Sleep(100 * time.Millisecond) // let the watcher register the directory
+
+	writeSkillFile(t, dir, "watched.json", Skill{Name: "watched", Description: "Picked up by the watcher"})
+
+	select {
+	case result := <-results:
+		assert.Equal(t, []string{"watched"}, result.Added)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not reload skills")
+	}
+}
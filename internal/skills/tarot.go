@@ -0,0 +1,309 @@
+package skills
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// TarotCard is one card of a reading, in the tarot function's response shape.
+type TarotCard struct {
+	Position    string `json:"position"`
+	Name        string `json:"name"`
+	Meaning     string `json:"meaning"`
+	Orientation string `json:"orientation"` // "upright" or "reversed"
+}
+
+// TarotReading is a drawn spread, from the tarot function or the local deck.
+type TarotReading struct {
+	SpreadName string      `json:"spread_name"`
+	SpreadType string      `json:"spread_type"`
+	Cards      []TarotCard `json:"cards"`
+}
+
+// String renders the reading as a numbered list, one card per line.
+func (r TarotReading) String() string {
+	var b strings.Builder
+	b.WriteString(r.SpreadName)
+	for i, card := range r.Cards {
+		fmt.Fprintf(&b, "\n%d. %s: %s (%s)", i+1, card.Position, card.Name, card.Orientation)
+		if card.Meaning != "" {
+			fmt.Fprintf(&b, " - %s", card.Meaning)
+		}
+	}
+	return b.String()
+}
+
+// result builds the tarot_reading skill result for the reading, with the
+// drawn layout for spreads that have one.
+func (r TarotReading) result(source, question string) map[string]interface{} {
+	result := map[string]interface{}{
+		"source":      source,
+		"spread_name": r.SpreadName,
+		"spread_type": r.SpreadType,
+		"cards":       r.Cards,
+		"summary":     r.String(),
+	}
+	if question != "" {
+		result["question"] = question
+	}
+	layout, warning := r.Layout()
+	if layout != "" {
+		result["layout"] = layout
+	}
+	if warning != "" {
+		result["layout_warning"] = warning
+	}
+	return result
+}
+
+// TarotSpreads lists the supported spread types, the default first.
+var TarotSpreads = []string{"three", "single", "horseshoe", "celtic"}
+
+// tarotSpreadNames are display names for spreads the function doesn't name.
+var tarotSpreadNames = map[string]string{
+	"single":    "Card of the Day",
+	"three":     "Three Card Spread",
+	"horseshoe": "Horseshoe Spread",
+	"celtic":    "Celtic Cross",
+}
+
+// validateTarotSpread checks spreadType against the supported spreads.
+func validateTarotSpread(spreadType string) error {
+	if _, ok := tarotSpreadPositions[spreadType]; !ok {
+		return fmt.Errorf("unknown spread type %q (use %s)", spreadType, strings.Join(TarotSpreads, ", "))
+	}
+	return nil
+}
+
+// decodeTarotReading decodes a tarot function response. It fails with a
+// description of what is wrong when the response doesn't have the expected
+// shape, rather than letting a renamed or missing field through.
+func decodeTarotReading(data []byte, spreadType string) (TarotReading, error) {
+	var reading TarotReading
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&reading); err != nil {
+		return TarotReading{}, fmt.Errorf("unexpected tarot response shape: %w", err)
+	}
+	if len(reading.Cards) == 0 {
+		return TarotReading{}, fmt.Errorf("tarot response has no cards (fields: %s)", responseFields(data, -1))
+	}
+
+	for i := range reading.Cards {
+		card := &reading.Cards[i]
+		switch {
+		case card.Name == "":
+			return TarotReading{}, fmt.Errorf("tarot card %d has no name (fields: %s)", i+1, responseFields(data, i))
+		case card.Position == "":
+			return TarotReading{}, fmt.Errorf("tarot card %d (%s) has no position (fields: %s)", i+1, card.Name, responseFields(data, i))
+		}
+		card.Orientation = strings.ToLower(card.Orientation)
+		if card.Orientation != "upright" && card.Orientation != "reversed" {
+			return TarotReading{}, fmt.Errorf("tarot card %d (%s) has orientation %q, want upright or reversed", i+1, card.Name, card.Orientation)
+		}
+	}
+
+	if reading.SpreadType == "" {
+		reading.SpreadType = spreadType
+	}
+	if reading.SpreadName == "" {
+		reading.SpreadName = tarotSpreadNames[reading.SpreadType]
+	}
+	return reading, nil
+}
+
+// responseFields lists the field names of the response (card < 0) or of one
+// of its cards, for error messages.
+func responseFields(data []byte, card int) string {
+	var raw struct {
+		Cards []map[string]json.RawMessage `json:"cards"`
+	}
+	var top map[string]json.RawMessage
+	_ = json.Unmarshal(data, &top)
+	fields := top
+	if card >= 0 && json.Unmarshal(data, &raw) == nil && card < len(raw.Cards) {
+		fields = raw.Cards[card]
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// deckCard is one card of the local deck with short upright/reversed meanings.
+type deckCard struct {
+	Name     string
+	Upright  string
+	Reversed string
+}
+
+// majorArcana holds the 22 trump cards in deck order.
+var majorArcana = []deckCard{
+	{Name: "The Fool", Upright: "beginnings, spontaneity, a leap of faith", Reversed: "recklessness, hesitation, poor judgement"},
+	{Name: "The Magician", Upright: "willpower, skill, manifestation", Reversed: "manipulation, untapped talent, trickery"},
+	{Name: "The High Priestess", Upright: "intuition, mystery, the subconscious", Reversed: "secrets, disconnection from intuition"},
+	{Name: "The Empress", Upright: "abundance, nurturing, creativity", Reversed: "dependence, creative block, smothering"},
+	{Name: "The Emperor", Upright: "authority, structure, control", Reversed: "rigidity, domination, lack of discipline"},
+	{Name: "The Hierophant", Upright: "tradition, guidance, institutions", Reversed: "rebellion, nonconformity, new approaches"},
+	{Name: "The Lovers", Upright: "love, harmony, meaningful choices", Reversed: "imbalance, misalignment, indecision"},
+	{Name: "The Chariot", Upright: "determination, victory, momentum", Reversed: "lack of direction, aggression, obstacles"},
+	{Name: "Strength", Upright: "courage, patience, inner strength", Reversed: "self-doubt, weakness, insecurity"},
+	{Name: "The Hermit", Upright: "introspection, solitude, inner guidance", Reversed: "isolation, loneliness, withdrawal"},
+	{Name: "Wheel of Fortune", Upright: "cycles, fate, turning points", Reversed: "bad luck, resistance to change"},
+	{Name: "Justice", Upright: "fairness, truth, cause and effect", Reversed: "injustice, dishonesty, avoiding accountability"},
+	{Name: "The Hanged Man", Upright: "surrender, new perspective, pause", Reversed: "stalling, resistance, needless sacrifice"},
+	{Name: "Death", Upright: "endings, transformation, transition", Reversed: "resisting change, stagnation"},
+	{Name: "Temperance", Upright: "balance, moderation, patience", Reversed: "excess, imbalance, haste"},
+	{Name: "The Devil", Upright: "attachment, temptation, shadow self", Reversed: "release, breaking free, reclaiming power"},
+	{Name: "The Tower", Upright: "upheaval, sudden revelation, collapse", Reversed: "averting disaster, fear of change"},
+	{Name: "The Star", Upright: "hope, renewal, inspiration", Reversed: "despair, disconnection, lost faith"},
+	{Name: "The Moon", Upright: "illusion, fear, intuition", Reversed: "clarity, released fear, truth revealed"},
+	{Name: "The Sun", Upright: "joy, success, vitality", Reversed: "temporary gloom, blocked happiness"},
+	{Name: "Judgement", Upright: "reckoning, awakening, renewal", Reversed: "self-doubt, refusing the call"},
+	{Name: "The World", Upright: "completion, fulfilment, wholeness", Reversed: "unfinished business, lack of closure"},
+}
+
+// tarotSuits maps each minor arcana suit to its domain.
+var tarotSuits = []struct {
+	Name   string
+	Domain string
+}{
+	{"Wands", "passion and ambition"},
+	{"Cups", "emotions and relationships"},
+	{"Swords", "thoughts and conflict"},
+	{"Pentacles", "work, money and the material world"},
+}
+
+// tarotRanks are the minor arcana ranks with upright/reversed keywords.
+var tarotRanks = []struct {
+	Name     string
+	Upright  string
+	Reversed string
+}{
+	{"Ace", "a new opportunity", "a missed or delayed start"},
+	{"Two", "balance and decisions", "indecision and imbalance"},
+	{"Three", "growth and collaboration", "setbacks in teamwork"},
+	{"Four", "stability and rest", "restlessness or stagnation"},
+	{"Five", "conflict and loss", "recovery and moving on"},
+	{"Six", "harmony and generosity", "imbalance in give and take"},
+	{"Seven", "challenge and perseverance", "giving up or overwhelm"},
+	{"Eight", "movement and progress", "delays and frustration"},
+	{"Nine", "near completion and resilience", "anxiety and last hurdles"},
+	{"Ten", "culmination and burden", "release from a heavy load"},
+	{"Page", "curiosity and new messages", "immaturity or bad news"},
+	{"Knight", "action and pursuit", "impulsiveness or inertia"},
+	{"Queen", "mastery through nurturing", "insecurity or coldness"},
+	{"King", "mastery through leadership", "control or misuse of power"},
+}
+
+// tarotSpreadPositions names each position of the supported spreads.
+var tarotSpreadPositions = map[string][]string{
+	"single":    {"Card of the Day"},
+	"three":     {"Past", "Present", "Future"},
+	"horseshoe": {"Past", "Present", "Hidden Influences", "Obstacles", "Outcome"},
+	"celtic": {
+		"Present", "Challenge", "Foundation", "Recent Past", "Crown",
+		"Near Future", "Self", "Environment", "Hopes and Fears", "Outcome",
+	},
+}
+
+// tarotDeck returns the full 78-card deck.
+func tarotDeck() []deckCard {
+	deck := make([]deckCard, 0, 78)
+	deck = append(deck, majorArcana...)
+	for _, suit := range tarotSuits {
+		for _, rank := range tarotRanks {
+			deck = append(deck, deckCard{
+				Name:     fmt.Sprintf("%s of %s", rank.Name, suit.Name),
+				Upright:  fmt.Sprintf("%s in %s", rank.Upright, suit.Domain),
+				Reversed: fmt.Sprintf("%s in %s", rank.Reversed, suit.Domain),
+			})
+		}
+	}
+	return deck
+}
+
+// randomIntn returns a uniform random int in [0, n) from crypto/rand.
+func randomIntn(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+// drawLocalTarotSpread draws a spread from the local deck without
+// replacement, giving each card a random orientation.
+func drawLocalTarotSpread(spreadType string) ([]TarotCard, error) {
+	if err := validateTarotSpread(spreadType); err != nil {
+		return nil, err
+	}
+	positions := tarotSpreadPositions[spreadType]
+
+	deck := tarotDeck()
+	cards := make([]TarotCard, 0, len(positions))
+	for i, position := range positions {
+		// Partial Fisher-Yates: swap a random remaining card into slot i
+		j, err := randomIntn(len(deck) - i)
+		if err != nil {
+			return nil, err
+		}
+		deck[i], deck[i+j] = deck[i+j], deck[i]
+
+		flip, err := randomIntn(2)
+		if err != nil {
+			return nil, err
+		}
+		card := TarotCard{
+			Position:    position,
+			Name:        deck[i].Name,
+			Meaning:     deck[i].Upright,
+			Orientation: "upright",
+		}
+		if flip == 1 {
+			card.Meaning = deck[i].Reversed
+			card.Orientation = "reversed"
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// localTarotReading builds a tarot_reading result from the local deck.
+// Used when the remote tarot function is unavailable.
+func localTarotReading(spreadType, question, reason string) (interface{}, error) {
+	spreadType = strings.ToLower(spreadType)
+	cards, err := drawLocalTarotSpread(spreadType)
+	if err != nil {
+		return formatErrorResponse(
+			"validation_error",
+			"Could not draw a local tarot spread",
+			"Use spread_type "+strings.Join(TarotSpreads, ", ")+".",
+			map[string]interface{}{
+				"skill":  "tarot_reading",
+				"field":  "spread_type",
+				"reason": err.Error(),
+			},
+		), nil
+	}
+
+	reading := TarotReading{
+		SpreadName: tarotSpreadNames[spreadType],
+		SpreadType: spreadType,
+		Cards:      cards,
+	}
+	result := reading.result("local", question)
+	if reason != "" {
+		result["fallback_reason"] = reason
+	}
+	return result, nil
+}
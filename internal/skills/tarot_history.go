@@ -0,0 +1,120 @@
+package skills
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+)
+
+// TarotHistoryEntry is one saved reading in tarot_history.json.
+type TarotHistoryEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Question  string    `json:"question,omitempty"`
+	Source    string    `json:"source"` // "remote" or "local"
+	TarotReading
+}
+
+// TarotCardCount is how often a card came up across saved readings.
+type TarotCardCount struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	Reversed int    `json:"reversed"` // How many of those were reversed
+}
+
+// TarotHistoryPath returns the file saved readings are kept in, in the
+// active profile's workspace.
+func TarotHistoryPath(configLoader ConfigLoader) string {
+	return filepath.Join(workspaceDir(configLoader), "tarot_history.json")
+}
+
+// LoadTarotHistory returns the saved readings, oldest first. A missing file
+// is an empty history.
+func LoadTarotHistory(path string) ([]TarotHistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tarot history: %w", err)
+	}
+	var entries []TarotHistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse tarot history %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// AppendTarotHistory adds entry to the history file at path under a lock,
+// so concurrent runs don't drop each other's readings.
+func AppendTarotHistory(path string, entry TarotHistoryEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create tarot history directory: %w", err)
+	}
+	return filelock.Update(path, 0644, func(data []byte) ([]byte, error) {
+		var entries []TarotHistoryEntry
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &entries); err != nil {
+				// Don't overwrite readings we can't read
+				return nil, fmt.Errorf("failed to parse tarot history %s: %w", path, err)
+			}
+		}
+		return json.MarshalIndent(append(entries, entry), "", "  ")
+	})
+}
+
+// TarotCardCounts tallies the cards drawn across entries, most frequent
+// first, ties by name.
+func TarotCardCounts(entries []TarotHistoryEntry) []TarotCardCount {
+	index := make(map[string]int)
+	var counts []TarotCardCount
+	for _, entry := range entries {
+		for _, card := range entry.Cards {
+			i, ok := index[card.Name]
+			if !ok {
+				i = len(counts)
+				index[card.Name] = i
+				counts = append(counts, TarotCardCount{Name: card.Name})
+			}
+			counts[i].Count++
+			if card.Orientation == "reversed" {
+				counts[i].Reversed++
+			}
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// saveTarotReading adds a tarot_reading result to the history, recording
+// the outcome in the result. Error results have no cards and aren't saved.
+func saveTarotReading(result map[string]interface{}, configLoader ConfigLoader) {
+	cards, ok := result["cards"].([]TarotCard)
+	if !ok {
+		return
+	}
+	entry := TarotHistoryEntry{Timestamp: time.Now()}
+	entry.Source, _ = result["source"].(string)
+	entry.Question, _ = result["question"].(string)
+	entry.SpreadName, _ = result["spread_name"].(string)
+	entry.SpreadType, _ = result["spread_type"].(string)
+	entry.Cards = cards
+
+	path := TarotHistoryPath(configLoader)
+	if err := AppendTarotHistory(path, entry); err != nil {
+		// The reading is still worth showing
+		result["save_error"] = err.Error()
+		return
+	}
+	result["saved_to"] = path
+}
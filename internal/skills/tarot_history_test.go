@@ -0,0 +1,104 @@
+package skills
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarotHandlerSavesReading(t *testing.T) {
+	t.Setenv("CELESTE_HOME", t.TempDir())
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			http.Error(w, "function unavailable", status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"spread_name": "Three Card Spread", "cards": [
+			{"position": "Past", "name": "The Tower", "orientation": "reversed"},
+			{"position": "Present", "name": "The Star", "orientation": "upright"},
+			{"position": "Future", "name": "Ace of Cups", "orientation": "upright"}]}`))
+	}))
+	defer server.Close()
+	workspace := t.TempDir()
+	loader := &MockConfigLoader{
+		TarotCfg:     TarotConfig{FunctionURL: server.URL, AuthToken: "token"},
+		WorkspaceCfg: WorkspaceConfig{Name: "work", Dir: workspace},
+	}
+	path := TarotHistoryPath(loader)
+	assert.Equal(t, filepath.Join(workspace, "tarot_history.json"), path, "readings are kept in the profile's workspace")
+
+	// Without save nothing is written
+	_, err := TarotHandler(context.Background(), map[string]interface{}{"spread_type": "three"}, loader)
+	require.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	result, err := TarotHandler(context.Background(), map[string]interface{}{"spread_type": "three", "question": "What next?", "save": true}, loader)
+	require.NoError(t, err)
+	reading := result.(map[string]interface{})
+	assert.Equal(t, path, reading["saved_to"])
+
+	entries, err := LoadTarotHistory(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "three", entry.SpreadType)
+	assert.Equal(t, "Three Card Spread", entry.SpreadName)
+	assert.Equal(t, "What next?", entry.Question)
+	assert.Equal(t, "remote", entry.Source)
+	require.Len(t, entry.Cards, 3)
+	assert.Equal(t, reading["cards"], entry.Cards)
+	assert.WithinDuration(t, time.Now(), entry.Timestamp, time.Minute)
+
+	// Readings from the local deck are saved too
+	status = http.StatusServiceUnavailable
+	_, err = TarotHandler(context.Background(), map[string]interface{}{"spread_type": "celtic", "save": true}, loader)
+	require.NoError(t, err)
+	entries, err = LoadTarotHistory(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "local", entries[1].Source)
+	require.Len(t, entries[1].Cards, 10)
+	for _, card := range entries[1].Cards {
+		assert.NotEmpty(t, card.Name)
+		assert.Contains(t, []string{"upright", "reversed"}, card.Orientation)
+	}
+
+	// Error results aren't saved
+	_, err = TarotHandler(context.Background(), map[string]interface{}{"spread_type": "pyramid", "save": true}, loader)
+	require.NoError(t, err)
+	entries, err = LoadTarotHistory(path)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestAppendTarotHistoryKeepsUnreadableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tarot_history.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+
+	err := AppendTarotHistory(path, TarotHistoryEntry{Timestamp: time.Now()})
+	assert.Error(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "not json", string(data))
+}
+
+func TestTarotCardCounts(t *testing.T) {
+	entries := []TarotHistoryEntry{
+		{TarotReading: TarotReading{Cards: []TarotCard{{Name: "The Tower", Orientation: "reversed"}, {Name: "The Star", Orientation: "upright"}}}},
+		{TarotReading: TarotReading{Cards: []TarotCard{{Name: "The Tower", Orientation: "upright"}, {Name: "Ace of Cups", Orientation: "upright"}}}},
+	}
+	assert.Equal(t, []TarotCardCount{
+		{Name: "The Tower", Count: 2, Reversed: 1},
+		{Name: "Ace of Cups", Count: 1},
+		{Name: "The Star", Count: 1},
+	}, TarotCardCounts(entries))
+}
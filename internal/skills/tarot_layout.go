@@ -0,0 +1,173 @@
+package skills
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// singleCardWidth is the inner width of the single card box.
+const singleCardWidth = 40
+
+// horseshoeCardWidth is the inner width of each horseshoe card box.
+const horseshoeCardWidth = 22
+
+// horseshoeSlots places the five horseshoe cards on an arc: the row of the
+// arc each card sits on and its column, in card order.
+var horseshoeSlots = []struct{ row, col int }{
+	{0, 0}, {1, 13}, {2, 26}, {1, 39}, {0, 52},
+}
+
+// Layout draws the reading for spreads with a layout of their own: a large
+// box for a single card and an arc for the horseshoe. It returns "" for
+// other spreads, which read fine as the String list. When the reading has
+// the wrong number of cards for its spread, layout is "" and warning says
+// so, so the cards are listed rather than drawn in the wrong places.
+func (r TarotReading) Layout() (layout, warning string) {
+	draw := map[string]func(TarotReading) string{
+		"single":    displaySingleCard,
+		"horseshoe": displayHorseshoe,
+	}[r.SpreadType]
+	if want := len(tarotSpreadPositions[r.SpreadType]); want > 0 && len(r.Cards) != want {
+		return "", fmt.Sprintf("%s returned %d cards, expected %d; listing them instead", r.SpreadName, len(r.Cards), want)
+	}
+	if draw == nil {
+		return "", ""
+	}
+	return draw(r), ""
+}
+
+// displaySingleCard draws one card in a large centered box, with the
+// meaning for its orientation and, for cards in the local deck, the other
+// orientation's meaning too.
+func displaySingleCard(r TarotReading) string {
+	card := r.Cards[0]
+	border := strings.Repeat("═", singleCardWidth)
+	lines := []string{"╔" + border + "╗"}
+	row := func(text string) {
+		lines = append(lines, "║"+centerText(text, singleCardWidth)+"║")
+	}
+
+	row(r.SpreadName)
+	row("")
+	row(card.Name)
+	row("(" + card.Orientation + ")")
+	row("")
+	for _, line := range wrapText(card.Meaning, singleCardWidth-4) {
+		row(line)
+	}
+	if other := oppositeMeaning(card); other != "" {
+		row("")
+		for _, line := range wrapText(other, singleCardWidth-4) {
+			row(line)
+		}
+	}
+	lines = append(lines, "╚"+border+"╝")
+	return strings.Join(lines, "\n")
+}
+
+// oppositeMeaning returns the meaning of the card's other orientation, e.g.
+// "Reversed: despair, disconnection, lost faith", or "" for a card not in
+// the local deck.
+func oppositeMeaning(card TarotCard) string {
+	for _, c := range tarotDeck() {
+		if !strings.EqualFold(c.Name, card.Name) {
+			continue
+		}
+		if card.Orientation == "reversed" {
+			return "Upright: " + c.Upright
+		}
+		return "Reversed: " + c.Reversed
+	}
+	return ""
+}
+
+// displayHorseshoe draws the five cards as a horseshoe opening upward, past
+// at the top left and outcome at the top right, then lists their meanings.
+func displayHorseshoe(r TarotReading) string {
+	const boxHeight = 5
+	width := horseshoeSlots[len(horseshoeSlots)-1].col + horseshoeCardWidth + 2
+	grid := make([][]rune, 3*boxHeight)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", width))
+	}
+
+	for i, card := range r.Cards {
+		slot := horseshoeSlots[i]
+		for j, line := range cardBox(i+1, card) {
+			copy(grid[slot.row*boxHeight+j][slot.col:], []rune(line))
+		}
+	}
+
+	lines := []string{r.SpreadName, ""}
+	for _, line := range grid {
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+	lines = append(lines, "")
+	for i, card := range r.Cards {
+		if card.Meaning != "" {
+			lines = append(lines, fmt.Sprintf("%d. %s: %s", i+1, card.Position, card.Meaning))
+		}
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// cardBox draws a small numbered card box for the horseshoe.
+func cardBox(n int, card TarotCard) []string {
+	border := strings.Repeat("─", horseshoeCardWidth)
+	row := func(text string) string {
+		return "│" + padText(fitText(text, horseshoeCardWidth-1), horseshoeCardWidth-1) + " │"
+	}
+	return []string{
+		"┌" + border + "┐",
+		row(fmt.Sprintf(" %d %s", n, card.Position)),
+		row(" " + card.Name),
+		row(" " + card.Orientation),
+		"└" + border + "┘",
+	}
+}
+
+// centerText centers text in width columns, trimming it if it is longer.
+func centerText(text string, width int) string {
+	text = fitText(text, width)
+	left := (width - utf8.RuneCountInString(text)) / 2
+	return padText(strings.Repeat(" ", left)+text, width)
+}
+
+// padText pads text with spaces to width columns.
+func padText(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		text += strings.Repeat(" ", width-n)
+	}
+	return text
+}
+
+// fitText cuts text to width columns, ending it with "…" when cut.
+func fitText(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + "…"
+}
+
+// wrapText breaks text into lines of at most width columns at spaces.
+func wrapText(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
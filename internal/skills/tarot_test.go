@@ -0,0 +1,255 @@
+package skills
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarotDeck(t *testing.T) {
+	deck := tarotDeck()
+	assert.Len(t, deck, 78)
+
+	names := make(map[string]bool, len(deck))
+	for _, card := range deck {
+		assert.False(t, names[card.Name], "duplicate card %s", card.Name)
+		names[card.Name] = true
+	}
+	assert.True(t, names["The Fool"])
+	assert.True(t, names["King of Pentacles"])
+}
+
+func TestDrawLocalTarotSpread(t *testing.T) {
+	for spread, size := range map[string]int{"single": 1, "three": 3, "horseshoe": 5, "celtic": 10} {
+		cards, err := drawLocalTarotSpread(spread)
+		require.NoError(t, err)
+		require.Len(t, cards, size)
+
+		seen := make(map[string]bool)
+		for i, card := range cards {
+			assert.Equal(t, tarotSpreadPositions[spread][i], card.Position)
+			assert.False(t, seen[card.Name], "card drawn twice: %s", card.Name)
+			seen[card.Name] = true
+			assert.Contains(t, []string{"upright", "reversed"}, card.Orientation)
+			assert.NotEmpty(t, card.Meaning)
+		}
+	}
+
+	_, err := drawLocalTarotSpread("pyramid")
+	assert.EqualError(t, err, `unknown spread type "pyramid" (use three, single, horseshoe, celtic)`)
+}
+
+func TestTarotHandlerRejectsUnknownSpread(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	loader := &MockConfigLoader{TarotCfg: TarotConfig{FunctionURL: server.URL, AuthToken: "token"}}
+	result, err := TarotHandler(context.Background(), map[string]interface{}{"spread_type": "pyramid"}, loader)
+	require.NoError(t, err)
+
+	data := result.(map[string]interface{})
+	assert.Equal(t, "validation_error", data["error_type"])
+	assert.Contains(t, data["hint"], "three, single, horseshoe, celtic")
+	assert.Zero(t, requests, "an unknown spread is rejected before calling the function")
+}
+
+func TestTarotHandlerFallsBackToLocalDeck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "function unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	loader := &MockConfigLoader{TarotCfg: TarotConfig{FunctionURL: server.URL, AuthToken: "token"}}
+	result, err := TarotHandler(context.Background(), map[string]interface{}{"spread_type": "celtic", "question": "What next?"}, loader)
+	require.NoError(t, err)
+
+	reading := result.(map[string]interface{})
+	assert.Equal(t, "local", reading["source"])
+	assert.Equal(t, "What next?", reading["question"])
+	assert.Contains(t, reading["fallback_reason"], "503")
+	assert.Len(t, reading["cards"], 10)
+}
+
+func TestTarotHandlerFallsBackWhenUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	loader := &MockConfigLoader{TarotCfg: TarotConfig{FunctionURL: url, AuthToken: "token"}}
+	result, err := TarotHandler(context.Background(), map[string]interface{}{"spread_type": "three"}, loader)
+	require.NoError(t, err)
+
+	reading := result.(map[string]interface{})
+	assert.Equal(t, "local", reading["source"])
+	assert.Len(t, reading["cards"], 3)
+}
+
+func TestDecodeTarotReading(t *testing.T) {
+	reading, err := decodeTarotReading([]byte(`{
+		"spread_type": "three",
+		"cards": [
+			{"position": "Past", "name": "The Tower", "meaning": "upheaval", "orientation": "Reversed"},
+			{"position": "Present", "name": "The Star", "meaning": "hope", "orientation": "upright"},
+			{"position": "Future", "name": "Ace of Cups", "meaning": "new love", "orientation": "upright", "extra": 1}
+		]
+	}`), "three")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Three Card Spread", reading.SpreadName)
+	require.Len(t, reading.Cards, 3)
+	assert.Equal(t, TarotCard{Position: "Past", Name: "The Tower", Meaning: "upheaval", Orientation: "reversed"}, reading.Cards[0])
+}
+
+func TestDecodeTarotReadingShapeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		expectErr string
+	}{
+		{name: "Not JSON", body: `<html>`, expectErr: "unexpected tarot response shape"},
+		{name: "Wrong type", body: `{"cards": [{"name": 7}]}`, expectErr: "unexpected tarot response shape"},
+		{name: "No cards", body: `{"spread": []}`, expectErr: "tarot response has no cards (fields: spread)"},
+		{
+			name:      "Renamed field",
+			body:      `{"cards": [{"position": "Past", "card": "The Fool", "orientation": "upright"}]}`,
+			expectErr: "tarot card 1 has no name (fields: card, orientation, position)",
+		},
+		{
+			name:      "Missing position",
+			body:      `{"cards": [{"name": "The Fool", "orientation": "upright"}]}`,
+			expectErr: "tarot card 1 (The Fool) has no position",
+		},
+		{
+			name:      "Bad orientation",
+			body:      `{"cards": [{"position": "Past", "name": "The Fool", "orientation": "sideways"}]}`,
+			expectErr: `has orientation "sideways", want upright or reversed`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeTarotReading([]byte(tt.body), "three")
+			assert.ErrorContains(t, err, tt.expectErr)
+		})
+	}
+}
+
+func TestTarotReadingString(t *testing.T) {
+	reading := TarotReading{
+		SpreadName: "Three Card Spread",
+		Cards: []TarotCard{
+			{Position: "Past", Name: "The Fool", Meaning: "beginnings", Orientation: "upright"},
+			{Position: "Present", Name: "Death", Orientation: "reversed"},
+		},
+	}
+	assert.Equal(t, "Three Card Spread\n1. Past: The Fool (upright) - beginnings\n2. Present: Death (reversed)", reading.String())
+}
+
+func TestTarotHandlerDecodesRemoteReading(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"spread_name": "Celtic Cross", "spread_type": "celtic", "cards": [
+			{"position": "Present", "name": "The Moon", "meaning": "illusion", "orientation": "upright"}
+		]}`))
+	}))
+	defer server.Close()
+
+	loader := &MockConfigLoader{TarotCfg: TarotConfig{FunctionURL: server.URL, AuthToken: "token"}}
+	result, err := TarotHandler(context.Background(), map[string]interface{}{"spread_type": "celtic"}, loader)
+	require.NoError(t, err)
+
+	reading := result.(map[string]interface{})
+	assert.Equal(t, "remote", reading["source"])
+	assert.Equal(t, []TarotCard{{Position: "Present", Name: "The Moon", Meaning: "illusion", Orientation: "upright"}}, reading["cards"])
+	assert.Equal(t, "Celtic Cross\n1. Present: The Moon (upright) - illusion", reading["summary"])
+	assert.NotContains(t, reading, "layout")
+	assert.Equal(t, "Celtic Cross returned 1 cards, expected 10; listing them instead", reading["layout_warning"])
+}
+
+const singleCardGolden = `╔════════════════════════════════════════╗
+║            Card of the Day             ║
+║                                        ║
+║                The Star                ║
+║               (upright)                ║
+║                                        ║
+║       hope, renewal, inspiration       ║
+║                                        ║
+║   Reversed: despair, disconnection,    ║
+║               lost faith               ║
+╚════════════════════════════════════════╝`
+
+func TestTarotLayoutSingle(t *testing.T) {
+	reading := TarotReading{SpreadName: "Card of the Day", SpreadType: "single", Cards: []TarotCard{
+		{Position: "Card of the Day", Name: "The Star", Meaning: "hope, renewal, inspiration", Orientation: "upright"},
+	}}
+	layout, warning := reading.Layout()
+	assert.Empty(t, warning)
+	assert.Equal(t, singleCardGolden, layout)
+
+	result := reading.result("local", "")
+	assert.Equal(t, singleCardGolden, result["layout"])
+}
+
+const horseshoeGolden = `Horseshoe Spread
+
+┌──────────────────────┐                            ┌──────────────────────┐
+│ 1 Past               │                            │ 5 Outcome            │
+│ The Fool             │                            │ The Sun              │
+│ upright              │                            │ upright              │
+└──────────────────────┘                            └──────────────────────┘
+             ┌──────────────────────┐  ┌──────────────────────┐
+             │ 2 Present            │  │ 4 Obstacles          │
+             │ Knight of Pentacles  │  │ Five of Swords       │
+             │ reversed             │  │ upright              │
+             └──────────────────────┘  └──────────────────────┘
+                          ┌──────────────────────┐
+                          │ 3 Hidden Influences  │
+                          │ The Moon             │
+                          │ upright              │
+                          └──────────────────────┘
+
+1. Past: beginnings, spontaneity, a leap of faith
+2. Present: impulsiveness or inertia in work, money and the material world
+3. Hidden Influences: illusion, fear, intuition
+4. Obstacles: conflict and loss in thoughts and conflict
+5. Outcome: joy, success, vitality`
+
+func TestTarotLayoutHorseshoe(t *testing.T) {
+	reading := TarotReading{SpreadName: "Horseshoe Spread", SpreadType: "horseshoe", Cards: []TarotCard{
+		{Position: "Past", Name: "The Fool", Meaning: "beginnings, spontaneity, a leap of faith", Orientation: "upright"},
+		{Position: "Present", Name: "Knight of Pentacles", Meaning: "impulsiveness or inertia in work, money and the material world", Orientation: "reversed"},
+		{Position: "Hidden Influences", Name: "The Moon", Meaning: "illusion, fear, intuition", Orientation: "upright"},
+		{Position: "Obstacles", Name: "Five of Swords", Meaning: "conflict and loss in thoughts and conflict", Orientation: "upright"},
+		{Position: "Outcome", Name: "The Sun", Meaning: "joy, success, vitality", Orientation: "upright"},
+	}}
+	layout, warning := reading.Layout()
+	assert.Empty(t, warning)
+	assert.Equal(t, horseshoeGolden, layout)
+
+	// Too few cards for the arc are listed instead
+	reading.Cards = reading.Cards[:3]
+	layout, warning = reading.Layout()
+	assert.Empty(t, layout)
+	assert.Equal(t, "Horseshoe Spread returned 3 cards, expected 5; listing them instead", warning)
+}
+
+func TestTarotHandlerReportsShapeMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"cards": [{"slot": "Past", "card": "The Fool"}]}`))
+	}))
+	defer server.Close()
+
+	loader := &MockConfigLoader{TarotCfg: TarotConfig{FunctionURL: server.URL, AuthToken: "token"}}
+	result, err := TarotHandler(context.Background(), map[string]interface{}{"spread_type": "three"}, loader)
+	require.NoError(t, err)
+
+	data := result.(map[string]interface{})
+	assert.Equal(t, "api_error", data["error_type"])
+	assert.Contains(t, data["error"], "has no name (fields: card, slot)")
+}
@@ -10,23 +10,34 @@ type MockConfigLoader struct {
 	TarotCfg          TarotConfig
 	VeniceCfg         VeniceConfig
 	WeatherCfg        WeatherConfig
+	LocationCfg       LocationConfig
 	TwitchCfg         TwitchConfig
 	YouTubeCfg        YouTubeConfig
+	DiscordCfg        DiscordConfig
+	TwitterCfg        TwitterConfig
+	MastodonCfg       MastodonConfig
 	IPFSCfg           IPFSConfig
 	AlchemyCfg        AlchemyConfig
 	BlockmonCfg       BlockmonConfig
 	WalletSecurityCfg WalletSecuritySettingsConfig
+	WorkspaceCfg      WorkspaceConfig
+	SkillDefaults     SkillDefaults
 
 	// Error flags to simulate missing config
 	TarotError          error
 	VeniceError         error
 	WeatherError        error
+	LocationError       error
 	TwitchError         error
 	YouTubeError        error
+	DiscordError        error
+	TwitterError        error
+	MastodonError       error
 	IPFSError           error
 	AlchemyError        error
 	BlockmonError       error
 	WalletSecurityError error
+	WorkspaceError      error
 }
 
 // GetTarotConfig returns mock tarot configuration
@@ -53,6 +64,14 @@ func (m *MockConfigLoader) GetWeatherConfig() (WeatherConfig, error) {
 	return m.WeatherCfg, nil
 }
 
+// GetLocationConfig returns mock IP geolocation configuration
+func (m *MockConfigLoader) GetLocationConfig() (LocationConfig, error) {
+	if m.LocationError != nil {
+		return LocationConfig{}, m.LocationError
+	}
+	return m.LocationCfg, nil
+}
+
 // GetTwitchConfig returns mock Twitch configuration
 func (m *MockConfigLoader) GetTwitchConfig() (TwitchConfig, error) {
 	if m.TwitchError != nil {
@@ -69,6 +88,30 @@ func (m *MockConfigLoader) GetYouTubeConfig() (YouTubeConfig, error) {
 	return m.YouTubeCfg, nil
 }
 
+// GetDiscordConfig returns mock Discord configuration
+func (m *MockConfigLoader) GetDiscordConfig() (DiscordConfig, error) {
+	if m.DiscordError != nil {
+		return DiscordConfig{}, m.DiscordError
+	}
+	return m.DiscordCfg, nil
+}
+
+// GetTwitterConfig returns mock Twitter configuration
+func (m *MockConfigLoader) GetTwitterConfig() (TwitterConfig, error) {
+	if m.TwitterError != nil {
+		return TwitterConfig{}, m.TwitterError
+	}
+	return m.TwitterCfg, nil
+}
+
+// GetMastodonConfig returns mock Mastodon configuration
+func (m *MockConfigLoader) GetMastodonConfig() (MastodonConfig, error) {
+	if m.MastodonError != nil {
+		return MastodonConfig{}, m.MastodonError
+	}
+	return m.MastodonCfg, nil
+}
+
 // GetIPFSConfig returns mock IPFS configuration
 func (m *MockConfigLoader) GetIPFSConfig() (IPFSConfig, error) {
 	if m.IPFSError != nil {
@@ -101,6 +144,19 @@ func (m *MockConfigLoader) GetWalletSecurityConfig() (WalletSecuritySettingsConf
 	return m.WalletSecurityCfg, nil
 }
 
+// GetWorkspaceConfig returns mock workspace configuration
+func (m *MockConfigLoader) GetWorkspaceConfig() (WorkspaceConfig, error) {
+	if m.WorkspaceError != nil {
+		return WorkspaceConfig{}, m.WorkspaceError
+	}
+	return m.WorkspaceCfg, nil
+}
+
+// GetSkillDefaults returns mock skill parameter defaults
+func (m *MockConfigLoader) GetSkillDefaults() (SkillDefaults, error) {
+	return m.SkillDefaults, nil
+}
+
 // NewMockConfigLoader creates a mock config loader with default values
 func NewMockConfigLoader() *MockConfigLoader {
 	return &MockConfigLoader{
@@ -117,6 +173,9 @@ func NewMockConfigLoader() *MockConfigLoader {
 		WeatherCfg: WeatherConfig{
 			DefaultZipCode: "10001",
 		},
+		LocationCfg: LocationConfig{
+			Provider: "ipapi",
+		},
 		TwitchCfg: TwitchConfig{
 			ClientID:        "mock-twitch-client-id",
 			DefaultStreamer: "test_streamer",
@@ -124,6 +183,19 @@ func NewMockConfigLoader() *MockConfigLoader {
 		YouTubeCfg: YouTubeConfig{
 			APIKey: "mock-youtube-key",
 		},
+		DiscordCfg: DiscordConfig{
+			WebhookURL: "http://mock-api:8080/discord/webhook",
+		},
+		TwitterCfg: TwitterConfig{
+			APIKey:            "mock-twitter-key",
+			APISecret:         "mock-twitter-secret",
+			AccessToken:       "mock-access-token",
+			AccessTokenSecret: "mock-access-secret",
+		},
+		MastodonCfg: MastodonConfig{
+			InstanceURL: "http://mock-api:8080/mastodon",
+			AccessToken: "mock-mastodon-token",
+		},
 		IPFSCfg: IPFSConfig{
 			Provider:       "infura",
 			APIKey:         "mock-ipfs-key",
@@ -156,12 +228,17 @@ func NewMockConfigLoaderWithErrors() *MockConfigLoader {
 		TarotError:          fmt.Errorf("tarot config not found"),
 		VeniceError:         fmt.Errorf("venice config not found"),
 		WeatherError:        fmt.Errorf("weather config not found"),
+		LocationError:       fmt.Errorf("geolocation is turned off"),
 		TwitchError:         fmt.Errorf("twitch config not found"),
 		YouTubeError:        fmt.Errorf("youtube config not found"),
+		DiscordError:        fmt.Errorf("discord config not found"),
+		TwitterError:        fmt.Errorf("twitter config not found"),
+		MastodonError:       fmt.Errorf("mastodon config not found"),
 		IPFSError:           fmt.Errorf("IPFS config not found"),
 		AlchemyError:        fmt.Errorf("Alchemy config not found"),
 		BlockmonError:       fmt.Errorf("blockchain monitoring config not found"),
 		WalletSecurityError: fmt.Errorf("wallet security config not found"),
+		WorkspaceError:      fmt.Errorf("workspace config not found"),
 	}
 }
 
@@ -0,0 +1,365 @@
+package skills
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits for the local text skills, so a pasted log file doesn't stall a
+// chat turn.
+const (
+	maxLocalTextBytes = 1 << 20
+	maxJSONIndent     = 8
+	readingWPM        = 200
+	topTermCount      = 10
+)
+
+// stopwords are common English words left out of an analysis's top terms.
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		a about above after again against all am an and any are as at be
+		because been before being below between both but by can could did do
+		does doing down during each few for from further had has have having
+		he her here hers herself him himself his how i if in into is it its
+		itself just me more most my myself no nor not now of off on once only
+		or other our ours ourselves out over own same she should so some such
+		than that the their theirs them themselves then there these they this
+		those through to too under until up very was we were what when where
+		which while who whom why will with would you your yours yourself
+		yourselves i'm it's don't doesn't didn't isn't aren't wasn't can't
+		won't i've you're we're they're that's there's let's`) {
+		stopwords[word] = true
+	}
+}
+
+// TermCount is how often a term appears in analyzed text.
+type TermCount struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// TextStats is the result of analyzing text.
+type TextStats struct {
+	Words              int         `json:"words"`
+	Characters         int         `json:"characters"`
+	CharactersNoSpaces int         `json:"characters_no_spaces"`
+	Sentences          int         `json:"sentences"`
+	ReadingTimeSeconds int         `json:"reading_time_seconds"` // At 200 words a minute
+	ReadingTime        string      `json:"reading_time"`
+	TopTerms           []TermCount `json:"top_terms"`
+}
+
+// analyzeText counts the words, characters and sentences in text. Words are
+// runs of letters and digits, with apostrophes inside them, so counts hold
+// for punctuation-heavy and non-Latin text; characters are runes, not bytes.
+func analyzeText(text string) TextStats {
+	stats := TextStats{TopTerms: []TermCount{}}
+	inSentence := false
+	for _, r := range text {
+		stats.Characters++
+		if !unicode.IsSpace(r) {
+			stats.CharactersNoSpaces++
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			inSentence = true
+		case isSentenceEnd(r) && inSentence:
+			stats.Sentences++
+			inSentence = false
+		}
+	}
+	if inSentence {
+		// Text that trails off without a full stop is still a sentence
+		stats.Sentences++
+	}
+
+	counts := make(map[string]int)
+	for _, word := range textWords(text) {
+		stats.Words++
+		term := strings.ToLower(word)
+		if stopwords[term] || utf8.RuneCountInString(term) < 2 || isNumeric(term) {
+			continue
+		}
+		counts[term]++
+	}
+	for term, count := range counts {
+		stats.TopTerms = append(stats.TopTerms, TermCount{Term: term, Count: count})
+	}
+	sort.Slice(stats.TopTerms, func(i, j int) bool {
+		a, b := stats.TopTerms[i], stats.TopTerms[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Term < b.Term
+	})
+	if len(stats.TopTerms) > topTermCount {
+		stats.TopTerms = stats.TopTerms[:topTermCount]
+	}
+
+	stats.ReadingTimeSeconds = int(math.Ceil(float64(stats.Words) * 60 / readingWPM))
+	stats.ReadingTime = readingTime(stats.ReadingTimeSeconds)
+	return stats
+}
+
+// textWords splits text into words: runs of letters, digits and marks,
+// keeping apostrophes between letters ("don't") but not around a word.
+func textWords(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) || r == '\'' || r == '’')
+	})
+	words := fields[:0]
+	for _, field := range fields {
+		field = strings.ReplaceAll(field, "’", "'")
+		if field = strings.Trim(field, "'"); field != "" {
+			words = append(words, field)
+		}
+	}
+	return words
+}
+
+// isSentenceEnd reports whether r ends a sentence, in Latin or CJK text.
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '.', '!', '?', '…', '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+// isNumeric reports whether s is only digits.
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if !unicode.IsNumber(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// readingTime describes a reading time, e.g. "under a minute" or "3 minutes".
+func readingTime(seconds int) string {
+	switch minutes := int(math.Round(float64(seconds) / 60)); {
+	case seconds == 0:
+		return "no time"
+	case seconds < 60:
+		return "under a minute"
+	case minutes == 1:
+		return "1 minute"
+	default:
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+}
+
+// formatJSON pretty-prints data with indent spaces per level, or compacts it
+// when indent is 0. Key order is kept unless sortKeys is set. Numbers are
+// written as they appear, so large integers and decimals aren't rounded.
+func formatJSON(data []byte, indent int, sortKeys bool) (string, error) {
+	// Unmarshal checks the whole input first, so syntax errors carry offsets
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "", err
+	}
+
+	if sortKeys {
+		// Maps marshal with their keys sorted
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(value); err != nil {
+			return "", err
+		}
+		data = buf.Bytes()
+	}
+
+	var out bytes.Buffer
+	var err error
+	if indent == 0 {
+		err = json.Compact(&out, data)
+	} else {
+		err = json.Indent(&out, data, "", strings.Repeat(" ", indent))
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// jsonErrorPosition finds the line and column, both from 1, of the byte at
+// offset in data. Columns count characters, not bytes.
+func jsonErrorPosition(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
+
+// AnalyzeTextSkill returns the text statistics skill definition.
+func AnalyzeTextSkill() Skill {
+	return Skill{
+		Name:        "analyze_text",
+		Description: "Count the words, characters and sentences in text, estimate its reading time and list its most frequent terms. Runs locally; use it instead of counting yourself.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"text": map[string]interface{}{
+					"type":        "string",
+					"description": "The text to analyze",
+				},
+			},
+			"required": []string{"text"},
+		},
+	}
+}
+
+// FormatJSONSkill returns the JSON formatter skill definition.
+func FormatJSONSkill() Skill {
+	return Skill{
+		Name:        "format_json",
+		Description: "Pretty-print or validate JSON, reporting the line and column of the first syntax error. Runs locally; use it instead of reformatting JSON yourself.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"json": map[string]interface{}{
+					"type":        "string",
+					"description": "The JSON to format",
+				},
+				"indent": map[string]interface{}{
+					"type":        "integer",
+					"description": "Spaces per level (default: 2, max: 8); 0 prints it on one line",
+				},
+				"sort_keys": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Sort object keys alphabetically (default: false keeps their order)",
+				},
+			},
+			"required": []string{"json"},
+		},
+	}
+}
+
+// AnalyzeTextHandler reports statistics about text.
+func AnalyzeTextHandler(args map[string]interface{}) (interface{}, error) {
+	text, _ := args["text"].(string)
+	if problem := checkLocalText(text, "text"); problem != "" {
+		return formatErrorResponse(
+			"validation_error",
+			problem,
+			"Provide the text to analyze, at most 1 MB.",
+			map[string]interface{}{
+				"skill": "analyze_text",
+				"field": "text",
+			},
+		), nil
+	}
+	return analyzeText(text), nil
+}
+
+// FormatJSONHandler pretty-prints JSON, or says where it is invalid.
+func FormatJSONHandler(args map[string]interface{}) (interface{}, error) {
+	var input string
+	switch v := args["json"].(type) {
+	case string:
+		input = v
+	case nil:
+	default:
+		// The model sometimes passes the JSON itself rather than as a string
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read json: %w", err)
+		}
+		input = string(data)
+	}
+	if problem := checkLocalText(strings.TrimSpace(input), "json"); problem != "" {
+		return formatErrorResponse(
+			"validation_error",
+			problem,
+			"Provide the JSON to format, at most 1 MB.",
+			map[string]interface{}{
+				"skill": "format_json",
+				"field": "json",
+			},
+		), nil
+	}
+
+	indent := 2
+	if i, ok := args["indent"].(float64); ok {
+		indent = int(i)
+	}
+	if indent < 0 || indent > maxJSONIndent {
+		return formatErrorResponse(
+			"validation_error",
+			fmt.Sprintf("The 'indent' parameter must be between 0 and %d", maxJSONIndent),
+			"Use 2 or 4 spaces, or 0 for a single line.",
+			map[string]interface{}{
+				"skill": "format_json",
+				"field": "indent",
+			},
+		), nil
+	}
+	sortKeys, _ := args["sort_keys"].(bool)
+
+	data := []byte(input)
+	formatted, err := formatJSON(data, indent, sortKeys)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset counts the bytes read, including the one that was wrong;
+		// at the end of the input it is the length, just past the last byte
+		offset := syntaxErr.Offset
+		message := syntaxErr.Error()
+		if offset > 0 && !strings.HasPrefix(message, "unexpected end") {
+			offset--
+		}
+		line, column := jsonErrorPosition(data, offset)
+		return formatErrorResponse(
+			"invalid_json",
+			fmt.Sprintf("Invalid JSON at line %d, column %d: %s", line, column, message),
+			"Fix the JSON at that position and try again.",
+			map[string]interface{}{
+				"skill":  "format_json",
+				"field":  "json",
+				"offset": offset,
+				"line":   line,
+				"column": column,
+			},
+		), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to format json: %w", err)
+	}
+
+	return map[string]interface{}{
+		"formatted": formatted,
+		"valid":     true,
+		"indent":    indent,
+		"sort_keys": sortKeys,
+	}, nil
+}
+
+// checkLocalText returns why text can't be used for field, or "".
+func checkLocalText(text, field string) string {
+	switch {
+	case text == "":
+		return fmt.Sprintf("The '%s' parameter is required", field)
+	case len(text) > maxLocalTextBytes:
+		return fmt.Sprintf("The '%s' parameter is %d bytes; at most %d are accepted", field, len(text), maxLocalTextBytes)
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+package skills
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeText(t *testing.T) {
+	tests := []struct {
+		name               string
+		text               string
+		words              int
+		characters         int
+		charactersNoSpaces int
+		sentences          int
+	}{
+		{"simple", "The cat sat. The cat ran!", 6, 25, 20, 2},
+		{"no final stop", "Hello world", 2, 11, 10, 1},
+		{"ellipsis counts once", "Wait... what?", 2, 13, 12, 2},
+		{"punctuation only", "?!...", 0, 5, 5, 0},
+		{"contractions", "Don't stop, won’t stop.", 4, 23, 20, 1},
+		{"accented", "Café crème brûlée.", 3, 18, 16, 1},
+		{"japanese", "今日は晴れです。明日は雨です。", 2, 15, 15, 2},
+		{"emoji", "Ship it 🚀🚀", 2, 10, 8, 1},
+		{"numbers", "Pay 42 dollars by 5pm", 5, 21, 17, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := analyzeText(tt.text)
+			assert.Equal(t, tt.words, stats.Words, "words")
+			assert.Equal(t, tt.characters, stats.Characters, "characters")
+			assert.Equal(t, tt.charactersNoSpaces, stats.CharactersNoSpaces, "characters without spaces")
+			assert.Equal(t, tt.sentences, stats.Sentences, "sentences")
+		})
+	}
+}
+
+func TestAnalyzeTextTopTerms(t *testing.T) {
+	stats := analyzeText("The fox and the dog. The fox jumps over the dog, and the fox runs. 42 42 42 a a a")
+	require.NotEmpty(t, stats.TopTerms)
+	assert.Equal(t, []TermCount{
+		{Term: "fox", Count: 3},
+		{Term: "dog", Count: 2},
+		{Term: "jumps", Count: 1},
+		{Term: "runs", Count: 1},
+	}, stats.TopTerms, "stopwords, single letters and numbers are left out; ties sort by term")
+
+	// At most 10 terms
+	var words []string
+	for _, w := range []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet", "kilo", "lima"} {
+		words = append(words, w, "Alpha")
+	}
+	stats = analyzeText(strings.Join(words, " "))
+	assert.Len(t, stats.TopTerms, 10)
+	assert.Equal(t, TermCount{Term: "alpha", Count: 13}, stats.TopTerms[0], "terms are case-insensitive")
+}
+
+func TestAnalyzeTextReadingTime(t *testing.T) {
+	tests := []struct {
+		words   int
+		seconds int
+		text    string
+	}{
+		{0, 0, "no time"},
+		{1, 1, "under a minute"},
+		{199, 60, "1 minute"},
+		{200, 60, "1 minute"},
+		{500, 150, "3 minutes"},
+		{1000, 300, "5 minutes"},
+	}
+	for _, tt := range tests {
+		stats := analyzeText(strings.Repeat("word ", tt.words))
+		assert.Equal(t, tt.seconds, stats.ReadingTimeSeconds, "%d words", tt.words)
+		assert.Equal(t, tt.text, stats.ReadingTime, "%d words", tt.words)
+	}
+}
+
+func TestAnalyzeTextHandler(t *testing.T) {
+	result, err := AnalyzeTextHandler(map[string]interface{}{"text": "One two three."})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.(TextStats).Words)
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"text": ""},
+		{"text": strings.Repeat("a", maxLocalTextBytes+1)},
+	} {
+		result, err := AnalyzeTextHandler(args)
+		require.NoError(t, err)
+		data := result.(map[string]interface{})
+		assert.Equal(t, "validation_error", data["error_type"])
+		assert.Equal(t, "text", data["field"])
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected string
+	}{
+		{
+			"default indent keeps key order",
+			map[string]interface{}{"json": `{"b":1,"a":[1,2]}`},
+			"{\n  \"b\": 1,\n  \"a\": [\n    1,\n    2\n  ]\n}",
+		},
+		{
+			"four spaces",
+			map[string]interface{}{"json": `{"a":{"b":true}}`, "indent": 4.0},
+			"{\n    \"a\": {\n        \"b\": true\n    }\n}",
+		},
+		{
+			"compact",
+			map[string]interface{}{"json": "{\n  \"a\": 1,\n  \"b\": null\n}", "indent": 0.0},
+			`{"a":1,"b":null}`,
+		},
+		{
+			"sorted keys keep numbers exact",
+			map[string]interface{}{"json": `{"z":12345678901234567890,"a":{"d":0.10,"c":"<b>"}}`, "sort_keys": true, "indent": 0.0},
+			`{"a":{"c":"<b>","d":0.10},"z":12345678901234567890}`,
+		},
+		{
+			"multi-byte text",
+			map[string]interface{}{"json": `{"名前":"セレステ","emoji":"🚀"}`},
+			"{\n  \"名前\": \"セレステ\",\n  \"emoji\": \"🚀\"\n}",
+		},
+		{
+			"value passed as an object",
+			map[string]interface{}{"json": map[string]interface{}{"a": 1.0}},
+			"{\n  \"a\": 1\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FormatJSONHandler(tt.args)
+			require.NoError(t, err)
+			data := result.(map[string]interface{})
+			require.Equal(t, true, data["valid"], "%v", data)
+			assert.Equal(t, tt.expected, data["formatted"])
+		})
+	}
+}
+
+func TestFormatJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		offset  int64
+		line    int
+		column  int
+		message string
+	}{
+		{"trailing comma", `{"a":1,}`, 7, 1, 8, "invalid character '}'"},
+		{"second line", "{\n  \"a\": tru\n}", 12, 2, 11, "invalid character '\\n'"},
+		{"truncated", `{"a":[1,2`, 9, 1, 10, "unexpected end"},
+		{"multi-byte before error", `{"名前": x}`, 11, 1, 8, "invalid character 'x'"},
+		{"two values", `{} {}`, 3, 1, 4, "after top-level value"},
+		{"single quotes", `{'a':1}`, 1, 1, 2, "invalid character '\\''"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FormatJSONHandler(map[string]interface{}{"json": tt.json})
+			require.NoError(t, err)
+			data := result.(map[string]interface{})
+			assert.Equal(t, true, data["error"])
+			assert.Equal(t, "invalid_json", data["error_type"])
+			assert.Equal(t, tt.offset, data["offset"])
+			assert.Equal(t, tt.line, data["line"])
+			assert.Equal(t, tt.column, data["column"])
+			assert.Contains(t, data["message"], tt.message)
+		})
+	}
+}
+
+func TestFormatJSONValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  map[string]interface{}
+		field string
+	}{
+		{"missing", map[string]interface{}{}, "json"},
+		{"blank", map[string]interface{}{"json": "   "}, "json"},
+		{"too large", map[string]interface{}{"json": `"` + strings.Repeat("a", maxLocalTextBytes) + `"`}, "json"},
+		{"negative indent", map[string]interface{}{"json": "{}", "indent": -1.0}, "indent"},
+		{"huge indent", map[string]interface{}{"json": "{}", "indent": 9.0}, "indent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FormatJSONHandler(tt.args)
+			require.NoError(t, err)
+			data := result.(map[string]interface{})
+			assert.Equal(t, "validation_error", data["error_type"])
+			assert.Equal(t, tt.field, data["field"])
+		})
+	}
+}
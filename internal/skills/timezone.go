@@ -0,0 +1,319 @@
+package skills
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // Embedded zone database so conversions work without system tzdata
+)
+
+// timezoneAbbreviations maps common timezone abbreviations to the IANA zone
+// people usually mean by them. Daylight variants map to the same zone so the
+// converted time follows the zone's DST rules.
+var timezoneAbbreviations = map[string]string{
+	"UTC":  "UTC",
+	"GMT":  "Etc/GMT",
+	"BST":  "Europe/London",
+	"WET":  "Europe/Lisbon",
+	"CET":  "Europe/Paris",
+	"CEST": "Europe/Paris",
+	"EET":  "Europe/Athens",
+	"EEST": "Europe/Athens",
+	"MSK":  "Europe/Moscow",
+	"ET":   "America/New_York",
+	"EST":  "America/New_York",
+	"EDT":  "America/New_York",
+	"CT":   "America/Chicago",
+	"CDT":  "America/Chicago",
+	"MT":   "America/Denver",
+	"MST":  "America/Denver",
+	"MDT":  "America/Denver",
+	"PT":   "America/Los_Angeles",
+	"PST":  "America/Los_Angeles",
+	"PDT":  "America/Los_Angeles",
+	"AKST": "America/Anchorage",
+	"AKDT": "America/Anchorage",
+	"HST":  "Pacific/Honolulu",
+	"JST":  "Asia/Tokyo",
+	"KST":  "Asia/Seoul",
+	"HKT":  "Asia/Hong_Kong",
+	"SGT":  "Asia/Singapore",
+	"PHT":  "Asia/Manila",
+	"WIB":  "Asia/Jakarta",
+	"AEST": "Australia/Sydney",
+	"AEDT": "Australia/Sydney",
+	"ACST": "Australia/Adelaide",
+	"ACDT": "Australia/Adelaide",
+	"AWST": "Australia/Perth",
+	"NZST": "Pacific/Auckland",
+	"NZDT": "Pacific/Auckland",
+	"BRT":  "America/Sao_Paulo",
+	"ART":  "America/Argentina/Buenos_Aires",
+	"ADT":  "America/Halifax",
+}
+
+// ambiguousTimezoneAbbreviations lists abbreviations shared by several zones.
+// These are never guessed; the caller gets the candidates back instead.
+var ambiguousTimezoneAbbreviations = map[string][]string{
+	"CST": {"America/Chicago", "Asia/Shanghai", "America/Havana"},
+	"IST": {"Asia/Kolkata", "Europe/Dublin", "Asia/Jerusalem"},
+	"AST": {"America/Halifax", "Asia/Riyadh"},
+}
+
+// timezoneRegions are the zone database's geographic areas. Names outside
+// them (US/Pacific, Etc/GMT+5, posix/...) are legacy aliases or duplicates.
+var timezoneRegions = []string{
+	"Africa", "America", "Antarctica", "Arctic", "Asia",
+	"Atlantic", "Australia", "Europe", "Indian", "Pacific",
+}
+
+// zoneinfoSources are the system zone database locations the time package
+// searches on Unix. $ZONEINFO is checked first.
+var zoneinfoSources = []string{
+	"/usr/share/zoneinfo",
+	"/usr/share/lib/zoneinfo",
+	"/usr/lib/locale/TZ",
+	"/etc/zoneinfo",
+}
+
+var (
+	zoneNamesOnce sync.Once
+	zoneNames     []string
+)
+
+// timezoneNames returns the geographic zone names in the zone database,
+// read once from the first source available. It is empty when the system
+// has no zone database; lookups then rely on the embedded one.
+func timezoneNames() []string {
+	zoneNamesOnce.Do(func() {
+		sources := zoneinfoSources
+		if env := os.Getenv("ZONEINFO"); env != "" {
+			sources = append([]string{env}, sources...)
+		}
+		for _, source := range sources {
+			if names := readZoneNames(source); len(names) > 0 {
+				zoneNames = names
+				return
+			}
+		}
+	})
+	return zoneNames
+}
+
+// readZoneNames lists the geographic zone names in a zoneinfo directory or
+// zip archive. Unreadable sources yield nil.
+func readZoneNames(source string) []string {
+	var names []string
+	add := func(name string) {
+		region, _, ok := strings.Cut(name, "/")
+		if ok && slices.Contains(timezoneRegions, region) {
+			names = append(names, name)
+		}
+	}
+
+	if strings.HasSuffix(source, ".zip") {
+		archive, err := zip.OpenReader(source)
+		if err != nil {
+			return nil
+		}
+		defer archive.Close()
+		for _, f := range archive.File {
+			add(f.Name)
+		}
+		return names
+	}
+
+	_ = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(source, path); err == nil {
+			add(filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return names
+}
+
+// lookupTimezoneName finds zones whose full name or city component matches
+// the normalized input. Without a system zone database it probes each
+// region with time.LoadLocation instead.
+func lookupTimezoneName(normalized, input string) []string {
+	names := timezoneNames()
+	if len(names) == 0 {
+		return probeTimezoneRegions(input)
+	}
+
+	var matches []string
+	for _, tz := range names {
+		city := tz[strings.LastIndex(tz, "/")+1:]
+		if normalizeTimezoneName(tz) == normalized || normalizeTimezoneName(city) == normalized {
+			matches = append(matches, tz)
+		}
+	}
+	return dedupeTimezones(matches)
+}
+
+// probeTimezoneRegions tries input as a city in each region, e.g. "new york"
+// as America/New_York, and as a full name with each part title-cased.
+func probeTimezoneRegions(input string) []string {
+	parts := strings.Split(input, "/")
+	for i, part := range parts {
+		words := strings.FieldsFunc(part, func(r rune) bool { return r == ' ' || r == '_' || r == '-' })
+		for j, word := range words {
+			words[j] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+		}
+		parts[i] = strings.Join(words, "_")
+	}
+	name := strings.Join(parts, "/")
+
+	candidates := []string{name}
+	if len(parts) == 1 {
+		candidates = candidates[:0]
+		for _, region := range timezoneRegions {
+			candidates = append(candidates, region+"/"+name)
+		}
+	}
+	var matches []string
+	for _, tz := range candidates {
+		if _, err := time.LoadLocation(tz); err == nil {
+			matches = append(matches, tz)
+		}
+	}
+	return dedupeTimezones(matches)
+}
+
+// dedupeTimezones collapses names that are links to the same zone (like
+// America/Buenos_Aires and America/Argentina/Buenos_Aires), keeping the most
+// specific one, and returns the rest sorted.
+func dedupeTimezones(names []string) []string {
+	sort.Slice(names, func(i, j int) bool {
+		di, dj := strings.Count(names[i], "/"), strings.Count(names[j], "/")
+		if di != dj {
+			return di > dj
+		}
+		return names[i] < names[j]
+	})
+
+	var kept []string
+	var locations []*time.Location
+	for _, name := range names {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			continue
+		}
+		if !slices.ContainsFunc(locations, func(other *time.Location) bool { return sameTimezone(loc, other) }) {
+			kept = append(kept, name)
+			locations = append(locations, loc)
+		}
+	}
+	sort.Strings(kept)
+	return kept
+}
+
+// sameTimezone reports whether two locations use the same abbreviation and
+// offset at the start and middle of every year from 1970 to 2037.
+func sameTimezone(a, b *time.Location) bool {
+	for year := 1970; year <= 2037; year++ {
+		for _, month := range []time.Month{time.January, time.July} {
+			t := time.Date(year, month, 1, 12, 0, 0, 0, time.UTC)
+			nameA, offsetA := t.In(a).Zone()
+			nameB, offsetB := t.In(b).Zone()
+			if nameA != nameB || offsetA != offsetB {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// timezoneCityAliases covers common city names that are not a zone's city component.
+var timezoneCityAliases = map[string]string{
+	"sanfrancisco": "America/Los_Angeles",
+	"seattle":      "America/Los_Angeles",
+	"lasvegas":     "America/Los_Angeles",
+	"boston":       "America/New_York",
+	"miami":        "America/New_York",
+	"washington":   "America/New_York",
+	"atlanta":      "America/New_York",
+	"dallas":       "America/Chicago",
+	"houston":      "America/Chicago",
+	"austin":       "America/Chicago",
+	"beijing":      "Asia/Shanghai",
+	"mumbai":       "Asia/Kolkata",
+	"delhi":        "Asia/Kolkata",
+	"newdelhi":     "Asia/Kolkata",
+	"osaka":        "Asia/Tokyo",
+	"hanoi":        "Asia/Bangkok",
+	"saigon":       "Asia/Ho_Chi_Minh",
+	"kiev":         "Europe/Kyiv",
+}
+
+// normalizeTimezoneName lowercases a name and strips spaces, underscores,
+// hyphens and dots so "new york", "New_York" and "new-york" compare equal.
+func normalizeTimezoneName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch r {
+		case ' ', '_', '-', '.':
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// resolveTimezone maps user input to an IANA timezone.
+// Accepts IANA identifiers, common abbreviations and city names.
+// When the input is ambiguous, zone is empty and candidates lists the options.
+func resolveTimezone(input string) (zone string, candidates []string) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return "", nil
+	}
+
+	upper := strings.ToUpper(trimmed)
+	if options, ok := ambiguousTimezoneAbbreviations[upper]; ok {
+		return "", options
+	}
+	if iana, ok := timezoneAbbreviations[upper]; ok {
+		return iana, nil
+	}
+
+	normalized := normalizeTimezoneName(trimmed)
+	if normalized == "" {
+		return "", nil
+	}
+
+	// Exact identifiers ("Pacific/Fiji", "Etc/GMT+5") load directly
+	if _, err := time.LoadLocation(trimmed); err == nil && strings.Contains(trimmed, "/") {
+		return trimmed, nil
+	}
+
+	if iana, ok := timezoneCityAliases[normalized]; ok {
+		return iana, nil
+	}
+
+	// Case/space-insensitive match on the full identifier ("america/new york")
+	// or on its city component ("new york", "Buenos Aires")
+	matches := lookupTimezoneName(normalized, trimmed)
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return "", matches
+	}
+
+	// Fall back to the zone database for anything else it knows ("Japan")
+	if _, err := time.LoadLocation(trimmed); err == nil {
+		return trimmed, nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,136 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResolveTimezone tests resolution of IANA names, abbreviations and city names
+func TestResolveTimezone(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		expectZone string
+		ambiguous  []string
+	}{
+		{name: "IANA identifier", input: "America/New_York", expectZone: "America/New_York"},
+		{name: "IANA identifier lowercase", input: "asia/tokyo", expectZone: "Asia/Tokyo"},
+		{name: "IANA identifier not in city list", input: "Pacific/Fiji", expectZone: "Pacific/Fiji"},
+		{name: "UTC", input: "UTC", expectZone: "UTC"},
+		{name: "utc lowercase", input: "utc", expectZone: "UTC"},
+		{name: "EST", input: "EST", expectZone: "America/New_York"},
+		{name: "EDT lowercase", input: "edt", expectZone: "America/New_York"},
+		{name: "PST", input: "PST", expectZone: "America/Los_Angeles"},
+		{name: "JST", input: "JST", expectZone: "Asia/Tokyo"},
+		{name: "GMT", input: "GMT", expectZone: "Etc/GMT"},
+		{name: "ADT", input: "ADT", expectZone: "America/Halifax"},
+		{name: "BST", input: "BST", expectZone: "Europe/London"},
+		{name: "City with space", input: "New York", expectZone: "America/New_York"},
+		{name: "City lowercase", input: "tokyo", expectZone: "Asia/Tokyo"},
+		{name: "City with underscore", input: "los_angeles", expectZone: "America/Los_Angeles"},
+		{name: "City with hyphen", input: "Sao-Paulo", expectZone: "America/Sao_Paulo"},
+		{name: "Nested region city", input: "buenos aires", expectZone: "America/Argentina/Buenos_Aires"},
+		{name: "City outside common list", input: "ulaanbaatar", expectZone: "Asia/Ulaanbaatar"},
+		{name: "Full nested identifier lowercase", input: "america/indiana/indianapolis", expectZone: "America/Indiana/Indianapolis"},
+		{name: "City alias", input: "San Francisco", expectZone: "America/Los_Angeles"},
+		{name: "Surrounding whitespace", input: "  London  ", expectZone: "Europe/London"},
+		{name: "Ambiguous CST", input: "CST", ambiguous: []string{"America/Chicago", "Asia/Shanghai", "America/Havana"}},
+		{name: "Ambiguous IST", input: "ist", ambiguous: []string{"Asia/Kolkata", "Europe/Dublin", "Asia/Jerusalem"}},
+		{name: "Unknown", input: "Atlantis"},
+		{name: "Empty", input: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, candidates := resolveTimezone(tt.input)
+			assert.Equal(t, tt.expectZone, zone)
+			assert.Equal(t, tt.ambiguous, candidates)
+		})
+	}
+}
+
+// TestProbeTimezoneRegions tests the lookup used when there is no system zone database
+func TestProbeTimezoneRegions(t *testing.T) {
+	assert.Equal(t, []string{"America/New_York"}, probeTimezoneRegions("new york"))
+	assert.Equal(t, []string{"Asia/Tokyo"}, probeTimezoneRegions("asia/tokyo"))
+	assert.Equal(t, []string{"America/Sao_Paulo"}, probeTimezoneRegions("sao-paulo"))
+	assert.Empty(t, probeTimezoneRegions("Atlantis"))
+}
+
+// TestDedupeTimezones tests that links to the same zone collapse to the most specific name
+func TestDedupeTimezones(t *testing.T) {
+	assert.Equal(t, []string{"America/Argentina/Buenos_Aires"},
+		dedupeTimezones([]string{"America/Buenos_Aires", "America/Argentina/Buenos_Aires"}))
+	assert.Equal(t, []string{"America/Chicago", "Asia/Shanghai"},
+		dedupeTimezones([]string{"Asia/Shanghai", "America/Chicago"}))
+}
+
+// TestTimezoneConverterHandlerResolution tests that the handler accepts abbreviations and cities
+func TestTimezoneConverterHandlerResolution(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       string
+		to         string
+		expectFrom string
+		expectTo   string
+		expectTime string
+	}{
+		{name: "Abbreviations", from: "EST", to: "JST", expectFrom: "America/New_York", expectTo: "Asia/Tokyo", expectTime: "2024-01-16 00:00:00 JST"},
+		{name: "Cities", from: "new york", to: "London", expectFrom: "America/New_York", expectTo: "Europe/London", expectTime: "2024-01-15 15:00:00 GMT"},
+		{name: "Mixed", from: "America/New_York", to: "pst", expectFrom: "America/New_York", expectTo: "America/Los_Angeles", expectTime: "2024-01-15 07:00:00 PST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TimezoneConverterHandler(map[string]interface{}{
+				"from_timezone": tt.from,
+				"to_timezone":   tt.to,
+				"time":          "10:00",
+				"date":          "2024-01-15",
+			})
+			require.NoError(t, err)
+
+			resultMap, ok := result.(map[string]interface{})
+			require.True(t, ok)
+			assert.Nil(t, resultMap["error"])
+			assert.Equal(t, tt.expectFrom, resultMap["from_timezone"])
+			assert.Equal(t, tt.expectTo, resultMap["to_timezone"])
+			assert.Equal(t, tt.from, resultMap["from_input"])
+			assert.Equal(t, tt.expectTime, resultMap["converted_time"])
+		})
+	}
+}
+
+// TestTimezoneConverterHandlerErrors tests structured errors for ambiguous and unknown zones
+func TestTimezoneConverterHandlerErrors(t *testing.T) {
+	t.Run("ambiguous abbreviation lists candidates", func(t *testing.T) {
+		result, err := TimezoneConverterHandler(map[string]interface{}{
+			"from_timezone": "CST",
+			"to_timezone":   "UTC",
+		})
+		require.NoError(t, err)
+
+		resultMap := result.(map[string]interface{})
+		assert.Equal(t, true, resultMap["error"])
+		assert.Equal(t, "validation_error", resultMap["error_type"])
+		assert.Contains(t, resultMap["message"], "Ambiguous timezone 'CST'")
+
+		assert.Equal(t, "from_timezone", resultMap["field"])
+		assert.Equal(t, []string{"America/Chicago", "Asia/Shanghai", "America/Havana"}, resultMap["candidates"])
+	})
+
+	t.Run("unknown timezone", func(t *testing.T) {
+		result, err := TimezoneConverterHandler(map[string]interface{}{
+			"from_timezone": "UTC",
+			"to_timezone":   "Atlantis",
+		})
+		require.NoError(t, err)
+
+		resultMap := result.(map[string]interface{})
+		assert.Equal(t, true, resultMap["error"])
+		assert.Contains(t, resultMap["message"], "Invalid timezone 'Atlantis'")
+		assert.Equal(t, "to_timezone", resultMap["field"])
+	})
+}
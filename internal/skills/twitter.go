@@ -0,0 +1,313 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// twitterAPIBaseURL is the X API v2 endpoint. Overridden in tests.
+var twitterAPIBaseURL = "https://api.x.com/2"
+
+// Tweet length limits, in X's weighted characters.
+const (
+	tweetMaxLength = 280
+	tweetURLLength = 23 // Every link is shortened to a t.co URL of this length
+)
+
+// tweetURLPattern finds links, which count as tweetURLLength whatever their length.
+var tweetURLPattern = regexp.MustCompile(`https?://\S+`)
+
+// PostTweetSkill returns the X (Twitter) posting skill definition.
+func PostTweetSkill() Skill {
+	return Skill{
+		Name:        "post_tweet",
+		Description: "Post a tweet to the user's X (Twitter) account. Posting is public and can't be undone: first call with dry_run true to show the user a preview, and only post once they approve it.",
+		SideEffect:  true,
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"text": map[string]interface{}{
+					"type":        "string",
+					"description": "Tweet text (max 280 characters; links count as 23, most CJK characters and emoji as 2)",
+				},
+				"reply_to": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional ID of a tweet to reply to",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Preview the tweet and its length without posting it",
+				},
+			},
+			"required": []string{"text"},
+		},
+	}
+}
+
+// PostTweetHandler posts a tweet through the X API v2, or previews it
+// when dry_run is set.
+func PostTweetHandler(ctx context.Context, args map[string]interface{}, configLoader ConfigLoader) (interface{}, error) {
+	text, _ := args["text"].(string)
+	text = strings.TrimSpace(text)
+	replyTo, _ := args["reply_to"].(string)
+
+	length := weightedTweetLength(text)
+	if text == "" || length > tweetMaxLength {
+		message := "Tweet text is required"
+		if text != "" {
+			message = fmt.Sprintf("Tweet is %d characters, over the %d character limit", length, tweetMaxLength)
+		}
+		return formatErrorResponse(
+			"validation_error",
+			message,
+			"Shorten the tweet and try again.",
+			map[string]interface{}{
+				"skill":  "post_tweet",
+				"length": length,
+				"limit":  tweetMaxLength,
+			},
+		), nil
+	}
+
+	// The CLI passes --dry-run, the LLM passes dry_run
+	if isDryRun(args["dry_run"]) || isDryRun(args["dry-run"]) {
+		preview := map[string]interface{}{
+			"dry_run":   true,
+			"text":      text,
+			"length":    length,
+			"remaining": tweetMaxLength - length,
+			"message":   "Preview only; nothing was posted. Call again without dry_run to post.",
+		}
+		if replyTo != "" {
+			preview["reply_to"] = replyTo
+		}
+		return preview, nil
+	}
+
+	config, err := configLoader.GetTwitterConfig()
+	if err != nil || config.APIKey == "" || config.AccessToken == "" {
+		return formatErrorResponse(
+			"config_error",
+			"X (Twitter) API credentials are required to post. Add twitter_api_key, twitter_api_secret, twitter_access_token and twitter_access_token_secret to ~/.celeste/skills.json",
+			"Create an app with read and write permissions in the X developer portal and generate an access token and secret for your account.",
+			map[string]interface{}{
+				"skill": "post_tweet",
+			},
+		), nil
+	}
+
+	payload := map[string]interface{}{"text": text}
+	if replyTo != "" {
+		payload["reply"] = map[string]string{"in_reply_to_tweet_id": replyTo}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tweet: %w", err)
+	}
+
+	endpoint := twitterAPIBaseURL + "/tweets"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", oauth1Header("POST", endpoint, config))
+
+	client := httpclient.New(0)
+	resp, err := client.Do(req)
+	if err != nil {
+		if cancelled := contextErrorResponse(ctx, "post_tweet"); cancelled != nil {
+			return cancelled, nil
+		}
+		return formatErrorResponse(
+			"network_error",
+			"Failed to connect to the X API",
+			"Please check your internet connection and try again.",
+			map[string]interface{}{
+				"skill": "post_tweet",
+				"error": err.Error(),
+			},
+		), nil
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return tweetErrorResponse(resp, respBody), nil
+	}
+
+	var created struct {
+		Data struct {
+			ID   string `json:"id"`
+			Text string `json:"text"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &created); err != nil || created.Data.ID == "" {
+		return formatErrorResponse(
+			"api_error",
+			"Failed to parse X API response",
+			"The tweet may have been posted; check the account before retrying.",
+			map[string]interface{}{
+				"skill":    "post_tweet",
+				"response": string(respBody),
+			},
+		), nil
+	}
+
+	return map[string]interface{}{
+		"success":     true,
+		"status_code": resp.StatusCode,
+		"tweet_id":    created.Data.ID,
+		"url":         "https://x.com/i/web/status/" + created.Data.ID,
+		"text":        created.Data.Text,
+	}, nil
+}
+
+// tweetErrorResponse describes a failed POST /tweets.
+func tweetErrorResponse(resp *http.Response, body []byte) map[string]interface{} {
+	var apiErr struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	}
+	_ = json.Unmarshal(body, &apiErr)
+	detail := apiErr.Detail
+	if detail == "" {
+		detail = string(body)
+	}
+
+	details := map[string]interface{}{
+		"skill":       "post_tweet",
+		"status_code": resp.StatusCode,
+		"response":    detail,
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		hint := "Wait before posting again."
+		if reset, err := strconv.ParseInt(resp.Header.Get("x-rate-limit-reset"), 10, 64); err == nil {
+			resetAt := time.Unix(reset, 0)
+			details["reset_at"] = resetAt.Format(time.RFC3339)
+			hint = fmt.Sprintf("Posting is allowed again at %s.", resetAt.Format("15:04"))
+		}
+		return formatErrorResponse("rate_limited", "X API rate limit reached", hint, details)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return formatErrorResponse(
+			"auth_error",
+			fmt.Sprintf("X API refused the tweet (status %d): %s", resp.StatusCode, detail),
+			"Check the credentials in skills.json and that the app has read and write permissions. Duplicate tweets are also refused.",
+			details,
+		)
+	default:
+		return formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("X API returned error (status %d)", resp.StatusCode),
+			"The X API may be temporarily unavailable.",
+			details,
+		)
+	}
+}
+
+// isDryRun reads a dry-run argument given as a bool or a string.
+func isDryRun(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		b, err := strconv.ParseBool(v)
+		return err == nil && b
+	}
+	return false
+}
+
+// weightedTweetLength approximates X's character counting: links count as
+// 23, characters in the Latin and general punctuation ranges as 1, and
+// everything else (CJK, emoji) as 2.
+func weightedTweetLength(text string) int {
+	length := 0
+	rest := tweetURLPattern.ReplaceAllStringFunc(text, func(string) string {
+		length += tweetURLLength
+		return ""
+	})
+	for _, r := range rest {
+		switch {
+		case r <= 4351, r >= 8192 && r <= 8205, r >= 8208 && r <= 8223, r >= 8242 && r <= 8247:
+			length++
+		default:
+			length += 2
+		}
+	}
+	return length
+}
+
+// oauth1Header builds an OAuth 1.0a Authorization header for a request
+// whose body is JSON, so only the OAuth parameters are signed.
+func oauth1Header(method, endpoint string, config TwitterConfig) string {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+
+	params := map[string]string{
+		"oauth_consumer_key":     config.APIKey,
+		"oauth_nonce":            hex.EncodeToString(nonce),
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_token":            config.AccessToken,
+		"oauth_version":          "1.0",
+	}
+	params["oauth_signature"] = oauth1Signature(method, endpoint, params, config.APISecret, config.AccessTokenSecret)
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf(`%s="%s"`, oauthEscape(k), oauthEscape(params[k]))
+	}
+	return "OAuth " + strings.Join(parts, ", ")
+}
+
+// oauth1Signature computes the HMAC-SHA1 signature over the request method,
+// URL and sorted parameters.
+func oauth1Signature(method, endpoint string, params map[string]string, consumerSecret, tokenSecret string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = oauthEscape(k) + "=" + oauthEscape(params[k])
+	}
+
+	base := strings.ToUpper(method) + "&" + oauthEscape(endpoint) + "&" + oauthEscape(strings.Join(pairs, "&"))
+	mac := hmac.New(sha1.New, []byte(oauthEscape(consumerSecret)+"&"+oauthEscape(tokenSecret)))
+	mac.Write([]byte(base))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// oauthEscape percent-encodes s as RFC 3986 requires for OAuth.
+func oauthEscape(s string) string {
+	escaped := url.QueryEscape(s)
+	escaped = strings.ReplaceAll(escaped, "+", "%20")
+	escaped = strings.ReplaceAll(escaped, "*", "%2A")
+	return strings.ReplaceAll(escaped, "%7E", "~")
+}
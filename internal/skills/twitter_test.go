@@ -0,0 +1,121 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTwitterAPI serves POST /tweets and records the decoded request bodies.
+func stubTwitterAPI(t *testing.T, status int, response string) *[]map[string]interface{} {
+	t.Helper()
+	bodies := []map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/tweets", r.URL.Path)
+		auth := r.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(auth, "OAuth "), auth)
+		assert.Contains(t, auth, `oauth_consumer_key="mock-twitter-key"`)
+		assert.Contains(t, auth, `oauth_token="mock-access-token"`)
+		assert.Contains(t, auth, `oauth_signature="`)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	original := twitterAPIBaseURL
+	twitterAPIBaseURL = server.URL
+	t.Cleanup(func() { twitterAPIBaseURL = original })
+	return &bodies
+}
+
+func TestPostTweetHandler(t *testing.T) {
+	bodies := stubTwitterAPI(t, http.StatusCreated, `{"data":{"id":"1850000000000000000","text":"Live now!"}}`)
+
+	result, err := PostTweetHandler(context.Background(), map[string]interface{}{
+		"text":     "Live now!",
+		"reply_to": "1849999999999999999",
+	}, NewMockConfigLoader())
+	require.NoError(t, err)
+
+	data := result.(map[string]interface{})
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, "1850000000000000000", data["tweet_id"])
+	assert.Equal(t, "https://x.com/i/web/status/1850000000000000000", data["url"])
+
+	require.Len(t, *bodies, 1)
+	assert.Equal(t, map[string]interface{}{
+		"text":  "Live now!",
+		"reply": map[string]interface{}{"in_reply_to_tweet_id": "1849999999999999999"},
+	}, (*bodies)[0])
+}
+
+func TestPostTweetHandlerDryRun(t *testing.T) {
+	bodies := stubTwitterAPI(t, http.StatusCreated, `{}`)
+
+	// The CLI passes --dry-run as a boolean flag
+	for _, args := range []map[string]interface{}{
+		{"text": "Going live at 8 https://twitch.tv/whykusanagi", "dry_run": true},
+		{"text": "Going live at 8 https://twitch.tv/whykusanagi", "dry-run": true},
+	} {
+		result, err := PostTweetHandler(context.Background(), args, &MockConfigLoader{})
+		require.NoError(t, err)
+		data := result.(map[string]interface{})
+		assert.Equal(t, true, data["dry_run"])
+		assert.Equal(t, 16+tweetURLLength, data["length"])
+		assert.Equal(t, tweetMaxLength-16-tweetURLLength, data["remaining"])
+	}
+	assert.Empty(t, *bodies, "dry runs never post")
+}
+
+func TestPostTweetHandlerErrors(t *testing.T) {
+	stubTwitterAPI(t, http.StatusForbidden, `{"title":"Forbidden","detail":"You are not allowed to create a Tweet with duplicate content."}`)
+
+	result, err := PostTweetHandler(context.Background(), map[string]interface{}{"text": strings.Repeat("a", 281)}, NewMockConfigLoader())
+	require.NoError(t, err)
+	assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+
+	result, err = PostTweetHandler(context.Background(), map[string]interface{}{"text": "hi"}, NewMockConfigLoaderWithErrors())
+	require.NoError(t, err)
+	assert.Equal(t, "config_error", result.(map[string]interface{})["error_type"])
+
+	result, err = PostTweetHandler(context.Background(), map[string]interface{}{"text": "hi"}, NewMockConfigLoader())
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, "auth_error", data["error_type"])
+	assert.Contains(t, data["message"], "duplicate content")
+}
+
+func TestWeightedTweetLength(t *testing.T) {
+	assert.Equal(t, 5, weightedTweetLength("hello"))
+	assert.Equal(t, 4, weightedTweetLength("日本"))
+	assert.Equal(t, 6+tweetURLLength, weightedTweetLength("watch https://example.com/a/very/long/path?with=query"))
+	assert.Equal(t, 2, weightedTweetLength("🔥"))
+}
+
+// TestOAuth1Signature checks the signature against X's documented example
+func TestOAuth1Signature(t *testing.T) {
+	params := map[string]string{
+		"include_entities":       "true",
+		"oauth_consumer_key":     "xvz1evFS4wEEPTGEFPHBog",
+		"oauth_nonce":            "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg",
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        "1318622958",
+		"oauth_token":            "370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb",
+		"oauth_version":          "1.0",
+		"status":                 "Hello Ladies + Gentlemen, a signed OAuth request!",
+	}
+	signature := oauth1Signature("POST", "https://api.twitter.com/1.1/statuses/update.json", params,
+		"kAcSOqF21Fu85e7zjz7ZN2U4ZRhfV3WpwPAoE3Z7kBw", "LswwdoUaIvS8ltyTt5jkRh4J50vUPVVHtR2YPi5kE")
+	assert.Equal(t, "hCtSmYh+iHYCEqBWrE7C7hYmtUk=", signature)
+}
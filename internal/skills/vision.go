@@ -0,0 +1,188 @@
+// Package skills provides vision skills registration
+package skills
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// MaxImageBytes is the default cap on local images sent to the vision model.
+const MaxImageBytes = 20 * 1024 * 1024
+
+// describeImageTimeout bounds a single describe_image request.
+const describeImageTimeout = 90 * time.Second
+
+// defaultDescribePrompt is used when the caller doesn't ask anything specific.
+const defaultDescribePrompt = "Describe this image in detail. Include the subject, composition, style, colors and any visible text. Keep it suitable for use as an image generation prompt."
+
+// ImageDescriber sends an image to a vision-capable chat model.
+// Implemented by the LLM client; defined here so skills don't import llm.
+type ImageDescriber interface {
+	SupportsVision() bool
+	DescribeImage(ctx context.Context, imageURL, prompt string) (string, error)
+}
+
+// RegisterVisionSkills registers skills that need a vision-capable model.
+func RegisterVisionSkills(registry *Registry, describer ImageDescriber) {
+	registry.RegisterSkill(DescribeImageSkill())
+	registry.RegisterContextHandler("describe_image", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return DescribeImageHandler(ctx, args, describer)
+	})
+}
+
+// DescribeImageSkill returns the image description skill definition.
+func DescribeImageSkill() Skill {
+	return Skill{
+		Name:        "describe_image",
+		Description: "Describe an image (local file path or URL) using the current vision-capable model. Useful for turning an image into a prompt.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"image": map[string]interface{}{
+					"type":        "string",
+					"description": "Local file path or http(s) URL of the image",
+				},
+				"prompt": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional instruction for the model (default: detailed description suitable as an image prompt)",
+				},
+			},
+			"required": []string{"image"},
+		},
+	}
+}
+
+// DescribeImageHandler handles the describe_image skill execution.
+// The request is bounded by describeImageTimeout and by ctx.
+func DescribeImageHandler(ctx context.Context, args map[string]interface{}, describer ImageDescriber) (interface{}, error) {
+	image, ok := args["image"].(string)
+	image = strings.TrimSpace(image)
+	if !ok || image == "" {
+		return formatErrorResponse(
+			"validation_error",
+			"The 'image' parameter is required",
+			"Please provide a local file path or an http(s) URL of the image to describe.",
+			map[string]interface{}{
+				"skill": "describe_image",
+				"field": "image",
+			},
+		), nil
+	}
+
+	if describer == nil || !describer.SupportsVision() {
+		return formatErrorResponse(
+			"config_error",
+			"The current model does not support image input",
+			"Switch to a vision-capable model (e.g. gpt-4o, gemini-2.0-flash, grok-4) and try again.",
+			map[string]interface{}{
+				"skill": "describe_image",
+			},
+		), nil
+	}
+
+	prompt, _ := args["prompt"].(string)
+	if strings.TrimSpace(prompt) == "" {
+		prompt = defaultDescribePrompt
+	}
+
+	ReportProgress(ctx, "loading image")
+	imageURL, err := LoadImageURL(image)
+	if err != nil {
+		return formatErrorResponse(
+			"validation_error",
+			"Could not load image",
+			"Make sure the path points to a readable PNG, JPEG, GIF or WebP file under 20MB.",
+			map[string]interface{}{
+				"skill":  "describe_image",
+				"field":  "image",
+				"image":  image,
+				"reason": err.Error(),
+			},
+		), nil
+	}
+
+	ReportProgress(ctx, "asking the vision model")
+	ctx, cancel := context.WithTimeout(ctx, describeImageTimeout)
+	defer cancel()
+
+	description, err := describer.DescribeImage(ctx, imageURL, prompt)
+	if err != nil {
+		return formatErrorResponse(
+			"api_error",
+			"Failed to describe image",
+			"The vision model request failed. Check your connection and API key, then try again.",
+			map[string]interface{}{
+				"skill":  "describe_image",
+				"reason": err.Error(),
+			},
+		), nil
+	}
+
+	return map[string]interface{}{
+		"image":       image,
+		"description": description,
+	}, nil
+}
+
+// LoadImageURL returns a URL a vision model can read.
+// Remote and data: URLs pass through; local files are inlined as base64 data URLs.
+func LoadImageURL(image string) (string, error) {
+	url, _, err := LoadImage(image, MaxImageBytes)
+	return url, err
+}
+
+// LoadImage is LoadImageURL with a size cap for local files (MaxImageBytes
+// if maxBytes <= 0). It also returns the file size, or 0 for URLs.
+// Used by the /image chat command.
+func LoadImage(image string, maxBytes int64) (string, int64, error) {
+	if maxBytes <= 0 {
+		maxBytes = MaxImageBytes
+	}
+	lower := strings.ToLower(image)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "data:") {
+		return image, 0, nil
+	}
+
+	path := paths.ExpandHome(image)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	if info.IsDir() {
+		return "", 0, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > maxBytes {
+		return "", 0, fmt.Errorf("image is %s, limit is %s", FormatImageSize(info.Size()), FormatImageSize(maxBytes))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return "", 0, fmt.Errorf("unsupported file type %s", mimeType)
+	}
+
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), info.Size(), nil
+}
+
+// FormatImageSize formats a file size for messages, e.g. "1.2MB" or "340KB".
+func FormatImageSize(bytes int64) string {
+	switch {
+	case bytes >= 1024*1024:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(bytes)/(1024*1024)), ".0") + "MB"
+	case bytes >= 1024:
+		return fmt.Sprintf("%dKB", bytes/1024)
+	}
+	return fmt.Sprintf("%dB", bytes)
+}
@@ -0,0 +1,104 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDescriber records the image it was asked to describe
+type mockDescriber struct {
+	vision    bool
+	response  string
+	err       error
+	gotImage  string
+	gotPrompt string
+}
+
+func (m *mockDescriber) SupportsVision() bool { return m.vision }
+
+func (m *mockDescriber) DescribeImage(ctx context.Context, imageURL, prompt string) (string, error) {
+	m.gotImage = imageURL
+	m.gotPrompt = prompt
+	return m.response, m.err
+}
+
+// pngHeader is enough of a PNG for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// TestDescribeImageHandler tests the describe_image skill against a mock vision model
+func TestDescribeImageHandler(t *testing.T) {
+	t.Run("Remote URL passes through", func(t *testing.T) {
+		describer := &mockDescriber{vision: true, response: "a cat on a sofa"}
+		result, err := DescribeImageHandler(context.Background(), map[string]interface{}{"image": "https://example.com/cat.png"}, describer)
+		require.NoError(t, err)
+
+		resultMap := result.(map[string]interface{})
+		assert.Equal(t, "a cat on a sofa", resultMap["description"])
+		assert.Equal(t, "https://example.com/cat.png", describer.gotImage)
+		assert.Equal(t, defaultDescribePrompt, describer.gotPrompt)
+	})
+
+	t.Run("Local file becomes data URL", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "image.png")
+		require.NoError(t, os.WriteFile(path, pngHeader, 0644))
+
+		describer := &mockDescriber{vision: true, response: "ok"}
+		_, err := DescribeImageHandler(context.Background(), map[string]interface{}{"image": path, "prompt": "What color?"}, describer)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(describer.gotImage, "data:image/png;base64,"))
+		assert.Equal(t, "What color?", describer.gotPrompt)
+	})
+
+	t.Run("Reports progress", func(t *testing.T) {
+		var statuses []string
+		ctx := WithProgress(context.Background(), func(status string) { statuses = append(statuses, status) })
+		_, err := DescribeImageHandler(ctx, map[string]interface{}{"image": "https://example.com/cat.png"}, &mockDescriber{vision: true, response: "ok"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"loading image", "asking the vision model"}, statuses)
+	})
+
+	t.Run("Text-only model errors cleanly", func(t *testing.T) {
+		describer := &mockDescriber{vision: false}
+		result, err := DescribeImageHandler(context.Background(), map[string]interface{}{"image": "https://example.com/cat.png"}, describer)
+		require.NoError(t, err)
+
+		resultMap := result.(map[string]interface{})
+		assert.Equal(t, true, resultMap["error"])
+		assert.Equal(t, "config_error", resultMap["error_type"])
+		assert.Empty(t, describer.gotImage, "no request should be sent")
+	})
+
+	t.Run("Missing image", func(t *testing.T) {
+		result, err := DescribeImageHandler(context.Background(), map[string]interface{}{}, &mockDescriber{vision: true})
+		require.NoError(t, err)
+		assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+	})
+
+	t.Run("Non-image file rejected", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notes.txt")
+		require.NoError(t, os.WriteFile(path, []byte("just text"), 0644))
+
+		describer := &mockDescriber{vision: true}
+		result, err := DescribeImageHandler(context.Background(), map[string]interface{}{"image": path}, describer)
+		require.NoError(t, err)
+
+		resultMap := result.(map[string]interface{})
+		assert.Equal(t, "validation_error", resultMap["error_type"])
+		assert.Contains(t, resultMap["reason"], "unsupported file type")
+	})
+
+	t.Run("Model error surfaces as api_error", func(t *testing.T) {
+		describer := &mockDescriber{vision: true, err: errors.New("boom")}
+		result, err := DescribeImageHandler(context.Background(), map[string]interface{}{"image": "https://example.com/cat.png"}, describer)
+		require.NoError(t, err)
+		assert.Equal(t, "api_error", result.(map[string]interface{})["error_type"])
+	})
+}
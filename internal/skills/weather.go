@@ -0,0 +1,218 @@
+package skills
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// wttrValue is a wttr.in text field, wrapped in a list of {"value": ...}.
+type wttrValue []struct {
+	Value string `json:"value"`
+}
+
+func (v wttrValue) String() string {
+	if len(v) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(v[0].Value)
+}
+
+// wttrConditions are the fields shared by current_condition and hourly
+// entries. wttr.in sends every number as a string.
+type wttrConditions struct {
+	TempC          string    `json:"temp_C"`
+	TempF          string    `json:"temp_F"`
+	FeelsLikeC     string    `json:"FeelsLikeC"`
+	FeelsLikeF     string    `json:"FeelsLikeF"`
+	Humidity       string    `json:"humidity"`
+	WindspeedKmph  string    `json:"windspeedKmph"`
+	WindspeedMiles string    `json:"windspeedMiles"`
+	WindDir        string    `json:"winddir16Point"`
+	PrecipMM       string    `json:"precipMM"`
+	PrecipInches   string    `json:"precipInches"`
+	ChanceOfRain   string    `json:"chanceofrain"`
+	WeatherDesc    wttrValue `json:"weatherDesc"`
+}
+
+// wttrResponse is the part of wttr.in's format=j1 response Celeste uses.
+type wttrResponse struct {
+	CurrentCondition []wttrConditions `json:"current_condition"`
+	NearestArea      []struct {
+		AreaName  wttrValue `json:"areaName"`
+		Region    wttrValue `json:"region"`
+		Country   wttrValue `json:"country"`
+		Latitude  string    `json:"latitude"`
+		Longitude string    `json:"longitude"`
+	} `json:"nearest_area"`
+	Weather []struct {
+		Date     string           `json:"date"`
+		MaxTempC string           `json:"maxtempC"`
+		MaxTempF string           `json:"maxtempF"`
+		MinTempC string           `json:"mintempC"`
+		MinTempF string           `json:"mintempF"`
+		Hourly   []wttrConditions `json:"hourly"`
+	} `json:"weather"`
+}
+
+// wttrUnitedStates is how wttr.in names the United States.
+const wttrUnitedStates = "United States of America"
+
+// country is the country wttr.in resolved the query to, if any.
+func (r wttrResponse) country() string {
+	if len(r.NearestArea) == 0 {
+		return ""
+	}
+	return r.NearestArea[0].Country.String()
+}
+
+// WeatherUnits names the units get_weather reports in.
+type WeatherUnits struct {
+	System        string `json:"system"` // "metric" or "imperial"
+	Temperature   string `json:"temperature"`
+	WindSpeed     string `json:"wind_speed"`
+	Precipitation string `json:"precipitation"`
+}
+
+var (
+	metricUnits   = WeatherUnits{System: "metric", Temperature: "°C", WindSpeed: "km/h", Precipitation: "mm"}
+	imperialUnits = WeatherUnits{System: "imperial", Temperature: "°F", WindSpeed: "mph", Precipitation: "in"}
+)
+
+// weatherUnits picks the unit system: the requested one, else the
+// configured one, else imperial for the United States and metric
+// everywhere else. It returns false for an unknown system name.
+func weatherUnits(requested, configured, country string) (WeatherUnits, bool) {
+	system := strings.ToLower(strings.TrimSpace(requested))
+	if system == "" {
+		system = strings.ToLower(configured)
+	}
+	switch system {
+	case "metric":
+		return metricUnits, true
+	case "imperial":
+		return imperialUnits, true
+	case "", "auto":
+		if country == wttrUnitedStates {
+			return imperialUnits, true
+		}
+		return metricUnits, true
+	}
+	return WeatherUnits{}, false
+}
+
+// CurrentWeather is the compact current conditions returned by get_weather,
+// in the units named by the result's WeatherUnits.
+type CurrentWeather struct {
+	Temp          int     `json:"temp"`
+	FeelsLike     int     `json:"feels_like"`
+	Condition     string  `json:"condition"`
+	Humidity      int     `json:"humidity_pct"`
+	WindSpeed     int     `json:"wind_speed"`
+	WindDir       string  `json:"wind_direction,omitempty"`
+	Precipitation float64 `json:"precipitation"`
+}
+
+// ForecastDay summarizes one day of the forecast.
+type ForecastDay struct {
+	Date          string  `json:"date"`
+	High          int     `json:"high"`
+	Low           int     `json:"low"`
+	Condition     string  `json:"condition,omitempty"`
+	ChanceOfRain  int     `json:"chance_of_rain_pct"`
+	Precipitation float64 `json:"precipitation"`
+}
+
+// summarizeWeather turns a wttr.in response into the compact current
+// conditions, the area wttr.in resolved the query to, and up to days of
+// forecast, in units.
+func summarizeWeather(resp wttrResponse, days int, units WeatherUnits) (*CurrentWeather, string, []ForecastDay) {
+	imperial := units.System == "imperial"
+	// pick chooses the metric or imperial reading of a pair
+	pick := func(metric, imperialValue string) string {
+		if imperial {
+			return imperialValue
+		}
+		return metric
+	}
+	// precipitation reads an entry's precipitation in the chosen units
+	precipitation := func(c wttrConditions) float64 {
+		if imperial {
+			return wttrFloat(c.PrecipInches)
+		}
+		return wttrFloat(c.PrecipMM)
+	}
+	// round keeps 0.1 mm or 0.01 in
+	round := func(f float64) float64 {
+		scale := 10.0
+		if imperial {
+			scale = 100
+		}
+		return math.Round(f*scale) / scale
+	}
+
+	var current *CurrentWeather
+	if len(resp.CurrentCondition) > 0 {
+		c := resp.CurrentCondition[0]
+		current = &CurrentWeather{
+			Temp:          wttrInt(pick(c.TempC, c.TempF)),
+			FeelsLike:     wttrInt(pick(c.FeelsLikeC, c.FeelsLikeF)),
+			Condition:     c.WeatherDesc.String(),
+			Humidity:      wttrInt(c.Humidity),
+			WindSpeed:     wttrInt(pick(c.WindspeedKmph, c.WindspeedMiles)),
+			WindDir:       c.WindDir,
+			Precipitation: round(precipitation(c)),
+		}
+	}
+
+	var area string
+	if len(resp.NearestArea) > 0 {
+		var parts []string
+		for _, part := range []wttrValue{resp.NearestArea[0].AreaName, resp.NearestArea[0].Region, resp.NearestArea[0].Country} {
+			if s := part.String(); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		area = strings.Join(parts, ", ")
+	}
+
+	forecast := []ForecastDay{}
+	for i, day := range resp.Weather {
+		if i >= days {
+			break
+		}
+		summary := ForecastDay{
+			Date: day.Date,
+			High: wttrInt(pick(day.MaxTempC, day.MaxTempF)),
+			Low:  wttrInt(pick(day.MinTempC, day.MinTempF)),
+		}
+		// The midday reading describes the day; rain takes the day's worst hour
+		if len(day.Hourly) > 0 {
+			summary.Condition = day.Hourly[len(day.Hourly)/2].WeatherDesc.String()
+		}
+		// Hourly entries each cover three hours, so their precipitation adds
+		// up to the day's total
+		var total float64
+		for _, hour := range day.Hourly {
+			if chance := wttrInt(hour.ChanceOfRain); chance > summary.ChanceOfRain {
+				summary.ChanceOfRain = chance
+			}
+			total += precipitation(hour)
+		}
+		summary.Precipitation = round(total)
+		forecast = append(forecast, summary)
+	}
+	return current, area, forecast
+}
+
+// wttrInt parses a wttr.in number, treating anything unparseable as 0.
+func wttrInt(s string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(s))
+	return n
+}
+
+// wttrFloat parses a wttr.in decimal, treating anything unparseable as 0.
+func wttrFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f
+}
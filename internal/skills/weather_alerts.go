@@ -0,0 +1,90 @@
+package skills
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nwsAPIBaseURL is the US National Weather Service API. Overridden in tests.
+var nwsAPIBaseURL = "https://api.weather.gov"
+
+// weatherAlertsCacheTTL is how long active alerts are reused. Alerts change
+// faster than forecasts, so this is shorter than weatherCacheTTL.
+const weatherAlertsCacheTTL = 5 * time.Minute
+
+// maxAlertInstructionRunes caps the safety instructions kept per alert.
+const maxAlertInstructionRunes = 300
+
+// WeatherAlert is an active severe weather alert.
+type WeatherAlert struct {
+	Event       string `json:"event"`
+	Headline    string `json:"headline,omitempty"`
+	Severity    string `json:"severity,omitempty"` // Extreme, Severe, Moderate, Minor or Unknown
+	Urgency     string `json:"urgency,omitempty"`
+	Onset       string `json:"onset,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	Area        string `json:"area,omitempty"`
+	Instruction string `json:"instruction,omitempty"`
+}
+
+// nwsAlertsResponse is the part of /alerts/active Celeste uses.
+type nwsAlertsResponse struct {
+	Features []struct {
+		Properties struct {
+			Event       string `json:"event"`
+			Headline    string `json:"headline"`
+			Severity    string `json:"severity"`
+			Urgency     string `json:"urgency"`
+			Onset       string `json:"onset"`
+			Effective   string `json:"effective"`
+			Expires     string `json:"expires"`
+			AreaDesc    string `json:"areaDesc"`
+			Instruction string `json:"instruction"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// lookupWeatherAlerts fetches active alerts for the area wttr.in resolved
+// the query to. Only the NWS is supported, so areas outside the US aren't
+// checked; checked reports whether a lookup was made. source "off" turns
+// alerts off.
+func lookupWeatherAlerts(ctx context.Context, source string, report wttrResponse) (alerts []WeatherAlert, checked bool, err error) {
+	if source == "off" || report.country() != wttrUnitedStates {
+		return nil, false, nil
+	}
+	area := report.NearestArea[0]
+	lat, latErr := strconv.ParseFloat(area.Latitude, 64)
+	lon, lonErr := strconv.ParseFloat(area.Longitude, 64)
+	if latErr != nil || lonErr != nil {
+		return nil, false, nil
+	}
+
+	// The NWS rejects points with more than four decimal places
+	point := strconv.FormatFloat(lat, 'f', 4, 64) + "," + strconv.FormatFloat(lon, 'f', 4, 64)
+	var resp nwsAlertsResponse
+	if err := CachedGetJSON(ctx, nwsAPIBaseURL+"/alerts/active?point="+point, weatherAlertsCacheTTL, &resp); err != nil {
+		return nil, true, err
+	}
+
+	alerts = []WeatherAlert{}
+	for _, feature := range resp.Features {
+		p := feature.Properties
+		onset := p.Onset
+		if onset == "" {
+			onset = p.Effective
+		}
+		alerts = append(alerts, WeatherAlert{
+			Event:       p.Event,
+			Headline:    p.Headline,
+			Severity:    p.Severity,
+			Urgency:     p.Urgency,
+			Onset:       onset,
+			Expires:     p.Expires,
+			Area:        p.AreaDesc,
+			Instruction: truncateRunes(strings.Join(strings.Fields(p.Instruction), " "), maxAlertInstructionRunes),
+		})
+	}
+	return alerts, true, nil
+}
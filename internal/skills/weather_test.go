@@ -0,0 +1,205 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wttrSample is a trimmed wttr.in format=j1 response.
+const wttrSample = `{
+	"current_condition": [{
+		"temp_C": "7", "temp_F": "45", "FeelsLikeC": "4", "FeelsLikeF": "39",
+		"humidity": "81", "windspeedKmph": "19", "windspeedMiles": "12", "winddir16Point": "NW",
+		"precipMM": "0.3", "precipInches": "0.01",
+		"weatherDesc": [{"value": "Partly cloudy"}], "pressure": "1012", "uvIndex": "1"
+	}],
+	"nearest_area": [{
+		"areaName": [{"value": "New York"}], "region": [{"value": "New York"}], "country": [{"value": "United States of America"}],
+		"latitude": "40.714", "longitude": "-74.006"
+	}],
+	"weather": [
+		{"date": "2025-06-01", "maxtempC": "12", "maxtempF": "54", "mintempC": "5", "mintempF": "41", "hourly": [
+			{"chanceofrain": "10", "precipMM": "0.0", "precipInches": "0.0", "weatherDesc": [{"value": "Cloudy"}]},
+			{"chanceofrain": "80", "precipMM": "2.4", "precipInches": "0.09", "weatherDesc": [{"value": "Light rain"}]},
+			{"chanceofrain": "30", "precipMM": "0.2", "precipInches": "0.01", "weatherDesc": [{"value": "Overcast"}]}
+		]},
+		{"date": "2025-06-02", "maxtempC": "15", "maxtempF": "59", "mintempC": "8", "mintempF": "46", "hourly": [
+			{"chanceofrain": "0", "weatherDesc": [{"value": "Sunny"}]}
+		]},
+		{"date": "2025-06-03", "maxtempC": "18", "maxtempF": "64", "mintempC": "9", "mintempF": "48", "hourly": []}
+	]
+}`
+
+func TestSummarizeWeather(t *testing.T) {
+	var resp wttrResponse
+	require.NoError(t, json.Unmarshal([]byte(wttrSample), &resp))
+
+	current, area, forecast := summarizeWeather(resp, 2, imperialUnits)
+	assert.Equal(t, &CurrentWeather{
+		Temp: 45, FeelsLike: 39, Condition: "Partly cloudy", Humidity: 81,
+		WindSpeed: 12, WindDir: "NW", Precipitation: 0.01,
+	}, current)
+	assert.Equal(t, "New York, New York, United States of America", area)
+	assert.Equal(t, []ForecastDay{
+		{Date: "2025-06-01", High: 54, Low: 41, Condition: "Light rain", ChanceOfRain: 80, Precipitation: 0.1},
+		{Date: "2025-06-02", High: 59, Low: 46, Condition: "Sunny"},
+	}, forecast)
+
+	current, _, forecast = summarizeWeather(resp, 1, metricUnits)
+	assert.Equal(t, &CurrentWeather{
+		Temp: 7, FeelsLike: 4, Condition: "Partly cloudy", Humidity: 81,
+		WindSpeed: 19, WindDir: "NW", Precipitation: 0.3,
+	}, current)
+	assert.Equal(t, []ForecastDay{
+		{Date: "2025-06-01", High: 12, Low: 5, Condition: "Light rain", ChanceOfRain: 80, Precipitation: 2.6},
+	}, forecast)
+
+	// An empty response doesn't panic
+	current, area, forecast = summarizeWeather(wttrResponse{}, 3, metricUnits)
+	assert.Nil(t, current)
+	assert.Empty(t, area)
+	assert.Empty(t, forecast)
+}
+
+func TestWeatherUnits(t *testing.T) {
+	for _, tc := range []struct {
+		requested, configured, country string
+		want                           string
+	}{
+		{"", "", wttrUnitedStates, "imperial"},
+		{"", "", "Japan", "metric"},
+		{"", "auto", "", "metric"},
+		{"", "metric", wttrUnitedStates, "metric"},
+		{" Imperial ", "metric", "Japan", "imperial"},
+	} {
+		units, ok := weatherUnits(tc.requested, tc.configured, tc.country)
+		assert.True(t, ok)
+		assert.Equal(t, tc.want, units.System, "%+v", tc)
+	}
+
+	_, ok := weatherUnits("kelvin", "", "")
+	assert.False(t, ok)
+}
+
+// stubWeather points wttr.in and the NWS at a server answering with
+// wttrSample and the given alerts response (or a 500 when it's empty).
+func stubWeather(t *testing.T, alerts string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/10001":
+			_, _ = w.Write([]byte(wttrSample))
+		case "/nws/alerts/active":
+			assert.Equal(t, "40.7140,-74.0060", r.URL.Query().Get("point"))
+			if alerts == "" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(alerts))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	originalWttr, originalNWS := wttrBaseURL, nwsAPIBaseURL
+	wttrBaseURL, nwsAPIBaseURL = server.URL, server.URL+"/nws"
+	t.Cleanup(func() { wttrBaseURL, nwsAPIBaseURL = originalWttr, originalNWS })
+}
+
+func TestWeatherHandlerReturnsSummary(t *testing.T) {
+	stubWeather(t, `{"features": []}`)
+
+	result, err := WeatherHandler(context.Background(), map[string]interface{}{"zip_code": "10001", "days": 3.0}, NewMockConfigLoader())
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, 45, data["current"].(*CurrentWeather).Temp)
+	assert.Equal(t, imperialUnits, data["units"], "imperial for a US location")
+	assert.Len(t, data["forecast"], 3)
+	assert.Equal(t, "10001", data["zip_code"])
+	assert.NotContains(t, data, "raw")
+	assert.NotContains(t, data, "current_condition")
+
+	summary, err := json.Marshal(data)
+	require.NoError(t, err)
+	assert.Less(t, len(summary), len(wttrSample))
+
+	// raw: true adds the untouched response
+	result, err = WeatherHandler(context.Background(), map[string]interface{}{"zip_code": "10001", "raw": true}, NewMockConfigLoader())
+	require.NoError(t, err)
+	data = result.(map[string]interface{})
+	assert.JSONEq(t, wttrSample, string(data["raw"].(json.RawMessage)))
+	assert.Len(t, data["forecast"], 1)
+
+	// units overrides the configured system
+	loader := NewMockConfigLoader()
+	loader.WeatherCfg.Units = "imperial"
+	result, err = WeatherHandler(context.Background(), map[string]interface{}{"zip_code": "10001", "units": "metric"}, loader)
+	require.NoError(t, err)
+	data = result.(map[string]interface{})
+	assert.Equal(t, 7, data["current"].(*CurrentWeather).Temp)
+	assert.Equal(t, metricUnits, data["units"])
+
+	result, err = WeatherHandler(context.Background(), map[string]interface{}{"zip_code": "10001", "units": "kelvin"}, loader)
+	require.NoError(t, err)
+	assert.Equal(t, "validation_error", result.(map[string]interface{})["error_type"])
+}
+
+func TestWeatherHandlerAlerts(t *testing.T) {
+	stubWeather(t, `{"features": [{"properties": {
+		"event": "Heat Advisory", "headline": "Heat Advisory issued June 1 at 3:00AM EDT",
+		"severity": "Moderate", "urgency": "Expected", "effective": "2025-06-01T03:00:00-04:00",
+		"expires": "2025-06-01T20:00:00-04:00", "areaDesc": "New York (Manhattan)",
+		"description": "Heat index values up to 105.", "instruction": "Drink plenty of fluids,\nstay in an air-conditioned room."
+	}}]}`)
+
+	result, err := WeatherHandler(context.Background(), map[string]interface{}{"zip_code": "10001"}, NewMockConfigLoader())
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, []WeatherAlert{{
+		Event:       "Heat Advisory",
+		Headline:    "Heat Advisory issued June 1 at 3:00AM EDT",
+		Severity:    "Moderate",
+		Urgency:     "Expected",
+		Onset:       "2025-06-01T03:00:00-04:00",
+		Expires:     "2025-06-01T20:00:00-04:00",
+		Area:        "New York (Manhattan)",
+		Instruction: "Drink plenty of fluids, stay in an air-conditioned room.",
+	}}, data["alerts"])
+	assert.Equal(t, "National Weather Service", data["alerts_source"])
+
+	// Turned off, no lookup is made, not even from the cache
+	loader := NewMockConfigLoader()
+	loader.WeatherCfg.AlertsSource = "off"
+	nwsAPIBaseURL = "http://127.0.0.1:0"
+	result, err = WeatherHandler(context.Background(), map[string]interface{}{"zip_code": "10001"}, loader)
+	require.NoError(t, err)
+	assert.NotContains(t, result.(map[string]interface{}), "alerts")
+}
+
+func TestWeatherHandlerAlertsUnavailable(t *testing.T) {
+	stubWeather(t, "")
+
+	result, err := WeatherHandler(context.Background(), map[string]interface{}{"zip_code": "10001"}, NewMockConfigLoader())
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.NotNil(t, data["current"], "the forecast is still returned")
+	assert.NotContains(t, data, "alerts")
+	assert.Contains(t, data["alerts_error"], "Weather alerts are unavailable")
+}
+
+func TestLookupWeatherAlertsOutsideUS(t *testing.T) {
+	var report wttrResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"nearest_area": [{"country": [{"value": "Japan"}], "latitude": "35.690", "longitude": "139.692"}]}`), &report))
+	alerts, checked, err := lookupWeatherAlerts(context.Background(), "nws", report)
+	assert.NoError(t, err)
+	assert.False(t, checked)
+	assert.Nil(t, alerts)
+}
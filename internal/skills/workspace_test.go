@@ -0,0 +1,155 @@
+package skills
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/whykusanagi/celesteCLI/internal/filelock"
+)
+
+func workspaceLoader(name, dir string) *MockConfigLoader {
+	loader := NewMockConfigLoader()
+	loader.WorkspaceCfg = WorkspaceConfig{Name: name, Dir: dir}
+	return loader
+}
+
+// TestWorkspaceIsolation tests that notes, reminders and QR codes saved under
+// one profile are not visible from another
+func TestWorkspaceIsolation(t *testing.T) {
+	root := t.TempDir()
+	work := workspaceLoader("work", filepath.Join(root, "work"))
+	stream := workspaceLoader("stream", filepath.Join(root, "stream"))
+
+	_, err := SaveNoteHandler(map[string]interface{}{"title": "standup", "content": "ship it"}, work)
+	require.NoError(t, err)
+	_, err = SetReminderHandler(map[string]interface{}{"message": "go live", "time": "23:59"}, stream)
+	require.NoError(t, err)
+	qr, err := QRCodeGeneratorHandler(map[string]interface{}{"text": "https://example.com"}, stream)
+	require.NoError(t, err)
+
+	notes, err := ListNotesHandler(map[string]interface{}{}, work)
+	require.NoError(t, err)
+	assert.Equal(t, 1, notes.(map[string]interface{})["count"])
+	notes, err = ListNotesHandler(map[string]interface{}{}, stream)
+	require.NoError(t, err)
+	assert.Equal(t, 0, notes.(map[string]interface{})["count"])
+
+	note, err := GetNoteHandler(map[string]interface{}{"title": "standup"}, stream)
+	require.NoError(t, err)
+	assert.Equal(t, true, note.(map[string]interface{})["error"])
+
+	assert.FileExists(t, filepath.Join(root, "stream", "reminders.json"))
+	assert.NoFileExists(t, filepath.Join(root, "work", "reminders.json"))
+
+	qrPath := qr.(map[string]interface{})["filepath"].(string)
+	assert.Equal(t, filepath.Join(root, "stream", "qr_codes"), filepath.Dir(qrPath))
+}
+
+// TestWorkspaceFallsBackToHome tests that data stays in ~/.celeste when no
+// workspace is configured
+func TestWorkspaceFallsBackToHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	_, err := SaveNoteHandler(map[string]interface{}{"title": "t", "content": "c"}, NewMockConfigLoaderWithErrors())
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(home, ".celeste", "notes.json"))
+	assert.NoError(t, err)
+}
+
+// TestXDGLocations tests that custom skills are read from the XDG config
+// directory and notes and caches written to the XDG data directory
+func TestXDGLocations(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("CELESTE_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	configDir := filepath.Join(home, ".config", "celeste")
+	dataDir := filepath.Join(home, ".local", "share", "celeste")
+
+	assert.Equal(t, filepath.Join(configDir, "skills"), NewRegistry().skillsDir)
+	assert.True(t, strings.HasPrefix(getHTTPCachePath("https://example.com"), filepath.Join(dataDir, "cache", "http")))
+
+	_, err := SaveNoteHandler(map[string]interface{}{"title": "standup", "content": "ship it"}, NewMockConfigLoader())
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dataDir, "notes.json"))
+	assert.NoDirExists(t, filepath.Join(home, ".celeste"))
+}
+
+// TestConcurrentSaves tests that notes and reminders saved at once, as by
+// the TUI and a cron job, all land in valid files
+func TestConcurrentSaves(t *testing.T) {
+	dir := t.TempDir()
+	loader := workspaceLoader("work", dir)
+	const writers, writes = 6, 10
+
+	var wg sync.WaitGroup
+	results := make(chan interface{}, 2*writers*writes)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				note, err := SaveNoteHandler(map[string]interface{}{"title": fmt.Sprintf("note %d-%d", w, i), "content": "c"}, loader)
+				require.NoError(t, err)
+				reminder, err := SetReminderHandler(map[string]interface{}{"message": fmt.Sprintf("reminder %d-%d", w, i), "time": "2099-01-01 09:00"}, loader)
+				require.NoError(t, err)
+				results <- note
+				results <- reminder
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(results)
+	for result := range results {
+		assert.Equal(t, true, result.(map[string]interface{})["success"], "%v", result)
+	}
+
+	var notes map[string]Note
+	data, err := os.ReadFile(filepath.Join(dir, "notes.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &notes))
+	assert.Len(t, notes, writers*writes)
+
+	var reminders []Reminder
+	data, err = os.ReadFile(filepath.Join(dir, "reminders.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &reminders))
+	assert.Len(t, reminders, writers*writes)
+}
+
+// TestSaveFileBusy tests that a lock held past the timeout is reported as
+// resource_busy rather than a generic failure
+func TestSaveFileBusy(t *testing.T) {
+	busy := saveFileError("save_note", "note", fmt.Errorf("notes.json: %w", filelock.ErrBusy))
+	assert.Equal(t, "resource_busy", busy["error_type"])
+	assert.Equal(t, "save_note", busy["skill"])
+
+	failed := saveFileError("save_note", "note", os.ErrPermission)
+	assert.Equal(t, "internal_error", failed["error_type"])
+}
+
+// TestQRCodeNamesDontCollide tests that QR codes saved in the same second
+// get distinct files
+func TestQRCodeNamesDontCollide(t *testing.T) {
+	loader := workspaceLoader("work", t.TempDir())
+	paths := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		qr, err := QRCodeGeneratorHandler(map[string]interface{}{"text": fmt.Sprintf("https://example.com/%d", i)}, loader)
+		require.NoError(t, err)
+		paths[qr.(map[string]interface{})["filepath"].(string)] = true
+	}
+	assert.Len(t, paths, 3)
+}
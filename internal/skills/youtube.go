@@ -0,0 +1,280 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// youtubeAPIBaseURL is the YouTube Data API v3 endpoint. Overridden in tests.
+var youtubeAPIBaseURL = "https://www.googleapis.com/youtube/v3"
+
+// Channel resolution paths reported in get_youtube_videos results, cheapest first.
+const (
+	youtubeResolutionCache  = "cache"  // 0 quota units
+	youtubeResolutionID     = "id"     // 0 quota units, input was already a channel ID
+	youtubeResolutionHandle = "handle" // channels.list forHandle, 1 unit
+	youtubeResolutionSearch = "search" // search.list, 100 units
+)
+
+// youtubeChannel is a resolved channel, cached by the string the user gave.
+type youtubeChannel struct {
+	ChannelID         string    `json:"channel_id"`
+	UploadsPlaylistID string    `json:"uploads_playlist_id"`
+	ResolvedAt        time.Time `json:"resolved_at"`
+}
+
+// Errors returned while resolving a channel or listing its videos.
+var (
+	errYouTubeChannelNotFound = errors.New("youtube channel not found")
+	errYouTubeBadResponse     = errors.New("invalid YouTube API response")
+)
+
+// youtubeAPIError is a non-200 response from the YouTube Data API.
+type youtubeAPIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *youtubeAPIError) Error() string {
+	return fmt.Sprintf("YouTube API returned status %d", e.StatusCode)
+}
+
+// getYouTubeChannelCachePath returns the path to the channel ID cache.
+func getYouTubeChannelCachePath() string {
+	return paths.Data("cache", "youtube_channels.json")
+}
+
+// loadYouTubeChannelCache reads the channel cache. A missing or corrupt
+// cache is treated as empty.
+func loadYouTubeChannelCache() map[string]youtubeChannel {
+	cache := make(map[string]youtubeChannel)
+	if data, err := os.ReadFile(getYouTubeChannelCachePath()); err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+// saveYouTubeChannel adds a resolved channel to the cache. Failures are
+// ignored; the next call simply resolves again.
+func saveYouTubeChannel(key string, channel youtubeChannel) {
+	cache := loadYouTubeChannelCache()
+	cache[key] = channel
+
+	path := getYouTubeChannelCachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
+
+// isYouTubeChannelID reports whether s looks like a channel ID (UC + 22 chars).
+func isYouTubeChannelID(s string) bool {
+	return strings.HasPrefix(s, "UC") && len(s) == 24
+}
+
+// uploadsPlaylistID derives a channel's uploads playlist from its ID.
+func uploadsPlaylistID(channelID string) string {
+	return "UU" + strings.TrimPrefix(channelID, "UC")
+}
+
+// youtubeGet calls a YouTube Data API method and decodes the JSON response.
+func youtubeGet(ctx context.Context, method string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", youtubeAPIBaseURL+"/"+method+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+
+	client := httpclient.New(0)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return &youtubeAPIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", errYouTubeBadResponse, err)
+	}
+	return nil
+}
+
+// resolveYouTubeChannel turns a channel ID, @handle or name into a channel,
+// using the cheapest path available, and reports which path was used.
+func resolveYouTubeChannel(ctx context.Context, channel, apiKey string) (youtubeChannel, string, error) {
+	if isYouTubeChannelID(channel) {
+		return youtubeChannel{ChannelID: channel, UploadsPlaylistID: uploadsPlaylistID(channel)}, youtubeResolutionID, nil
+	}
+
+	if cached, ok := loadYouTubeChannelCache()[channel]; ok && cached.ChannelID != "" {
+		return cached, youtubeResolutionCache, nil
+	}
+
+	var resolved youtubeChannel
+	var resolution string
+	if strings.HasPrefix(channel, "@") {
+		var result struct {
+			Items []struct {
+				ID             string `json:"id"`
+				ContentDetails struct {
+					RelatedPlaylists struct {
+						Uploads string `json:"uploads"`
+					} `json:"relatedPlaylists"`
+				} `json:"contentDetails"`
+			} `json:"items"`
+		}
+		params := url.Values{"part": {"contentDetails"}, "forHandle": {channel}, "key": {apiKey}}
+		if err := youtubeGet(ctx, "channels", params, &result); err != nil {
+			return youtubeChannel{}, "", err
+		}
+		if len(result.Items) == 0 {
+			return youtubeChannel{}, "", fmt.Errorf("%w: %s", errYouTubeChannelNotFound, channel)
+		}
+		item := result.Items[0]
+		resolved = youtubeChannel{ChannelID: item.ID, UploadsPlaylistID: item.ContentDetails.RelatedPlaylists.Uploads}
+		resolution = youtubeResolutionHandle
+	} else {
+		var result struct {
+			Items []struct {
+				ID struct {
+					ChannelID string `json:"channelId"`
+				} `json:"id"`
+			} `json:"items"`
+		}
+		params := url.Values{"part": {"snippet"}, "q": {channel}, "type": {"channel"}, "maxResults": {"1"}, "key": {apiKey}}
+		if err := youtubeGet(ctx, "search", params, &result); err != nil {
+			return youtubeChannel{}, "", err
+		}
+		if len(result.Items) == 0 {
+			return youtubeChannel{}, "", fmt.Errorf("%w: %s", errYouTubeChannelNotFound, channel)
+		}
+		channelID := result.Items[0].ID.ChannelID
+		resolved = youtubeChannel{ChannelID: channelID}
+		resolution = youtubeResolutionSearch
+	}
+
+	if resolved.UploadsPlaylistID == "" {
+		resolved.UploadsPlaylistID = uploadsPlaylistID(resolved.ChannelID)
+	}
+	resolved.ResolvedAt = time.Now()
+	saveYouTubeChannel(channel, resolved)
+	return resolved, resolution, nil
+}
+
+// fetchYouTubeUploads lists the most recent videos in an uploads playlist.
+func fetchYouTubeUploads(ctx context.Context, playlistID, apiKey string, maxResults int) ([]map[string]interface{}, error) {
+	var result struct {
+		Items []struct {
+			Snippet struct {
+				Title       string    `json:"title"`
+				Description string    `json:"description"`
+				PublishedAt time.Time `json:"publishedAt"`
+				Thumbnails  struct {
+					Default struct {
+						URL string `json:"url"`
+					} `json:"default"`
+				} `json:"thumbnails"`
+				ChannelTitle string `json:"channelTitle"`
+				ResourceID   struct {
+					VideoID string `json:"videoId"`
+				} `json:"resourceId"`
+			} `json:"snippet"`
+		} `json:"items"`
+	}
+	params := url.Values{
+		"part":       {"snippet"},
+		"playlistId": {playlistID},
+		"maxResults": {fmt.Sprintf("%d", maxResults)},
+		"key":        {apiKey},
+	}
+	if err := youtubeGet(ctx, "playlistItems", params, &result); err != nil {
+		return nil, err
+	}
+
+	videos := make([]map[string]interface{}, 0, len(result.Items))
+	for _, item := range result.Items {
+		videoID := item.Snippet.ResourceID.VideoID
+		videos = append(videos, map[string]interface{}{
+			"video_id":      videoID,
+			"title":         item.Snippet.Title,
+			"description":   item.Snippet.Description,
+			"published_at":  item.Snippet.PublishedAt.Format(time.RFC3339),
+			"thumbnail_url": item.Snippet.Thumbnails.Default.URL,
+			"channel_title": item.Snippet.ChannelTitle,
+			"url":           fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID),
+		})
+	}
+	return videos, nil
+}
+
+// youtubeErrorResponse converts a YouTube API failure into a skill error.
+func youtubeErrorResponse(ctx context.Context, channel string, err error) map[string]interface{} {
+	if cancelled := contextErrorResponse(ctx, "get_youtube_videos"); cancelled != nil {
+		return cancelled
+	}
+
+	var apiErr *youtubeAPIError
+	switch {
+	case errors.As(err, &apiErr):
+		return formatErrorResponse(
+			"api_error",
+			fmt.Sprintf("YouTube API returned error (status %d)", apiErr.StatusCode),
+			"The YouTube API may be temporarily unavailable or the channel may not exist.",
+			map[string]interface{}{
+				"skill":       "get_youtube_videos",
+				"status_code": apiErr.StatusCode,
+				"response":    apiErr.Body,
+			},
+		)
+	case errors.Is(err, errYouTubeChannelNotFound):
+		return formatErrorResponse(
+			"not_found",
+			fmt.Sprintf("YouTube channel '%s' not found", channel),
+			"Check the channel name, or use its @handle or channel ID (UC...).",
+			map[string]interface{}{
+				"skill":   "get_youtube_videos",
+				"channel": channel,
+			},
+		)
+	case errors.Is(err, errYouTubeBadResponse):
+		return formatErrorResponse(
+			"api_error",
+			"Failed to parse YouTube API response",
+			"The YouTube API returned invalid data. Please try again.",
+			map[string]interface{}{
+				"skill": "get_youtube_videos",
+				"error": err.Error(),
+			},
+		)
+	default:
+		return formatErrorResponse(
+			"network_error",
+			"Failed to connect to YouTube API",
+			"Please check your internet connection and try again.",
+			map[string]interface{}{
+				"skill": "get_youtube_videos",
+				"error": err.Error(),
+			},
+		)
+	}
+}
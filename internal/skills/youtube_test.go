@@ -0,0 +1,119 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannelID = "UCabcdefghijklmnopqrstuv"
+
+// stubYouTubeAPI serves canned channels, search and playlistItems responses
+// and records which API methods were called.
+func stubYouTubeAPI(t *testing.T) *[]string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	var mu sync.Mutex
+	calls := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.URL.Path)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/channels":
+			if r.URL.Query().Get("forHandle") != "@whykusanagi" {
+				_, _ = w.Write([]byte(`{"items":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"items":[{"id":"` + testChannelID + `","contentDetails":{"relatedPlaylists":{"uploads":"UUuploads"}}}]}`))
+		case "/search":
+			assert.Equal(t, "channel", r.URL.Query().Get("type"))
+			_, _ = w.Write([]byte(`{"items":[{"id":{"channelId":"` + testChannelID + `"}}]}`))
+		case "/playlistItems":
+			_, _ = w.Write([]byte(`{"items":[{"snippet":{"title":"Stream VOD","publishedAt":"2025-06-01T12:00:00Z","channelTitle":"whykusanagi","resourceId":{"videoId":"vid1"}}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	original := youtubeAPIBaseURL
+	youtubeAPIBaseURL = server.URL
+	t.Cleanup(func() { youtubeAPIBaseURL = original })
+
+	return &calls
+}
+
+func youtubeLoader() *MockConfigLoader {
+	return &MockConfigLoader{YouTubeCfg: YouTubeConfig{APIKey: "test-key"}}
+}
+
+func TestYouTubeVideosHandlerHandle(t *testing.T) {
+	calls := stubYouTubeAPI(t)
+
+	result, err := YouTubeVideosHandler(context.Background(), map[string]interface{}{"channel": "@whykusanagi"}, youtubeLoader())
+	require.NoError(t, err)
+
+	data := result.(map[string]interface{})
+	assert.Equal(t, "handle", data["resolution"])
+	assert.Equal(t, testChannelID, data["channel_id"])
+	assert.Equal(t, 1, data["count"])
+	assert.Equal(t, []string{"/channels", "/playlistItems"}, *calls)
+
+	videos := data["videos"].([]map[string]interface{})
+	assert.Equal(t, "vid1", videos[0]["video_id"])
+	assert.Equal(t, "https://www.youtube.com/watch?v=vid1", videos[0]["url"])
+}
+
+func TestYouTubeVideosHandlerSearchThenCache(t *testing.T) {
+	calls := stubYouTubeAPI(t)
+
+	result, err := YouTubeVideosHandler(context.Background(), map[string]interface{}{"channel": "whykusanagi"}, youtubeLoader())
+	require.NoError(t, err)
+	assert.Equal(t, "search", result.(map[string]interface{})["resolution"])
+	assert.Equal(t, []string{"/search", "/playlistItems"}, *calls)
+
+	// The resolved ID is cached under the input string
+	data, err := os.ReadFile(getYouTubeChannelCachePath())
+	require.NoError(t, err)
+	var cache map[string]youtubeChannel
+	require.NoError(t, json.Unmarshal(data, &cache))
+	assert.Equal(t, testChannelID, cache["whykusanagi"].ChannelID)
+	assert.Equal(t, "UUabcdefghijklmnopqrstuv", cache["whykusanagi"].UploadsPlaylistID)
+
+	*calls = nil
+	result, err = YouTubeVideosHandler(context.Background(), map[string]interface{}{"channel": "whykusanagi"}, youtubeLoader())
+	require.NoError(t, err)
+	assert.Equal(t, "cache", result.(map[string]interface{})["resolution"])
+	assert.Equal(t, []string{"/playlistItems"}, *calls)
+}
+
+func TestYouTubeVideosHandlerChannelID(t *testing.T) {
+	calls := stubYouTubeAPI(t)
+
+	result, err := YouTubeVideosHandler(context.Background(), map[string]interface{}{"channel": testChannelID}, youtubeLoader())
+	require.NoError(t, err)
+	assert.Equal(t, "id", result.(map[string]interface{})["resolution"])
+	assert.Equal(t, []string{"/playlistItems"}, *calls)
+	assert.NoFileExists(t, filepath.Join(os.Getenv("HOME"), ".celeste", "cache", "youtube_channels.json"))
+}
+
+func TestYouTubeVideosHandlerUnknownHandle(t *testing.T) {
+	stubYouTubeAPI(t)
+
+	result, err := YouTubeVideosHandler(context.Background(), map[string]interface{}{"channel": "@missing"}, youtubeLoader())
+	require.NoError(t, err)
+	data := result.(map[string]interface{})
+	assert.Equal(t, true, data["error"])
+	assert.Equal(t, "not_found", data["error_type"])
+}
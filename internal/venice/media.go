@@ -5,15 +5,25 @@ package venice
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"image"
+	_ "image/jpeg" // Decoders for imageDimensions
+	_ "image/png"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	_ "golang.org/x/image/webp" // WebP decoder for imageDimensions
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/version"
 )
 
 // Config holds Venice.ai API configuration.
@@ -21,6 +31,9 @@ type Config struct {
 	APIKey  string
 	BaseURL string
 	Model   string // For image generation: fluently-xl, pixart-a, etc.
+
+	// SafeMode forces Venice's safe_mode on every request regardless of params.
+	SafeMode bool
 }
 
 // MediaRequest represents a media generation request.
@@ -32,18 +45,61 @@ type MediaRequest struct {
 
 // MediaResponse represents the response from media generation.
 type MediaResponse struct {
-	Success   bool   `json:"success"`
-	URL       string `json:"url,omitempty"`
-	Path      string `json:"path,omitempty"`
-	Error     string `json:"error,omitempty"`
-	MediaType string `json:"media_type"`
+	Success   bool     `json:"success"`
+	URL       string   `json:"url,omitempty"`
+	Path      string   `json:"path,omitempty"`
+	Paths     []string `json:"paths,omitempty"` // All saved files when multiple variants were generated
+	Error     string   `json:"error,omitempty"`
+	MediaType string   `json:"media_type"`
+
+	// Params holds the effective generation parameters sent to Venice,
+	// returned so a result can be reproduced.
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// Width and Height are the output dimensions, when they could be decoded.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// Seed is the seed the image was generated with, when known.
+	Seed *int `json:"seed,omitempty"`
+
+	// Warning reports a problem that didn't stop generation (e.g. an ignored seed).
+	Warning string `json:"warning,omitempty"`
 }
 
 // GenerateImage generates an image using Venice.ai.
-func GenerateImage(config Config, prompt string, params map[string]interface{}) (*MediaResponse, error) {
+func GenerateImage(ctx context.Context, config Config, prompt string, params map[string]interface{}) (*MediaResponse, error) {
 	// Use Venice's full-featured image generation endpoint
 	url := config.BaseURL + "/image/generate"
 
+	// Work on a copy so --reuse-seed-from doesn't change the caller's params
+	requested := params
+	params = make(map[string]interface{}, len(requested))
+	for k, v := range requested {
+		params[k] = v
+	}
+
+	// A reused image's prompt already carries its style, so only an
+	// explicit --style is applied on top
+	if _, reusing := params["reuse_seed_from"]; reusing {
+		if _, named := params["style"]; !named {
+			params["style"] = NoStyle
+		}
+	}
+	prompt, err := applyStyle(prompt, params)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err = applyReusedSeed(prompt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject out-of-range parameters before making a network call
+	if err := ValidateImageParams(params); err != nil {
+		return nil, fmt.Errorf("invalid image parameters: %w", err)
+	}
+
 	// Default parameters
 	// Use image generation model from config, or default to lustify-sdxl
 	model := config.Model
@@ -56,19 +112,19 @@ func GenerateImage(config Config, prompt string, params map[string]interface{})
 	}
 
 	// Width and height (1-1280, default 1024)
-	width := 1024
+	width := DefaultImageWidth
 	if w, ok := params["width"].(int); ok {
 		width = w
 	}
 
-	height := 1024
+	height := DefaultImageHeight
 	if h, ok := params["height"].(int); ok {
 		height = h
 	}
 
 	// Steps (1-50, default 40 for high quality)
 	// Note: Some models have lower limits (e.g., wai-Illustrious max is 30)
-	steps := 40
+	steps := DefaultImageSteps
 	if s, ok := params["steps"].(int); ok {
 		steps = s
 	}
@@ -88,7 +144,7 @@ func GenerateImage(config Config, prompt string, params map[string]interface{})
 	}
 
 	// CFG scale (0 < value <= 20, default 12 for strong prompt adherence)
-	cfgScale := 12.0
+	cfgScale := DefaultImageCFGScale
 	if cfg, ok := params["cfg_scale"].(float64); ok {
 		cfgScale = cfg
 	}
@@ -110,6 +166,9 @@ func GenerateImage(config Config, prompt string, params map[string]interface{})
 	if sm, ok := params["safe_mode"].(bool); ok {
 		safeMode = sm
 	}
+	if config.SafeMode {
+		safeMode = true
+	}
 
 	// Build request payload according to Venice /image/generate API
 	payload := map[string]interface{}{
@@ -134,20 +193,29 @@ func GenerateImage(config Config, prompt string, params map[string]interface{})
 		payload["seed"] = seed
 	}
 
+	// Effective parameters returned with the result (everything but the prompt)
+	effective := make(map[string]interface{}, len(payload))
+	for k, v := range payload {
+		if k != "prompt" {
+			effective[k] = v
+		}
+	}
+
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+config.APIKey)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent())
 
-	client := &http.Client{Timeout: 120 * time.Second}
+	client := httpclient.New(120 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -173,21 +241,45 @@ func GenerateImage(config Config, prompt string, params map[string]interface{})
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	// Extract base64 images from response
-	if images, ok := result["images"].([]interface{}); ok && len(images) > 0 {
-		// Venice returns base64 strings directly in the images array
-		if b64, ok := images[0].(string); ok {
-			// Save to file
-			path, err := saveBase64Image(b64, "image")
+	// Record the seed actually used, and warn if it isn't the one we sent
+	var warnings []string
+	if seed, ok := responseSeed(result); ok {
+		if sent, hasSeed := payload["seed"].(int); hasSeed && sent != seed {
+			warnings = append(warnings, fmt.Sprintf("Venice ignored seed %d and used %d; this image may not be reproducible", sent, seed))
+		}
+		effective["seed"] = seed
+	}
+
+	// Extract base64 images from response (one per requested variant)
+	images := extractImages(result)
+	if len(images) > 0 {
+		paths := make([]string, 0, len(images))
+		for i, b64 := range images {
+			prefix := "image"
+			if len(images) > 1 {
+				prefix = fmt.Sprintf("image_%d", i+1)
+			}
+			path, err := saveBase64Image(b64, prefix)
 			if err != nil {
-				return nil, fmt.Errorf("failed to save image: %w", err)
+				return nil, fmt.Errorf("failed to save image %d: %w", i+1, err)
+			}
+			paths = append(paths, path)
+			if err := writeImageMetadata(path, prompt, effective); err != nil {
+				warnings = append(warnings, fmt.Sprintf("failed to save metadata for %s: %v", filepath.Base(path), err))
 			}
-			return &MediaResponse{
-				Success:   true,
-				Path:      path,
-				MediaType: "image",
-			}, nil
 		}
+		response := &MediaResponse{
+			Success:   true,
+			Path:      paths[0],
+			Paths:     paths,
+			MediaType: "image",
+			Params:    effective,
+			Warning:   strings.Join(warnings, "; "),
+		}
+		if seed, ok := effective["seed"].(int); ok {
+			response.Seed = &seed
+		}
+		return response, nil
 	}
 
 	return &MediaResponse{
@@ -198,35 +290,27 @@ func GenerateImage(config Config, prompt string, params map[string]interface{})
 }
 
 // UpscaleImage upscales an image using Venice.ai.
-func UpscaleImage(config Config, imagePath string, params map[string]interface{}) (*MediaResponse, error) {
+func UpscaleImage(ctx context.Context, config Config, imagePath string, params map[string]interface{}) (*MediaResponse, error) {
 	url := config.BaseURL + "/image/upscale"
 
+	if err := ValidateUpscaleParams(params); err != nil {
+		return nil, fmt.Errorf("invalid upscale parameters: %w", err)
+	}
+
 	// Read image file
 	imageData, err := os.ReadFile(imagePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read image: %w", err)
 	}
 
-	// Convert to base64
-	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
-
-	// Default parameters
-	scale := 2
-	if s, ok := params["scale"].(int); ok {
-		scale = s
-	}
+	payload := buildUpscalePayload(config, imageData, params)
 
-	creativity := 0.5
-	if c, ok := params["creativity"].(float64); ok {
-		creativity = c
-	}
-
-	// Build request payload
-	payload := map[string]interface{}{
-		"image":             imageBase64,
-		"scale":             scale,
-		"enhance":           true,
-		"enhanceCreativity": creativity,
+	// Effective parameters for the result (everything but the image itself)
+	effective := make(map[string]interface{}, len(payload))
+	for k, v := range payload {
+		if k != "image" {
+			effective[k] = v
+		}
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -234,15 +318,16 @@ func UpscaleImage(config Config, imagePath string, params map[string]interface{}
 		return nil, fmt.Errorf("failed to encode request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+config.APIKey)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent())
 
-	client := &http.Client{Timeout: 120 * time.Second}
+	client := httpclient.New(120 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -274,6 +359,7 @@ func UpscaleImage(config Config, imagePath string, params map[string]interface{}
 			Success:   true,
 			URL:       imageURL,
 			MediaType: "upscale",
+			Params:    effective,
 		}, nil
 	}
 
@@ -282,10 +368,14 @@ func UpscaleImage(config Config, imagePath string, params map[string]interface{}
 		if err != nil {
 			return nil, fmt.Errorf("failed to save upscaled image: %w", err)
 		}
+		width, height := imageDimensions(b64)
 		return &MediaResponse{
 			Success:   true,
 			Path:      path,
 			MediaType: "upscale",
+			Params:    effective,
+			Width:     width,
+			Height:    height,
 		}, nil
 	}
 
@@ -296,8 +386,52 @@ func UpscaleImage(config Config, imagePath string, params map[string]interface{}
 	}, nil
 }
 
+// buildUpscalePayload builds the /image/upscale request body.
+// The upscaler model comes from params["model"] if set, else config.Model.
+func buildUpscalePayload(config Config, imageData []byte, params map[string]interface{}) map[string]interface{} {
+	scale := DefaultUpscaleScale
+	if s, ok := params["scale"].(int); ok {
+		scale = s
+	}
+
+	creativity := 0.5
+	if c, ok := params["creativity"].(float64); ok {
+		creativity = c
+	}
+
+	model := config.Model
+	if m, ok := params["model"].(string); ok && m != "" {
+		model = m
+	}
+
+	payload := map[string]interface{}{
+		"image":             base64.StdEncoding.EncodeToString(imageData),
+		"scale":             scale,
+		"enhance":           true,
+		"enhanceCreativity": creativity,
+	}
+	if model != "" {
+		payload["model"] = model
+	}
+	return payload
+}
+
+// imageDimensions decodes the header of a base64 image and returns its size.
+// Handles JPEG, PNG and WebP; returns zeros for anything else.
+func imageDimensions(b64 string) (int, int) {
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return 0, 0
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Width, cfg.Height
+}
+
 // GenerateVideo generates a video using Venice.ai.
-func GenerateVideo(config Config, prompt string, params map[string]interface{}) (*MediaResponse, error) {
+func GenerateVideo(ctx context.Context, config Config, prompt string, params map[string]interface{}) (*MediaResponse, error) {
 	url := config.BaseURL + "/videos/generations"
 
 	// Default parameters
@@ -323,15 +457,16 @@ func GenerateVideo(config Config, prompt string, params map[string]interface{})
 		return nil, fmt.Errorf("failed to encode request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+config.APIKey)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent())
 
-	client := &http.Client{Timeout: 180 * time.Second} // Longer timeout for video
+	client := httpclient.New(180 * time.Second) // Longer timeout for video
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -374,7 +509,7 @@ func GenerateVideo(config Config, prompt string, params map[string]interface{})
 }
 
 // ImageToVideo converts an image to video using Venice.ai.
-func ImageToVideo(config Config, imagePath string, params map[string]interface{}) (*MediaResponse, error) {
+func ImageToVideo(ctx context.Context, config Config, imagePath string, params map[string]interface{}) (*MediaResponse, error) {
 	url := config.BaseURL + "/videos/image-to-video"
 
 	// Read image file
@@ -413,15 +548,16 @@ func ImageToVideo(config Config, imagePath string, params map[string]interface{}
 		return nil, fmt.Errorf("failed to encode request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+config.APIKey)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent())
 
-	client := &http.Client{Timeout: 180 * time.Second}
+	client := httpclient.New(180 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -463,6 +599,22 @@ func ImageToVideo(config Config, imagePath string, params map[string]interface{}
 	}, nil
 }
 
+// extractImages returns every base64 image in a Venice /image/generate response.
+// Venice returns base64 strings directly in the images array.
+func extractImages(result map[string]interface{}) []string {
+	raw, ok := result["images"].([]interface{})
+	if !ok {
+		return nil
+	}
+	images := make([]string, 0, len(raw))
+	for _, img := range raw {
+		if b64, ok := img.(string); ok && b64 != "" {
+			images = append(images, b64)
+		}
+	}
+	return images
+}
+
 // saveBase64Image saves a base64 encoded image to disk.
 func saveBase64Image(b64 string, prefix string) (string, error) {
 	// Decode base64
@@ -498,16 +650,11 @@ func getDownloadsDir() string {
 	}
 
 	// Try to load from config
-	configPath := filepath.Join(homeDir, ".celeste", "skills.json")
-	if data, err := os.ReadFile(configPath); err == nil {
+	if data, err := os.ReadFile(paths.Config("skills.json")); err == nil {
 		var config map[string]interface{}
 		if json.Unmarshal(data, &config) == nil {
 			if dir, ok := config["downloads_dir"].(string); ok && dir != "" {
-				// Expand ~ to home directory
-				if strings.HasPrefix(dir, "~/") {
-					return filepath.Join(homeDir, dir[2:])
-				}
-				return dir
+				return paths.ExpandHome(dir)
 			}
 		}
 	}
@@ -529,6 +676,7 @@ func ParseMediaCommand(message string) (string, string, map[string]interface{},
 			"model": "wai-Illustrious", // Anime model
 			"steps": 30,                // wai-Illustrious max steps is 30
 		}
+		content = ParseImageFlags(content, params)
 		return "image", content, params, true
 	}
 
@@ -539,6 +687,7 @@ func ParseMediaCommand(message string) (string, string, map[string]interface{},
 			"model": "hidream", // High quality dream-like images
 			"steps": 30,        // hidream max steps is 30
 		}
+		content = ParseImageFlags(content, params)
 		return "image", content, params, true
 	}
 
@@ -551,6 +700,7 @@ func ParseMediaCommand(message string) (string, string, map[string]interface{},
 			params := map[string]interface{}{
 				"model": modelName,
 			}
+			content = ParseImageFlags(content, params)
 			return "image", content, params, true
 		}
 	}
@@ -573,11 +723,13 @@ func ParseMediaCommand(message string) (string, string, map[string]interface{},
 				if len(parts) > 0 {
 					params["path"] = parts[0]
 					if len(parts) > 1 {
-						content = parts[1] // Rest is additional params
+						content = ParseUpscaleFlags(parts[1], params) // Rest is flags
 					} else {
 						content = ""
 					}
 				}
+			} else {
+				content = ParseImageFlags(content, params)
 			}
 
 			return mediaType, content, params, true
@@ -1,7 +1,15 @@
 package venice
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -387,3 +395,101 @@ func TestParseMediaCommandWithSpecialCharacters(t *testing.T) {
 		})
 	}
 }
+
+// TestGenerateImageSafeModeOverride tests that Config.SafeMode wins over params
+func TestGenerateImageSafeModeOverride(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := Config{APIKey: "test-key", BaseURL: server.URL, SafeMode: true}
+	resp, err := GenerateImage(context.Background(), config, "a red car", map[string]interface{}{"safe_mode": false})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, true, payload["safe_mode"])
+}
+
+// TestGenerateImageSavesAllVariants tests that every image in a multi-variant response is saved
+func TestGenerateImageSavesAllVariants(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	var payload map[string]interface{}
+	img := base64.StdEncoding.EncodeToString([]byte{0x89, 0x50, 0x4E, 0x47})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":     "gen-1",
+			"images": []string{img, img, img},
+		})
+	}))
+	defer server.Close()
+
+	config := Config{APIKey: "test-key", BaseURL: server.URL}
+	resp, err := GenerateImage(context.Background(), config, "a red car", map[string]interface{}{"variants": 3})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+
+	assert.Equal(t, float64(3), payload["variants"])
+	require.Len(t, resp.Paths, 3)
+	assert.Equal(t, resp.Paths[0], resp.Path)
+	for i, path := range resp.Paths {
+		assert.Contains(t, filepath.Base(path), fmt.Sprintf("celeste_image_%d_", i+1))
+		_, statErr := os.Stat(path)
+		assert.NoError(t, statErr)
+	}
+	assert.Equal(t, 3, resp.Params["variants"])
+}
+
+// TestUpscaleImageReportsDimensions tests the upscale request payload and decoded output size
+func TestUpscaleImageReportsDimensions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var out bytes.Buffer
+	require.NoError(t, png.Encode(&out, image.NewRGBA(image.Rect(0, 0, 8, 6))))
+	upscaled := base64.StdEncoding.EncodeToString(out.Bytes())
+
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/image/upscale", r.URL.Path)
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"image": upscaled})
+	}))
+	defer server.Close()
+
+	source := filepath.Join(t.TempDir(), "source.png")
+	require.NoError(t, os.WriteFile(source, out.Bytes(), 0644))
+
+	config := Config{APIKey: "test-key", BaseURL: server.URL, Model: "upscaler"}
+	resp, err := UpscaleImage(context.Background(), config, source, map[string]interface{}{"scale": 4})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+
+	assert.Equal(t, float64(4), payload["scale"])
+	assert.Equal(t, "upscaler", payload["model"])
+	assert.Equal(t, 8, resp.Width)
+	assert.Equal(t, 6, resp.Height)
+	assert.NotContains(t, resp.Params, "image")
+}
+
+// TestImageDimensionsWebP tests that WebP output, Venice's default format, is decoded
+func TestImageDimensionsWebP(t *testing.T) {
+	// 1x1 lossless WebP
+	width, height := imageDimensions("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	assert.Equal(t, 1, width)
+	assert.Equal(t, 1, height)
+
+	width, height = imageDimensions("bm90IGFuIGltYWdl")
+	assert.Zero(t, width)
+	assert.Zero(t, height)
+}
+
+// TestUpscaleImageRejectsInvalidScale tests validation happens before any request
+func TestUpscaleImageRejectsInvalidScale(t *testing.T) {
+	resp, err := UpscaleImage(context.Background(), Config{BaseURL: "http://127.0.0.1:0"}, "missing.png", map[string]interface{}{"scale": 3})
+	assert.Nil(t, resp)
+	assert.ErrorContains(t, err, "invalid upscale parameters")
+}
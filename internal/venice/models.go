@@ -0,0 +1,257 @@
+package venice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/httpclient"
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// Model types accepted by ListModels and the --type filter.
+const (
+	ModelTypeText    = "text"
+	ModelTypeImage   = "image"
+	ModelTypeUpscale = "upscale"
+)
+
+// ModelCacheTTL is how long the cached model list is used before refetching.
+const ModelCacheTTL = time.Hour
+
+// Model is a Venice model with the capability flags from its model_spec.
+type Model struct {
+	ID                      string   `json:"id"`
+	Type                    string   `json:"type"`
+	Name                    string   `json:"name,omitempty"`
+	ContextTokens           int      `json:"context_tokens,omitempty"`
+	SupportsFunctionCalling bool     `json:"supports_function_calling,omitempty"`
+	SupportsVision          bool     `json:"supports_vision,omitempty"`
+	SupportsReasoning       bool     `json:"supports_reasoning,omitempty"`
+	SupportsWebSearch       bool     `json:"supports_web_search,omitempty"`
+	Traits                  []string `json:"traits,omitempty"`
+	Offline                 bool     `json:"offline,omitempty"`
+	Beta                    bool     `json:"beta,omitempty"`
+}
+
+// Capabilities lists the model's capability flags and traits for display.
+func (m Model) Capabilities() []string {
+	var caps []string
+	for _, c := range []struct {
+		name string
+		ok   bool
+	}{
+		{"tools", m.SupportsFunctionCalling},
+		{"vision", m.SupportsVision},
+		{"reasoning", m.SupportsReasoning},
+		{"web search", m.SupportsWebSearch},
+		{"beta", m.Beta},
+		{"offline", m.Offline},
+	} {
+		if c.ok {
+			caps = append(caps, c.name)
+		}
+	}
+	return append(caps, m.Traits...)
+}
+
+// apiModel is a model as returned by GET /models. Current responses keep
+// the capability flags under model_spec.capabilities; older ones put
+// supportsFunctionCalling directly on model_spec.
+type apiModel struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	ModelSpec struct {
+		Name                    string   `json:"name"`
+		AvailableContextTokens  int      `json:"availableContextTokens"`
+		Traits                  []string `json:"traits"`
+		Offline                 bool     `json:"offline"`
+		Beta                    bool     `json:"beta"`
+		SupportsFunctionCalling bool     `json:"supportsFunctionCalling"`
+		Capabilities            struct {
+			SupportsFunctionCalling bool `json:"supportsFunctionCalling"`
+			SupportsVision          bool `json:"supportsVision"`
+			SupportsReasoning       bool `json:"supportsReasoning"`
+			SupportsWebSearch       bool `json:"supportsWebSearch"`
+		} `json:"capabilities"`
+	} `json:"model_spec"`
+}
+
+// toModel flattens an API model into a Model.
+func (a apiModel) toModel() Model {
+	spec := a.ModelSpec
+	modelType := a.Type
+	if modelType == "" {
+		modelType = ModelTypeText
+	}
+	return Model{
+		ID:                      a.ID,
+		Type:                    modelType,
+		Name:                    spec.Name,
+		ContextTokens:           spec.AvailableContextTokens,
+		SupportsFunctionCalling: spec.Capabilities.SupportsFunctionCalling || spec.SupportsFunctionCalling,
+		SupportsVision:          spec.Capabilities.SupportsVision,
+		SupportsReasoning:       spec.Capabilities.SupportsReasoning,
+		SupportsWebSearch:       spec.Capabilities.SupportsWebSearch,
+		Traits:                  spec.Traits,
+		Offline:                 spec.Offline,
+		Beta:                    spec.Beta,
+	}
+}
+
+// parseModels decodes a /models response, accepting both the documented
+// {"data": [...]} wrapper and the bare array older responses used.
+func parseModels(body []byte) ([]Model, error) {
+	var raw []apiModel
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse models response: %w", err)
+		}
+	} else {
+		var wrapper struct {
+			Data *[]apiModel `json:"data"`
+		}
+		if err := json.Unmarshal(body, &wrapper); err != nil {
+			return nil, fmt.Errorf("failed to parse models response: %w", err)
+		}
+		if wrapper.Data == nil {
+			return nil, fmt.Errorf("failed to parse models response: no data field")
+		}
+		raw = *wrapper.Data
+	}
+
+	models := make([]Model, 0, len(raw))
+	for _, m := range raw {
+		if m.ID != "" {
+			models = append(models, m.toModel())
+		}
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Type != models[j].Type {
+			return models[i].Type < models[j].Type
+		}
+		return models[i].ID < models[j].ID
+	})
+	return models, nil
+}
+
+// modelCache is the on-disk model list.
+type modelCache struct {
+	BaseURL   string    `json:"base_url"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Models    []Model   `json:"models"`
+}
+
+// ModelCachePath returns the model list cache (cache/venice_models.json in
+// the data directory).
+func ModelCachePath() string {
+	return paths.Data("cache", "venice_models.json")
+}
+
+// loadModelCache returns the cached models for baseURL if they are younger
+// than ModelCacheTTL.
+func loadModelCache(baseURL string) ([]Model, bool) {
+	data, err := os.ReadFile(ModelCachePath())
+	if err != nil {
+		return nil, false
+	}
+	var cache modelCache
+	if json.Unmarshal(data, &cache) != nil || cache.BaseURL != baseURL || time.Since(cache.FetchedAt) > ModelCacheTTL {
+		return nil, false
+	}
+	return cache.Models, true
+}
+
+// saveModelCache writes the model list. Failures are ignored; the next
+// call simply fetches again.
+func saveModelCache(baseURL string, models []Model) {
+	path := ModelCachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(modelCache{BaseURL: baseURL, FetchedAt: time.Now(), Models: models}, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
+
+// fetchModels requests every model type from GET /models.
+func fetchModels(ctx context.Context, config Config) ([]Model, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimRight(config.BaseURL, "/")+"/models?type=all", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+	req.Header.Set("Authorization", "Bearer "+config.APIKey)
+
+	client := httpclient.New(0)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read models response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("models API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return parseModels(body)
+}
+
+// ListModels returns Venice's models of the given type ("" for all), using
+// the cached list when it is under an hour old unless refresh is set.
+func ListModels(ctx context.Context, config Config, modelType string, refresh bool) ([]Model, error) {
+	switch modelType {
+	case "", ModelTypeText, ModelTypeImage, ModelTypeUpscale:
+	default:
+		return nil, fmt.Errorf("unknown model type %q (use %s, %s or %s)", modelType, ModelTypeText, ModelTypeImage, ModelTypeUpscale)
+	}
+
+	models, ok := loadModelCache(config.BaseURL)
+	if refresh || !ok {
+		var err error
+		models, err = fetchModels(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		saveModelCache(config.BaseURL, models)
+	}
+
+	if modelType == "" {
+		return models, nil
+	}
+	filtered := make([]Model, 0, len(models))
+	for _, m := range models {
+		if m.Type == modelType {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered, nil
+}
+
+// FindModel looks up a model by ID in the (cached) model list.
+func FindModel(ctx context.Context, config Config, id string) (Model, bool, error) {
+	models, err := ListModels(ctx, config, "", false)
+	if err != nil {
+		return Model{}, false, err
+	}
+	for _, m := range models {
+		if m.ID == id {
+			return m, true, nil
+		}
+	}
+	return Model{}, false, nil
+}
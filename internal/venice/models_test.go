@@ -0,0 +1,147 @@
+package venice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modelsResponse is the documented GET /models shape.
+const modelsResponse = `{
+  "object": "list",
+  "type": "all",
+  "data": [
+    {
+      "id": "venice-uncensored",
+      "type": "text",
+      "object": "model",
+      "owned_by": "venice.ai",
+      "model_spec": {
+        "name": "Venice Uncensored",
+        "availableContextTokens": 32768,
+        "traits": ["most_uncensored"],
+        "capabilities": {"supportsFunctionCalling": false, "supportsVision": false, "supportsReasoning": false, "supportsWebSearch": true},
+        "offline": false,
+        "beta": false
+      }
+    },
+    {
+      "id": "llama-3.3-70b",
+      "type": "text",
+      "object": "model",
+      "model_spec": {
+        "availableContextTokens": 65536,
+        "traits": ["function_calling_default"],
+        "capabilities": {"supportsFunctionCalling": true, "supportsWebSearch": true}
+      }
+    },
+    {"id": "lustify-sdxl", "type": "image", "model_spec": {"traits": ["default"]}},
+    {"id": "upscaler", "type": "upscale", "model_spec": {}}
+  ]
+}`
+
+// legacyModelsResponse is the bare array older responses used, with the
+// function calling flag directly on model_spec.
+const legacyModelsResponse = `[
+  {"id": "qwen3-235b", "model_spec": {"availableContextTokens": 131072, "supportsFunctionCalling": true}},
+  {"id": "hidream", "type": "image", "model_spec": {}}
+]`
+
+// newModelsServer serves body from /models and counts the requests.
+func newModelsServer(t *testing.T, body string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/models", r.URL.Path)
+		assert.Equal(t, "all", r.URL.Query().Get("type"))
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// TestParseModels tests the documented and the legacy response shapes
+func TestParseModels(t *testing.T) {
+	models, err := parseModels([]byte(modelsResponse))
+	require.NoError(t, err)
+	require.Len(t, models, 4)
+
+	assert.Equal(t, Model{
+		ID:                      "llama-3.3-70b",
+		Type:                    "text",
+		ContextTokens:           65536,
+		Traits:                  []string{"function_calling_default"},
+		SupportsFunctionCalling: true,
+		SupportsWebSearch:       true,
+	}, models[1])
+	assert.Equal(t, "venice-uncensored", models[2].ID)
+	assert.False(t, models[2].SupportsFunctionCalling)
+	assert.Equal(t, "Venice Uncensored", models[2].Name)
+	assert.Equal(t, []string{"web search", "most_uncensored"}, models[2].Capabilities())
+
+	legacy, err := parseModels([]byte(legacyModelsResponse))
+	require.NoError(t, err)
+	require.Len(t, legacy, 2)
+	assert.Equal(t, "hidream", legacy[0].ID)
+	assert.Equal(t, "qwen3-235b", legacy[1].ID)
+	assert.Equal(t, "text", legacy[1].Type, "type defaults to text")
+	assert.True(t, legacy[1].SupportsFunctionCalling)
+	assert.Equal(t, 131072, legacy[1].ContextTokens)
+
+	_, err = parseModels([]byte(`{"error": "unauthorized"}`))
+	assert.ErrorContains(t, err, "no data field")
+}
+
+// TestListModelsCache tests type filtering and that the list is cached for an hour
+func TestListModelsCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, requests := newModelsServer(t, modelsResponse)
+	config := Config{APIKey: "test-key", BaseURL: server.URL}
+
+	images, err := ListModels(context.Background(), config, ModelTypeImage, false)
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	assert.Equal(t, "lustify-sdxl", images[0].ID)
+
+	text, err := ListModels(context.Background(), config, ModelTypeText, false)
+	require.NoError(t, err)
+	assert.Len(t, text, 2)
+	assert.Equal(t, 1, *requests, "second call is served from the cache")
+
+	model, found, err := FindModel(context.Background(), config, "llama-3.3-70b")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.True(t, model.SupportsFunctionCalling)
+	assert.Equal(t, 1, *requests)
+
+	// refresh skips the cache
+	_, err = ListModels(context.Background(), config, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, *requests)
+
+	// An expired cache is refetched
+	data, err := os.ReadFile(ModelCachePath())
+	require.NoError(t, err)
+	var cache modelCache
+	require.NoError(t, json.Unmarshal(data, &cache))
+	cache.FetchedAt = time.Now().Add(-2 * ModelCacheTTL)
+	data, err = json.Marshal(cache)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(ModelCachePath(), data, 0644))
+
+	_, err = ListModels(context.Background(), config, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, *requests)
+
+	_, err = ListModels(context.Background(), config, "video", false)
+	assert.ErrorContains(t, err, `unknown model type "video"`)
+}
@@ -0,0 +1,229 @@
+package venice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Image generation parameter defaults and limits for /image/generate.
+const (
+	DefaultImageWidth    = 1024
+	DefaultImageHeight   = 1024
+	DefaultImageSteps    = 40
+	DefaultImageCFGScale = 12.0
+
+	MinImageSteps     = 1
+	MaxImageSteps     = 50
+	MaxImageDimension = 1280
+	MaxImageCFGScale  = 20.0
+	ImageDimensionMul = 64
+	MaxImageVariants  = 4
+	MaxImageSeed      = 999999999
+
+	DefaultUpscaleScale = 2
+	DefaultUpscaler     = "upscaler"
+)
+
+// upscaleScales lists the scale factors /image/upscale accepts.
+var upscaleScales = []int{2, 4}
+
+// imageFlags maps media command flags to Venice payload keys.
+var imageFlags = map[string]string{
+	"--cfg-scale":       "cfg_scale",
+	"--steps":           "steps",
+	"--width":           "width",
+	"--height":          "height",
+	"--negative-prompt": "negative_prompt",
+	"--variants":        "variants",
+	"--seed":            "seed",
+	"--reuse-seed-from": "reuse_seed_from",
+	"--style":           "style",
+}
+
+// ParseImageFlags extracts generation flags (--cfg-scale, --steps, --width,
+// --height, --negative-prompt, --variants, --seed, --reuse-seed-from,
+// --style) from an image prompt.
+// Returns the prompt with flags removed; parsed values are merged into params.
+// Values that fail to parse are kept as strings so ValidateImageParams can
+// report them.
+func ParseImageFlags(prompt string, params map[string]interface{}) string {
+	words := strings.Fields(prompt)
+	hasFlag := false
+	for _, w := range words {
+		if _, ok := imageFlags[strings.ToLower(w)]; ok {
+			hasFlag = true
+			break
+		}
+	}
+	if !hasFlag {
+		// Leave the prompt untouched (including newlines) when there are no flags
+		return prompt
+	}
+
+	var kept []string
+	for i := 0; i < len(words); i++ {
+		key, ok := imageFlags[strings.ToLower(words[i])]
+		if !ok {
+			kept = append(kept, words[i])
+			continue
+		}
+
+		if key == "negative_prompt" {
+			// Negative prompt runs until the next known flag
+			var negative []string
+			for i+1 < len(words) {
+				if _, isFlag := imageFlags[strings.ToLower(words[i+1])]; isFlag {
+					break
+				}
+				i++
+				negative = append(negative, words[i])
+			}
+			params[key] = strings.Join(negative, " ")
+			continue
+		}
+
+		if i+1 >= len(words) {
+			params[key] = ""
+			continue
+		}
+		i++
+		raw := words[i]
+
+		if key == "reuse_seed_from" || key == "style" {
+			params[key] = raw
+			continue
+		}
+
+		if key == "cfg_scale" {
+			if f, err := strconv.ParseFloat(raw, 64); err == nil {
+				params[key] = f
+			} else {
+				params[key] = raw
+			}
+			continue
+		}
+
+		if n, err := strconv.Atoi(raw); err == nil {
+			params[key] = n
+		} else {
+			params[key] = raw
+		}
+	}
+
+	return strings.Join(kept, " ")
+}
+
+// ValidateImageParams checks generation parameters against Venice's limits.
+// Only keys present in params are validated.
+func ValidateImageParams(params map[string]interface{}) error {
+	if v, ok := params["steps"]; ok {
+		steps, isInt := v.(int)
+		if !isInt {
+			return fmt.Errorf("steps must be an integer, got %v", v)
+		}
+		if steps < MinImageSteps || steps > MaxImageSteps {
+			return fmt.Errorf("steps must be between %d and %d, got %d", MinImageSteps, MaxImageSteps, steps)
+		}
+	}
+
+	for _, key := range []string{"width", "height"} {
+		v, ok := params[key]
+		if !ok {
+			continue
+		}
+		dim, isInt := v.(int)
+		if !isInt {
+			return fmt.Errorf("%s must be an integer, got %v", key, v)
+		}
+		if dim <= 0 || dim > MaxImageDimension {
+			return fmt.Errorf("%s must be between %d and %d, got %d", key, ImageDimensionMul, MaxImageDimension, dim)
+		}
+		if dim%ImageDimensionMul != 0 {
+			return fmt.Errorf("%s must be a multiple of %d, got %d", key, ImageDimensionMul, dim)
+		}
+	}
+
+	if v, ok := params["variants"]; ok {
+		variants, isInt := v.(int)
+		if !isInt {
+			return fmt.Errorf("variants must be an integer, got %v", v)
+		}
+		if variants < 1 || variants > MaxImageVariants {
+			return fmt.Errorf("variants must be between 1 and %d, got %d", MaxImageVariants, variants)
+		}
+	}
+
+	if v, ok := params["cfg_scale"]; ok {
+		cfg, isFloat := v.(float64)
+		if !isFloat {
+			return fmt.Errorf("cfg_scale must be a number, got %v", v)
+		}
+		if cfg <= 0 || cfg > MaxImageCFGScale {
+			return fmt.Errorf("cfg_scale must be greater than 0 and at most %.0f, got %g", MaxImageCFGScale, cfg)
+		}
+	}
+
+	if v, ok := params["negative_prompt"]; ok {
+		if _, isString := v.(string); !isString {
+			return fmt.Errorf("negative_prompt must be a string, got %v", v)
+		}
+	}
+
+	if v, ok := params["seed"]; ok {
+		seed, isInt := v.(int)
+		if !isInt {
+			return fmt.Errorf("seed must be an integer, got %v", v)
+		}
+		if seed < 0 || seed > MaxImageSeed {
+			return fmt.Errorf("seed must be between 0 and %d, got %d", MaxImageSeed, seed)
+		}
+	}
+
+	return nil
+}
+
+// ParseUpscaleFlags extracts --scale and --model from the text following an
+// upscale path. Returns any remaining text; parsed values are merged into params.
+func ParseUpscaleFlags(content string, params map[string]interface{}) string {
+	words := strings.Fields(content)
+	var kept []string
+	for i := 0; i < len(words); i++ {
+		flag := strings.ToLower(words[i])
+		if (flag != "--scale" && flag != "--model") || i+1 >= len(words) {
+			kept = append(kept, words[i])
+			continue
+		}
+		i++
+		raw := words[i]
+
+		if flag == "--model" {
+			params["model"] = raw
+			continue
+		}
+		if n, err := strconv.Atoi(raw); err == nil {
+			params["scale"] = n
+		} else {
+			params["scale"] = raw
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// ValidateUpscaleParams checks upscale parameters against Venice's limits.
+func ValidateUpscaleParams(params map[string]interface{}) error {
+	v, ok := params["scale"]
+	if !ok {
+		return nil
+	}
+	scale, isInt := v.(int)
+	if !isInt {
+		return fmt.Errorf("scale must be 2 or 4, got %v", v)
+	}
+	for _, allowed := range upscaleScales {
+		if scale == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("scale must be 2 or 4, got %d", scale)
+}
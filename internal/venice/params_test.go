@@ -0,0 +1,160 @@
+package venice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseImageFlags tests extracting generation flags from prompts
+func TestParseImageFlags(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectPrompt string
+		expectParams map[string]interface{}
+	}{
+		{
+			name:         "No flags",
+			input:        "a red car",
+			expectPrompt: "a red car",
+			expectParams: map[string]interface{}{},
+		},
+		{
+			name:         "Numeric flags",
+			input:        "a red car --steps 30 --width 768 --height 512 --cfg-scale 7.5",
+			expectPrompt: "a red car",
+			expectParams: map[string]interface{}{"steps": 30, "width": 768, "height": 512, "cfg_scale": 7.5},
+		},
+		{
+			name:         "Negative prompt runs until next flag",
+			input:        "castle --negative-prompt blurry, low quality --steps 20",
+			expectPrompt: "castle",
+			expectParams: map[string]interface{}{"negative_prompt": "blurry, low quality", "steps": 20},
+		},
+		{
+			name:         "Seed flags",
+			input:        "castle at night --seed 42 --reuse-seed-from ~/old/123.png",
+			expectPrompt: "castle at night",
+			expectParams: map[string]interface{}{"seed": 42, "reuse_seed_from": "~/old/123.png"},
+		},
+		{
+			name:         "Unparseable value kept as string",
+			input:        "castle --steps many",
+			expectPrompt: "castle",
+			expectParams: map[string]interface{}{"steps": "many"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]interface{}{}
+			prompt := ParseImageFlags(tt.input, params)
+			assert.Equal(t, tt.expectPrompt, prompt)
+			assert.Equal(t, tt.expectParams, params)
+		})
+	}
+}
+
+// TestValidateImageParams tests range validation for generation parameters
+func TestValidateImageParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    map[string]interface{}
+		expectErr string
+	}{
+		{name: "Empty params", params: map[string]interface{}{}},
+		{name: "Valid params", params: map[string]interface{}{"steps": 50, "width": 1280, "height": 64, "cfg_scale": 20.0}},
+		{name: "Steps too low", params: map[string]interface{}{"steps": 0}, expectErr: "steps must be between 1 and 50"},
+		{name: "Steps too high", params: map[string]interface{}{"steps": 51}, expectErr: "steps must be between 1 and 50"},
+		{name: "Steps not integer", params: map[string]interface{}{"steps": "many"}, expectErr: "steps must be an integer"},
+		{name: "Width not multiple of 64", params: map[string]interface{}{"width": 1000}, expectErr: "width must be a multiple of 64"},
+		{name: "Height too large", params: map[string]interface{}{"height": 2048}, expectErr: "height must be between"},
+		{name: "Too many variants", params: map[string]interface{}{"variants": 5}, expectErr: "variants must be between 1 and 4"},
+		{name: "CFG scale zero", params: map[string]interface{}{"cfg_scale": 0.0}, expectErr: "cfg_scale must be greater than 0"},
+		{name: "Seed zero", params: map[string]interface{}{"seed": 0}},
+		{name: "Negative seed", params: map[string]interface{}{"seed": -1}, expectErr: "seed must be between 0 and"},
+		{name: "Seed not integer", params: map[string]interface{}{"seed": "lucky"}, expectErr: "seed must be an integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImageParams(tt.params)
+			if tt.expectErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectErr)
+			}
+		})
+	}
+}
+
+// TestParseMediaCommandWithFlags tests that media commands pick up generation flags
+func TestParseMediaCommandWithFlags(t *testing.T) {
+	mediaType, prompt, params, isMedia := ParseMediaCommand("anime: magical girl --steps 20 --width 832")
+
+	assert.True(t, isMedia)
+	assert.Equal(t, "image", mediaType)
+	assert.Equal(t, "magical girl", prompt)
+	assert.Equal(t, "wai-Illustrious", params["model"])
+	assert.Equal(t, 20, params["steps"])
+	assert.Equal(t, 832, params["width"])
+}
+
+// TestGenerateImageRejectsInvalidParams tests validation happens before any request
+func TestGenerateImageRejectsInvalidParams(t *testing.T) {
+	config := Config{APIKey: "test-key", BaseURL: "http://127.0.0.1:0"}
+	resp, err := GenerateImage(context.Background(), config, "a red car", map[string]interface{}{"width": 1000})
+
+	assert.Nil(t, resp)
+	assert.ErrorContains(t, err, "invalid image parameters")
+}
+
+// TestParseUpscaleFlags tests extracting --scale and --model from upscale commands
+func TestParseUpscaleFlags(t *testing.T) {
+	mediaType, _, params, isMedia := ParseMediaCommand("upscale: ~/photo.png --scale 4 --model realesrgan")
+
+	assert.True(t, isMedia)
+	assert.Equal(t, "upscale", mediaType)
+	assert.Equal(t, "~/photo.png", params["path"])
+	assert.Equal(t, 4, params["scale"])
+	assert.Equal(t, "realesrgan", params["model"])
+
+	_, _, params, _ = ParseMediaCommand("upscale: ~/photo.png")
+	assert.NotContains(t, params, "scale", "default scale is applied at request time")
+}
+
+// TestValidateUpscaleParams tests scale factor validation
+func TestValidateUpscaleParams(t *testing.T) {
+	assert.NoError(t, ValidateUpscaleParams(map[string]interface{}{}))
+	assert.NoError(t, ValidateUpscaleParams(map[string]interface{}{"scale": 2}))
+	assert.NoError(t, ValidateUpscaleParams(map[string]interface{}{"scale": 4}))
+	assert.ErrorContains(t, ValidateUpscaleParams(map[string]interface{}{"scale": 3}), "scale must be 2 or 4")
+	assert.ErrorContains(t, ValidateUpscaleParams(map[string]interface{}{"scale": "big"}), "scale must be 2 or 4")
+}
+
+// TestBuildUpscalePayload tests the upscale request carries scale and upscaler model
+func TestBuildUpscalePayload(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		params      map[string]interface{}
+		expectScale int
+		expectModel interface{}
+	}{
+		{name: "Defaults to 2x with configured upscaler", config: Config{Model: "upscaler"}, params: map[string]interface{}{}, expectScale: 2, expectModel: "upscaler"},
+		{name: "Scale override", config: Config{Model: "upscaler"}, params: map[string]interface{}{"scale": 4}, expectScale: 4, expectModel: "upscaler"},
+		{name: "Model override", config: Config{Model: "upscaler"}, params: map[string]interface{}{"model": "realesrgan"}, expectScale: 2, expectModel: "realesrgan"},
+		{name: "No model configured", config: Config{}, params: map[string]interface{}{}, expectScale: 2, expectModel: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := buildUpscalePayload(tt.config, []byte("img"), tt.params)
+			assert.Equal(t, tt.expectScale, payload["scale"])
+			assert.Equal(t, tt.expectModel, payload["model"])
+			assert.Equal(t, "aW1n", payload["image"])
+		})
+	}
+}
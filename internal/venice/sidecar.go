@@ -0,0 +1,143 @@
+package venice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/version"
+)
+
+// ImageMetadata is the JSON sidecar saved next to each generated image so
+// the image can be reproduced or varied with --reuse-seed-from.
+type ImageMetadata struct {
+	Prompt    string                 `json:"prompt"`
+	Seed      *int                   `json:"seed,omitempty"` // Nil when Venice chose a seed and didn't report it
+	Params    map[string]interface{} `json:"params"`
+	Image     string                 `json:"image"`
+	CreatedAt time.Time              `json:"created_at"`
+
+	// The Celeste build that generated the image
+	CelesteVersion string `json:"celeste_version,omitempty"`
+	CelesteCommit  string `json:"celeste_commit,omitempty"`
+}
+
+// intParams are the image parameters that are integers. JSON decodes every
+// number as float64, so these are converted back when a sidecar is loaded.
+var intParams = map[string]bool{"width": true, "height": true, "steps": true, "variants": true, "seed": true}
+
+// SidecarPath returns the metadata path for an image: the same name with a
+// .json extension.
+func SidecarPath(imagePath string) string {
+	return strings.TrimSuffix(imagePath, filepath.Ext(imagePath)) + ".json"
+}
+
+// writeImageMetadata saves the sidecar for a generated image.
+func writeImageMetadata(imagePath, prompt string, params map[string]interface{}) error {
+	meta := ImageMetadata{
+		Prompt:    prompt,
+		Params:    params,
+		Image:     filepath.Base(imagePath),
+		CreatedAt: time.Now(),
+
+		CelesteVersion: version.Version,
+		CelesteCommit:  version.Commit,
+	}
+	if seed, ok := params["seed"].(int); ok {
+		meta.Seed = &seed
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(SidecarPath(imagePath), data, 0644)
+}
+
+// LoadImageMetadata reads the sidecar for an image. path may be the image
+// itself or its .json sidecar.
+func LoadImageMetadata(path string) (*ImageMetadata, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		path = SidecarPath(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image metadata: %w", err)
+	}
+
+	var meta ImageMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse image metadata %s: %w", path, err)
+	}
+	for key, v := range meta.Params {
+		if f, ok := v.(float64); ok && intParams[key] {
+			meta.Params[key] = int(f)
+		}
+	}
+	if meta.Seed == nil {
+		if seed, ok := meta.Params["seed"].(int); ok {
+			meta.Seed = &seed
+		}
+	}
+	return &meta, nil
+}
+
+// applyReusedSeed handles --reuse-seed-from: it loads the earlier run's
+// sidecar and fills in its seed and generation parameters, with flags given
+// on this run taking precedence. The earlier prompt is reused, and any new
+// prompt text is appended to it as a variation. params is updated in place.
+func applyReusedSeed(prompt string, params map[string]interface{}) (string, error) {
+	source, ok := params["reuse_seed_from"]
+	if !ok {
+		return prompt, nil
+	}
+	delete(params, "reuse_seed_from")
+
+	path, isString := source.(string)
+	if !isString || path == "" {
+		return "", fmt.Errorf("--reuse-seed-from needs an image or sidecar path")
+	}
+	meta, err := LoadImageMetadata(path)
+	if err != nil {
+		return "", err
+	}
+	if meta.Seed == nil {
+		return "", fmt.Errorf("%s has no recorded seed", path)
+	}
+
+	for key, v := range meta.Params {
+		if _, set := params[key]; !set && key != "safe_mode" {
+			params[key] = v
+		}
+	}
+	if _, set := params["seed"]; !set {
+		params["seed"] = *meta.Seed
+	}
+
+	prompt = strings.TrimSpace(prompt)
+	switch {
+	case meta.Prompt == "":
+		return prompt, nil
+	case prompt == "":
+		return meta.Prompt, nil
+	default:
+		return meta.Prompt + ", " + prompt, nil
+	}
+}
+
+// responseSeed returns the seed Venice reports having used, if any. It may
+// be at the top level or in the echoed request.
+func responseSeed(result map[string]interface{}) (int, bool) {
+	if seed, ok := result["seed"].(float64); ok {
+		return int(seed), true
+	}
+	if request, ok := result["request"].(map[string]interface{}); ok {
+		if seed, ok := request["seed"].(float64); ok {
+			return int(seed), true
+		}
+	}
+	return 0, false
+}
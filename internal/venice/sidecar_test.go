@@ -0,0 +1,140 @@
+package venice
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newImageServer answers /image/generate with one image, echoing seed as the
+// seed used (omitted when negative), and records the request payload.
+func newImageServer(t *testing.T, payload *map[string]interface{}, seed int) *httptest.Server {
+	t.Helper()
+	img := base64.StdEncoding.EncodeToString([]byte{0x89, 0x50, 0x4E, 0x47})
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*payload = nil
+		_ = json.NewDecoder(r.Body).Decode(payload)
+		result := map[string]interface{}{"id": "gen-1", "images": []string{img}}
+		if seed >= 0 {
+			result["request"] = map[string]interface{}{"seed": seed}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+}
+
+// TestGenerateImageSendsSeed tests that --seed reaches the payload and the sidecar
+func TestGenerateImageSendsSeed(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var payload map[string]interface{}
+	server := newImageServer(t, &payload, 42)
+	defer server.Close()
+
+	config := Config{APIKey: "test-key", BaseURL: server.URL}
+	resp, err := GenerateImage(context.Background(), config, "castle", map[string]interface{}{"seed": 42, "steps": 20})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+
+	assert.Equal(t, float64(42), payload["seed"])
+	require.NotNil(t, resp.Seed)
+	assert.Equal(t, 42, *resp.Seed)
+	assert.Empty(t, resp.Warning)
+
+	meta, err := LoadImageMetadata(resp.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "castle", meta.Prompt)
+	require.NotNil(t, meta.Seed)
+	assert.Equal(t, 42, *meta.Seed)
+	assert.Equal(t, 20, meta.Params["steps"])
+	assert.Equal(t, filepath.Base(resp.Path), meta.Image)
+}
+
+// TestGenerateImageRecordsChosenSeed tests that a seed picked by Venice is reported
+func TestGenerateImageRecordsChosenSeed(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var payload map[string]interface{}
+	server := newImageServer(t, &payload, 777)
+	defer server.Close()
+
+	resp, err := GenerateImage(context.Background(), Config{APIKey: "test-key", BaseURL: server.URL}, "castle", map[string]interface{}{})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+
+	assert.NotContains(t, payload, "seed")
+	require.NotNil(t, resp.Seed)
+	assert.Equal(t, 777, *resp.Seed)
+	assert.Equal(t, 777, resp.Params["seed"])
+}
+
+// TestGenerateImageWarnsOnIgnoredSeed tests the warning when Venice uses another seed
+func TestGenerateImageWarnsOnIgnoredSeed(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var payload map[string]interface{}
+	server := newImageServer(t, &payload, 9)
+	defer server.Close()
+
+	resp, err := GenerateImage(context.Background(), Config{APIKey: "test-key", BaseURL: server.URL}, "castle", map[string]interface{}{"seed": 5})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Contains(t, resp.Warning, "ignored seed 5 and used 9")
+	assert.Equal(t, 9, *resp.Seed)
+}
+
+// TestGenerateImageReuseSeedFrom tests a controlled variation from an earlier sidecar
+func TestGenerateImageReuseSeedFrom(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	image := filepath.Join(t.TempDir(), "celeste_image_old.png")
+	require.NoError(t, writeImageMetadata(image, "castle on a hill", map[string]interface{}{
+		"model": "hidream", "seed": 1234, "steps": 30, "width": 768, "cfg_scale": 7.5, "safe_mode": false,
+	}))
+
+	var payload map[string]interface{}
+	server := newImageServer(t, &payload, -1)
+	defer server.Close()
+
+	config := Config{APIKey: "test-key", BaseURL: server.URL, SafeMode: true}
+	params := map[string]interface{}{"reuse_seed_from": image, "steps": 20}
+	resp, err := GenerateImage(context.Background(), config, "same but at night", params)
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+
+	assert.Equal(t, "castle on a hill, same but at night", payload["prompt"])
+	assert.Equal(t, float64(1234), payload["seed"])
+	assert.Equal(t, "hidream", payload["model"])
+	assert.Equal(t, float64(768), payload["width"])
+	assert.Equal(t, 7.5, payload["cfg_scale"])
+	assert.Equal(t, float64(20), payload["steps"], "flags on this run win")
+	assert.Equal(t, true, payload["safe_mode"])
+
+	// The caller's params are left alone
+	assert.Equal(t, map[string]interface{}{"reuse_seed_from": image, "steps": 20}, params)
+
+	// The sidecar can be given directly, and an empty prompt reuses the old one
+	_, err = GenerateImage(context.Background(), config, "", map[string]interface{}{"reuse_seed_from": SidecarPath(image)})
+	require.NoError(t, err)
+	assert.Equal(t, "castle on a hill", payload["prompt"])
+}
+
+// TestGenerateImageReuseSeedFromErrors tests missing and seedless sidecars
+func TestGenerateImageReuseSeedFromErrors(t *testing.T) {
+	config := Config{APIKey: "test-key", BaseURL: "http://127.0.0.1:0"}
+
+	_, err := GenerateImage(context.Background(), config, "castle", map[string]interface{}{"reuse_seed_from": filepath.Join(t.TempDir(), "missing.png")})
+	assert.ErrorContains(t, err, "failed to read image metadata")
+
+	sidecar := filepath.Join(t.TempDir(), "old.json")
+	require.NoError(t, os.WriteFile(sidecar, []byte(`{"prompt":"castle","params":{"steps":30}}`), 0644))
+	_, err = GenerateImage(context.Background(), config, "castle", map[string]interface{}{"reuse_seed_from": sidecar})
+	assert.ErrorContains(t, err, "has no recorded seed")
+}
@@ -0,0 +1,118 @@
+package venice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/paths"
+)
+
+// NoStyle is the --style value that skips every preset, including "default".
+const NoStyle = "none"
+
+// DefaultStyle is the preset applied when no --style is given.
+const DefaultStyle = "default"
+
+// ImageStyle is a named preset from ~/.celeste/image_styles.json, wrapped
+// around the prompt and used for any parameter the request doesn't set.
+type ImageStyle struct {
+	PromptPrefix   string  `json:"prompt_prefix,omitempty"`
+	PromptSuffix   string  `json:"prompt_suffix,omitempty"`
+	NegativePrompt string  `json:"negative_prompt,omitempty"`
+	Model          string  `json:"model,omitempty"`
+	Steps          int     `json:"steps,omitempty"`
+	CFGScale       float64 `json:"cfg_scale,omitempty"`
+}
+
+// StylesPath returns the image style presets file (image_styles.json in the
+// config directory).
+func StylesPath() string {
+	return paths.Config("image_styles.json")
+}
+
+// LoadImageStyles reads the presets file, keyed by style name. A missing
+// file means no presets.
+func LoadImageStyles() (map[string]ImageStyle, error) {
+	data, err := os.ReadFile(StylesPath())
+	if os.IsNotExist(err) {
+		return map[string]ImageStyle{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var styles map[string]ImageStyle
+	if err := json.Unmarshal(data, &styles); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", StylesPath(), err)
+	}
+	return styles, nil
+}
+
+// StyleNames returns the preset names in order.
+func StyleNames(styles map[string]ImageStyle) []string {
+	names := make([]string, 0, len(styles))
+	for name := range styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply composes the prompt as prefix, prompt, suffix and fills in the
+// preset's negative prompt, model, steps and CFG scale where params doesn't
+// already set them, so explicit flags win. params is updated in place.
+func (s ImageStyle) Apply(prompt string, params map[string]interface{}) string {
+	var parts []string
+	for _, part := range []string{s.PromptPrefix, prompt, s.PromptSuffix} {
+		if part = strings.Trim(part, " ,\n"); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	setDefault := func(key string, value interface{}, ok bool) {
+		if _, set := params[key]; ok && !set {
+			params[key] = value
+		}
+	}
+	setDefault("negative_prompt", s.NegativePrompt, s.NegativePrompt != "")
+	setDefault("model", s.Model, s.Model != "")
+	setDefault("steps", s.Steps, s.Steps != 0)
+	setDefault("cfg_scale", s.CFGScale, s.CFGScale != 0)
+
+	return strings.Join(parts, ", ")
+}
+
+// applyStyle applies the preset named by params["style"], or the "default"
+// preset when none is named, and removes the style key. --style none
+// bypasses presets entirely. params is updated in place.
+func applyStyle(prompt string, params map[string]interface{}) (string, error) {
+	name := DefaultStyle
+	named := false
+	if v, ok := params["style"]; ok {
+		delete(params, "style")
+		s, isString := v.(string)
+		if !isString || s == "" {
+			return "", fmt.Errorf("--style needs a preset name")
+		}
+		name, named = s, true
+	}
+	if name == NoStyle {
+		return prompt, nil
+	}
+
+	styles, err := LoadImageStyles()
+	if err != nil {
+		return "", err
+	}
+	style, ok := styles[name]
+	if !ok {
+		if !named {
+			return prompt, nil
+		}
+		available := append(StyleNames(styles), NoStyle)
+		return "", fmt.Errorf("unknown style %q (available: %s)", name, strings.Join(available, ", "))
+	}
+	return style.Apply(prompt, params), nil
+}
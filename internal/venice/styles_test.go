@@ -0,0 +1,117 @@
+package venice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStyles = `{
+  "anime": {
+    "prompt_prefix": "anime style, clean lineart,",
+    "prompt_suffix": "vibrant colors",
+    "negative_prompt": "blurry, lowres",
+    "model": "wai-Illustrious",
+    "steps": 30,
+    "cfg_scale": 7
+  },
+  "default": {"negative_prompt": "watermark"}
+}`
+
+// writeStyles points HOME at a temp dir holding the given presets file.
+func writeStyles(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".celeste"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".celeste", "image_styles.json"), []byte(content), 0644))
+}
+
+// TestImageStyleApply tests prefix, prompt, suffix order and that explicit params win
+func TestImageStyleApply(t *testing.T) {
+	style := ImageStyle{
+		PromptPrefix:   "anime style, clean lineart,",
+		PromptSuffix:   " vibrant colors",
+		NegativePrompt: "blurry",
+		Model:          "wai-Illustrious",
+		Steps:          30,
+		CFGScale:       7,
+	}
+
+	params := map[string]interface{}{"negative_prompt": "extra fingers", "steps": 20}
+	prompt := style.Apply("a fox in the snow", params)
+	assert.Equal(t, "anime style, clean lineart, a fox in the snow, vibrant colors", prompt)
+	assert.Equal(t, map[string]interface{}{
+		"negative_prompt": "extra fingers",
+		"steps":           20,
+		"model":           "wai-Illustrious",
+		"cfg_scale":       7.0,
+	}, params)
+
+	// Empty parts are skipped
+	assert.Equal(t, "a fox, glowing", ImageStyle{PromptSuffix: "glowing"}.Apply("a fox", map[string]interface{}{}))
+	assert.Equal(t, "a fox", ImageStyle{}.Apply("a fox", map[string]interface{}{}))
+}
+
+// TestApplyStyle tests choosing presets by name, the default preset and --style none
+func TestApplyStyle(t *testing.T) {
+	writeStyles(t, testStyles)
+
+	params := map[string]interface{}{"style": "anime"}
+	prompt, err := applyStyle("a fox", params)
+	require.NoError(t, err)
+	assert.Equal(t, "anime style, clean lineart, a fox, vibrant colors", prompt)
+	assert.NotContains(t, params, "style")
+	assert.Equal(t, "blurry, lowres", params["negative_prompt"])
+
+	// With no --style the default preset applies
+	params = map[string]interface{}{}
+	prompt, err = applyStyle("a fox", params)
+	require.NoError(t, err)
+	assert.Equal(t, "a fox", prompt)
+	assert.Equal(t, "watermark", params["negative_prompt"])
+
+	// none bypasses every preset
+	params = map[string]interface{}{"style": NoStyle}
+	prompt, err = applyStyle("a fox", params)
+	require.NoError(t, err)
+	assert.Equal(t, "a fox", prompt)
+	assert.Empty(t, params)
+
+	_, err = applyStyle("a fox", map[string]interface{}{"style": "vaporwave"})
+	assert.ErrorContains(t, err, `unknown style "vaporwave" (available: anime, default, none)`)
+
+	// No presets file means no default and no named styles
+	t.Setenv("HOME", t.TempDir())
+	params = map[string]interface{}{}
+	prompt, err = applyStyle("a fox", params)
+	require.NoError(t, err)
+	assert.Equal(t, "a fox", prompt)
+	assert.Empty(t, params)
+}
+
+// TestGenerateImageWithStyle tests that the styled prompt and preset parameters reach Venice
+func TestGenerateImageWithStyle(t *testing.T) {
+	writeStyles(t, testStyles)
+
+	var payload map[string]interface{}
+	server := newImageServer(t, &payload, 7)
+	defer server.Close()
+
+	params := map[string]interface{}{}
+	prompt := ParseImageFlags("a fox --style anime --negative-prompt extra fingers", params)
+	resp, err := GenerateImage(context.Background(), Config{APIKey: "test-key", BaseURL: server.URL}, prompt, params)
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+
+	assert.Equal(t, "anime style, clean lineart, a fox, vibrant colors", payload["prompt"])
+	assert.Equal(t, "extra fingers", payload["negative_prompt"])
+	assert.Equal(t, "wai-Illustrious", payload["model"])
+	assert.Equal(t, float64(30), payload["steps"])
+	assert.Equal(t, float64(7), payload["cfg_scale"])
+	assert.NotContains(t, payload, "style")
+}
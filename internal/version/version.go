@@ -0,0 +1,59 @@
+// Package version holds the build's version information, stamped at link
+// time:
+//
+//	go build -ldflags "-X github.com/whykusanagi/celesteCLI/internal/version.Version=1.5.0 \
+//	  -X github.com/whykusanagi/celesteCLI/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/whykusanagi/celesteCLI/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// `make build` does this from git. Plain `go build` keeps the fallbacks.
+package version
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+// Build information. These are variables, not constants, so -ldflags -X
+// can set them.
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// IsDev reports whether this is a local build without a stamped version.
+func IsDev() bool {
+	return Version == "dev"
+}
+
+// String describes the build, e.g. "1.5.0 (commit 3f2c1ab, built
+// 2025-06-01T12:00:00Z)".
+func String() string {
+	return fmt.Sprintf("%s (commit %s, built %s)", Version, Commit, BuildDate)
+}
+
+// UserAgent is the User-Agent header sent with every outbound request, e.g.
+// "celeste-cli/1.5.0 (3f2c1ab; linux/amd64)".
+func UserAgent() string {
+	return fmt.Sprintf("celeste-cli/%s (%s; %s/%s)", Version, Commit, runtime.GOOS, runtime.GOARCH)
+}
+
+// Transport is an http.RoundTripper that adds UserAgent to requests that
+// don't set their own, for HTTP clients owned by SDKs.
+type Transport struct {
+	Base http.RoundTripper // nil means http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", UserAgent())
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
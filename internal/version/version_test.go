@@ -0,0 +1,44 @@
+package version
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFallbacks tests the values a build without -ldflags reports
+func TestFallbacks(t *testing.T) {
+	assert.Equal(t, "dev", Version)
+	assert.Equal(t, "unknown", Commit)
+	assert.Equal(t, "unknown", BuildDate)
+	assert.True(t, IsDev())
+	assert.Equal(t, "dev (commit unknown, built unknown)", String())
+	assert.Equal(t, "celeste-cli/dev (unknown; "+runtime.GOOS+"/"+runtime.GOARCH+")", UserAgent())
+}
+
+func TestTransportSetsUserAgent(t *testing.T) {
+	var agents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// A User-Agent the caller set is kept
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "custom")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{UserAgent(), "custom"}, agents)
+}
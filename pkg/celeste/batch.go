@@ -0,0 +1,326 @@
+package celeste
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Batch job statuses.
+const (
+	BatchOK      = "ok"
+	BatchFailed  = "failed"
+	BatchSkipped = "skipped"
+)
+
+// BatchResultsFile is the combined results file written to the output directory.
+const BatchResultsFile = "results.jsonl"
+
+// BatchJob is one content generation job from a batch file. The fields
+// match the `celeste content` flags.
+type BatchJob struct {
+	Platform string `json:"platform,omitempty"`
+	Format   string `json:"format,omitempty"`
+	Topic    string `json:"topic,omitempty"`
+	Tone     string `json:"tone,omitempty"`
+	Persona  string `json:"persona,omitempty"`
+	Request  string `json:"request,omitempty"`
+	Context  string `json:"context,omitempty"` // Background added to the request
+}
+
+// BatchResult is the outcome of one job, as written to results.jsonl.
+type BatchResult struct {
+	Index     int    `json:"index"` // 1-based line in the job file
+	Platform  string `json:"platform,omitempty"`
+	Status    string `json:"status"`
+	Output    string `json:"output,omitempty"` // Output file path
+	Chars     int    `json:"chars,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Shortened bool   `json:"shortened,omitempty"`
+	Usage     *Usage `json:"usage,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BatchOptions controls RunBatch.
+type BatchOptions struct {
+	// OutputDir receives one <index>_<platform>.txt per job and results.jsonl.
+	OutputDir string
+
+	// Concurrency bounds how many jobs run at once. Defaults to 1.
+	Concurrency int
+
+	// Resume skips jobs whose output file already exists.
+	Resume bool
+
+	// Overflow is passed to GenerateContent for every job.
+	Overflow string
+
+	// Persona is used for jobs that don't name one.
+	Persona string
+
+	// OnResult, if set, is called as each job finishes, one call at a time.
+	OnResult func(result BatchResult)
+}
+
+// BatchSummary counts results by status.
+type BatchSummary struct {
+	Succeeded, Failed, Skipped int
+}
+
+// Summarize counts batch results by status.
+func Summarize(results []BatchResult) BatchSummary {
+	var s BatchSummary
+	for _, r := range results {
+		switch r.Status {
+		case BatchOK:
+			s.Succeeded++
+		case BatchFailed:
+			s.Failed++
+		case BatchSkipped:
+			s.Skipped++
+		}
+	}
+	return s
+}
+
+// LoadBatchJobs reads jobs from a file: CSV with a header row when the name
+// ends in .csv, JSON Lines otherwise.
+func LoadBatchJobs(path string) ([]BatchJob, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return ReadBatchCSV(f)
+	}
+	return ReadBatchJSONL(f)
+}
+
+// ReadBatchJSONL reads one JSON job per line. Blank lines are ignored.
+func ReadBatchJSONL(r io.Reader) ([]BatchJob, error) {
+	var jobs []BatchJob
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+		var job BatchJob
+		if err := dec.Decode(&job); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, scanner.Err()
+}
+
+// ReadBatchCSV reads jobs from CSV. The header row names the columns, using
+// the same names as the JSON fields.
+func ReadBatchCSV(r io.Reader) ([]BatchJob, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := batchField(&BatchJob{}, header[i]); !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+
+	jobs := make([]BatchJob, 0, len(records)-1)
+	for _, record := range records[1:] {
+		var job BatchJob
+		for i, value := range record {
+			if i < len(header) {
+				field, _ := batchField(&job, header[i])
+				*field = strings.TrimSpace(value)
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// batchField returns the job field for a column name.
+func batchField(job *BatchJob, name string) (*string, bool) {
+	switch name {
+	case "platform":
+		return &job.Platform, true
+	case "format":
+		return &job.Format, true
+	case "topic":
+		return &job.Topic, true
+	case "tone":
+		return &job.Tone, true
+	case "persona":
+		return &job.Persona, true
+	case "request":
+		return &job.Request, true
+	case "context":
+		return &job.Context, true
+	}
+	return nil, false
+}
+
+// BatchOutputPath returns where a job's content is written, e.g.
+// out/003_twitter.txt. index is 1-based.
+func BatchOutputPath(dir string, index int, job BatchJob) string {
+	platform := job.Platform
+	if platform == "" {
+		platform = "content"
+	}
+	return filepath.Join(dir, fmt.Sprintf("%03d_%s.txt", index, platform))
+}
+
+// RunBatch generates content for every job with GenerateContent and writes
+// each result to OutputDir, followed by results.jsonl in job order. A failed
+// job is recorded and the rest still run. The returned error is only for
+// problems with the options or the output directory.
+func RunBatch(ctx context.Context, config Config, jobs []BatchJob, opts BatchOptions) ([]BatchResult, error) {
+	if err := ValidateOverflow(opts.Overflow); err != nil {
+		return nil, fmt.Errorf("celeste: %w", err)
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(jobs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i, job := range jobs {
+		wg.Add(1)
+		go func(i int, job BatchJob) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := runBatchJob(ctx, config, i+1, job, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = result
+			if opts.OnResult != nil {
+				opts.OnResult(result)
+			}
+		}(i, job)
+	}
+	wg.Wait()
+
+	return results, writeBatchResults(filepath.Join(opts.OutputDir, BatchResultsFile), results)
+}
+
+// runBatchJob generates and saves a single job.
+func runBatchJob(ctx context.Context, config Config, index int, job BatchJob, opts BatchOptions) BatchResult {
+	path := BatchOutputPath(opts.OutputDir, index, job)
+	result := BatchResult{Index: index, Platform: job.Platform, Output: path}
+
+	if opts.Resume {
+		if _, err := os.Stat(path); err == nil {
+			result.Status = BatchSkipped
+			return result
+		}
+	}
+
+	fail := func(err error) BatchResult {
+		result.Status = BatchFailed
+		result.Output = ""
+		result.Error = err.Error()
+		return result
+	}
+	if job.Request == "" && job.Topic == "" {
+		return fail(errors.New("job needs a request or topic"))
+	}
+	format := job.Format
+	if format == "" {
+		format = "general"
+	}
+
+	prompt := job.Request
+	if prompt == "" {
+		prompt = "Write a post about " + job.Topic
+	}
+	if job.Context != "" {
+		prompt += "\n\nContext: " + job.Context
+	}
+
+	persona := job.Persona
+	if persona == "" {
+		persona = opts.Persona
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		return fail(err)
+	}
+	defer client.Close()
+
+	generated, err := client.GenerateContent(ctx, GenerateRequest{
+		Prompt:   prompt,
+		Platform: job.Platform,
+		Format:   format,
+		Tone:     job.Tone,
+		Topic:    job.Topic,
+		Persona:  persona,
+		Overflow: opts.Overflow,
+	})
+	if err != nil {
+		return fail(err)
+	}
+	result.Usage = generated.Usage
+
+	// Write then rename so an interrupted job never looks done to --resume
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(generated.Content+"\n"), 0644); err != nil {
+		return fail(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fail(err)
+	}
+
+	result.Status = BatchOK
+	result.Chars = len([]rune(generated.Content))
+	result.Truncated = generated.Truncated
+	result.Shortened = generated.Shortened
+	return result
+}
+
+// writeBatchResults writes one JSON result per line.
+func writeBatchResults(path string, results []BatchResult) error {
+	var sb strings.Builder
+	for _, r := range results {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		sb.Write(line)
+		sb.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
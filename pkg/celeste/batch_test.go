@@ -0,0 +1,182 @@
+package celeste
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJobs = `{"platform":"twitter","format":"short","topic":"launch day","tone":"hype"}
+
+{"platform":"discord","request":"announce the giveaway","context":"ends Friday"}
+{"platform":"myspace","topic":"retro"}
+{"format":"long","request":"write a devlog"}
+`
+
+// newEchoChatServer answers every chat request with a reply naming the
+// request's last user message, failing requests that mention "fail".
+func newEchoChatServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		last := req.Messages[len(req.Messages)-1].Content
+		if strings.Contains(last, "fail") {
+			http.Error(w, `{"error":{"message":"boom"}}`, http.StatusInternalServerError)
+			return
+		}
+		data, _ := json.Marshal(map[string]interface{}{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion.chunk",
+			"choices": []interface{}{map[string]interface{}{"index": 0, "delta": map[string]interface{}{"content": "post for: " + last}}},
+		})
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: " + string(data) + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"id":"chatcmpl-test","object":"chat.completion.chunk","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func readResults(t *testing.T, dir string) []BatchResult {
+	t.Helper()
+	f, err := os.Open(filepath.Join(dir, BatchResultsFile))
+	require.NoError(t, err)
+	defer f.Close()
+	var results []BatchResult
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r BatchResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		results = append(results, r)
+	}
+	return results
+}
+
+// TestReadBatchJSONL tests parsing job lines
+func TestReadBatchJSONL(t *testing.T) {
+	jobs, err := ReadBatchJSONL(strings.NewReader(testJobs))
+	require.NoError(t, err)
+	require.Len(t, jobs, 4)
+	assert.Equal(t, BatchJob{Platform: "twitter", Format: "short", Topic: "launch day", Tone: "hype"}, jobs[0])
+	assert.Equal(t, "ends Friday", jobs[1].Context)
+
+	_, err = ReadBatchJSONL(strings.NewReader("{\"platform\":\"twitter\"}\n{\"platfrom\":\"x\"}\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+// TestReadBatchCSV tests parsing a CSV job file with a header row
+func TestReadBatchCSV(t *testing.T) {
+	input := "Platform,format,topic,request\n" +
+		"twitter,short,launch day,\n" +
+		"youtube,long,,\"write a description, with commas\"\n"
+	jobs, err := ReadBatchCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, BatchJob{Platform: "twitter", Format: "short", Topic: "launch day"}, jobs[0])
+	assert.Equal(t, "write a description, with commas", jobs[1].Request)
+
+	_, err = ReadBatchCSV(strings.NewReader("platform,colour\ntwitter,red\n"))
+	assert.ErrorContains(t, err, `unknown column "colour"`)
+}
+
+// TestRunBatch tests running a job file against the mock server
+func TestRunBatch(t *testing.T) {
+	var calls atomic.Int32
+	server := newEchoChatServer(t, &calls)
+	jobs, err := ReadBatchJSONL(strings.NewReader(testJobs + `{"platform":"tiktok","request":"this will fail"}` + "\n"))
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "out")
+	var seen atomic.Int32
+	results, err := RunBatch(context.Background(), Config{APIKey: "k", BaseURL: server.URL + "/v1", Model: "m"}, jobs, BatchOptions{
+		OutputDir:   dir,
+		Concurrency: 2,
+		OnResult:    func(BatchResult) { seen.Add(1) },
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	assert.EqualValues(t, 5, seen.Load())
+
+	assert.Equal(t, BatchSummary{Succeeded: 3, Failed: 2}, Summarize(results))
+	assert.Equal(t, BatchOK, results[0].Status)
+	assert.Equal(t, filepath.Join(dir, "001_twitter.txt"), results[0].Output)
+	require.NotNil(t, results[0].Usage)
+	assert.Equal(t, 15, results[0].Usage.TotalTokens)
+
+	// Unknown platforms fail without a request; provider errors don't stop the batch
+	assert.Equal(t, BatchFailed, results[2].Status)
+	assert.Contains(t, results[2].Error, "unknown platform")
+	assert.Equal(t, BatchFailed, results[4].Status)
+	assert.EqualValues(t, 4, calls.Load())
+
+	data, err := os.ReadFile(filepath.Join(dir, "002_discord.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "announce the giveaway")
+	assert.Contains(t, string(data), "Context: ends Friday")
+
+	data, err = os.ReadFile(filepath.Join(dir, "001_twitter.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Write a post about launch day")
+
+	assert.FileExists(t, filepath.Join(dir, "004_content.txt"))
+	assert.Equal(t, results, readResults(t, dir))
+}
+
+// TestRunBatchResume tests that jobs with output are skipped
+func TestRunBatchResume(t *testing.T) {
+	var calls atomic.Int32
+	server := newEchoChatServer(t, &calls)
+	jobs, err := ReadBatchJSONL(strings.NewReader(testJobs))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001_twitter.txt"), []byte("done earlier\n"), 0644))
+
+	results, err := RunBatch(context.Background(), Config{APIKey: "k", BaseURL: server.URL + "/v1", Model: "m"}, jobs, BatchOptions{
+		OutputDir: dir,
+		Resume:    true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, BatchSummary{Succeeded: 2, Failed: 1, Skipped: 1}, Summarize(results))
+	assert.Equal(t, BatchSkipped, results[0].Status)
+	assert.EqualValues(t, 2, calls.Load())
+
+	data, err := os.ReadFile(filepath.Join(dir, "001_twitter.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "done earlier\n", string(data))
+}
+
+// TestRunBatchDefaultPersona tests that BatchOptions.Persona applies to jobs without one
+func TestRunBatchDefaultPersona(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var calls atomic.Int32
+	server := newEchoChatServer(t, &calls)
+	jobs := []BatchJob{{Platform: "twitter", Topic: "launch"}, {Platform: "discord", Topic: "event", Persona: "celeste"}}
+
+	results, err := RunBatch(context.Background(), Config{APIKey: "k", BaseURL: server.URL + "/v1", Model: "m"}, jobs, BatchOptions{
+		OutputDir: t.TempDir(),
+		Persona:   "nobody",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, BatchFailed, results[0].Status)
+	assert.Contains(t, results[0].Error, `unknown persona "nobody"`)
+	assert.Equal(t, BatchOK, results[1].Status)
+	assert.EqualValues(t, 1, calls.Load())
+}
@@ -0,0 +1,431 @@
+// Package celeste is the embeddable Celeste content generation API.
+//
+// It exposes the same chat, prompt assembly and Venice.ai image generation
+// the CLI uses, so other Go programs (bots, services) can generate content
+// without shelling out to the binary:
+//
+//	client, err := celeste.NewClient(celeste.Config{
+//		APIKey:  os.Getenv("OPENAI_API_KEY"),
+//		BaseURL: "https://api.openai.com/v1",
+//		Model:   "gpt-4o-mini",
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer client.Close()
+//
+//	result, err := client.Generate(ctx, celeste.GenerateRequest{Prompt: "Say hi to chat"})
+package celeste
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/whykusanagi/celesteCLI/internal/chat"
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/llm"
+	"github.com/whykusanagi/celesteCLI/internal/prompts"
+	"github.com/whykusanagi/celesteCLI/internal/venice"
+)
+
+// DefaultTimeout is used when Config.Timeout is zero.
+const DefaultTimeout = 60 * time.Second
+
+// Config configures a Client.
+type Config struct {
+	// APIKey, BaseURL and Model select the chat endpoint (any OpenAI-compatible
+	// provider, or Gemini/Vertex AI by base URL).
+	APIKey  string
+	BaseURL string
+	Model   string
+
+	// Timeout bounds each request. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// SkipPersonaPrompt sends requests without the Celeste persona system prompt.
+	SkipPersonaPrompt bool
+
+	// RequireStream fails requests with llm.ErrStreamUnsupported when the
+	// server ignores streaming, instead of falling back to its buffered reply.
+	RequireStream bool
+
+	// Temperature (0-2), TopP (0-1) and MaxTokens set sampling defaults for
+	// every request. Nil and zero leave the provider's defaults. A persona's
+	// own sampling section takes precedence, and GenerateRequest over both.
+	Temperature *float64
+	TopP        *float64
+	MaxTokens   int
+
+	// ExtraBody holds provider-specific fields added to every chat request,
+	// such as DigitalOcean agent retrieval options. Fields Celeste already
+	// sets are left as they are.
+	ExtraBody map[string]interface{}
+
+	// Venice configures image generation. Optional; GenerateImage returns
+	// ErrImageNotConfigured when Venice.APIKey is empty.
+	Venice VeniceConfig
+
+	// SafeMode forces Venice's safe_mode on every image request.
+	SafeMode bool
+}
+
+// VeniceConfig configures Venice.ai image generation.
+type VeniceConfig struct {
+	APIKey     string
+	BaseURL    string // Defaults to https://api.venice.ai/api/v1
+	ImageModel string // Defaults to lustify-sdxl
+}
+
+// Message is a single prior turn in a conversation.
+type Message struct {
+	Role    string // "user" or "assistant", or "tool" in a Recap
+	Content string
+	Name    string // For "tool" messages, the skill that returned Content
+}
+
+// GenerateRequest describes a text generation request.
+type GenerateRequest struct {
+	// Prompt is the new user message.
+	Prompt string
+
+	// History holds earlier conversation turns, oldest first.
+	History []Message
+
+	// Images are image URLs or data: URLs attached to the prompt.
+	// Requires a vision-capable model.
+	Images []string
+
+	// SystemPrompt overrides the persona prompt for this request.
+	SystemPrompt string
+
+	// Persona selects a persona from ~/.celeste/personas instead of the
+	// built-in Celeste persona. See prompts.ListPersonas.
+	Persona string
+
+	// Content scaffolding. When any of these are set the persona prompt is
+	// extended with content generation guidance for the platform.
+	Platform string // twitter, tiktok, youtube, discord
+	Format   string // short, long, general
+	Tone     string
+	Topic    string
+
+	// Overflow is what GenerateContent does with content over the format's
+	// limit: OverflowShorten (the default) or OverflowTruncate.
+	Overflow string
+
+	// Temperature, TopP and MaxTokens override the client's and the
+	// persona's sampling for this request.
+	Temperature *float64
+	TopP        *float64
+	MaxTokens   int
+}
+
+// GenerateResult is the outcome of a text generation request.
+type GenerateResult struct {
+	Content      string
+	FinishReason string
+	Usage        *Usage // nil if the provider did not report usage
+
+	// Truncated is set by GenerateContent when the content was cut to fit
+	// the format's length limit, and Shortened when the model was asked to
+	// rewrite it.
+	Truncated bool
+	Shortened bool
+}
+
+// Usage reports token counts for a request.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ImageRequest describes an image generation request.
+type ImageRequest struct {
+	Prompt string
+
+	// Model overrides VeniceConfig.ImageModel for this request.
+	Model string
+
+	// Params are Venice /image/generate parameters (width, height, steps,
+	// cfg_scale, negative_prompt, variants). They are validated before sending.
+	Params map[string]interface{}
+
+	// Style names a preset from ~/.celeste/image_styles.json, or "none" to
+	// skip presets. Empty applies the "default" preset if there is one.
+	// Model and Params take precedence over the preset.
+	Style string
+}
+
+// ImageResult is the outcome of an image generation request.
+type ImageResult struct {
+	// Path is the first saved image; Paths lists every saved variant.
+	Path  string
+	Paths []string
+
+	// Params holds the effective generation parameters sent to Venice.
+	Params map[string]interface{}
+}
+
+// StreamFunc receives each content chunk as it arrives.
+type StreamFunc func(chunk string)
+
+// ErrImageNotConfigured is returned by GenerateImage when no Venice API key is set.
+var ErrImageNotConfigured = errors.New("celeste: venice API key not configured")
+
+// Client generates Celeste content. It is safe to reuse across requests
+// but not for concurrent use.
+type Client struct {
+	config Config
+	llm    *llm.Client
+}
+
+// NewClient creates a Client for the given configuration.
+func NewClient(config Config) (*Client, error) {
+	if config.BaseURL == "" {
+		return nil, errors.New("celeste: BaseURL is required")
+	}
+	baseURL, err := normalizeBaseURL(config.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	config.BaseURL = baseURL
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	client := llm.NewClient(&llm.Config{
+		APIKey:            config.APIKey,
+		BaseURL:           config.BaseURL,
+		Model:             config.Model,
+		Timeout:           config.Timeout,
+		SkipPersonaPrompt: config.SkipPersonaPrompt,
+		RequireStream:     config.RequireStream,
+		ExtraBody:         config.ExtraBody,
+	}, nil)
+
+	return &Client{config: config, llm: client}, nil
+}
+
+// normalizeBaseURL checks baseURL and puts it in canonical form, without
+// trailing slashes or a pasted /chat/completions path.
+func normalizeBaseURL(baseURL string) (string, error) {
+	normalized, _, err := config.NormalizeBaseURL(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("celeste: %w", err)
+	}
+	return normalized, nil
+}
+
+// Close releases backend resources.
+func (c *Client) Close() error {
+	return c.llm.Close()
+}
+
+// SystemPrompt returns the system prompt a request will be sent with.
+func (c *Client) SystemPrompt(req GenerateRequest) string {
+	return systemPrompt(req, c.config.SkipPersonaPrompt)
+}
+
+func systemPrompt(req GenerateRequest, skipPersona bool) string {
+	if req.SystemPrompt != "" {
+		return req.SystemPrompt
+	}
+	if skipPersona && req.Persona == "" {
+		return ""
+	}
+	base, err := prompts.GetPersonaPrompt(req.Persona)
+	if err != nil {
+		base = prompts.GetSystemPrompt(false)
+	}
+	if req.Platform != "" || req.Format != "" || req.Tone != "" || req.Topic != "" {
+		return base + prompts.ContentGuidance(req.Platform, req.Format, req.Tone, req.Topic)
+	}
+	return base
+}
+
+// sampling returns a request's sampling parameters: the client's, then the
+// persona's when its prompt is used, then the request's own.
+func (c *Client) sampling(req GenerateRequest) (config.Sampling, error) {
+	sampling := config.Sampling{Temperature: c.config.Temperature, TopP: c.config.TopP, MaxTokens: c.config.MaxTokens}
+	if req.SystemPrompt == "" && (req.Persona != "" || !c.config.SkipPersonaPrompt) {
+		persona, err := prompts.PersonaSampling(req.Persona)
+		if err != nil {
+			return config.Sampling{}, fmt.Errorf("celeste: %w", err)
+		}
+		sampling = sampling.Merge(persona)
+	}
+	sampling = sampling.Merge(config.Sampling{Temperature: req.Temperature, TopP: req.TopP, MaxTokens: req.MaxTokens})
+	if err := sampling.Validate(c.config.Model, 0); err != nil {
+		return config.Sampling{}, fmt.Errorf("celeste: %w", err)
+	}
+	return sampling, nil
+}
+
+// Generate sends a request and returns the complete response.
+func (c *Client) Generate(ctx context.Context, req GenerateRequest) (GenerateResult, error) {
+	return c.GenerateStream(ctx, req, nil)
+}
+
+// GenerateStream sends a request and calls onChunk for each piece of content
+// as it streams in. The full response is also returned.
+func (c *Client) GenerateStream(ctx context.Context, req GenerateRequest, onChunk StreamFunc) (GenerateResult, error) {
+	ctx, cancel, err := c.prepare(ctx, req)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	defer cancel()
+
+	var result GenerateResult
+	var content []byte
+	err = c.llm.SendMessageStream(ctx, buildMessages(req), nil, func(chunk llm.StreamChunk) {
+		if chunk.Content != "" {
+			content = append(content, chunk.Content...)
+			if onChunk != nil {
+				onChunk(chunk.Content)
+			}
+		}
+		// The model's own finish reason arrives before the end-of-stream chunk
+		if chunk.IsFinal && result.FinishReason == "" {
+			result.FinishReason = chunk.FinishReason
+		}
+		if chunk.Usage != nil {
+			result.Usage = &Usage{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+	})
+	if err != nil {
+		return GenerateResult{}, fmt.Errorf("celeste: generate: %w", err)
+	}
+
+	result.Content = string(content)
+	return result, nil
+}
+
+// generateSync sends a request without streaming and returns the whole
+// response at once. Compare uses it.
+func (c *Client) generateSync(ctx context.Context, req GenerateRequest) (GenerateResult, error) {
+	ctx, cancel, err := c.prepare(ctx, req)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	defer cancel()
+
+	completion, err := c.llm.SendMessageSync(llm.WithoutStreaming(ctx), buildMessages(req), nil)
+	if err == nil {
+		err = completion.Error
+	}
+	if err != nil {
+		return GenerateResult{}, fmt.Errorf("celeste: generate: %w", err)
+	}
+
+	result := GenerateResult{Content: completion.Content, FinishReason: completion.FinishReason}
+	if completion.Usage != nil {
+		result.Usage = &Usage{
+			PromptTokens:     completion.Usage.PromptTokens,
+			CompletionTokens: completion.Usage.CompletionTokens,
+			TotalTokens:      completion.Usage.TotalTokens,
+		}
+	}
+	return result, nil
+}
+
+// prepare checks a request and sets the client up to send it. The returned
+// context carries the client's timeout and the request's sampling; cancel
+// it once the request is done.
+func (c *Client) prepare(ctx context.Context, req GenerateRequest) (context.Context, context.CancelFunc, error) {
+	if req.Prompt == "" && len(req.Images) == 0 {
+		return nil, nil, errors.New("celeste: prompt is required")
+	}
+	if req.Persona != "" && !prompts.IsPersona(req.Persona) {
+		return nil, nil, fmt.Errorf("celeste: unknown persona %q", req.Persona)
+	}
+
+	sampling, err := c.sampling(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	c.llm.SetSystemPrompt(c.SystemPrompt(req))
+	return llm.WithSampling(ctx, sampling), cancel, nil
+}
+
+// buildMessages converts a request into the chat history sent to the model.
+func buildMessages(req GenerateRequest) []chat.Message {
+	now := time.Now()
+	messages := make([]chat.Message, 0, len(req.History)+1)
+	for _, m := range req.History {
+		messages = append(messages, chat.Message{
+			Role:      m.Role,
+			Content:   m.Content,
+			Timestamp: now,
+		})
+	}
+	messages = append(messages, chat.Message{
+		Role:      "user",
+		Content:   req.Prompt,
+		Images:    req.Images,
+		Timestamp: now,
+	})
+	return messages
+}
+
+// GenerateImage generates an image with Venice.ai and saves it to the
+// user's Downloads directory. Canceling ctx stops the request.
+func (c *Client) GenerateImage(ctx context.Context, req ImageRequest) (ImageResult, error) {
+	if c.config.Venice.APIKey == "" {
+		return ImageResult{}, ErrImageNotConfigured
+	}
+	if req.Prompt == "" {
+		return ImageResult{}, errors.New("celeste: image prompt is required")
+	}
+
+	baseURL := c.config.Venice.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.venice.ai/api/v1"
+	}
+	model := req.Model
+	if model == "" {
+		model = c.config.Venice.ImageModel
+	}
+	if model == "" {
+		model = "lustify-sdxl"
+	}
+
+	params := make(map[string]interface{}, len(req.Params))
+	for k, v := range req.Params {
+		params[k] = v
+	}
+	if req.Model != "" {
+		params["model"] = req.Model
+	}
+	if req.Style != "" {
+		params["style"] = req.Style
+	}
+
+	resp, err := venice.GenerateImage(ctx, venice.Config{
+		APIKey:   c.config.Venice.APIKey,
+		BaseURL:  baseURL,
+		Model:    model,
+		SafeMode: c.config.SafeMode,
+	}, req.Prompt, params)
+	if err != nil {
+		return ImageResult{}, fmt.Errorf("celeste: generate image: %w", err)
+	}
+
+	paths := resp.Paths
+	if len(paths) == 0 && resp.Path != "" {
+		paths = []string{resp.Path}
+	}
+	return ImageResult{
+		Path:   resp.Path,
+		Paths:  paths,
+		Params: resp.Params,
+	}, nil
+}
@@ -0,0 +1,271 @@
+package celeste
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockChatServer serves OpenAI-compatible streaming chat completions in
+// the same shape as test/mock-server, or one buffered completion when the
+// request doesn't ask to stream, recording the last request body.
+func newMockChatServer(t *testing.T, chunks []string, lastRequest *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if lastRequest != nil {
+			*lastRequest = req
+		}
+
+		if req["stream"] != true {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":      "chatcmpl-test",
+				"object":  "chat.completion",
+				"model":   "gpt-4o-mini",
+				"choices": []interface{}{map[string]interface{}{"index": 0, "message": map[string]interface{}{"role": "assistant", "content": strings.Join(chunks, "")}, "finish_reason": "length"}},
+				"usage":   map[string]interface{}{"prompt_tokens": 12, "completion_tokens": 4, "total_tokens": 16},
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, c := range chunks {
+			data, _ := json.Marshal(map[string]interface{}{
+				"id":      "chatcmpl-test",
+				"object":  "chat.completion.chunk",
+				"model":   "gpt-4o-mini",
+				"choices": []interface{}{map[string]interface{}{"index": 0, "delta": map[string]interface{}{"content": c}}},
+			})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, `data: {"id":"chatcmpl-test","object":"chat.completion.chunk","choices":[{"index":0,"delta":{},"finish_reason":"length"}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"chatcmpl-test","object":"chat.completion.chunk","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":4,"total_tokens":16}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func newTestClient(t *testing.T, serverURL string, config Config) *Client {
+	t.Helper()
+	config.APIKey = "test-key"
+	config.BaseURL = serverURL + "/v1"
+	config.Model = "gpt-4o-mini"
+	client, err := NewClient(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// TestNewClientRequiresBaseURL tests configuration validation
+func TestNewClientRequiresBaseURL(t *testing.T) {
+	_, err := NewClient(Config{APIKey: "key"})
+	assert.Error(t, err)
+
+	_, err = NewClient(Config{APIKey: "key", BaseURL: "api.openai.com/v1"})
+	assert.ErrorContains(t, err, "http:// or https://")
+
+	client, err := NewClient(Config{APIKey: "key", BaseURL: "https://api.openai.com/v1/chat/completions"})
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, "https://api.openai.com/v1", client.config.BaseURL)
+}
+
+// TestGenerate tests a synchronous generation against the mock server
+func TestGenerate(t *testing.T) {
+	var req map[string]interface{}
+	server := newMockChatServer(t, []string{"Hello ", "chat!"}, &req)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, Config{})
+	result, err := client.Generate(context.Background(), GenerateRequest{
+		Prompt:  "Say hi",
+		History: []Message{{Role: "user", Content: "earlier"}, {Role: "assistant", Content: "reply"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello chat!", result.Content)
+	assert.Equal(t, "length", result.FinishReason)
+	require.NotNil(t, result.Usage)
+	assert.Equal(t, 16, result.Usage.TotalTokens)
+
+	messages := req["messages"].([]interface{})
+	require.Len(t, messages, 4, "system + history + prompt")
+	assert.Equal(t, "system", messages[0].(map[string]interface{})["role"])
+	assert.Equal(t, "Say hi", messages[3].(map[string]interface{})["content"])
+}
+
+// TestGenerateStream tests that chunks are delivered to the callback in order
+func TestGenerateStream(t *testing.T) {
+	server := newMockChatServer(t, []string{"a", "b", "c"}, nil)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, Config{SkipPersonaPrompt: true})
+
+	var chunks []string
+	result, err := client.GenerateStream(context.Background(), GenerateRequest{Prompt: "go"}, func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, chunks)
+	assert.Equal(t, "abc", result.Content)
+}
+
+// TestGenerateSampling tests that sampling parameters reach the request JSON,
+// with the persona's defaults over the client's and the request's over both
+func TestGenerateSampling(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	dir := filepath.Join(home, ".celeste", "personas")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	persona := "character: Tweets\ndescription: Hype\nvoice:\n  style: Loud\nsampling:\n  temperature: 1.1\n  top_p: 0.9\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tweets.yaml"), []byte(persona), 0644))
+
+	var req map[string]interface{}
+	server := newMockChatServer(t, []string{"ok"}, &req)
+	defer server.Close()
+
+	low := 0.3
+	client := newTestClient(t, server.URL, Config{Temperature: &low, MaxTokens: 400})
+	_, err := client.Generate(context.Background(), GenerateRequest{Prompt: "hi"})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.3, req["temperature"], 0.0001)
+	assert.Equal(t, float64(400), req["max_tokens"])
+	assert.NotContains(t, req, "top_p")
+
+	_, err = client.Generate(context.Background(), GenerateRequest{Prompt: "hi", Persona: "tweets"})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.1, req["temperature"], 0.0001)
+	assert.InDelta(t, 0.9, req["top_p"], 0.0001)
+	assert.Equal(t, float64(400), req["max_tokens"])
+
+	topP := 0.5
+	_, err = client.Generate(context.Background(), GenerateRequest{Prompt: "hi", Persona: "tweets", TopP: &topP, MaxTokens: 50})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.1, req["temperature"], 0.0001)
+	assert.InDelta(t, 0.5, req["top_p"], 0.0001)
+	assert.Equal(t, float64(50), req["max_tokens"])
+
+	// A temperature of 0 is still sent
+	zero := 0.0
+	_, err = client.Generate(context.Background(), GenerateRequest{Prompt: "hi", Temperature: &zero})
+	require.NoError(t, err)
+	assert.Contains(t, req, "temperature")
+	assert.InDelta(t, 0, req["temperature"], 0.0001)
+
+	hot := 3.0
+	_, err = client.Generate(context.Background(), GenerateRequest{Prompt: "hi", Temperature: &hot})
+	assert.ErrorContains(t, err, "temperature must be between 0 and 2")
+}
+
+// TestGenerateRequiresPrompt tests request validation
+func TestGenerateRequiresPrompt(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:0", Config{})
+	_, err := client.Generate(context.Background(), GenerateRequest{})
+	assert.Error(t, err)
+}
+
+// TestSystemPrompt tests persona and content scaffolding selection
+func TestSystemPrompt(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:0", Config{})
+
+	assert.Equal(t, "custom", client.SystemPrompt(GenerateRequest{SystemPrompt: "custom"}))
+	assert.NotContains(t, client.SystemPrompt(GenerateRequest{}), "CONTENT GENERATION MODE")
+
+	content := client.SystemPrompt(GenerateRequest{Platform: "twitter", Format: "short"})
+	assert.Contains(t, content, "CONTENT GENERATION MODE")
+	assert.Contains(t, content, "Twitter")
+
+	skip := newTestClient(t, "http://127.0.0.1:0", Config{SkipPersonaPrompt: true})
+	assert.Empty(t, skip.SystemPrompt(GenerateRequest{Platform: "twitter"}))
+}
+
+// TestBuildMessagesWithImages tests that images are attached to the prompt turn
+func TestBuildMessagesWithImages(t *testing.T) {
+	messages := buildMessages(GenerateRequest{Prompt: "what is this?", Images: []string{"https://example.com/a.png"}})
+	require.Len(t, messages, 1)
+	assert.Equal(t, "user", messages[0].Role)
+	assert.Equal(t, []string{"https://example.com/a.png"}, messages[0].Images)
+}
+
+// TestGenerateImage tests image generation against a mock Venice server
+func TestGenerateImage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/image/generate"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.Header().Set("Content-Type", "application/json")
+		// "aGVsbG8=" is base64 for "hello"
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"images": []string{"aGVsbG8="}})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, "http://127.0.0.1:0", Config{
+		SafeMode: true,
+		Venice:   VeniceConfig{APIKey: "venice-key", BaseURL: server.URL},
+	})
+
+	result, err := client.GenerateImage(context.Background(), ImageRequest{
+		Prompt: "a castle",
+		Params: map[string]interface{}{"steps": 20},
+	})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, result.Path)
+	assert.Equal(t, []string{result.Path}, result.Paths)
+	assert.Equal(t, "lustify-sdxl", payload["model"])
+	assert.Equal(t, true, payload["safe_mode"])
+	assert.EqualValues(t, 20, payload["steps"])
+}
+
+// TestGenerateImageCanceled tests that canceling the context stops a
+// request Venice never answers
+func TestGenerateImageCanceled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client := newTestClient(t, "http://127.0.0.1:0", Config{Venice: VeniceConfig{APIKey: "venice-key", BaseURL: server.URL}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := client.GenerateImage(ctx, ImageRequest{Prompt: "a castle"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	_, err = client.GenerateImage(ctx, ImageRequest{Prompt: "a castle"})
+	assert.ErrorIs(t, err, context.Canceled, "an already canceled context sends nothing")
+}
+
+// TestGenerateImageErrors tests configuration and validation failures
+func TestGenerateImageErrors(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:0", Config{})
+	_, err := client.GenerateImage(context.Background(), ImageRequest{Prompt: "x"})
+	assert.True(t, errors.Is(err, ErrImageNotConfigured))
+
+	configured := newTestClient(t, "http://127.0.0.1:0", Config{Venice: VeniceConfig{APIKey: "k", BaseURL: "http://127.0.0.1:0"}})
+	_, err = configured.GenerateImage(context.Background(), ImageRequest{Prompt: "x", Params: map[string]interface{}{"width": 1000}})
+	assert.ErrorContains(t, err, "invalid image parameters")
+}
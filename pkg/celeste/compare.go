@@ -0,0 +1,116 @@
+package celeste
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultCompareConcurrency is used when CompareOptions.Concurrency is zero.
+const DefaultCompareConcurrency = 4
+
+// CompareTarget is one provider a Compare request is sent to.
+type CompareTarget struct {
+	Name   string // Label shown with the result, e.g. a config profile name
+	Config Config
+}
+
+// CompareResult is the outcome of a Compare request for a single target.
+type CompareResult struct {
+	Provider  string `json:"provider"`
+	Content   string `json:"content"`
+	Usage     *Usage `json:"usage"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+	Error     string `json:"error"`
+}
+
+// CompareOptions controls how Compare fans out.
+type CompareOptions struct {
+	// Concurrency bounds how many targets are queried at once.
+	// Defaults to DefaultCompareConcurrency.
+	Concurrency int
+
+	// Timeout bounds each target. Zero keeps each target's Config.Timeout.
+	Timeout time.Duration
+
+	// OnResult, if set, is called as each target finishes, one call at a time.
+	OnResult func(index int, result CompareResult)
+}
+
+// Compare sends the same request to every target concurrently and returns
+// the results in target order. The system prompt is resolved once from the
+// first target so every provider sees an identical prompt.
+func Compare(ctx context.Context, targets []CompareTarget, req GenerateRequest, opts CompareOptions) []CompareResult {
+	results := make([]CompareResult, len(targets))
+	if len(targets) == 0 {
+		return results
+	}
+
+	if req.SystemPrompt == "" {
+		req.SystemPrompt = systemPrompt(req, targets[0].Config.SkipPersonaPrompt)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCompareConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target CompareTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := compareOne(ctx, target, req, opts.Timeout)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = result
+			if opts.OnResult != nil {
+				opts.OnResult(i, result)
+			}
+		}(i, target)
+	}
+	wg.Wait()
+	return results
+}
+
+// compareOne runs a single Compare target.
+func compareOne(ctx context.Context, target CompareTarget, req GenerateRequest, timeout time.Duration) CompareResult {
+	result := CompareResult{Provider: target.Name}
+	start := time.Now()
+
+	config := target.Config
+	if timeout > 0 {
+		config.Timeout = timeout
+	}
+	// An empty system prompt means "no persona" for every target
+	config.SkipPersonaPrompt = req.SystemPrompt == ""
+
+	client, err := NewClient(config)
+	if err != nil {
+		result.Error = err.Error()
+		result.ElapsedMS = time.Since(start).Milliseconds()
+		return result
+	}
+	defer client.Close()
+
+	generated, err := client.generateSync(ctx, req)
+	result.ElapsedMS = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Content = generated.Content
+	result.Usage = generated.Usage
+	return result
+}
+
+// Failed reports whether the target returned an error.
+func (r CompareResult) Failed() bool {
+	return r.Error != ""
+}
@@ -0,0 +1,76 @@
+package celeste
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compareTarget(name, serverURL string) CompareTarget {
+	return CompareTarget{Name: name, Config: Config{APIKey: "test-key", BaseURL: serverURL + "/v1", Model: "gpt-4o-mini"}}
+}
+
+// TestCompare tests that every target gets the same prompt and results keep target order
+func TestCompare(t *testing.T) {
+	var reqA, reqB map[string]interface{}
+	serverA := newMockChatServer(t, []string{"from ", "a"}, &reqA)
+	defer serverA.Close()
+	serverB := newMockChatServer(t, []string{"from b"}, &reqB)
+	defer serverB.Close()
+
+	targets := []CompareTarget{compareTarget("a", serverA.URL), compareTarget("b", serverB.URL)}
+	// A target that skips the persona must still get the same prompt as the others
+	targets[1].Config.SkipPersonaPrompt = true
+
+	var finished []int
+	results := Compare(context.Background(), targets, GenerateRequest{Prompt: "hi"}, CompareOptions{
+		OnResult: func(i int, _ CompareResult) { finished = append(finished, i) },
+	})
+	require.Len(t, results, 2)
+	assert.ElementsMatch(t, []int{0, 1}, finished)
+
+	assert.Equal(t, "a", results[0].Provider)
+	assert.Equal(t, "from a", results[0].Content)
+	assert.Equal(t, "b", results[1].Provider)
+	assert.Equal(t, "from b", results[1].Content)
+	for _, r := range results {
+		assert.False(t, r.Failed())
+		require.NotNil(t, r.Usage)
+		assert.Equal(t, 16, r.Usage.TotalTokens)
+	}
+
+	assert.Equal(t, reqA["messages"], reqB["messages"])
+	assert.Nil(t, reqA["stream"], "compare doesn't stream")
+}
+
+// TestCompareTimeout tests that a slow target fails on its own without holding up the rest
+func TestCompareTimeout(t *testing.T) {
+	fast := newMockChatServer(t, []string{"quick"}, nil)
+	defer fast.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	start := time.Now()
+	results := Compare(context.Background(),
+		[]CompareTarget{compareTarget("slow", slow.URL), compareTarget("fast", fast.URL)},
+		GenerateRequest{Prompt: "hi", SystemPrompt: "be brief"},
+		CompareOptions{Concurrency: 1, Timeout: 200 * time.Millisecond},
+	)
+	assert.Less(t, time.Since(start), 3*time.Second)
+
+	assert.True(t, results[0].Failed())
+	assert.False(t, results[1].Failed())
+	assert.Equal(t, "quick", results[1].Content)
+}
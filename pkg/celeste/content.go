@@ -0,0 +1,156 @@
+package celeste
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/whykusanagi/celesteCLI/internal/prompts"
+)
+
+// Content length limits, in characters, for the short and long formats.
+const (
+	ShortContentLimit = 280
+	LongContentLimit  = 5000
+)
+
+// How GenerateContent handles content over the format's limit.
+const (
+	OverflowShorten  = "shorten"  // Ask the model to rewrite it, then trim if it still doesn't fit
+	OverflowTruncate = "truncate" // Trim it at a word boundary without another request
+)
+
+// ContentLimit returns the maximum length in characters for a content
+// format, or 0 if the format has no limit.
+func ContentLimit(format string) int {
+	switch format {
+	case "short":
+		return ShortContentLimit
+	case "long":
+		return LongContentLimit
+	}
+	return 0
+}
+
+// ValidateContent checks a platform and format against the values content
+// generation supports. Empty values are allowed.
+func ValidateContent(platform, format string) error {
+	if platform != "" && !slices.Contains(prompts.ContentPlatforms, platform) {
+		return fmt.Errorf("unknown platform %q (available: %s)", platform, strings.Join(prompts.ContentPlatforms, ", "))
+	}
+	if format != "" && !slices.Contains(prompts.ContentFormats, format) {
+		return fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(prompts.ContentFormats, ", "))
+	}
+	return nil
+}
+
+// ValidateOverflow checks an overflow behavior. Empty means OverflowShorten.
+func ValidateOverflow(overflow string) error {
+	switch overflow {
+	case "", OverflowShorten, OverflowTruncate:
+		return nil
+	}
+	return fmt.Errorf("unknown overflow behavior %q (available: %s, %s)", overflow, OverflowShorten, OverflowTruncate)
+}
+
+// GenerateContent generates platform-formatted content for req.Platform and
+// req.Format. When the response is over the format's limit, req.Overflow
+// decides what happens: by default the model is asked once to shorten it
+// (setting Shortened), and if it still doesn't fit it is cut at a word
+// boundary and Truncated is set. With OverflowTruncate it is cut right away.
+// Usage covers every request made. With no prompt, a post about req.Topic is
+// requested.
+func (c *Client) GenerateContent(ctx context.Context, req GenerateRequest) (GenerateResult, error) {
+	if req.Platform == "" && req.Format == "" {
+		return GenerateResult{}, errors.New("celeste: content needs a platform or format")
+	}
+	if err := ValidateContent(req.Platform, req.Format); err != nil {
+		return GenerateResult{}, fmt.Errorf("celeste: %w", err)
+	}
+	if err := ValidateOverflow(req.Overflow); err != nil {
+		return GenerateResult{}, fmt.Errorf("celeste: %w", err)
+	}
+	req = withTopicPrompt(req)
+
+	result, err := c.Generate(ctx, req)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	limit := ContentLimit(req.Format)
+	if limit == 0 || utf8.RuneCountInString(result.Content) <= limit {
+		return result, nil
+	}
+	if req.Overflow == OverflowTruncate {
+		result.Content = TrimToLimit(result.Content, limit)
+		result.Truncated = true
+		return result, nil
+	}
+
+	rewrite := req
+	rewrite.History = append(append([]Message(nil), req.History...),
+		Message{Role: "user", Content: req.Prompt},
+		Message{Role: "assistant", Content: result.Content},
+	)
+	rewrite.Prompt = fmt.Sprintf("That is %d characters, over the %d character limit. Rewrite it to fit in %d characters. Reply with only the rewritten text.",
+		utf8.RuneCountInString(result.Content), limit, limit)
+	rewrite.Images = nil
+
+	shorter, err := c.Generate(ctx, rewrite)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	shorter.Usage = addUsage(result.Usage, shorter.Usage)
+	shorter.Shortened = true
+	if utf8.RuneCountInString(shorter.Content) > limit {
+		shorter.Content = TrimToLimit(shorter.Content, limit)
+		shorter.Truncated = true
+	}
+	return shorter, nil
+}
+
+// withTopicPrompt fills in a prompt asking for a post about req.Topic when
+// the request has none.
+func withTopicPrompt(req GenerateRequest) GenerateRequest {
+	if req.Prompt == "" && req.Topic != "" {
+		req.Prompt = "Write a post about " + req.Topic
+	}
+	return req
+}
+
+// TrimToLimit shortens content to at most limit characters, cutting at the
+// last word boundary that fits and marking the cut with an ellipsis.
+func TrimToLimit(content string, limit int) string {
+	content = strings.TrimSpace(content)
+	if limit <= 0 || utf8.RuneCountInString(content) <= limit {
+		return content
+	}
+
+	runes := []rune(content)[:limit-1] // Leave room for the ellipsis
+	cut := len(runes)
+	for i := len(runes) - 1; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + "…"
+}
+
+// addUsage sums token counts from two requests. Either may be nil.
+func addUsage(a, b *Usage) *Usage {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return &Usage{
+		PromptTokens:     a.PromptTokens + b.PromptTokens,
+		CompletionTokens: a.CompletionTokens + b.CompletionTokens,
+		TotalTokens:      a.TotalTokens + b.TotalTokens,
+	}
+}
@@ -0,0 +1,154 @@
+package celeste
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSequenceChatServer answers each chat request with the next reply in
+// order, repeating the last one, and records every request body.
+func newSequenceChatServer(t *testing.T, replies []string, requests *[]map[string]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+
+		reply := replies[len(replies)-1]
+		if len(*requests) <= len(replies) {
+			reply = replies[len(*requests)-1]
+		}
+		data, _ := json.Marshal(map[string]interface{}{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion.chunk",
+			"choices": []interface{}{map[string]interface{}{"index": 0, "delta": map[string]interface{}{"content": reply}}},
+		})
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		fmt.Fprint(w, `data: {"id":"chatcmpl-test","object":"chat.completion.chunk","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestContentLimit tests the per-format limits
+func TestContentLimit(t *testing.T) {
+	assert.Equal(t, 280, ContentLimit("short"))
+	assert.Equal(t, 5000, ContentLimit("long"))
+	assert.Equal(t, 0, ContentLimit("general"))
+	assert.Equal(t, 0, ContentLimit(""))
+}
+
+// TestTrimToLimit tests cutting at word boundaries
+func TestTrimToLimit(t *testing.T) {
+	assert.Equal(t, "short enough", TrimToLimit("  short enough ", 20))
+	assert.Equal(t, "the quick…", TrimToLimit("the quick brown fox", 12))
+
+	// Counted in characters, not bytes
+	trimmed := TrimToLimit(strings.Repeat("🔥 ", 200), 280)
+	assert.LessOrEqual(t, utf8.RuneCountInString(trimmed), 280)
+	assert.True(t, utf8.ValidString(trimmed))
+
+	// A single long word is cut mid-word
+	assert.Equal(t, "abcd…", TrimToLimit("abcdefghij", 5))
+}
+
+// TestGenerateContentWithinLimit tests that fitting content is sent once with the content prompt
+func TestGenerateContentWithinLimit(t *testing.T) {
+	var requests []map[string]interface{}
+	server := newSequenceChatServer(t, []string{"Stream tonight! #vtuber"}, &requests)
+	client := newTestClient(t, server.URL, Config{})
+
+	result, err := client.GenerateContent(context.Background(), GenerateRequest{
+		Prompt:   "announce the stream",
+		Platform: "twitter",
+		Format:   "short",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Stream tonight! #vtuber", result.Content)
+	assert.False(t, result.Truncated)
+	require.Len(t, requests, 1)
+
+	messages := requests[0]["messages"].([]interface{})
+	system := messages[0].(map[string]interface{})["content"].(string)
+	assert.Contains(t, system, "CONTENT GENERATION MODE")
+	assert.Contains(t, system, "Twitter/X")
+}
+
+// TestGenerateContentRewritesLongOutput tests the shorten-then-trim fallback
+func TestGenerateContentRewritesLongOutput(t *testing.T) {
+	long := strings.Repeat("word ", 100) // 500 characters
+
+	var requests []map[string]interface{}
+	server := newSequenceChatServer(t, []string{long, "Much shorter now."}, &requests)
+	client := newTestClient(t, server.URL, Config{})
+
+	result, err := client.GenerateContent(context.Background(), GenerateRequest{Prompt: "hype post", Format: "short"})
+	require.NoError(t, err)
+	assert.Equal(t, "Much shorter now.", result.Content)
+	assert.False(t, result.Truncated)
+	assert.True(t, result.Shortened)
+	require.Len(t, requests, 2)
+	require.NotNil(t, result.Usage)
+	assert.Equal(t, 30, result.Usage.TotalTokens)
+
+	// The rewrite request carries the first attempt and asks for the limit
+	messages := requests[1]["messages"].([]interface{})
+	last := messages[len(messages)-1].(map[string]interface{})["content"].(string)
+	assert.Contains(t, last, "280 character limit")
+	assert.Equal(t, "assistant", messages[len(messages)-2].(map[string]interface{})["role"])
+
+	// A model that won't shorten gets trimmed
+	requests = nil
+	server = newSequenceChatServer(t, []string{long}, &requests)
+	client = newTestClient(t, server.URL, Config{})
+	result, err = client.GenerateContent(context.Background(), GenerateRequest{Prompt: "hype post", Format: "short"})
+	require.NoError(t, err)
+	assert.True(t, result.Truncated)
+	assert.LessOrEqual(t, utf8.RuneCountInString(result.Content), ShortContentLimit)
+	assert.True(t, strings.HasSuffix(result.Content, "…"))
+}
+
+// TestGenerateContentTruncateOverflow tests trimming without a rewrite request
+func TestGenerateContentTruncateOverflow(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+
+	var requests []map[string]interface{}
+	server := newSequenceChatServer(t, []string{long, "never sent"}, &requests)
+	client := newTestClient(t, server.URL, Config{})
+
+	result, err := client.GenerateContent(context.Background(), GenerateRequest{Prompt: "hype post", Format: "short", Overflow: OverflowTruncate})
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.True(t, result.Truncated)
+	assert.False(t, result.Shortened)
+	assert.LessOrEqual(t, utf8.RuneCountInString(result.Content), ShortContentLimit)
+
+	_, err = client.GenerateContent(context.Background(), GenerateRequest{Prompt: "hype post", Format: "short", Overflow: "explode"})
+	assert.ErrorContains(t, err, `unknown overflow behavior "explode"`)
+}
+
+// TestGenerateContentRequiresPlatformOrFormat tests request validation
+func TestGenerateContentRequiresPlatformOrFormat(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:0", Config{})
+	_, err := client.GenerateContent(context.Background(), GenerateRequest{Prompt: "hi"})
+	assert.Error(t, err)
+}
+
+// TestGenerateUnknownPersona tests that a missing persona file is an error, not a silent fallback
+func TestGenerateUnknownPersona(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	client := newTestClient(t, "http://127.0.0.1:0", Config{})
+	_, err := client.Generate(context.Background(), GenerateRequest{Prompt: "hi", Persona: "nobody"})
+	assert.ErrorContains(t, err, `unknown persona "nobody"`)
+}
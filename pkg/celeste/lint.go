@@ -0,0 +1,187 @@
+package celeste
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+
+	"github.com/whykusanagi/celesteCLI/internal/prompts"
+)
+
+// LintRules are the platform constraints generated content is checked
+// against. Zero means no limit.
+type LintRules struct {
+	MaxCharacters int
+	MaxHashtags   int
+	MaxURLs       int
+	Banned        []string // Matched case-insensitively anywhere in the text
+}
+
+// platformLintRules holds each platform's limits: X's post length and the
+// hashtag counts past which platforms treat posts as spam or ignore tags.
+var platformLintRules = map[string]LintRules{
+	"twitter": {MaxCharacters: 280, MaxHashtags: 2, MaxURLs: 1},
+	"tiktok":  {MaxCharacters: 2200, MaxHashtags: 5},
+	"youtube": {MaxCharacters: LongContentLimit, MaxHashtags: 15},
+	"discord": {MaxCharacters: 2000},
+}
+
+// ContentLintRules returns the rules for a platform and format: the
+// platform's limits, with the format's length limit when it is stricter.
+func ContentLintRules(platform, format string, banned []string) LintRules {
+	rules := platformLintRules[platform]
+	if limit := ContentLimit(format); limit > 0 && (rules.MaxCharacters == 0 || limit < rules.MaxCharacters) {
+		rules.MaxCharacters = limit
+	}
+	rules.Banned = banned
+	return rules
+}
+
+// BannedTerms returns the persona's safety refuse list as phrases
+// ("real_world_threats" becomes "real world threats"), followed by extra.
+func BannedTerms(extra []string) []string {
+	var terms []string
+	if essence, err := prompts.LoadEssence(); err == nil {
+		for _, term := range essence.Safety.RefuseList {
+			terms = append(terms, strings.ReplaceAll(term, "_", " "))
+		}
+	}
+	return append(terms, extra...)
+}
+
+// LintIssue is one broken rule.
+type LintIssue struct {
+	Rule    string `json:"rule"` // "length", "hashtags", "urls" or "banned"
+	Message string `json:"message"`
+}
+
+// LintReport is the result of checking content against LintRules.
+type LintReport struct {
+	Characters int         `json:"characters"`
+	Hashtags   []string    `json:"hashtags,omitempty"`
+	URLs       []string    `json:"urls,omitempty"`
+	Issues     []LintIssue `json:"issues,omitempty"`
+}
+
+// OK reports whether the content broke no rules.
+func (r LintReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Summary returns "ok" or the issues joined on one line.
+func (r LintReport) Summary() string {
+	if r.OK() {
+		return "ok"
+	}
+	messages := make([]string, len(r.Issues))
+	for i, issue := range r.Issues {
+		messages[i] = issue.Rule + ": " + issue.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// LintContent checks content against rules.
+func LintContent(content string, rules LintRules) LintReport {
+	report := LintReport{
+		Characters: CountCharacters(content),
+		Hashtags:   FindHashtags(content),
+		URLs:       urlPattern.FindAllString(content, -1),
+	}
+	if rules.MaxCharacters > 0 && report.Characters > rules.MaxCharacters {
+		report.Issues = append(report.Issues, LintIssue{"length",
+			fmt.Sprintf("%d characters, over the %d limit", report.Characters, rules.MaxCharacters)})
+	}
+	if rules.MaxHashtags > 0 && len(report.Hashtags) > rules.MaxHashtags {
+		report.Issues = append(report.Issues, LintIssue{"hashtags",
+			fmt.Sprintf("%d hashtags (%s), more than %d", len(report.Hashtags), strings.Join(report.Hashtags, " "), rules.MaxHashtags)})
+	}
+	if rules.MaxURLs > 0 && len(report.URLs) > rules.MaxURLs {
+		report.Issues = append(report.Issues, LintIssue{"urls",
+			fmt.Sprintf("%d links, more than %d", len(report.URLs), rules.MaxURLs)})
+	}
+	lower := strings.ToLower(content)
+	for _, term := range rules.Banned {
+		if term != "" && strings.Contains(lower, strings.ToLower(term)) {
+			report.Issues = append(report.Issues, LintIssue{"banned", fmt.Sprintf("contains %q", term)})
+		}
+	}
+	return report
+}
+
+// CountCharacters counts what a reader sees as characters: an emoji built
+// from several code points, such as a flag or a family, counts once.
+func CountCharacters(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// FindHashtags returns the hashtags in s in order. A hashtag is # at the
+// start of a word followed by letters, digits or underscores, at least one
+// of them a letter, so "#1", "C#" and URL fragments are not hashtags.
+func FindHashtags(s string) []string {
+	s = urlPattern.ReplaceAllString(s, " ")
+	var tags []string
+	for i := 0; i < len(s); i++ {
+		if s[i] != '#' {
+			continue
+		}
+		if i > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(s[:i])
+			if isTagRune(prev) || prev == '#' || prev == '&' {
+				continue
+			}
+		}
+		end := i + 1
+		letter := false
+		for end < len(s) {
+			r, size := utf8.DecodeRuneInString(s[end:])
+			if !isTagRune(r) {
+				break
+			}
+			letter = letter || unicode.IsLetter(r)
+			end += size
+		}
+		if letter {
+			tags = append(tags, s[i:end])
+		}
+		i = end - 1
+	}
+	return tags
+}
+
+func isTagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+// FixContent asks the model once to rewrite previous, the content generated
+// for req, so it no longer has the issues in report while keeping its
+// meaning. Usage covers both requests.
+func (c *Client) FixContent(ctx context.Context, req GenerateRequest, previous GenerateResult, report LintReport) (GenerateResult, error) {
+	var problems strings.Builder
+	for _, issue := range report.Issues {
+		fmt.Fprintf(&problems, "- %s: %s\n", issue.Rule, issue.Message)
+	}
+
+	req = withTopicPrompt(req)
+	fix := req
+	fix.History = append(append([]Message(nil), req.History...),
+		Message{Role: "user", Content: req.Prompt},
+		Message{Role: "assistant", Content: previous.Content},
+	)
+	fix.Prompt = "That post breaks these platform rules:\n" + problems.String() +
+		"Rewrite it to fix every one while keeping the meaning and voice. Reply with only the rewritten text."
+	fix.Images = nil
+
+	fixed, err := c.Generate(ctx, fix)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	fixed.Usage = addUsage(previous.Usage, fixed.Usage)
+	return fixed, nil
+}
@@ -0,0 +1,93 @@
+package celeste
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCountCharacters tests that multi-code-point emoji count once
+func TestCountCharacters(t *testing.T) {
+	assert.Equal(t, 5, CountCharacters("hello"))
+	assert.Equal(t, 4, CountCharacters("café"))
+	assert.Equal(t, 4, CountCharacters("café"), "combining accent")
+	assert.Equal(t, 1, CountCharacters("👨‍👩‍👧‍👦"), "family ZWJ sequence")
+	assert.Equal(t, 1, CountCharacters("🇯🇵"), "flag")
+	assert.Equal(t, 1, CountCharacters("👍🏽"), "skin tone")
+	assert.Equal(t, 3, CountCharacters("🔥🔥🔥"))
+}
+
+// TestFindHashtags tests hashtag detection
+func TestFindHashtags(t *testing.T) {
+	assert.Equal(t, []string{"#live", "#Celeste_Abyss", "#día2"},
+		FindHashtags("We're #live! (#Celeste_Abyss) #día2"))
+	assert.Equal(t, []string{"#a"}, FindHashtags("#a#b"))
+	assert.Empty(t, FindHashtags("C# and F# are languages"))
+	assert.Empty(t, FindHashtags("We're #1 on the charts, issue #42"))
+	assert.Empty(t, FindHashtags("See https://example.com/page#section"))
+	assert.Empty(t, FindHashtags("Use &#35; for a literal ## sign"))
+	assert.Equal(t, []string{"#go"}, FindHashtags("#go"))
+}
+
+// TestLintContent tests each rule and the platform limits
+func TestLintContent(t *testing.T) {
+	rules := ContentLintRules("twitter", "short", []string{"doxxing"})
+	assert.Equal(t, 280, rules.MaxCharacters)
+
+	report := LintContent("Stream tonight! #live #gaming #vtuber https://a.example https://b.example", rules)
+	assert.False(t, report.OK())
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, "hashtags", report.Issues[0].Rule)
+	assert.Contains(t, report.Issues[0].Message, "3 hashtags (#live #gaming #vtuber), more than 2")
+	assert.Equal(t, "urls", report.Issues[1].Rule)
+
+	report = LintContent(strings.Repeat("a", 281)+" no DOXXING here", rules)
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, "length", report.Issues[0].Rule)
+	assert.Equal(t, "banned", report.Issues[1].Rule)
+	assert.Contains(t, report.Summary(), "length: 297 characters, over the 280 limit")
+
+	// 280 emoji flags are 280 characters, though 560 code points
+	assert.True(t, LintContent(strings.Repeat("🇯🇵", 280), rules).OK())
+	assert.Equal(t, "ok", LintContent("fine #one", rules).Summary())
+
+	// The stricter of the platform and format limits wins
+	assert.Equal(t, 2000, ContentLintRules("discord", "long", nil).MaxCharacters)
+	assert.Equal(t, 280, ContentLintRules("tiktok", "short", nil).MaxCharacters)
+	assert.Equal(t, 0, ContentLintRules("", "general", nil).MaxCharacters)
+}
+
+// TestBannedTerms tests the persona refuse list becomes plain phrases
+func TestBannedTerms(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	terms := BannedTerms([]string{"spoilers"})
+	assert.Contains(t, terms, "real world threats")
+	assert.Equal(t, "spoilers", terms[len(terms)-1])
+}
+
+// TestFixContent tests the follow-up request carries the issues
+func TestFixContent(t *testing.T) {
+	var requests []map[string]interface{}
+	server := newSequenceChatServer(t, []string{"Live now! #live"}, &requests)
+	client := newTestClient(t, server.URL, Config{})
+
+	req := GenerateRequest{Topic: "stream", Platform: "twitter", Format: "short"}
+	previous := GenerateResult{Content: "Live! #a #b #c", Usage: &Usage{TotalTokens: 15}}
+	report := LintContent(previous.Content, ContentLintRules("twitter", "short", nil))
+
+	fixed, err := client.FixContent(context.Background(), req, previous, report)
+	require.NoError(t, err)
+	assert.Equal(t, "Live now! #live", fixed.Content)
+	assert.Equal(t, 30, fixed.Usage.TotalTokens)
+
+	require.Len(t, requests, 1)
+	messages := requests[0]["messages"].([]interface{})
+	last := messages[len(messages)-1].(map[string]interface{})["content"].(string)
+	assert.Contains(t, last, "- hashtags: 3 hashtags (#a #b #c), more than 2")
+	assert.Contains(t, last, "keeping the meaning")
+	assert.Equal(t, "Live! #a #b #c", messages[len(messages)-2].(map[string]interface{})["content"])
+	assert.Equal(t, "Write a post about stream", messages[len(messages)-3].(map[string]interface{})["content"])
+}
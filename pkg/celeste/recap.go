@@ -0,0 +1,70 @@
+package celeste
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/whykusanagi/celesteCLI/internal/config"
+	"github.com/whykusanagi/celesteCLI/internal/llm"
+)
+
+// RecapStyles are the styles Recap can write in.
+var RecapStyles = llm.RecapStyles
+
+// RecapRequest describes a conversation recap.
+type RecapRequest struct {
+	// Messages is the conversation to recap, oldest first. Tool results
+	// are described by the skill that returned them, not their content.
+	Messages []Message
+
+	// Style is one of RecapStyles.
+	Style string
+
+	// Persona and SystemPrompt select the voice the recap is written in,
+	// as for GenerateRequest.
+	Persona      string
+	SystemPrompt string
+}
+
+// Recap summarizes a conversation in the request's style. Conversations
+// too long for one request are condensed into notes first, so a recap may
+// take several requests; the result's Usage is their total.
+func (c *Client) Recap(ctx context.Context, req RecapRequest) (GenerateResult, error) {
+	messages := make([]config.SessionMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = config.SessionMessage{Role: m.Role, Content: m.Content, Name: m.Name}
+	}
+
+	var usage *Usage
+	complete := func(ctx context.Context, prompt string) (string, error) {
+		result, err := c.Generate(ctx, GenerateRequest{
+			Prompt:       prompt,
+			Persona:      req.Persona,
+			SystemPrompt: req.SystemPrompt,
+		})
+		if err != nil {
+			return "", err
+		}
+		if result.Usage != nil {
+			if usage == nil {
+				usage = &Usage{}
+			}
+			usage.PromptTokens += result.Usage.PromptTokens
+			usage.CompletionTokens += result.Usage.CompletionTokens
+			usage.TotalTokens += result.Usage.TotalTokens
+		}
+		content := strings.TrimSpace(result.Content)
+		if content == "" {
+			return "", errors.New("empty summary returned")
+		}
+		return content, nil
+	}
+
+	content, err := llm.Recap(ctx, messages, req.Style, c.config.Model, complete)
+	if err != nil {
+		return GenerateResult{}, fmt.Errorf("celeste: recap: %w", err)
+	}
+	return GenerateResult{Content: content, Usage: usage}, nil
+}
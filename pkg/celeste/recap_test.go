@@ -0,0 +1,68 @@
+package celeste
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecap tests a recap in the persona's voice, with tool results described as actions
+func TestRecap(t *testing.T) {
+	var req map[string]interface{}
+	server := newMockChatServer(t, []string{"  We drew ", "cards.\n"}, &req)
+	defer server.Close()
+	client := newTestClient(t, server.URL, Config{})
+
+	result, err := client.Recap(context.Background(), RecapRequest{
+		Messages: []Message{
+			{Role: "user", Content: "Give me a tarot reading"},
+			{Role: "tool", Name: "tarot_reading", Content: `{"cards": []}`},
+			{Role: "assistant", Content: "The Tower, reversed."},
+		},
+		Style:        "bullets",
+		SystemPrompt: "You are a test persona.",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "We drew cards.", result.Content)
+	require.NotNil(t, result.Usage)
+	assert.Equal(t, 16, result.Usage.TotalTokens)
+
+	messages := req["messages"].([]interface{})
+	require.Len(t, messages, 2)
+	assert.Equal(t, "You are a test persona.", messages[0].(map[string]interface{})["content"])
+	prompt := messages[1].(map[string]interface{})["content"].(string)
+	assert.Contains(t, prompt, "User: Give me a tarot reading")
+	assert.Contains(t, prompt, "[Celeste did a tarot reading]")
+	assert.NotContains(t, prompt, `"cards"`)
+}
+
+// TestRecapTotalsUsage tests that a recap taking several requests reports their combined usage
+func TestRecapTotalsUsage(t *testing.T) {
+	server := newMockChatServer(t, []string{"notes"}, nil)
+	defer server.Close()
+	client, err := NewClient(Config{APIKey: "test-key", BaseURL: server.URL + "/v1", Model: "unknown-model", SkipPersonaPrompt: true})
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Well over the unknown model's 4096-token budget, so it's condensed first
+	var messages []Message
+	for i := 0; i < 30; i++ {
+		messages = append(messages, Message{Role: "user", Content: strings.Repeat("word ", 300)})
+	}
+	result, err := client.Recap(context.Background(), RecapRequest{Messages: messages, Style: "narrative"})
+	require.NoError(t, err)
+	require.NotNil(t, result.Usage)
+	assert.Greater(t, result.Usage.TotalTokens, 2*16, "expected one request per chunk plus the final recap")
+	assert.Zero(t, result.Usage.TotalTokens%16)
+	assert.Equal(t, result.Usage.PromptTokens+result.Usage.CompletionTokens, result.Usage.TotalTokens)
+}
+
+// TestRecapRejectsUnknownStyle tests style validation before any request is sent
+func TestRecapRejectsUnknownStyle(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:0", Config{})
+	_, err := client.Recap(context.Background(), RecapRequest{Messages: []Message{{Role: "user", Content: "hi"}}, Style: "haiku"})
+	assert.ErrorContains(t, err, "unknown recap style")
+}